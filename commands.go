@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform/internal/command/views"
 	"github.com/hashicorp/terraform/internal/command/webbrowser"
 	"github.com/hashicorp/terraform/internal/getproviders"
+	tfplugin "github.com/hashicorp/terraform/internal/plugin"
 	pluginDiscovery "github.com/hashicorp/terraform/internal/plugin/discovery"
 	"github.com/hashicorp/terraform/internal/terminal"
 )
@@ -82,7 +83,10 @@ func initCommands(
 	meta := command.Meta{
 		WorkingDir: wd,
 		Streams:    streams,
-		View:       views.NewView(streams).SetRunningInAutomation(inAutomation),
+		View: views.NewView(streams).SetRunningInAutomation(inAutomation).SetRedactionRules(views.RedactionRules{
+			Providers:  config.RedactProviders,
+			Attributes: config.RedactAttributes,
+		}),
 
 		Color:            true,
 		GlobalPluginDirs: globalPluginDirs(),
@@ -91,9 +95,22 @@ func initCommands(
 		Services:        services,
 		BrowserLauncher: webbrowser.NewNativeLauncher(),
 
-		RunningInAutomation: inAutomation,
-		CLIConfigDir:        configDir,
-		PluginCacheDir:      config.PluginCacheDir,
+		RunningInAutomation:  inAutomation,
+		CLIConfigDir:         configDir,
+		PluginCacheDir:       config.PluginCacheDir,
+		ProviderAuditLogPath: config.ProviderInstallationAuditLog,
+		ModuleMirrorDir:      moduleMirrorDir(config),
+		PluginGRPCClient: tfplugin.GRPCClientConfig{
+			Compression: config.PluginGRPCCompression,
+			MaxRecvSize: config.PluginGRPCMaxRecvSize,
+			MaxSendSize: config.PluginGRPCMaxSendSize,
+		},
+
+		OperationHistoryDisabled: config.DisableOperationHistory,
+
+		PlanGateProgram: config.PlanGateProgram,
+
+		ReadonlyWorkspacePatterns: config.ReadonlyWorkspaces,
 
 		ShutdownCh: makeShutdownCh(),
 
@@ -115,6 +132,12 @@ func initCommands(
 			}, nil
 		},
 
+		"checkup": func() (cli.Command, error) {
+			return &command.CheckupCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"console": func() (cli.Command, error) {
 			return &command.ConsoleCommand{
 				Meta: meta,
@@ -181,6 +204,12 @@ func initCommands(
 			}, nil
 		},
 
+		"history": func() (cli.Command, error) {
+			return &command.HistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"import": func() (cli.Command, error) {
 			return &command.ImportCommand{
 				Meta: meta,
@@ -205,6 +234,12 @@ func initCommands(
 			}, nil
 		},
 
+		"modules mirror": func() (cli.Command, error) {
+			return &command.ModulesMirrorCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"output": func() (cli.Command, error) {
 			return &command.OutputCommand{
 				Meta: meta,
@@ -223,6 +258,12 @@ func initCommands(
 			}, nil
 		},
 
+		"providers cache prune": func() (cli.Command, error) {
+			return &command.ProvidersCachePruneCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"providers lock": func() (cli.Command, error) {
 			return &command.ProvidersLockCommand{
 				Meta: meta,
@@ -241,6 +282,12 @@ func initCommands(
 			}, nil
 		},
 
+		"providers verify": func() (cli.Command, error) {
+			return &command.ProvidersVerifyCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"push": func() (cli.Command, error) {
 			return &command.PushCommand{
 				Meta: meta,
@@ -253,6 +300,12 @@ func initCommands(
 			}, nil
 		},
 
+		"sbom": func() (cli.Command, error) {
+			return &command.SbomCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"show": func() (cli.Command, error) {
 			return &command.ShowCommand{
 				Meta: meta,
@@ -329,6 +382,24 @@ func initCommands(
 			}, nil
 		},
 
+		"workspace reap": func() (cli.Command, error) {
+			return &command.WorkspaceReapCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace tag": func() (cli.Command, error) {
+			return &command.WorkspaceTagCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace describe": func() (cli.Command, error) {
+			return &command.WorkspaceDescribeCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		//-----------------------------------------------------------
 		// Plumbing
 		//-----------------------------------------------------------
@@ -343,6 +414,32 @@ func initCommands(
 			return &command.StateCommand{}, nil
 		},
 
+		"state analyze-size": func() (cli.Command, error) {
+			return &command.StateAnalyzeSizeCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"state decrypt": func() (cli.Command, error) {
+			return &command.StateDecryptCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"state diff": func() (cli.Command, error) {
+			return &command.StateDiffCommand{
+				StateMeta: command.StateMeta{
+					Meta: meta,
+				},
+			}, nil
+		},
+
+		"state encrypt": func() (cli.Command, error) {
+			return &command.StateEncryptCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state list": func() (cli.Command, error) {
 			return &command.StateListCommand{
 				Meta: meta,
@@ -383,6 +480,12 @@ func initCommands(
 			}, nil
 		},
 
+		"state restore-version": func() (cli.Command, error) {
+			return &command.StateRestoreVersionCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"state replace-provider": func() (cli.Command, error) {
 			return &command.StateReplaceProviderCommand{
 				StateMeta: command.StateMeta{
@@ -392,6 +495,17 @@ func initCommands(
 		},
 	}
 
+	// Each command gets told its own name, so that command-scoped settings
+	// such as entries in a project-local development overrides file can be
+	// matched against the command actually being run.
+	for name := range Commands {
+		name, factory := name, Commands[name]
+		Commands[name] = func() (cli.Command, error) {
+			meta.CommandName = name
+			return factory()
+		}
+	}
+
 	PrimaryCommands = []string{
 		"init",
 		"validate",
@@ -408,6 +522,17 @@ func initCommands(
 
 }
 
+// moduleMirrorDir returns the module filesystem mirror directory selected in
+// the CLI configuration, if any.
+func moduleMirrorDir(config *cliconfig.Config) string {
+	for _, mi := range config.ModuleInstallation {
+		if mi.FilesystemMirrorDir != "" {
+			return mi.FilesystemMirrorDir
+		}
+	}
+	return ""
+}
+
 // makeShutdownCh creates an interrupt listener and returns a channel.
 // A message will be sent on the channel for every interrupt received.
 func makeShutdownCh() <-chan struct{} {
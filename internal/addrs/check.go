@@ -30,6 +30,13 @@ func (c Check) String() string {
 		return fmt.Sprintf("%s.postconditions[%d]", container, c.Index)
 	case OutputPrecondition:
 		return fmt.Sprintf("%s.preconditions[%d]", container, c.Index)
+	case ModulePrecondition:
+		if container == "" {
+			// The root module instance renders as an empty string, and a
+			// leading period would look like a mistake.
+			return fmt.Sprintf("preconditions[%d]", c.Index)
+		}
+		return fmt.Sprintf("%s.preconditions[%d]", container, c.Index)
 	default:
 		// This should not happen
 		return fmt.Sprintf("%s.conditions[%d]", container, c.Index)
@@ -50,6 +57,7 @@ type Checkable interface {
 var (
 	_ Checkable = AbsResourceInstance{}
 	_ Checkable = AbsOutputValue{}
+	_ Checkable = ModuleInstance(nil)
 )
 
 type checkable struct {
@@ -67,6 +75,7 @@ const (
 	ResourcePrecondition  CheckType = 1
 	ResourcePostcondition CheckType = 2
 	OutputPrecondition    CheckType = 3
+	ModulePrecondition    CheckType = 4
 )
 
 // Description returns a human-readable description of the check type. This is
@@ -79,6 +88,8 @@ func (c CheckType) Description() string {
 		return "Resource postcondition"
 	case OutputPrecondition:
 		return "Module output value precondition"
+	case ModulePrecondition:
+		return "Module precondition"
 	default:
 		// This should not happen
 		return "Condition"
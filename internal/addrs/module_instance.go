@@ -535,6 +535,21 @@ func (m ModuleInstance) IsDeclaredByCall(other AbsModuleCall) bool {
 	return lastStep.Name == m[len(m)-1].Name
 }
 
+// ModuleInstance is Checkable because modules can contain module-level
+// precondition blocks.
+func (m ModuleInstance) checkableSigil() {
+}
+
+// Check returns the address of an individual check rule of a specified
+// type and index within this module instance.
+func (m ModuleInstance) Check(t CheckType, i int) Check {
+	return Check{
+		Container: m,
+		Type:      t,
+		Index:     i,
+	}
+}
+
 func (s ModuleInstanceStep) String() string {
 	if s.InstanceKey != NoKey {
 		return s.Name + s.InstanceKey.String()
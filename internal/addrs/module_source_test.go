@@ -154,6 +154,19 @@ func TestParseModuleSource(t *testing.T) {
 				Subdir:      "bleep/bloop",
 			},
 		},
+		"OCI artifact": {
+			input: "oci://registry.example.com/modules/vpc:1.0.0",
+			want: ModuleSourceRemote{
+				PackageAddr: ModulePackage("oci://registry.example.com/modules/vpc:1.0.0"),
+			},
+		},
+		"OCI artifact, digest reference, subdir": {
+			input: "oci://registry.example.com/modules/vpc@sha256:abc123//bleep/bloop",
+			want: ModuleSourceRemote{
+				PackageAddr: ModulePackage("oci://registry.example.com/modules/vpc@sha256:abc123"),
+				Subdir:      "bleep/bloop",
+			},
+		},
 		"git over SSH, URL-style": {
 			input: "git::ssh://git@example.com/code/baz.git",
 			want: ModuleSourceRemote{
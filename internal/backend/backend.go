@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/command/clistate"
@@ -155,6 +156,64 @@ type Local interface {
 	LocalRun(*Operation) (*LocalRun, statemgr.Full, tfdiags.Diagnostics)
 }
 
+// StateVersion describes one historical state snapshot retained by a
+// backend that implements VersionHistory.
+type StateVersion struct {
+	// Serial is the state serial the snapshot was recorded with.
+	Serial uint64
+
+	// Created is the time the snapshot was recorded.
+	Created time.Time
+}
+
+// VersionHistory is an optional extra interface for Backend, implemented
+// by backends that retain a history of prior state snapshots which can be
+// listed and retrieved, such as for restoring an earlier state version.
+//
+// Backends that support history only when specially configured should
+// still implement this interface unconditionally, and return an error
+// from its methods explaining how to enable history when it is disabled.
+type VersionHistory interface {
+	Backend
+
+	// StateVersions returns descriptions of the retained snapshots for the
+	// given workspace, in the order they were recorded.
+	StateVersions(workspace string) ([]StateVersion, error)
+
+	// StateVersion returns the raw state snapshot data recorded for the
+	// given workspace with the given serial. If more than one snapshot was
+	// recorded with the same serial, the most recent one is returned.
+	StateVersion(workspace string, serial uint64) ([]byte, error)
+}
+
+// WorkspaceMetadata is descriptive metadata stored alongside a workspace's
+// state, for organizing workspaces rather than for Terraform's own use.
+type WorkspaceMetadata struct {
+	// Description is free text describing the workspace's purpose. Empty
+	// means no description has been set.
+	Description string `json:"description,omitempty"`
+
+	// Tags are arbitrary key/value pairs attached to the workspace.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// WorkspaceMetadataStore is an optional extra interface for Backend,
+// implemented by backends that can store descriptive metadata alongside
+// each workspace's state, in whatever location is appropriate for the
+// backend's storage.
+type WorkspaceMetadataStore interface {
+	Backend
+
+	// WorkspaceMetadata returns the metadata stored for the given workspace.
+	// A workspace with no stored metadata yields an empty WorkspaceMetadata
+	// with no error.
+	WorkspaceMetadata(workspace string) (*WorkspaceMetadata, error)
+
+	// SetWorkspaceMetadata replaces the metadata stored for the given
+	// workspace.
+	SetWorkspaceMetadata(workspace string, meta *WorkspaceMetadata) error
+}
+
 // LocalRun represents the assortment of objects that we can collect or
 // calculate from an Operation object, which we can then use for local
 // operations.
@@ -265,6 +324,41 @@ type Operation struct {
 	ForceReplace []addrs.AbsResourceInstance
 	Variables    map[string]UnparsedVariableValue
 
+	// CheckpointPath, if non-empty, is a path to a journal file where an
+	// apply operation should record each completed resource action, so
+	// that an interrupted apply can later be resumed with ResumePath.
+	CheckpointPath string
+
+	// ResumePath, if non-empty, is a path to a checkpoint journal from an
+	// earlier interrupted apply. Actions recorded there are skipped during
+	// the apply, after verifying that the state reflects their completion.
+	ResumePath string
+
+	// PlanGateProgram, if non-empty, is an external program to run with the
+	// JSON representation of the plan on its stdin before applying it. If
+	// the program exits with a non-zero status the apply is aborted and the
+	// program's output is reported to the user.
+	PlanGateProgram string
+
+	// ForceLocalExecution, if true, asks a backend that would normally
+	// execute this operation remotely to run it locally instead, while
+	// still using the remote system for state storage and locking.
+	// Backends that always run operations locally ignore it.
+	ForceLocalExecution bool
+
+	// ContinueOnError requests that an apply operation keep working on
+	// any planned changes that are unaffected by a failed change, rather
+	// than treating the whole run as failed, and then report which
+	// instances failed and which were never attempted because of them.
+	ContinueOnError bool
+
+	// AutoApproveMaxDestroy places an upper limit on how many resource
+	// instances an apply operation may destroy or replace, evaluated from
+	// the plan before any changes are made. A negative value, which callers
+	// should use by default, disables the limit; zero forbids any destroy
+	// or replace actions at all.
+	AutoApproveMaxDestroy int
+
 	// Some operations use root module variables only opportunistically or
 	// don't need them at all. If this flag is set, the backend must treat
 	// all variables as optional and provide an unknown value for any required
@@ -370,6 +464,11 @@ type RunningOperation struct {
 	// to note whether a plan is empty or has changes.
 	PlanEmpty bool
 
+	// DriftDetected is populated after a Plan operation completes without
+	// error, and is true if the refresh phase found remote objects that no
+	// longer match the previous run's state.
+	DriftDetected bool
+
 	// State is the final state after the operation completed. Persisting
 	// this state is managed by the backend. This should only be read
 	// after the operation completes to avoid read/write races.
@@ -387,6 +486,18 @@ const (
 	// of error, and thus may have been only partially performed or not
 	// performed at all.
 	OperationFailure OperationResult = 1
+
+	// OperationBlocked indicates that the operation made no changes at all
+	// because a guardrail such as the -auto-approve-max-destroy option
+	// declined it before any work began. The distinct exit status lets
+	// automation tell this apart from an operation that failed partway.
+	OperationBlocked OperationResult = 2
+
+	// OperationPartialFailure indicates that some of the operation's
+	// changes failed while others were completed, as happens when an apply
+	// runs with the -continue-on-error option. The distinct exit status
+	// lets automation tell a partial apply from a total failure.
+	OperationPartialFailure OperationResult = 3
 )
 
 func (r OperationResult) ExitStatus() int {
@@ -19,6 +19,7 @@ import (
 	backendEtcdv2 "github.com/hashicorp/terraform/internal/backend/remote-state/etcdv2"
 	backendEtcdv3 "github.com/hashicorp/terraform/internal/backend/remote-state/etcdv3"
 	backendGCS "github.com/hashicorp/terraform/internal/backend/remote-state/gcs"
+	backendGit "github.com/hashicorp/terraform/internal/backend/remote-state/git"
 	backendHTTP "github.com/hashicorp/terraform/internal/backend/remote-state/http"
 	backendInmem "github.com/hashicorp/terraform/internal/backend/remote-state/inmem"
 	backendKubernetes "github.com/hashicorp/terraform/internal/backend/remote-state/kubernetes"
@@ -61,6 +62,7 @@ func Init(services *disco.Disco) {
 		"etcd":        func() backend.Backend { return backendEtcdv2.New() },
 		"etcdv3":      func() backend.Backend { return backendEtcdv3.New() },
 		"gcs":         func() backend.Backend { return backendGCS.New() },
+		"git":         func() backend.Backend { return backendGit.New() },
 		"http":        func() backend.Backend { return backendHTTP.New() },
 		"inmem":       func() backend.Backend { return backendInmem.New() },
 		"kubernetes":  func() backend.Backend { return backendKubernetes.New() },
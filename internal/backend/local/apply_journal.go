@@ -0,0 +1,243 @@
+package local
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/terraform"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// applyJournalHeader is the first line of a checkpoint journal file,
+// identifying the file format so that future versions can evolve the
+// entry schema.
+type applyJournalHeader struct {
+	FormatVersion string `json:"format_version"`
+}
+
+// applyJournalEntry is one line of a checkpoint journal file, recording
+// that a particular action completed successfully for a particular
+// resource instance object.
+type applyJournalEntry struct {
+	Resource    string `json:"resource"`
+	Deposed     string `json:"deposed,omitempty"`
+	Action      string `json:"action"`
+	CompletedAt string `json:"completed_at"`
+}
+
+const applyJournalFormatVersion = "1.0"
+
+// JournalHook is a hook that appends a record of each successfully-completed
+// resource action to a checkpoint journal file, so that an interrupted apply
+// can later skip the already-completed actions using the -resume option.
+type JournalHook struct {
+	terraform.NilHook
+	sync.Mutex
+
+	file *os.File
+
+	// pending tracks the planned action for each object that is currently
+	// being applied, so that PostApply can record it. The keys are produced
+	// by applyJournalKey.
+	pending map[string]plans.Action
+}
+
+var _ terraform.Hook = (*JournalHook)(nil)
+
+// openApplyJournal opens the checkpoint journal file at the given path for
+// appending, creating it with a format header if it doesn't already exist
+// or is empty. Opening an existing journal is allowed so that a resumed
+// apply can continue recording into the same file with -checkpoint.
+func openApplyJournal(path string) (*JournalHook, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		header, err := json.Marshal(applyJournalHeader{FormatVersion: applyJournalFormatVersion})
+		if err != nil {
+			// Should never happen, since our header type is so simple.
+			f.Close()
+			return nil, err
+		}
+		if _, err := fmt.Fprintf(f, "%s\n", header); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &JournalHook{
+		file:    f,
+		pending: make(map[string]plans.Action),
+	}, nil
+}
+
+// Close flushes and closes the underlying journal file. The hook must not
+// be used again after calling Close.
+func (h *JournalHook) Close() error {
+	h.Lock()
+	defer h.Unlock()
+	return h.file.Close()
+}
+
+func (h *JournalHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.pending[applyJournalKey(addr, gen)] = action
+	return terraform.HookActionContinue, nil
+}
+
+func (h *JournalHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (terraform.HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	key := applyJournalKey(addr, gen)
+	action, ok := h.pending[key]
+	delete(h.pending, key)
+	if err != nil || !ok {
+		// We only journal actions that completed successfully; a failed
+		// action must run again on resume.
+		return terraform.HookActionContinue, nil
+	}
+
+	entry := applyJournalEntry{
+		Resource:    addr.String(),
+		Action:      action.String(),
+		CompletedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if dk, ok := gen.(states.DeposedKey); ok && dk != states.NotDeposed {
+		entry.Deposed = string(dk)
+	}
+	line, jsonErr := json.Marshal(entry)
+	if jsonErr != nil {
+		return terraform.HookActionHalt, jsonErr
+	}
+	if _, writeErr := fmt.Fprintf(h.file, "%s\n", line); writeErr != nil {
+		return terraform.HookActionHalt, writeErr
+	}
+	// Sync after each entry so that the journal reflects the completed
+	// action even if the process is subsequently killed outright.
+	if syncErr := h.file.Sync(); syncErr != nil {
+		return terraform.HookActionHalt, syncErr
+	}
+
+	return terraform.HookActionContinue, nil
+}
+
+// applyJournalKey produces the map/journal key for a particular resource
+// instance object, distinguishing deposed objects from current ones.
+func applyJournalKey(addr addrs.AbsResourceInstance, gen states.Generation) string {
+	if dk, ok := gen.(states.DeposedKey); ok && dk != states.NotDeposed {
+		return addr.String() + "|" + string(dk)
+	}
+	return addr.String()
+}
+
+// readApplyJournal reads a checkpoint journal file previously written by
+// JournalHook, returning the recorded action for each completed object
+// keyed as in applyJournalKey. Lines that don't describe a resource, such
+// as the format header, are ignored.
+func readApplyJournal(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	completed := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry applyJournalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("invalid journal line %q: %s", line, err)
+		}
+		if entry.Resource == "" {
+			continue
+		}
+		key := entry.Resource
+		if entry.Deposed != "" {
+			key += "|" + entry.Deposed
+		}
+		completed[key] = entry.Action
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// filterResumedChanges removes from the given plan any resource instance
+// changes whose actions the checkpoint journal at the given path records
+// as already completed, as long as the given state agrees that the action
+// took effect. Changes whose journal entries contradict the state are kept
+// and reported as warnings, since re-applying them is the safe choice.
+func filterResumedChanges(plan *plans.Plan, state *states.State, journalPath string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	completed, err := readApplyJournal(journalPath)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read checkpoint journal",
+			fmt.Sprintf("Cannot resume from %s: %s.", journalPath, err),
+		))
+		return diags
+	}
+
+	var remain []*plans.ResourceInstanceChangeSrc
+	for _, change := range plan.Changes.Resources {
+		key := change.Addr.String()
+		if change.DeposedKey != states.NotDeposed {
+			key += "|" + string(change.DeposedKey)
+		}
+		action, ok := completed[key]
+		if !ok || action != change.Action.String() {
+			remain = append(remain, change)
+			continue
+		}
+
+		// The journal says this action already completed, but we'll trust
+		// it only if the state agrees, since the journal entry might be
+		// from an apply against a different state snapshot.
+		is := state.ResourceInstance(change.Addr)
+		var verified bool
+		if change.Action == plans.Delete {
+			if change.DeposedKey != states.NotDeposed {
+				verified = !is.HasDeposed(change.DeposedKey)
+			} else {
+				verified = !is.HasCurrent()
+			}
+		} else {
+			verified = is.HasCurrent()
+		}
+		if !verified {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Completed action not reflected in state",
+				fmt.Sprintf("The checkpoint journal records that the %s action for %s already completed, but the current state does not reflect that, so Terraform will apply this change again.", change.Action, change.Addr),
+			))
+			remain = append(remain, change)
+		}
+	}
+	plan.Changes.Resources = remain
+
+	return diags
+}
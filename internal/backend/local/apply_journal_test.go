@@ -0,0 +1,133 @@
+package local
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/states"
+)
+
+func TestJournalHook(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "journal")
+
+	hook, err := openApplyJournal(journalPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	fooAddr := mustResourceInstanceAddr("test_instance.foo")
+	barAddr := mustResourceInstanceAddr("test_instance.bar")
+
+	// A successful action should be recorded.
+	hook.PreApply(fooAddr, states.CurrentGen, plans.Create, cty.NilVal, cty.NilVal)
+	hook.PostApply(fooAddr, states.CurrentGen, cty.NilVal, nil)
+
+	// A failed action should not be recorded.
+	hook.PreApply(barAddr, states.CurrentGen, plans.Create, cty.NilVal, cty.NilVal)
+	hook.PostApply(barAddr, states.CurrentGen, cty.NilVal, errors.New("boom"))
+
+	// A deposed object's action should be recorded with its deposed key.
+	hook.PreApply(barAddr, states.DeposedKey("deadbeef"), plans.Delete, cty.NilVal, cty.NilVal)
+	hook.PostApply(barAddr, states.DeposedKey("deadbeef"), cty.NilVal, nil)
+
+	if err := hook.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := readApplyJournal(journalPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := map[string]string{
+		"test_instance.foo":          plans.Create.String(),
+		"test_instance.bar|deadbeef": plans.Delete.String(),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong entries %#v; want %#v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("wrong action for %s: got %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestFilterResumedChanges(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "journal")
+
+	fooAddr := mustResourceInstanceAddr("test_instance.foo")
+	barAddr := mustResourceInstanceAddr("test_instance.bar")
+	bazAddr := mustResourceInstanceAddr("test_instance.baz")
+	quxAddr := mustResourceInstanceAddr("test_instance.qux")
+
+	// Journal records foo (create), bar (create), and baz (delete) as done.
+	hook, err := openApplyJournal(journalPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, step := range []struct {
+		addr   addrs.AbsResourceInstance
+		action plans.Action
+	}{
+		{fooAddr, plans.Create},
+		{barAddr, plans.Create},
+		{bazAddr, plans.Delete},
+	} {
+		hook.PreApply(step.addr, states.CurrentGen, step.action, cty.NilVal, cty.NilVal)
+		hook.PostApply(step.addr, states.CurrentGen, cty.NilVal, nil)
+	}
+	if err := hook.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The state confirms foo's create and baz's delete, but not bar's
+	// create, so only foo and baz should be skipped.
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceCurrent(
+		fooAddr.Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"id":"foo"}`),
+		},
+		mustProviderConfig(`provider["registry.terraform.io/hashicorp/test"]`),
+	)
+
+	plan := &plans.Plan{
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				{Addr: fooAddr, ChangeSrc: plans.ChangeSrc{Action: plans.Create}},
+				{Addr: barAddr, ChangeSrc: plans.ChangeSrc{Action: plans.Create}},
+				{Addr: bazAddr, ChangeSrc: plans.ChangeSrc{Action: plans.Delete}},
+				{Addr: quxAddr, ChangeSrc: plans.ChangeSrc{Action: plans.Create}},
+			},
+		},
+	}
+
+	diags := filterResumedChanges(plan, state, journalPath)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if got, want := len(diags), 1; got != want {
+		t.Fatalf("wrong number of warnings %d; want %d", got, want)
+	}
+
+	var remain []string
+	for _, change := range plan.Changes.Resources {
+		remain = append(remain, change.Addr.String())
+	}
+	want := []string{"test_instance.bar", "test_instance.qux"}
+	if len(remain) != len(want) {
+		t.Fatalf("wrong remaining changes %#v; want %#v", remain, want)
+	}
+	for i := range want {
+		if remain[i] != want[i] {
+			t.Errorf("wrong remaining change at %d: got %s, want %s", i, remain[i], want[i])
+		}
+	}
+}
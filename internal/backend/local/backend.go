@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/terraform/internal/command/views"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/logging"
+	"github.com/hashicorp/terraform/internal/states/encryption"
 	"github.com/hashicorp/terraform/internal/states/statemgr"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
@@ -59,6 +60,12 @@ type Local struct {
 	OverrideStateOutPath    string
 	OverrideStateBackupPath string
 
+	// StateEncryption, if non-nil, is applied to the state managers
+	// returned by StateMgr so that state snapshots are encrypted before
+	// they are written to disk. It is set from the optional "encryption"
+	// block in the backend configuration.
+	StateEncryption *encryption.Encryptor
+
 	// We only want to create a single instance of a local state, so store them
 	// here as they're loaded.
 	states map[string]statemgr.Full
@@ -117,6 +124,32 @@ func (b *Local) ConfigSchema() *configschema.Block {
 				Optional: true,
 			},
 		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"encryption": {
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"passphrase": {
+							Type:      cty.String,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"passphrase_env": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"key_command": {
+							Type:     cty.String,
+							Optional: true,
+						},
+						"iterations": {
+							Type:     cty.Number,
+							Optional: true,
+						},
+					},
+				},
+				Nesting: configschema.NestingSingle,
+			},
+		},
 	}
 }
 
@@ -151,6 +184,23 @@ func (b *Local) PrepareConfig(obj cty.Value) (cty.Value, tfdiags.Diagnostics) {
 		}
 	}
 
+	if val := obj.GetAttr("encryption"); !val.IsNull() {
+		sources := 0
+		for _, name := range []string{"passphrase", "passphrase_env", "key_command"} {
+			if v := val.GetAttr(name); !v.IsNull() && v.AsString() != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid state encryption configuration",
+				`Exactly one of "passphrase", "passphrase_env", or "key_command" must be set in the "encryption" block.`,
+				cty.Path{cty.GetAttrStep{Name: "encryption"}},
+			))
+		}
+	}
+
 	return obj, diags
 }
 
@@ -177,6 +227,35 @@ func (b *Local) Configure(obj cty.Value) tfdiags.Diagnostics {
 		b.StateWorkspaceDir = DefaultWorkspaceDir
 	}
 
+	if val := obj.GetAttr("encryption"); !val.IsNull() {
+		var config encryption.Config
+		if v := val.GetAttr("passphrase"); !v.IsNull() {
+			config.Passphrase = v.AsString()
+		}
+		if v := val.GetAttr("passphrase_env"); !v.IsNull() {
+			config.PassphraseEnv = v.AsString()
+		}
+		if v := val.GetAttr("key_command"); !v.IsNull() {
+			config.KeyCommand = v.AsString()
+		}
+		if v := val.GetAttr("iterations"); !v.IsNull() {
+			n, _ := v.AsBigFloat().Int64()
+			config.Iterations = int(n)
+		}
+
+		enc, err := encryption.New(config)
+		if err != nil {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid state encryption configuration",
+				fmt.Sprintf("Cannot configure state encryption: %s.", err),
+				cty.Path{cty.GetAttrStep{Name: "encryption"}},
+			))
+			return diags
+		}
+		b.StateEncryption = enc
+	}
+
 	return diags
 }
 
@@ -201,7 +280,13 @@ func (b *Local) Workspaces() ([]string, error) {
 	var listed []string
 	for _, entry := range entries {
 		if entry.IsDir() {
-			listed = append(listed, filepath.Base(entry.Name()))
+			name := filepath.Base(entry.Name())
+			// The default workspace is already in the listing, but it may
+			// also have a directory here holding its metadata.
+			if name == backend.DefaultStateName {
+				continue
+			}
+			listed = append(listed, name)
 		}
 	}
 
@@ -253,6 +338,9 @@ func (b *Local) StateMgr(name string) (statemgr.Full, error) {
 	if backupPath != "" {
 		s.SetBackupPath(backupPath)
 	}
+	if b.StateEncryption != nil {
+		s.SetEncryption(b.StateEncryption)
+	}
 
 	if b.states == nil {
 		b.states = map[string]statemgr.Full{}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform/internal/backend"
 	"github.com/hashicorp/terraform/internal/command/views"
@@ -46,6 +48,31 @@ func (b *Local) opApply(
 	stateHook := new(StateHook)
 	op.Hooks = append(op.Hooks, stateHook)
 
+	// If the apply is allowed to continue past failures then we track which
+	// instances were attempted so we can report the ones that were not.
+	var progressHook *applyProgressHook
+	if op.ContinueOnError {
+		progressHook = newApplyProgressHook()
+		op.Hooks = append(op.Hooks, progressHook)
+	}
+
+	// If requested, record each completed resource action to a checkpoint
+	// journal so an interrupted apply can later be resumed.
+	if op.CheckpointPath != "" {
+		journalHook, err := openApplyJournal(op.CheckpointPath)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to open checkpoint journal",
+				fmt.Sprintf("Cannot record completed actions in %s: %s.", op.CheckpointPath, err),
+			))
+			op.ReportResult(runningOp, diags)
+			return
+		}
+		defer journalHook.Close()
+		op.Hooks = append(op.Hooks, journalHook)
+	}
+
 	// Get our context
 	lr, _, opState, contextDiags := b.localRun(op)
 	diags = diags.Append(contextDiags)
@@ -169,6 +196,62 @@ func (b *Local) opApply(
 		}
 	}
 
+	// If the caller set a destroy budget then we refuse to proceed when the
+	// plan would destroy or replace more resource instances than allowed,
+	// regardless of any approval given above. This is a guardrail for
+	// automation that auto-approves routine plans but wants unusually
+	// destructive ones to be reviewed by a human first.
+	if op.AutoApproveMaxDestroy >= 0 {
+		destroyCount := 0
+		for _, change := range plan.Changes.Resources {
+			if change.Action == plans.Delete || change.Action.IsReplace() {
+				destroyCount++
+			}
+		}
+		if destroyCount > op.AutoApproveMaxDestroy {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Too many resource instances to destroy",
+				fmt.Sprintf(
+					"This plan would destroy or replace %d resource instances, but the -auto-approve-max-destroy option allows at most %d. Terraform has not made any changes.\n\nIf these destroy actions are intended then re-run this command with a higher limit, or review and apply the plan interactively.",
+					destroyCount, op.AutoApproveMaxDestroy,
+				),
+			))
+			op.View.Diagnostics(diags)
+			runningOp.Result = backend.OperationBlocked
+			return
+		}
+	}
+
+	// If a plan gate program is configured then it gets the final say on
+	// whether this plan may be applied at all, before we make any changes.
+	if op.PlanGateProgram != "" {
+		schemas, moreDiags := lr.Core.Schemas(lr.Config, lr.InputState)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+		moreDiags = checkPlanGate(op.PlanGateProgram, lr.Config, plan, schemas)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
+
+	// If we're resuming from a checkpoint journal then we can drop any
+	// planned changes whose actions already completed, as long as the
+	// state we loaded agrees that they took effect.
+	if op.ResumePath != "" {
+		moreDiags = filterResumedChanges(plan, lr.InputState, op.ResumePath)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			op.ReportResult(runningOp, diags)
+			return
+		}
+	}
+
 	// Set up our hook for continuous state updates
 	stateHook.StateMgr = opState
 
@@ -214,6 +297,15 @@ func (b *Local) opApply(
 	}
 
 	if applyDiags.HasErrors() {
+		if progressHook != nil {
+			// Terraform already carried on with any changes unaffected by
+			// the failures, so all that remains is to summarize the outcome
+			// and report it with the distinct partial-failure status.
+			diags = diags.Append(partialApplySummary(plan, progressHook))
+			op.View.Diagnostics(diags)
+			runningOp.Result = backend.OperationPartialFailure
+			return
+		}
 		op.ReportResult(runningOp, diags)
 		return
 	}
@@ -224,6 +316,38 @@ func (b *Local) opApply(
 	op.View.Diagnostics(diags)
 }
 
+// partialApplySummary produces a consolidated warning describing the outcome
+// of an apply that continued past failures: which planned resource instances
+// failed, and which were never attempted because something they depend on
+// failed first.
+func partialApplySummary(plan *plans.Plan, hook *applyProgressHook) tfdiags.Diagnostic {
+	failed, deferred := hook.outcome(plan.Changes)
+	sort.Strings(failed)
+	sort.Strings(deferred)
+
+	var buf strings.Builder
+	buf.WriteString("Terraform continued applying changes after one or more operations failed, as requested with -continue-on-error.\n")
+	if len(failed) > 0 {
+		buf.WriteString("\nThe following resource instances failed, as described in the errors above:\n")
+		for _, addr := range failed {
+			fmt.Fprintf(&buf, "  - %s\n", addr)
+		}
+	}
+	if len(deferred) > 0 {
+		buf.WriteString("\nThe following resource instances were not changed because they depend on a failed operation:\n")
+		for _, addr := range deferred {
+			fmt.Fprintf(&buf, "  - %s\n", addr)
+		}
+	}
+	buf.WriteString("\nAfter resolving the errors, apply again to complete the remaining changes.")
+
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Apply incomplete",
+		buf.String(),
+	)
+}
+
 // backupStateForError is called in a scenario where we're unable to persist the
 // state for some reason, and will attempt to save a backup copy of the state
 // to local disk to help the user recover. This is a "last ditch effort" sort
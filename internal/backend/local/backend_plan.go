@@ -2,10 +2,18 @@ package local
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/logging"
 	"github.com/hashicorp/terraform/internal/plans"
 	"github.com/hashicorp/terraform/internal/plans/planfile"
@@ -13,6 +21,7 @@ import (
 	"github.com/hashicorp/terraform/internal/states/statemgr"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
+	tfversion "github.com/hashicorp/terraform/version"
 )
 
 func (b *Local) opPlan(
@@ -104,6 +113,16 @@ func (b *Local) opPlan(
 	// Record whether this plan includes any side-effects that could be applied.
 	runningOp.PlanEmpty = !plan.CanApply()
 
+	// Record whether the refresh phase detected any changes made outside of
+	// Terraform. Move-only changes are recorded as no-op drift, so they
+	// don't count.
+	for _, dr := range plan.DriftedResources {
+		if dr.Action != plans.NoOp {
+			runningOp.DriftDetected = true
+			break
+		}
+	}
+
 	// Save the plan to disk
 	if path := op.PlanOutPath; path != "" {
 		if op.PlanOutBackend == nil {
@@ -134,13 +153,28 @@ func (b *Local) opPlan(
 			State: plan.PrevRunState,
 		}
 
+		// We record the environment the plan was created in alongside the
+		// plan itself, so that "terraform show -metadata" can display it
+		// and "terraform apply -strict-environment" can check it.
+		planEnv, err := planRunEnvironment(op, lr.Config, plan)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write plan file",
+				fmt.Sprintf("Failed to summarize the run environment for the plan file: %s.", err),
+			))
+			op.ReportResult(runningOp, diags)
+			return
+		}
+
 		log.Printf("[INFO] backend/local: writing plan output to: %s", path)
-		err := planfile.Create(path, planfile.CreateArgs{
+		err = planfile.Create(path, planfile.CreateArgs{
 			ConfigSnapshot:       configSnap,
 			PreviousRunStateFile: prevStateFile,
 			StateFile:            plannedStateFile,
 			Plan:                 plan,
 			DependencyLocks:      op.DependencyLocks,
+			Environment:          planEnv,
 		})
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -171,3 +205,54 @@ func (b *Local) opPlan(
 		op.View.PlanNextStep(op.PlanOutPath)
 	}
 }
+
+// planRunEnvironment summarizes the environment a plan was created in, for
+// embedding in the saved plan file: the Terraform version, the workspace
+// name, the provider versions selected in the dependency lock file, and the
+// input variable values the plan was created with. Values of variables
+// marked as sensitive in the configuration are recorded only as a SHA-256
+// digest.
+func planRunEnvironment(op *backend.Operation, config *configs.Config, plan *plans.Plan) (*planfile.Environment, error) {
+	env := &planfile.Environment{
+		TerraformVersion: tfversion.String(),
+		Workspace:        op.Workspace,
+		Providers:        make(map[string]*planfile.EnvironmentProvider),
+		Variables:        make(map[string]*planfile.EnvironmentVariable),
+	}
+
+	if op.DependencyLocks != nil {
+		for addr, lock := range op.DependencyLocks.AllProviders() {
+			provider := &planfile.EnvironmentProvider{
+				Version: lock.Version().String(),
+			}
+			for _, hash := range lock.AllHashes() {
+				provider.Hashes = append(provider.Hashes, hash.String())
+			}
+			sort.Strings(provider.Hashes)
+			env.Providers[addr.String()] = provider
+		}
+	}
+
+	for name, dv := range plan.VariableValues {
+		if vc, ok := config.Module.Variables[name]; ok && vc.Sensitive {
+			digest := sha256.Sum256([]byte(dv))
+			env.Variables[name] = &planfile.EnvironmentVariable{
+				ValueSHA256: hex.EncodeToString(digest[:]),
+			}
+			continue
+		}
+		val, err := dv.Decode(cty.DynamicPseudoType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for input variable %q: %s", name, err)
+		}
+		src, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			return nil, fmt.Errorf("can't serialize value of input variable %q: %s", name, err)
+		}
+		env.Variables[name] = &planfile.EnvironmentVariable{
+			Value: json.RawMessage(src),
+		}
+	}
+
+	return env, nil
+}
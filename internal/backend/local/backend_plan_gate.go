@@ -0,0 +1,87 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/command/jsonplan"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/terraform"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// planGateTimeout bounds how long a plan gate program may run before we
+// give up on it, so that a hung policy system cannot hang Terraform
+// indefinitely.
+const planGateTimeout = 1 * time.Minute
+
+// checkPlanGate runs the program configured with the plan_gate_program CLI
+// configuration setting, sending it the JSON representation of the given
+// plan on its stdin. If the program exits with a non-zero status then the
+// returned diagnostics contain an error quoting the program's output, and
+// the caller must not apply the plan.
+func checkPlanGate(program string, config *configs.Config, plan *plans.Plan, schemas *terraform.Schemas) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	planJSON, err := jsonplan.Marshal(config, plan, nil, schemas)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to run plan gate program",
+			fmt.Sprintf("Cannot produce the JSON plan representation for %s: %s.", program, err),
+		))
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), planGateTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, program)
+	cmd.Stdin = bytes.NewReader(planJSON)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan gate program timed out",
+			fmt.Sprintf("The plan gate program %s did not decide within %s whether this plan may be applied, so the apply was aborted.", program, planGateTimeout),
+		))
+		return diags
+	}
+	if err != nil {
+		if _, isExit := err.(*exec.ExitError); !isExit {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to run plan gate program",
+				fmt.Sprintf("Cannot run the plan gate program %s: %s.", program, err),
+			))
+			return diags
+		}
+
+		// The program rejected the plan, so we'll report its output as the
+		// reason. The program may explain itself on either stream, so we
+		// prefer stderr but fall back to stdout.
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		if message == "" {
+			message = "The plan gate program did not explain its decision."
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Apply rejected by plan gate program",
+			fmt.Sprintf("The plan gate program %s rejected this plan:\n\n%s", program, message),
+		))
+	}
+
+	return diags
+}
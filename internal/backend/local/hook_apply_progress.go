@@ -0,0 +1,75 @@
+package local
+
+import (
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/terraform"
+)
+
+// applyProgressHook records which resource instances an apply operation
+// started work on and which of those failed, so that after an apply that
+// continued past failures we can report both the instances that failed and
+// the instances that were never attempted because something they depend on
+// failed first.
+type applyProgressHook struct {
+	terraform.NilHook
+
+	mu      sync.Mutex
+	started map[string]struct{}
+	failed  []string
+}
+
+var _ terraform.Hook = (*applyProgressHook)(nil)
+
+func newApplyProgressHook() *applyProgressHook {
+	return &applyProgressHook{
+		started: make(map[string]struct{}),
+	}
+}
+
+func (h *applyProgressHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (terraform.HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.started[addr.String()] = struct{}{}
+	return terraform.HookActionContinue, nil
+}
+
+func (h *applyProgressHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (terraform.HookAction, error) {
+	if err == nil {
+		return terraform.HookActionContinue, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failed = append(h.failed, addr.String())
+	return terraform.HookActionContinue, nil
+}
+
+// outcome returns the addresses of the instances that failed and, given the
+// set of planned changes, the addresses of the instances that were planned
+// for a change but never attempted.
+func (h *applyProgressHook) outcome(changes *plans.Changes) (failed, deferred []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen := make(map[string]struct{})
+	for _, rc := range changes.Resources {
+		if rc.Action == plans.NoOp {
+			continue
+		}
+		key := rc.Addr.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if _, ok := h.started[key]; !ok {
+			deferred = append(deferred, key)
+		}
+	}
+
+	return h.failed, deferred
+}
@@ -0,0 +1,93 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/internal/backend"
+)
+
+// workspaceMetaFilename is the name of the file that stores a workspace's
+// metadata, inside the same directory as the workspace's state.
+const workspaceMetaFilename = "workspace-meta.json"
+
+var _ backend.WorkspaceMetadataStore = (*Local)(nil)
+
+// WorkspaceMetadata is an implementation of backend.WorkspaceMetadataStore.
+func (b *Local) WorkspaceMetadata(name string) (*backend.WorkspaceMetadata, error) {
+	// If we have a backend handling state, defer to that.
+	if b.Backend != nil {
+		if s, ok := b.Backend.(backend.WorkspaceMetadataStore); ok {
+			return s.WorkspaceMetadata(name)
+		}
+		return nil, errors.New("the configured backend does not support workspace metadata")
+	}
+
+	if name == "" {
+		return nil, errors.New("empty state name")
+	}
+
+	src, err := os.ReadFile(b.workspaceMetaPath(name))
+	if os.IsNotExist(err) {
+		return &backend.WorkspaceMetadata{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace metadata: %w", err)
+	}
+
+	ret := &backend.WorkspaceMetadata{}
+	if err := json.Unmarshal(src, ret); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace metadata: %w", err)
+	}
+	return ret, nil
+}
+
+// SetWorkspaceMetadata is an implementation of backend.WorkspaceMetadataStore.
+func (b *Local) SetWorkspaceMetadata(name string, meta *backend.WorkspaceMetadata) error {
+	// If we have a backend handling state, defer to that.
+	if b.Backend != nil {
+		if s, ok := b.Backend.(backend.WorkspaceMetadataStore); ok {
+			return s.SetWorkspaceMetadata(name, meta)
+		}
+		return errors.New("the configured backend does not support workspace metadata")
+	}
+
+	if name == "" {
+		return errors.New("empty state name")
+	}
+
+	path := b.workspaceMetaPath(name)
+
+	// Metadata with nothing in it is equivalent to no metadata at all, so
+	// we remove the file rather than keeping an empty record around.
+	if meta == nil || (meta.Description == "" && len(meta.Tags) == 0) {
+		err := os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove workspace metadata: %w", err)
+		}
+		return nil
+	}
+
+	buf, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize workspace metadata: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to prepare workspace directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		return fmt.Errorf("failed to write workspace metadata: %w", err)
+	}
+	return nil
+}
+
+// workspaceMetaPath returns the path of the metadata file for the given
+// workspace. The "default" workspace keeps its state outside the workspace
+// directory, but its metadata still lives under the workspace directory so
+// that all metadata is stored consistently.
+func (b *Local) workspaceMetaPath(name string) string {
+	return filepath.Join(b.stateWorkspaceDir(), name, workspaceMetaFilename)
+}
@@ -0,0 +1,184 @@
+// Package git implements a remote state backend that stores state as
+// files committed to a branch of a git repository, so that small teams
+// can version their state in a repository they already have without
+// running any extra infrastructure. Locking uses a ref outside the
+// branch and tag namespaces, relying on the atomicity of remote ref
+// creation for mutual exclusion.
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/legacy/helper/schema"
+)
+
+// New creates a new backend for git remote state.
+func New() backend.Backend {
+	s := &schema.Backend{
+		Schema: map[string]*schema.Schema{
+			"url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The URL of the git repository, in any form accepted by the git tool",
+			},
+
+			"branch": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The branch that state is committed to",
+				Default:     "terraform-state",
+			},
+
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The repository-relative path of the state file for the default workspace",
+				Default:     "terraform.tfstate",
+			},
+
+			"workspace_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The repository-relative directory holding the state of non-default workspaces",
+				Default:     "terraform.tfstate.d",
+			},
+
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The username for HTTP basic authentication, used together with token",
+				DefaultFunc: schema.EnvDefaultFunc("TF_GIT_USERNAME", "git"),
+			},
+
+			"token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An access token for HTTP basic authentication",
+				DefaultFunc: schema.EnvDefaultFunc("TF_GIT_TOKEN", ""),
+			},
+
+			"ssh_private_key_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path of an SSH private key file to authenticate with, for SSH repository URLs",
+				DefaultFunc: schema.EnvDefaultFunc("TF_GIT_SSH_PRIVATE_KEY_FILE", ""),
+			},
+
+			"author_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The author name recorded on state commits",
+				Default:     "Terraform",
+			},
+
+			"author_email": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The author email address recorded on state commits",
+				Default:     "terraform@localhost",
+			},
+		},
+	}
+
+	result := &Backend{Backend: s}
+	result.Backend.ConfigureFunc = result.configure
+	return result
+}
+
+type Backend struct {
+	*schema.Backend
+
+	// The fields below are set from configure
+	repo         *repo
+	branch       string
+	path         string
+	workspaceDir string
+}
+
+func (b *Backend) configure(ctx context.Context) error {
+	data := schema.FromContextBackendConfig(ctx)
+
+	url := data.Get("url").(string)
+	b.branch = data.Get("branch").(string)
+	b.path = data.Get("path").(string)
+	b.workspaceDir = data.Get("workspace_dir").(string)
+
+	for name, value := range map[string]string{
+		"path":          b.path,
+		"workspace_dir": b.workspaceDir,
+	} {
+		if err := validateRelPath(value); err != nil {
+			return fmt.Errorf("invalid %s %q: %s", name, value, err)
+		}
+	}
+
+	// Auth is handled entirely by the git tool: tokens become an HTTP
+	// basic auth header, and an SSH key is passed on to ssh. Terminal
+	// prompting is disabled so that missing credentials fail instead of
+	// hanging a background operation.
+	env := []string{
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_AUTHOR_NAME=" + data.Get("author_name").(string),
+		"GIT_AUTHOR_EMAIL=" + data.Get("author_email").(string),
+		"GIT_COMMITTER_NAME=" + data.Get("author_name").(string),
+		"GIT_COMMITTER_EMAIL=" + data.Get("author_email").(string),
+	}
+	if token := data.Get("token").(string); token != "" {
+		creds := data.Get("username").(string) + ":" + token
+		header := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(creds))
+		// The header goes through the environment rather than as a "-c"
+		// command-line argument so that the credential is never visible
+		// to other local users in the process listing.
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0="+header,
+		)
+	}
+	if keyFile := data.Get("ssh_private_key_file").(string); keyFile != "" {
+		if _, err := os.Stat(keyFile); err != nil {
+			return fmt.Errorf("cannot use ssh_private_key_file %q: %s", keyFile, err)
+		}
+		// GIT_SSH_COMMAND is parsed by a shell, so the key path needs
+		// shell-style quoting rather than Go's %q.
+		env = append(env, "GIT_SSH_COMMAND=ssh -i "+shellQuote(keyFile)+" -o IdentitiesOnly=yes")
+	}
+
+	repo, err := newRepo(url, env)
+	if err != nil {
+		return err
+	}
+	b.repo = repo
+
+	return nil
+}
+
+// shellQuote wraps the given string in single quotes, escaping any single
+// quotes within it, so that the result always parses as a single shell word
+// regardless of the characters it contains.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// validateRelPath requires a slash-separated path that stays within the
+// repository, since the configured paths are used both as git paths and
+// as paths in our local working tree.
+func validateRelPath(p string) error {
+	if p == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if strings.HasPrefix(p, "/") {
+		return fmt.Errorf("must be relative to the repository root")
+	}
+	clean := path.Clean(p)
+	if clean != p || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("must be a clean path within the repository")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/remote"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
+)
+
+func (b *Backend) Workspaces() ([]string, error) {
+	b.repo.mu.Lock()
+	defer b.repo.mu.Unlock()
+
+	result := []string{
+		backend.DefaultStateName,
+	}
+
+	exists, err := b.repo.fetchRef("refs/heads/" + b.branch)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return result, nil
+	}
+
+	out, err := b.repo.run("ls-tree", "-r", "--name-only", "FETCH_HEAD", "--", b.workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	prefix := b.workspaceDir + "/"
+	suffix := "/" + b.path
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, prefix) || !strings.HasSuffix(line, suffix) {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(line, prefix), suffix)
+		if name == "" || strings.Contains(name, "/") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return append(result, names...), nil
+}
+
+func (b *Backend) DeleteWorkspace(name string) error {
+	if name == backend.DefaultStateName || name == "" {
+		return fmt.Errorf("can't delete default state")
+	}
+
+	client := b.client(name)
+	return client.Delete()
+}
+
+func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
+	var stateMgr statemgr.Full = &remote.State{
+		Client: b.client(name),
+	}
+
+	// Check to see if this state already exists.
+	// If the state doesn't exist, we have to assume this
+	// is a normal create operation, and take the lock at that point.
+	existing, err := b.Workspaces()
+	if err != nil {
+		return nil, err
+	}
+
+	exists := false
+	for _, s := range existing {
+		if s == name {
+			exists = true
+			break
+		}
+	}
+
+	// Grab a lock, we use this to write an empty state if one doesn't
+	// exist already, so that the workspace shows up in Workspaces().
+	if !exists {
+		lockInfo := statemgr.NewLockInfo()
+		lockInfo.Operation = "init"
+		lockId, err := stateMgr.Lock(lockInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock state in the git repository: %s", err)
+		}
+
+		// Local helper function so we can call it multiple places
+		lockUnlock := func(parent error) error {
+			if err := stateMgr.Unlock(lockId); err != nil {
+				return fmt.Errorf("error unlocking git repository state: %s", err)
+			}
+			return parent
+		}
+
+		if v := stateMgr.State(); v == nil {
+			if err := stateMgr.WriteState(states.NewState()); err != nil {
+				err = lockUnlock(err)
+				return nil, err
+			}
+			if err := stateMgr.PersistState(); err != nil {
+				err = lockUnlock(err)
+				return nil, err
+			}
+		}
+
+		// Unlock, the state should now be initialized
+		if err := lockUnlock(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return stateMgr, nil
+}
+
+// client returns the remote client for the given workspace.
+func (b *Backend) client(name string) *RemoteClient {
+	statePath := b.statePath(name)
+	return &RemoteClient{
+		Repo:    b.repo,
+		Branch:  b.branch,
+		Path:    statePath,
+		LockRef: "refs/locks/" + statePath,
+	}
+}
+
+// statePath returns the repository-relative path of the state file for
+// the given workspace.
+func (b *Backend) statePath(name string) string {
+	if name == backend.DefaultStateName {
+		return b.path
+	}
+	return path.Join(b.workspaceDir, name, b.path)
+}
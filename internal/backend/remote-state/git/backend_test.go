@@ -0,0 +1,62 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/backend"
+)
+
+func TestBackend_impl(t *testing.T) {
+	var _ backend.Backend = new(Backend)
+}
+
+// testBareRepoURL creates an empty bare repository for a test to push
+// state into, returning a URL for it.
+func testBareRepoURL(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available in PATH")
+	}
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", dir).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create bare repository: %s\n%s", err, out)
+	}
+	return "file://" + dir
+}
+
+func TestBackendStates(t *testing.T) {
+	url := testBareRepoURL(t)
+
+	b := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+		"url": url,
+	})).(*Backend)
+
+	backend.TestBackendStates(t, b)
+}
+
+func TestBackendStateLocks(t *testing.T) {
+	url := testBareRepoURL(t)
+
+	b1 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+		"url": url,
+	})).(*Backend)
+	b2 := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+		"url": url,
+	})).(*Backend)
+
+	backend.TestBackendStateLocks(t, b1, b2)
+}
+
+func TestValidateRelPath(t *testing.T) {
+	if err := validateRelPath("../outside.tfstate"); err == nil {
+		t.Errorf("expected error for path outside the repository")
+	}
+	if err := validateRelPath("/absolute.tfstate"); err == nil {
+		t.Errorf("expected error for absolute path")
+	}
+	if err := validateRelPath("envs/prod/terraform.tfstate"); err != nil {
+		t.Errorf("unexpected error for valid path: %s", err)
+	}
+}
@@ -0,0 +1,249 @@
+package git
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform/internal/states/remote"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
+)
+
+// RemoteClient is a remote client that stores state as a file committed
+// to a branch of a git repository, using a lock ref for locking.
+type RemoteClient struct {
+	Repo   *repo
+	Branch string
+
+	// Path is the repository-relative path of the state file for the
+	// workspace this client is for.
+	Path string
+
+	// LockRef is the full name of the ref used to lock this workspace's
+	// state, outside the usual branch and tag namespaces.
+	LockRef string
+}
+
+func (c *RemoteClient) Get() (*remote.Payload, error) {
+	c.Repo.mu.Lock()
+	defer c.Repo.mu.Unlock()
+
+	exists, err := c.Repo.fetchRef("refs/heads/" + c.Branch)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		// The state branch hasn't been created yet, so there's no state.
+		return nil, nil
+	}
+
+	data, err := c.Repo.readFile("FETCH_HEAD", c.Path)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	md5 := md5.Sum(data)
+	return &remote.Payload{
+		Data: data,
+		MD5:  md5[:],
+	}, nil
+}
+
+func (c *RemoteClient) Put(data []byte) error {
+	c.Repo.mu.Lock()
+	defer c.Repo.mu.Unlock()
+
+	if err := c.checkoutBranch(); err != nil {
+		return err
+	}
+
+	fullPath := filepath.Join(c.Repo.dir, filepath.FromSlash(c.Path))
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to prepare state directory: %s", err)
+	}
+	if err := os.WriteFile(fullPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %s", err)
+	}
+	if _, err := c.Repo.run("add", "--", c.Path); err != nil {
+		return err
+	}
+
+	// If the new state is byte-for-byte identical to what's already
+	// committed then there's nothing to record.
+	if _, err := c.Repo.run("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+
+	message := fmt.Sprintf("Update Terraform state at %s", c.Path)
+	var meta struct {
+		Serial uint64 `json:"serial"`
+	}
+	if err := json.Unmarshal(data, &meta); err == nil {
+		message = fmt.Sprintf("%s (serial %d)", message, meta.Serial)
+	}
+	if _, err := c.Repo.run("commit", "--quiet", "-m", message); err != nil {
+		return err
+	}
+
+	return c.pushBranch()
+}
+
+func (c *RemoteClient) Delete() error {
+	c.Repo.mu.Lock()
+	defer c.Repo.mu.Unlock()
+
+	exists, err := c.Repo.fetchRef("refs/heads/" + c.Branch)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	data, err := c.Repo.readFile("FETCH_HEAD", c.Path)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	if err := c.checkoutBranch(); err != nil {
+		return err
+	}
+	if _, err := c.Repo.run("rm", "--quiet", "--", c.Path); err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Delete Terraform state at %s", c.Path)
+	if _, err := c.Repo.run("commit", "--quiet", "-m", message); err != nil {
+		return err
+	}
+
+	return c.pushBranch()
+}
+
+// checkoutBranch makes the working tree reflect the current tip of the
+// remote state branch, or an empty tree if the branch doesn't exist in
+// the remote repository yet. The caller must hold the repository mutex.
+func (c *RemoteClient) checkoutBranch() error {
+	exists, err := c.Repo.fetchRef("refs/heads/" + c.Branch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		_, err = c.Repo.run("checkout", "--quiet", "-B", c.Branch, "FETCH_HEAD")
+		return err
+	}
+
+	// An orphan checkout gives us an unborn branch, so that our first
+	// commit creates the branch with no parent.
+	if _, err := c.Repo.run("checkout", "--quiet", "--orphan", c.Branch); err != nil {
+		return err
+	}
+	_, err = c.Repo.run("rm", "-rf", "--quiet", "--ignore-unmatch", "--", ".")
+	return err
+}
+
+// pushBranch pushes the local state branch to the remote repository.
+// The caller must hold the repository mutex.
+func (c *RemoteClient) pushBranch() error {
+	if _, err := c.Repo.run("push", "--quiet", "origin", c.Branch+":refs/heads/"+c.Branch); err != nil {
+		return fmt.Errorf("failed to push state to the remote repository, possibly because something else pushed to the state branch concurrently: %s", err)
+	}
+	return nil
+}
+
+func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
+	c.Repo.mu.Lock()
+	defer c.Repo.mu.Unlock()
+
+	if info.ID == "" {
+		lockID, err := uuid.GenerateUUID()
+		if err != nil {
+			return "", err
+		}
+		info.ID = lockID
+	}
+	info.Path = c.LockRef
+
+	// We record the lock information as the message of a parentless
+	// commit with an empty tree, and then try to create the lock ref
+	// pointing at it. The remote repository applies ref updates
+	// atomically and rejects the creation if the ref already exists,
+	// which is what gives us mutual exclusion.
+	treeID, err := c.Repo.runInput("", "hash-object", "-t", "tree", "-w", "--stdin")
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+	commitID, err := c.Repo.run("commit-tree", treeID, "-m", string(info.Marshal()))
+	if err != nil {
+		return "", &statemgr.LockError{Info: info, Err: err}
+	}
+
+	if _, err := c.Repo.run("push", "--quiet", "origin", commitID+":"+c.LockRef); err != nil {
+		lockErr := &statemgr.LockError{
+			Info: info,
+			Err:  fmt.Errorf("state is already locked"),
+		}
+		// Try to report who holds the lock, for the benefit of
+		// force-unlock. If this fails we still report the lock error.
+		if holder, infoErr := c.lockInfo(); infoErr == nil && holder != nil {
+			lockErr.Info = holder
+		}
+		return "", lockErr
+	}
+
+	return info.ID, nil
+}
+
+// lockInfo reads the lock information recorded in the current remote
+// lock ref, or nil if the ref doesn't exist. The caller must hold the
+// repository mutex.
+func (c *RemoteClient) lockInfo() (*statemgr.LockInfo, error) {
+	exists, err := c.Repo.fetchRef(c.LockRef)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	message, err := c.Repo.run("log", "-1", "--format=%B", "FETCH_HEAD")
+	if err != nil {
+		return nil, err
+	}
+	info := &statemgr.LockInfo{}
+	if err := json.Unmarshal([]byte(message), info); err != nil {
+		return nil, fmt.Errorf("failed to parse lock info from ref %s: %s", c.LockRef, err)
+	}
+	return info, nil
+}
+
+func (c *RemoteClient) Unlock(id string) error {
+	c.Repo.mu.Lock()
+	defer c.Repo.mu.Unlock()
+
+	holder, err := c.lockInfo()
+	if err != nil {
+		return err
+	}
+	if holder == nil {
+		return fmt.Errorf("the state is not locked")
+	}
+	if holder.ID != id {
+		return &statemgr.LockError{
+			Info: holder,
+			Err:  fmt.Errorf("lock id %q does not match existing lock", id),
+		}
+	}
+
+	// Deleting the ref releases the lock.
+	if _, err := c.Repo.run("push", "--quiet", "origin", ":"+c.LockRef); err != nil {
+		return &statemgr.LockError{Info: holder, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// repo is a thin wrapper around the local git tool, operating on a
+// private clone of the configured repository. All of the backend's
+// interactions with the remote repository go through the methods of
+// this type.
+//
+// The methods serialize themselves with a mutex because a single
+// working tree can't safely host concurrent git operations, and
+// Terraform may call into the backend from several goroutines.
+type repo struct {
+	mu sync.Mutex
+
+	// dir is the directory holding our private clone.
+	dir string
+
+	// env is extra environment for every git command, used for commit
+	// authorship and for HTTPS and SSH authentication. Credentials are
+	// deliberately passed only through the environment, never as
+	// command-line arguments, so that they don't show up in the process
+	// listing.
+	env []string
+}
+
+// newRepo initializes a private clone of the repository at the given URL
+// in a new temporary directory. The remote is recorded but nothing is
+// fetched yet, so this succeeds even for an entirely empty repository.
+func newRepo(url string, env []string) (*repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf(`the "git" backend requires the git tool to be available in PATH`)
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-backend-git")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working directory: %s", err)
+	}
+
+	r := &repo{
+		dir: dir,
+		env: env,
+	}
+	if _, err := r.run("init", "--quiet"); err != nil {
+		return nil, err
+	}
+	if _, err := r.run("remote", "add", "origin", url); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// run runs a single git command in the repository directory, returning
+// its standard output with any trailing newline removed.
+func (r *repo) run(args ...string) (string, error) {
+	out, err := r.runRaw(args...)
+	return strings.TrimRight(out, "\n"), err
+}
+
+// runInput is like run but also supplies the given input on the
+// command's standard input.
+func (r *repo) runInput(input string, args ...string) (string, error) {
+	out, err := r.runRawInput(input, args...)
+	return strings.TrimRight(out, "\n"), err
+}
+
+// runRaw is like run but returns the command's standard output exactly
+// as produced, for commands whose output bytes are significant.
+func (r *repo) runRaw(args ...string) (string, error) {
+	return r.runRawInput("", args...)
+}
+
+func (r *repo) runRawInput(input string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), r.env...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], message)
+	}
+	return stdout.String(), nil
+}
+
+// fetchRef fetches the given remote ref into FETCH_HEAD, returning false
+// with no error if the ref doesn't exist in the remote repository.
+func (r *repo) fetchRef(ref string) (bool, error) {
+	_, err := r.run("fetch", "--quiet", "origin", ref)
+	if err != nil {
+		if strings.Contains(err.Error(), "couldn't find remote ref") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// readFile returns the content of the given file as of the given
+// revision, or nil with no error if the file doesn't exist there.
+func (r *repo) readFile(rev, path string) ([]byte, error) {
+	out, err := r.runRaw("cat-file", "blob", rev+":"+path)
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "Not a valid object name") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []byte(out), nil
+}
@@ -3,8 +3,10 @@ package http
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"time"
@@ -16,6 +18,8 @@ import (
 	"github.com/hashicorp/terraform/internal/logging"
 	"github.com/hashicorp/terraform/internal/states/remote"
 	"github.com/hashicorp/terraform/internal/states/statemgr"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 func New() backend.Backend {
@@ -57,6 +61,24 @@ func New() backend.Backend {
 				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_UNLOCK_METHOD", "UNLOCK"),
 				Description: "The HTTP method to use when unlocking",
 			},
+			"renew_address": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_RENEW_ADDRESS", nil),
+				Description: "The address of the lock renewal REST endpoint",
+			},
+			"renew_method": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_RENEW_METHOD", "RENEW"),
+				Description: "The HTTP method to use when renewing the lock lease",
+			},
+			"renew_interval": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_RENEW_INTERVAL", 600),
+				Description: "The interval in seconds between lock lease renewals",
+			},
 			"username": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -93,6 +115,48 @@ func New() backend.Backend {
 				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_RETRY_WAIT_MAX", 30),
 				Description: "The maximum time in seconds to wait between HTTP request attempts.",
 			},
+			"retry_jitter": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to randomize the exponential backoff wait between HTTP request attempts.",
+			},
+			"oauth2_token_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_OAUTH2_TOKEN_URL", nil),
+				Description: "The OAuth2 token endpoint for client credentials authentication",
+			},
+			"oauth2_client_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_OAUTH2_CLIENT_ID", nil),
+				Description: "The OAuth2 client ID for client credentials authentication",
+			},
+			"oauth2_client_secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_OAUTH2_CLIENT_SECRET", nil),
+				Description: "The OAuth2 client secret for client credentials authentication",
+			},
+			"client_ca_certificate_pem": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_CLIENT_CA_CERTIFICATE_PEM", nil),
+				Description: "A PEM-encoded CA certificate chain used by the client to verify server certificates",
+			},
+			"client_certificate_pem": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_CLIENT_CERTIFICATE_PEM", nil),
+				Description: "A PEM-encoded certificate used by the server to verify the client during mutual TLS",
+			},
+			"client_private_key_pem": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("TF_HTTP_CLIENT_PRIVATE_KEY_PEM", nil),
+				Description: "A PEM-encoded private key, required if client_certificate_pem is specified",
+			},
 		},
 	}
 
@@ -149,21 +213,111 @@ func (b *Backend) configure(ctx context.Context) error {
 
 	unlockMethod := data.Get("unlock_method").(string)
 
+	var renewURL *url.URL
+	if v, ok := data.GetOk("renew_address"); ok && v.(string) != "" {
+		var err error
+		renewURL, err = url.Parse(v.(string))
+		if err != nil {
+			return fmt.Errorf("failed to parse renewAddress URL: %s", err)
+		}
+		if renewURL.Scheme != "http" && renewURL.Scheme != "https" {
+			return fmt.Errorf("renewAddress must be HTTP or HTTPS")
+		}
+		if lockURL == nil {
+			return fmt.Errorf("renew_address requires lock_address to be set")
+		}
+	}
+
+	renewMethod := data.Get("renew_method").(string)
+
+	renewInterval := data.Get("renew_interval").(int)
+	if renewInterval <= 0 {
+		return fmt.Errorf("renew_interval must be greater than zero")
+	}
+
 	client := cleanhttp.DefaultPooledClient()
+	transport := client.Transport.(*http.Transport)
 
 	if data.Get("skip_cert_verification").(bool) {
 		// ignores TLS verification
-		client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
+		transport.TLSClientConfig = &tls.Config{
 			InsecureSkipVerify: true,
 		}
 	}
 
+	if v, ok := data.GetOk("client_ca_certificate_pem"); ok && v.(string) != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(v.(string))) {
+			return fmt.Errorf("failed to parse client_ca_certificate_pem: no valid certificates found")
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	clientCertificatePem := data.Get("client_certificate_pem").(string)
+	clientPrivateKeyPem := data.Get("client_private_key_pem").(string)
+	if clientCertificatePem != "" || clientPrivateKeyPem != "" {
+		if clientCertificatePem == "" || clientPrivateKeyPem == "" {
+			return fmt.Errorf("client_certificate_pem and client_private_key_pem must be set together")
+		}
+		certificate, err := tls.X509KeyPair([]byte(clientCertificatePem), []byte(clientPrivateKeyPem))
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate: %s", err)
+		}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	username := data.Get("username").(string)
+	password := data.Get("password").(string)
+
+	if v, ok := data.GetOk("oauth2_token_url"); ok && v.(string) != "" {
+		tokenURL, err := url.Parse(v.(string))
+		if err != nil {
+			return fmt.Errorf("failed to parse oauth2_token_url: %s", err)
+		}
+		if tokenURL.Scheme != "http" && tokenURL.Scheme != "https" {
+			return fmt.Errorf("oauth2_token_url must be HTTP or HTTPS")
+		}
+		clientID := data.Get("oauth2_client_id").(string)
+		clientSecret := data.Get("oauth2_client_secret").(string)
+		if clientID == "" || clientSecret == "" {
+			return fmt.Errorf("oauth2_client_id and oauth2_client_secret must be set when oauth2_token_url is set")
+		}
+		if username != "" {
+			return fmt.Errorf("username cannot be used together with oauth2_token_url")
+		}
+
+		creds := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			TokenURL:     tokenURL.String(),
+		}
+		// The token requests themselves use the same transport as the state
+		// requests, so they honor the TLS settings above.
+		tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		})
+		client.Transport = &oauth2.Transport{
+			Base:   transport,
+			Source: creds.TokenSource(tokenCtx),
+		}
+	}
+
 	rClient := retryablehttp.NewClient()
 	rClient.HTTPClient = client
 	rClient.RetryMax = data.Get("retry_max").(int)
 	rClient.RetryWaitMin = time.Duration(data.Get("retry_wait_min").(int)) * time.Second
 	rClient.RetryWaitMax = time.Duration(data.Get("retry_wait_max").(int)) * time.Second
 	rClient.Logger = log.New(logging.LogOutput(), "", log.Flags())
+	if data.Get("retry_jitter").(bool) {
+		rClient.Backoff = retryJitterBackoff
+	}
 
 	b.client = &httpClient{
 		URL:          updateURL,
@@ -174,8 +328,12 @@ func (b *Backend) configure(ctx context.Context) error {
 		UnlockURL:    unlockURL,
 		UnlockMethod: unlockMethod,
 
-		Username: data.Get("username").(string),
-		Password: data.Get("password").(string),
+		RenewURL:      renewURL,
+		RenewMethod:   renewMethod,
+		RenewInterval: time.Duration(renewInterval) * time.Second,
+
+		Username: username,
+		Password: password,
 
 		// accessible only for testing use
 		Client: rClient,
@@ -183,6 +341,19 @@ func (b *Backend) configure(ctx context.Context) error {
 	return nil
 }
 
+// retryJitterBackoff waits like retryablehttp.DefaultBackoff, but randomizes
+// each wait between half of the exponential backoff value and the full value,
+// so that many clients retrying against the same server don't retry in
+// lockstep.
+func retryJitterBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	wait := retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+	if wait <= 0 {
+		return wait
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
 func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	if name != backend.DefaultStateName {
 		return nil, backend.ErrWorkspacesNotSupported
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/zclconf/go-cty/cty"
 
@@ -39,6 +42,9 @@ func TestHTTPClientFactory(t *testing.T) {
 	if client.UnlockURL != nil || client.UnlockMethod != "UNLOCK" {
 		t.Fatal("Unexpected unlock_address or unlock_method")
 	}
+	if client.RenewURL != nil || client.RenewMethod != "RENEW" || client.RenewInterval != 600*time.Second {
+		t.Fatal("Unexpected renew_address, renew_method or renew_interval")
+	}
 	if client.Username != "" || client.Password != "" {
 		t.Fatal("Unexpected username or password")
 	}
@@ -51,6 +57,9 @@ func TestHTTPClientFactory(t *testing.T) {
 		"lock_method":    cty.StringVal("BLIP"),
 		"unlock_address": cty.StringVal("http://127.0.0.1:8888/baz"),
 		"unlock_method":  cty.StringVal("BLOOP"),
+		"renew_address":  cty.StringVal("http://127.0.0.1:8888/qux"),
+		"renew_method":   cty.StringVal("BLORP"),
+		"renew_interval": cty.StringVal("90"),
 		"username":       cty.StringVal("user"),
 		"password":       cty.StringVal("pass"),
 		"retry_max":      cty.StringVal("999"),
@@ -79,6 +88,13 @@ func TestHTTPClientFactory(t *testing.T) {
 		t.Fatalf("Unexpected username \"%s\" vs \"%s\" or password \"%s\" vs \"%s\"", client.Username, conf["username"],
 			client.Password, conf["password"])
 	}
+	if client.RenewURL.String() != conf["renew_address"].AsString() || client.RenewMethod != "BLORP" {
+		t.Fatalf("Unexpected renew_address \"%s\" vs \"%s\" or renew_method \"%s\" vs \"%s\"", client.RenewURL.String(),
+			conf["renew_address"].AsString(), client.RenewMethod, conf["renew_method"])
+	}
+	if client.RenewInterval != 90*time.Second {
+		t.Fatalf("Expected renew_interval \"%s\", got \"%s\"", 90*time.Second, client.RenewInterval)
+	}
 	if client.Client.RetryMax != 999 {
 		t.Fatalf("Expected retry_max \"%d\", got \"%d\"", 999, client.Client.RetryMax)
 	}
@@ -150,6 +166,76 @@ func TestHTTPClientFactoryWithEnv(t *testing.T) {
 	}
 }
 
+func TestRetryJitterBackoff(t *testing.T) {
+	for attempt := 0; attempt < 8; attempt++ {
+		base := retryablehttp.DefaultBackoff(1*time.Second, 30*time.Second, attempt, nil)
+		for i := 0; i < 100; i++ {
+			wait := retryJitterBackoff(1*time.Second, 30*time.Second, attempt, nil)
+			if wait < base/2 || wait > base {
+				t.Fatalf("attempt %d: wait %s outside [%s, %s]", attempt, wait, base/2, base)
+			}
+		}
+	}
+}
+
+func TestHTTPBackendOAuth2(t *testing.T) {
+	var tokenRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		id, secret, _ := r.BasicAuth()
+		if id == "" {
+			id = r.Form.Get("client_id")
+			secret = r.Form.Get("client_secret")
+		}
+		if id != "test-client" || secret != "test-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/state", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"version": 4}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	conf := map[string]cty.Value{
+		"address":              cty.StringVal(srv.URL + "/state"),
+		"oauth2_token_url":     cty.StringVal(srv.URL + "/token"),
+		"oauth2_client_id":     cty.StringVal("test-client"),
+		"oauth2_client_secret": cty.StringVal("test-secret"),
+	}
+	b := backend.TestBackendConfig(t, New(), configs.SynthBody("synth", conf)).(*Backend)
+
+	payload, err := b.client.Get()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if payload == nil || string(payload.Data) != `{"version": 4}` {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokenRequests)
+	}
+
+	// The cached token is reused for subsequent requests.
+	if _, err := b.client.Get(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected 1 token request after reuse, got %d", tokenRequests)
+	}
+}
+
 // testWithEnv sets an environment variable and returns a deferable func to clean up
 func testWithEnv(t *testing.T, key string, value string) func() {
 	if err := os.Setenv(key, value); err != nil {
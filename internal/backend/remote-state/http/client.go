@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform/internal/states/remote"
@@ -28,6 +29,11 @@ type httpClient struct {
 	UnlockURL    *url.URL
 	UnlockMethod string
 
+	// Lock lease renewal
+	RenewURL      *url.URL
+	RenewMethod   string
+	RenewInterval time.Duration
+
 	// HTTP
 	Client   *retryablehttp.Client
 	Username string
@@ -113,6 +119,38 @@ func (c *httpClient) Lock(info *statemgr.LockInfo) (string, error) {
 	}
 }
 
+// LockRenewInterval returns the configured renewal interval, or zero when no
+// renewal endpoint is configured, which disables renewal entirely.
+func (c *httpClient) LockRenewInterval() time.Duration {
+	if c.RenewURL == nil {
+		return 0
+	}
+	return c.RenewInterval
+}
+
+// RenewLock sends the held lock info to the renewal endpoint so that the
+// server can extend the lease on the lock.
+func (c *httpClient) RenewLock(id string) error {
+	if c.RenewURL == nil {
+		return nil
+	}
+
+	resp, err := c.httpRequest(c.RenewMethod, c.RenewURL, &c.jsonLockInfo, "renew lock")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusConflict, http.StatusLocked, http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("HTTP remote state lock %s is no longer held", id)
+	default:
+		return fmt.Errorf("Unexpected HTTP response code %d", resp.StatusCode)
+	}
+}
+
 func (c *httpClient) Unlock(id string) error {
 	if c.UnlockURL == nil {
 		return nil
@@ -9,14 +9,17 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/hashicorp/terraform/internal/states/remote"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
 )
 
 func TestHTTPClient_impl(t *testing.T) {
 	var _ remote.Client = new(httpClient)
 	var _ remote.ClientLocker = new(httpClient)
+	var _ remote.ClientLockRenewer = new(httpClient)
 }
 
 func TestHTTPClient(t *testing.T) {
@@ -94,6 +97,52 @@ func TestHTTPClient(t *testing.T) {
 	remote.TestClient(t, client)
 }
 
+func TestHTTPClientRenewLock(t *testing.T) {
+	handler := new(testHTTPHandler)
+	ts := httptest.NewServer(http.HandlerFunc(handler.Handle))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+
+	client := &httpClient{
+		URL:           url,
+		UpdateMethod:  "PUT",
+		LockURL:       url,
+		LockMethod:    "LOCK",
+		UnlockURL:     url,
+		UnlockMethod:  "UNLOCK",
+		RenewURL:      url,
+		RenewMethod:   "RENEW",
+		RenewInterval: time.Minute,
+		Client:        retryablehttp.NewClient(),
+	}
+
+	if got, want := client.LockRenewInterval(), time.Minute; got != want {
+		t.Fatalf("wrong renew interval %s; want %s", got, want)
+	}
+
+	info := statemgr.NewLockInfo()
+	info.Operation = "test"
+	id, err := client.Lock(info)
+	if err != nil {
+		t.Fatalf("unexpected lock error: %s", err)
+	}
+
+	// while the lock is held renewal succeeds
+	if err := client.RenewLock(id); err != nil {
+		t.Fatalf("unexpected renew error: %s", err)
+	}
+
+	// once the lock is gone the server reports it and renewal must fail
+	handler.Locked = false
+	if err := client.RenewLock(id); err == nil {
+		t.Fatal("renew of a lost lock succeeded; want error")
+	}
+}
+
 type testHTTPHandler struct {
 	Data   []byte
 	Locked bool
@@ -124,6 +173,12 @@ func (h *testHTTPHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		}
 	case "UNLOCK":
 		h.Locked = false
+	case "RENEW":
+		if h.Locked {
+			w.WriteHeader(200)
+		} else {
+			w.WriteHeader(423)
+		}
 	case "DELETE":
 		h.Data = nil
 		w.WriteHeader(200)
@@ -6,6 +6,7 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,12 +28,31 @@ import (
 
 const (
 	tfstateKey                = "tfstate"
+	tfstateIndexKey           = "tfstateIndex"
 	tfstateSecretSuffixKey    = "tfstateSecretSuffix"
 	tfstateWorkspaceKey       = "tfstateWorkspace"
+	tfstateChunkKey           = "tfstateChunk"
 	tfstateLockInfoAnnotation = "app.terraform.io/lock-info"
 	managedByKey              = "app.kubernetes.io/managed-by"
 )
 
+// maxSecretChunkSize is the largest number of bytes of compressed state we
+// store in a single secret. The Kubernetes API limits secrets to 1MiB
+// including metadata and the base64 expansion of the data, so we keep each
+// chunk conservatively below that. Compressed state larger than this is
+// split across multiple secrets, with the main secret holding an index of
+// the chunk secrets instead of the state itself.
+const maxSecretChunkSize = 700 * 1024
+
+// chunkIndex is the document stored in the main state secret under
+// tfstateIndexKey when the state is split across multiple chunk secrets. The
+// chunk names include a digest of the payload, so replacing the state writes
+// a fresh set of chunk secrets and then atomically repoints the index at
+// them with a single update of the main secret.
+type chunkIndex struct {
+	Chunks []string `json:"chunks"`
+}
+
 type RemoteClient struct {
 	kubernetesSecretClient dynamic.ResourceInterface
 	kubernetesLeaseClient  coordinationclientv1.LeaseInterface
@@ -56,15 +76,31 @@ func (c *RemoteClient) Get() (payload *remote.Payload, err error) {
 	}
 
 	secretData := getSecretData(secret)
-	stateRaw, ok := secretData[tfstateKey]
-	if !ok {
-		// The secret exists but there is no state in it
-		return nil, nil
-	}
 
-	stateRawString := stateRaw.(string)
+	var compressed []byte
+	if indexRaw, ok := secretData[tfstateIndexKey]; ok {
+		index, err := decodeChunkIndex(indexRaw.(string))
+		if err != nil {
+			return nil, err
+		}
+		compressed, err = c.readChunks(index)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		stateRaw, ok := secretData[tfstateKey]
+		if !ok {
+			// The secret exists but there is no state in it
+			return nil, nil
+		}
+		var err error
+		compressed, err = base64.StdEncoding.DecodeString(stateRaw.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	state, err := uncompressState(stateRawString)
+	state, err := uncompressState(compressed)
 	if err != nil {
 		return nil, err
 	}
@@ -113,18 +149,63 @@ func (c *RemoteClient) Put(data []byte) error {
 		}
 	}
 
-	setState(secret, payload)
+	// Keep track of any chunk secrets the previous state was using, so that
+	// we can clean them up once the new state is in place.
+	var oldChunks []string
+	if indexRaw, ok := getSecretData(secret)[tfstateIndexKey]; ok {
+		if oldIndex, err := decodeChunkIndex(indexRaw.(string)); err == nil {
+			oldChunks = oldIndex.Chunks
+		}
+	}
+
+	var newChunks []string
+	if len(payload) <= maxSecretChunkSize {
+		setState(secret, payload)
+	} else {
+		// The compressed state is too large for a single secret, so we
+		// write it to a set of chunk secrets first and then atomically
+		// switch the main secret over to the new set by replacing its index.
+		index, err := c.writeChunks(ctx, secretName, payload)
+		if err != nil {
+			return err
+		}
+		newChunks = index.Chunks
+		if err := setChunkIndex(secret, index); err != nil {
+			return err
+		}
+	}
+
 	_, err = c.kubernetesSecretClient.Update(ctx, secret, metav1.UpdateOptions{})
-	return err
+	if err != nil {
+		return err
+	}
+
+	// The old chunk secrets are now unreferenced, except for any that the
+	// new state is reusing because its content produced the same names.
+	return c.deleteChunks(oldChunks, newChunks)
 }
 
-// Delete the state secret
+// Delete the state secret, along with any chunk secrets it refers to
 func (c *RemoteClient) Delete() error {
 	secretName, err := c.createSecretName()
 	if err != nil {
 		return err
 	}
 
+	// If the state is chunked then the chunk secrets must go too.
+	secret, err := c.getSecret(secretName)
+	if err == nil {
+		if indexRaw, ok := getSecretData(secret)[tfstateIndexKey]; ok {
+			if index, err := decodeChunkIndex(indexRaw.(string)); err == nil {
+				if err := c.deleteChunks(index.Chunks, nil); err != nil {
+					return err
+				}
+			}
+		}
+	} else if !k8serrors.IsNotFound(err) {
+		return err
+	}
+
 	err = c.deleteSecret(secretName)
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
@@ -355,14 +436,9 @@ func compressState(data []byte) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
-func uncompressState(data string) ([]byte, error) {
-	decode, err := base64.StdEncoding.DecodeString(data)
-	if err != nil {
-		return nil, err
-	}
-
+func uncompressState(data []byte) ([]byte, error) {
 	b := new(bytes.Buffer)
-	gz, err := gzip.NewReader(bytes.NewReader(decode))
+	gz, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -373,6 +449,140 @@ func uncompressState(data string) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// splitChunks cuts the compressed state into pieces of at most
+// maxSecretChunkSize bytes each.
+func splitChunks(payload []byte) [][]byte {
+	var chunks [][]byte
+	for len(payload) > 0 {
+		size := len(payload)
+		if size > maxSecretChunkSize {
+			size = maxSecretChunkSize
+		}
+		chunks = append(chunks, payload[:size])
+		payload = payload[size:]
+	}
+	return chunks
+}
+
+// chunkSecretNames derives the names of the chunk secrets for the given
+// payload. The names include a digest of the whole payload, so two different
+// states never share chunk secret names and writing a new state can't
+// corrupt the set of chunks the current index refers to.
+func chunkSecretNames(secretName string, payload []byte, count int) []string {
+	digest := md5.Sum(payload)
+	generation := hex.EncodeToString(digest[:])[:12]
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s-chunk-%s-%d", secretName, generation, i)
+	}
+	return names
+}
+
+// writeChunks stores the given compressed state across multiple chunk
+// secrets and returns an index describing them. The chunk names are derived
+// from the payload content, so re-writing identical state is idempotent.
+func (c *RemoteClient) writeChunks(ctx context.Context, secretName string, payload []byte) (*chunkIndex, error) {
+	chunks := splitChunks(payload)
+	names := chunkSecretNames(secretName, payload, len(chunks))
+
+	for i, chunk := range chunks {
+		secret := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": metav1.ObjectMeta{
+					Name:        names[i],
+					Namespace:   c.namespace,
+					Labels:      c.getChunkLabels(),
+					Annotations: map[string]string{"encoding": "gzip"},
+				},
+			},
+		}
+		setState(secret, chunk)
+
+		_, err := c.kubernetesSecretClient.Create(ctx, secret, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			// The same payload was written before; the existing chunk
+			// already has this content by construction of its name.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &chunkIndex{Chunks: names}, nil
+}
+
+// readChunks fetches the chunk secrets named by the given index and
+// reassembles the compressed state they hold.
+func (c *RemoteClient) readChunks(index *chunkIndex) ([]byte, error) {
+	var payload []byte
+	for _, name := range index.Chunks {
+		secret, err := c.getSecret(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state chunk secret %q: %s", name, err)
+		}
+		chunkRaw, ok := getSecretData(secret)[tfstateKey]
+		if !ok {
+			return nil, fmt.Errorf("state chunk secret %q has no %q key", name, tfstateKey)
+		}
+		chunk, err := base64.StdEncoding.DecodeString(chunkRaw.(string))
+		if err != nil {
+			return nil, err
+		}
+		payload = append(payload, chunk...)
+	}
+	return payload, nil
+}
+
+// deleteChunks removes the chunk secrets with the given names, except for
+// any that also appear in keep. Chunks that are already gone are ignored.
+func (c *RemoteClient) deleteChunks(names []string, keep []string) error {
+	keeping := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		keeping[name] = true
+	}
+	for _, name := range names {
+		if keeping[name] {
+			continue
+		}
+		err := c.deleteSecret(name)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RemoteClient) getChunkLabels() map[string]string {
+	l := c.getLabels()
+	l[tfstateChunkKey] = "true"
+	return l
+}
+
+func decodeChunkIndex(raw string) (*chunkIndex, error) {
+	src, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	index := &chunkIndex{}
+	if err := json.Unmarshal(src, index); err != nil {
+		return nil, fmt.Errorf("invalid state chunk index: %s", err)
+	}
+	return index, nil
+}
+
+func setChunkIndex(secret *unstructured.Unstructured, index *chunkIndex) error {
+	src, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	secretData := getSecretData(secret)
+	secretData[tfstateIndexKey] = src
+	delete(secretData, tfstateKey)
+	secret.Object["data"] = secretData
+	return nil
+}
+
 func getSecretData(secret *unstructured.Unstructured) map[string]interface{} {
 	if m, ok := secret.Object["data"].(map[string]interface{}); ok {
 		return m
@@ -409,5 +619,6 @@ func removeLockInfo(lease *coordinationv1.Lease) {
 func setState(secret *unstructured.Unstructured, t []byte) {
 	secretData := getSecretData(secret)
 	secretData[tfstateKey] = t
+	delete(secretData, tfstateIndexKey)
 	secret.Object["data"] = secretData
 }
@@ -1,11 +1,15 @@
 package kubernetes
 
 import (
+	"bytes"
+	"math/rand"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/internal/backend"
 	"github.com/hashicorp/terraform/internal/states/remote"
 	"github.com/hashicorp/terraform/internal/states/statemgr"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func TestRemoteClient_impl(t *testing.T) {
@@ -13,6 +17,71 @@ func TestRemoteClient_impl(t *testing.T) {
 	var _ remote.ClientLocker = new(RemoteClient)
 }
 
+func TestSplitChunks(t *testing.T) {
+	testCases := map[string]struct {
+		size       int
+		wantChunks int
+	}{
+		"empty":               {0, 0},
+		"small":               {100, 1},
+		"exactly one chunk":   {maxSecretChunkSize, 1},
+		"just over one chunk": {maxSecretChunkSize + 1, 2},
+		"several chunks":      {maxSecretChunkSize*3 + 17, 4},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			payload := make([]byte, tc.size)
+			for i := range payload {
+				payload[i] = byte(i)
+			}
+
+			chunks := splitChunks(payload)
+			if got := len(chunks); got != tc.wantChunks {
+				t.Fatalf("wrong number of chunks %d; want %d", got, tc.wantChunks)
+			}
+
+			var joined []byte
+			for _, chunk := range chunks {
+				if len(chunk) > maxSecretChunkSize {
+					t.Errorf("chunk of %d bytes exceeds the maximum size", len(chunk))
+				}
+				joined = append(joined, chunk...)
+			}
+			if !bytes.Equal(joined, payload) {
+				t.Errorf("joined chunks don't reproduce the original payload")
+			}
+		})
+	}
+}
+
+func TestChunkSecretNames(t *testing.T) {
+	payload := []byte("just some example state")
+	names := chunkSecretNames("tfstate-default-test", payload, 2)
+	if len(names) != 2 {
+		t.Fatalf("wrong number of names %d; want 2", len(names))
+	}
+	for _, name := range names {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			t.Errorf("chunk name %q is not a valid secret name: %s", name, strings.Join(errs, ","))
+		}
+	}
+	if names[0] == names[1] {
+		t.Errorf("chunk names are not distinct: %q", names[0])
+	}
+
+	// The names must be stable for the same payload, so that re-writing
+	// identical state is idempotent, and different for a different payload.
+	again := chunkSecretNames("tfstate-default-test", payload, 2)
+	if names[0] != again[0] {
+		t.Errorf("chunk names are not stable for the same payload: %q vs %q", names[0], again[0])
+	}
+	other := chunkSecretNames("tfstate-default-test", []byte("different state"), 2)
+	if names[0] == other[0] {
+		t.Errorf("different payloads produced the same chunk name %q", names[0])
+	}
+}
+
 func TestRemoteClient(t *testing.T) {
 	testACC(t)
 	defer cleanupK8sResources(t)
@@ -29,6 +98,56 @@ func TestRemoteClient(t *testing.T) {
 	remote.TestClient(t, state.(*remote.State).Client)
 }
 
+func TestRemoteClientLargeState(t *testing.T) {
+	testACC(t)
+	defer cleanupK8sResources(t)
+
+	b := backend.TestBackendConfig(t, New(), backend.TestWrapConfig(map[string]interface{}{
+		"secret_suffix": secretSuffix,
+	}))
+
+	state, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := state.(*remote.State).Client
+
+	// Generate a payload that is too large for a single secret even after
+	// compression, so it must be split into chunk secrets.
+	rnd := rand.New(rand.NewSource(42))
+	large := make([]byte, 3*1024*1024)
+	rnd.Read(large)
+
+	if err := client.Put(large); err != nil {
+		t.Fatalf("failed to put large state: %s", err)
+	}
+	p, err := client.Get()
+	if err != nil {
+		t.Fatalf("failed to get large state: %s", err)
+	}
+	if p == nil || !bytes.Equal(p.Data, large) {
+		t.Fatalf("large state did not round-trip intact")
+	}
+
+	// A subsequent small state must collapse back to a single secret and
+	// clean up the chunk secrets.
+	small := []byte(`{"version": 4}`)
+	if err := client.Put(small); err != nil {
+		t.Fatalf("failed to put small state: %s", err)
+	}
+	p, err = client.Get()
+	if err != nil {
+		t.Fatalf("failed to get small state: %s", err)
+	}
+	if p == nil || !bytes.Equal(p.Data, small) {
+		t.Fatalf("small state did not round-trip intact")
+	}
+
+	if err := client.Delete(); err != nil {
+		t.Fatalf("failed to delete state: %s", err)
+	}
+}
+
 func TestRemoteClientLocks(t *testing.T) {
 	testACC(t)
 	defer cleanupK8sResources(t)
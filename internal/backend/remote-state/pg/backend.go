@@ -11,8 +11,10 @@ import (
 )
 
 const (
-	statesTableName = "states"
-	statesIndexName = "states_by_name"
+	statesTableName        = "states"
+	statesIndexName        = "states_by_name"
+	statesHistoryTableName = "states_history"
+	statesHistoryIndexName = "states_history_by_name"
 )
 
 // New creates a new backend for Postgres remote state.
@@ -50,6 +52,20 @@ func New() backend.Backend {
 				Optional:    true,
 				Description: "If set to `true`, Terraform won't try to create the Postgres index",
 			},
+
+			"history": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "If set to `true`, every state write also records a snapshot in a history table, for later restore with `terraform state restore-version`",
+				Default:     false,
+			},
+
+			"history_retention": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Number of history snapshots to retain per workspace; older snapshots are removed as new ones are recorded. The default of `0` retains all snapshots",
+				Default:     0,
+			},
 		},
 	}
 
@@ -62,10 +78,12 @@ type Backend struct {
 	*schema.Backend
 
 	// The fields below are set from configure
-	db         *sql.DB
-	configData *schema.ResourceData
-	connStr    string
-	schemaName string
+	db               *sql.DB
+	configData       *schema.ResourceData
+	connStr          string
+	schemaName       string
+	history          bool
+	historyRetention int
 }
 
 func (b *Backend) configure(ctx context.Context) error {
@@ -75,6 +93,8 @@ func (b *Backend) configure(ctx context.Context) error {
 
 	b.connStr = data.Get("conn_str").(string)
 	b.schemaName = pq.QuoteIdentifier(data.Get("schema_name").(string))
+	b.history = data.Get("history").(bool)
+	b.historyRetention = data.Get("history_retention").(int)
 
 	db, err := sql.Open("postgres", b.connStr)
 	if err != nil {
@@ -119,11 +139,35 @@ func (b *Backend) configure(ctx context.Context) error {
 		}
 	}
 
+	if b.history && !data.Get("skip_table_creation").(bool) {
+		if _, err := db.Exec("CREATE SEQUENCE IF NOT EXISTS public.global_states_history_id_seq AS bigint"); err != nil {
+			return err
+		}
+
+		query = `CREATE TABLE IF NOT EXISTS %s.%s (
+			id bigint NOT NULL DEFAULT nextval('public.global_states_history_id_seq') PRIMARY KEY,
+			name text,
+			serial bigint,
+			data text,
+			created_at timestamp with time zone NOT NULL DEFAULT now()
+			)`
+		if _, err := db.Exec(fmt.Sprintf(query, b.schemaName, statesHistoryTableName)); err != nil {
+			return err
+		}
+	}
+
 	if !data.Get("skip_index_creation").(bool) {
 		query = `CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s.%s (name)`
 		if _, err := db.Exec(fmt.Sprintf(query, statesIndexName, b.schemaName, statesTableName)); err != nil {
 			return err
 		}
+
+		if b.history {
+			query = `CREATE INDEX IF NOT EXISTS %s ON %s.%s (name)`
+			if _, err := db.Exec(fmt.Sprintf(query, statesHistoryIndexName, b.schemaName, statesHistoryTableName)); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Assign db after its schema is prepared.
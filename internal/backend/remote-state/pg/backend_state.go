@@ -1,6 +1,8 @@
 package pg
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform/internal/backend"
@@ -9,6 +11,8 @@ import (
 	"github.com/hashicorp/terraform/internal/states/statemgr"
 )
 
+var _ backend.VersionHistory = (*Backend)(nil)
+
 func (b *Backend) Workspaces() ([]string, error) {
 	query := `SELECT name FROM %s.%s WHERE name != 'default' ORDER BY name`
 	rows, err := b.db.Query(fmt.Sprintf(query, b.schemaName, statesTableName))
@@ -49,13 +53,67 @@ func (b *Backend) DeleteWorkspace(name string) error {
 	return nil
 }
 
+// StateVersions implements backend.VersionHistory, returning the retained
+// snapshots for the given workspace in the order they were recorded.
+func (b *Backend) StateVersions(workspace string) ([]backend.StateVersion, error) {
+	if !b.history {
+		return nil, errHistoryNotEnabled
+	}
+
+	query := `SELECT serial, created_at FROM %s.%s WHERE name = $1 ORDER BY id`
+	rows, err := b.db.Query(fmt.Sprintf(query, b.schemaName, statesHistoryTableName), workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []backend.StateVersion
+	for rows.Next() {
+		var version backend.StateVersion
+		if err := rows.Scan(&version.Serial, &version.Created); err != nil {
+			return nil, err
+		}
+		result = append(result, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// StateVersion implements backend.VersionHistory, returning the most
+// recently recorded snapshot for the given workspace with the given serial.
+func (b *Backend) StateVersion(workspace string, serial uint64) ([]byte, error) {
+	if !b.history {
+		return nil, errHistoryNotEnabled
+	}
+
+	query := `SELECT data FROM %s.%s WHERE name = $1 AND serial = $2 ORDER BY id DESC LIMIT 1`
+	row := b.db.QueryRow(fmt.Sprintf(query, b.schemaName, statesHistoryTableName), workspace, serial)
+	var data []byte
+	err := row.Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("no state snapshot with serial %d is recorded for workspace %q", serial, workspace)
+	case err != nil:
+		return nil, err
+	}
+
+	return data, nil
+}
+
+var errHistoryNotEnabled = errors.New(`state history is not enabled for this backend; set "history = true" in the backend configuration to begin recording state snapshots`)
+
 func (b *Backend) StateMgr(name string) (statemgr.Full, error) {
 	// Build the state client
 	var stateMgr statemgr.Full = &remote.State{
 		Client: &RemoteClient{
-			Client:     b.db,
-			Name:       name,
-			SchemaName: b.schemaName,
+			Client:           b.db,
+			Name:             name,
+			SchemaName:       b.schemaName,
+			History:          b.history,
+			HistoryRetention: b.historyRetention,
 		},
 	}
 
@@ -260,6 +260,69 @@ func TestBackendStates(t *testing.T) {
 	}
 }
 
+func TestBackendStateHistory(t *testing.T) {
+	testACC(t)
+	connStr := getDatabaseUrl()
+	schemaName := fmt.Sprintf("terraform_%s", t.Name())
+	dbCleaner, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbCleaner.Query(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schemaName))
+
+	config := backend.TestWrapConfig(map[string]interface{}{
+		"conn_str":          connStr,
+		"schema_name":       schemaName,
+		"history":           true,
+		"history_retention": 2,
+	})
+	b := backend.TestBackendConfig(t, New(), config).(*Backend)
+
+	if b == nil {
+		t.Fatal("Backend could not be configured")
+	}
+
+	s, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := s.(*remote.State).Client.(*RemoteClient)
+
+	// Each write should record a snapshot, but only the most recent two
+	// should be retained.
+	for serial := 1; serial <= 3; serial++ {
+		data := []byte(fmt.Sprintf(`{"version": 4, "lineage": "test", "serial": %d}`, serial))
+		if err := client.Put(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, err := b.StateVersions(backend.DefaultStateName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The sentinel empty state written by StateMgr is pruned along with
+	// the first explicit write, leaving serials 2 and 3.
+	if len(versions) != 2 {
+		t.Fatalf("expected 2 retained versions, got %d", len(versions))
+	}
+	if versions[0].Serial != 2 || versions[1].Serial != 3 {
+		t.Fatalf("unexpected retained serials: %d, %d", versions[0].Serial, versions[1].Serial)
+	}
+
+	data, err := b.StateVersion(backend.DefaultStateName, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"version": 4, "lineage": "test", "serial": 2}`; string(data) != want {
+		t.Fatalf("wrong snapshot data\ngot:  %s\nwant: %s", data, want)
+	}
+
+	if _, err := b.StateVersion(backend.DefaultStateName, 1); err == nil {
+		t.Fatal("expected error for pruned snapshot, got none")
+	}
+}
+
 func TestBackendStateLocks(t *testing.T) {
 	testACC(t)
 	connStr := getDatabaseUrl()
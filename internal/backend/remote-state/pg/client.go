@@ -3,6 +3,7 @@ package pg
 import (
 	"crypto/md5"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	uuid "github.com/hashicorp/go-uuid"
@@ -17,6 +18,12 @@ type RemoteClient struct {
 	Name       string
 	SchemaName string
 
+	// History enables recording a snapshot of every state write in the
+	// history table. HistoryRetention limits how many snapshots are kept
+	// per workspace, with zero meaning unlimited.
+	History          bool
+	HistoryRetention int
+
 	info *statemgr.LockInfo
 }
 
@@ -48,6 +55,44 @@ func (c *RemoteClient) Put(data []byte) error {
 	if err != nil {
 		return err
 	}
+
+	if c.History {
+		if err := c.putHistory(data); err != nil {
+			return fmt.Errorf("failed to record state history: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// putHistory records the given state snapshot in the history table, and
+// prunes snapshots beyond the configured retention limit.
+func (c *RemoteClient) putHistory(data []byte) error {
+	// The serial is recorded in its own column so that snapshots can be
+	// listed and retrieved without parsing each one.
+	var meta struct {
+		Serial uint64 `json:"serial"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("failed to parse state serial: %s", err)
+	}
+
+	query := `INSERT INTO %s.%s (name, serial, data) VALUES ($1, $2, $3)`
+	_, err := c.Client.Exec(fmt.Sprintf(query, c.SchemaName, statesHistoryTableName), c.Name, meta.Serial, data)
+	if err != nil {
+		return err
+	}
+
+	if c.HistoryRetention > 0 {
+		query = `DELETE FROM %s.%s WHERE name = $1 AND id NOT IN (
+			SELECT id FROM %s.%s WHERE name = $1 ORDER BY id DESC LIMIT $2
+			)`
+		_, err = c.Client.Exec(fmt.Sprintf(query, c.SchemaName, statesHistoryTableName, c.SchemaName, statesHistoryTableName), c.Name, c.HistoryRetention)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
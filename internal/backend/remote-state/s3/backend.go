@@ -121,6 +121,13 @@ func New() backend.Backend {
 				Default:     "",
 			},
 
+			"use_lockfile": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Whether to use a lockfile in S3 for state locking, using conditional writes. This allows locking without a DynamoDB table, for S3-compatible object stores that support the If-None-Match precondition.",
+				Default:     false,
+			},
+
 			"profile": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -282,6 +289,7 @@ type Backend struct {
 	acl                   string
 	kmsKeyID              string
 	ddbTable              string
+	useLockfile           bool
 	workspaceKeyPrefix    string
 }
 
@@ -306,6 +314,7 @@ func (b *Backend) configure(ctx context.Context) error {
 	b.serverSideEncryption = data.Get("encrypt").(bool)
 	b.kmsKeyID = data.Get("kms_key_id").(string)
 	b.ddbTable = data.Get("dynamodb_table").(string)
+	b.useLockfile = data.Get("use_lockfile").(bool)
 
 	customerKeyString := data.Get("sse_customer_key").(string)
 	if customerKeyString != "" {
@@ -119,6 +119,7 @@ func (b *Backend) remoteClient(name string) (*RemoteClient, error) {
 		acl:                   b.acl,
 		kmsKeyID:              b.kmsKeyID,
 		ddbTable:              b.ddbTable,
+		useLockfile:           b.useLockfile,
 	}
 
 	return client, nil
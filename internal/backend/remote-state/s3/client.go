@@ -39,6 +39,7 @@ type RemoteClient struct {
 	acl                   string
 	kmsKeyID              string
 	ddbTable              string
+	useLockfile           bool
 }
 
 var (
@@ -212,7 +213,7 @@ func (c *RemoteClient) Delete() error {
 }
 
 func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
-	if c.ddbTable == "" {
+	if c.ddbTable == "" && !c.useLockfile {
 		return "", nil
 	}
 
@@ -227,6 +228,70 @@ func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 		info.ID = lockID
 	}
 
+	if c.useLockfile {
+		if err := c.lockWithFile(info); err != nil {
+			return "", err
+		}
+	}
+
+	if c.ddbTable != "" {
+		if err := c.lockWithDynamoDB(info); err != nil {
+			// If we also took the lockfile then release it again, so that a
+			// partial failure doesn't leave a dangling lock object behind.
+			if c.useLockfile {
+				if unlockErr := c.unlockWithFile(info.ID); unlockErr != nil {
+					log.Printf("[WARN] failed to release S3 lockfile after DynamoDB lock failure: %s", unlockErr)
+				}
+			}
+			return "", err
+		}
+	}
+
+	return info.ID, nil
+}
+
+// lockWithFile writes a lock object next to the state object in S3, using a
+// conditional write so that the request fails if the lock object already
+// exists. This provides locking on S3-compatible object stores that support
+// the If-None-Match precondition, without requiring a DynamoDB table.
+func (c *RemoteClient) lockWithFile(info *statemgr.LockInfo) error {
+	data := info.Marshal()
+	contentType := "application/json"
+	contentLength := int64(len(data))
+
+	input := &s3.PutObjectInput{
+		ContentType:   &contentType,
+		ContentLength: &contentLength,
+		Body:          bytes.NewReader(data),
+		Bucket:        &c.bucketName,
+		Key:           aws.String(c.lockFilePath()),
+	}
+
+	log.Printf("[DEBUG] Writing S3 lockfile: %#v", input)
+
+	// The SDK's PutObjectInput has no field for the If-None-Match
+	// precondition, so we must set the header on the request directly.
+	req, _ := c.s3Client.PutObjectRequest(input)
+	req.HTTPRequest.Header.Set("If-None-Match", "*")
+
+	if err := req.Send(); err != nil {
+		lockInfo, infoErr := c.getFileLockInfo()
+		if infoErr != nil {
+			err = multierror.Append(err, infoErr)
+		}
+
+		return &statemgr.LockError{
+			Err:  err,
+			Info: lockInfo,
+		}
+	}
+
+	return nil
+}
+
+// lockWithDynamoDB writes a lock item into the configured DynamoDB table,
+// failing if an item for this state already exists.
+func (c *RemoteClient) lockWithDynamoDB(info *statemgr.LockInfo) error {
 	putParams := &dynamodb.PutItemInput{
 		Item: map[string]*dynamodb.AttributeValue{
 			"LockID": {S: aws.String(c.lockPath())},
@@ -243,14 +308,13 @@ func (c *RemoteClient) Lock(info *statemgr.LockInfo) (string, error) {
 			err = multierror.Append(err, infoErr)
 		}
 
-		lockErr := &statemgr.LockError{
+		return &statemgr.LockError{
 			Err:  err,
 			Info: lockInfo,
 		}
-		return "", lockErr
 	}
 
-	return info.ID, nil
+	return nil
 }
 
 func (c *RemoteClient) getMD5() ([]byte, error) {
@@ -357,7 +421,78 @@ func (c *RemoteClient) getLockInfo() (*statemgr.LockInfo, error) {
 	return lockInfo, nil
 }
 
+// getFileLockInfo reads and parses the lock object written by lockWithFile,
+// returning nil if no lock object is present.
+func (c *RemoteClient) getFileLockInfo() (*statemgr.LockInfo, error) {
+	output, err := c.s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: &c.bucketName,
+		Key:    aws.String(c.lockFilePath()),
+	})
+	if err != nil {
+		if awserr, ok := err.(awserr.Error); ok && awserr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	buf := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buf, output.Body); err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %s", err)
+	}
+
+	lockInfo := &statemgr.LockInfo{}
+	if err := json.Unmarshal(buf.Bytes(), lockInfo); err != nil {
+		return nil, err
+	}
+
+	return lockInfo, nil
+}
+
+// unlockWithFile deletes the lock object written by lockWithFile, after
+// verifying that it records the given lock ID.
+func (c *RemoteClient) unlockWithFile(id string) error {
+	lockErr := &statemgr.LockError{}
+
+	lockInfo, err := c.getFileLockInfo()
+	if err != nil {
+		lockErr.Err = fmt.Errorf("failed to retrieve lockfile info: %s", err)
+		return lockErr
+	}
+	if lockInfo == nil {
+		lockErr.Err = fmt.Errorf("no lockfile present for lock id %q", id)
+		return lockErr
+	}
+	lockErr.Info = lockInfo
+
+	if lockInfo.ID != id {
+		lockErr.Err = fmt.Errorf("lock id %q does not match existing lock", id)
+		return lockErr
+	}
+
+	_, err = c.s3Client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: &c.bucketName,
+		Key:    aws.String(c.lockFilePath()),
+	})
+	if err != nil {
+		lockErr.Err = err
+		return lockErr
+	}
+
+	return nil
+}
+
 func (c *RemoteClient) Unlock(id string) error {
+	if c.ddbTable == "" && !c.useLockfile {
+		return nil
+	}
+
+	if c.useLockfile {
+		if err := c.unlockWithFile(id); err != nil {
+			return err
+		}
+	}
+
 	if c.ddbTable == "" {
 		return nil
 	}
@@ -398,6 +533,12 @@ func (c *RemoteClient) lockPath() string {
 	return fmt.Sprintf("%s/%s", c.bucketName, c.path)
 }
 
+// lockFilePath is the key of the lock object used when use_lockfile is
+// enabled, stored alongside the state object itself.
+func (c *RemoteClient) lockFilePath() string {
+	return c.path + ".tflock"
+}
+
 func (c *RemoteClient) getSSECustomerKeyMD5() string {
 	b := md5.Sum(c.customerEncryptionKey)
 	return base64.StdEncoding.EncodeToString(b[:])
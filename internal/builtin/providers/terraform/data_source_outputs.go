@@ -0,0 +1,311 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/httpclient"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+func dataSourceRemoteOutputsGetSchema() providers.Schema {
+	return providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"url": {
+					Type: cty.String,
+					Description: "The URL of an HTTP endpoint returning a " +
+						"JSON document of output values, either as a plain " +
+						"object of values or in the format produced by " +
+						"`terraform output -json`.",
+					DescriptionKind: configschema.StringMarkdown,
+					Required:        true,
+				},
+				"headers": {
+					Type: cty.Map(cty.String),
+					Description: "Additional HTTP headers to send with the " +
+						"request, e.g. for authentication.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"oauth2_token_url": {
+					Type: cty.String,
+					Description: "The URL of an OAuth2 token endpoint. When " +
+						"set, an access token is obtained using the client " +
+						"credentials grant and sent with the request.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"oauth2_client_id": {
+					Type:            cty.String,
+					Description:     "The OAuth2 client ID for the client credentials grant.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"oauth2_client_secret": {
+					Type:            cty.String,
+					Description:     "The OAuth2 client secret for the client credentials grant.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+					Sensitive:       true,
+				},
+				"oauth2_scopes": {
+					Type:            cty.List(cty.String),
+					Description:     "The OAuth2 scopes to request, if any.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"defaults": {
+					Type: cty.DynamicPseudoType,
+					Description: "Default values for outputs, in case " +
+						"the document lacks a required output.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"outputs": {
+					Type: cty.DynamicPseudoType,
+					Description: "An object containing every output value " +
+						"in the fetched document.",
+					DescriptionKind: configschema.StringMarkdown,
+					Computed:        true,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRemoteOutputsValidate(cfg cty.Value) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if urlVal := cfg.GetAttr("url"); urlVal.IsKnown() && !urlVal.IsNull() {
+		u, err := url.Parse(urlVal.AsString())
+		if err != nil {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid outputs endpoint URL",
+				fmt.Sprintf("The given URL is not valid: %s.", err),
+				cty.GetAttrPath("url"),
+			))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid outputs endpoint URL",
+				"The outputs endpoint URL must use the http or https scheme.",
+				cty.GetAttrPath("url"),
+			))
+		}
+	}
+
+	tokenURL := cfg.GetAttr("oauth2_token_url")
+	clientID := cfg.GetAttr("oauth2_client_id")
+	clientSecret := cfg.GetAttr("oauth2_client_secret")
+	if tokenURL.IsKnown() && clientID.IsKnown() && clientSecret.IsKnown() {
+		set := 0
+		for _, v := range []cty.Value{tokenURL, clientID, clientSecret} {
+			if !v.IsNull() {
+				set++
+			}
+		}
+		if set != 0 && set != 3 {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Incomplete OAuth2 configuration",
+				"To use OAuth2 client credentials, oauth2_token_url, oauth2_client_id, and oauth2_client_secret must all be set.",
+				cty.GetAttrPath("oauth2_token_url"),
+			))
+		}
+	}
+	if scopes := cfg.GetAttr("oauth2_scopes"); !scopes.IsNull() && tokenURL.IsKnown() && tokenURL.IsNull() {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Incomplete OAuth2 configuration",
+			"oauth2_scopes can be set only in conjunction with oauth2_token_url.",
+			cty.GetAttrPath("oauth2_scopes"),
+		))
+	}
+
+	{
+		defaultsTy := cfg.GetAttr("defaults").Type()
+		if defaultsTy != cty.DynamicPseudoType && !(defaultsTy.IsObjectType() || defaultsTy.IsMapType()) {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid default values",
+				"Defaults must be given in an object value.",
+				cty.GetAttrPath("defaults"),
+			))
+		}
+	}
+
+	return diags
+}
+
+func dataSourceRemoteOutputsRead(d cty.Value) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	newState := make(map[string]cty.Value)
+	for _, name := range []string{"url", "headers", "oauth2_token_url", "oauth2_client_id", "oauth2_client_secret", "oauth2_scopes", "defaults"} {
+		newState[name] = d.GetAttr(name)
+	}
+
+	client := httpclient.New()
+	if tokenURL := d.GetAttr("oauth2_token_url"); !tokenURL.IsNull() {
+		cc := &clientcredentials.Config{
+			ClientID:     d.GetAttr("oauth2_client_id").AsString(),
+			ClientSecret: d.GetAttr("oauth2_client_secret").AsString(),
+			TokenURL:     tokenURL.AsString(),
+		}
+		if scopes := d.GetAttr("oauth2_scopes"); !scopes.IsNull() {
+			for it := scopes.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+				cc.Scopes = append(cc.Scopes, v.AsString())
+			}
+		}
+		// The token request uses our own client, so that it too carries the
+		// Terraform user agent.
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+		client = cc.Client(ctx)
+	}
+
+	req, err := http.NewRequest("GET", d.GetAttr("url").AsString(), nil)
+	if err != nil {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid outputs endpoint URL",
+			fmt.Sprintf("The given URL is not valid: %s.", err),
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+	req.Header.Set("Accept", "application/json")
+	if headers := d.GetAttr("headers"); !headers.IsNull() {
+		for it := headers.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			req.Header.Set(k.AsString(), v.AsString())
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Failed to fetch outputs document",
+			fmt.Sprintf("Error requesting the outputs document: %s.", err),
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Failed to fetch outputs document",
+			fmt.Sprintf("The outputs endpoint returned status %s.", resp.Status),
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Failed to fetch outputs document",
+			fmt.Sprintf("Error reading the outputs document: %s.", err),
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+
+	outputsVal, moreDiags := parseOutputsDocument(body)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	outputs := make(map[string]cty.Value)
+	if defaultsVal := d.GetAttr("defaults"); !defaultsVal.IsNull() {
+		for it := defaultsVal.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			outputs[k.AsString()] = v
+		}
+	}
+	for it := outputsVal.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		outputs[k.AsString()] = v
+	}
+	newState["outputs"] = cty.ObjectVal(outputs)
+
+	return cty.ObjectVal(newState), diags
+}
+
+// parseOutputsDocument decodes a JSON outputs document into an object value.
+// The document must be a JSON object, either mapping output names directly
+// to their values or in the wrapped format produced by
+// "terraform output -json", in which each name maps to an object with a
+// "value" property.
+func parseOutputsDocument(src []byte) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ty, err := ctyjson.ImpliedType(src)
+	if err != nil {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid outputs document",
+			fmt.Sprintf("The outputs endpoint did not return valid JSON: %s.", err),
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+	if !ty.IsObjectType() {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid outputs document",
+			"The outputs endpoint must return a JSON object whose properties are the output values.",
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+	val, err := ctyjson.Unmarshal(src, ty)
+	if err != nil {
+		// Shouldn't get here, since the document already survived ImpliedType.
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid outputs document",
+			fmt.Sprintf("The outputs endpoint did not return valid JSON: %s.", err),
+			cty.GetAttrPath("url"),
+		))
+		return cty.NilVal, diags
+	}
+
+	// If every property is itself an object with a "value" property then
+	// this looks like the "terraform output -json" format, so we'll unwrap
+	// the values.
+	if attrTys := ty.AttributeTypes(); len(attrTys) > 0 {
+		wrapped := true
+		for _, aty := range attrTys {
+			if !aty.IsObjectType() || !aty.HasAttribute("value") {
+				wrapped = false
+				break
+			}
+		}
+		if wrapped {
+			unwrapped := make(map[string]cty.Value)
+			for name := range attrTys {
+				unwrapped[name] = val.GetAttr(name).GetAttr("value")
+			}
+			val = cty.ObjectVal(unwrapped)
+		}
+	}
+
+	return val, diags
+}
@@ -0,0 +1,246 @@
+package terraform
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apparentlymart/go-dump/dump"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestRemoteOutputsSchema(t *testing.T) {
+	if err := dataSourceRemoteOutputsGetSchema().Block.InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestRemoteOutputs_basic(t *testing.T) {
+	var tests = map[string]struct {
+		Handler http.HandlerFunc
+		Config  map[string]cty.Value
+		Want    cty.Value
+		Err     bool
+	}{
+		"plain object": {
+			func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"foo":"bar","count":2}`))
+			},
+			nil,
+			cty.ObjectVal(map[string]cty.Value{
+				"foo":   cty.StringVal("bar"),
+				"count": cty.NumberIntVal(2),
+			}),
+			false,
+		},
+		"terraform output -json format": {
+			func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"foo":{"value":"bar","type":"string","sensitive":false}}`))
+			},
+			nil,
+			cty.ObjectVal(map[string]cty.Value{
+				"foo": cty.StringVal("bar"),
+			}),
+			false,
+		},
+		"headers": {
+			func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Bearer abc123" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{"foo":"bar"}`))
+			},
+			map[string]cty.Value{
+				"headers": cty.MapVal(map[string]cty.Value{
+					"Authorization": cty.StringVal("Bearer abc123"),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"foo": cty.StringVal("bar"),
+			}),
+			false,
+		},
+		"defaults": {
+			func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`{"foo":"bar"}`))
+			},
+			map[string]cty.Value{
+				"defaults": cty.ObjectVal(map[string]cty.Value{
+					"foo":   cty.StringVal("overridden"),
+					"extra": cty.StringVal("default"),
+				}),
+			},
+			cty.ObjectVal(map[string]cty.Value{
+				"foo":   cty.StringVal("bar"),
+				"extra": cty.StringVal("default"),
+			}),
+			false,
+		},
+		"error status": {
+			func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			nil,
+			cty.NilVal,
+			true,
+		},
+		"not an object": {
+			func(w http.ResponseWriter, req *http.Request) {
+				w.Write([]byte(`["foo"]`))
+			},
+			nil,
+			cty.NilVal,
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(test.Handler)
+			defer server.Close()
+
+			attrs := map[string]cty.Value{
+				"url": cty.StringVal(server.URL),
+			}
+			for k, v := range test.Config {
+				attrs[k] = v
+			}
+
+			schema := dataSourceRemoteOutputsGetSchema().Block
+			config, err := schema.CoerceValue(cty.ObjectVal(attrs))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			diags := dataSourceRemoteOutputsValidate(config)
+			var got cty.Value
+			if !diags.HasErrors() {
+				var moreDiags tfdiags.Diagnostics
+				got, moreDiags = dataSourceRemoteOutputsRead(config)
+				diags = diags.Append(moreDiags)
+			}
+
+			if test.Err {
+				if !diags.HasErrors() {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+
+			outputs := got.GetAttr("outputs")
+			if !test.Want.RawEquals(outputs) {
+				t.Errorf("wrong result\ngot:  %swant: %s", dump.Value(outputs), dump.Value(test.Want))
+			}
+		})
+	}
+}
+
+func TestRemoteOutputs_oauth2(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := req.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id, secret, _ := req.BasicAuth()
+		if id == "" {
+			id = req.Form.Get("client_id")
+			secret = req.Form.Get("client_secret")
+		}
+		if id != "test-client" || secret != "test-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"opaque-token","token_type":"bearer"}`))
+	}))
+	defer tokenServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer opaque-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"foo":"bar"}`))
+	}))
+	defer server.Close()
+
+	schema := dataSourceRemoteOutputsGetSchema().Block
+	config, err := schema.CoerceValue(cty.ObjectVal(map[string]cty.Value{
+		"url":                  cty.StringVal(server.URL),
+		"oauth2_token_url":     cty.StringVal(tokenServer.URL),
+		"oauth2_client_id":     cty.StringVal("test-client"),
+		"oauth2_client_secret": cty.StringVal("test-secret"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	diags := dataSourceRemoteOutputsValidate(config)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	got, diags := dataSourceRemoteOutputsRead(config)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	want := cty.ObjectVal(map[string]cty.Value{
+		"foo": cty.StringVal("bar"),
+	})
+	if outputs := got.GetAttr("outputs"); !want.RawEquals(outputs) {
+		t.Errorf("wrong result\ngot:  %swant: %s", dump.Value(outputs), dump.Value(want))
+	}
+}
+
+func TestRemoteOutputs_validate(t *testing.T) {
+	tests := map[string]struct {
+		Config  map[string]cty.Value
+		WantErr string
+	}{
+		"bad scheme": {
+			map[string]cty.Value{
+				"url": cty.StringVal("ftp://example.com/outputs.json"),
+			},
+			"must use the http or https scheme",
+		},
+		"incomplete oauth2": {
+			map[string]cty.Value{
+				"url":              cty.StringVal("https://example.com/outputs.json"),
+				"oauth2_token_url": cty.StringVal("https://example.com/token"),
+			},
+			"Incomplete OAuth2 configuration",
+		},
+		"scopes without token url": {
+			map[string]cty.Value{
+				"url":           cty.StringVal("https://example.com/outputs.json"),
+				"oauth2_scopes": cty.ListVal([]cty.Value{cty.StringVal("read")}),
+			},
+			"oauth2_scopes can be set only in conjunction with oauth2_token_url",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema := dataSourceRemoteOutputsGetSchema().Block
+			config, err := schema.CoerceValue(cty.ObjectVal(test.Config))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			diags := dataSourceRemoteOutputsValidate(config)
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want error")
+			}
+			if got := fmt.Sprintf("%s", diags.Err()); !strings.Contains(got, test.WantErr) {
+				t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, test.WantErr)
+			}
+		})
+	}
+}
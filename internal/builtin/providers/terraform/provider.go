@@ -25,7 +25,8 @@ func NewProvider() providers.Interface {
 func (p *Provider) GetProviderSchema() providers.GetProviderSchemaResponse {
 	return providers.GetProviderSchemaResponse{
 		DataSources: map[string]providers.Schema{
-			"terraform_remote_state": dataSourceRemoteStateGetSchema(),
+			"terraform_remote_state":   dataSourceRemoteStateGetSchema(),
+			"terraform_remote_outputs": dataSourceRemoteOutputsGetSchema(),
 		},
 	}
 }
@@ -46,15 +47,16 @@ func (p *Provider) ValidateDataResourceConfig(req providers.ValidateDataResource
 	// errors in terraform validate as well as during terraform plan.
 	var res providers.ValidateDataResourceConfigResponse
 
-	// This should not happen
-	if req.TypeName != "terraform_remote_state" {
+	switch req.TypeName {
+	case "terraform_remote_state":
+		res.Diagnostics = dataSourceRemoteStateValidate(req.Config)
+	case "terraform_remote_outputs":
+		res.Diagnostics = dataSourceRemoteOutputsValidate(req.Config)
+	default:
+		// This should not happen
 		res.Diagnostics.Append(fmt.Errorf("Error: unsupported data source %s", req.TypeName))
-		return res
 	}
 
-	diags := dataSourceRemoteStateValidate(req.Config)
-	res.Diagnostics = diags
-
 	return res
 }
 
@@ -71,17 +73,16 @@ func (p *Provider) ReadDataSource(req providers.ReadDataSourceRequest) providers
 	// call function
 	var res providers.ReadDataSourceResponse
 
-	// This should not happen
-	if req.TypeName != "terraform_remote_state" {
+	switch req.TypeName {
+	case "terraform_remote_state":
+		res.State, res.Diagnostics = dataSourceRemoteStateRead(req.Config)
+	case "terraform_remote_outputs":
+		res.State, res.Diagnostics = dataSourceRemoteOutputsRead(req.Config)
+	default:
+		// This should not happen
 		res.Diagnostics.Append(fmt.Errorf("Error: unsupported data source %s", req.TypeName))
-		return res
 	}
 
-	newState, diags := dataSourceRemoteStateRead(req.Config)
-
-	res.State = newState
-	res.Diagnostics = diags
-
 	return res
 }
 
@@ -92,8 +93,8 @@ func (p *Provider) Stop() error {
 }
 
 // All the Resource-specific functions are below.
-// The terraform provider supplies a single data source, `terraform_remote_state`
-// and no resources.
+// The terraform provider supplies only data sources, `terraform_remote_state`
+// and `terraform_remote_outputs`, and no resources.
 
 // UpgradeResourceState is called when the state loader encounters an
 // instance state whose schema version is less than the one reported by the
@@ -79,6 +79,11 @@ type Cloud struct {
 	// forceLocal, if true, will force the use of the local backend.
 	forceLocal bool
 
+	// allowLocalExecution, if true, permits the user to override remote
+	// execution for a single operation with the -execution=local command
+	// line option, as a fallback for when remote runs are unavailable.
+	allowLocalExecution bool
+
 	// opLock locks operations
 	opLock sync.Mutex
 
@@ -125,6 +130,11 @@ func (b *Cloud) ConfigSchema() *configschema.Block {
 				Optional:    true,
 				Description: schemaDescriptionToken,
 			},
+			"allow_local_execution": {
+				Type:        cty.Bool,
+				Optional:    true,
+				Description: schemaDescriptionAllowLocalExecution,
+			},
 		},
 
 		BlockTypes: map[string]*configschema.NestedBlock{
@@ -398,6 +408,12 @@ func (b *Cloud) setConfigurationFields(obj cty.Value) tfdiags.Diagnostics {
 		b.WorkspaceMapping.Name = os.Getenv("TF_WORKSPACE")
 	}
 
+	// Determine whether the organization has opted in to allowing the
+	// -execution=local override.
+	if val := obj.GetAttr("allow_local_execution"); !val.IsNull() {
+		b.allowLocalExecution = val.True()
+	}
+
 	// Determine if we are forced to use the local backend.
 	b.forceLocal = os.Getenv("TF_FORCE_LOCAL_BACKEND") != ""
 
@@ -657,6 +673,21 @@ func (b *Cloud) Operation(ctx context.Context, op *backend.Operation) (*backend.
 		return b.local.Operation(ctx, op)
 	}
 
+	// An explicit -execution=local on the command line runs this one
+	// operation with the local backend even though the workspace normally
+	// executes remotely, as an escape hatch for when the remote run
+	// service is unavailable. State storage and locking still go through
+	// Terraform Cloud as usual.
+	if op.ForceLocalExecution {
+		if err := b.confirmLocalExecutionOverride(ctx, op, w); err != nil {
+			return nil, err
+		}
+		// Record that we're running locally to allow the command package
+		// UI to operate correctly.
+		b.forceLocal = true
+		return b.local.Operation(ctx, op)
+	}
+
 	// Set the remote workspace name.
 	op.Workspace = w.Name
 
@@ -756,6 +787,39 @@ func (b *Cloud) Operation(ctx context.Context, op *backend.Operation) (*backend.
 	return runningOp, nil
 }
 
+// confirmLocalExecutionOverride checks that the -execution=local override
+// may be used with this configuration and, if so, asks the user to confirm
+// it interactively. It returns an error if the override is not permitted
+// or was not confirmed, in which case the operation must not run.
+func (b *Cloud) confirmLocalExecutionOverride(ctx context.Context, op *backend.Operation, w *tfe.Workspace) error {
+	if !b.allowLocalExecution {
+		return fmt.Errorf(strings.TrimSpace(errLocalExecutionNotAllowed), b.organization)
+	}
+
+	if op.UIIn == nil || !b.input {
+		return fmt.Errorf(strings.TrimSpace(errLocalExecutionNoInput))
+	}
+
+	if b.CLI != nil {
+		b.CLI.Output(b.Colorize().Color(strings.TrimSpace(
+			fmt.Sprintf(localExecutionWarning, b.organization, w.Name)) + "\n"))
+	}
+
+	v, err := op.UIIn.Input(ctx, &terraform.InputOpts{
+		Id:          "execution-override",
+		Query:       "Do you want to run this operation locally?",
+		Description: "Only 'yes' will be accepted to confirm.",
+	})
+	if err != nil {
+		return fmt.Errorf("Error asking to confirm local execution: %w", err)
+	}
+	if strings.ToLower(strings.TrimSpace(v)) != "yes" {
+		return fmt.Errorf("Local execution override canceled.")
+	}
+
+	return nil
+}
+
 func (b *Cloud) cancel(cancelCtx context.Context, op *backend.Operation, r *tfe.Run) error {
 	if r.Actions.IsCancelable {
 		// Only ask if the remote operation should be canceled
@@ -920,6 +984,7 @@ func (b *Cloud) IsLocalOperations() bool {
 // as a helper to wrap any potentially colored strings.
 //
 // TODO SvH: Rename this back to Colorize as soon as we can pass -no-color.
+//
 //lint:ignore U1000 see above todo
 func (b *Cloud) cliColorize() *colorstring.Colorize {
 	if b.CLIColor != nil {
@@ -1144,6 +1209,26 @@ const operationNotCanceled = `
 
 const refreshToApplyRefresh = `[bold][yellow]Proceeding with 'terraform apply -refresh-only -auto-approve'.[reset]`
 
+const errLocalExecutionNotAllowed = `
+Local execution override is not allowed for organization %q.
+
+The -execution=local option can be used only when the cloud block of this
+configuration sets allow_local_execution = true. Ask the administrators of
+this configuration to enable it if your organization wants to permit running
+operations locally when remote runs are unavailable.
+`
+
+const errLocalExecutionNoInput = `
+The -execution=local option requires interactive confirmation, so it cannot
+be used when input is disabled.
+`
+
+const localExecutionWarning = `
+[reset][yellow]This operation will run locally instead of in Terraform Cloud, bypassing any
+remote run controls configured for organization %q. State for workspace %q
+will still be stored and locked in Terraform Cloud.[reset]
+`
+
 const unavailableTerraformVersion = `
 [reset][yellow]The local Terraform version (%s) is not available in Terraform Cloud, or your
 organization does not have access to it. The new workspace will use %s. You can
@@ -1173,6 +1258,10 @@ for use with Terraform Cloud.`
 be set, and 'terraform login' used instead; your credentials will then be fetched from your CLI
 configuration file or configured credential helper.`
 
+	schemaDescriptionAllowLocalExecution = `Whether to allow the -execution=local command line option to run plan and apply
+operations locally while still using Terraform Cloud for state storage and locking, as a fallback for
+when remote runs are unavailable. Defaults to false.`
+
 	schemaDescriptionTags = `A set of tags used to select remote Terraform Cloud workspaces to be used for this single
 configuration. New workspaces will automatically be tagged with these tag values. Generally, this
 is the primary and recommended strategy to use.  This option conflicts with "name".`
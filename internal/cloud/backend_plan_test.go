@@ -96,6 +96,108 @@ func TestCloud_planBasic(t *testing.T) {
 	}
 }
 
+func TestCloud_planLocalExecutionOverride(t *testing.T) {
+	t.Run("not allowed by configuration", func(t *testing.T) {
+		b, bCleanup := testBackendWithName(t)
+		defer bCleanup()
+
+		op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+		defer configCleanup()
+		defer done(t)
+
+		op.Workspace = testBackendSingleWorkspaceName
+		op.ForceLocalExecution = true
+
+		_, err := b.Operation(context.Background(), op)
+		if err == nil {
+			t.Fatal("expected error, got none")
+		}
+		if !strings.Contains(err.Error(), "allow_local_execution") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("input disabled", func(t *testing.T) {
+		b, bCleanup := testBackendWithName(t)
+		defer bCleanup()
+		b.allowLocalExecution = true
+		b.input = false
+
+		op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+		defer configCleanup()
+		defer done(t)
+
+		op.Workspace = testBackendSingleWorkspaceName
+		op.ForceLocalExecution = true
+
+		_, err := b.Operation(context.Background(), op)
+		if err == nil {
+			t.Fatal("expected error, got none")
+		}
+		if !strings.Contains(err.Error(), "requires interactive confirmation") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("not confirmed", func(t *testing.T) {
+		b, bCleanup := testBackendWithName(t)
+		defer bCleanup()
+		b.allowLocalExecution = true
+
+		op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+		defer configCleanup()
+		defer done(t)
+
+		op.Workspace = testBackendSingleWorkspaceName
+		op.ForceLocalExecution = true
+		op.UIIn = testInput(t, map[string]string{
+			"execution-override": "no",
+		})
+
+		_, err := b.Operation(context.Background(), op)
+		if err == nil {
+			t.Fatal("expected error, got none")
+		}
+		if !strings.Contains(err.Error(), "Local execution override canceled") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		b, bCleanup := testBackendWithName(t)
+		defer bCleanup()
+		b.allowLocalExecution = true
+
+		op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+		defer configCleanup()
+		defer done(t)
+
+		op.Workspace = testBackendSingleWorkspaceName
+		op.ForceLocalExecution = true
+		op.UIIn = testInput(t, map[string]string{
+			"execution-override": "yes",
+		})
+
+		run, err := b.Operation(context.Background(), op)
+		if err != nil {
+			t.Fatalf("error starting operation: %v", err)
+		}
+
+		<-run.Done()
+		if run.Result != backend.OperationSuccess {
+			t.Fatalf("operation failed: %s", b.CLI.(*cli.MockUi).ErrorWriter.String())
+		}
+
+		output := b.CLI.(*cli.MockUi).OutputWriter.String()
+		if !strings.Contains(output, "will run locally instead of in Terraform Cloud") {
+			t.Fatalf("expected local execution warning in output: %s", output)
+		}
+		if strings.Contains(output, "Running plan in Terraform Cloud") {
+			t.Fatalf("unexpected TFC header in output: %s", output)
+		}
+	})
+}
+
 func TestCloud_planCanceled(t *testing.T) {
 	b, bCleanup := testBackendWithName(t)
 	defer bCleanup()
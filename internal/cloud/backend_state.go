@@ -6,6 +6,7 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	tfe "github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/terraform/internal/states/remote"
@@ -180,3 +181,36 @@ func (r *remoteClient) Unlock(id string) error {
 
 	return nil
 }
+
+// lockRenewInterval is how often we re-check that the workspace lock we took
+// is still held during a long-running operation.
+const lockRenewInterval = 5 * time.Minute
+
+// LockRenewInterval implements remote.ClientLockRenewer.
+func (r *remoteClient) LockRenewInterval() time.Duration {
+	return lockRenewInterval
+}
+
+// RenewLock verifies that the workspace is still locked on our behalf.
+// Terraform Cloud locks don't expire on their own, so there is no lease to
+// extend; this instead acts as a keep-alive that detects a lock lost to a
+// force-unlock while an operation is still running.
+func (r *remoteClient) RenewLock(id string) error {
+	if r.lockInfo == nil || r.lockInfo.ID != id {
+		return fmt.Errorf("lock ID does not match existing lock")
+	}
+
+	ws, err := r.client.Workspaces.ReadByID(context.Background(), r.workspace.ID)
+	if err != nil {
+		return fmt.Errorf("Error checking workspace lock: %v", err)
+	}
+	if !ws.Locked {
+		return fmt.Errorf(
+			"workspace \"%s/%s\" is no longer locked; it may have been force-unlocked by another user",
+			r.organization,
+			r.workspace.Name,
+		)
+	}
+
+	return nil
+}
@@ -233,9 +233,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 	}{
 		"with no organization specified": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.NullVal(cty.String),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.NullVal(cty.String),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -248,9 +249,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 		},
 		"with both organization and env var specified": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("hashicorp"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -263,9 +265,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 		},
 		"with no hostname specified": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("hashicorp"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -278,9 +281,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 		},
 		"with hostname and env var specified": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal("private.hashicorp.engineering"),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
+				"hostname":              cty.StringVal("private.hashicorp.engineering"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("hashicorp"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -293,9 +297,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 		},
 		"an invalid workspace env var": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("hashicorp"),
 				"workspaces": cty.NullVal(cty.Object(map[string]cty.Type{
 					"name": cty.String,
 					"tags": cty.Set(cty.String),
@@ -308,9 +313,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 		},
 		"workspaces and env var specified": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("mordor"),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("mordor"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("mt-doom"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -332,9 +338,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 				})
 			},
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("mordor"),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("mordor"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.NullVal(cty.String),
 					"tags": cty.SetVal([]cty.Value{
@@ -363,9 +370,10 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 				})
 			},
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.StringVal("mordor"),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.StringVal("mordor"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.NullVal(cty.String),
 					"tags": cty.SetVal([]cty.Value{
@@ -380,10 +388,11 @@ func TestCloud_configWithEnvVars(t *testing.T) {
 		},
 		"with everything set as env vars": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"token":        cty.NullVal(cty.String),
-				"organization": cty.NullVal(cty.String),
-				"workspaces":   cty.NullVal(cty.String),
+				"hostname":              cty.NullVal(cty.String),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"organization":          cty.NullVal(cty.String),
+				"workspaces":            cty.NullVal(cty.String),
 			}),
 			vars: map[string]string{
 				"TF_CLOUD_ORGANIZATION": "mordor",
@@ -449,9 +458,10 @@ func TestCloud_config(t *testing.T) {
 	}{
 		"with_an_unknown_host": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal("nonexisting.local"),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":              cty.StringVal("nonexisting.local"),
+				"organization":          cty.StringVal("hashicorp"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -462,9 +472,10 @@ func TestCloud_config(t *testing.T) {
 		// localhost advertises TFE services, but has no token in the credentials
 		"without_a_token": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.StringVal("localhost"),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":              cty.StringVal("localhost"),
+				"organization":          cty.StringVal("hashicorp"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -474,9 +485,10 @@ func TestCloud_config(t *testing.T) {
 		},
 		"with_tags": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":              cty.NullVal(cty.String),
+				"organization":          cty.StringVal("hashicorp"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.NullVal(cty.String),
 					"tags": cty.SetVal(
@@ -489,9 +501,10 @@ func TestCloud_config(t *testing.T) {
 		},
 		"with_a_name": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":              cty.NullVal(cty.String),
+				"organization":          cty.StringVal("hashicorp"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -500,9 +513,10 @@ func TestCloud_config(t *testing.T) {
 		},
 		"without_a_name_tags": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":              cty.NullVal(cty.String),
+				"organization":          cty.StringVal("hashicorp"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.NullVal(cty.String),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -512,9 +526,10 @@ func TestCloud_config(t *testing.T) {
 		},
 		"with_both_a_name_and_tags": {
 			config: cty.ObjectVal(map[string]cty.Value{
-				"hostname":     cty.NullVal(cty.String),
-				"organization": cty.StringVal("hashicorp"),
-				"token":        cty.NullVal(cty.String),
+				"hostname":              cty.NullVal(cty.String),
+				"organization":          cty.StringVal("hashicorp"),
+				"token":                 cty.NullVal(cty.String),
+				"allow_local_execution": cty.NullVal(cty.Bool),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.SetVal(
@@ -553,9 +568,10 @@ func TestCloud_config(t *testing.T) {
 
 func TestCloud_configVerifyMinimumTFEVersion(t *testing.T) {
 	config := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.NullVal(cty.String),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":              cty.NullVal(cty.String),
+		"organization":          cty.StringVal("hashicorp"),
+		"token":                 cty.NullVal(cty.String),
+		"allow_local_execution": cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name": cty.NullVal(cty.String),
 			"tags": cty.SetVal(
@@ -589,9 +605,10 @@ func TestCloud_configVerifyMinimumTFEVersion(t *testing.T) {
 
 func TestCloud_configVerifyMinimumTFEVersionInAutomation(t *testing.T) {
 	config := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.NullVal(cty.String),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":              cty.NullVal(cty.String),
+		"organization":          cty.StringVal("hashicorp"),
+		"token":                 cty.NullVal(cty.String),
+		"allow_local_execution": cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name": cty.NullVal(cty.String),
 			"tags": cty.SetVal(
@@ -632,9 +649,10 @@ func TestCloud_setUnavailableTerraformVersion(t *testing.T) {
 	workspaceName := "unavailable-terraform-version"
 
 	config := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.NullVal(cty.String),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":              cty.NullVal(cty.String),
+		"organization":          cty.StringVal("hashicorp"),
+		"token":                 cty.NullVal(cty.String),
+		"allow_local_execution": cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name": cty.NullVal(cty.String),
 			"tags": cty.SetVal(
@@ -693,8 +711,9 @@ func TestCloud_setConfigurationFields(t *testing.T) {
 	}{
 		"with hostname set": {
 			obj: cty.ObjectVal(map[string]cty.Value{
-				"organization": cty.StringVal("hashicorp"),
-				"hostname":     cty.StringVal("hashicorp.com"),
+				"organization":          cty.StringVal("hashicorp"),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"hostname":              cty.StringVal("hashicorp.com"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -705,8 +724,9 @@ func TestCloud_setConfigurationFields(t *testing.T) {
 		},
 		"with hostname not set, set to default hostname": {
 			obj: cty.ObjectVal(map[string]cty.Value{
-				"organization": cty.StringVal("hashicorp"),
-				"hostname":     cty.NullVal(cty.String),
+				"organization":          cty.StringVal("hashicorp"),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"hostname":              cty.NullVal(cty.String),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -717,8 +737,9 @@ func TestCloud_setConfigurationFields(t *testing.T) {
 		},
 		"with workspace name set": {
 			obj: cty.ObjectVal(map[string]cty.Value{
-				"organization": cty.StringVal("hashicorp"),
-				"hostname":     cty.StringVal("hashicorp.com"),
+				"organization":          cty.StringVal("hashicorp"),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"hostname":              cty.StringVal("hashicorp.com"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.StringVal("prod"),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -730,8 +751,9 @@ func TestCloud_setConfigurationFields(t *testing.T) {
 		},
 		"with workspace tags set": {
 			obj: cty.ObjectVal(map[string]cty.Value{
-				"organization": cty.StringVal("hashicorp"),
-				"hostname":     cty.StringVal("hashicorp.com"),
+				"organization":          cty.StringVal("hashicorp"),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"hostname":              cty.StringVal("hashicorp.com"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.NullVal(cty.String),
 					"tags": cty.SetVal(
@@ -747,8 +769,9 @@ func TestCloud_setConfigurationFields(t *testing.T) {
 		},
 		"with force local set": {
 			obj: cty.ObjectVal(map[string]cty.Value{
-				"organization": cty.StringVal("hashicorp"),
-				"hostname":     cty.StringVal("hashicorp.com"),
+				"organization":          cty.StringVal("hashicorp"),
+				"allow_local_execution": cty.NullVal(cty.Bool),
+				"hostname":              cty.StringVal("hashicorp.com"),
 				"workspaces": cty.ObjectVal(map[string]cty.Value{
 					"name": cty.NullVal(cty.String),
 					"tags": cty.NullVal(cty.Set(cty.String)),
@@ -67,9 +67,10 @@ func testInput(t *testing.T, answers map[string]string) *mockInput {
 
 func testBackendWithName(t *testing.T) (*Cloud, func()) {
 	obj := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.NullVal(cty.String),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":              cty.NullVal(cty.String),
+		"organization":          cty.StringVal("hashicorp"),
+		"token":                 cty.NullVal(cty.String),
+		"allow_local_execution": cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name": cty.StringVal(testBackendSingleWorkspaceName),
 			"tags": cty.NullVal(cty.Set(cty.String)),
@@ -80,9 +81,10 @@ func testBackendWithName(t *testing.T) (*Cloud, func()) {
 
 func testBackendWithTags(t *testing.T) (*Cloud, func()) {
 	obj := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.NullVal(cty.String),
-		"organization": cty.StringVal("hashicorp"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":              cty.NullVal(cty.String),
+		"organization":          cty.StringVal("hashicorp"),
+		"token":                 cty.NullVal(cty.String),
+		"allow_local_execution": cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name": cty.NullVal(cty.String),
 			"tags": cty.SetVal(
@@ -97,9 +99,10 @@ func testBackendWithTags(t *testing.T) (*Cloud, func()) {
 
 func testBackendNoOperations(t *testing.T) (*Cloud, func()) {
 	obj := cty.ObjectVal(map[string]cty.Value{
-		"hostname":     cty.NullVal(cty.String),
-		"organization": cty.StringVal("no-operations"),
-		"token":        cty.NullVal(cty.String),
+		"hostname":              cty.NullVal(cty.String),
+		"organization":          cty.StringVal("no-operations"),
+		"token":                 cty.NullVal(cty.String),
+		"allow_local_execution": cty.NullVal(cty.Bool),
 		"workspaces": cty.ObjectVal(map[string]cty.Value{
 			"name": cty.StringVal(testBackendSingleWorkspaceName),
 			"tags": cty.NullVal(cty.Set(cty.String)),
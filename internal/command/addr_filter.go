@@ -0,0 +1,40 @@
+package command
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/command/addrfilter"
+	"github.com/hashicorp/terraform/internal/states"
+)
+
+// filterResourceInstanceAddrs returns the addresses of all of the resource
+// instances in the given state that match the given filter expression, in
+// address order.
+func filterResourceInstanceAddrs(state *states.State, filter *addrfilter.Filter) []addrs.AbsResourceInstance {
+	var ret []addrs.AbsResourceInstance
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key := range rs.Instances {
+				addr := rs.Addr.Instance(key)
+				if filter.Match(addrfilter.NewSubject(addr, rs.ProviderConfig.Provider)) {
+					ret = append(ret, addr)
+				}
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret
+}
+
+// matchesResourceInstanceFilter returns true if the given address, which
+// must exist in the given state, matches the given filter expression.
+func matchesResourceInstanceFilter(state *states.State, filter *addrfilter.Filter, addr addrs.AbsResourceInstance) bool {
+	rs := state.Resource(addr.ContainingResource())
+	if rs == nil {
+		return false
+	}
+	return filter.Match(addrfilter.NewSubject(addr, rs.ProviderConfig.Provider))
+}
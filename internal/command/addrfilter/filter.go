@@ -0,0 +1,439 @@
+// Package addrfilter implements a small filter expression language for
+// selecting resource instances by the components of their addresses, used
+// by commands that offer a -filter option for bulk operations.
+//
+// A filter expression is a boolean combination of comparisons between an
+// address component and a quoted string, for example:
+//
+//	type == "aws_iam_role" && module =~ "^module\\.network"
+//
+// The available components are "addr", "module", "mode", "type", "name",
+// "key", and "provider". The comparison operators are == and != for exact
+// string comparison and =~ and !~ for regular expression matching, and
+// comparisons can be combined with &&, ||, !, and parentheses.
+package addrfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// Subject is the set of address components that a filter expression is
+// evaluated against, describing one resource instance.
+type Subject struct {
+	// Addr is the full display form of the instance address.
+	Addr string
+
+	// Module is the module portion of the address, such as
+	// "module.network.module.subnets", or the empty string for the root
+	// module.
+	Module string
+
+	// Mode is "managed" or "data".
+	Mode string
+
+	// Type and Name are the resource type and name.
+	Type string
+	Name string
+
+	// Key is the string form of the instance key, without brackets or
+	// quotes, or the empty string for an instance with no key.
+	Key string
+
+	// Provider is the fully-qualified provider source address, such as
+	// "registry.terraform.io/hashicorp/aws".
+	Provider string
+}
+
+// NewSubject builds the Subject for the given resource instance address and
+// its provider.
+func NewSubject(addr addrs.AbsResourceInstance, provider addrs.Provider) Subject {
+	s := Subject{
+		Addr:     addr.String(),
+		Type:     addr.Resource.Resource.Type,
+		Name:     addr.Resource.Resource.Name,
+		Provider: provider.String(),
+	}
+	if !addr.Module.IsRoot() {
+		s.Module = addr.Module.String()
+	}
+	switch addr.Resource.Resource.Mode {
+	case addrs.DataResourceMode:
+		s.Mode = "data"
+	default:
+		s.Mode = "managed"
+	}
+	switch key := addr.Resource.Key.(type) {
+	case nil:
+		// leave Key empty
+	case addrs.StringKey:
+		s.Key = string(key)
+	case addrs.IntKey:
+		s.Key = fmt.Sprintf("%d", int(key))
+	default:
+		// Should never happen, since the above covers all key types, but
+		// the display form is a reasonable fallback.
+		s.Key = key.String()
+	}
+	return s
+}
+
+// component returns the value of the named address component, or false if
+// there is no such component.
+func (s Subject) component(name string) (string, bool) {
+	switch name {
+	case "addr":
+		return s.Addr, true
+	case "module":
+		return s.Module, true
+	case "mode":
+		return s.Mode, true
+	case "type":
+		return s.Type, true
+	case "name":
+		return s.Name, true
+	case "key":
+		return s.Key, true
+	case "provider":
+		return s.Provider, true
+	default:
+		return "", false
+	}
+}
+
+// Filter is a parsed filter expression, ready to be evaluated against
+// subjects.
+type Filter struct {
+	root expr
+	src  string
+}
+
+// Parse parses the given filter expression source, returning an error
+// describing the first problem if the source is not a valid expression.
+func Parse(src string) (*Filter, error) {
+	p := &parser{src: src}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", tok.describe(), tok.pos+1)
+	}
+	return &Filter{root: root, src: src}, nil
+}
+
+// Match returns true if the filter expression matches the given subject.
+func (f *Filter) Match(s Subject) bool {
+	return f.root.eval(s)
+}
+
+// String returns the source the filter was parsed from.
+func (f *Filter) String() string {
+	return f.src
+}
+
+// expr is one node of a parsed filter expression.
+type expr interface {
+	eval(s Subject) bool
+}
+
+type orExpr struct{ terms []expr }
+
+func (e orExpr) eval(s Subject) bool {
+	for _, term := range e.terms {
+		if term.eval(s) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr struct{ terms []expr }
+
+func (e andExpr) eval(s Subject) bool {
+	for _, term := range e.terms {
+		if !term.eval(s) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct{ term expr }
+
+func (e notExpr) eval(s Subject) bool {
+	return !e.term.eval(s)
+}
+
+type compareExpr struct {
+	component string
+	want      string         // for == and !=
+	pattern   *regexp.Regexp // for =~ and !~
+	negate    bool
+}
+
+func (e compareExpr) eval(s Subject) bool {
+	got, _ := s.component(e.component)
+	var match bool
+	if e.pattern != nil {
+		match = e.pattern.MatchString(got)
+	} else {
+		match = got == e.want
+	}
+	if e.negate {
+		return !match
+	}
+	return match
+}
+
+// The lexer and parser below implement the little grammar described in the
+// package documentation, with the usual precedence: ! binds tightest, then
+// &&, then ||.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenEq      // ==
+	tokenNeq     // !=
+	tokenMatch   // =~
+	tokenNoMatch // !~
+	tokenAnd     // &&
+	tokenOr      // ||
+	tokenNot     // !
+	tokenLParen  // (
+	tokenRParen  // )
+	tokenInvalid
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenIdent:
+		return fmt.Sprintf("%q", t.val)
+	case tokenString:
+		return fmt.Sprintf("string %q", t.val)
+	default:
+		return fmt.Sprintf("%q", t.val)
+	}
+}
+
+type parser struct {
+	src    string
+	pos    int
+	peeked *token
+}
+
+func (p *parser) peek() token {
+	if p.peeked == nil {
+		tok := p.lex()
+		p.peeked = &tok
+	}
+	return *p.peeked
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	p.peeked = nil
+	return tok
+}
+
+func (p *parser) lex() token {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return token{kind: tokenEOF, pos: p.pos}
+	}
+	start := p.pos
+	c := p.src[p.pos]
+	switch {
+	case c == '(':
+		p.pos++
+		return token{kind: tokenLParen, val: "(", pos: start}
+	case c == ')':
+		p.pos++
+		return token{kind: tokenRParen, val: ")", pos: start}
+	case c == '=':
+		if strings.HasPrefix(p.src[p.pos:], "==") {
+			p.pos += 2
+			return token{kind: tokenEq, val: "==", pos: start}
+		}
+		if strings.HasPrefix(p.src[p.pos:], "=~") {
+			p.pos += 2
+			return token{kind: tokenMatch, val: "=~", pos: start}
+		}
+	case c == '!':
+		if strings.HasPrefix(p.src[p.pos:], "!=") {
+			p.pos += 2
+			return token{kind: tokenNeq, val: "!=", pos: start}
+		}
+		if strings.HasPrefix(p.src[p.pos:], "!~") {
+			p.pos += 2
+			return token{kind: tokenNoMatch, val: "!~", pos: start}
+		}
+		p.pos++
+		return token{kind: tokenNot, val: "!", pos: start}
+	case c == '&':
+		if strings.HasPrefix(p.src[p.pos:], "&&") {
+			p.pos += 2
+			return token{kind: tokenAnd, val: "&&", pos: start}
+		}
+	case c == '|':
+		if strings.HasPrefix(p.src[p.pos:], "||") {
+			p.pos += 2
+			return token{kind: tokenOr, val: "||", pos: start}
+		}
+	case c == '"':
+		p.pos++
+		var buf strings.Builder
+		for p.pos < len(p.src) {
+			switch p.src[p.pos] {
+			case '"':
+				p.pos++
+				return token{kind: tokenString, val: buf.String(), pos: start}
+			case '\\':
+				if p.pos+1 < len(p.src) {
+					next := p.src[p.pos+1]
+					if next == '"' || next == '\\' {
+						buf.WriteByte(next)
+						p.pos += 2
+						continue
+					}
+				}
+				// Other backslash sequences pass through literally, so that
+				// regular expression escapes like \. don't need doubling.
+				buf.WriteByte('\\')
+				p.pos++
+			default:
+				buf.WriteByte(p.src[p.pos])
+				p.pos++
+			}
+		}
+		return token{kind: tokenInvalid, val: p.src[start:], pos: start}
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_':
+		for p.pos < len(p.src) {
+			c := p.src[p.pos]
+			if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+				p.pos++
+				continue
+			}
+			break
+		}
+		return token{kind: tokenIdent, val: p.src[start:p.pos], pos: start}
+	}
+	p.pos++
+	return token{kind: tokenInvalid, val: p.src[start:p.pos], pos: start}
+}
+
+func (p *parser) parseOr() (expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []expr{first}
+	for p.peek().kind == tokenOr {
+		p.next()
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return orExpr{terms: terms}, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []expr{first}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return andExpr{terms: terms}, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{term: term}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" but found %s at position %d", closing.describe(), closing.pos+1)
+		}
+		return inner, nil
+	case tokenIdent:
+		if _, ok := (Subject{}).component(tok.val); !ok {
+			return nil, fmt.Errorf("unknown address component %q at position %d; valid components are addr, module, mode, type, name, key, and provider", tok.val, tok.pos+1)
+		}
+		opTok := p.next()
+		switch opTok.kind {
+		case tokenEq, tokenNeq, tokenMatch, tokenNoMatch:
+			// ok
+		default:
+			return nil, fmt.Errorf("expected a comparison operator (==, !=, =~, or !~) after %q but found %s at position %d", tok.val, opTok.describe(), opTok.pos+1)
+		}
+		valTok := p.next()
+		if valTok.kind != tokenString {
+			return nil, fmt.Errorf("expected a quoted string after %q but found %s at position %d", opTok.val, valTok.describe(), valTok.pos+1)
+		}
+		ret := compareExpr{
+			component: tok.val,
+			negate:    opTok.kind == tokenNeq || opTok.kind == tokenNoMatch,
+		}
+		if opTok.kind == tokenMatch || opTok.kind == tokenNoMatch {
+			pattern, err := regexp.Compile(valTok.val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %s", valTok.val, err)
+			}
+			ret.pattern = pattern
+		} else {
+			ret.want = valTok.val
+		}
+		return ret, nil
+	case tokenString:
+		return nil, fmt.Errorf("unexpected string %q at position %d; comparisons are written with the address component first, such as type == %q", tok.val, tok.pos+1, tok.val)
+	default:
+		return nil, fmt.Errorf("expected an address component or \"(\" but found %s at position %d", tok.describe(), tok.pos+1)
+	}
+}
@@ -0,0 +1,131 @@
+package addrfilter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+func TestFilterMatch(t *testing.T) {
+	subject := Subject{
+		Addr:     `module.network.aws_iam_role.example["a"]`,
+		Module:   "module.network",
+		Mode:     "managed",
+		Type:     "aws_iam_role",
+		Name:     "example",
+		Key:      "a",
+		Provider: "registry.terraform.io/hashicorp/aws",
+	}
+
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`type == "aws_iam_role"`, true},
+		{`type == "aws_instance"`, false},
+		{`type != "aws_instance"`, true},
+		{`type =~ "^aws_"`, true},
+		{`type !~ "^aws_"`, false},
+		{`module =~ "^module\.network"`, true},
+		{`module == ""`, false},
+		{`mode == "managed"`, true},
+		{`mode == "data"`, false},
+		{`name == "example" && key == "a"`, true},
+		{`name == "example" && key == "b"`, false},
+		{`key == "b" || key == "a"`, true},
+		{`!(type == "aws_instance")`, true},
+		{`provider =~ "hashicorp/aws"`, true},
+		{`provider == "registry.terraform.io/hashicorp/aws"`, true},
+		{`addr =~ "\[\"a\"\]$"`, true},
+		// && binds tighter than ||
+		{`type == "nope" && name == "nope" || mode == "managed"`, true},
+		{`mode == "managed" || type == "nope" && name == "nope"`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			f, err := Parse(test.src)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := f.Match(subject); got != test.want {
+				t.Errorf("wrong result %t for %s", got, test.src)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		wantErr string
+	}{
+		{``, `expected an address component or "("`},
+		{`type`, "expected a comparison operator"},
+		{`type ==`, "expected a quoted string"},
+		{`type == aws_iam_role`, "expected a quoted string"},
+		{`boop == "a"`, `unknown address component "boop"`},
+		{`type == "a" extra`, `unexpected "extra"`},
+		{`(type == "a"`, `expected ")"`},
+		{`type =~ "["`, "invalid regular expression"},
+		{`type == "unterminated`, "expected a quoted string"},
+		{`"a" == type`, "comparisons are written with the address component first"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			_, err := Parse(test.src)
+			if err == nil {
+				t.Fatalf("unexpected success; want error containing %q", test.wantErr)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("wrong error %q; want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSubject(t *testing.T) {
+	providerAws := addrs.NewDefaultProvider("aws")
+
+	instAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "aws_iam_role",
+		Name: "example",
+	}.Instance(addrs.StringKey("a")).Absolute(addrs.RootModuleInstance.Child("network", addrs.NoKey))
+
+	got := NewSubject(instAddr, providerAws)
+	want := Subject{
+		Addr:     `module.network.aws_iam_role.example["a"]`,
+		Module:   "module.network",
+		Mode:     "managed",
+		Type:     "aws_iam_role",
+		Name:     "example",
+		Key:      "a",
+		Provider: "registry.terraform.io/hashicorp/aws",
+	}
+	if got != want {
+		t.Errorf("wrong subject\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	dataAddr := addrs.Resource{
+		Mode: addrs.DataResourceMode,
+		Type: "aws_ami",
+		Name: "lookup",
+	}.Instance(addrs.IntKey(2)).Absolute(addrs.RootModuleInstance)
+
+	got = NewSubject(dataAddr, providerAws)
+	want = Subject{
+		Addr:     `data.aws_ami.lookup[2]`,
+		Module:   "",
+		Mode:     "data",
+		Type:     "aws_ami",
+		Name:     "lookup",
+		Key:      "2",
+		Provider: "registry.terraform.io/hashicorp/aws",
+	}
+	if got != want {
+		t.Errorf("wrong subject\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
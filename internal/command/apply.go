@@ -2,13 +2,17 @@ package command
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform/internal/backend"
 	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/views"
+	"github.com/hashicorp/terraform/internal/command/workdir"
 	"github.com/hashicorp/terraform/internal/plans/planfile"
 	"github.com/hashicorp/terraform/internal/tfdiags"
+	tfversion "github.com/hashicorp/terraform/version"
 )
 
 // ApplyCommand is a Command implementation that applies a Terraform
@@ -53,6 +57,17 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Applying (or destroying) is never allowed in a read-only workspace.
+	if c.Destroy {
+		diags = diags.Append(c.requireWritableWorkspace("destroy objects"))
+	} else {
+		diags = diags.Append(c.requireWritableWorkspace("apply changes"))
+	}
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
 	// Check for user-supplied plugin path
 	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
@@ -61,6 +76,16 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// If the user asked for signature verification then we check the plan
+	// file's detached signature before doing anything else with the file.
+	if args.VerifyKey != "" {
+		diags = diags.Append(verifyPlanFile(args.PlanPath, args.VerifyKey))
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
 	// Attempt to load the plan file, if specified
 	planFile, diags := c.LoadPlanFile(args.PlanPath)
 	if diags.HasErrors() {
@@ -68,12 +93,23 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// If the user asked for a strict environment check then the plan file
+	// must record the environment it was created in, and it must match the
+	// environment we're running in now.
+	if args.StrictEnvironment {
+		diags = diags.Append(c.checkPlanFileEnvironment(planFile))
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
 	// Check for invalid combination of plan file and variable overrides
-	if planFile != nil && !args.Vars.Empty() {
+	if planFile != nil && (!args.Vars.Empty() || args.Vars.VarProgram != "") {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Can't set variables when applying a saved plan",
-			"The -var and -var-file options cannot be used when applying a saved plan file, because a saved plan includes the variable values that were set when it was created.",
+			"The -var, -var-file, and -var-program options cannot be used when applying a saved plan file, because a saved plan includes the variable values that were set when it was created.",
 		))
 		view.Diagnostics(diags)
 		return 1
@@ -91,6 +127,9 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.refreshParallelism = args.Operation.RefreshParallelism
+	c.Meta.operationTimeouts = args.Operation.OperationTimeouts
+	c.Meta.noSchemaCache = args.Operation.NoSchemaCache
 
 	// Prepare the backend, passing the plan file if present, and the
 	// backend-specific arguments
@@ -102,8 +141,13 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	}
 
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(be, view, planFile, args.Operation, args.AutoApprove)
+	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, planFile, args.Operation, args.AutoApprove)
 	diags = diags.Append(opDiags)
+	opReq.CheckpointPath = args.CheckpointPath
+	opReq.ResumePath = args.ResumePath
+	opReq.PlanGateProgram = c.PlanGateProgram
+	opReq.AutoApproveMaxDestroy = args.AutoApproveMaxDestroy
+	opReq.ContinueOnError = args.ContinueOnError
 
 	// Collect variable value and add them to the operation request
 	diags = diags.Append(c.GatherVariables(opReq, args.Vars))
@@ -125,6 +169,37 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Record a condensed summary of this operation in the working
+	// directory's local history, for later review with "terraform history".
+	{
+		command := "apply"
+		if c.Destroy {
+			command = "destroy"
+		}
+		var extras []string
+		if args.PlanPath != "" {
+			extras = append(extras, "saved plan")
+		}
+		if args.AutoApprove {
+			extras = append(extras, "-auto-approve")
+		}
+		if args.AutoApproveMaxDestroy >= 0 {
+			extras = append(extras, fmt.Sprintf("-auto-approve-max-destroy=%d", args.AutoApproveMaxDestroy))
+		}
+		if args.Vars.VarProgram != "" {
+			// Record only the program's base name, since the full path might
+			// include details that shouldn't end up in the history.
+			extras = append(extras, "-var-program="+filepath.Base(args.Vars.VarProgram))
+		}
+		entry := &workdir.OperationHistoryEntry{
+			Command: command,
+			Options: operationOptionsSummary(args.Operation, extras...),
+			Result:  operationHistoryResult(op.Result == backend.OperationSuccess),
+		}
+		entry.Added, entry.Changed, entry.Removed = view.ResourceCounts()
+		c.recordOperationHistory(entry, diags)
+	}
+
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
@@ -191,6 +266,86 @@ func (c *ApplyCommand) LoadPlanFile(path string) (*planfile.Reader, tfdiags.Diag
 	return planFile, diags
 }
 
+// checkPlanFileEnvironment implements the -strict-environment option by
+// comparing the run environment recorded in the given plan file against the
+// current environment, returning an error describing any differences.
+func (c *ApplyCommand) checkPlanFileEnvironment(planFile *planfile.Reader) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	env, err := planFile.ReadEnvironment()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Couldn't read the run environment record from the plan file: %s.", err),
+		))
+		return diags
+	}
+	if env == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No run environment recorded",
+			"The -strict-environment option requires a plan file that records the environment it was created in, but the given plan file doesn't, because it was created by an earlier version of Terraform.",
+		))
+		return diags
+	}
+
+	var problems []string
+
+	if got, want := tfversion.String(), env.TerraformVersion; got != want {
+		problems = append(problems, fmt.Sprintf("the plan was created by Terraform %s, but this is Terraform %s", want, got))
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("error selecting workspace: %s", err))
+		return diags
+	}
+	if workspace != env.Workspace {
+		problems = append(problems, fmt.Sprintf("the plan was created in workspace %q, but the current workspace is %q", env.Workspace, workspace))
+	}
+
+	locks, moreDiags := c.lockedDependencies()
+	if moreDiags.HasErrors() {
+		return diags.Append(moreDiags)
+	}
+	currentProviders := make(map[string]string)
+	for addr, lock := range locks.AllProviders() {
+		currentProviders[addr.String()] = lock.Version().String()
+	}
+	recorded := make([]string, 0, len(env.Providers))
+	for addr := range env.Providers {
+		recorded = append(recorded, addr)
+	}
+	sort.Strings(recorded)
+	for _, addr := range recorded {
+		want := env.Providers[addr].Version
+		got, ok := currentProviders[addr]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("the plan was created with provider %s v%s, which is no longer in the dependency lock file", addr, want))
+		case got != want:
+			problems = append(problems, fmt.Sprintf("the plan was created with provider %s v%s, but v%s is now selected", addr, want, got))
+		}
+	}
+
+	if len(problems) > 0 {
+		var detail strings.Builder
+		detail.WriteString("The environment this plan was created in differs from the current environment:\n")
+		for _, problem := range problems {
+			fmt.Fprintf(&detail, "  - %s\n", problem)
+		}
+		detail.WriteString("\nCreate a new plan in the current environment, or apply without the -strict-environment option if the differences are acceptable.")
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan environment mismatch",
+			detail.String(),
+		))
+	}
+
+	return diags
+}
+
 func (c *ApplyCommand) PrepareBackend(planFile *planfile.Reader, args *arguments.State) (backend.Enhanced, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -245,6 +400,7 @@ func (c *ApplyCommand) PrepareBackend(planFile *planfile.Reader, args *arguments
 func (c *ApplyCommand) OperationRequest(
 	be backend.Enhanced,
 	view views.Apply,
+	viewType arguments.ViewType,
 	planFile *planfile.Reader,
 	args *arguments.Operation,
 	autoApprove bool,
@@ -257,7 +413,7 @@ func (c *ApplyCommand) OperationRequest(
 	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
 
 	// Build the operation
-	opReq := c.Operation(be)
+	opReq := c.Operation(be, viewType)
 	opReq.AutoApprove = autoApprove
 	opReq.ConfigDir = "."
 	opReq.PlanMode = args.PlanMode
@@ -266,6 +422,7 @@ func (c *ApplyCommand) OperationRequest(
 	opReq.PlanRefresh = args.Refresh
 	opReq.Targets = args.Targets
 	opReq.ForceReplace = args.ForceReplace
+	opReq.ForceLocalExecution = args.ForceLocalExecution
 	opReq.Type = backend.OperationTypeApply
 	opReq.View = view.Operation()
 
@@ -296,6 +453,7 @@ func (c *ApplyCommand) GatherVariables(opReq *backend.Operation, args *arguments
 		items[i].Value = varArgs[i].Value
 	}
 	c.Meta.variableArgs = rawFlags{items: &items}
+	c.Meta.varProgram = args.VarProgram
 	opReq.Variables, diags = c.collectVariableValues()
 
 	return diags
@@ -334,27 +492,74 @@ Options:
 
   -auto-approve          Skip interactive approval of plan before applying.
 
+  -auto-approve-max-destroy=n
+                         Abort before making any changes, with exit status 2,
+                         if the plan would destroy or replace more than the
+                         given number of resource instances. Intended as a
+                         guardrail for unattended applies, so this option
+                         requires either a saved plan file or -auto-approve.
+
   -backup=path           Path to backup the existing state file before
                          modifying. Defaults to the "-state-out" path with
                          ".backup" extension. Set to "-" to disable backup.
 
+  -checkpoint=path       Record each completed resource action to the given
+                         journal file, so that an interrupted apply can later
+                         be resumed with -resume.
+
   -compact-warnings      If Terraform produces any warnings that are not
                          accompanied by errors, show them in a more compact
                          form that includes only the summary messages.
 
+  -continue-on-error     If a change fails, keep applying any planned changes
+                         that don't depend on it, then report which resource
+                         instances failed and which were never attempted.
+                         A partial apply exits with status 3 instead of 1.
+
+  -execution=local       For the cloud backend only, run this operation
+                         locally instead of in a remote run, while still using
+                         the remote system for state storage and locking. This
+                         is a fallback for when remote runs are unavailable,
+                         and must be permitted with allow_local_execution in
+                         the cloud block.
+
   -lock=false            Don't hold a state lock during the operation. This is
                          dangerous if others might concurrently run commands
                          against the same workspace.
 
   -lock-timeout=0s       Duration to retry a state lock.
 
+  -lock-wait             Keep waiting for the state lock until it is released,
+                         instead of failing on the first attempt. A
+                         -lock-timeout places an overall deadline on the wait.
+
   -input=true            Ask for input for variables if not directly set.
 
   -no-color              If specified, output won't contain any color.
 
+  -no-schema-cache       Fetch fresh schemas from the providers instead of
+                         reusing schemas cached on disk from an earlier run.
+
+  -operation-timeout=ADDRESS=DURATION
+                         Place a deadline on the provider calls that apply
+                         changes to the given resource instance. On timeout
+                         the call is abandoned, the object is recorded as
+                         tainted, and an error is reported. This option can
+                         be specified multiple times.
+
   -parallelism=n         Limit the number of parallel resource operations.
                          Defaults to 10.
 
+  -refresh-parallelism=n Limit the number of concurrent operations while
+                         refreshing existing objects during planning,
+                         separately from -parallelism. Defaults to the
+                         -parallelism value.
+
+  -resume=path           Skip any actions that the given checkpoint journal,
+                         written by an earlier interrupted apply run with
+                         -checkpoint, records as already completed and that
+                         the current state confirms took effect.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
@@ -362,6 +567,19 @@ Options:
                          "-state". This can be used to preserve the old
                          state.
 
+  -strict-environment    Refuse to apply the given saved plan file if the
+                         run environment recorded in it differs from the
+                         current environment: the Terraform version, the
+                         workspace name, or the provider versions selected
+                         in the dependency lock file.
+
+  -verify-key=source     Verify the detached signature written by
+                         "terraform plan -sign-key" before applying the given
+                         saved plan file, refusing to proceed if the plan was
+                         modified since it was signed. The key material is
+                         read from the given file, or from the stdout of a
+                         program named with a "cmd:" prefix.
+
   If you don't provide a saved plan file then this command will also accept
   all of the plan-customization options accepted by the terraform plan command.
   For more information on those options, run:
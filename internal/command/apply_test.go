@@ -3,6 +3,8 @@ package command
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -10,6 +12,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -24,14 +27,91 @@ import (
 	"github.com/hashicorp/terraform/internal/command/views"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/plans/planfile"
 	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/states/statemgr"
+	"github.com/hashicorp/terraform/internal/terminal"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 	tfversion "github.com/hashicorp/terraform/version"
 )
 
+func TestApply_planGate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test gate programs are shell scripts")
+	}
+
+	// run applies the "apply" fixture with the given gate program script
+	// and returns the exit code and output.
+	run := func(t *testing.T, script string) (int, *terminal.TestOutput, string, string) {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("apply"), td)
+		defer testChdir(t, td)()
+
+		program := filepath.Join(td, "plan-gate.sh")
+		if err := os.WriteFile(program, []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		statePath := testTempFile(t)
+
+		p := applyFixtureProvider()
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+				PlanGateProgram:  program,
+			},
+		}
+
+		code := c.Run([]string{"-state", statePath, "-auto-approve"})
+		return code, done(t), statePath, td
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		// The program receives the JSON plan on stdin; we echo it into a
+		// file so we can verify what the gate was shown.
+		code, output, statePath, td := run(t, "#!/bin/sh\ncat >plan-gate-input.json\nexit 0\n")
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+
+		state := testStateRead(t, statePath)
+		if state.Empty() {
+			t.Fatal("state is empty; the apply should have run")
+		}
+
+		input, err := os.ReadFile(filepath.Join(td, "plan-gate-input.json"))
+		if err != nil {
+			t.Fatalf("gate program did not receive the plan: %s", err)
+		}
+		if !strings.Contains(string(input), `"resource_changes"`) {
+			t.Errorf("gate program input does not look like a JSON plan:\n%s", input)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		code, output, statePath, _ := run(t, "#!/bin/sh\necho 'too many deletions' >&2\nexit 1\n")
+		if code == 0 {
+			t.Fatal("apply succeeded; want the gate program to block it")
+		}
+
+		stderr := output.Stderr()
+		if !strings.Contains(stderr, "Apply rejected by plan gate program") {
+			t.Errorf("missing rejection error in output:\n%s", stderr)
+		}
+		if !strings.Contains(stderr, "too many deletions") {
+			t.Errorf("missing gate program message in output:\n%s", stderr)
+		}
+
+		if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+			t.Fatalf("state file exists; the apply should not have run (stat error: %v)", err)
+		}
+	})
+}
+
 func TestApply(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -445,6 +525,89 @@ func TestApply_defaultState(t *testing.T) {
 	}
 }
 
+func TestApply_continueOnError(t *testing.T) {
+	// This configuration has one resource that always fails to apply, one
+	// that depends on it, and one that is entirely independent of both.
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-error-continue"), td)
+	defer testChdir(t, td)()
+
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		s := req.PlannedState.AsValueMap()
+		if v, ok := s["error"]; ok && !v.IsNull() && v.True() {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("error"))
+			resp.NewState = cty.NullVal(req.PlannedState.Type())
+			return
+		}
+		s["id"] = cty.StringVal("foo")
+		resp.NewState = cty.ObjectVal(s)
+		return
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		s := req.ProposedNewState.AsValueMap()
+		s["id"] = cty.UnknownVal(cty.String)
+		resp.PlannedState = cty.ObjectVal(s)
+		return
+	}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":    {Type: cty.String, Optional: true, Computed: true},
+						"ami":   {Type: cty.String, Optional: true},
+						"error": {Type: cty.Bool, Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"-continue-on-error",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 3 {
+		t.Fatalf("wrong exit code %d; want 3\n%s", code, output.All())
+	}
+
+	// The consolidated summary must name the failed instance and the one
+	// that was never attempted because it depends on the failure.
+	all := output.All()
+	if !strings.Contains(all, "Apply incomplete") {
+		t.Errorf("missing partial apply summary:\n%s", all)
+	}
+	if !strings.Contains(all, "test_instance.fail") {
+		t.Errorf("summary doesn't name the failed instance:\n%s", all)
+	}
+	if !strings.Contains(all, "test_instance.downstream") {
+		t.Errorf("summary doesn't name the deferred instance:\n%s", all)
+	}
+
+	// The independent resource must still have been applied.
+	state := testStateRead(t, statePath)
+	if state == nil {
+		t.Fatal("state should not be nil")
+	}
+	if got := state.RootModule().Resources["test_instance.ok"]; got == nil {
+		t.Fatalf("test_instance.ok is missing from the state:\n%s", state)
+	}
+}
+
 func TestApply_error(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -667,6 +830,10 @@ func TestApply_plan(t *testing.T) {
 	planPath := applyFixturePlanFile(t)
 	statePath := testTempFile(t)
 
+	// run in a temporary directory so that anything the command writes to
+	// its working directory cannot end up in the source tree
+	testCwd(t)
+
 	p := applyFixtureProvider()
 	view, done := testView(t)
 	c := &ApplyCommand{
@@ -696,6 +863,91 @@ func TestApply_plan(t *testing.T) {
 	}
 }
 
+func TestApply_strictEnvironment(t *testing.T) {
+	t.Run("matching environment", func(t *testing.T) {
+		planPath := applyFixturePlanFile(t)
+		statePath := testTempFile(t)
+
+		testCwd(t)
+
+		p := applyFixtureProvider()
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-state-out", statePath,
+			"-strict-environment",
+			planPath,
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+	})
+
+	t.Run("workspace mismatch", func(t *testing.T) {
+		planPath := applyFixturePlanFile(t)
+		statePath := testTempFile(t)
+
+		testCwd(t)
+
+		// The plan file records the "default" workspace, so selecting a
+		// different one must make the strict check fail.
+		os.Setenv(WorkspaceNameEnvVar, "dev")
+		defer os.Unsetenv(WorkspaceNameEnvVar)
+
+		p := applyFixtureProvider()
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-state-out", statePath,
+			"-strict-environment",
+			planPath,
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if got, want := output.Stderr(), "Plan environment mismatch"; !strings.Contains(got, want) {
+			t.Errorf("missing expected error\ngot:\n%s\nwant substring: %s", got, want)
+		}
+		if got, want := output.Stderr(), `the current workspace is "dev"`; !strings.Contains(got, want) {
+			t.Errorf("missing workspace detail\ngot:\n%s\nwant substring: %s", got, want)
+		}
+	})
+
+	t.Run("without a plan file", func(t *testing.T) {
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				View: view,
+			},
+		}
+
+		code := c.Run([]string{"-strict-environment", "-auto-approve"})
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if got, want := output.Stderr(), "Invalid strict-environment option"; !strings.Contains(got, want) {
+			t.Errorf("missing expected error\ngot:\n%s\nwant substring: %s", got, want)
+		}
+	})
+}
+
 func TestApply_plan_backup(t *testing.T) {
 	statePath := testTempFile(t)
 	backupPath := testTempFile(t)
@@ -721,6 +973,10 @@ func TestApply_plan_backup(t *testing.T) {
 	// backed up
 	planPath := applyFixturePlanFileMatchState(t, fs.StateSnapshotMeta())
 
+	// run in a temporary directory so that anything the command writes to
+	// its working directory cannot end up in the source tree
+	testCwd(t)
+
 	args := []string{
 		"-state", statePath,
 		"-backup", backupPath,
@@ -740,6 +996,10 @@ func TestApply_plan_noBackup(t *testing.T) {
 	planPath := applyFixturePlanFile(t)
 	statePath := testTempFile(t)
 
+	// run in a temporary directory so that anything the command writes to
+	// its working directory cannot end up in the source tree
+	testCwd(t)
+
 	p := applyFixtureProvider()
 	view, done := testView(t)
 	c := &ApplyCommand{
@@ -794,18 +1054,28 @@ func TestApply_plan_remoteState(t *testing.T) {
 
 	_, snap := testModuleWithSnapshot(t, "apply")
 	backendConfig := cty.ObjectVal(map[string]cty.Value{
-		"address":                cty.StringVal(srv.URL),
-		"update_method":          cty.NullVal(cty.String),
-		"lock_address":           cty.NullVal(cty.String),
-		"unlock_address":         cty.NullVal(cty.String),
-		"lock_method":            cty.NullVal(cty.String),
-		"unlock_method":          cty.NullVal(cty.String),
-		"username":               cty.NullVal(cty.String),
-		"password":               cty.NullVal(cty.String),
-		"skip_cert_verification": cty.NullVal(cty.Bool),
-		"retry_max":              cty.NullVal(cty.String),
-		"retry_wait_min":         cty.NullVal(cty.String),
-		"retry_wait_max":         cty.NullVal(cty.String),
+		"address":                   cty.StringVal(srv.URL),
+		"update_method":             cty.NullVal(cty.String),
+		"lock_address":              cty.NullVal(cty.String),
+		"unlock_address":            cty.NullVal(cty.String),
+		"lock_method":               cty.NullVal(cty.String),
+		"unlock_method":             cty.NullVal(cty.String),
+		"renew_address":             cty.NullVal(cty.String),
+		"renew_method":              cty.NullVal(cty.String),
+		"renew_interval":            cty.NullVal(cty.String),
+		"username":                  cty.NullVal(cty.String),
+		"password":                  cty.NullVal(cty.String),
+		"skip_cert_verification":    cty.NullVal(cty.Bool),
+		"retry_max":                 cty.NullVal(cty.String),
+		"retry_wait_min":            cty.NullVal(cty.String),
+		"retry_wait_max":            cty.NullVal(cty.String),
+		"retry_jitter":              cty.NullVal(cty.Bool),
+		"oauth2_token_url":          cty.NullVal(cty.String),
+		"oauth2_client_id":          cty.NullVal(cty.String),
+		"oauth2_client_secret":      cty.NullVal(cty.String),
+		"client_ca_certificate_pem": cty.NullVal(cty.String),
+		"client_certificate_pem":    cty.NullVal(cty.String),
+		"client_private_key_pem":    cty.NullVal(cty.String),
 	})
 	backendConfigRaw, err := plans.NewDynamicValue(backendConfig, backendConfig.Type())
 	if err != nil {
@@ -1548,6 +1818,67 @@ func TestApply_varFileDefaultJSON(t *testing.T) {
 	}
 }
 
+func TestApply_varFileDefaultYAML(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply-vars"), td)
+	defer testChdir(t, td)()
+
+	varFilePath := filepath.Join(td, "terraform.tfvars.yaml")
+	if err := ioutil.WriteFile(varFilePath, []byte(applyVarFileYAML), 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	statePath := testTempFile(t)
+
+	p := testProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	actual := ""
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"value": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		return providers.ApplyResourceChangeResponse{
+			NewState: req.PlannedState,
+		}
+	}
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		actual = req.ProposedNewState.GetAttr("value").AsString()
+		return providers.PlanResourceChangeResponse{
+			PlannedState: req.ProposedNewState,
+		}
+	}
+
+	args := []string{
+		"-auto-approve",
+		"-state", statePath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if actual != "bar" {
+		t.Fatal("didn't work")
+	}
+}
+
 func TestApply_backup(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -2251,6 +2582,80 @@ func applyFixtureProvider() *terraform.MockProvider {
 // applyFixturePlanFile creates a plan file at a temporary location containing
 // a single change to create the test_instance.foo that is included in the
 // "apply" test fixture, returning the location of that plan file.
+func TestApply_verifyKey(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+
+	// run in a temporary directory so that anything the command writes to
+	// its working directory cannot end up in the source tree
+	testCwd(t)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "plan.pub")
+	if err := ioutil.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(pub)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Applying before the plan has been signed must fail.
+	{
+		p := applyFixtureProvider()
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-state-out", statePath,
+			"-verify-key", keyPath,
+			planPath,
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code == 0 {
+			t.Fatalf("succeeded; want error")
+		}
+		if got, want := output.Stderr(), "Plan signature verification failed"; !strings.Contains(got, want) {
+			t.Fatalf("missing error\ngot: %s\nwant substring: %s", got, want)
+		}
+	}
+
+	if err := planfile.Sign(planPath, priv); err != nil {
+		t.Fatalf("failed to sign plan: %s", err)
+	}
+
+	// With a valid signature the apply must proceed as usual.
+	{
+		p := applyFixtureProvider()
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-state-out", statePath,
+			"-verify-key", keyPath,
+			planPath,
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if _, err := os.Stat(statePath); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+}
+
 func applyFixturePlanFile(t *testing.T) string {
 	return applyFixturePlanFileMatchState(t, statemgr.SnapshotMeta{})
 }
@@ -2305,3 +2710,7 @@ foo = "bar"
 const applyVarFileJSON = `
 { "foo": "bar" }
 `
+
+const applyVarFileYAML = `
+foo: bar
+`
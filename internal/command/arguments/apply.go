@@ -17,6 +17,17 @@ type Apply struct {
 	// AutoApprove skips the manual verification step for the apply operation.
 	AutoApprove bool
 
+	// AutoApproveMaxDestroy, when zero or greater, aborts the apply before
+	// any changes are made if the plan would destroy or replace more than
+	// that many resource instances. The default of -1 means no limit.
+	AutoApproveMaxDestroy int
+
+	// ContinueOnError requests that a failed change doesn't stop the apply
+	// as a whole: Terraform keeps applying any planned changes that don't
+	// depend on the failure, then reports the failed and unattempted
+	// instances together and exits with a distinct status.
+	ContinueOnError bool
+
 	// InputEnabled is used to disable interactive input for unspecified
 	// variable and backend config values. Default is true.
 	InputEnabled bool
@@ -24,6 +35,27 @@ type Apply struct {
 	// PlanPath contains an optional path to a stored plan file
 	PlanPath string
 
+	// CheckpointPath is an optional path to a journal file where the apply
+	// operation records each completed resource action, so that an
+	// interrupted apply can later be resumed with ResumePath.
+	CheckpointPath string
+
+	// ResumePath is an optional path to a checkpoint journal written by an
+	// earlier interrupted apply. Actions recorded there are skipped after
+	// verifying that the current state reflects their completion.
+	ResumePath string
+
+	// StrictEnvironment requests that the apply refuse to run when the
+	// run environment recorded in the given plan file differs from the
+	// current environment. It requires PlanPath.
+	StrictEnvironment bool
+
+	// VerifyKey optionally names the key material used to verify the
+	// detached signature of the given plan file, either as the path of a
+	// file to read or, with a "cmd:" prefix, a program to run. It requires
+	// PlanPath.
+	VerifyKey string
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 }
@@ -41,7 +73,14 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 
 	cmdFlags := extendedFlagSet("apply", apply.State, apply.Operation, apply.Vars)
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
+	cmdFlags.IntVar(&apply.AutoApproveMaxDestroy, "auto-approve-max-destroy", -1, "auto-approve-max-destroy")
+	cmdFlags.BoolVar(&apply.ContinueOnError, "continue-on-error", false, "continue-on-error")
 	cmdFlags.BoolVar(&apply.InputEnabled, "input", true, "input")
+	cmdFlags.StringVar(&apply.CheckpointPath, "checkpoint", "", "checkpoint")
+	cmdFlags.StringVar(&apply.ResumePath, "resume", "", "resume")
+	cmdFlags.BoolVar(&apply.StrictEnvironment, "strict-environment", false, "strict-environment")
+	cmdFlags.StringVar(&apply.VerifyKey, "verify-key", "", "verify-key")
+	cmdFlags.Var((*flagStringSlice)(&apply.Operation.operationTimeoutsRaw), "operation-timeout", "operation-timeout")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -84,6 +123,33 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	// The destroy limit is meaningful only when there will be no interactive
+	// approval step, so we require either a plan file or auto-approve rather
+	// than silently enabling auto-approve, which would be dangerous.
+	if apply.AutoApproveMaxDestroy >= 0 && apply.PlanPath == "" && !apply.AutoApprove {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file or auto-approve required",
+			"The -auto-approve-max-destroy option is a guardrail for unattended applies, so it can be used only when applying a saved plan file or together with the -auto-approve option.",
+		))
+	}
+
+	if apply.StrictEnvironment && apply.PlanPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid strict-environment option",
+			"The -strict-environment option can be used only when applying a saved plan file, because only a saved plan file records the environment it was created in.",
+		))
+	}
+
+	if apply.VerifyKey != "" && apply.PlanPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid verify-key option",
+			"The -verify-key option can be used only when applying a saved plan file, because only a saved plan file carries a detached signature.",
+		))
+	}
+
 	diags = diags.Append(apply.Operation.Parse())
 
 	switch {
@@ -3,6 +3,7 @@ package arguments
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -18,12 +19,50 @@ func TestParseApply_basicValid(t *testing.T) {
 		"defaults": {
 			nil,
 			&Apply{
-				AutoApprove:  false,
-				InputEnabled: true,
-				PlanPath:     "",
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:           false,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          true,
+				PlanPath:              "",
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"refresh parallelism": {
+			[]string{"-refresh-parallelism=42"},
+			&Apply{
+				AutoApprove:           false,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          true,
+				PlanPath:              "",
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
+				Operation: &Operation{
+					PlanMode:           plans.NormalMode,
+					Parallelism:        10,
+					RefreshParallelism: 42,
+					Refresh:            true,
+				},
+			},
+		},
+		"checkpoint and resume": {
+			[]string{"-checkpoint=journal.ckpt", "-resume=journal.ckpt"},
+			&Apply{
+				AutoApprove:           false,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          true,
+				PlanPath:              "",
+				CheckpointPath:        "journal.ckpt",
+				ResumePath:            "journal.ckpt",
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -34,12 +73,13 @@ func TestParseApply_basicValid(t *testing.T) {
 		"auto-approve, disabled input, and plan path": {
 			[]string{"-auto-approve", "-input=false", "saved.tfplan"},
 			&Apply{
-				AutoApprove:  true,
-				InputEnabled: false,
-				PlanPath:     "saved.tfplan",
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:           true,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          false,
+				PlanPath:              "saved.tfplan",
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -50,12 +90,13 @@ func TestParseApply_basicValid(t *testing.T) {
 		"destroy mode": {
 			[]string{"-destroy"},
 			&Apply{
-				AutoApprove:  false,
-				InputEnabled: true,
-				PlanPath:     "",
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:           false,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          true,
+				PlanPath:              "",
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -66,12 +107,13 @@ func TestParseApply_basicValid(t *testing.T) {
 		"JSON view disables input": {
 			[]string{"-json", "-auto-approve"},
 			&Apply{
-				AutoApprove:  true,
-				InputEnabled: false,
-				PlanPath:     "",
-				ViewType:     ViewJSON,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:           true,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          false,
+				PlanPath:              "",
+				ViewType:              ViewJSON,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -136,6 +178,46 @@ func TestParseApply_json(t *testing.T) {
 	}
 }
 
+func TestParseApply_maxDestroy(t *testing.T) {
+	testCases := map[string]struct {
+		args        []string
+		wantSuccess bool
+	}{
+		"-auto-approve-max-destroy=5": {
+			[]string{"-auto-approve-max-destroy=5"},
+			false,
+		},
+		"-auto-approve-max-destroy=5 -auto-approve": {
+			[]string{"-auto-approve-max-destroy=5", "-auto-approve"},
+			true,
+		},
+		"-auto-approve-max-destroy=0 -auto-approve": {
+			[]string{"-auto-approve-max-destroy=0", "-auto-approve"},
+			true,
+		},
+		"-auto-approve-max-destroy=5 saved.tfplan": {
+			[]string{"-auto-approve-max-destroy=5", "saved.tfplan"},
+			true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParseApply(tc.args)
+
+			if tc.wantSuccess {
+				if len(diags) > 0 {
+					t.Errorf("unexpected diags: %v", diags)
+				}
+			} else {
+				if got, want := diags.Err().Error(), "Plan file or auto-approve required"; !strings.Contains(got, want) {
+					t.Errorf("wrong diags\n got: %s\nwant: %s", got, want)
+				}
+			}
+		})
+	}
+}
+
 func TestParseApply_invalid(t *testing.T) {
 	got, diags := ParseApply([]string{"-frob"})
 	if len(diags) == 0 {
@@ -270,6 +352,71 @@ func TestParseApply_replace(t *testing.T) {
 	}
 }
 
+func TestParseApply_operationTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    map[string]time.Duration
+		wantErr string
+	}{
+		"no timeouts by default": {
+			args: nil,
+			want: nil,
+		},
+		"one timeout": {
+			args: []string{"-operation-timeout=foo_bar.baz=5m"},
+			want: map[string]time.Duration{"foo_bar.baz": 5 * time.Minute},
+		},
+		"two timeouts": {
+			args: []string{"-operation-timeout=foo_bar.baz=5m", "-operation-timeout", "foo_bar.beep=30s"},
+			want: map[string]time.Duration{
+				"foo_bar.baz":  5 * time.Minute,
+				"foo_bar.beep": 30 * time.Second,
+			},
+		},
+		"missing duration": {
+			args:    []string{"-operation-timeout=foo_bar.baz"},
+			want:    nil,
+			wantErr: "expects an argument of the form ADDRESS=DURATION",
+		},
+		"invalid duration": {
+			args:    []string{"-operation-timeout=foo_bar.baz=later"},
+			want:    nil,
+			wantErr: "must be a positive duration",
+		},
+		"negative duration": {
+			args:    []string{"-operation-timeout=foo_bar.baz=-5m"},
+			want:    nil,
+			wantErr: "must be a positive duration",
+		},
+		"data resource address": {
+			args:    []string{"-operation-timeout=data.foo.bar=5m"},
+			want:    nil,
+			wantErr: "Only managed resources can be used",
+		},
+		"non-resource-instance address": {
+			args:    []string{"-operation-timeout=module.boop=5m"},
+			want:    nil,
+			wantErr: "A resource instance address is required here.",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseApply(tc.args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			}
+			if !cmp.Equal(got.Operation.OperationTimeouts, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.OperationTimeouts, tc.want))
+			}
+		})
+	}
+}
+
 func TestParseApply_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
@@ -329,11 +476,12 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 		"defaults": {
 			nil,
 			&Apply{
-				AutoApprove:  false,
-				InputEnabled: true,
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:           false,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          true,
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -344,11 +492,12 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 		"auto-approve and disabled input": {
 			[]string{"-auto-approve", "-input=false"},
 			&Apply{
-				AutoApprove:  true,
-				InputEnabled: false,
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:           true,
+				AutoApproveMaxDestroy: -1,
+				InputEnabled:          false,
+				ViewType:              ViewHuman,
+				State:                 &State{Lock: true},
+				Vars:                  &Vars{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
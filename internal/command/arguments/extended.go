@@ -3,6 +3,8 @@ package arguments
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -27,6 +29,11 @@ type State struct {
 	// The default is 0, meaning no limit.
 	LockTimeout time.Duration
 
+	// LockWait controls whether Terraform keeps waiting for a contended
+	// state lock instead of failing on the first attempt. LockTimeout, when
+	// also set, places an overall deadline on the wait.
+	LockWait bool
+
 	// StatePath specifies a non-default location for the state file. The
 	// default value is blank, which is interpeted as "terraform.tfstate".
 	StatePath string
@@ -55,14 +62,31 @@ type Operation struct {
 	// as it walks the dependency graph.
 	Parallelism int
 
+	// RefreshParallelism, if non-zero, is a separate limit for the graph
+	// walk that refreshes existing objects as part of planning, which is
+	// typically network-bound and so can often safely run at a higher
+	// concurrency than the walk that applies changes. Zero means to use
+	// the same limit as Parallelism.
+	RefreshParallelism int
+
 	// Refresh controls whether or not the operation should refresh existing
 	// state before proceeding. Default is true.
 	Refresh bool
 
+	// NoSchemaCache disables reuse of provider schemas cached on disk from
+	// earlier runs, forcing a fresh fetch from each provider.
+	NoSchemaCache bool
+
 	// Targets allow limiting an operation to a set of resource addresses and
 	// their dependencies.
 	Targets []addrs.Targetable
 
+	// OperationTimeouts associates resource instance addresses (in their
+	// string representation) with deadlines for the provider calls that
+	// apply their changes, so that a hung provider operation for a specific
+	// resource can be abandoned instead of stalling the whole run.
+	OperationTimeouts map[string]time.Duration
+
 	// ForceReplace addresses cause Terraform to force a particular set of
 	// resource instances to generate "replace" actions in any plan where they
 	// would normally have generated "no-op" or "update" actions.
@@ -75,13 +99,22 @@ type Operation struct {
 	// learn a use-case for broader matching.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// ForceLocalExecution is true when the user gave -execution=local,
+	// asking a backend that would normally run this operation remotely to
+	// run it locally instead. Backends that always run operations locally
+	// ignore it.
+	ForceLocalExecution bool
+
 	// These private fields are used only temporarily during decoding. Use
 	// method Parse to populate the exported fields from these, validating
 	// the raw values in the process.
-	targetsRaw      []string
-	forceReplaceRaw []string
-	destroyRaw      bool
-	refreshOnlyRaw  bool
+	executionRaw         string
+	targetsRaw           []string
+	targetFilesRaw       []string
+	forceReplaceRaw      []string
+	operationTimeoutsRaw []string
+	destroyRaw           bool
+	refreshOnlyRaw       bool
 }
 
 // Parse must be called on Operation after initial flag parse. This processes
@@ -92,12 +125,52 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 
 	o.Targets = nil
 
+	// rawTarget associates a raw target address with where it came from, so
+	// that errors in addresses read from a -target-file can name the file
+	// and line. Addresses given directly with -target have no source.
+	type rawTarget struct {
+		addr   string
+		source string
+	}
+	rawTargets := make([]rawTarget, 0, len(o.targetsRaw))
 	for _, tr := range o.targetsRaw {
-		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(tr), "", hcl.Pos{Line: 1, Column: 1})
+		rawTargets = append(rawTargets, rawTarget{addr: tr})
+	}
+	for _, path := range o.targetFilesRaw {
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read target file",
+				fmt.Sprintf("Cannot read the file given in -target-file: %s.", err),
+			))
+			continue
+		}
+		// One target address per line, with blank lines and #-prefixed
+		// comment lines ignored.
+		for i, line := range strings.Split(string(src), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			rawTargets = append(rawTargets, rawTarget{
+				addr:   line,
+				source: fmt.Sprintf("%s:%d", path, i+1),
+			})
+		}
+	}
+
+	for _, rt := range rawTargets {
+		summary := fmt.Sprintf("Invalid target %q", rt.addr)
+		if rt.source != "" {
+			summary = fmt.Sprintf("Invalid target %q in %s", rt.addr, rt.source)
+		}
+
+		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(rt.addr), "", hcl.Pos{Line: 1, Column: 1})
 		if syntaxDiags.HasErrors() {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
-				fmt.Sprintf("Invalid target %q", tr),
+				summary,
 				syntaxDiags[0].Detail,
 			))
 			continue
@@ -107,7 +180,7 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 		if targetDiags.HasErrors() {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
-				fmt.Sprintf("Invalid target %q", tr),
+				summary,
 				targetDiags[0].Description().Detail,
 			))
 			continue
@@ -149,6 +222,81 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 		o.ForceReplace = append(o.ForceReplace, addr)
 	}
 
+	o.OperationTimeouts = nil
+	for _, raw := range o.operationTimeoutsRaw {
+		// The duration never contains an equals sign, so splitting on the
+		// last one leaves any equals signs in the address (such as in a
+		// for_each string key) intact.
+		eq := strings.LastIndex(raw, "=")
+		if eq < 1 || eq == len(raw)-1 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid operation timeout %q", raw),
+				"The -operation-timeout option expects an argument of the form ADDRESS=DURATION, such as aws_instance.example=5m.",
+			))
+			continue
+		}
+		addrRaw, durRaw := raw[:eq], raw[eq+1:]
+
+		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(addrRaw), "", hcl.Pos{Line: 1, Column: 1})
+		if syntaxDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid operation timeout address %q", addrRaw),
+				syntaxDiags[0].Detail,
+			))
+			continue
+		}
+
+		addr, addrDiags := addrs.ParseAbsResourceInstance(traversal)
+		if addrDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid operation timeout address %q", addrRaw),
+				addrDiags[0].Description().Detail,
+			))
+			continue
+		}
+
+		if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid operation timeout address %q", addrRaw),
+				"Only managed resources can be used with the -operation-timeout=... option.",
+			))
+			continue
+		}
+
+		dur, err := time.ParseDuration(durRaw)
+		if err != nil || dur <= 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid operation timeout %q", raw),
+				fmt.Sprintf("The duration given for %s must be a positive duration such as 30s or 5m.", addr),
+			))
+			continue
+		}
+
+		if o.OperationTimeouts == nil {
+			o.OperationTimeouts = make(map[string]time.Duration)
+		}
+		o.OperationTimeouts[addr.String()] = dur
+	}
+
+	switch o.executionRaw {
+	case "", "remote":
+		// "remote" is the implied default, so there's no override to apply.
+		o.ForceLocalExecution = false
+	case "local":
+		o.ForceLocalExecution = true
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			fmt.Sprintf("Invalid execution mode %q", o.executionRaw),
+			`The -execution option accepts only "local" or "remote".`,
+		))
+	}
+
 	// If you add a new possible value for o.PlanMode here, consider also
 	// adding a specialized error message for it in ParseApplyDestroy.
 	switch {
@@ -184,6 +332,10 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 type Vars struct {
 	vars     *flagNameValueSlice
 	varFiles *flagNameValueSlice
+
+	// VarProgram is an optional external program to run to obtain variable
+	// values, merged at the lowest precedence above declared defaults.
+	VarProgram string
 }
 
 func (v *Vars) All() []FlagNameValue {
@@ -213,6 +365,7 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 	if state != nil {
 		f.BoolVar(&state.Lock, "lock", true, "lock")
 		f.DurationVar(&state.LockTimeout, "lock-timeout", 0, "lock-timeout")
+		f.BoolVar(&state.LockWait, "lock-wait", false, "lock-wait")
 		f.StringVar(&state.StatePath, "state", "", "state-path")
 		f.StringVar(&state.StateOutPath, "state-out", "", "state-path")
 		f.StringVar(&state.BackupPath, "backup", "", "backup-path")
@@ -220,10 +373,14 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 
 	if operation != nil {
 		f.IntVar(&operation.Parallelism, "parallelism", DefaultParallelism, "parallelism")
+		f.IntVar(&operation.RefreshParallelism, "refresh-parallelism", 0, "refresh-parallelism")
 		f.BoolVar(&operation.Refresh, "refresh", true, "refresh")
+		f.BoolVar(&operation.NoSchemaCache, "no-schema-cache", false, "no-schema-cache")
+		f.StringVar(&operation.executionRaw, "execution", "", "execution")
 		f.BoolVar(&operation.destroyRaw, "destroy", false, "destroy")
 		f.BoolVar(&operation.refreshOnlyRaw, "refresh-only", false, "refresh-only")
 		f.Var((*flagStringSlice)(&operation.targetsRaw), "target", "target")
+		f.Var((*flagStringSlice)(&operation.targetFilesRaw), "target-file", "target-file")
 		f.Var((*flagStringSlice)(&operation.forceReplaceRaw), "replace", "replace")
 	}
 
@@ -236,6 +393,7 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 		vars.varFiles = &varFilesFlags
 		f.Var(vars.vars, "var", "var")
 		f.Var(vars.varFiles, "var-file", "var-file")
+		f.StringVar(&vars.VarProgram, "var-program", "", "var-program")
 	}
 
 	return f
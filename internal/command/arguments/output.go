@@ -1,6 +1,8 @@
 package arguments
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
@@ -26,10 +28,11 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 	output := &Output{}
 
 	var jsonOutput, rawOutput bool
-	var statePath string
+	var statePath, format string
 	cmdFlags := defaultFlagSet("output")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
+	cmdFlags.StringVar(&format, "format", "", "format")
 	cmdFlags.StringVar(&statePath, "state", "", "path")
 
 	if err := cmdFlags.Parse(args); err != nil {
@@ -61,6 +64,39 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		rawOutput = false
 	}
 
+	if format != "" && (jsonOutput || rawOutput) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -format option is mutually-exclusive with the -raw and -json options.",
+		))
+
+		// Since the desired output format is unknowable, fall back to default
+		jsonOutput = false
+		rawOutput = false
+		format = ""
+	}
+
+	// The -json and -raw options are older equivalents of -format=json and
+	// -format=raw respectively, so we can treat them all uniformly below.
+	switch format {
+	case "":
+		// Use whatever the boolean options selected.
+	case "json":
+		jsonOutput = true
+	case "raw":
+		rawOutput = true
+	case "yaml", "shell":
+		// Handled in the view type switch below.
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			fmt.Sprintf("The format %q is not supported. The -format option must be one of \"json\", \"yaml\", \"raw\", or \"shell\".", format),
+		))
+		format = ""
+	}
+
 	output.StatePath = statePath
 
 	if len(args) > 0 {
@@ -80,6 +116,10 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		output.ViewType = ViewJSON
 	case rawOutput:
 		output.ViewType = ViewRaw
+	case format == "yaml":
+		output.ViewType = ViewYAML
+	case format == "shell":
+		output.ViewType = ViewShell
 	default:
 		output.ViewType = ViewHuman
 	}
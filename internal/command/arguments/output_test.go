@@ -45,6 +45,38 @@ func TestParseOutput_valid(t *testing.T) {
 				StatePath: "foobar.tfstate",
 			},
 		},
+		"format json": {
+			[]string{"-format=json"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewJSON,
+				StatePath: "",
+			},
+		},
+		"format yaml": {
+			[]string{"-format=yaml"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewYAML,
+				StatePath: "",
+			},
+		},
+		"format raw": {
+			[]string{"-format=raw", "foo"},
+			&Output{
+				Name:      "foo",
+				ViewType:  ViewRaw,
+				StatePath: "",
+			},
+		},
+		"format shell": {
+			[]string{"-format=shell"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewShell,
+				StatePath: "",
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -111,6 +143,36 @@ func TestParseOutput_invalid(t *testing.T) {
 				),
 			},
 		},
+		"format and json specified": {
+			[]string{"-format=yaml", "-json"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewHuman,
+				StatePath: "",
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -format option is mutually-exclusive with the -raw and -json options.",
+				),
+			},
+		},
+		"unsupported format": {
+			[]string{"-format=xml"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewHuman,
+				StatePath: "",
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					`The format "xml" is not supported. The -format option must be one of "json", "yaml", "raw", or "shell".`,
+				),
+			},
+		},
 		"too many arguments": {
 			[]string{"-raw", "-state=foo.tfstate", "bar", "baz"},
 			&Output{
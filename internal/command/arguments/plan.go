@@ -1,6 +1,8 @@
 package arguments
 
 import (
+	"github.com/hashicorp/terraform/internal/command/addrfilter"
+	"github.com/hashicorp/terraform/internal/plans"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
@@ -15,13 +17,28 @@ type Plan struct {
 	// changes, and success with no changes.
 	DetailedExitCode bool
 
+	// DriftOnly makes the plan consider only changes made outside of
+	// Terraform, by planning in refresh-only mode, and produces a distinct
+	// exit code when such changes were detected. This is for detecting
+	// drift from automation without proposing any configuration changes.
+	DriftOnly bool
+
 	// InputEnabled is used to disable interactive input for unspecified
 	// variable and backend config values. Default is true.
 	InputEnabled bool
 
+	// Filter is an optional filter expression that selects additional
+	// targets from the resource instances tracked in the state.
+	Filter *addrfilter.Filter
+
 	// OutPath contains an optional path to store the plan file
 	OutPath string
 
+	// SignKey optionally names the key material used to create a detached
+	// signature for the saved plan file, either as the path of a file to
+	// read or, with a "cmd:" prefix, a program to run. It requires OutPath.
+	SignKey string
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 }
@@ -39,8 +56,13 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 
 	cmdFlags := extendedFlagSet("plan", plan.State, plan.Operation, plan.Vars)
 	cmdFlags.BoolVar(&plan.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
+	cmdFlags.BoolVar(&plan.DriftOnly, "drift-only", false, "drift-only")
 	cmdFlags.BoolVar(&plan.InputEnabled, "input", true, "input")
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
+	cmdFlags.StringVar(&plan.SignKey, "sign-key", "", "sign-key")
+
+	var filterExpr string
+	cmdFlags.StringVar(&filterExpr, "filter", "", "filter")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -63,8 +85,51 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 		))
 	}
 
+	if filterExpr != "" {
+		filter, err := addrfilter.Parse(filterExpr)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid filter expression",
+				err.Error(),
+			))
+		} else {
+			plan.Filter = filter
+		}
+	}
+
+	if plan.SignKey != "" && plan.OutPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid sign-key option",
+			"The -sign-key option can be used only in conjunction with -out, because only a saved plan file can be signed.",
+		))
+	}
+
 	diags = diags.Append(plan.Operation.Parse())
 
+	if plan.DriftOnly {
+		switch {
+		case plan.Operation.PlanMode == plans.DestroyMode:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible plan options",
+				"The -drift-only and -destroy options are mutually-exclusive.",
+			))
+		case !plan.Operation.Refresh:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible drift-only options",
+				"It doesn't make sense to use -drift-only at the same time as -refresh=false, because drift can only be detected by refreshing.",
+			))
+		default:
+			// Detecting drift without proposing configuration changes is
+			// exactly what refresh-only planning does, so -drift-only is
+			// that plus the distinct exit code.
+			plan.Operation.PlanMode = plans.RefreshOnlyMode
+		}
+	}
+
 	// JSON view currently does not support input, so we disable it here
 	if json {
 		plan.InputEnabled = false
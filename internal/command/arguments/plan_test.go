@@ -1,6 +1,8 @@
 package arguments
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -155,6 +157,67 @@ func TestParsePlan_targets(t *testing.T) {
 	}
 }
 
+func TestParsePlan_targetFile(t *testing.T) {
+	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
+	boop, _ := addrs.ParseTargetStr("module.boop")
+	testCases := map[string]struct {
+		fileContent string
+		args        []string
+		want        []addrs.Targetable
+		wantErr     string
+	}{
+		"target file": {
+			fileContent: "foo_bar.baz\nmodule.boop\n",
+			want:        []addrs.Targetable{foobarbaz.Subject, boop.Subject},
+		},
+		"comments and blank lines": {
+			fileContent: "# comment\n\n  foo_bar.baz  \n",
+			want:        []addrs.Targetable{foobarbaz.Subject},
+		},
+		"combined with target flags": {
+			fileContent: "module.boop\n",
+			args:        []string{"-target=foo_bar.baz"},
+			want:        []addrs.Targetable{foobarbaz.Subject, boop.Subject},
+		},
+		"invalid address in file": {
+			fileContent: "data[0].foo\n",
+			want:        nil,
+			wantErr:     "A data source name is required",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "targets.txt")
+			if err := ioutil.WriteFile(path, []byte(tc.fileContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			args := append(tc.args, "-target-file="+path)
+			got, diags := ParsePlan(args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			}
+			if !cmp.Equal(got.Operation.Targets, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.Targets, tc.want))
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, diags := ParsePlan([]string{"-target-file=" + filepath.Join(t.TempDir(), "nonexist.txt")})
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := diags.Err().Error(), "Failed to read target file"; !strings.Contains(got, want) {
+			t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+		}
+	})
+}
+
 func TestParsePlan_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
@@ -10,7 +10,22 @@ type Show struct {
 	// unspecified, show will display the latest state snapshot.
 	Path string
 
-	// ViewType specifies which output format to use: human, JSON, or "raw".
+	// Last requests the saved summary of the most recent operation from
+	// the working directory's local operation history, instead of a state
+	// or plan.
+	Last bool
+
+	// Timings requests a per-resource timing summary computed from a JSON
+	// log file captured from a command run with the -json option, instead
+	// of a state or plan.
+	Timings bool
+
+	// Metadata requests the run environment record embedded in a saved
+	// plan file, instead of the plan itself.
+	Metadata bool
+
+	// ViewType specifies which output format to use: human, JSON, SARIF,
+	// HTML, or "raw".
 	ViewType ViewType
 }
 
@@ -23,9 +38,14 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 		Path: "",
 	}
 
-	var jsonOutput bool
+	var jsonOutput, sarifOutput, htmlOutput bool
 	cmdFlags := defaultFlagSet("show")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.BoolVar(&sarifOutput, "sarif", false, "sarif")
+	cmdFlags.BoolVar(&htmlOutput, "html", false, "html")
+	cmdFlags.BoolVar(&show.Last, "last", false, "show the most recent recorded operation")
+	cmdFlags.BoolVar(&show.Timings, "timings", false, "summarize resource timings from a JSON log file")
+	cmdFlags.BoolVar(&show.Metadata, "metadata", false, "show the run environment recorded in a plan file")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -48,7 +68,43 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 		show.Path = args[0]
 	}
 
+	formatCount := 0
+	for _, set := range []bool{jsonOutput, sarifOutput, htmlOutput} {
+		if set {
+			formatCount++
+		}
+	}
+	if formatCount > 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -json, -sarif, and -html options are mutually exclusive.",
+		))
+		sarifOutput = false
+		htmlOutput = false
+	}
+
+	if show.Timings && formatCount > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -timings option renders its own summary, so it cannot be combined with -json, -sarif, or -html.",
+		))
+	}
+
+	if show.Metadata && (show.Timings || formatCount > 0) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			"The -metadata option renders its own summary, so it cannot be combined with the other output options.",
+		))
+	}
+
 	switch {
+	case sarifOutput:
+		show.ViewType = ViewSARIF
+	case htmlOutput:
+		show.ViewType = ViewHTML
 	case jsonOutput:
 		show.ViewType = ViewJSON
 	default:
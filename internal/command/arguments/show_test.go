@@ -27,6 +27,20 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType: ViewJSON,
 			},
 		},
+		"sarif": {
+			[]string{"-sarif"},
+			&Show{
+				Path:     "",
+				ViewType: ViewSARIF,
+			},
+		},
+		"html": {
+			[]string{"-html"},
+			&Show{
+				Path:     "",
+				ViewType: ViewHTML,
+			},
+		},
 		"path": {
 			[]string{"-json", "foo"},
 			&Show{
@@ -34,6 +48,14 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType: ViewJSON,
 			},
 		},
+		"timings": {
+			[]string{"-timings", "apply.log"},
+			&Show{
+				Path:     "apply.log",
+				Timings:  true,
+				ViewType: ViewHuman,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -69,6 +91,49 @@ func TestParseShow_invalid(t *testing.T) {
 				),
 			},
 		},
+		"json and sarif": {
+			[]string{"-json", "-sarif"},
+			&Show{
+				Path:     "",
+				ViewType: ViewJSON,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -json, -sarif, and -html options are mutually exclusive.",
+				),
+			},
+		},
+		"json and html": {
+			[]string{"-json", "-html"},
+			&Show{
+				Path:     "",
+				ViewType: ViewJSON,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -json, -sarif, and -html options are mutually exclusive.",
+				),
+			},
+		},
+		"timings and json": {
+			[]string{"-timings", "-json"},
+			&Show{
+				Path:     "",
+				Timings:  true,
+				ViewType: ViewJSON,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -timings option renders its own summary, so it cannot be combined with -json, -sarif, or -html.",
+				),
+			},
+		},
 		"too many arguments": {
 			[]string{"-json", "bar", "baz"},
 			&Show{
@@ -10,6 +10,18 @@ import (
 // Test represents the command line arguments for the "terraform test" command.
 type Test struct {
 	Output TestOutput
+
+	// Parallel is the maximum number of test suites to run concurrently.
+	// The default of 1 runs the suites serially.
+	Parallel int
+
+	// Record indicates that the provider calls made while running the
+	// tests should be captured into replay fixture files.
+	Record bool
+
+	// Replay indicates that provider calls should be answered from
+	// previously recorded fixture files instead of real providers.
+	Replay bool
 }
 
 // TestOutput represents a subset of the arguments for "terraform test"
@@ -23,6 +35,13 @@ type TestOutput struct {
 	// is to configure them to look for a separate test result file on disk
 	// after running the tests.)
 	JUnitXMLFile string
+
+	// If not an empty string, JSONReportFile gives a filename where a JSON
+	// report of the test results should be written, in addition to the normal
+	// output printed to the standard output and error streams. The JSON
+	// format covers the same information as the JUnit XML output but in a
+	// schema that follows our own suite/component/assertion model directly.
+	JSONReportFile string
 }
 
 // ParseTest interprets a slice of raw command line arguments into a
@@ -39,6 +58,10 @@ func ParseTest(args []string) (Test, tfdiags.Diagnostics) {
 	f.SetOutput(ioutil.Discard)
 	f.Usage = func() {}
 	f.StringVar(&ret.Output.JUnitXMLFile, "junit-xml", "", "Write a JUnit XML file describing the results")
+	f.StringVar(&ret.Output.JSONReportFile, "json-report", "", "Write a JSON report file describing the results")
+	f.IntVar(&ret.Parallel, "parallel", 1, "Maximum number of test suites to run concurrently")
+	f.BoolVar(&ret.Record, "record", false, "Record provider calls into replay fixture files")
+	f.BoolVar(&ret.Replay, "replay", false, "Answer provider calls from recorded fixture files")
 
 	err := f.Parse(args)
 	if err != nil {
@@ -46,6 +69,24 @@ func ParseTest(args []string) (Test, tfdiags.Diagnostics) {
 		return ret, diags
 	}
 
+	if ret.Record && ret.Replay {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command-line options",
+			"The -record and -replay options are mutually-exclusive.",
+		))
+		return ret, diags
+	}
+
+	if ret.Parallel < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallelism value",
+			"The -parallel option must be given a value of at least 1.",
+		))
+		return ret, diags
+	}
+
 	// We'll now discard all of the arguments that the flag package handled,
 	// and focus only on the positional arguments for the rest of the function.
 	args = f.Args()
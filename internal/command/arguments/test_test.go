@@ -20,6 +20,7 @@ func TestParseTest(t *testing.T) {
 				Output: TestOutput{
 					JUnitXMLFile: "",
 				},
+				Parallel: 1,
 			},
 			``,
 		},
@@ -29,6 +30,7 @@ func TestParseTest(t *testing.T) {
 				Output: TestOutput{
 					JUnitXMLFile: "",
 				},
+				Parallel: 1,
 			},
 			`flag provided but not defined: -invalid`,
 		},
@@ -38,15 +40,81 @@ func TestParseTest(t *testing.T) {
 				Output: TestOutput{
 					JUnitXMLFile: "result.xml",
 				},
+				Parallel: 1,
 			},
 			``,
 		},
+		{
+			[]string{"-json-report=report.json"},
+			Test{
+				Output: TestOutput{
+					JSONReportFile: "report.json",
+				},
+				Parallel: 1,
+			},
+			``,
+		},
+		{
+			[]string{"-parallel=4"},
+			Test{
+				Output: TestOutput{
+					JUnitXMLFile: "",
+				},
+				Parallel: 4,
+			},
+			``,
+		},
+		{
+			[]string{"-parallel=0"},
+			Test{
+				Output: TestOutput{
+					JUnitXMLFile: "",
+				},
+				Parallel: 0,
+			},
+			`Invalid parallelism value`,
+		},
+		{
+			[]string{"-record"},
+			Test{
+				Output: TestOutput{
+					JUnitXMLFile: "",
+				},
+				Parallel: 1,
+				Record:   true,
+			},
+			``,
+		},
+		{
+			[]string{"-replay"},
+			Test{
+				Output: TestOutput{
+					JUnitXMLFile: "",
+				},
+				Parallel: 1,
+				Replay:   true,
+			},
+			``,
+		},
+		{
+			[]string{"-record", "-replay"},
+			Test{
+				Output: TestOutput{
+					JUnitXMLFile: "",
+				},
+				Parallel: 1,
+				Record:   true,
+				Replay:   true,
+			},
+			`Incompatible command-line options`,
+		},
 		{
 			[]string{"baz"},
 			Test{
 				Output: TestOutput{
 					JUnitXMLFile: "",
 				},
+				Parallel: 1,
 			},
 			`Invalid command arguments`,
 		},
@@ -10,6 +10,10 @@ const (
 	ViewHuman ViewType = 'H'
 	ViewJSON  ViewType = 'J'
 	ViewRaw   ViewType = 'R'
+	ViewSARIF ViewType = 'S'
+	ViewYAML  ViewType = 'Y'
+	ViewShell ViewType = 'E'
+	ViewHTML  ViewType = 'W'
 )
 
 func (vt ViewType) String() string {
@@ -22,6 +26,14 @@ func (vt ViewType) String() string {
 		return "json"
 	case ViewRaw:
 		return "raw"
+	case ViewSARIF:
+		return "sarif"
+	case ViewYAML:
+		return "yaml"
+	case ViewShell:
+		return "shell"
+	case ViewHTML:
+		return "html"
 	default:
 		return "unknown"
 	}
@@ -1,6 +1,8 @@
 package arguments
 
 import (
+	"strings"
+
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
@@ -12,6 +14,14 @@ type Validate struct {
 
 	// ViewType specifies which output format to use: human, JSON, or "raw".
 	ViewType ViewType
+
+	// NoSchemaCache disables reuse of provider schemas cached on disk from
+	// earlier runs, forcing a fresh fetch from each provider.
+	NoSchemaCache bool
+
+	// Rules is the set of opt-in validation rule names to run against the
+	// configuration, from the -rules option.
+	Rules []string
 }
 
 // ParseValidate processes CLI arguments, returning a Validate value and errors.
@@ -24,8 +34,11 @@ func ParseValidate(args []string) (*Validate, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput bool
+	var rawRules string
 	cmdFlags := defaultFlagSet("validate")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.BoolVar(&validate.NoSchemaCache, "no-schema-cache", false, "no-schema-cache")
+	cmdFlags.StringVar(&rawRules, "rules", "", "rules")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -48,6 +61,14 @@ func ParseValidate(args []string) (*Validate, tfdiags.Diagnostics) {
 		validate.Path = args[0]
 	}
 
+	if rawRules != "" {
+		for _, name := range strings.Split(rawRules, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				validate.Rules = append(validate.Rules, name)
+			}
+		}
+	}
+
 	switch {
 	case jsonOutput:
 		validate.ViewType = ViewJSON
@@ -34,6 +34,14 @@ func TestParseValidate_valid(t *testing.T) {
 				ViewType: ViewJSON,
 			},
 		},
+		"rules": {
+			[]string{"-rules=unused-variable, provider-version"},
+			&Validate{
+				Path:     ".",
+				ViewType: ViewHuman,
+				Rules:    []string{"unused-variable", "provider-version"},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -42,7 +50,7 @@ func TestParseValidate_valid(t *testing.T) {
 			if len(diags) > 0 {
 				t.Fatalf("unexpected diags: %v", diags)
 			}
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 		})
@@ -88,7 +96,7 @@ func TestParseValidate_invalid(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			got, gotDiags := ParseValidate(tc.args)
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 			if !reflect.DeepEqual(gotDiags, tc.wantDiags) {
@@ -10,6 +10,11 @@ type View struct {
 	// level of noise when multiple instances of the same warning are raised
 	// for a configuration.
 	CompactWarnings bool
+
+	// RawStringDiffs disables the structural rendering of string attribute
+	// values that parse as JSON or YAML documents in plan output, showing
+	// plain string diffs instead.
+	RawStringDiffs bool
 }
 
 // ParseView processes CLI arguments, returning a View value and a
@@ -27,6 +32,8 @@ func ParseView(args []string) (*View, []string) {
 			common.NoColor = true
 		case "-compact-warnings":
 			common.CompactWarnings = true
+		case "-raw-string-diffs":
+			common.RawStringDiffs = true
 		default:
 			// Unsupported argument: move left to the current position, and
 			// increment the index.
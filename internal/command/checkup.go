@@ -0,0 +1,228 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+
+	"github.com/hashicorp/terraform/internal/terraform"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	tfversion "github.com/hashicorp/terraform/version"
+)
+
+// CheckupCommand is a Command implementation that implements the
+// "terraform checkup" command, which reports how the behavior of the
+// current Terraform version might differ from an earlier version for the
+// current configuration, without touching any state or requiring any
+// credentials beyond what is needed to read provider schemas.
+type CheckupCommand struct {
+	Meta
+}
+
+func (c *CheckupCommand) Synopsis() string {
+	return "Report likely behavior changes before upgrading Terraform"
+}
+
+func (c *CheckupCommand) Run(args []string) int {
+	var optSince string
+	var jsonOutput bool
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("checkup")
+	cmdFlags.StringVar(&optSince, "since", "", "report only behavior changes since this earlier version")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	configPath, err := ModulePath(cmdFlags.Args())
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var since *version.Version
+	if optSince != "" {
+		since, err = version.NewVersion(optSince)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid -since version",
+				fmt.Sprintf("The string %q given in the -since option is not a valid Terraform version: %s.", optSince, err),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	rules, err := decodeCheckupRules()
+	if err != nil {
+		// Should never happen, because the embedded ruleset is fixed at
+		// build time, but we'll be robust about it anyway.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid embedded checkup ruleset",
+			fmt.Sprintf("The behavior change ruleset embedded in this build of Terraform is invalid: %s. This is a bug in Terraform.", err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Check for user-supplied plugin path, since reading provider schemas
+	// from the local cache is the only provider access we need.
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	var findings []*checkupFinding
+
+	// The configuration load and validation walk are diagnostics-only:
+	// they never read or write state, and only consult provider schemas.
+	config, configDiags := c.loadConfig(configPath)
+	diags = diags.Append(configDiags)
+
+	if config != nil && !configDiags.HasErrors() {
+		opts, err := c.contextOpts()
+		if err != nil {
+			diags = diags.Append(err)
+			c.showDiagnostics(diags)
+			return 1
+		}
+		tfCtx, ctxDiags := terraform.NewContext(opts)
+		diags = diags.Append(ctxDiags)
+		if ctxDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		diags = diags.Append(tfCtx.Validate(config))
+
+		for _, rule := range rules {
+			if !rule.appliesSince(since) {
+				continue
+			}
+			findings = append(findings, rule.findings(config)...)
+		}
+	}
+
+	// Any diagnostics from loading and validation also become findings:
+	// an error here means the configuration would fail outright under this
+	// version of Terraform, and a warning is one this version would print.
+	for _, diag := range diags {
+		findings = append(findings, checkupFindingForDiagnostic(diag))
+	}
+
+	sortCheckupFindings(findings)
+
+	if jsonOutput {
+		doc := checkupJSON{
+			FormatVersion:    checkupJSONFormatVersion,
+			TerraformVersion: tfversion.String(),
+			Since:            optSince,
+			Findings:         findings,
+		}
+		if doc.Findings == nil {
+			doc.Findings = []*checkupFinding{} // encode as empty array, not null
+		}
+		j, err := json.MarshalIndent(&doc, "", "  ")
+		if err != nil {
+			// Should never happen, since we fully control the input here
+			panic(fmt.Sprintf("failed to serialize checkup results as JSON: %s", err))
+		}
+		c.Ui.Output(string(j))
+	} else {
+		c.showCheckupFindings(findings, optSince)
+	}
+
+	for _, finding := range findings {
+		if finding.Category == checkupCategoryWouldError {
+			return 1
+		}
+	}
+	return 0
+}
+
+// showCheckupFindings renders the findings in human-readable form, grouped
+// by category.
+func (c *CheckupCommand) showCheckupFindings(findings []*checkupFinding, since string) {
+	if len(findings) == 0 {
+		if since != "" {
+			c.Ui.Output(fmt.Sprintf("No likely behavior changes found for this configuration since Terraform v%s.", since))
+		} else {
+			c.Ui.Output("No likely behavior changes found for this configuration.")
+		}
+		return
+	}
+
+	categories := []struct {
+		category string
+		heading  string
+	}{
+		{checkupCategoryWouldError, "Would error"},
+		{checkupCategoryNewWarning, "New warnings"},
+		{checkupCategoryNote, "Behavioral notes"},
+	}
+	for _, cat := range categories {
+		var matching []*checkupFinding
+		for _, finding := range findings {
+			if finding.Category == cat.category {
+				matching = append(matching, finding)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+		c.Ui.Output(fmt.Sprintf("\n%s:", cat.heading))
+		for _, finding := range matching {
+			line := fmt.Sprintf("  - %s", finding.Summary)
+			if finding.Source != "" {
+				line += fmt.Sprintf(" (at %s)", finding.Source)
+			}
+			c.Ui.Output(line)
+			if finding.Detail != "" {
+				c.Ui.Output(fmt.Sprintf("      %s", strings.ReplaceAll(finding.Detail, "\n", "\n      ")))
+			}
+		}
+	}
+	c.Ui.Output("")
+}
+
+func (c *CheckupCommand) Help() string {
+	return `
+Usage: terraform [global options] checkup [options]
+
+  Reports how the behavior of this version of Terraform is likely to differ
+  from an earlier version for the configuration in the current working
+  directory.
+
+  Run this command using the new Terraform binary against a working
+  directory that has not yet been upgraded. It loads and validates the
+  configuration in a diagnostics-only mode, and compares it against a
+  ruleset of known behavior changes embedded in the binary. Findings are
+  categorized as "would-error" (the configuration would fail under this
+  version), "new-warning" (this version would report a new warning), or
+  "behavioral-note" (planning behavior may differ without any diagnostics).
+
+  This command never reads or writes state and requires no credentials;
+  the only provider access is reading schemas from the local plugin cache,
+  so the working directory must be initialized.
+
+  The command exits with status 1 if any "would-error" findings are
+  reported, so it can act as a gate in CI.
+
+Options:
+
+  -since=VERSION  Report only behavior changes introduced after the given
+                  earlier Terraform version, such as -since=1.1. By default
+                  all known behavior changes are reported.
+
+  -json           Produce output in a machine-readable JSON format,
+                  suitable for use in scripts or CI. Always disables color.
+`
+}
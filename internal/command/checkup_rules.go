@@ -0,0 +1,290 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	version "github.com/hashicorp/go-version"
+
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	tfversion "github.com/hashicorp/terraform/version"
+)
+
+// checkupJSONFormatVersion records the current version of the
+// "terraform checkup -json" output format, so that consumers can detect
+// incompatible future changes.
+const checkupJSONFormatVersion = "1.0"
+
+// The categories a checkup finding can belong to.
+const (
+	checkupCategoryWouldError = "would-error"
+	checkupCategoryNewWarning = "new-warning"
+	checkupCategoryNote       = "behavioral-note"
+)
+
+// checkupJSON is the top-level document produced by "terraform checkup -json".
+type checkupJSON struct {
+	FormatVersion    string            `json:"format_version"`
+	TerraformVersion string            `json:"terraform_version"`
+	Since            string            `json:"since,omitempty"`
+	Findings         []*checkupFinding `json:"findings"`
+}
+
+// checkupFinding is a single result from a checkup run, either produced by
+// one of the embedded behavior change rules or derived from a diagnostic
+// raised while loading and validating the configuration.
+type checkupFinding struct {
+	// RuleID is the identifier of the ruleset entry that produced this
+	// finding, or empty for findings derived from diagnostics.
+	RuleID   string `json:"rule_id,omitempty"`
+	Category string `json:"category"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+
+	// Source describes where in the configuration the finding arose, in
+	// "filename:line,column" form, if that is known.
+	Source string `json:"source,omitempty"`
+}
+
+// checkupRulesJSON is the machine-readable ruleset embedded in the binary,
+// describing behavior changes between Terraform versions that can be
+// detected statically from a configuration. Each rule names one of the
+// check implementations in checkupRuleFindings, the version whose release
+// introduced the change, and the category its findings belong to.
+//
+// The "since" version of a rule is compared against the -since option, so
+// that users upgrading across several versions see only the changes that
+// are new to them.
+const checkupRulesJSON = `
+{
+  "format_version": "1.0",
+  "rules": [
+    {
+      "id": "CHK001",
+      "since": "0.12",
+      "category": "would-error",
+      "check": "core_version_constraint",
+      "summary": "Configuration rejects the current Terraform version",
+      "detail": "A required_version constraint in this configuration does not accept the version of Terraform running this checkup, so every command would fail with this version until the constraint is updated."
+    },
+    {
+      "id": "CHK002",
+      "since": "0.14",
+      "category": "new-warning",
+      "check": "implicit_provider_requirements",
+      "summary": "Provider used without a required_providers entry",
+      "detail": "This module uses a provider without declaring it in a required_providers block, so Terraform resolves it as a provider in the hashicorp namespace. Later versions are stricter about implied provider requirements, and providers outside that namespace must be declared explicitly."
+    },
+    {
+      "id": "CHK003",
+      "since": "0.13",
+      "category": "behavioral-note",
+      "check": "active_experiments",
+      "summary": "Module opts in to a language experiment",
+      "detail": "Experimental language features are subject to change or removal between minor releases based on feedback, so planning behavior for this module may differ from the previous version even without any configuration changes."
+    }
+  ]
+}
+`
+
+// checkupRule is one entry in the embedded behavior change ruleset.
+type checkupRule struct {
+	ID       string `json:"id"`
+	Since    string `json:"since"`
+	Category string `json:"category"`
+	Check    string `json:"check"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+}
+
+// checkupRuleFindings maps the "check" field of a ruleset entry to the
+// implementation that produces that rule's findings for a configuration.
+var checkupRuleFindings = map[string]func(rule *checkupRule, config *configs.Config) []*checkupFinding{
+	"core_version_constraint":        checkupCoreVersionConstraint,
+	"implicit_provider_requirements": checkupImplicitProviderRequirements,
+	"active_experiments":             checkupActiveExperiments,
+}
+
+// decodeCheckupRules parses the embedded ruleset, verifying that every rule
+// refers to a known check implementation and category.
+func decodeCheckupRules() ([]*checkupRule, error) {
+	var doc struct {
+		FormatVersion string         `json:"format_version"`
+		Rules         []*checkupRule `json:"rules"`
+	}
+	if err := json.Unmarshal([]byte(checkupRulesJSON), &doc); err != nil {
+		return nil, err
+	}
+	if doc.FormatVersion != "1.0" {
+		return nil, fmt.Errorf("unsupported ruleset format version %q", doc.FormatVersion)
+	}
+	for _, rule := range doc.Rules {
+		if _, ok := checkupRuleFindings[rule.Check]; !ok {
+			return nil, fmt.Errorf("rule %s refers to unknown check %q", rule.ID, rule.Check)
+		}
+		switch rule.Category {
+		case checkupCategoryWouldError, checkupCategoryNewWarning, checkupCategoryNote:
+			// ok
+		default:
+			return nil, fmt.Errorf("rule %s has unknown category %q", rule.ID, rule.Category)
+		}
+		if _, err := version.NewVersion(rule.Since); err != nil {
+			return nil, fmt.Errorf("rule %s has invalid since version %q: %s", rule.ID, rule.Since, err)
+		}
+	}
+	return doc.Rules, nil
+}
+
+// appliesSince returns true if this rule describes a behavior change
+// introduced after the given earlier version. A nil version means the user
+// didn't constrain the comparison, in which case all rules apply.
+func (r *checkupRule) appliesSince(since *version.Version) bool {
+	if since == nil {
+		return true
+	}
+	ruleSince, err := version.NewVersion(r.Since)
+	if err != nil {
+		// decodeCheckupRules validated this already, so this should never
+		// happen, but we'll fail open so a bad rule is visible.
+		return true
+	}
+	return ruleSince.GreaterThan(since)
+}
+
+// findings applies this rule to the given configuration.
+func (r *checkupRule) findings(config *configs.Config) []*checkupFinding {
+	return checkupRuleFindings[r.Check](r, config)
+}
+
+// newFinding constructs a finding for this rule, with the given additional
+// context appended to the rule's summary and the given source location.
+func (r *checkupRule) newFinding(context, source string) *checkupFinding {
+	summary := r.Summary
+	if context != "" {
+		summary = fmt.Sprintf("%s: %s", summary, context)
+	}
+	return &checkupFinding{
+		RuleID:   r.ID,
+		Category: r.Category,
+		Summary:  summary,
+		Detail:   r.Detail,
+		Source:   source,
+	}
+}
+
+func checkupCoreVersionConstraint(rule *checkupRule, config *configs.Config) []*checkupFinding {
+	var findings []*checkupFinding
+	config.DeepEach(func(c *configs.Config) {
+		for _, constraint := range c.Module.CoreVersionConstraints {
+			if constraint.Required.Check(tfversion.SemVer) {
+				continue
+			}
+			findings = append(findings, rule.newFinding(
+				fmt.Sprintf("required_version %q excludes v%s", constraint.Required.String(), tfversion.String()),
+				constraint.DeclRange.String(),
+			))
+		}
+	})
+	return findings
+}
+
+func checkupImplicitProviderRequirements(rule *checkupRule, config *configs.Config) []*checkupFinding {
+	var findings []*checkupFinding
+	config.DeepEach(func(c *configs.Config) {
+		seen := make(map[string]bool)
+		var localNames []string
+		addResource := func(r *configs.Resource) {
+			localName := r.ProviderConfigAddr().LocalName
+			if !seen[localName] {
+				seen[localName] = true
+				localNames = append(localNames, localName)
+			}
+		}
+		for _, r := range c.Module.ManagedResources {
+			addResource(r)
+		}
+		for _, r := range c.Module.DataResources {
+			addResource(r)
+		}
+		sort.Strings(localNames)
+		for _, localName := range localNames {
+			if _, declared := c.Module.ProviderRequirements.RequiredProviders[localName]; declared {
+				continue
+			}
+			findings = append(findings, rule.newFinding(
+				fmt.Sprintf("provider %q in %s", localName, checkupModuleDisplayAddr(c)),
+				"",
+			))
+		}
+	})
+	return findings
+}
+
+func checkupActiveExperiments(rule *checkupRule, config *configs.Config) []*checkupFinding {
+	var findings []*checkupFinding
+	config.DeepEach(func(c *configs.Config) {
+		var keywords []string
+		for exp := range c.Module.ActiveExperiments {
+			keywords = append(keywords, exp.Keyword())
+		}
+		sort.Strings(keywords)
+		for _, keyword := range keywords {
+			findings = append(findings, rule.newFinding(
+				fmt.Sprintf("%s in %s", keyword, checkupModuleDisplayAddr(c)),
+				"",
+			))
+		}
+	})
+	return findings
+}
+
+// checkupModuleDisplayAddr returns a user-friendly name for the module at
+// the given configuration node.
+func checkupModuleDisplayAddr(c *configs.Config) string {
+	if c.Path.IsRoot() {
+		return "the root module"
+	}
+	return c.Path.String()
+}
+
+// checkupFindingForDiagnostic converts a diagnostic raised while loading or
+// validating the configuration into a finding: an error means the
+// configuration would fail outright under this version of Terraform, and a
+// warning is one this version would newly report.
+func checkupFindingForDiagnostic(diag tfdiags.Diagnostic) *checkupFinding {
+	category := checkupCategoryNewWarning
+	if diag.Severity() == tfdiags.Error {
+		category = checkupCategoryWouldError
+	}
+	desc := diag.Description()
+	finding := &checkupFinding{
+		Category: category,
+		Summary:  desc.Summary,
+		Detail:   desc.Detail,
+	}
+	if source := diag.Source(); source.Subject != nil {
+		finding.Source = source.Subject.StartString()
+	}
+	return finding
+}
+
+// sortCheckupFindings sorts findings by decreasing severity of category,
+// then by rule and summary so that the output is stable.
+func sortCheckupFindings(findings []*checkupFinding) {
+	categoryOrder := map[string]int{
+		checkupCategoryWouldError: 0,
+		checkupCategoryNewWarning: 1,
+		checkupCategoryNote:       2,
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if a, b := categoryOrder[findings[i].Category], categoryOrder[findings[j].Category]; a != b {
+			return a < b
+		}
+		if findings[i].RuleID != findings[j].RuleID {
+			return findings[i].RuleID < findings[j].RuleID
+		}
+		return findings[i].Summary < findings[j].Summary
+	})
+}
@@ -0,0 +1,129 @@
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/terraform"
+)
+
+func checkupFixtureProvider() *terraform.MockProvider {
+	p := testProvider()
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"ami": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	return p
+}
+
+func runCheckup(t *testing.T, fixture string, extraArgs ...string) (checkupJSON, int, *cli.MockUi) {
+	t.Helper()
+
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(fixture), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &CheckupCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(checkupFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := append([]string{"-json"}, extraArgs...)
+	code := c.Run(args)
+
+	var doc checkupJSON
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to decode output as JSON: %s\noutput:\n%s\nstderr:\n%s", err, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+	return doc, code, ui
+}
+
+func TestCheckup_findings(t *testing.T) {
+	doc, code, _ := runCheckup(t, "checkup-findings")
+	if code != 1 {
+		t.Errorf("wrong exit code %d; want 1 because of would-error findings", code)
+	}
+	if got, want := doc.FormatVersion, "1.0"; got != want {
+		t.Errorf("wrong format version %q; want %q", got, want)
+	}
+
+	byRule := map[string]*checkupFinding{}
+	for _, finding := range doc.Findings {
+		if finding.RuleID != "" {
+			byRule[finding.RuleID] = finding
+		}
+	}
+
+	// The fixture's required_version excludes the current version.
+	if finding := byRule["CHK001"]; finding == nil {
+		t.Errorf("no CHK001 finding for the excluded required_version")
+	} else {
+		if got, want := finding.Category, checkupCategoryWouldError; got != want {
+			t.Errorf("wrong category %q for CHK001; want %q", got, want)
+		}
+		if finding.Source == "" {
+			t.Errorf("no source location recorded for CHK001")
+		}
+	}
+
+	// The fixture uses the test provider without a required_providers entry.
+	if finding := byRule["CHK002"]; finding == nil {
+		t.Errorf("no CHK002 finding for the implied provider requirement")
+	} else if got, want := finding.Category, checkupCategoryNewWarning; got != want {
+		t.Errorf("wrong category %q for CHK002; want %q", got, want)
+	}
+}
+
+func TestCheckup_since(t *testing.T) {
+	// All of the embedded rules describe changes from before v1.0, so
+	// comparing against v1.0 should produce no rule findings at all, but
+	// validation diagnostics are unaffected by -since.
+	doc, _, _ := runCheckup(t, "checkup-findings", "-since=1.0")
+	for _, finding := range doc.Findings {
+		if finding.RuleID != "" {
+			t.Errorf("unexpected rule finding %s with -since=1.0: %s", finding.RuleID, finding.Summary)
+		}
+	}
+}
+
+func TestCheckup_clean(t *testing.T) {
+	doc, code, ui := runCheckup(t, "checkup-clean")
+	if code != 0 {
+		t.Errorf("wrong exit code %d; want 0\nstderr:\n%s", code, ui.ErrorWriter.String())
+	}
+	if len(doc.Findings) != 0 {
+		t.Errorf("unexpected findings for a clean configuration: %#v", doc.Findings)
+	}
+}
+
+func TestCheckup_invalidSince(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("checkup-clean"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &CheckupCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(checkupFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+	if code := c.Run([]string{"-since=not-a-version"}); code != 1 {
+		t.Errorf("wrong exit code %d; want 1", code)
+	}
+}
@@ -12,8 +12,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/hcl"
 
@@ -22,6 +25,7 @@ import (
 )
 
 const pluginCacheDirEnvVar = "TF_PLUGIN_CACHE_DIR"
+const providerAuditLogEnvVar = "TF_PROVIDER_AUDIT_LOG"
 
 // Config is the structure of the configuration for the Terraform CLI.
 //
@@ -48,6 +52,71 @@ type Config struct {
 	// configuration, but we decode into a slice here so that we can handle
 	// that validation at validation time rather than initial decode time.
 	ProviderInstallation []*ProviderInstallation
+
+	// ModuleInstallation represents any module_installation blocks in the
+	// configuration. Only one of these is allowed across the whole
+	// configuration, but we decode into a slice here so that we can handle
+	// that validation at validation time rather than initial decode time.
+	ModuleInstallation []*ModuleInstallation
+
+	// ReadonlyWorkspaces is a set of glob patterns (in the syntax accepted
+	// by path.Match) for workspace names that should be treated as
+	// read-only: commands that would modify the workspace's state refuse
+	// to run against a matching workspace.
+	ReadonlyWorkspaces []string `hcl:"readonly_workspaces"`
+
+	// DisableOperationHistory disables the recording of the local history
+	// of recent operations in each working directory, for environments
+	// where even an operation summary is too sensitive to keep around.
+	DisableOperationHistory bool `hcl:"disable_operation_history"`
+
+	// RedactProviders is a set of glob patterns (in the syntax accepted
+	// by path.Match) for provider source addresses whose values should
+	// always be redacted in plan renderings, regardless of whether the
+	// provider schema marks them as sensitive.
+	RedactProviders []string `hcl:"redact_providers"`
+
+	// RedactAttributes is a set of dot-separated attribute path patterns,
+	// such as "aws_instance.user_data" or "*.password", selecting resource
+	// attributes whose values should always be redacted in plan renderings.
+	// The first label matches the resource type and the remaining labels
+	// match the attribute path; each label is a glob pattern in the syntax
+	// accepted by path.Match.
+	RedactAttributes []string `hcl:"redact_attributes"`
+
+	// PlanGateProgram is an optional external program that Terraform runs
+	// before applying a plan, sending it the plan as JSON on its stdin. If
+	// the program exits with a non-zero status then the apply is aborted
+	// and the program's output is reported to the user, so a gate program
+	// can enforce policies such as limiting how many resources a single
+	// apply may destroy.
+	PlanGateProgram string `hcl:"plan_gate_program"`
+
+	// PluginGRPCCompression enables gzip compression of the messages
+	// exchanged with plugin processes over their gRPC channels, which
+	// reduces the cost of transferring large provider schemas at the
+	// expense of some CPU time.
+	PluginGRPCCompression bool `hcl:"plugin_grpc_compression"`
+
+	// PluginGRPCMaxRecvSize and PluginGRPCMaxSendSize override the maximum
+	// size in bytes of the gRPC messages Terraform will receive from and
+	// send to plugin processes. Zero leaves the corresponding gRPC default
+	// in effect. Providers with very large schemas can exceed the default
+	// receive limit.
+	PluginGRPCMaxRecvSize int `hcl:"plugin_grpc_max_recv_size"`
+	PluginGRPCMaxSendSize int `hcl:"plugin_grpc_max_send_size"`
+
+	// ProviderInstallationAuditLog, if set, names a file to which the
+	// provider installer appends a JSON line describing each installation
+	// decision it makes: which package was installed, from where, and
+	// whether its checksums and signatures were verified.
+	ProviderInstallationAuditLog string `hcl:"provider_installation_audit_log"`
+
+	// TraceEndpoint, if set, enables trace export by giving the base URL
+	// of an OTLP/HTTP collector to which Terraform sends spans describing
+	// the phases of each command it runs. The TF_TRACE_ENDPOINT
+	// environment variable overrides this setting.
+	TraceEndpoint string `hcl:"trace_endpoint"`
 }
 
 // ConfigHost is the structure of the "host" nested block within the CLI
@@ -160,6 +229,12 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	diags = diags.Append(moreDiags)
 	result.ProviderInstallation = providerInstBlocks
 
+	// The module_installation block is handled in the same way, for the
+	// same reason.
+	moduleInstBlocks, moreDiags := decodeModuleInstallationFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.ModuleInstallation = moduleInstBlocks
+
 	// Replace all env vars
 	for k, v := range result.Providers {
 		result.Providers[k] = os.ExpandEnv(v)
@@ -172,6 +247,10 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 		result.PluginCacheDir = os.ExpandEnv(result.PluginCacheDir)
 	}
 
+	if result.ProviderInstallationAuditLog != "" {
+		result.ProviderInstallationAuditLog = os.ExpandEnv(result.ProviderInstallationAuditLog)
+	}
+
 	return result, diags
 }
 
@@ -219,6 +298,10 @@ func EnvConfig() *Config {
 		config.PluginCacheDir = envPluginCacheDir
 	}
 
+	if envAuditLog := os.Getenv(providerAuditLogEnvVar); envAuditLog != "" {
+		config.ProviderInstallationAuditLog = envAuditLog
+	}
+
 	return config
 }
 
@@ -273,6 +356,13 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		)
 	}
 
+	// Should have zero or one "module_installation" blocks
+	if len(c.ModuleInstallation) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one module_installation block may be specified"),
+		)
+	}
+
 	if c.PluginCacheDir != "" {
 		_, err := os.Stat(c.PluginCacheDir)
 		if err != nil {
@@ -282,6 +372,63 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		}
 	}
 
+	// Check that all readonly_workspaces patterns are valid glob patterns.
+	for _, pattern := range c.ReadonlyWorkspaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The readonly_workspaces pattern %q is not a valid pattern: %s", pattern, err),
+			)
+		}
+	}
+
+	// Check that all redaction patterns are valid glob patterns. The
+	// attribute patterns are dot-separated sequences of glob patterns, so
+	// we check each of their labels individually.
+	for _, pattern := range c.RedactProviders {
+		if _, err := path.Match(pattern, ""); err != nil {
+			diags = diags.Append(
+				fmt.Errorf("The redact_providers pattern %q is not a valid pattern: %s", pattern, err),
+			)
+		}
+	}
+	for _, pattern := range c.RedactAttributes {
+		labels := strings.Split(pattern, ".")
+		if len(labels) < 2 {
+			diags = diags.Append(
+				fmt.Errorf("The redact_attributes pattern %q must include at least a resource type and an attribute name, separated by a dot", pattern),
+			)
+			continue
+		}
+		for _, label := range labels {
+			if _, err := path.Match(label, ""); err != nil {
+				diags = diags.Append(
+					fmt.Errorf("The redact_attributes pattern %q is not a valid pattern: %s", pattern, err),
+				)
+				break
+			}
+		}
+	}
+
+	if c.PluginGRPCMaxRecvSize < 0 {
+		diags = diags.Append(
+			fmt.Errorf("The plugin_grpc_max_recv_size setting must not be negative"),
+		)
+	}
+	if c.PluginGRPCMaxSendSize < 0 {
+		diags = diags.Append(
+			fmt.Errorf("The plugin_grpc_max_send_size setting must not be negative"),
+		)
+	}
+
+	if c.TraceEndpoint != "" {
+		u, err := url.Parse(c.TraceEndpoint)
+		if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+			diags = diags.Append(
+				fmt.Errorf("The trace_endpoint setting must be an absolute http or https URL"),
+			)
+		}
+	}
+
 	return diags
 }
 
@@ -311,6 +458,7 @@ func (c *Config) Merge(c2 *Config) *Config {
 	}
 	result.DisableCheckpoint = c.DisableCheckpoint || c2.DisableCheckpoint
 	result.DisableCheckpointSignature = c.DisableCheckpointSignature || c2.DisableCheckpointSignature
+	result.DisableOperationHistory = c.DisableOperationHistory || c2.DisableOperationHistory
 
 	result.PluginCacheDir = c.PluginCacheDir
 	if result.PluginCacheDir == "" {
@@ -355,6 +503,51 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.ProviderInstallation = append(result.ProviderInstallation, c2.ProviderInstallation...)
 	}
 
+	if (len(c.ModuleInstallation) + len(c2.ModuleInstallation)) > 0 {
+		result.ModuleInstallation = append(result.ModuleInstallation, c.ModuleInstallation...)
+		result.ModuleInstallation = append(result.ModuleInstallation, c2.ModuleInstallation...)
+	}
+
+	if (len(c.ReadonlyWorkspaces) + len(c2.ReadonlyWorkspaces)) > 0 {
+		result.ReadonlyWorkspaces = append(result.ReadonlyWorkspaces, c.ReadonlyWorkspaces...)
+		result.ReadonlyWorkspaces = append(result.ReadonlyWorkspaces, c2.ReadonlyWorkspaces...)
+	}
+
+	if (len(c.RedactProviders) + len(c2.RedactProviders)) > 0 {
+		result.RedactProviders = append(result.RedactProviders, c.RedactProviders...)
+		result.RedactProviders = append(result.RedactProviders, c2.RedactProviders...)
+	}
+
+	if (len(c.RedactAttributes) + len(c2.RedactAttributes)) > 0 {
+		result.RedactAttributes = append(result.RedactAttributes, c.RedactAttributes...)
+		result.RedactAttributes = append(result.RedactAttributes, c2.RedactAttributes...)
+	}
+
+	result.PlanGateProgram = c.PlanGateProgram
+	if result.PlanGateProgram == "" {
+		result.PlanGateProgram = c2.PlanGateProgram
+	}
+
+	result.PluginGRPCCompression = c.PluginGRPCCompression || c2.PluginGRPCCompression
+	result.PluginGRPCMaxRecvSize = c.PluginGRPCMaxRecvSize
+	if result.PluginGRPCMaxRecvSize == 0 {
+		result.PluginGRPCMaxRecvSize = c2.PluginGRPCMaxRecvSize
+	}
+	result.PluginGRPCMaxSendSize = c.PluginGRPCMaxSendSize
+	if result.PluginGRPCMaxSendSize == 0 {
+		result.PluginGRPCMaxSendSize = c2.PluginGRPCMaxSendSize
+	}
+
+	result.ProviderInstallationAuditLog = c.ProviderInstallationAuditLog
+	if result.ProviderInstallationAuditLog == "" {
+		result.ProviderInstallationAuditLog = c2.ProviderInstallationAuditLog
+	}
+
+	result.TraceEndpoint = c.TraceEndpoint
+	if result.TraceEndpoint == "" {
+		result.TraceEndpoint = c2.TraceEndpoint
+	}
+
 	return &result
 }
 
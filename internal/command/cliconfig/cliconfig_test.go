@@ -193,12 +193,87 @@ func TestConfigValidate(t *testing.T) {
 			},
 			1, // no more than one provider_installation block allowed
 		},
+		"readonly_workspaces good": {
+			&Config{
+				ReadonlyWorkspaces: []string{"prod", "prod-*"},
+			},
+			0,
+		},
+		"readonly_workspaces bad pattern": {
+			&Config{
+				ReadonlyWorkspaces: []string{"prod-["},
+			},
+			1, // pattern is not a valid pattern
+		},
+		"redaction patterns good": {
+			&Config{
+				RedactProviders:  []string{"hashicorp/*", "example.com/*/*"},
+				RedactAttributes: []string{"aws_instance.user_data", "*.password"},
+			},
+			0,
+		},
+		"redact_providers bad pattern": {
+			&Config{
+				RedactProviders: []string{"hashicorp/["},
+			},
+			1, // pattern is not a valid pattern
+		},
+		"redact_attributes bad pattern": {
+			&Config{
+				RedactAttributes: []string{"aws_instance.["},
+			},
+			1, // pattern is not a valid pattern
+		},
+		"redact_attributes missing attribute name": {
+			&Config{
+				RedactAttributes: []string{"aws_instance"},
+			},
+			1, // pattern must include a resource type and an attribute name
+		},
+		"plugin_grpc sizes good": {
+			&Config{
+				PluginGRPCCompression: true,
+				PluginGRPCMaxRecvSize: 64 * 1024 * 1024,
+				PluginGRPCMaxSendSize: 64 * 1024 * 1024,
+			},
+			0,
+		},
+		"plugin_grpc_max_recv_size negative": {
+			&Config{
+				PluginGRPCMaxRecvSize: -1,
+			},
+			1, // must not be negative
+		},
+		"plugin_grpc_max_send_size negative": {
+			&Config{
+				PluginGRPCMaxSendSize: -1,
+			},
+			1, // must not be negative
+		},
 		"plugin_cache_dir does not exist": {
 			&Config{
 				PluginCacheDir: "fake",
 			},
 			1, // The specified plugin cache dir %s cannot be opened
 		},
+		"trace_endpoint good": {
+			&Config{
+				TraceEndpoint: "https://collector.example.com/",
+			},
+			0,
+		},
+		"trace_endpoint not absolute": {
+			&Config{
+				TraceEndpoint: "collector.example.com",
+			},
+			1, // must be an absolute http or https URL
+		},
+		"trace_endpoint wrong scheme": {
+			&Config{
+				TraceEndpoint: "ftp://collector.example.com/",
+			},
+			1, // must be an absolute http or https URL
+		},
 	}
 
 	for name, test := range tests {
@@ -0,0 +1,153 @@
+package cliconfig
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// ModuleInstallation is the structure of the "module_installation" nested
+// block within the CLI configuration, which customizes how Terraform
+// resolves registry module sources.
+type ModuleInstallation struct {
+	// FilesystemMirrorDir, when non-empty, is a local directory laid out by
+	// "terraform modules mirror" that Terraform should resolve registry
+	// module sources from instead of contacting the origin registries.
+	FilesystemMirrorDir string
+}
+
+// decodeModuleInstallationFromConfig uses the HCL AST API directly to
+// decode "module_installation" blocks from the given file, in the same
+// way as decodeProviderInstallationFromConfig handles its corresponding
+// block type.
+//
+// Note that this function wants the top-level file object which might or
+// might not contain module_installation blocks, not a module_installation
+// block directly itself.
+func decodeModuleInstallationFromConfig(hclFile *hclast.File) ([]*ModuleInstallation, tfdiags.Diagnostics) {
+	var ret []*ModuleInstallation
+	var diags tfdiags.Diagnostics
+
+	root := hclFile.Node.(*hclast.ObjectList)
+
+	for _, block := range root.Items {
+		if block.Keys[0].Token.Value() != "module_installation" {
+			continue
+		}
+		// HCL only tracks whether the input was JSON or native syntax inside
+		// individual tokens, so we'll use our block type token to decide
+		// and assume that the rest of the block must be written in the same
+		// syntax, because syntax is a whole-file idea.
+		isJSON := block.Keys[0].Token.JSON
+		if block.Assign.Line != 0 && !isJSON {
+			// Seems to be an attribute rather than a block
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module_installation block",
+				fmt.Sprintf("The module_installation block at %s must not be introduced with an equals sign.", block.Pos()),
+			))
+			continue
+		}
+		if len(block.Keys) > 1 && !isJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module_installation block",
+				fmt.Sprintf("The module_installation block at %s must not have any labels.", block.Pos()),
+			))
+		}
+
+		mi := &ModuleInstallation{}
+
+		body, ok := block.Val.(*hclast.ObjectType)
+		if !ok {
+			// We can't get in here with native HCL syntax because we
+			// already checked above that we're using block syntax, but
+			// if we're reading JSON then our value could potentially be
+			// anything.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module_installation block",
+				fmt.Sprintf("The module_installation block at %s must not be introduced with an equals sign.", block.Pos()),
+			))
+			continue
+		}
+
+		for _, methodBlock := range body.List.Items {
+			if methodBlock.Assign.Line != 0 && !isJSON {
+				// Seems to be an attribute rather than a block
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid module_installation method block",
+					fmt.Sprintf("The items inside the module_installation block at %s must all be blocks.", block.Pos()),
+				))
+				continue
+			}
+			if len(methodBlock.Keys) > 1 && !isJSON {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid module_installation method block",
+					fmt.Sprintf("The blocks inside the module_installation block at %s may not have any labels.", block.Pos()),
+				))
+			}
+
+			methodBody, ok := methodBlock.Val.(*hclast.ObjectType)
+			if !ok {
+				// As above, only reachable for JSON input.
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid module_installation method block",
+					fmt.Sprintf("The items inside the module_installation block at %s must all be blocks.", block.Pos()),
+				))
+				continue
+			}
+
+			methodTypeStr := methodBlock.Keys[0].Token.Value().(string)
+			switch methodTypeStr {
+			case "filesystem_mirror":
+				type BodyContent struct {
+					Path string `hcl:"path"`
+				}
+				var bodyContent BodyContent
+				err := hcl.DecodeObject(&bodyContent, methodBody)
+				if err != nil {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Invalid module_installation method block",
+						fmt.Sprintf("Invalid %s block at %s: %s.", methodTypeStr, block.Pos(), err),
+					))
+					continue
+				}
+				if bodyContent.Path == "" {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Invalid module_installation method block",
+						fmt.Sprintf("Invalid %s block at %s: \"path\" argument is required.", methodTypeStr, block.Pos()),
+					))
+					continue
+				}
+				if mi.FilesystemMirrorDir != "" {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Invalid module_installation method block",
+						fmt.Sprintf("The module_installation block at %s may include only one filesystem_mirror block.", block.Pos()),
+					))
+					continue
+				}
+				mi.FilesystemMirrorDir = bodyContent.Path
+			default:
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid module_installation method block",
+					fmt.Sprintf("Unknown module installation method %q at %s.", methodTypeStr, methodBlock.Pos()),
+				))
+				continue
+			}
+		}
+
+		ret = append(ret, mi)
+	}
+
+	return ret, diags
+}
@@ -0,0 +1,27 @@
+package cliconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig_moduleInstallation(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "module-installation"))
+	if diags.HasErrors() {
+		t.Errorf("unexpected diagnostics: %s", diags.Err().Error())
+	}
+
+	want := &Config{
+		ModuleInstallation: []*ModuleInstallation{
+			{
+				FilesystemMirrorDir: "/tmp/module-mirror",
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
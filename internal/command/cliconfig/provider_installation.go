@@ -136,11 +136,16 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 			methodTypeStr := methodBlock.Keys[0].Token.Value().(string)
 			var location ProviderInstallationLocation
 			var include, exclude []string
+			var retries, retryWait int
+			var fallbackOnError bool
 			switch methodTypeStr {
 			case "direct":
 				type BodyContent struct {
-					Include []string `hcl:"include"`
-					Exclude []string `hcl:"exclude"`
+					Include         []string `hcl:"include"`
+					Exclude         []string `hcl:"exclude"`
+					Retries         int      `hcl:"retries"`
+					RetryWait       int      `hcl:"retry_wait"`
+					FallbackOnError bool     `hcl:"fallback_on_error"`
 				}
 				var bodyContent BodyContent
 				err := hcl.DecodeObject(&bodyContent, methodBody)
@@ -155,6 +160,9 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 				location = ProviderInstallationDirect
 				include = bodyContent.Include
 				exclude = bodyContent.Exclude
+				retries = bodyContent.Retries
+				retryWait = bodyContent.RetryWait
+				fallbackOnError = bodyContent.FallbackOnError
 			case "filesystem_mirror":
 				type BodyContent struct {
 					Path    string   `hcl:"path"`
@@ -184,9 +192,12 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 				exclude = bodyContent.Exclude
 			case "network_mirror":
 				type BodyContent struct {
-					URL     string   `hcl:"url"`
-					Include []string `hcl:"include"`
-					Exclude []string `hcl:"exclude"`
+					URL             string   `hcl:"url"`
+					Include         []string `hcl:"include"`
+					Exclude         []string `hcl:"exclude"`
+					Retries         int      `hcl:"retries"`
+					RetryWait       int      `hcl:"retry_wait"`
+					FallbackOnError bool     `hcl:"fallback_on_error"`
 				}
 				var bodyContent BodyContent
 				err := hcl.DecodeObject(&bodyContent, methodBody)
@@ -209,6 +220,9 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 				location = ProviderInstallationNetworkMirror(bodyContent.URL)
 				include = bodyContent.Include
 				exclude = bodyContent.Exclude
+				retries = bodyContent.Retries
+				retryWait = bodyContent.RetryWait
+				fallbackOnError = bodyContent.FallbackOnError
 			case "dev_overrides":
 				if len(pi.Methods) > 0 {
 					// We require dev_overrides to appear first if it's present,
@@ -265,10 +279,22 @@ func decodeProviderInstallationFromConfig(hclFile *hclast.File) ([]*ProviderInst
 				continue
 			}
 
+			if retries < 0 || retryWait < 0 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid provider_installation method block",
+					fmt.Sprintf("Invalid %s block at %s: \"retries\" and \"retry_wait\" must not be negative.", methodTypeStr, block.Pos()),
+				))
+				continue
+			}
+
 			pi.Methods = append(pi.Methods, &ProviderInstallationMethod{
-				Location: location,
-				Include:  include,
-				Exclude:  exclude,
+				Location:        location,
+				Include:         include,
+				Exclude:         exclude,
+				Retries:         retries,
+				RetryWait:       retryWait,
+				FallbackOnError: fallbackOnError,
 			})
 		}
 
@@ -288,6 +314,21 @@ type ProviderInstallationMethod struct {
 	Location ProviderInstallationLocation
 	Include  []string `hcl:"include"`
 	Exclude  []string `hcl:"exclude"`
+
+	// Retries and RetryWait configure retrying of failed requests to this
+	// installation method: how many additional attempts to make after a
+	// failure, and how many seconds to wait before the first retry. The
+	// wait doubles before each subsequent attempt. These are accepted only
+	// for the "direct" and "network_mirror" methods, since those are the
+	// ones that make network requests.
+	Retries   int
+	RetryWait int
+
+	// FallbackOnError, if set, causes a failure of this installation method
+	// (after any retries) to be treated the same as the requested provider
+	// not being available from it, so that a method listed later in the
+	// provider_installation block can satisfy the request instead.
+	FallbackOnError bool
 }
 
 // ProviderInstallationLocation is an interface type representing the
@@ -26,9 +26,12 @@ func TestLoadConfig_providerInstallation(t *testing.T) {
 								Include:  []string{"example.com/*/*"},
 							},
 							{
-								Location: ProviderInstallationNetworkMirror("https://tf-Mirror.example.com/"),
-								Include:  []string{"registry.terraform.io/*/*"},
-								Exclude:  []string{"registry.Terraform.io/foobar/*"},
+								Location:        ProviderInstallationNetworkMirror("https://tf-Mirror.example.com/"),
+								Include:         []string{"registry.terraform.io/*/*"},
+								Exclude:         []string{"registry.Terraform.io/foobar/*"},
+								Retries:         2,
+								RetryWait:       5,
+								FallbackOnError: true,
 							},
 							{
 								Location: ProviderInstallationFilesystemMirror("/tmp/example2"),
@@ -7,6 +7,7 @@ package clistate
 import (
 	"context"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
@@ -17,7 +18,12 @@ import (
 )
 
 const (
-	LockThreshold    = 400 * time.Millisecond
+	LockThreshold = 400 * time.Millisecond
+
+	// LockWaitReportInterval is how often we remind the user that we are
+	// still waiting for a contended state lock.
+	LockWaitReportInterval = 10 * time.Second
+
 	LockErrorMessage = `Error message: %s
 
 Terraform acquires a state lock to protect the state from being written
@@ -65,12 +71,14 @@ type Locker interface {
 }
 
 type locker struct {
-	mu      sync.Mutex
-	ctx     context.Context
-	timeout time.Duration
-	state   statemgr.Locker
-	view    views.StateLocker
-	lockID  string
+	mu        sync.Mutex
+	ctx       context.Context
+	timeout   time.Duration
+	wait      bool
+	state     statemgr.Locker
+	view      views.StateLocker
+	lockID    string
+	renewStop chan struct{}
 }
 
 var _ Locker = (*locker)(nil)
@@ -80,9 +88,18 @@ var _ Locker = (*locker)(nil)
 // timeout is reached, or the context is canceled. Lock progress will be be
 // reported to the user through the provided UI.
 func NewLocker(timeout time.Duration, view views.StateLocker) Locker {
+	return NewLockerWithWait(timeout, false, view)
+}
+
+// NewLockerWithWait is like NewLocker, but when wait is set and no timeout is
+// given the returned Locker keeps retrying a contended lock indefinitely,
+// until its context is cancelled, rather than giving up after the first
+// attempt. A timeout still places an overall deadline on the wait.
+func NewLockerWithWait(timeout time.Duration, wait bool, view views.StateLocker) Locker {
 	return &locker{
 		ctx:     context.Background(),
 		timeout: timeout,
+		wait:    wait,
 		view:    view,
 	}
 }
@@ -96,6 +113,7 @@ func (l *locker) WithContext(ctx context.Context) Locker {
 	return &locker{
 		ctx:     ctx,
 		timeout: l.timeout,
+		wait:    l.wait,
 		view:    l.view,
 	}
 }
@@ -111,14 +129,51 @@ func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
 
 	l.state = s
 
-	ctx, cancel := context.WithTimeout(l.ctx, l.timeout)
-	defer cancel()
+	ctx := l.ctx
+	if l.timeout > 0 || !l.wait {
+		// Without an explicit request to wait we make only a single
+		// attempt, by giving LockWithProgress an already-expired deadline.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
 
 	lockInfo := statemgr.NewLockInfo()
 	lockInfo.Operation = reason
 
+	// While waiting on a contended lock we periodically report who is
+	// holding it and how long we have been waiting, so that a long wait
+	// (or a Lock implementation that blocks natively rather than
+	// returning a LockError) doesn't appear hung.
+	var heldMu sync.Mutex
+	var held *statemgr.LockInfo
+	progress := func(current *statemgr.LockInfo) {
+		heldMu.Lock()
+		held = current
+		heldMu.Unlock()
+	}
+
+	start := time.Now()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(LockWaitReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				heldMu.Lock()
+				current := held
+				heldMu.Unlock()
+				l.view.Waiting(current, time.Since(start))
+			}
+		}
+	}()
+
 	err := slowmessage.Do(LockThreshold, func() error {
-		id, err := statemgr.LockWithContext(ctx, s, lockInfo)
+		id, err := statemgr.LockWithProgress(ctx, s, lockInfo, progress)
 		l.lockID = id
 		return err
 	}, l.view.Locking)
@@ -129,6 +184,34 @@ func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
 			"Error acquiring the state lock",
 			fmt.Sprintf(LockErrorMessage, err),
 		))
+		return diags
+	}
+
+	// If the state manager's lock is a lease that can expire, keep renewing
+	// it in the background for as long as we hold it, so that a multi-hour
+	// operation doesn't lose the lock partway through.
+	if renewer, ok := s.(statemgr.LockRenewer); ok && l.lockID != "" {
+		if interval := renewer.LockRenewInterval(); interval > 0 {
+			l.renewStop = make(chan struct{})
+			go func(id string, stop chan struct{}) {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-stop:
+						return
+					case <-ticker.C:
+						if err := renewer.RenewLock(id); err != nil {
+							// The operation itself carries on; the worst
+							// case is that the lock expires and a later
+							// state write fails, which the renewal cannot
+							// prevent anyway once it's failing.
+							log.Printf("[ERROR] failed to renew state lock %s: %s", id, err)
+						}
+					}
+				}
+			}(l.lockID, l.renewStop)
+		}
 	}
 
 	return diags
@@ -144,6 +227,11 @@ func (l *locker) Unlock() tfdiags.Diagnostics {
 		return diags
 	}
 
+	if l.renewStop != nil {
+		close(l.renewStop)
+		l.renewStop = nil
+	}
+
 	err := slowmessage.Do(LockThreshold, func() error {
 		return l.state.Unlock(l.lockID)
 	}, l.view.Unlocking)
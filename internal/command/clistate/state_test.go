@@ -1,7 +1,10 @@
 package clistate
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/views"
@@ -9,6 +12,109 @@ import (
 	"github.com/hashicorp/terraform/internal/terminal"
 )
 
+func TestLockWithWait(t *testing.T) {
+	streams, _ := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+
+	s := statemgr.NewFullFake(nil, statemgr.TestFullInitialState())
+
+	// hold the lock as if another process had taken it
+	holderInfo := statemgr.NewLockInfo()
+	holderInfo.Operation = "test holder"
+	id, err := s.Lock(holderInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without -lock-wait a contended lock fails on the first attempt
+	l := NewLocker(0, views.NewStateLocker(arguments.ViewHuman, view))
+	if diags := l.Lock(s, "test-lock"); !diags.HasErrors() {
+		t.Fatal("lock should have failed while held elsewhere")
+	}
+
+	// with -lock-wait we keep retrying until the holder releases the lock
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		s.Unlock(id)
+	}()
+
+	l = NewLockerWithWait(0, true, views.NewStateLocker(arguments.ViewHuman, view))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	l = l.WithContext(ctx)
+
+	if diags := l.Lock(s, "test-lock"); diags.HasErrors() {
+		t.Fatalf("lock should have succeeded once released: %s", diags.Err())
+	}
+	if diags := l.Unlock(); diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+}
+
+// renewableFake wraps a fake state manager with a counting implementation of
+// statemgr.LockRenewer, so we can observe the background renewal loop.
+type renewableFake struct {
+	statemgr.Full
+
+	mu       sync.Mutex
+	interval time.Duration
+	renewals int
+}
+
+func (s *renewableFake) LockRenewInterval() time.Duration {
+	return s.interval
+}
+
+func (s *renewableFake) RenewLock(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.renewals++
+	return nil
+}
+
+func (s *renewableFake) renewalCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renewals
+}
+
+func TestLockRenewal(t *testing.T) {
+	streams, _ := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+
+	s := &renewableFake{
+		Full:     statemgr.NewFullFake(nil, statemgr.TestFullInitialState()),
+		interval: 10 * time.Millisecond,
+	}
+
+	l := NewLocker(0, views.NewStateLocker(arguments.ViewHuman, view))
+	if diags := l.Lock(s, "test-lock"); diags.HasErrors() {
+		t.Fatalf("unexpected lock failure: %s", diags.Err())
+	}
+
+	// while the lock is held the lease must be renewed periodically
+	deadline := time.Now().Add(5 * time.Second)
+	for s.renewalCount() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for background lock renewals")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if diags := l.Unlock(); diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	// after unlocking the renewal loop must stop, though a final renewal
+	// that was already in flight when we unlocked may still land
+	time.Sleep(30 * time.Millisecond)
+	count := s.renewalCount()
+	time.Sleep(50 * time.Millisecond)
+	if got := s.renewalCount(); got != count {
+		t.Fatalf("lock renewed %d more times after unlock", got-count)
+	}
+}
+
 func TestUnlock(t *testing.T) {
 	streams, _ := terminal.StreamsForTesting(t)
 	view := views.NewView(streams)
@@ -174,6 +174,12 @@ func testPlan(t *testing.T) *plans.Plan {
 	backendConfig := cty.ObjectVal(map[string]cty.Value{
 		"path":          cty.NullVal(cty.String),
 		"workspace_dir": cty.NullVal(cty.String),
+		"encryption": cty.NullVal(cty.Object(map[string]cty.Type{
+			"passphrase":     cty.String,
+			"passphrase_env": cty.String,
+			"key_command":    cty.String,
+			"iterations":     cty.Number,
+		})),
 	})
 	backendConfigRaw, err := plans.NewDynamicValue(backendConfig, backendConfig.Type())
 	if err != nil {
@@ -219,6 +225,12 @@ func testPlanFileMatchState(t *testing.T, configSnap *configload.Snapshot, state
 		StateFile:            stateFile,
 		Plan:                 plan,
 		DependencyLocks:      depsfile.NewLocks(),
+		Environment: &planfile.Environment{
+			TerraformVersion: version.String(),
+			Workspace:        "default",
+			Providers:        map[string]*planfile.EnvironmentProvider{},
+			Variables:        map[string]*planfile.EnvironmentVariable{},
+		},
 	})
 	if err != nil {
 		t.Fatalf("failed to create temporary plan file: %s", err)
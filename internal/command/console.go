@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/repl"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
@@ -23,8 +24,14 @@ type ConsoleCommand struct {
 
 func (c *ConsoleCommand) Run(args []string) int {
 	args = c.Meta.process(args)
+	var evalExprs FlagStringSlice
+	var jsonOutput bool
+	var planPath string
 	cmdFlags := c.Meta.extendedFlagSet("console")
 	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.Var(&evalExprs, "eval", "expression to evaluate")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.StringVar(&planPath, "plan", "", "path to a saved plan file to evaluate against")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
@@ -75,7 +82,7 @@ func (c *ConsoleCommand) Run(args []string) int {
 	c.ignoreRemoteVersionConflict(b)
 
 	// Build the operation
-	opReq := c.Operation(b)
+	opReq := c.Operation(b, arguments.ViewHuman)
 	opReq.ConfigDir = configPath
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	opReq.AllowUnsetVariables = true // we'll just evaluate them as unknown
@@ -85,6 +92,19 @@ func (c *ConsoleCommand) Run(args []string) int {
 		return 1
 	}
 
+	if planPath != "" {
+		// Evaluate against the configuration snapshot, prior state, and
+		// variable values recorded in the given saved plan, instead of
+		// the current working directory contents.
+		planFile, err := c.PlanFile(planPath)
+		if err != nil {
+			diags = diags.Append(err)
+			c.showDiagnostics(diags)
+			return 1
+		}
+		opReq.PlanFile = planFile
+	}
+
 	{
 		var moreDiags tfdiags.Diagnostics
 		opReq.Variables, moreDiags = c.collectVariableValues()
@@ -154,19 +174,85 @@ func (c *ConsoleCommand) Run(args []string) int {
 		Scope: scope,
 	}
 
+	// The ":vars" console command lists the named values declared in the
+	// root module, which the session can't discover from the scope itself.
+	if mod := lr.Config.Module; mod != nil {
+		for name := range mod.Variables {
+			session.NamedValues = append(session.NamedValues, "var."+name)
+		}
+		for name := range mod.Locals {
+			session.NamedValues = append(session.NamedValues, "local."+name)
+		}
+	}
+
+	// Expressions given with -eval take precedence over anything on stdin.
+	if len(evalExprs) > 0 {
+		return c.modeEval(session, ui, evalExprs, jsonOutput)
+	}
+
 	// Determine if stdin is a pipe. If so, we evaluate directly.
 	if c.StdinPiped() {
-		return c.modePiped(session, ui)
+		return c.modePiped(session, ui, jsonOutput)
+	}
+
+	if jsonOutput {
+		c.Ui.Error("The -json option can be used only with -eval or with expressions on stdin.")
+		return 1
 	}
 
 	return c.modeInteractive(session, ui)
 }
 
-func (c *ConsoleCommand) modePiped(session *repl.Session, ui cli.Ui) int {
+// modeEval evaluates the expressions given with -eval options, in order,
+// printing one result per line.
+func (c *ConsoleCommand) modeEval(session *repl.Session, ui cli.Ui, exprs []string, jsonOutput bool) int {
+	for _, expr := range exprs {
+		var result string
+		var diags tfdiags.Diagnostics
+		if jsonOutput {
+			result, diags = session.HandleJSON(expr)
+		} else {
+			result, _, diags = session.Handle(expr)
+		}
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		ui.Output(result)
+	}
+	return 0
+}
+
+func (c *ConsoleCommand) modePiped(session *repl.Session, ui cli.Ui, jsonOutput bool) int {
 	var lastResult string
+	var lines []string
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
-		result, exit, diags := session.Handle(strings.TrimSpace(scanner.Text()))
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+		input := strings.Join(lines, "\n")
+		if repl.InputIncomplete(input) {
+			// Keep reading lines until the brackets balance.
+			continue
+		}
+		lines = nil
+
+		if jsonOutput {
+			// In JSON mode we print one result per expression so that a
+			// script feeding several expressions can correlate the results
+			// with its input lines.
+			if strings.TrimSpace(input) == "" {
+				continue
+			}
+			result, diags := session.HandleJSON(input)
+			if diags.HasErrors() {
+				c.showDiagnostics(diags)
+				return 1
+			}
+			ui.Output(result)
+			continue
+		}
+
+		result, exit, diags := session.Handle(input)
 		if diags.HasErrors() {
 			// In piped mode we'll exit immediately on error.
 			c.showDiagnostics(diags)
@@ -180,8 +266,20 @@ func (c *ConsoleCommand) modePiped(session *repl.Session, ui cli.Ui) int {
 		lastResult = result
 	}
 
-	// Output the final result
-	ui.Output(lastResult)
+	if len(lines) > 0 {
+		// The input ended part-way through a multi-line expression, so
+		// we'll evaluate what we have and let the parser report the problem.
+		_, _, diags := session.Handle(strings.Join(lines, "\n"))
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	if !jsonOutput {
+		// Output the final result
+		ui.Output(lastResult)
+	}
 
 	return 0
 }
@@ -200,8 +298,28 @@ Usage: terraform [global options] console [options]
 
   This command will never modify your state.
 
+  When one or more -eval options are given, or when expressions are piped
+  in on stdin, the console evaluates them non-interactively and prints one
+  result per expression, which is convenient for scripting.
+
 Options:
 
+  -eval='expr'      Evaluate the given expression and print its result,
+                    instead of starting the interactive console. This flag
+                    can be set multiple times to evaluate several
+                    expressions in order.
+
+  -json             Print each result as a JSON-encoded value on a single
+                    line, for consumption by scripts. Can be used only
+                    with -eval or with expressions on stdin. Sensitive
+                    value markings are discarded, as with
+                    "terraform output -json".
+
+  -plan=path        Evaluate expressions against the given saved plan file,
+                    using the configuration, prior state, and variable
+                    values recorded in it, instead of the current working
+                    directory contents.
+
   -state=path       Legacy option for the local backend only. See the local
                     backend's documentation for more information.
 
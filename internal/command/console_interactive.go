@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/terraform/internal/repl"
 
@@ -18,15 +20,19 @@ import (
 )
 
 func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
-	// Configure input
+	// Configure input. History is kept in the working directory's data
+	// directory so that it survives between console sessions; readline
+	// quietly skips persistence if the directory doesn't exist yet.
 	l, err := readline.NewEx(&readline.Config{
-		Prompt:            "> ",
-		InterruptPrompt:   "^C",
-		EOFPrompt:         "exit",
-		HistorySearchFold: true,
-		Stdin:             os.Stdin,
-		Stdout:            os.Stdout,
-		Stderr:            os.Stderr,
+		Prompt:                 "> ",
+		InterruptPrompt:        "^C",
+		EOFPrompt:              "exit",
+		HistoryFile:            filepath.Join(c.WorkingDir.DataDir(), "console_history"),
+		DisableAutoSaveHistory: true,
+		HistorySearchFold:      true,
+		Stdin:                  os.Stdin,
+		Stdout:                 os.Stdout,
+		Stderr:                 os.Stderr,
 	})
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf(
@@ -36,20 +42,41 @@ func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
 	}
 	defer l.Close()
 
+	var lines []string
 	for {
 		// Read a line
 		line, err := l.Readline()
 		if err == readline.ErrInterrupt {
-			if len(line) == 0 {
+			if len(line) == 0 && len(lines) == 0 {
 				break
-			} else {
-				continue
 			}
+			// An interrupt part-way through an expression discards the
+			// pending input and starts over at the main prompt.
+			lines = nil
+			l.SetPrompt("> ")
+			continue
 		} else if err == io.EOF {
 			break
 		}
 
-		out, exit, diags := session.Handle(line)
+		lines = append(lines, line)
+		input := strings.Join(lines, "\n")
+		if repl.InputIncomplete(input) {
+			// There are unbalanced brackets, so read continuation lines
+			// until the expression is complete.
+			l.SetPrompt(". ")
+			continue
+		}
+		lines = nil
+		l.SetPrompt("> ")
+
+		if strings.TrimSpace(input) != "" {
+			// We save the whole expression as a single history entry so
+			// that recalling it doesn't replay it one fragment at a time.
+			l.SaveHistory(strings.ReplaceAll(input, "\n", " "))
+		}
+
+		out, exit, diags := session.Handle(input)
 		if diags.HasErrors() {
 			c.showDiagnostics(diags)
 		}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/terminal"
 	"github.com/mitchellh/cli"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -195,6 +196,237 @@ func TestConsole_variables(t *testing.T) {
 	}
 }
 
+func TestConsole_multiline(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("variables"), td)
+	defer testChdir(t, td)()
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	// An expression with unbalanced brackets continues across lines until
+	// the brackets balance, so this input is a single expression.
+	commands := map[string]string{
+		"[\nvar.foo,\nvar.snack,\n]\n": "[\n  \"bar\",\n  \"popcorn\",\n]\n",
+		"upper(\nvar.foo\n)\n":         "\"BAR\"\n",
+	}
+
+	args := []string{}
+
+	for cmd, val := range commands {
+		var output bytes.Buffer
+		defer testStdinPipe(t, strings.NewReader(cmd))()
+		outCloser := testStdoutCapture(t, &output)
+		code := c.Run(args)
+		outCloser()
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+		}
+
+		actual := output.String()
+		if output.String() != val {
+			t.Fatalf("bad: %q, expected %q", actual, val)
+		}
+	}
+}
+
+func TestConsole_commands(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("variables"), td)
+	defer testChdir(t, td)()
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	commands := map[string]string{
+		":type var.snack\n":     "string\n",
+		":type local.snack_bar": "tuple([\n    string,\n    string,\n])\n",
+		":vars\n":               "local.snack_bar\nvar.foo\nvar.secret_snack\nvar.snack\n",
+	}
+
+	args := []string{}
+
+	for cmd, val := range commands {
+		var output bytes.Buffer
+		defer testStdinPipe(t, strings.NewReader(cmd))()
+		outCloser := testStdoutCapture(t, &output)
+		code := c.Run(args)
+		outCloser()
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+		}
+
+		actual := output.String()
+		if output.String() != val {
+			t.Fatalf("bad: %q, expected %q", actual, val)
+		}
+	}
+}
+
+func TestConsole_eval(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("variables"), td)
+	defer testChdir(t, td)()
+
+	p := testProvider()
+
+	newCommand := func(ui *cli.MockUi) *ConsoleCommand {
+		view, _ := testView(t)
+		return &ConsoleCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				Ui:               ui,
+				View:             view,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		args []string
+		want string
+	}{
+		"single expression": {
+			args: []string{"-eval=var.snack"},
+			want: "\"popcorn\"\n",
+		},
+		"multiple expressions": {
+			args: []string{"-eval=1+5", "-eval=var.snack"},
+			want: "6\n\"popcorn\"\n",
+		},
+		"json output": {
+			args: []string{"-json", "-eval=local.snack_bar"},
+			want: "[\"popcorn\",\"seaweed snacks\"]\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			var output bytes.Buffer
+			outCloser := testStdoutCapture(t, &output)
+			code := newCommand(ui).Run(test.args)
+			outCloser()
+			if code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+			}
+			if got := output.String(); got != test.want {
+				t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, test.want)
+			}
+		})
+	}
+
+	// An evaluation error exits non-zero.
+	ui := cli.NewMockUi()
+	var output bytes.Buffer
+	outCloser := testStdoutCapture(t, &output)
+	code := newCommand(ui).Run([]string{"-eval=var.nonexist"})
+	outCloser()
+	if code == 0 {
+		t.Fatal("expected error")
+	}
+	if got, want := ui.ErrorWriter.String(), "Reference to undeclared input variable"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+}
+
+func TestConsole_jsonPiped(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("variables"), td)
+	defer testChdir(t, td)()
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	// Populating Streams makes StdinPiped notice the piped stdin below.
+	streams, _ := terminal.StreamsForTesting(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+			Streams:          streams,
+		},
+	}
+
+	var output bytes.Buffer
+	defer testStdinPipe(t, strings.NewReader("1+5\nvar.snack\n"))()
+	outCloser := testStdoutCapture(t, &output)
+	code := c.Run([]string{"-json"})
+	outCloser()
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	// In JSON mode every expression produces one line of output, not
+	// just the last one.
+	if got, want := output.String(), "6\n\"popcorn\"\n"; got != want {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestConsole_plan(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+
+	// First create a saved plan file to evaluate against.
+	{
+		ui := cli.NewMockUi()
+		view, _ := testView(t)
+		pc := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				Ui:               ui,
+				View:             view,
+			},
+		}
+		if code := pc.Run([]string{"-out=console.tfplan"}); code != 0 {
+			t.Fatalf("plan failed: %s", ui.ErrorWriter.String())
+		}
+	}
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	var output bytes.Buffer
+	outCloser := testStdoutCapture(t, &output)
+	code := c.Run([]string{"-plan=console.tfplan", "-eval=test_instance.foo.ami"})
+	outCloser()
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+	// The resource is in the plan's configuration but not yet in its prior
+	// state, so its attributes are not yet known.
+	if got, want := output.String(), "(known after apply)\n"; got != want {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 func TestConsole_modules(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("modules"), td)
@@ -0,0 +1,192 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// localDevOverridesFilename is the name of an optional file in the root
+// module directory that declares provider development overrides scoped to
+// this particular configuration, as a project-local supplement to the
+// global dev_overrides block in the CLI configuration.
+const localDevOverridesFilename = ".terraform-dev-overrides.hcl"
+
+// localDevOverride represents one dev_override block from a project-local
+// development overrides file.
+//
+// Unlike the CLI configuration's dev_overrides, each entry can optionally
+// be limited to particular workspaces or particular commands, so that for
+// example a development build of a provider can be used when planning in a
+// scratch workspace without also being used for applies against shared
+// workspaces.
+type localDevOverride struct {
+	Provider addrs.Provider
+	LocalDir getproviders.PackageLocalDir
+
+	// Workspaces and Commands optionally restrict when the override is in
+	// effect. An empty slice places no restriction on that dimension.
+	Workspaces []string
+	Commands   []string
+}
+
+// appliesTo returns true if this override should be in effect for the
+// given current workspace and CLI command name.
+func (o *localDevOverride) appliesTo(workspace, commandName string) bool {
+	if len(o.Workspaces) != 0 {
+		found := false
+		for _, name := range o.Workspaces {
+			if name == workspace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(o.Commands) != 0 {
+		found := false
+		for _, name := range o.Commands {
+			if name == commandName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// effectiveProviderDevOverrides returns the provider development overrides
+// in effect for the current run: the dev_overrides from the CLI
+// configuration, which always apply, merged with any entries from the
+// project-local overrides file whose workspace and command scopes match.
+// Project-local entries take priority for the providers they name.
+//
+// The returned diagnostics describe any problems with the project-local
+// file. They don't invalidate the returned map, which still includes
+// whatever could be understood, but callers that are about to launch
+// providers should treat errors in them as fatal.
+func (m *Meta) effectiveProviderDevOverrides() (map[addrs.Provider]getproviders.PackageLocalDir, tfdiags.Diagnostics) {
+	if !m.localDevOverridesLoaded {
+		m.localDevOverrides, m.localDevOverridesDiags = loadLocalDevOverrides(localDevOverridesFilename)
+		m.localDevOverridesLoaded = true
+	}
+	diags := m.localDevOverridesDiags
+
+	if len(m.localDevOverrides) == 0 {
+		return m.ProviderDevOverrides, diags
+	}
+
+	workspace, err := m.Workspace()
+	if err != nil {
+		// An invalid workspace selection will be reported elsewhere, so here
+		// we just leave the workspace unset so that any workspace-scoped
+		// overrides are conservatively treated as inactive.
+		workspace = ""
+	}
+
+	ret := make(map[addrs.Provider]getproviders.PackageLocalDir, len(m.ProviderDevOverrides)+len(m.localDevOverrides))
+	for provider, localDir := range m.ProviderDevOverrides {
+		ret[provider] = localDir
+	}
+	for _, override := range m.localDevOverrides {
+		if override.appliesTo(workspace, m.CommandName) {
+			ret[override.Provider] = override.LocalDir
+		}
+	}
+	return ret, diags
+}
+
+// loadLocalDevOverrides reads a project-local development overrides file,
+// returning the overrides it declares. If the file doesn't exist at all
+// then the result is nil with no diagnostics, because the file is always
+// optional.
+func loadLocalDevOverrides(filename string) ([]*localDevOverride, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	parser := hclparse.NewParser()
+	file, hclDiags := parser.ParseHCLFile(filename)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	content, hclDiags := file.Body.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "dev_override", LabelNames: []string{"source"}},
+		},
+	})
+	diags = diags.Append(hclDiags)
+
+	var ret []*localDevOverride
+	for _, block := range content.Blocks {
+		provider, moreDiags := addrs.ParseProviderSourceString(block.Labels[0])
+		if moreDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider development override",
+				fmt.Sprintf("The dev_override label %q at %s is not a valid provider source string.", block.Labels[0], block.DefRange),
+			))
+			continue
+		}
+
+		blockContent, hclDiags := block.Body.Content(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{
+				{Name: "path", Required: true},
+				{Name: "workspaces"},
+				{Name: "commands"},
+			},
+		})
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+
+		override := &localDevOverride{
+			Provider: provider,
+		}
+
+		var path string
+		hclDiags = gohcl.DecodeExpression(blockContent.Attributes["path"].Expr, nil, &path)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+		override.LocalDir = getproviders.PackageLocalDir(filepath.Clean(path))
+
+		if attr, ok := blockContent.Attributes["workspaces"]; ok {
+			hclDiags = gohcl.DecodeExpression(attr.Expr, nil, &override.Workspaces)
+			diags = diags.Append(hclDiags)
+			if hclDiags.HasErrors() {
+				continue
+			}
+		}
+		if attr, ok := blockContent.Attributes["commands"]; ok {
+			hclDiags = gohcl.DecodeExpression(attr.Expr, nil, &override.Commands)
+			diags = diags.Append(hclDiags)
+			if hclDiags.HasErrors() {
+				continue
+			}
+		}
+
+		ret = append(ret, override)
+	}
+
+	return ret, diags
+}
@@ -0,0 +1,214 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+)
+
+func TestLoadLocalDevOverrides(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		overrides, diags := loadLocalDevOverrides(filepath.Join(t.TempDir(), localDevOverridesFilename))
+		if len(diags) > 0 {
+			t.Fatalf("unexpected diags: %s", diags.Err())
+		}
+		if overrides != nil {
+			t.Fatalf("unexpected overrides: %#v", overrides)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), localDevOverridesFilename)
+		err := os.WriteFile(filename, []byte(`
+dev_override "hashicorp/null" {
+  path = "/tmp/null-provider"
+}
+
+dev_override "example.com/foo/bar" {
+  path       = "/tmp/bar-provider"
+  workspaces = ["dev"]
+  commands   = ["plan"]
+}
+`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overrides, diags := loadLocalDevOverrides(filename)
+		if len(diags) > 0 {
+			t.Fatalf("unexpected diags: %s", diags.Err())
+		}
+		if got, want := len(overrides), 2; got != want {
+			t.Fatalf("wrong number of overrides %d; want %d", got, want)
+		}
+
+		first := overrides[0]
+		if got, want := first.Provider, addrs.NewDefaultProvider("null"); got != want {
+			t.Errorf("wrong provider %s; want %s", got, want)
+		}
+		if got, want := first.LocalDir, getproviders.PackageLocalDir(filepath.Clean("/tmp/null-provider")); got != want {
+			t.Errorf("wrong local dir %s; want %s", got, want)
+		}
+		if len(first.Workspaces) != 0 || len(first.Commands) != 0 {
+			t.Errorf("unexpected scoping on unscoped override: %#v", first)
+		}
+
+		second := overrides[1]
+		if got, want := second.Provider, addrs.MustParseProviderSourceString("example.com/foo/bar"); got != want {
+			t.Errorf("wrong provider %s; want %s", got, want)
+		}
+		if got, want := len(second.Workspaces), 1; got != want || second.Workspaces[0] != "dev" {
+			t.Errorf("wrong workspaces %#v; want [dev]", second.Workspaces)
+		}
+		if got, want := len(second.Commands), 1; got != want || second.Commands[0] != "plan" {
+			t.Errorf("wrong commands %#v; want [plan]", second.Commands)
+		}
+	})
+
+	t.Run("invalid provider source", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), localDevOverridesFilename)
+		err := os.WriteFile(filename, []byte(`
+dev_override "not a valid source!" {
+  path = "/tmp/provider"
+}
+`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, diags := loadLocalDevOverrides(filename)
+		if !diags.HasErrors() {
+			t.Fatal("expected errors but got none")
+		}
+		if got, want := diags.Err().Error(), "not a valid provider source string"; !strings.Contains(got, want) {
+			t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), localDevOverridesFilename)
+		err := os.WriteFile(filename, []byte(`
+dev_override "hashicorp/null" {
+  workspaces = ["dev"]
+}
+`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, diags := loadLocalDevOverrides(filename)
+		if !diags.HasErrors() {
+			t.Fatal("expected errors but got none")
+		}
+	})
+}
+
+func TestLocalDevOverrideAppliesTo(t *testing.T) {
+	testCases := map[string]struct {
+		override  localDevOverride
+		workspace string
+		command   string
+		want      bool
+	}{
+		"unscoped": {
+			localDevOverride{},
+			"default", "apply",
+			true,
+		},
+		"matching workspace": {
+			localDevOverride{Workspaces: []string{"dev", "staging"}},
+			"dev", "apply",
+			true,
+		},
+		"other workspace": {
+			localDevOverride{Workspaces: []string{"dev"}},
+			"default", "apply",
+			false,
+		},
+		"matching command": {
+			localDevOverride{Commands: []string{"plan"}},
+			"default", "plan",
+			true,
+		},
+		"other command": {
+			localDevOverride{Commands: []string{"plan"}},
+			"default", "apply",
+			false,
+		},
+		"workspace matches but command doesn't": {
+			localDevOverride{Workspaces: []string{"dev"}, Commands: []string{"plan"}},
+			"dev", "apply",
+			false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.override.appliesTo(tc.workspace, tc.command); got != tc.want {
+				t.Errorf("wrong result %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMetaEffectiveProviderDevOverrides(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	err := os.WriteFile(filepath.Join(td, localDevOverridesFilename), []byte(`
+dev_override "hashicorp/null" {
+  path = "/tmp/local-null"
+}
+
+dev_override "hashicorp/random" {
+  path     = "/tmp/local-random"
+  commands = ["plan"]
+}
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nullProvider := addrs.NewDefaultProvider("null")
+	randomProvider := addrs.NewDefaultProvider("random")
+	testProvider := addrs.NewDefaultProvider("test")
+
+	m := new(Meta)
+	m.CommandName = "apply"
+	m.ProviderDevOverrides = map[addrs.Provider]getproviders.PackageLocalDir{
+		// The project-local file should win for this provider.
+		nullProvider: getproviders.PackageLocalDir("/tmp/global-null"),
+		testProvider: getproviders.PackageLocalDir("/tmp/global-test"),
+	}
+
+	overrides, diags := m.effectiveProviderDevOverrides()
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %s", diags.Err())
+	}
+	if got, want := overrides[nullProvider], getproviders.PackageLocalDir("/tmp/local-null"); got != want {
+		t.Errorf("wrong null override %s; want %s", got, want)
+	}
+	if got, want := overrides[testProvider], getproviders.PackageLocalDir("/tmp/global-test"); got != want {
+		t.Errorf("wrong test override %s; want %s", got, want)
+	}
+	if _, ok := overrides[randomProvider]; ok {
+		t.Errorf("random override is in effect for apply, but is scoped to plan only")
+	}
+
+	// The same file should include the plan-scoped override when the
+	// current command is plan. We use a fresh Meta because the parsed file
+	// is cached on the receiver.
+	m = new(Meta)
+	m.CommandName = "plan"
+	overrides, diags = m.effectiveProviderDevOverrides()
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %s", diags.Err())
+	}
+	if got, want := overrides[randomProvider], getproviders.PackageLocalDir("/tmp/local-random"); got != want {
+		t.Errorf("wrong random override %s; want %s", got, want)
+	}
+}
@@ -0,0 +1,338 @@
+package e2etest
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	//lint:file-ignore SA1019 openpgp is deprecated but the registry protocol still uses it
+	"golang.org/x/crypto/openpgp"
+	openpgpArmor "golang.org/x/crypto/openpgp/armor"
+)
+
+// fakeProviderRegistry is a small in-process provider registry that
+// implements just enough of the provider registry protocol for commands like
+// "terraform providers mirror" to install from it: the versions and download
+// endpoints, plus package zip, SHA256SUMS, and signature files generated on
+// the fly with a throwaway GPG key.
+//
+// It intentionally serves plain HTTP so that a spawned terraform binary can
+// talk to it without any TLS trust configuration; tests point the binary at
+// it with a CLI configuration file produced by CLIConfigEnv, which overrides
+// service discovery for a chosen hostname.
+type fakeProviderRegistry struct {
+	server *httptest.Server
+
+	// providers maps "namespace/type" to the list of version strings the
+	// registry should claim to have available.
+	providers map[string][]string
+
+	key              *openpgp.Entity
+	armoredPublicKey string
+
+	// RateLimit, when set, makes the download-related endpoints respond
+	// with HTTP 429, to exercise rate-limit error handling.
+	RateLimit bool
+
+	// CorruptChecksums, when set, makes the SHA256SUMS documents contain
+	// checksums that don't match the served zip files, to exercise checksum
+	// verification error handling.
+	CorruptChecksums bool
+
+	// MissingPlatforms maps "namespace/type" provider keys to platform
+	// strings like "windows_386" for which the download endpoint responds
+	// with HTTP 404, to exercise handling of providers that don't publish
+	// packages for every requested platform.
+	MissingPlatforms map[string][]string
+
+	mu   sync.Mutex
+	zips map[string][]byte // cache of generated package zips, keyed by filename
+}
+
+// newFakeProviderRegistry starts a fake registry serving the given providers,
+// each identified by a "namespace/type" key with a list of available version
+// strings. The caller must call Close when finished with it.
+func newFakeProviderRegistry(t *testing.T, providers map[string][]string) *fakeProviderRegistry {
+	t.Helper()
+
+	key, err := openpgp.NewEntity("Terraform Fake Registry", "e2e test key", "noreply@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %s", err)
+	}
+
+	var armored bytes.Buffer
+	armorWriter, err := openpgpArmor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to prepare armored public key: %s", err)
+	}
+	if err := key.Serialize(armorWriter); err != nil {
+		t.Fatalf("failed to serialize public key: %s", err)
+	}
+	if err := armorWriter.Close(); err != nil {
+		t.Fatalf("failed to finish armored public key: %s", err)
+	}
+
+	r := &fakeProviderRegistry{
+		providers:        providers,
+		key:              key,
+		armoredPublicKey: armored.String(),
+		zips:             make(map[string][]byte),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.handler))
+	return r
+}
+
+func (r *fakeProviderRegistry) Close() {
+	r.server.Close()
+}
+
+// CLIConfigEnv writes a CLI configuration file that overrides service
+// discovery for the given hostname so that it points at this fake registry,
+// and returns a TF_CLI_CONFIG_FILE=... environment entry suitable for
+// passing to the AddEnv method of the e2e binary harness.
+func (r *fakeProviderRegistry) CLIConfigEnv(t *testing.T, hostname string) string {
+	t.Helper()
+	return r.CLIConfigEnvWithExtra(t, hostname, "")
+}
+
+// CLIConfigEnvWithExtra is like CLIConfigEnv but appends the given extra
+// CLI configuration after the service discovery override, for tests that
+// also need settings such as provider_installation rules.
+func (r *fakeProviderRegistry) CLIConfigEnvWithExtra(t *testing.T, hostname, extra string) string {
+	t.Helper()
+
+	config := fmt.Sprintf(`
+host %q {
+  services = {
+    "providers.v1" = %q
+  }
+}
+`, hostname, r.server.URL+"/v1/providers/") + extra
+
+	path := filepath.Join(t.TempDir(), "fake-registry.tfrc")
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatalf("failed to write CLI config for fake registry: %s", err)
+	}
+	return "TF_CLI_CONFIG_FILE=" + path
+}
+
+func (r *fakeProviderRegistry) handler(resp http.ResponseWriter, req *http.Request) {
+	path := req.URL.EscapedPath()
+
+	switch {
+	case strings.HasPrefix(path, "/files/"):
+		r.handleFile(resp, strings.TrimPrefix(path, "/files/"))
+	case strings.HasPrefix(path, "/v1/providers/"):
+		r.handleRegistry(resp, strings.TrimPrefix(path, "/v1/providers/"))
+	default:
+		resp.WriteHeader(404)
+		fmt.Fprintf(resp, "no endpoint at %s", path)
+	}
+}
+
+func (r *fakeProviderRegistry) handleRegistry(resp http.ResponseWriter, path string) {
+	parts := strings.Split(path, "/")
+
+	switch {
+	case len(parts) == 3 && parts[2] == "versions":
+		providerKey := parts[0] + "/" + parts[1]
+		versions, ok := r.providers[providerKey]
+		if !ok {
+			resp.WriteHeader(404)
+			fmt.Fprintf(resp, "unknown provider %s", providerKey)
+			return
+		}
+		type versionJSON struct {
+			Version   string   `json:"version"`
+			Protocols []string `json:"protocols"`
+		}
+		var body struct {
+			Versions []versionJSON `json:"versions"`
+		}
+		for _, version := range versions {
+			body.Versions = append(body.Versions, versionJSON{version, []string{"5.0"}})
+		}
+		r.writeJSON(resp, body)
+
+	case len(parts) == 6 && parts[3] == "download":
+		if r.RateLimit {
+			resp.Header().Set("Retry-After", "120")
+			resp.WriteHeader(429)
+			fmt.Fprint(resp, "too many requests")
+			return
+		}
+		namespace, typeName, version, osName, arch := parts[0], parts[1], parts[2], parts[4], parts[5]
+		if !r.hasVersion(namespace+"/"+typeName, version) {
+			resp.WriteHeader(404)
+			fmt.Fprintf(resp, "unknown provider version %s/%s v%s", namespace, typeName, version)
+			return
+		}
+		if r.platformMissing(namespace+"/"+typeName, osName+"_"+arch) {
+			resp.WriteHeader(404)
+			fmt.Fprintf(resp, "no package for %s/%s v%s on %s_%s", namespace, typeName, version, osName, arch)
+			return
+		}
+		filename := fmt.Sprintf("terraform-provider-%s_%s_%s_%s.zip", typeName, version, osName, arch)
+		zipData := r.packageZip(typeName, version, osName, arch)
+		body := map[string]interface{}{
+			"protocols":             []string{"5.0"},
+			"os":                    osName,
+			"arch":                  arch,
+			"filename":              filename,
+			"shasum":                fmt.Sprintf("%x", sha256.Sum256(zipData)),
+			"download_url":          r.server.URL + "/files/" + filename,
+			"shasums_url":           fmt.Sprintf("%s/files/terraform-provider-%s_%s_SHA256SUMS", r.server.URL, typeName, version),
+			"shasums_signature_url": fmt.Sprintf("%s/files/terraform-provider-%s_%s_SHA256SUMS.sig", r.server.URL, typeName, version),
+			"signing_keys": map[string]interface{}{
+				"gpg_public_keys": []map[string]interface{}{
+					{"ascii_armor": r.armoredPublicKey},
+				},
+			},
+		}
+		if r.CorruptChecksums {
+			body["shasum"] = strings.Repeat("0", 63) + "1"
+		}
+		r.writeJSON(resp, body)
+
+	default:
+		resp.WriteHeader(404)
+		fmt.Fprintf(resp, "unexpected registry request %s", path)
+	}
+}
+
+func (r *fakeProviderRegistry) handleFile(resp http.ResponseWriter, filename string) {
+	if r.RateLimit {
+		resp.Header().Set("Retry-After", "120")
+		resp.WriteHeader(429)
+		fmt.Fprint(resp, "too many requests")
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(filename, "_SHA256SUMS"):
+		// e.g. terraform-provider-foo_1.0.0_SHA256SUMS
+		base := strings.TrimSuffix(filename, "_SHA256SUMS")
+		resp.Write(r.shasumsDocument(base))
+
+	case strings.HasSuffix(filename, "_SHA256SUMS.sig"):
+		base := strings.TrimSuffix(filename, "_SHA256SUMS.sig")
+		var sig bytes.Buffer
+		err := openpgp.DetachSign(&sig, r.key, bytes.NewReader(r.shasumsDocument(base)), nil)
+		if err != nil {
+			resp.WriteHeader(500)
+			fmt.Fprintf(resp, "failed to sign checksums: %s", err)
+			return
+		}
+		resp.Write(sig.Bytes())
+
+	case strings.HasSuffix(filename, ".zip"):
+		// e.g. terraform-provider-foo_1.0.0_linux_amd64.zip
+		parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(filename, "terraform-provider-"), ".zip"), "_")
+		if len(parts) != 4 {
+			resp.WriteHeader(404)
+			fmt.Fprintf(resp, "malformed package filename %s", filename)
+			return
+		}
+		resp.Write(r.packageZip(parts[0], parts[1], parts[2], parts[3]))
+
+	default:
+		resp.WriteHeader(404)
+		fmt.Fprintf(resp, "no file %s", filename)
+	}
+}
+
+// shasumsDocument produces the SHA256SUMS document covering all platform
+// packages of the provider release identified by base, which has the form
+// "terraform-provider-foo_1.0.0". All platforms we generate packages for
+// appear in the document so that it's stable regardless of request order.
+func (r *fakeProviderRegistry) shasumsDocument(base string) []byte {
+	var buf bytes.Buffer
+	parts := strings.Split(strings.TrimPrefix(base, "terraform-provider-"), "_")
+	if len(parts) != 2 {
+		return nil
+	}
+	typeName, version := parts[0], parts[1]
+	for _, platform := range fakeRegistryPlatforms {
+		filename := fmt.Sprintf("terraform-provider-%s_%s_%s.zip", typeName, version, platform)
+		platformParts := strings.Split(platform, "_")
+		zipData := r.packageZip(typeName, version, platformParts[0], platformParts[1])
+		sum := sha256.Sum256(zipData)
+		if r.CorruptChecksums {
+			sum = sha256.Sum256(append(zipData, '!'))
+		}
+		fmt.Fprintf(&buf, "%x  %s\n", sum, filename)
+	}
+	return buf.Bytes()
+}
+
+// fakeRegistryPlatforms are the platforms the fake registry claims to have
+// packages for. Tests that ask for other platforms will get 404 responses
+// at mirror time but cover these in SHA256SUMS regardless.
+var fakeRegistryPlatforms = []string{"linux_amd64", "windows_386", "darwin_amd64"}
+
+// packageZip returns a deterministic tiny package zip for the given release,
+// generating and caching it on first use so that checksum documents and
+// downloads always agree.
+func (r *fakeProviderRegistry) packageZip(typeName, version, osName, arch string) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filename := fmt.Sprintf("terraform-provider-%s_%s_%s_%s.zip", typeName, version, osName, arch)
+	if data, ok := r.zips[filename]; ok {
+		return data
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	hdr := &zip.FileHeader{
+		Name:   fmt.Sprintf("terraform-provider-%s_v%s", typeName, version),
+		Method: zip.Deflate,
+	}
+	hdr.SetMode(0755) // real provider packages record an executable mode
+	f, err := w.CreateHeader(hdr)
+	if err == nil {
+		fmt.Fprintf(f, "#!/bin/sh\necho fake provider %s v%s for %s_%s\n", typeName, version, osName, arch)
+	}
+	w.Close()
+
+	r.zips[filename] = buf.Bytes()
+	return r.zips[filename]
+}
+
+func (r *fakeProviderRegistry) platformMissing(providerKey, platform string) bool {
+	for _, missing := range r.MissingPlatforms[providerKey] {
+		if missing == platform {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeProviderRegistry) hasVersion(providerKey, version string) bool {
+	for _, v := range r.providers[providerKey] {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeProviderRegistry) writeJSON(resp http.ResponseWriter, body interface{}) {
+	enc, err := json.Marshal(body)
+	if err != nil {
+		resp.WriteHeader(500)
+		fmt.Fprintf(resp, "failed to encode body: %s", err)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Write(enc)
+}
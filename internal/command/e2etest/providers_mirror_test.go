@@ -1,20 +1,27 @@
 package e2etest
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform/internal/e2e"
 )
 
-// The tests in this file are for the "terraform providers mirror" command,
-// which is tested in an e2etest mode rather than a unit test mode because it
-// interacts directly with Terraform Registry and the full details of that are
-// tricky to mock. Such a mock is _possible_, but we're using e2etest as a
-// compromise for now to keep these tests relatively simple.
+// The tests in this file are for the "terraform providers mirror" command.
+// The original tests interact directly with Terraform Registry for full
+// fidelity, and so only run when network access is allowed. The "Offline"
+// variants run against the in-process fake registry from
+// fake_registry_test.go, so that the common cases still get covered in
+// environments without network access.
 
 func TestTerraformProvidersMirror(t *testing.T) {
 	// This test reaches out to releases.hashicorp.com to download the
@@ -38,6 +45,7 @@ func TestTerraformProvidersMirror(t *testing.T) {
 	// In the (unlikely) event that these particular versions of these
 	// providers are removed from the registry, this test will start to fail.
 	want := []string{
+		"providers-index.json",
 		"registry.terraform.io/hashicorp/null/2.1.0.json",
 		"registry.terraform.io/hashicorp/null/index.json",
 		"registry.terraform.io/hashicorp/null/terraform-provider-null_2.1.0_linux_amd64.zip",
@@ -71,3 +79,526 @@ func TestTerraformProvidersMirror(t *testing.T) {
 		t.Errorf("unexpected files in result\n%s", diff)
 	}
 }
+
+func TestTerraformProvidersMirrorOffline(t *testing.T) {
+	t.Parallel()
+
+	// This variant of TestTerraformProvidersMirror runs against an
+	// in-process fake registry, so it works without network access.
+	registry := newFakeProviderRegistry(t, map[string][]string{
+		"fake/foo": {"0.9.0", "1.0.0"},
+		"fake/bar": {"2.1.0"},
+	})
+	defer registry.Close()
+
+	outputDir := t.TempDir()
+	t.Logf("creating mirror directory in %s", outputDir)
+
+	fixturePath := filepath.Join("testdata", "terraform-providers-mirror-offline")
+	tf := e2e.NewBinary(t, terraformBin, fixturePath)
+	tf.AddEnv(registry.CLIConfigEnv(t, "example.com"))
+
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	stdout, stderr, err := tf.Run(
+		"providers", "mirror",
+		"-platform=linux_amd64", "-platform=windows_386",
+		"-also-version=example.com/fake/foo=0.9.0",
+		"-also-version=example.com/fake/bar=2.1.0", // already selected, so a no-op
+		"-report="+reportPath,
+		outputDir,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+
+	want := []string{
+		"example.com/fake/bar/2.1.0.json",
+		"example.com/fake/bar/index.json",
+		"example.com/fake/bar/terraform-provider-bar_2.1.0_SHA256SUMS",
+		"example.com/fake/bar/terraform-provider-bar_2.1.0_SHA256SUMS.sig",
+		"example.com/fake/bar/terraform-provider-bar_2.1.0_linux_amd64.zip",
+		"example.com/fake/bar/terraform-provider-bar_2.1.0_windows_386.zip",
+		"example.com/fake/foo/0.9.0.json",
+		"example.com/fake/foo/1.0.0.json",
+		"example.com/fake/foo/index.json",
+		"example.com/fake/foo/terraform-provider-foo_0.9.0_SHA256SUMS",
+		"example.com/fake/foo/terraform-provider-foo_0.9.0_SHA256SUMS.sig",
+		"example.com/fake/foo/terraform-provider-foo_0.9.0_linux_amd64.zip",
+		"example.com/fake/foo/terraform-provider-foo_0.9.0_windows_386.zip",
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_SHA256SUMS",
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_SHA256SUMS.sig",
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_linux_amd64.zip",
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_windows_386.zip",
+		"providers-index.json",
+	}
+	var got []string
+	walkErr := filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil // we only care about leaf files for this test
+		}
+		relPath, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		got = append(got, filepath.ToSlash(relPath))
+		return nil
+	})
+	if walkErr != nil {
+		t.Fatal(walkErr)
+	}
+	sort.Strings(got)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files in result\n%s", diff)
+	}
+
+	// The -report option must record the decisions made during the run.
+	reportSrc, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("failed to read run report: %s", err)
+	}
+	type platformReport struct {
+		Outcome   string `json:"outcome"`
+		SizeBytes int64  `json:"size_bytes"`
+		Hash      string `json:"hash"`
+	}
+	var report struct {
+		FormatVersion string `json:"format_version"`
+		Providers     map[string]struct {
+			CandidateVersions []string                              `json:"candidate_versions"`
+			SelectedVersion   string                                `json:"selected_version"`
+			Platforms         map[string]*platformReport            `json:"platforms"`
+			AlsoVersions      map[string]map[string]*platformReport `json:"also_versions"`
+		} `json:"providers"`
+	}
+	if err := json.Unmarshal(reportSrc, &report); err != nil {
+		t.Fatalf("failed to decode run report: %s", err)
+	}
+	if got, want := report.FormatVersion, "1.0"; got != want {
+		t.Errorf("wrong report format version %q; want %q", got, want)
+	}
+	if got, want := len(report.Providers), 2; got != want {
+		t.Fatalf("report covers %d providers; want %d", got, want)
+	}
+	foo, ok := report.Providers["example.com/fake/foo"]
+	if !ok {
+		t.Fatalf("report has no entry for example.com/fake/foo")
+	}
+	if got, want := foo.SelectedVersion, "1.0.0"; got != want {
+		t.Errorf("wrong selected version %q for fake/foo; want %q", got, want)
+	}
+	if diff := cmp.Diff([]string{"1.0.0"}, foo.CandidateVersions); diff != "" {
+		t.Errorf("wrong candidate versions for fake/foo\n%s", diff)
+	}
+	for _, platform := range []string{"linux_amd64", "windows_386"} {
+		pr := foo.Platforms[platform]
+		if pr == nil {
+			t.Fatalf("report has no entry for fake/foo on %s", platform)
+		}
+		if got, want := pr.Outcome, "downloaded"; got != want {
+			t.Errorf("wrong outcome %q for fake/foo on %s; want %q", got, platform, want)
+		}
+		if pr.SizeBytes == 0 {
+			t.Errorf("no byte count recorded for fake/foo on %s", platform)
+		}
+		if !strings.HasPrefix(pr.Hash, "h1:") {
+			t.Errorf("no h1: hash recorded for fake/foo on %s; got %q", platform, pr.Hash)
+		}
+	}
+
+	// The extra version requested with -also-version is reported separately.
+	if pr := foo.AlsoVersions["0.9.0"]["linux_amd64"]; pr == nil || pr.Outcome != "downloaded" {
+		t.Errorf("missing or wrong also_versions entry for fake/foo v0.9.0 on linux_amd64: %#v", pr)
+	}
+	// Requesting a version that the constraints already selected is a no-op
+	// rather than a duplicate entry.
+	if also := report.Providers["example.com/fake/bar"].AlsoVersions; len(also) != 0 {
+		t.Errorf("unexpected also_versions entries for fake/bar: %#v", also)
+	}
+
+	// The top-level providers index must summarize the whole mirror,
+	// including the extra version added with -also-version.
+	indexSrc, err := os.ReadFile(filepath.Join(outputDir, "providers-index.json"))
+	if err != nil {
+		t.Fatalf("failed to read providers index: %s", err)
+	}
+	var index struct {
+		FormatVersion string `json:"format_version"`
+		Providers     map[string]struct {
+			Versions map[string]struct {
+				Platforms []string `json:"platforms"`
+				SizeBytes int64    `json:"size_bytes"`
+			} `json:"versions"`
+			TotalSizeBytes int64  `json:"total_size_bytes"`
+			LastRefreshed  string `json:"last_refreshed"`
+		} `json:"providers"`
+	}
+	if err := json.Unmarshal(indexSrc, &index); err != nil {
+		t.Fatalf("failed to decode providers index: %s", err)
+	}
+	if got, want := index.FormatVersion, "1.0"; got != want {
+		t.Errorf("wrong providers index format version %q; want %q", got, want)
+	}
+	if got, want := len(index.Providers), 2; got != want {
+		t.Fatalf("providers index covers %d providers; want %d", got, want)
+	}
+	fooIndex, ok := index.Providers["example.com/fake/foo"]
+	if !ok {
+		t.Fatalf("providers index has no entry for example.com/fake/foo")
+	}
+	if got, want := len(fooIndex.Versions), 2; got != want {
+		t.Fatalf("providers index records %d versions of fake/foo; want %d", got, want)
+	}
+	for _, versionStr := range []string{"0.9.0", "1.0.0"} {
+		version, ok := fooIndex.Versions[versionStr]
+		if !ok {
+			t.Fatalf("providers index has no entry for fake/foo v%s", versionStr)
+		}
+		if diff := cmp.Diff([]string{"linux_amd64", "windows_386"}, version.Platforms); diff != "" {
+			t.Errorf("wrong platforms for fake/foo v%s\n%s", versionStr, diff)
+		}
+		if version.SizeBytes == 0 {
+			t.Errorf("no size recorded for fake/foo v%s", versionStr)
+		}
+	}
+	if fooIndex.TotalSizeBytes == 0 {
+		t.Errorf("no total size recorded for fake/foo")
+	}
+	if fooIndex.LastRefreshed == "" {
+		t.Errorf("no last_refreshed timestamp recorded for fake/foo")
+	}
+
+	// Requesting a version that doesn't exist upstream, or a provider that
+	// the configuration doesn't require, must fail.
+	stdout, stderr, err = tf.Run(
+		"providers", "mirror",
+		"-platform=linux_amd64",
+		"-also-version=example.com/fake/foo=9.9.9",
+		"-also-version=example.com/fake/baz=1.0.0",
+		t.TempDir(),
+	)
+	if err == nil {
+		t.Fatalf("unexpected success with invalid -also-version options\nstdout:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "Provider version not available") {
+		t.Errorf("missing error for nonexistent version\nstderr:\n%s", stderr)
+	}
+	if !strings.Contains(stderr, "Invalid additional version") || !strings.Contains(stderr, "fake/baz") {
+		t.Errorf("missing error for unrequired provider\nstderr:\n%s", stderr)
+	}
+}
+
+func TestTerraformProvidersMirrorOffline_partialFailure(t *testing.T) {
+	t.Parallel()
+
+	// The registry has no windows_386 package for fake/bar, so mirroring
+	// both providers for both platforms can only partially succeed. The
+	// command must still mirror everything else, summarize what is missing,
+	// and exit with the "partial success" status so that automation can
+	// distinguish this from a total failure.
+	registry := newFakeProviderRegistry(t, map[string][]string{
+		"fake/foo": {"1.0.0"},
+		"fake/bar": {"2.1.0"},
+	})
+	defer registry.Close()
+	registry.MissingPlatforms = map[string][]string{
+		"fake/bar": {"windows_386"},
+	}
+
+	outputDir := t.TempDir()
+	t.Logf("creating mirror directory in %s", outputDir)
+
+	fixturePath := filepath.Join("testdata", "terraform-providers-mirror-offline")
+	tf := e2e.NewBinary(t, terraformBin, fixturePath)
+	tf.AddEnv(registry.CLIConfigEnv(t, "example.com"))
+
+	stdout, stderr, err := tf.Run("providers", "mirror", "-platform=linux_amd64", "-platform=windows_386", outputDir)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("wrong error %v; want an exit status error\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	if code := exitErr.ExitCode(); code != 2 {
+		t.Fatalf("wrong exit status %d; want 2 for partial success\nstdout:\n%s\nstderr:\n%s", code, stdout, stderr)
+	}
+	if !strings.Contains(stderr, "Provider release not available") {
+		t.Errorf("missing diagnostic for the unavailable package\nstderr:\n%s", stderr)
+	}
+
+	// The missing package must not prevent the others from being mirrored.
+	for _, relPath := range []string{
+		"example.com/fake/bar/terraform-provider-bar_2.1.0_linux_amd64.zip",
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_linux_amd64.zip",
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_windows_386.zip",
+	} {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(relPath))); err != nil {
+			t.Errorf("missing mirrored package %s: %s", relPath, err)
+		}
+	}
+
+	// The run must end with a summary of what was and wasn't mirrored.
+	if want := "Summary: 2 providers selected, 1 fully mirrored, 1 partially mirrored, 0 failed."; !strings.Contains(stdout, want) {
+		t.Errorf("missing summary line %q\nstdout:\n%s", want, stdout)
+	}
+	if want := "example.com/fake/bar is missing packages for: windows_386 (v2.1.0)"; !strings.Contains(stdout, want) {
+		t.Errorf("missing packages are not listed in the summary\nstdout:\n%s", stdout)
+	}
+
+	// With -json the same run produces a stream of JSON objects ending with
+	// a summary object.
+	stdout, stderr, err = tf.Run("providers", "mirror", "-json", "-platform=linux_amd64", "-platform=windows_386", t.TempDir())
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("wrong error %v from -json run; want exit status 2\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	type event struct {
+		Type              string              `json:"type"`
+		Outcome           string              `json:"outcome"`
+		Providers         int                 `json:"providers"`
+		FullyMirrored     int                 `json:"fully_mirrored"`
+		PartiallyMirrored int                 `json:"partially_mirrored"`
+		Failed            int                 `json:"failed"`
+		Missing           map[string][]string `json:"missing"`
+	}
+	var sawFailedPackage bool
+	for i, line := range lines {
+		var evt event
+		if err := json.Unmarshal([]byte(line), &evt); err != nil {
+			t.Fatalf("line %d of -json output is not a JSON object: %s\nline: %s", i+1, err, line)
+		}
+		if evt.Type == "package" && evt.Outcome == "failed" {
+			sawFailedPackage = true
+		}
+	}
+	if !sawFailedPackage {
+		t.Errorf("no failed package event in -json output\nstdout:\n%s", stdout)
+	}
+	var summary event
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+		t.Fatalf("failed to decode final -json object: %s", err)
+	}
+	if summary.Type != "summary" {
+		t.Fatalf("final -json object has type %q; want %q\nstdout:\n%s", summary.Type, "summary", stdout)
+	}
+	if summary.Providers != 2 || summary.FullyMirrored != 1 || summary.PartiallyMirrored != 1 || summary.Failed != 0 {
+		t.Errorf("wrong summary counts %#v; want 2 providers, 1 fully mirrored, 1 partially mirrored, 0 failed", summary)
+	}
+	if diff := cmp.Diff(map[string][]string{"example.com/fake/bar": {"windows_386 (v2.1.0)"}}, summary.Missing); diff != "" {
+		t.Errorf("wrong missing packages in summary\n%s", diff)
+	}
+}
+
+func TestTerraformProvidersMirrorOffline_installConfig(t *testing.T) {
+	t.Parallel()
+
+	// The provider_installation block in the CLI configuration encodes
+	// installation policy, which the mirror command must honor rather than
+	// always going directly to each provider's origin registry: excluded
+	// providers and providers with development overrides are skipped with
+	// warnings, while -ignore-cli-config restores the registry-direct
+	// behavior.
+	registry := newFakeProviderRegistry(t, map[string][]string{
+		"fake/foo": {"1.0.0"},
+		"fake/bar": {"2.1.0"},
+		"fake/dev": {"1.0.0"},
+	})
+	defer registry.Close()
+
+	fixturePath := filepath.Join("testdata", "terraform-providers-mirror-policy")
+	tf := e2e.NewBinary(t, terraformBin, fixturePath)
+	tf.AddEnv(registry.CLIConfigEnvWithExtra(t, "example.com", fmt.Sprintf(`
+provider_installation {
+  dev_overrides {
+    "example.com/fake/dev" = %q
+  }
+  direct {
+    exclude = ["example.com/fake/bar"]
+  }
+}
+`, t.TempDir())))
+
+	outputDir := t.TempDir()
+	t.Logf("creating mirror directory in %s", outputDir)
+
+	stdout, stderr, err := tf.Run("providers", "mirror", "-platform=linux_amd64", outputDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	all := stdout + stderr
+
+	// The excluded provider is skipped with a warning naming the pattern
+	// that excluded it, and the development override produces its own
+	// warning, rather than mirroring a release that would disagree with the
+	// local build.
+	if want := `excluded by the provider_installation pattern "example.com/fake/bar"`; !strings.Contains(all, want) {
+		t.Errorf("missing warning %q for the excluded provider\nstdout:\n%s\nstderr:\n%s", want, stdout, stderr)
+	}
+	if want := "dev_overrides entry for example.com/fake/dev"; !strings.Contains(all, want) {
+		t.Errorf("missing warning %q for the overridden provider\nstdout:\n%s\nstderr:\n%s", want, stdout, stderr)
+	}
+	if want := "Summary: 1 providers selected, 1 fully mirrored, 0 partially mirrored, 0 failed."; !strings.Contains(stdout, want) {
+		t.Errorf("missing summary line %q\nstdout:\n%s", want, stdout)
+	}
+
+	// Only the provider that installation policy allows is mirrored.
+	if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash("example.com/fake/foo/terraform-provider-foo_1.0.0_linux_amd64.zip"))); err != nil {
+		t.Errorf("missing mirrored package for fake/foo: %s", err)
+	}
+	for _, typeName := range []string{"bar", "dev"} {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash("example.com/fake/"+typeName))); !os.IsNotExist(err) {
+			t.Errorf("fake/%s was mirrored despite the installation policy (stat error: %v)", typeName, err)
+		}
+	}
+
+	// -ignore-cli-config mirrors everything directly from the origin
+	// registry, ignoring the provider_installation rules.
+	outputDir = t.TempDir()
+	stdout, stderr, err = tf.Run("providers", "mirror", "-ignore-cli-config", "-platform=linux_amd64", outputDir)
+	if err != nil {
+		t.Fatalf("unexpected error with -ignore-cli-config: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	if want := "Summary: 3 providers selected, 3 fully mirrored, 0 partially mirrored, 0 failed."; !strings.Contains(stdout, want) {
+		t.Errorf("missing summary line %q\nstdout:\n%s", want, stdout)
+	}
+	for _, relPath := range []string{
+		"example.com/fake/foo/terraform-provider-foo_1.0.0_linux_amd64.zip",
+		"example.com/fake/bar/terraform-provider-bar_2.1.0_linux_amd64.zip",
+		"example.com/fake/dev/terraform-provider-dev_1.0.0_linux_amd64.zip",
+	} {
+		if _, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(relPath))); err != nil {
+			t.Errorf("missing mirrored package %s: %s", relPath, err)
+		}
+	}
+}
+
+func TestTerraformProvidersMirrorOffline_unpacked(t *testing.T) {
+	t.Parallel()
+
+	registry := newFakeProviderRegistry(t, map[string][]string{
+		"fake/foo": {"1.0.0"},
+		"fake/bar": {"2.1.0"},
+	})
+	defer registry.Close()
+
+	outputDir := t.TempDir()
+	t.Logf("creating mirror directory in %s", outputDir)
+
+	fixturePath := filepath.Join("testdata", "terraform-providers-mirror-offline")
+	tf := e2e.NewBinary(t, terraformBin, fixturePath)
+	tf.AddEnv(registry.CLIConfigEnv(t, "example.com"))
+
+	stdout, stderr, err := tf.Run("providers", "mirror", "-layout=unpacked", "-platform=linux_amd64", outputDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "h1:") {
+		t.Errorf("output does not include an h1: hash for the extracted packages\nstdout:\n%s", stdout)
+	}
+
+	// The unpacked layout contains the extracted plugin executables directly,
+	// with no zip archives and no network mirror JSON indexes, but the
+	// top-level informational index and the signed checksum documents are
+	// still maintained.
+	want := []string{
+		"example.com/fake/bar/2.1.0/linux_amd64/terraform-provider-bar_v2.1.0",
+		"example.com/fake/bar/2.1.0/terraform-provider-bar_2.1.0_SHA256SUMS",
+		"example.com/fake/bar/2.1.0/terraform-provider-bar_2.1.0_SHA256SUMS.sig",
+		"example.com/fake/foo/1.0.0/linux_amd64/terraform-provider-foo_v1.0.0",
+		"example.com/fake/foo/1.0.0/terraform-provider-foo_1.0.0_SHA256SUMS",
+		"example.com/fake/foo/1.0.0/terraform-provider-foo_1.0.0_SHA256SUMS.sig",
+		"providers-index.json",
+	}
+	var got []string
+	walkErr := filepath.Walk(outputDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil // we only care about leaf files for this test
+		}
+		relPath, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		got = append(got, filepath.ToSlash(relPath))
+		return nil
+	})
+	if walkErr != nil {
+		t.Fatal(walkErr)
+	}
+	sort.Strings(got)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected files in result\n%s", diff)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(outputDir, filepath.FromSlash(want[0])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode()&0111 == 0 {
+			t.Errorf("extracted plugin executable has mode %s; want the executable bits preserved", info.Mode())
+		}
+	}
+
+	// A second run with -keep should leave the existing versions alone.
+	stdout, stderr, err = tf.Run("providers", "mirror", "-layout=unpacked", "-keep", "-platform=linux_amd64", outputDir)
+	if err != nil {
+		t.Fatalf("unexpected error on -keep re-run: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
+	}
+	if !strings.Contains(stdout, "already present; skipping") {
+		t.Errorf("-keep re-run did not skip the existing packages\nstdout:\n%s", stdout)
+	}
+
+	// Mirroring the packed layout into the same directory must fail.
+	stdout, stderr, err = tf.Run("providers", "mirror", "-platform=linux_amd64", outputDir)
+	if err == nil {
+		t.Fatalf("unexpected success mixing layouts\nstdout:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "Incompatible mirror directory layout") {
+		t.Errorf("wrong error mixing layouts\nstderr:\n%s", stderr)
+	}
+}
+
+func TestTerraformProvidersMirrorOffline_rateLimited(t *testing.T) {
+	t.Parallel()
+
+	registry := newFakeProviderRegistry(t, map[string][]string{
+		"fake/foo": {"1.0.0"},
+		"fake/bar": {"2.1.0"},
+	})
+	defer registry.Close()
+	registry.RateLimit = true
+
+	fixturePath := filepath.Join("testdata", "terraform-providers-mirror-offline")
+	tf := e2e.NewBinary(t, terraformBin, fixturePath)
+	tf.AddEnv(registry.CLIConfigEnv(t, "example.com"))
+
+	stdout, stderr, err := tf.Run("providers", "mirror", "-platform=linux_amd64", t.TempDir())
+	if err == nil {
+		t.Fatalf("unexpected success; want rate limit error\nstdout:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+}
+
+func TestTerraformProvidersMirrorOffline_badChecksums(t *testing.T) {
+	t.Parallel()
+
+	registry := newFakeProviderRegistry(t, map[string][]string{
+		"fake/foo": {"1.0.0"},
+		"fake/bar": {"2.1.0"},
+	})
+	defer registry.Close()
+	registry.CorruptChecksums = true
+
+	fixturePath := filepath.Join("testdata", "terraform-providers-mirror-offline")
+	tf := e2e.NewBinary(t, terraformBin, fixturePath)
+	tf.AddEnv(registry.CLIConfigEnv(t, "example.com"))
+
+	stdout, stderr, err := tf.Run("providers", "mirror", "-platform=linux_amd64", t.TempDir())
+	if err == nil {
+		t.Fatalf("unexpected success; want checksum verification error\nstdout:\n%s\nstderr:\n%s", stdout, stderr)
+	}
+}
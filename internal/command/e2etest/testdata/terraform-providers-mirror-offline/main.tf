@@ -0,0 +1,12 @@
+terraform {
+  required_providers {
+    foo = {
+      source  = "example.com/fake/foo"
+      version = "1.0.0"
+    }
+    bar = {
+      source  = "example.com/fake/bar"
+      version = "2.1.0"
+    }
+  }
+}
@@ -0,0 +1,16 @@
+terraform {
+  required_providers {
+    foo = {
+      source  = "example.com/fake/foo"
+      version = "1.0.0"
+    }
+    bar = {
+      source  = "example.com/fake/bar"
+      version = "2.1.0"
+    }
+    dev = {
+      source  = "example.com/fake/dev"
+      version = "1.0.0"
+    }
+  }
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -28,12 +29,40 @@ const (
 // files to a canonical format and style.
 type FmtCommand struct {
 	Meta
-	list      bool
-	write     bool
-	diff      bool
-	check     bool
-	recursive bool
-	input     io.Reader // STDIN if nil
+	list       bool
+	write      bool
+	diff       bool
+	check      bool
+	jsonOutput bool
+	recursive  bool
+	input      io.Reader // STDIN if nil
+
+	// styleCache remembers the style rules in effect for each directory
+	// we've already visited, so that we read each .tffmt.hcl file only
+	// once per run.
+	styleCache map[string]*fmtStyleRules
+
+	// checkResults accumulates a machine-readable description of each
+	// file that needs formatting, when running with both -check and
+	// -json.
+	checkResults []fmtCheckResult
+}
+
+// fmtCheckResult describes one file that is not in the expected format,
+// for the JSON output of "terraform fmt -check -json".
+type fmtCheckResult struct {
+	Filename string         `json:"filename"`
+	Changes  []fmtByteRange `json:"changes"`
+}
+
+// fmtByteRange describes a contiguous range of bytes in a source file
+// that fmt would rewrite, along with the replacement text, so that
+// editors and CI integrations can apply or highlight the change without
+// re-running fmt themselves.
+type fmtByteRange struct {
+	StartByte   int    `json:"start_byte"`
+	EndByte     int    `json:"end_byte"`
+	Replacement string `json:"replacement"`
 }
 
 func (c *FmtCommand) Run(args []string) int {
@@ -47,6 +76,7 @@ func (c *FmtCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.write, "write", true, "write")
 	cmdFlags.BoolVar(&c.diff, "diff", false, "diff")
 	cmdFlags.BoolVar(&c.check, "check", false, "check")
+	cmdFlags.BoolVar(&c.jsonOutput, "json", false, "produce JSON output")
 	cmdFlags.BoolVar(&c.recursive, "recursive", false, "recursive")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
@@ -61,6 +91,12 @@ func (c *FmtCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.jsonOutput && !c.check {
+		c.Ui.Error("The -json option can only be used together with -check.")
+		cmdFlags.Usage()
+		return 1
+	}
+
 	var paths []string
 	if len(args) == 0 {
 		paths = []string{"."}
@@ -92,7 +128,26 @@ func (c *FmtCommand) Run(args []string) int {
 	if c.check {
 		buf := output.(*bytes.Buffer)
 		ok := buf.Len() == 0
-		if list {
+		if c.jsonOutput {
+			outcome := struct {
+				FormatVersion string           `json:"format_version"`
+				Correct       bool             `json:"correct"`
+				Files         []fmtCheckResult `json:"files"`
+			}{
+				FormatVersion: "1.0",
+				Correct:       ok,
+				Files:         c.checkResults,
+			}
+			if outcome.Files == nil {
+				outcome.Files = []fmtCheckResult{}
+			}
+			j, err := json.MarshalIndent(&outcome, "", "  ")
+			if err != nil {
+				// Should never happen, since we fully control the input here
+				panic(err)
+			}
+			c.Ui.Output(string(j))
+		} else if list {
 			io.Copy(&cli.UiWriter{Ui: c.Ui}, buf)
 		}
 		if ok {
@@ -176,13 +231,35 @@ func (c *FmtCommand) processFile(path string, r io.Reader, w io.Writer, isStdout
 		return diags
 	}
 
+	// Any additional opt-in style rules for the directory containing this
+	// file apply on top of the standard formatting.
+	styleRules, styleDiags := c.styleRulesForDir(filepath.Dir(path))
+	diags = diags.Append(styleDiags)
+	if styleDiags.HasErrors() {
+		return diags
+	}
+
 	result := c.formatSourceCode(src, path)
+	if styleRules != nil {
+		if styled := styleRules.apply(result, path); !bytes.Equal(styled, result) {
+			// Moving lines around can leave argument alignment behind, so
+			// we re-canonicalize the result. This never reorders anything,
+			// so the style rules still hold afterwards.
+			result = c.formatSourceCode(styled, path)
+		}
+	}
 
 	if !bytes.Equal(src, result) {
 		// Something was changed
 		if c.list {
 			fmt.Fprintln(w, path)
 		}
+		if c.jsonOutput {
+			c.checkResults = append(c.checkResults, fmtCheckResult{
+				Filename: path,
+				Changes:  fmtChangedRanges(src, result),
+			})
+		}
 		if c.write {
 			err := ioutil.WriteFile(path, result, 0644)
 			if err != nil {
@@ -538,6 +615,11 @@ Usage: terraform [global options] fmt [options] [DIR]
 	If DIR is "-" then content will be read from STDIN. The given content must
 	be in the Terraform language native syntax; JSON is not supported.
 
+	If a file named .tffmt.hcl exists in the directory containing a file, or
+	in one of its parent directories, then fmt also applies the additional
+	opt-in style rules it describes, such as sorting the arguments of
+	particular block types.
+
 Options:
 
   -list=false    Don't list files whose formatting differs
@@ -551,6 +633,10 @@ Options:
   -check         Check if the input is formatted. Exit status will be 0 if all
                  input is properly formatted and non-zero otherwise.
 
+  -json          Produce a machine-readable JSON description of which files
+                 differ and of the byte ranges that fmt would change in each
+                 one. Can be used only together with -check.
+
   -no-color      If specified, output won't contain any color.
 
   -recursive     Also process files in subdirectories. By default, only the
@@ -563,6 +649,28 @@ func (c *FmtCommand) Synopsis() string {
 	return "Reformat your configuration in the standard style"
 }
 
+// fmtChangedRanges returns the byte ranges of src that fmt would rewrite
+// to produce result, for the JSON output of -check -json. The bytes
+// outside the returned ranges are identical in both versions; currently
+// the result is always a single contiguous range.
+func fmtChangedRanges(src, result []byte) []fmtByteRange {
+	prefix := 0
+	for prefix < len(src) && prefix < len(result) && src[prefix] == result[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(src)-prefix && suffix < len(result)-prefix && src[len(src)-1-suffix] == result[len(result)-1-suffix] {
+		suffix++
+	}
+	return []fmtByteRange{
+		{
+			StartByte:   prefix,
+			EndByte:     len(src) - suffix,
+			Replacement: string(result[prefix : len(result)-suffix]),
+		},
+	}
+}
+
 func bytesDiff(b1, b2 []byte, path string) (data []byte, err error) {
 	f1, err := ioutil.TempFile("", "")
 	if err != nil {
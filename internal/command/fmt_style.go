@@ -0,0 +1,302 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// fmtStyleFilename is the name of the optional per-directory file that
+// enables additional style rules for "terraform fmt". These rules are
+// strictly opt-in: without such a file, fmt applies only the standard
+// canonical formatting.
+const fmtStyleFilename = ".tffmt.hcl"
+
+// fmtStyleRules represents the additional style rules decoded from a
+// .tffmt.hcl file. All of the rules are optional, and each one that is
+// unset leaves the standard formatting behavior unchanged.
+type fmtStyleRules struct {
+	// SortArguments gives the block type names whose directly-nested
+	// arguments fmt will sort into lexical order. Sorting only reorders
+	// runs of consecutive arguments, so blank lines and nested blocks
+	// act as grouping boundaries that sorting will not cross.
+	SortArguments []string `hcl:"sort_arguments,optional"`
+
+	// MaxBlankLines, if set, is the maximum number of consecutive blank
+	// lines fmt will retain. Longer runs are reduced to this many.
+	MaxBlankLines *int `hcl:"max_blank_lines,optional"`
+}
+
+// loadStyleRules reads and decodes the style rules file at the given path.
+func loadStyleRules(path string) (*fmtStyleRules, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to read style rules file %s: %s", path, err))
+		return nil, diags
+	}
+
+	f, syntaxDiags := hclsyntax.ParseConfig(src, path, hcl.Pos{Line: 1, Column: 1})
+	diags = diags.Append(syntaxDiags)
+	if syntaxDiags.HasErrors() {
+		return nil, diags
+	}
+
+	rules := &fmtStyleRules{}
+	decodeDiags := gohcl.DecodeBody(f.Body, nil, rules)
+	diags = diags.Append(decodeDiags)
+	if decodeDiags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, name := range rules.SortArguments {
+		if !hclsyntax.ValidIdentifier(name) {
+			diags = diags.Append(fmt.Errorf("Invalid block type %q in sort_arguments in %s: must be a valid identifier", name, path))
+		}
+	}
+	if rules.MaxBlankLines != nil && *rules.MaxBlankLines < 0 {
+		diags = diags.Append(fmt.Errorf("Invalid max_blank_lines in %s: must be at least zero", path))
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return rules, diags
+}
+
+// styleRulesForDir returns the style rules in effect for files in the
+// given directory, searching the directory itself and then each of its
+// parents in turn for a style rules file. The result is nil, with no
+// diagnostics, if no such file exists; diagnostics are returned only the
+// first time a particular file is loaded.
+func (c *FmtCommand) styleRulesForDir(dir string) (*fmtStyleRules, tfdiags.Diagnostics) {
+	if c.styleCache == nil {
+		c.styleCache = make(map[string]*fmtStyleRules)
+	}
+	if rules, ok := c.styleCache[dir]; ok {
+		return rules, nil
+	}
+
+	var rules *fmtStyleRules
+	var diags tfdiags.Diagnostics
+	candidate := filepath.Join(dir, fmtStyleFilename)
+	if _, err := os.Stat(candidate); err == nil {
+		rules, diags = loadStyleRules(candidate)
+	} else if parent := filepath.Dir(dir); parent != dir {
+		rules, diags = c.styleRulesForDir(parent)
+	}
+
+	c.styleCache[dir] = rules
+	return rules, diags
+}
+
+// apply rewrites already-canonically-formatted source code to conform to
+// the additional style rules. The input must be valid configuration
+// syntax; if it is not then it is returned unchanged.
+func (r *fmtStyleRules) apply(src []byte, filename string) []byte {
+	if len(r.SortArguments) > 0 {
+		src = r.sortArguments(src, filename)
+	}
+	if r.MaxBlankLines != nil {
+		src = r.limitBlankLines(src, filename)
+	}
+	return src
+}
+
+// sortArguments reorders the arguments directly inside blocks of the
+// types named in SortArguments into lexical order by argument name.
+//
+// This works on whole lines of the source rather than on tokens so that
+// each argument keeps its trailing comment and any comment lines
+// immediately above it. Only runs of consecutive argument lines are
+// reordered: a blank line or a nested block ends the current run, so
+// authors can still use deliberate grouping.
+func (r *fmtStyleRules) sortArguments(src []byte, filename string) []byte {
+	f, parseDiags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		return src
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return src
+	}
+
+	lines := splitSourceLines(src)
+
+	sortTypes := make(map[string]bool, len(r.SortArguments))
+	for _, name := range r.SortArguments {
+		sortTypes[name] = true
+	}
+
+	var walk func(body *hclsyntax.Body)
+	walk = func(body *hclsyntax.Body) {
+		for _, block := range body.Blocks {
+			if sortTypes[block.Type] {
+				sortBodyArguments(lines, block.Body)
+			}
+			walk(block.Body)
+		}
+	}
+	walk(body)
+
+	return bytes.Join(lines, nil)
+}
+
+// fmtArgumentItem records the full extent of one argument within a file,
+// including any comment lines attached immediately above it.
+type fmtArgumentItem struct {
+	name      string
+	startLine int // 1-based, inclusive
+	endLine   int // 1-based, inclusive
+}
+
+// sortBodyArguments sorts the arguments of a single block body in place
+// in the given line slice. Because each run of arguments is replaced by
+// a permutation of its own lines, the line numbering of everything else
+// in the file is unaffected.
+func sortBodyArguments(lines [][]byte, body *hclsyntax.Body) {
+	// We track which lines each item of the body covers so that we can
+	// detect layouts we mustn't reorder, such as several arguments
+	// sharing a line inside a one-line block.
+	claimed := make(map[int]bool)
+	for _, block := range body.Blocks {
+		for line := block.Range().Start.Line; line <= block.Range().End.Line; line++ {
+			claimed[line] = true
+		}
+	}
+
+	var items []fmtArgumentItem
+	for _, attr := range body.Attributes {
+		start := attr.SrcRange.Start.Line
+		end := attr.SrcRange.End.Line
+		for line := start; line <= end; line++ {
+			if claimed[line] {
+				// An unexpected layout, such as an argument sharing a
+				// line with something else. Leave this body alone.
+				return
+			}
+			claimed[line] = true
+		}
+		items = append(items, fmtArgumentItem{attr.Name, start, end})
+	}
+	if len(items) < 2 {
+		return
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].startLine < items[j].startLine
+	})
+
+	// Comment lines immediately above an argument move along with it.
+	for i := range items {
+		for line := items[i].startLine - 1; line >= 1 && !claimed[line] && isCommentLine(lines[line-1]); line-- {
+			claimed[line] = true
+			items[i].startLine = line
+		}
+	}
+
+	// Split the items into runs of consecutive arguments, then sort each
+	// run independently.
+	runStart := 0
+	for i := 1; i <= len(items); i++ {
+		if i < len(items) && items[i].startLine == items[i-1].endLine+1 {
+			continue
+		}
+		sortArgumentRun(lines, items[runStart:i])
+		runStart = i
+	}
+}
+
+// sortArgumentRun replaces the lines covered by the given run of
+// arguments with the same lines reordered so that the arguments appear
+// in lexical order by name.
+func sortArgumentRun(lines [][]byte, run []fmtArgumentItem) {
+	if len(run) < 2 {
+		return
+	}
+	sorted := make([]fmtArgumentItem, len(run))
+	copy(sorted, run)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+
+	var newLines [][]byte
+	for _, item := range sorted {
+		newLines = append(newLines, lines[item.startLine-1:item.endLine]...)
+	}
+	copy(lines[run[0].startLine-1:], newLines)
+}
+
+// limitBlankLines reduces any run of consecutive blank lines longer than
+// MaxBlankLines down to exactly MaxBlankLines, leaving blank lines that
+// appear inside heredoc templates untouched.
+func (r *fmtStyleRules) limitBlankLines(src []byte, filename string) []byte {
+	max := *r.MaxBlankLines
+
+	tokens, lexDiags := hclsyntax.LexConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if lexDiags.HasErrors() {
+		return src
+	}
+
+	// Lines inside heredocs are part of a string value, so blank lines
+	// there are significant and must be preserved.
+	protected := make(map[int]bool)
+	heredocStart := 0
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOHeredoc:
+			heredocStart = token.Range.Start.Line
+		case hclsyntax.TokenCHeredoc:
+			for line := heredocStart; line <= token.Range.End.Line; line++ {
+				protected[line] = true
+			}
+			heredocStart = 0
+		}
+	}
+
+	lines := splitSourceLines(src)
+	var buf bytes.Buffer
+	blanks := 0
+	for i, line := range lines {
+		lineNum := i + 1
+		if !protected[lineNum] && len(bytes.TrimSpace(line)) == 0 {
+			blanks++
+			if blanks > max {
+				continue
+			}
+		} else {
+			blanks = 0
+		}
+		buf.Write(line)
+	}
+	return buf.Bytes()
+}
+
+// splitSourceLines splits source code into lines, with each line
+// retaining its terminating newline so that joining the lines with no
+// separator reproduces the original bytes.
+func splitSourceLines(src []byte) [][]byte {
+	var lines [][]byte
+	for len(src) > 0 {
+		idx := bytes.IndexByte(src, '\n')
+		if idx < 0 {
+			lines = append(lines, src)
+			break
+		}
+		lines = append(lines, src[:idx+1])
+		src = src[idx+1:]
+	}
+	return lines
+}
+
+func isCommentLine(line []byte) bool {
+	trimmed := bytes.TrimSpace(line)
+	return bytes.HasPrefix(trimmed, []byte("#")) || bytes.HasPrefix(trimmed, []byte("//"))
+}
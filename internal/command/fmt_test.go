@@ -2,6 +2,7 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -401,3 +402,201 @@ func fmtFixtureWriteDir(t *testing.T) string {
 
 	return dir
 }
+
+func TestFmt_styleRules(t *testing.T) {
+	tempDir := testTempDir(t)
+
+	styleSrc := `
+sort_arguments  = ["variable"]
+max_blank_lines = 1
+`
+	inputSrc := `variable "example" {
+  type    = string
+  default = "a"
+
+  # Comments travel with the argument below them.
+  sensitive   = true
+  description = "example"
+}
+
+
+
+locals {
+  b = 2
+  a = 1
+}
+`
+	want := `variable "example" {
+  default = "a"
+  type    = string
+
+  description = "example"
+  # Comments travel with the argument below them.
+  sensitive = true
+}
+
+locals {
+  b = 2
+  a = 1
+}
+`
+
+	err := ioutil.WriteFile(filepath.Join(tempDir, ".tffmt.hcl"), []byte(styleSrc), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(tempDir, "main.tf"), []byte(inputSrc), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{tempDir}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("wrong exit code. errors: \n%s", ui.ErrorWriter.String())
+	}
+
+	got, err := ioutil.ReadFile(filepath.Join(tempDir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	// A second run must be a no-op, since the style rules are designed to
+	// produce stable output.
+	ui = new(cli.MockUi)
+	c = &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+	args = []string{"-check", tempDir}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("style rules are not stable:\n%s\n%s", ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+}
+
+func TestFmt_styleRulesInvalid(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	err := ioutil.WriteFile(filepath.Join(tempDir, ".tffmt.hcl"), []byte(`nonsense = true`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{tempDir}
+	if code := c.Run(args); code != 2 {
+		t.Fatalf("wrong exit code. errors: \n%s", ui.ErrorWriter.String())
+	}
+
+	expected := "Unsupported argument"
+	if actual := ui.ErrorWriter.String(); !strings.Contains(actual, expected) {
+		t.Fatalf("expected:\n%s\n\nto include: %q", actual, expected)
+	}
+}
+
+func TestFmt_checkJSON(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{
+		"-check",
+		"-json",
+		tempDir,
+	}
+	if code := c.Run(args); code != 3 {
+		t.Fatalf("wrong exit code. expected 3")
+	}
+
+	var outcome struct {
+		FormatVersion string `json:"format_version"`
+		Correct       bool   `json:"correct"`
+		Files         []struct {
+			Filename string `json:"filename"`
+			Changes  []struct {
+				StartByte   int    `json:"start_byte"`
+				EndByte     int    `json:"end_byte"`
+				Replacement string `json:"replacement"`
+			} `json:"changes"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &outcome); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+
+	if outcome.Correct {
+		t.Errorf("unexpected \"correct\": true for an unformatted file")
+	}
+	if len(outcome.Files) != 1 {
+		t.Fatalf("wrong number of files %d; want 1", len(outcome.Files))
+	}
+	file := outcome.Files[0]
+	if want := c.normalizePath(filepath.Join(tempDir, fmtFixture.filename)); file.Filename != want {
+		t.Errorf("wrong filename %q; want %q", file.Filename, want)
+	}
+	if len(file.Changes) != 1 {
+		t.Fatalf("wrong number of changes %d; want 1", len(file.Changes))
+	}
+
+	// Applying the reported change to the input must produce the same
+	// result that fmt itself would write.
+	change := file.Changes[0]
+	input := string(fmtFixture.input)
+	if change.StartByte < 0 || change.EndByte > len(input) || change.StartByte > change.EndByte {
+		t.Fatalf("change has invalid byte range %d..%d", change.StartByte, change.EndByte)
+	}
+	patched := input[:change.StartByte] + change.Replacement + input[change.EndByte:]
+	if want := string(fmtFixture.golden); patched != want {
+		t.Errorf("applying the reported change gave %q; want %q", patched, want)
+	}
+}
+
+func TestFmt_jsonWithoutCheck(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{
+		"-json",
+		tempDir,
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("wrong exit code. expected 1, got %d", code)
+	}
+
+	expected := "can only be used together with -check"
+	if actual := ui.ErrorWriter.String(); !strings.Contains(actual, expected) {
+		t.Fatalf("expected:\n%s\n\nto include: %q", actual, expected)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/mitchellh/colorstring"
+	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
@@ -36,6 +37,15 @@ const (
 	DiffLanguageDetectedDrift DiffLanguage = 'D'
 )
 
+// DiffOpts are optional settings that adjust how ResourceChange renders
+// a change.
+type DiffOpts struct {
+	// RawStringDiffs disables the special rendering of string values that
+	// parse as JSON or YAML documents, which normally shows a structural
+	// diff of the decoded value instead of a plain string diff.
+	RawStringDiffs bool
+}
+
 // ResourceChange returns a string representation of a change to a particular
 // resource, for inclusion in user-facing plan output.
 //
@@ -50,6 +60,7 @@ func ResourceChange(
 	schema *configschema.Block,
 	color *colorstring.Colorize,
 	language DiffLanguage,
+	opts DiffOpts,
 ) string {
 	addr := change.Addr
 	var buf bytes.Buffer
@@ -188,6 +199,7 @@ func ResourceChange(
 		color:           color,
 		action:          change.Action,
 		requiredReplace: change.RequiredReplace,
+		rawStrings:      opts.RawStringDiffs,
 	}
 
 	// Most commonly-used resources have nested blocks that result in us
@@ -264,6 +276,9 @@ type blockBodyDiffPrinter struct {
 	requiredReplace cty.PathSet
 	// verbose is set to true when using the "diff" printer to format state
 	verbose bool
+	// rawStrings disables the structural rendering of string values that
+	// parse as JSON or YAML documents
+	rawStrings bool
 }
 
 type blockBodyDiffResult struct {
@@ -1025,7 +1040,7 @@ func (p *blockBodyDiffPrinter) writeValue(val cty.Value, action plans.Action, in
 	case ty.IsPrimitiveType():
 		switch ty {
 		case cty.String:
-			{
+			if !p.rawStrings {
 				// Special behavior for JSON strings containing array or object
 				src := []byte(val.AsString())
 				ty, err := ctyjson.ImpliedType(src)
@@ -1050,6 +1065,17 @@ func (p *blockBodyDiffPrinter) writeValue(val cty.Value, action plans.Action, in
 				}
 			}
 
+			if yv, ok := p.yamlStructuralValue(val.AsString()); ok {
+				p.buf.WriteString("yamlencode(")
+				p.buf.WriteByte('\n')
+				p.buf.WriteString(strings.Repeat(" ", indent+4))
+				p.writeValue(yv, action, indent+4)
+				p.buf.WriteByte('\n')
+				p.buf.WriteString(strings.Repeat(" ", indent))
+				p.buf.WriteByte(')')
+				break // don't *also* do the normal behavior below
+			}
+
 			if strings.Contains(val.AsString(), "\n") {
 				// It's a multi-line string, so we want to use the multi-line
 				// rendering so it'll be readable. Rather than re-implement
@@ -1162,6 +1188,28 @@ func (p *blockBodyDiffPrinter) writeValue(val cty.Value, action plans.Action, in
 	}
 }
 
+// yamlStructuralValue attempts to interpret the given string as a YAML
+// document describing a non-primitive value, returning the decoded value if
+// so. YAML is permissive enough that almost any string parses as a YAML
+// scalar, so to reduce false positives this applies only to multi-line
+// strings whose documents decode to a mapping or a sequence, and not at all
+// when the printer is configured for raw string diffs.
+func (p *blockBodyDiffPrinter) yamlStructuralValue(s string) (cty.Value, bool) {
+	if p.rawStrings || !strings.Contains(s, "\n") {
+		return cty.NilVal, false
+	}
+	src := []byte(s)
+	ty, err := ctyyaml.ImpliedType(src)
+	if err != nil || !(ty.IsObjectType() || ty.IsTupleType()) {
+		return cty.NilVal, false
+	}
+	v, err := ctyyaml.Unmarshal(src, ty)
+	if err != nil {
+		return cty.NilVal, false
+	}
+	return v, true
+}
+
 func (p *blockBodyDiffPrinter) writeValueDiff(old, new cty.Value, indent int, path cty.Path) {
 	ty := old.Type()
 	typesEqual := ctyTypesEqual(ty, new.Type())
@@ -1191,7 +1239,7 @@ func (p *blockBodyDiffPrinter) writeValueDiff(old, new cty.Value, indent int, pa
 			oldS := old.AsString()
 			newS := new.AsString()
 
-			{
+			if !p.rawStrings {
 				// Special behavior for JSON strings containing object or
 				// list values.
 				oldBytes := []byte(oldS)
@@ -1232,6 +1280,43 @@ func (p *blockBodyDiffPrinter) writeValueDiff(old, new cty.Value, indent int, pa
 				}
 			}
 
+			{
+				// Special behavior for YAML strings containing mapping or
+				// sequence documents, in the same spirit as the JSON handling
+				// above. Note that any valid JSON document is also valid YAML,
+				// so strings that parse as JSON are always handled by the
+				// JSON-flavored rendering first.
+				oldYV, oldOk := p.yamlStructuralValue(oldS)
+				newYV, newOk := p.yamlStructuralValue(newS)
+				if oldOk && newOk {
+					if !oldYV.RawEquals(newYV) { // two YAML documents may differ only in formatting
+						p.buf.WriteString("yamlencode(")
+						p.buf.WriteByte('\n')
+						p.buf.WriteString(strings.Repeat(" ", indent+2))
+						p.writeActionSymbol(plans.Update)
+						p.writeValueDiff(oldYV, newYV, indent+4, path)
+						p.buf.WriteByte('\n')
+						p.buf.WriteString(strings.Repeat(" ", indent))
+						p.buf.WriteByte(')')
+					} else {
+						// if they differ only in formatting then we'll note
+						// that but still expand out the effective value.
+						if p.pathForcesNewResource(path) {
+							p.buf.WriteString(p.color.Color("yamlencode( [red]# formatting changes force replacement[reset]"))
+						} else {
+							p.buf.WriteString(p.color.Color("yamlencode( [dim]# formatting changes[reset]"))
+						}
+						p.buf.WriteByte('\n')
+						p.buf.WriteString(strings.Repeat(" ", indent+4))
+						p.writeValue(oldYV, plans.NoOp, indent+4)
+						p.buf.WriteByte('\n')
+						p.buf.WriteString(strings.Repeat(" ", indent))
+						p.buf.WriteByte(')')
+					}
+					return
+				}
+			}
+
 			if !strings.Contains(oldS, "\n") && !strings.Contains(newS, "\n") {
 				break
 			}
@@ -19,6 +19,7 @@ func (c *GetCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("get")
 	cmdFlags.BoolVar(&update, "update", false, "update")
+	cmdFlags.BoolVar(&c.Meta.allowYankedModules, "allow-yanked-modules", false, "allow selection of yanked registry module versions")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -63,6 +64,10 @@ Options:
   -update             Check already-downloaded modules for available updates
                       and install the newest versions available.
 
+  -allow-yanked-modules  Allow installing registry module versions that the
+                         registry has yanked, which Terraform otherwise
+                         refuses to newly select.
+
   -no-color           Disable text coloring in the output.
 
 `
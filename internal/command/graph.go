@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/dag"
 	"github.com/hashicorp/terraform/internal/plans"
 	"github.com/hashicorp/terraform/internal/plans/planfile"
@@ -24,6 +25,8 @@ func (c *GraphCommand) Run(args []string) int {
 	var moduleDepth int
 	var verbose bool
 	var planPath string
+	var format string
+	var impact bool
 
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("graph")
@@ -32,12 +35,34 @@ func (c *GraphCommand) Run(args []string) int {
 	cmdFlags.IntVar(&moduleDepth, "module-depth", -1, "module-depth")
 	cmdFlags.BoolVar(&verbose, "verbose", false, "verbose")
 	cmdFlags.StringVar(&planPath, "plan", "", "plan")
+	cmdFlags.StringVar(&format, "format", "dot", "format")
+	cmdFlags.BoolVar(&impact, "impact", false, "impact")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
 	}
 
+	if format != "dot" && format != "json" {
+		c.Ui.Error(`The -format=... argument must be either "dot" or "json".`)
+		return 1
+	}
+
+	if impact {
+		if planPath == "" {
+			c.Ui.Error("The -impact option renders the changes from a saved plan file, so it requires the -plan=... option.")
+			return 1
+		}
+		if format != "dot" {
+			c.Ui.Error("The -impact option is supported only with the default DOT format.")
+			return 1
+		}
+		if graphTypeStr != "" && graphTypeStr != "apply" {
+			c.Ui.Error(`The -impact option is supported only with the default "apply" graph type.`)
+			return 1
+		}
+	}
+
 	configPath, err := ModulePath(cmdFlags.Args())
 	if err != nil {
 		c.Ui.Error(err.Error())
@@ -91,7 +116,7 @@ func (c *GraphCommand) Run(args []string) int {
 	c.ignoreRemoteVersionConflict(b)
 
 	// Build the operation
-	opReq := c.Operation(b)
+	opReq := c.Operation(b, arguments.ViewHuman)
 	opReq.ConfigDir = configPath
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	opReq.PlanFile = planFile
@@ -167,14 +192,31 @@ func (c *GraphCommand) Run(args []string) int {
 		return 1
 	}
 
-	graphStr, err := terraform.GraphDot(g, &dag.DotOpts{
-		DrawCycles: drawCycles,
-		MaxDepth:   moduleDepth,
-		Verbose:    verbose,
-	})
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
-		return 1
+	var graphStr string
+	switch {
+	case impact:
+		graphStr, err = terraform.GraphImpactDot(g, lr.Plan)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
+			return 1
+		}
+	case format == "json":
+		j, err := terraform.GraphJSON(g)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
+			return 1
+		}
+		graphStr = string(j)
+	default:
+		graphStr, err = terraform.GraphDot(g, &dag.DotOpts{
+			DrawCycles: drawCycles,
+			MaxDepth:   moduleDepth,
+			Verbose:    verbose,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
+			return 1
+		}
 	}
 
 	if diags.HasErrors() {
@@ -197,15 +239,26 @@ Usage: terraform [global options] graph [options]
   Produces a representation of the dependency graph between different
   objects in the current configuration and state.
 
-  The graph is presented in the DOT language. The typical program that can
-  read this format is GraphViz, but many web services are also available
-  to read this format.
+  The graph is presented in the DOT language by default. The typical program
+  that can read this format is GraphViz, but many web services are also
+  available to read this format. Use -format=json to instead produce a
+  machine-readable JSON description of the nodes and edges, for consumption
+  by external analysis tools.
 
 Options:
 
   -plan=tfplan     Render graph using the specified plan file instead of the
                    configuration in the current directory.
 
+  -format=dot      Output format. Can be "dot" (the default) or "json".
+                   The -draw-cycles and -verbose options apply only to the
+                   DOT format.
+
+  -impact          Render only the subgraph impacted by the changes in the
+                   given plan file: the resource instances with planned
+                   actions, annotated with those actions, and everything
+                   that depends on them. Requires the -plan=... option.
+
   -draw-cycles     Highlight any cycles in the graph with colored edges.
                    This helps when diagnosing cycle errors.
 
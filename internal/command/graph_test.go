@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -37,6 +38,91 @@ func TestGraph(t *testing.T) {
 	}
 }
 
+func TestGraph_json(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("graph"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{"-format=json"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	var got struct {
+		FormatVersion string `json:"format_version"`
+		Nodes         []struct {
+			Name     string `json:"name"`
+			Type     string `json:"type"`
+			Address  string `json:"address"`
+			Provider string `json:"provider"`
+		} `json:"nodes"`
+		Edges []struct {
+			Source string `json:"source"`
+			Target string `json:"target"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("output isn't valid JSON: %s\n%s", err, ui.OutputWriter.String())
+	}
+
+	if got.FormatVersion != "1.0" {
+		t.Errorf("wrong format_version %q; want %q", got.FormatVersion, "1.0")
+	}
+	if len(got.Edges) == 0 {
+		t.Errorf("graph has no edges")
+	}
+
+	foundResource := false
+	foundProvider := false
+	for _, node := range got.Nodes {
+		switch {
+		case node.Type == "resource" && node.Address == "test_instance.foo":
+			foundResource = true
+			if want := "registry.terraform.io/hashicorp/test"; node.Provider != want {
+				t.Errorf("wrong provider %q for %s; want %q", node.Provider, node.Address, want)
+			}
+		case node.Type == "provider":
+			foundProvider = true
+		}
+	}
+	if !foundResource {
+		t.Errorf("no resource node for test_instance.foo in output:\n%s", ui.OutputWriter.String())
+	}
+	if !foundProvider {
+		t.Errorf("no provider node in output:\n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestGraph_badFormat(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("graph"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{"-format=yaml"}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("expected error, got:\n%s", ui.OutputWriter.String())
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), `must be either "dot" or "json"`) {
+		t.Fatalf("expected format error, got:\n%s", ui.ErrorWriter.String())
+	}
+}
+
 func TestGraph_multipleArgs(t *testing.T) {
 	ui := new(cli.MockUi)
 	c := &GraphCommand{
@@ -157,3 +243,101 @@ func TestGraph_plan(t *testing.T) {
 		t.Fatalf("doesn't look like digraph: %s", output)
 	}
 }
+
+func TestGraph_impact(t *testing.T) {
+	testCwd(t)
+
+	objTy := cty.Object(map[string]cty.Type{
+		"id":  cty.String,
+		"ami": cty.String,
+	})
+	before, err := plans.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.StringVal("foo"),
+		"ami": cty.StringVal("bar"),
+	}), objTy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := plans.NewDynamicValue(cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.StringVal("foo"),
+		"ami": cty.StringVal("baz"),
+	}), objTy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &plans.Plan{
+		Changes: plans.NewChanges(),
+	}
+	plan.Changes.Resources = append(plan.Changes.Resources, &plans.ResourceInstanceChangeSrc{
+		Addr: addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: "foo",
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		ChangeSrc: plans.ChangeSrc{
+			Action: plans.Update,
+			Before: before,
+			After:  after,
+		},
+		ProviderAddr: addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+	})
+	emptyConfig, err := plans.NewDynamicValue(cty.EmptyObjectVal, cty.EmptyObject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Backend = plans.Backend{
+		Type:   "placeholder",
+		Config: emptyConfig,
+	}
+	_, configSnap := testModuleWithSnapshot(t, "graph")
+
+	planPath := testPlanFile(t, configSnap, states.NewState(), plan)
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{
+		"-plan", planPath,
+		"-impact",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if want := `label = "test_instance.foo (update)"`; !strings.Contains(output, want) {
+		t.Fatalf("missing annotated change node %s in output: %s", want, output)
+	}
+	if dontWant := `provider[\"registry.terraform.io/hashicorp/test\"]`; strings.Contains(output, dontWant) {
+		t.Fatalf("provider node %s should not be in the impact output: %s", dontWant, output)
+	}
+}
+
+func TestGraph_impactNoPlan(t *testing.T) {
+	testCwd(t)
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"-impact"}); code != 1 {
+		t.Fatalf("expected error, got:\n%s", ui.OutputWriter.String())
+	}
+
+	output := ui.ErrorWriter.String()
+	if want := "requires the -plan=... option"; !strings.Contains(output, want) {
+		t.Fatalf("expected error containing %q, got:\n%s", want, output)
+	}
+}
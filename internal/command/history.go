@@ -0,0 +1,104 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/command/workdir"
+)
+
+// HistoryCommand is a Command implementation that lists the recent
+// operations recorded in the working directory's local operation history.
+type HistoryCommand struct {
+	Meta
+}
+
+func (c *HistoryCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("history")
+	var jsonOutput bool
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	entries, err := c.WorkingDir.ReadOperationHistory()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if jsonOutput {
+		buf, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal operation history to json: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(buf))
+		return 0
+	}
+
+	if len(entries) == 0 {
+		c.Ui.Output("No operation history has been recorded in this working directory.")
+		return 0
+	}
+
+	// Most recent first, since that's what someone reconstructing a recent
+	// failure will want to see at the top.
+	for i := len(entries) - 1; i >= 0; i-- {
+		c.Ui.Output(formatHistoryEntry(entries[i]))
+	}
+
+	return 0
+}
+
+// formatHistoryEntry renders a single operation history entry as one line
+// of human-oriented output.
+func formatHistoryEntry(entry *workdir.OperationHistoryEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %-8s %s", entry.Timestamp.Local().Format("2006-01-02 15:04:05"), entry.Command, entry.Result)
+	if entry.Added > 0 || entry.Changed > 0 || entry.Removed > 0 {
+		fmt.Fprintf(&b, " (%d added, %d changed, %d destroyed)", entry.Added, entry.Changed, entry.Removed)
+	}
+	if entry.Options != "" {
+		fmt.Fprintf(&b, " [%s]", entry.Options)
+	}
+	if entry.DiagnosticsDigest != "" {
+		fmt.Fprintf(&b, "\n    %s", entry.DiagnosticsDigest)
+	}
+	return b.String()
+}
+
+func (c *HistoryCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] history [options]
+
+  Lists the recent operations recorded in this working directory's local
+  operation history, most recent first.
+
+  Terraform records a bounded history of recent operations (such as plan
+  and apply) under .terraform/history, including when each operation ran,
+  a summary of its options, and how it finished. The history never
+  contains variable values or state data, and recording can be disabled
+  entirely with the disable_operation_history setting in the CLI
+  configuration.
+
+  Use "terraform show -last" to display the full saved summary of the most
+  recent operation.
+
+Options:
+
+  -json     Produce output in a machine-readable JSON format, listing the
+            recorded operations oldest first.
+
+  -no-color If specified, output won't contain any color.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *HistoryCommand) Synopsis() string {
+	return "Show the recent operations run in this working directory"
+}
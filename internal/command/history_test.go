@@ -0,0 +1,125 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/command/workdir"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/mitchellh/cli"
+)
+
+func TestHistory_empty(t *testing.T) {
+	wd := workdir.NewDir(t.TempDir())
+
+	ui := cli.NewMockUi()
+	c := &HistoryCommand{
+		Meta: Meta{
+			Ui:         ui,
+			WorkingDir: wd,
+		},
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), "No operation history"; !strings.Contains(got, want) {
+		t.Fatalf("missing %q in output:\n%s", want, got)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	wd := workdir.NewDir(t.TempDir())
+
+	m := Meta{
+		Ui:         cli.NewMockUi(),
+		WorkingDir: wd,
+	}
+
+	var diags tfdiags.Diagnostics
+	diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Something broke", "It broke badly."))
+	m.recordOperationHistory(&workdir.OperationHistoryEntry{
+		Command: "plan",
+		Options: "-out",
+		Result:  "failure",
+	}, diags)
+	m.recordOperationHistory(&workdir.OperationHistoryEntry{
+		Command: "apply",
+		Result:  "success",
+		Added:   2,
+		Changed: 1,
+	}, nil)
+
+	ui := cli.NewMockUi()
+	c := &HistoryCommand{
+		Meta: Meta{
+			Ui:         ui,
+			WorkingDir: wd,
+		},
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		"apply",
+		"success (2 added, 1 changed, 0 destroyed)",
+		"plan",
+		"failure [-out]",
+		"first error: Something broke",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in output:\n%s", want, output)
+		}
+	}
+
+	// The most recent operation must be listed first.
+	if strings.Index(output, "apply") > strings.Index(output, "plan") {
+		t.Errorf("entries are not listed most recent first:\n%s", output)
+	}
+
+	// The "show -last" command displays the saved summary of the most
+	// recent operation.
+	showUi := cli.NewMockUi()
+	showView, _ := testView(t)
+	sc := &ShowCommand{
+		Meta: Meta{
+			Ui:         showUi,
+			View:       showView,
+			WorkingDir: wd,
+		},
+	}
+	if code := sc.Run([]string{"-last"}); code != 0 {
+		t.Fatalf("bad: \n%s", showUi.ErrorWriter.String())
+	}
+	showOutput := showUi.OutputWriter.String()
+	for _, want := range []string{`"command": "apply"`, `"added": 2`} {
+		if !strings.Contains(showOutput, want) {
+			t.Errorf("missing %q in show -last output:\n%s", want, showOutput)
+		}
+	}
+}
+
+func TestHistory_disabled(t *testing.T) {
+	wd := workdir.NewDir(t.TempDir())
+
+	m := Meta{
+		Ui:                       cli.NewMockUi(),
+		WorkingDir:               wd,
+		OperationHistoryDisabled: true,
+	}
+	m.recordOperationHistory(&workdir.OperationHistoryEntry{
+		Command: "plan",
+		Result:  "success",
+	}, nil)
+
+	entries, err := wd.ReadOperationHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("recorded %d entries; want none because history is disabled", len(entries))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform/internal/command/views"
 	"github.com/hashicorp/terraform/internal/initwd"
 	"github.com/mitchellh/cli"
 )
@@ -12,11 +13,19 @@ type uiModuleInstallHooks struct {
 	initwd.ModuleInstallHooksImpl
 	Ui             cli.Ui
 	ShowLocalPaths bool
+
+	// View, if non-nil, receives typed messages for each event instead of
+	// the free-form Ui text, for commands running in JSON output mode.
+	View views.Init
 }
 
 var _ initwd.ModuleInstallHooks = uiModuleInstallHooks{}
 
 func (h uiModuleInstallHooks) Download(modulePath, packageAddr string, v *version.Version) {
+	if h.View != nil {
+		h.View.ModuleDownload(modulePath, packageAddr, versionString(v))
+		return
+	}
 	if v != nil {
 		h.Ui.Info(fmt.Sprintf("Downloading %s %s for %s...", packageAddr, v, modulePath))
 	} else {
@@ -25,9 +34,20 @@ func (h uiModuleInstallHooks) Download(modulePath, packageAddr string, v *versio
 }
 
 func (h uiModuleInstallHooks) Install(modulePath string, v *version.Version, localDir string) {
+	if h.View != nil {
+		h.View.ModuleInstalled(modulePath, versionString(v), localDir)
+		return
+	}
 	if h.ShowLocalPaths {
 		h.Ui.Info(fmt.Sprintf("- %s in %s", modulePath, localDir))
 	} else {
 		h.Ui.Info(fmt.Sprintf("- %s", modulePath))
 	}
 }
+
+func versionString(v *version.Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
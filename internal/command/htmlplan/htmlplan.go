@@ -0,0 +1,291 @@
+// Package htmlplan translates the JSON plan representation produced by
+// package jsonplan into a self-contained HTML report, so that a saved plan
+// can be reviewed in a web browser by approvers who don't work with the
+// terminal output.
+//
+// The report includes summary counts of the planned actions and one
+// collapsible section per resource change showing the object before and
+// after the change. Values that the plan marks as sensitive are masked, and
+// values not known until apply are shown as "(known after apply)", matching
+// the terminal renderer's behavior.
+package htmlplan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+)
+
+// planDoc describes the subset of the jsonplan representation that we need
+// in order to build the report. We intentionally parse the serialized form
+// rather than depending on jsonplan's internal types, so this package works
+// with any input conforming to the documented plan representation.
+type planDoc struct {
+	TerraformVersion string `json:"terraform_version"`
+	ResourceChanges  []struct {
+		Address string `json:"address"`
+		Deposed string `json:"deposed"`
+		Change  struct {
+			Actions         []string        `json:"actions"`
+			Before          json.RawMessage `json:"before"`
+			After           json.RawMessage `json:"after"`
+			AfterUnknown    json.RawMessage `json:"after_unknown"`
+			BeforeSensitive json.RawMessage `json:"before_sensitive"`
+			AfterSensitive  json.RawMessage `json:"after_sensitive"`
+		} `json:"change"`
+		ActionReason string `json:"action_reason"`
+	} `json:"resource_changes"`
+}
+
+// reportData is the root value passed to the report template.
+type reportData struct {
+	TerraformVersion string
+	Create           int
+	Update           int
+	Replace          int
+	Delete           int
+	Changes          []changeData
+}
+
+// changeData describes one resource change section in the report.
+type changeData struct {
+	Address string
+	Deposed string
+	Action  string // "create", "update", "replace" or "delete"
+	Reason  string
+	Before  string // pretty-printed JSON, or "" if there's no prior object
+	After   string // pretty-printed JSON, or "" if the object is destroyed
+}
+
+// FromPlanJSON converts a JSON plan representation, as produced by
+// jsonplan.Marshal, into a self-contained HTML report. Resource changes
+// whose action is "no-op" are omitted from the report.
+func FromPlanJSON(planJSON []byte) ([]byte, error) {
+	var plan planDoc
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan representation: %s", err)
+	}
+
+	data := reportData{
+		TerraformVersion: plan.TerraformVersion,
+	}
+	for _, rc := range plan.ResourceChanges {
+		action := actionKeyword(rc.Change.Actions)
+		if action == "" {
+			continue // no-op changes are not worth reporting
+		}
+		switch action {
+		case "create":
+			data.Create++
+		case "update":
+			data.Update++
+		case "replace":
+			data.Replace++
+		case "delete":
+			data.Delete++
+		}
+
+		before, err := renderObject(rc.Change.Before, rc.Change.BeforeSensitive, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change for %s: %s", rc.Address, err)
+		}
+		after, err := renderObject(rc.Change.After, rc.Change.AfterSensitive, rc.Change.AfterUnknown)
+		if err != nil {
+			return nil, fmt.Errorf("invalid change for %s: %s", rc.Address, err)
+		}
+
+		data.Changes = append(data.Changes, changeData{
+			Address: rc.Address,
+			Deposed: rc.Deposed,
+			Action:  action,
+			Reason:  rc.ActionReason,
+			Before:  before,
+			After:   after,
+		})
+	}
+
+	sort.SliceStable(data.Changes, func(i, j int) bool {
+		return data.Changes[i].Address < data.Changes[j].Address
+	})
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// actionKeyword reduces a jsonplan actions list to a single keyword, or ""
+// for changes that need no report section.
+func actionKeyword(actions []string) string {
+	switch {
+	case len(actions) == 0:
+		return ""
+	case len(actions) > 1:
+		// Both ["create", "delete"] and ["delete", "create"] mean replace.
+		return "replace"
+	case actions[0] == "no-op":
+		return ""
+	default:
+		return actions[0]
+	}
+}
+
+// renderObject produces the pretty-printed form of one side of a change,
+// with sensitive values masked and unknown values replaced by a
+// "(known after apply)" marker. A JSON null (no object on this side of the
+// change) renders as the empty string.
+func renderObject(raw, sensitive, unknown json.RawMessage) (string, error) {
+	var val, sensitiveVal, unknownVal interface{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &val); err != nil {
+			return "", err
+		}
+	}
+	if val == nil {
+		return "", nil
+	}
+	if len(sensitive) > 0 {
+		if err := json.Unmarshal(sensitive, &sensitiveVal); err != nil {
+			return "", err
+		}
+	}
+	if len(unknown) > 0 {
+		if err := json.Unmarshal(unknown, &unknownVal); err != nil {
+			return "", err
+		}
+	}
+
+	val = maskUnknown(val, unknownVal)
+	val = maskSensitive(val, sensitiveVal)
+
+	buf, err := json.MarshalIndent(val, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// maskSensitive replaces every part of val that the given sensitivity
+// structure marks with true by a masking marker, following the jsonplan
+// convention where the sensitivity structure mirrors the value structure.
+func maskSensitive(val, sensitive interface{}) interface{} {
+	if sensitive == true {
+		return "(sensitive value)"
+	}
+	switch tv := val.(type) {
+	case map[string]interface{}:
+		sm, _ := sensitive.(map[string]interface{})
+		ret := make(map[string]interface{}, len(tv))
+		for k, ev := range tv {
+			ret[k] = maskSensitive(ev, sm[k])
+		}
+		return ret
+	case []interface{}:
+		ss, _ := sensitive.([]interface{})
+		ret := make([]interface{}, len(tv))
+		for i, ev := range tv {
+			var s interface{}
+			if i < len(ss) {
+				s = ss[i]
+			}
+			ret[i] = maskSensitive(ev, s)
+		}
+		return ret
+	default:
+		return val
+	}
+}
+
+// maskUnknown merges the after_unknown structure into val, representing
+// every value that won't be known until apply with a placeholder marker.
+func maskUnknown(val, unknown interface{}) interface{} {
+	if unknown == true {
+		return "(known after apply)"
+	}
+	switch tv := val.(type) {
+	case map[string]interface{}:
+		um, _ := unknown.(map[string]interface{})
+		ret := make(map[string]interface{}, len(tv)+len(um))
+		for k, ev := range tv {
+			ret[k] = maskUnknown(ev, um[k])
+		}
+		// Attributes that are wholly unknown appear only in the unknown
+		// structure, not in the value itself.
+		for k, uv := range um {
+			if _, exists := ret[k]; !exists {
+				ret[k] = maskUnknown(nil, uv)
+			}
+		}
+		return ret
+	case []interface{}:
+		us, _ := unknown.([]interface{})
+		ret := make([]interface{}, len(tv))
+		for i, ev := range tv {
+			var u interface{}
+			if i < len(us) {
+				u = us[i]
+			}
+			ret[i] = maskUnknown(ev, u)
+		}
+		return ret
+	default:
+		return val
+	}
+}
+
+// reportTemplate renders the whole report. The styling is deliberately
+// self-contained: no external scripts, stylesheets or fonts, so the report
+// can be attached to a ticket or emailed without losing anything.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Terraform Plan Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; color: #1f2229; }
+h1 { font-size: 1.5em; }
+.summary { margin: 1em 0; }
+.summary span { display: inline-block; margin-right: 1em; padding: 0.2em 0.6em; border-radius: 0.3em; font-weight: bold; }
+.create { background: #e6f4ea; color: #137333; }
+.update { background: #fef7e0; color: #a56400; }
+.replace { background: #fce8e6; color: #a50e0e; }
+.delete { background: #fce8e6; color: #a50e0e; }
+details { border: 1px solid #d8dee4; border-radius: 0.3em; margin: 0.5em 0; }
+summary { cursor: pointer; padding: 0.5em; font-family: "SFMono-Regular", Consolas, Menlo, monospace; }
+summary .action { float: right; padding: 0 0.5em; border-radius: 0.3em; font-family: inherit; }
+.reason { margin: 0 1em; font-style: italic; color: #57606a; }
+.diff { display: flex; gap: 1em; padding: 0.5em 1em 1em; }
+.diff div { flex: 1 1 0; min-width: 0; }
+.diff h3 { margin: 0 0 0.3em; font-size: 0.9em; color: #57606a; }
+pre { background: #f6f8fa; border-radius: 0.3em; padding: 0.7em; overflow-x: auto; font-size: 0.85em; margin: 0; }
+footer { margin-top: 2em; color: #57606a; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>Terraform Plan Report</h1>
+<div class="summary">
+<span class="create">{{.Create}} to create</span>
+<span class="update">{{.Update}} to update</span>
+<span class="replace">{{.Replace}} to replace</span>
+<span class="delete">{{.Delete}} to destroy</span>
+</div>
+{{if not .Changes}}
+<p>No changes. The plan proposes no actions.</p>
+{{end}}
+{{range .Changes}}
+<details>
+<summary>{{.Address}}{{if .Deposed}} (deposed object {{.Deposed}}){{end}}<span class="action {{.Action}}">{{.Action}}</span></summary>
+{{if .Reason}}<p class="reason">Reason: {{.Reason}}</p>{{end}}
+<div class="diff">
+{{if .Before}}<div><h3>Before</h3><pre>{{.Before}}</pre></div>{{end}}
+{{if .After}}<div><h3>After</h3><pre>{{.After}}</pre></div>{{end}}
+</div>
+</details>
+{{end}}
+<footer>Generated by Terraform{{if .TerraformVersion}} v{{.TerraformVersion}}{{end}}.</footer>
+</body>
+</html>
+`))
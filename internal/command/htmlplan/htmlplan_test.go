@@ -0,0 +1,115 @@
+package htmlplan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromPlanJSON(t *testing.T) {
+	planJSON := []byte(`{
+		"format_version": "1.2",
+		"terraform_version": "1.3.0",
+		"resource_changes": [
+			{
+				"address": "test_resource.create",
+				"change": {
+					"actions": ["create"],
+					"before": null,
+					"after": {"ami": "ami-1234", "password": "hunter2"},
+					"after_unknown": {"id": true},
+					"after_sensitive": {"password": true}
+				}
+			},
+			{
+				"address": "test_resource.update",
+				"change": {
+					"actions": ["update"],
+					"before": {"ami": "ami-1234"},
+					"after": {"ami": "ami-5678"}
+				}
+			},
+			{
+				"address": "test_resource.replace",
+				"change": {"actions": ["delete", "create"]},
+				"action_reason": "replace_because_cannot_update"
+			},
+			{
+				"address": "test_resource.delete",
+				"deposed": "byebye",
+				"change": {
+					"actions": ["delete"],
+					"before": {"secret": "s3cret"},
+					"after": null,
+					"before_sensitive": {"secret": true}
+				}
+			},
+			{
+				"address": "test_resource.noop",
+				"change": {"actions": ["no-op"]}
+			}
+		]
+	}`)
+
+	got, err := FromPlanJSON(planJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(got)
+
+	if !strings.HasPrefix(report, "<!DOCTYPE html>") {
+		t.Errorf("report doesn't start with an HTML doctype")
+	}
+	for _, want := range []string{
+		`<span class="create">1 to create</span>`,
+		`<span class="update">1 to update</span>`,
+		`<span class="replace">1 to replace</span>`,
+		`<span class="delete">1 to destroy</span>`,
+		`<summary>test_resource.create<span class="action create">create</span></summary>`,
+		`<summary>test_resource.delete (deposed object byebye)<span class="action delete">delete</span></summary>`,
+		`Reason: replace_because_cannot_update`,
+		`v1.3.0`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q", want)
+		}
+	}
+
+	// The no-op change must not produce a section.
+	if strings.Contains(report, "test_resource.noop") {
+		t.Errorf("report contains a section for the no-op change")
+	}
+
+	// Sensitive values must be masked on both sides of a change, and unknown
+	// values must render as the usual placeholder.
+	for _, banned := range []string{"hunter2", "s3cret"} {
+		if strings.Contains(report, banned) {
+			t.Errorf("report contains sensitive value %q", banned)
+		}
+	}
+	if !strings.Contains(report, "(sensitive value)") {
+		t.Errorf("report doesn't mask sensitive values")
+	}
+	if !strings.Contains(report, "(known after apply)") {
+		t.Errorf("report doesn't mark unknown values")
+	}
+}
+
+func TestFromPlanJSON_empty(t *testing.T) {
+	got, err := FromPlanJSON([]byte(`{"format_version": "1.2", "terraform_version": "1.3.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(got)
+	if !strings.Contains(report, "No changes.") {
+		t.Errorf("report for an empty plan doesn't say there are no changes")
+	}
+	if strings.Contains(report, "<details>") {
+		t.Errorf("report for an empty plan contains a change section")
+	}
+}
+
+func TestFromPlanJSON_invalid(t *testing.T) {
+	if _, err := FromPlanJSON([]byte(`ceci n'est pas un plan`)); err == nil {
+		t.Fatal("succeeded; want error")
+	}
+}
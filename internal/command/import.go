@@ -60,6 +60,14 @@ func (c *ImportCommand) Run(args []string) int {
 
 	var diags tfdiags.Diagnostics
 
+	// Importing writes to the state, so it isn't allowed in a read-only
+	// workspace.
+	diags = diags.Append(c.requireWritableWorkspace("import objects"))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	// Parse the provided resource address.
 	traversalSrc := []byte(args[0])
 	traversal, travDiags := hclsyntax.ParseTraversalAbs(traversalSrc, "<import-address>", hcl.Pos{Line: 1, Column: 1})
@@ -183,7 +191,7 @@ func (c *ImportCommand) Run(args []string) int {
 	}
 
 	// Build the operation
-	opReq := c.Operation(b)
+	opReq := c.Operation(b, arguments.ViewHuman)
 	opReq.ConfigDir = configPath
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	if err != nil {
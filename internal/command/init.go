@@ -3,12 +3,18 @@ package command
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/zclconf/go-cty/cty"
 
@@ -17,13 +23,16 @@ import (
 	backendInit "github.com/hashicorp/terraform/internal/backend/init"
 	"github.com/hashicorp/terraform/internal/cloud"
 	"github.com/hashicorp/terraform/internal/command/arguments"
+	"github.com/hashicorp/terraform/internal/command/views"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/modsdir"
 	"github.com/hashicorp/terraform/internal/providercache"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/hashicorp/terraform/internal/tracing"
 	tfversion "github.com/hashicorp/terraform/version"
 )
 
@@ -31,11 +40,20 @@ import (
 // module and clones it to the working directory.
 type InitCommand struct {
 	Meta
+
+	// view renders typed machine-readable messages when running in JSON
+	// output mode, and is a no-op for the human UI, which this command
+	// still renders directly via cli.Ui.
+	view views.Init
+
+	// jsonOutput is true when the -json option was set, in which case all
+	// output must be routed through the view.
+	jsonOutput bool
 }
 
 func (c *InitCommand) Run(args []string) int {
 	var flagFromModule, flagLockfile string
-	var flagBackend, flagCloud, flagGet, flagUpgrade bool
+	var flagBackend, flagCloud, flagGet, flagUpgrade, flagUpgradeModules bool
 	var flagPluginPath FlagStringSlice
 	flagConfigExtra := newRawFlags("-backend-config")
 
@@ -51,15 +69,34 @@ func (c *InitCommand) Run(args []string) int {
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
 	cmdFlags.BoolVar(&c.reconfigure, "reconfigure", false, "reconfigure")
 	cmdFlags.BoolVar(&c.migrateState, "migrate-state", false, "migrate state")
+	cmdFlags.BoolVar(&c.migrateStateDryRun, "dry-run", false, "report what a state migration would do without doing it")
+	var flagMigrateWorkspaces string
+	cmdFlags.StringVar(&flagMigrateWorkspaces, "migrate-workspaces", "", "limit state migration to the named workspaces")
 	cmdFlags.BoolVar(&flagUpgrade, "upgrade", false, "")
+	cmdFlags.BoolVar(&flagUpgradeModules, "upgrade-modules", false, "")
 	cmdFlags.Var(&flagPluginPath, "plugin-dir", "plugin directory")
 	cmdFlags.StringVar(&flagLockfile, "lockfile", "", "Set a dependency lockfile mode")
 	cmdFlags.BoolVar(&c.Meta.ignoreRemoteVersion, "ignore-remote-version", false, "continue even if remote and local Terraform versions are incompatible")
+	cmdFlags.BoolVar(&c.Meta.allowYankedModules, "allow-yanked-modules", false, "allow selection of yanked registry module versions")
+	cmdFlags.BoolVar(&c.Meta.offline, "offline", false, "forbid installation steps that require network access")
+	cmdFlags.BoolVar(&c.jsonOutput, "json", false, "json output")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
 	}
 
+	if c.jsonOutput {
+		// In JSON output mode everything we print must be machine-readable,
+		// so we emit typed messages through the view and route the legacy
+		// cli.Ui text output through it as JSON log lines.
+		c.view = views.NewInit(arguments.ViewJSON, c.View)
+		c.Meta.Color = false
+		c.Meta.color = false
+		c.Ui = &initJSONUi{view: c.view}
+	} else {
+		c.view = views.NewInit(arguments.ViewHuman, c.View)
+	}
+
 	backendFlagSet := arguments.FlagIsSet(cmdFlags, "backend")
 	cloudFlagSet := arguments.FlagIsSet(cmdFlags, "cloud")
 
@@ -84,6 +121,23 @@ func (c *InitCommand) Run(args []string) int {
 		c.migrateState = true
 	}
 
+	if c.migrateStateDryRun && !c.migrateState {
+		c.Ui.Error("The -dry-run option can be used only with -migrate-state")
+		return 1
+	}
+	if flagMigrateWorkspaces != "" {
+		if !c.migrateState {
+			c.Ui.Error("The -migrate-workspaces option can be used only with -migrate-state")
+			return 1
+		}
+		for _, name := range strings.Split(flagMigrateWorkspaces, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" {
+				c.migrateWorkspaces = append(c.migrateWorkspaces, name)
+			}
+		}
+	}
+
 	var diags tfdiags.Diagnostics
 
 	if len(flagPluginPath) > 0 {
@@ -129,6 +183,9 @@ func (c *InitCommand) Run(args []string) int {
 			Ui:             c.Ui,
 			ShowLocalPaths: false, // since they are in a weird location for init
 		}
+		if c.jsonOutput {
+			hooks.View = c.view
+		}
 
 		initDirFromModuleAbort, initDirFromModuleDiags := c.initDirFromModule(path, src, hooks)
 		diags = diags.Append(initDirFromModuleDiags)
@@ -153,6 +210,13 @@ func (c *InitCommand) Run(args []string) int {
 		return 0
 	}
 
+	// If an earlier init was interrupted then it may have left
+	// partially-installed artifacts behind, which could otherwise cause
+	// confusing failures during the steps below. We remove anything that is
+	// recognizably incomplete before we start, so that this init can
+	// reinstall it from scratch.
+	c.repairPartialInitArtifacts()
+
 	// For Terraform v0.12 we introduced a special loading mode where we would
 	// use the 0.11-syntax-compatible "earlyconfig" package as a heuristic to
 	// identify situations where it was likely that the user was trying to use
@@ -189,7 +253,12 @@ func (c *InitCommand) Run(args []string) int {
 	}
 
 	if flagGet {
-		modsOutput, modsAbort, modsDiags := c.getModules(path, rootModEarly, flagUpgrade)
+		if flagUpgradeModules && flagLockfile == "readonly" {
+			c.Ui.Error("The -upgrade-modules flag conflicts with -lockfile=readonly.")
+			return 1
+		}
+
+		modsOutput, modsAbort, modsDiags := c.getModules(path, rootModEarly, flagUpgrade || flagUpgradeModules, flagLockfile)
 		diags = diags.Append(modsDiags)
 		if modsAbort || modsDiags.HasErrors() {
 			c.showDiagnostics(diags)
@@ -341,12 +410,93 @@ func (c *InitCommand) Run(args []string) int {
 	return 0
 }
 
-func (c *InitCommand) getModules(path string, earlyRoot *tfconfig.Module, upgrade bool) (output bool, abort bool, diags tfdiags.Diagnostics) {
+// repairPartialInitArtifacts removes anything under the working directory's
+// data dir that was recognizably left behind by an interrupted earlier init:
+// the staging directories that the module and provider installers fetch
+// into before moving a completed result into place, and any module
+// directories that have no corresponding entry in the module manifest
+// (which is written only after all modules have installed successfully).
+//
+// This is a best-effort cleanup, so problems here are only logged; anything
+// we can't remove will surface through the installers' own error reporting
+// shortly afterwards. Note that the ".partial" *files* under the provider
+// cache directory are resumable downloads, which we deliberately keep.
+func (c *InitCommand) repairPartialInitArtifacts() {
+	var removed []string
+
+	isStagingDir := func(info os.FileInfo) bool {
+		name := info.Name()
+		return info.IsDir() && strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".partial")
+	}
+
+	// Staging directories can appear at any depth under the provider cache
+	// directory, next to the platform directory they were destined for.
+	providersDir := c.providerLocalCacheDir().BasePath()
+	filepath.Walk(providersDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // a missing or unreadable cache dir just means nothing to clean
+		}
+		if isStagingDir(info) {
+			if err := os.RemoveAll(path); err != nil {
+				log.Printf("[WARN] failed to remove partial provider package %s: %s", path, err)
+				return filepath.SkipDir
+			}
+			removed = append(removed, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	// Module directories all live at the top level of the modules dir, so
+	// we can compare them directly against the manifest. Local modules have
+	// manifest entries pointing outside the modules dir, which we must not
+	// treat as orphaned entries here.
+	modsDir := c.modulesDir()
+	if manifest, err := modsdir.ReadManifestSnapshotForDir(modsDir); err == nil {
+		referenced := make(map[string]bool)
+		for _, record := range manifest {
+			rel, err := filepath.Rel(modsDir, record.Dir)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			if first := strings.Split(rel, string(filepath.Separator))[0]; first != "." {
+				referenced[first] = true
+			}
+		}
+		if entries, err := ioutil.ReadDir(modsDir); err == nil {
+			for _, info := range entries {
+				if !info.IsDir() || referenced[info.Name()] {
+					continue
+				}
+				path := filepath.Join(modsDir, info.Name())
+				if err := os.RemoveAll(path); err != nil {
+					log.Printf("[WARN] failed to remove partial module %s: %s", path, err)
+					continue
+				}
+				removed = append(removed, path)
+			}
+		}
+	}
+
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		c.Ui.Output(c.Colorize().Color("[reset][bold]Cleaning up after an interrupted earlier run..."))
+		for _, path := range removed {
+			c.Ui.Output(fmt.Sprintf("- Removed incomplete %s", path))
+		}
+		c.Ui.Output("")
+	}
+}
+
+func (c *InitCommand) getModules(path string, earlyRoot *tfconfig.Module, upgrade bool, flagLockfile string) (output bool, abort bool, diags tfdiags.Diagnostics) {
 	if len(earlyRoot.ModuleCalls) == 0 {
 		// Nothing to do
 		return false, false, nil
 	}
 
+	span := tracing.StartSpan("init installing modules")
+	defer span.End()
+
 	if upgrade {
 		c.Ui.Output(c.Colorize().Color("[reset][bold]Upgrading modules..."))
 	} else {
@@ -357,6 +507,9 @@ func (c *InitCommand) getModules(path string, earlyRoot *tfconfig.Module, upgrad
 		Ui:             c.Ui,
 		ShowLocalPaths: true,
 	}
+	if c.jsonOutput {
+		hooks.View = c.view
+	}
 
 	installAbort, installDiags := c.installModules(path, upgrade, hooks)
 	diags = diags.Append(installDiags)
@@ -365,6 +518,14 @@ func (c *InitCommand) getModules(path string, earlyRoot *tfconfig.Module, upgrad
 	// aborted by SIGINT. In any case we continue and the manifest as best
 	// we can.
 
+	// If installation succeeded then we'll record the checksums of the
+	// installed remote module packages in the dependency lock file, and
+	// verify any checksums that were recorded by a previous run.
+	if !installAbort && !installDiags.HasErrors() {
+		moreDiags := c.lockModuleDependencies(upgrade, flagLockfile)
+		diags = diags.Append(moreDiags)
+	}
+
 	// Since module installer has modified the module manifest on disk, we need
 	// to refresh the cache of it in the loader.
 	if c.configLoader != nil {
@@ -381,7 +542,134 @@ func (c *InitCommand) getModules(path string, earlyRoot *tfconfig.Module, upgrad
 	return true, installAbort, diags
 }
 
+// lockModuleDependencies verifies the installed remote module packages
+// against any checksums recorded in the dependency lock file and then
+// updates the lock file to describe the current installation.
+//
+// Modules with local path sources are part of the calling configuration and
+// so are not subject to locking. A recorded checksum is authoritative only
+// while the module's source address and selected version remain unchanged;
+// if either changes then the module was deliberately reinstalled and the
+// lock entry is refreshed to match. The upgrade flag forces refreshing all
+// entries, for when a module's content has intentionally changed in place.
+func (c *InitCommand) lockModuleDependencies(upgrade bool, flagLockfile string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	manifest, err := modsdir.ReadManifestSnapshotForDir(c.modulesDir())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read modules manifest file",
+			fmt.Sprintf("Error reading manifest for %s: %s.", c.modulesDir(), err),
+		))
+		return diags
+	}
+
+	previousLocks, moreDiags := c.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+	newLocks := previousLocks.DeepCopy()
+
+	keys := make([]string, 0, len(manifest))
+	for key := range manifest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lockable := make(map[string]bool)
+	for _, key := range keys {
+		record := manifest[key]
+		if key == "" {
+			continue // the root module is the configuration itself
+		}
+		sourceAddr, err := addrs.ParseModuleSource(record.SourceAddr)
+		if err != nil {
+			// Invalid addresses in the manifest will be caught by the
+			// configuration loader, so we just skip them here.
+			continue
+		}
+		if _, isLocal := sourceAddr.(addrs.ModuleSourceLocal); isLocal {
+			continue // local modules belong to their calling package
+		}
+		lockable[key] = true
+
+		hash, err := getproviders.PackageHashV1(getproviders.PackageLocalDir(record.Dir))
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to hash module package",
+				fmt.Sprintf("Terraform could not calculate a checksum for the installed package of module %q in %s: %s.", key, record.Dir, err),
+			))
+			continue
+		}
+
+		versionStr := ""
+		if record.Version != nil {
+			versionStr = record.Version.String()
+		}
+
+		existing := newLocks.Module(key)
+		if existing == nil || upgrade || existing.Source() != record.SourceAddr || existing.Version() != versionStr {
+			newLocks.SetModule(key, record.SourceAddr, versionStr, []getproviders.Hash{hash})
+			continue
+		}
+
+		matched := false
+		for _, want := range existing.AllHashes() {
+			if want == hash {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Module checksum verification failed",
+				fmt.Sprintf(
+					"The installed package for module %q does not match the checksum recorded in the dependency lock file for %s version %s. The package content may have changed upstream since it was locked, or the local copy may have been modified.\n\nIf you intend to accept the new content, run \"terraform init -upgrade-modules\" to refresh the recorded checksums.",
+					key, existing.Source(), versionStr,
+				),
+			))
+		}
+	}
+
+	// Modules that are no longer called don't need lock entries anymore.
+	for key := range newLocks.AllModules() {
+		if !lockable[key] {
+			newLocks.RemoveModule(key)
+		}
+	}
+
+	if diags.HasErrors() {
+		// We don't rewrite the lock file while it disagrees with what is
+		// installed, so that re-running init after fixing the problem will
+		// verify against the original checksums again.
+		return diags
+	}
+
+	if !newLocks.Equal(previousLocks) {
+		if flagLockfile == "readonly" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Module lock file entries not updated",
+				"Changes to the module selections were detected, but not saved in the .terraform.lock.hcl file. To record these selections, run \"terraform init\" without the \"-lockfile=readonly\" flag.",
+			))
+			return diags
+		}
+		moreDiags = c.replaceLockedDependencies(newLocks)
+		diags = diags.Append(moreDiags)
+	}
+
+	return diags
+}
+
 func (c *InitCommand) initCloud(root *configs.Module, extraConfig rawFlags) (be backend.Backend, output bool, diags tfdiags.Diagnostics) {
+	span := tracing.StartSpan("init initializing backend")
+	span.SetAttribute("backend_type", "cloud")
+	defer span.End()
+
 	c.Ui.Output(c.Colorize().Color("\n[reset][bold]Initializing Terraform Cloud..."))
 
 	if len(extraConfig.AllItems()) != 0 {
@@ -402,10 +690,19 @@ func (c *InitCommand) initCloud(root *configs.Module, extraConfig rawFlags) (be
 
 	back, backDiags := c.Backend(opts)
 	diags = diags.Append(backDiags)
+	if back != nil && !backDiags.HasErrors() {
+		c.view.BackendInitialized("cloud")
+	}
 	return back, true, diags
 }
 
 func (c *InitCommand) initBackend(root *configs.Module, extraConfig rawFlags) (be backend.Backend, output bool, diags tfdiags.Diagnostics) {
+	span := tracing.StartSpan("init initializing backend")
+	if root.Backend != nil {
+		span.SetAttribute("backend_type", root.Backend.Type)
+	}
+	defer span.End()
+
 	c.Ui.Output(c.Colorize().Color("\n[reset][bold]Initializing the backend..."))
 
 	var backendConfig *configs.Backend
@@ -476,12 +773,22 @@ the backend configuration is present and valid.
 
 	back, backDiags := c.Backend(opts)
 	diags = diags.Append(backDiags)
+	if back != nil && !backDiags.HasErrors() {
+		backendType := "local"
+		if backendConfig != nil {
+			backendType = backendConfig.Type
+		}
+		c.view.BackendInitialized(backendType)
+	}
 	return back, true, diags
 }
 
 // Load the complete module tree, and fetch any missing providers.
 // This method outputs its own Ui.
 func (c *InitCommand) getProviders(config *configs.Config, state *states.State, upgrade bool, pluginDirs []string, flagLockfile string) (output, abort bool, diags tfdiags.Diagnostics) {
+	span := tracing.StartSpan("init installing providers")
+	defer span.End()
+
 	// Dev overrides cause the result of "terraform init" to be irrelevant for
 	// any overridden providers, so we'll warn about it to avoid later
 	// confusion when Terraform ends up using a different provider than the
@@ -522,9 +829,18 @@ func (c *InitCommand) getProviders(config *configs.Config, state *states.State,
 
 	var inst *providercache.Installer
 	if len(pluginDirs) == 0 {
-		// By default we use a source that looks for providers in all of the
-		// standard locations, possibly customized by the user in CLI config.
-		inst = c.providerInstaller()
+		if c.offline {
+			// In offline mode we use only the installation methods that can
+			// answer queries without network access, such as filesystem
+			// mirrors and the plugin cache.
+			log.Println("[DEBUG] init: offline mode, skipping network-based provider installation methods")
+			inst = c.providerInstallerCustomSource(c.providerInstallSourceWithoutNetwork())
+		} else {
+			// By default we use a source that looks for providers in all of
+			// the standard locations, possibly customized by the user in CLI
+			// config.
+			inst = c.providerInstaller()
+		}
 	} else {
 		// If the user passes at least one -plugin-dir then that circumvents
 		// the usual sources and forces Terraform to consult only the given
@@ -557,6 +873,12 @@ func (c *InitCommand) getProviders(config *configs.Config, state *states.State,
 		ProviderAlreadyInstalled: func(provider addrs.Provider, selectedVersion getproviders.Version) {
 			c.Ui.Info(fmt.Sprintf("- Using previously-installed %s v%s", provider.ForDisplay(), selectedVersion))
 		},
+		ProviderInstalledPackageInvalid: func(provider addrs.Provider, selectedVersion getproviders.Version) {
+			c.Ui.Info(fmt.Sprintf(
+				"- Previously-installed %s v%s no longer matches the dependency lock file, so it will be reinstalled",
+				provider.ForDisplay(), selectedVersion,
+			))
+		},
 		BuiltInProviderAvailable: func(provider addrs.Provider) {
 			c.Ui.Info(fmt.Sprintf("- %s is built in to Terraform", provider.ForDisplay()))
 		},
@@ -581,8 +903,21 @@ func (c *InitCommand) getProviders(config *configs.Config, state *states.State,
 		LinkFromCacheBegin: func(provider addrs.Provider, version getproviders.Version, cacheRoot string) {
 			c.Ui.Info(fmt.Sprintf("- Using %s v%s from the shared cache directory", provider.ForDisplay(), version))
 		},
-		FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation) {
-			c.Ui.Info(fmt.Sprintf("- Installing %s v%s...", provider.ForDisplay(), version))
+		QueryPackagesSuccess: func(provider addrs.Provider, selectedVersion getproviders.Version) {
+			if c.jsonOutput {
+				c.view.ProviderVersionSelected(provider.ForDisplay(), selectedVersion.String())
+			}
+		},
+		FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation, sourceKind getproviders.SourceKind) {
+			if c.jsonOutput {
+				c.view.ProviderDownload(provider.ForDisplay(), version.String(), fmt.Sprintf("%s", location), string(sourceKind))
+				return
+			}
+			if desc := providerInstallSourceDescription(sourceKind, location); desc != "" {
+				c.Ui.Info(fmt.Sprintf("- Installing %s v%s (%s)...", provider.ForDisplay(), version, desc))
+			} else {
+				c.Ui.Info(fmt.Sprintf("- Installing %s v%s...", provider.ForDisplay(), version))
+			}
 		},
 		QueryPackagesFailure: func(provider addrs.Provider, err error) {
 			switch errorTy := err.(type) {
@@ -592,11 +927,19 @@ func (c *InitCommand) getProviders(config *configs.Config, state *states.State,
 				for i, source := range sources {
 					displaySources[i] = fmt.Sprintf("  - %s", source)
 				}
+				var offlineHint string
+				if c.offline {
+					if len(sources) == 0 {
+						offlineHint = "\n\nTerraform is running in offline mode (-offline) and no local installation methods (such as filesystem mirrors) are configured, so this provider must already be installed before initialization."
+					} else {
+						offlineHint = "\n\nTerraform is running in offline mode (-offline), so only the local installation methods listed above were consulted."
+					}
+				}
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
 					"Failed to query available provider packages",
-					fmt.Sprintf("Could not retrieve the list of available versions for provider %s: %s\n\n%s",
-						provider.ForDisplay(), err, strings.Join(displaySources, "\n"),
+					fmt.Sprintf("Could not retrieve the list of available versions for provider %s: %s\n\n%s%s",
+						provider.ForDisplay(), err, strings.Join(displaySources, "\n"), offlineHint,
 					),
 				))
 			case getproviders.ErrRegistryProviderNotKnown:
@@ -773,7 +1116,11 @@ func (c *InitCommand) getProviders(config *configs.Config, state *states.State,
 				))
 			}
 		},
-		FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult) {
+		FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult, hashesVerified bool) {
+			if c.jsonOutput {
+				c.view.ProviderInstalled(provider.ForDisplay(), version.String(), authResult.String(), hashesVerified)
+				return
+			}
 			var keyID string
 			if authResult != nil && authResult.ThirdPartySigned() {
 				keyID = authResult.KeyID
@@ -890,11 +1237,52 @@ version control system if they represent changes you intended to make.`))
 
 		moreDiags = c.replaceLockedDependencies(newLocks)
 		diags = diags.Append(moreDiags)
+		if c.jsonOutput && !moreDiags.HasErrors() {
+			c.view.LockFileUpdated(dependencyLockFilename)
+		}
 	}
 
 	return true, false, diags
 }
 
+// providerInstallSourceDescription returns a short English description of
+// where a provider package is being fetched from, so that the installation
+// messages can distinguish packages coming from a provider's origin
+// registry from those delivered by a network or filesystem mirror. It
+// returns an empty string if there's nothing useful to say about the
+// given source.
+func providerInstallSourceDescription(kind getproviders.SourceKind, location getproviders.PackageLocation) string {
+	switch kind {
+	case getproviders.SourceKindRegistry:
+		return "from the origin registry"
+	case getproviders.SourceKindNetworkMirror:
+		if loc, ok := location.(getproviders.PackageHTTPURL); ok {
+			if u, err := url.Parse(string(loc)); err == nil && u.Hostname() != "" {
+				return fmt.Sprintf("from network mirror %s", u.Hostname())
+			}
+		}
+		return "from a network mirror"
+	case getproviders.SourceKindFilesystemMirror:
+		return fmt.Sprintf("from filesystem mirror %s", location)
+	}
+
+	// Without a source classification the best we can do is describe the
+	// location itself.
+	switch loc := location.(type) {
+	case getproviders.PackageLocalDir:
+		return fmt.Sprintf("from %s", loc)
+	case getproviders.PackageLocalArchive:
+		return fmt.Sprintf("from %s", loc)
+	case getproviders.PackageHTTPURL:
+		if u, err := url.Parse(string(loc)); err == nil && u.Hostname() != "" {
+			return fmt.Sprintf("from %s", u.Hostname())
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
 // backendConfigOverrideBody interprets the raw values of -backend-config
 // arguments into a hcl Body that should override the backend settings given
 // in the configuration.
@@ -1006,20 +1394,26 @@ func (c *InitCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *InitCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		"-backend":        completePredictBoolean,
-		"-cloud":          completePredictBoolean,
-		"-backend-config": complete.PredictFiles("*.tfvars"), // can also be key=value, but we can't "predict" that
-		"-force-copy":     complete.PredictNothing,
-		"-from-module":    completePredictModuleSource,
-		"-get":            completePredictBoolean,
-		"-input":          completePredictBoolean,
-		"-lock":           completePredictBoolean,
-		"-lock-timeout":   complete.PredictAnything,
-		"-no-color":       complete.PredictNothing,
-		"-plugin-dir":     complete.PredictDirs(""),
-		"-reconfigure":    complete.PredictNothing,
-		"-migrate-state":  complete.PredictNothing,
-		"-upgrade":        completePredictBoolean,
+		"-backend":              completePredictBoolean,
+		"-cloud":                completePredictBoolean,
+		"-backend-config":       complete.PredictFiles("*.tfvars"), // can also be key=value, but we can't "predict" that
+		"-force-copy":           complete.PredictNothing,
+		"-from-module":          completePredictModuleSource,
+		"-get":                  completePredictBoolean,
+		"-input":                completePredictBoolean,
+		"-lock":                 completePredictBoolean,
+		"-lock-timeout":         complete.PredictAnything,
+		"-no-color":             complete.PredictNothing,
+		"-json":                 complete.PredictNothing,
+		"-offline":              complete.PredictNothing,
+		"-plugin-dir":           complete.PredictDirs(""),
+		"-reconfigure":          complete.PredictNothing,
+		"-migrate-state":        complete.PredictNothing,
+		"-dry-run":              complete.PredictNothing,
+		"-migrate-workspaces":   complete.PredictAnything,
+		"-upgrade":              completePredictBoolean,
+		"-upgrade-modules":      completePredictBoolean,
+		"-allow-yanked-modules": completePredictBoolean,
 	}
 }
 
@@ -1070,6 +1464,11 @@ Options:
                           require interactive prompts and will error if input is
                           disabled.
 
+  -json                   Produce output in a machine-readable JSON format,
+                          suitable for use in text editor integrations and
+                          other automated systems. Always disables color and
+                          interactive prompts.
+
   -lock=false             Don't hold a state lock during backend migration.
                           This is dangerous if others might concurrently run
                           commands against the same workspace.
@@ -1078,6 +1477,11 @@ Options:
 
   -no-color               If specified, output won't contain any color.
 
+  -offline                Forbid any installation step that would require
+                          network access. Providers and modules must then come
+                          from local mirrors, local paths, or what is already
+                          installed.
+
   -plugin-dir             Directory containing plugin binaries. This overrides all
                           default search paths for plugins, and prevents the
                           automatic installation of plugins. This flag can be used
@@ -1089,14 +1493,32 @@ Options:
   -migrate-state          Reconfigure a backend, and attempt to migrate any
                           existing state.
 
+  -dry-run                When used with -migrate-state, report workspace by
+                          workspace what the migration would do and then exit
+                          without copying any state or changing the backend
+                          configuration.
+
+  -migrate-workspaces=a,b When used with -migrate-state, migrate only the
+                          named workspaces instead of all workspaces in the
+                          source backend.
+
   -upgrade                Install the latest module and provider versions
                           allowed within configured constraints, overriding the
                           default behavior of selecting exactly the version
                           recorded in the dependency lockfile.
 
+  -upgrade-modules        Refresh the module checksums recorded in the
+                          dependency lockfile to match the currently
+                          installed module packages, accepting any upstream
+                          content changes.
+
   -lockfile=MODE          Set a dependency lockfile mode.
                           Currently only "readonly" is valid.
 
+  -allow-yanked-modules   Allow installing registry module versions that the
+                          registry has yanked, which Terraform otherwise
+                          refuses to newly select.
+
   -ignore-remote-version  A rare option used for Terraform Cloud and the remote backend
                           only. Set this to ignore checking that the local and remote
                           Terraform versions use compatible state representations, making
@@ -1108,6 +1530,62 @@ Options:
 	return strings.TrimSpace(helpText)
 }
 
+// showDiagnostics shadows Meta.showDiagnostics so that in JSON output mode
+// diagnostics are rendered as structured messages through the view rather
+// than as formatted text.
+func (c *InitCommand) showDiagnostics(vals ...interface{}) {
+	if c.jsonOutput {
+		var diags tfdiags.Diagnostics
+		diags = diags.Append(vals...)
+		diags.Sort()
+		c.view.Diagnostics(diags)
+		return
+	}
+	c.Meta.showDiagnostics(vals...)
+}
+
+// initJSONUi is a cli.Ui implementation that routes the init command's
+// legacy text output through the JSON view as log messages, so that in
+// JSON output mode everything written to the output streams is
+// machine-readable. Interactive prompts are not supported in this mode.
+type initJSONUi struct {
+	view views.Init
+}
+
+var _ cli.Ui = (*initJSONUi)(nil)
+
+func (u *initJSONUi) Ask(query string) (string, error) {
+	return "", fmt.Errorf("can't ask for input %q in JSON output mode", query)
+}
+
+func (u *initJSONUi) AskSecret(query string) (string, error) {
+	return u.Ask(query)
+}
+
+func (u *initJSONUi) Output(message string) {
+	u.log(message)
+}
+
+func (u *initJSONUi) Info(message string) {
+	u.log(message)
+}
+
+func (u *initJSONUi) Warn(message string) {
+	u.log(message)
+}
+
+func (u *initJSONUi) Error(message string) {
+	if message = strings.TrimSpace(message); message != "" {
+		u.view.LogError(message)
+	}
+}
+
+func (u *initJSONUi) log(message string) {
+	if message = strings.TrimSpace(message); message != "" {
+		u.view.Log(message)
+	}
+}
+
 func (c *InitCommand) Synopsis() string {
 	return "Prepare your working directory for other commands"
 }
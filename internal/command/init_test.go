@@ -183,6 +183,109 @@ func TestInit_get(t *testing.T) {
 	}
 }
 
+func TestInit_json(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	args := []string{"-json"}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.All())
+	}
+
+	// Every line of output must be a valid JSON object, and the typed
+	// messages for the main initialization steps must be present.
+	wantTypes := map[string]bool{
+		"version":             false,
+		"init_module_install": false,
+		"init_backend":        false,
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output.Stdout()), "\n") {
+		var msg map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid JSON output line %q: %s", line, err)
+		}
+		if ty, ok := msg["type"].(string); ok {
+			if _, exists := wantTypes[ty]; exists {
+				wantTypes[ty] = true
+			}
+		}
+	}
+	for ty, seen := range wantTypes {
+		if !seen {
+			t.Errorf("no %q message in output", ty)
+		}
+	}
+}
+
+func TestInit_repairPartialArtifacts(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get"), td)
+	defer testChdir(t, td)()
+
+	// Simulate the leftovers of an earlier init that was interrupted during
+	// provider extraction and module installation: staging directories that
+	// never got renamed into place, and a module directory with no manifest
+	// entry. The ".partial" *file* stands in for a resumable provider
+	// download, which must survive the cleanup.
+	stagingDir := filepath.Join(td, ".terraform", "providers", "registry.terraform.io", "hashicorp", "test", "1.0.0", ".linux_amd64.partial")
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	partialDownload := filepath.Join(td, ".terraform", "providers", "registry.terraform.io", "hashicorp", "test", "1.0.0", ".linux_amd64.zip.partial")
+	if err := os.WriteFile(partialDownload, []byte("partial bytes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	orphanModule := filepath.Join(td, ".terraform", "modules", "orphan")
+	if err := os.MkdirAll(orphanModule, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	args := []string{}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, "Cleaning up after an interrupted earlier run") {
+		t.Fatalf("output doesn't mention the cleanup: %s", output)
+	}
+
+	if _, err := os.Stat(stagingDir); !os.IsNotExist(err) {
+		t.Errorf("provider staging directory still present after init")
+	}
+	if _, err := os.Stat(orphanModule); !os.IsNotExist(err) {
+		t.Errorf("orphaned module directory still present after init")
+	}
+	if _, err := os.Stat(partialDownload); err != nil {
+		t.Errorf("resumable provider download was removed by the cleanup: %s", err)
+	}
+}
+
 func TestInit_getUpgradeModules(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -214,6 +317,90 @@ func TestInit_getUpgradeModules(t *testing.T) {
 	}
 }
 
+func TestInit_moduleLocks(t *testing.T) {
+	// This test exercises the module checksum handling directly, fabricating
+	// a modules manifest describing a remote module package so that we don't
+	// need to actually download anything.
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	pkgDir := filepath.Join(td, "acme-pkg")
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	mainTf := filepath.Join(pkgDir, "main.tf")
+	if err := ioutil.WriteFile(mainTf, []byte("# original content\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modsDir := filepath.Join(td, ".terraform", "modules")
+	if err := os.MkdirAll(modsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := fmt.Sprintf(
+		`{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"acme","Source":"git::https://example.com/acme.git","Dir":%q}]}`,
+		pkgDir,
+	)
+	if err := ioutil.WriteFile(filepath.Join(modsDir, "modules.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	// The first run should record a lock entry for the remote module.
+	if diags := c.lockModuleDependencies(false, ""); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	locks, diags := c.lockedDependencies()
+	if diags.HasErrors() {
+		t.Fatalf("failed to read lock file: %s", diags.Err())
+	}
+	lock := locks.Module("acme")
+	if lock == nil {
+		t.Fatal("no lock entry recorded for module \"acme\"")
+	}
+	if got, want := lock.Source(), "git::https://example.com/acme.git"; got != want {
+		t.Errorf("wrong locked source\ngot:  %s\nwant: %s", got, want)
+	}
+	if got := lock.AllHashes(); len(got) != 1 || !got[0].HasScheme(getproviders.HashScheme1) {
+		t.Errorf("wrong locked hashes %#v; want one h1: hash", got)
+	}
+
+	// A second run with unchanged content must verify successfully and leave
+	// the lock entry as it was.
+	if diags := c.lockModuleDependencies(false, ""); diags.HasErrors() {
+		t.Fatalf("unexpected errors on re-run: %s", diags.Err())
+	}
+
+	// Changing the package content must now fail verification.
+	if err := ioutil.WriteFile(mainTf, []byte("# tampered content\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diags = c.lockModuleDependencies(false, "")
+	if !diags.HasErrors() {
+		t.Fatal("verification succeeded after content change; want error")
+	}
+	if got, want := diags.Err().Error(), "Module checksum verification failed"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+
+	// The upgrade flag accepts the new content and refreshes the checksum.
+	if diags := c.lockModuleDependencies(true, ""); diags.HasErrors() {
+		t.Fatalf("unexpected errors when upgrading: %s", diags.Err())
+	}
+	if diags := c.lockModuleDependencies(false, ""); diags.HasErrors() {
+		t.Fatalf("unexpected errors after upgrading: %s", diags.Err())
+	}
+}
+
 func TestInit_backend(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -329,7 +516,7 @@ func TestInit_backendConfigFile(t *testing.T) {
 
 		// Read our saved backend config and verify we have our settings
 		state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-		if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"hello","workspace_dir":null}`; got != want {
+		if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"hello","workspace_dir":null}`; got != want {
 			t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 		}
 	})
@@ -412,7 +599,7 @@ func TestInit_backendConfigFile(t *testing.T) {
 
 		// Read our saved backend config and verify the backend config is empty
 		state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-		if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":null,"workspace_dir":null}`; got != want {
+		if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":null,"workspace_dir":null}`; got != want {
 			t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 		}
 	})
@@ -547,7 +734,7 @@ func TestInit_backendConfigFileChange(t *testing.T) {
 
 	// Read our saved backend config and verify we have our settings
 	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"hello","workspace_dir":null}`; got != want {
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"hello","workspace_dir":null}`; got != want {
 		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 	}
 }
@@ -639,6 +826,80 @@ func TestInit_backendConfigFileChangeWithExistingState(t *testing.T) {
 	}
 }
 
+func TestInit_backendMigrateDryRun(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-backend-config-file-change-migrate-existing"), td)
+	defer testChdir(t, td)()
+
+	newCommand := func(ui *cli.MockUi) *InitCommand {
+		view, _ := testView(t)
+		return &InitCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+				View:             view,
+			},
+		}
+	}
+
+	// -dry-run requires -migrate-state
+	ui := cli.NewMockUi()
+	if code := newCommand(ui).Run([]string{"-dry-run"}); code == 0 {
+		t.Fatalf("expected error\n%s", ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "The -dry-run option can be used only with -migrate-state"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+
+	// -migrate-workspaces requires -migrate-state too
+	ui = cli.NewMockUi()
+	if code := newCommand(ui).Run([]string{"-migrate-workspaces=foo"}); code == 0 {
+		t.Fatalf("expected error\n%s", ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "The -migrate-workspaces option can be used only with -migrate-state"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+
+	// -migrate-workspaces must name workspaces that exist in the source
+	ui = cli.NewMockUi()
+	args := []string{"-migrate-state", "-migrate-workspaces=nope", "-force-copy", "-backend-config", "input.config", "-input=false"}
+	if code := newCommand(ui).Run(args); code == 0 {
+		t.Fatalf("expected error\n%s", ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), `workspace "nope", which does not exist in the source backend`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+
+	oldState := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
+
+	// A dry run reports what would be copied and then aborts initialization
+	ui = cli.NewMockUi()
+	args = []string{"-migrate-state", "-dry-run", "-backend-config", "input.config", "-input=false"}
+	if code := newCommand(ui).Run(args); code == 0 {
+		t.Fatalf("expected nonzero exit code\n%s", ui.OutputWriter.String())
+	}
+	if got, want := ui.OutputWriter.String(), `workspace "default": would copy source state (serial 8) into the empty destination`; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot: %s\nwant substring: %s", got, want)
+	}
+	if got, want := ui.ErrorWriter.String(), "Dry run complete: no state was migrated"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+
+	// Nothing was written to the destination and the saved backend
+	// configuration is unchanged.
+	if _, err := os.Stat("hello"); err == nil {
+		t.Fatal("destination state file exists after dry run")
+	}
+	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"local-state.tfstate"}`; got != want {
+		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
+	}
+	if oldState.Backend.Hash != state.Backend.Hash {
+		t.Errorf("backend hash should not have changed\ngot:  %d\nwant: %d", state.Backend.Hash, oldState.Backend.Hash)
+	}
+}
+
 func TestInit_backendConfigKV(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -662,7 +923,7 @@ func TestInit_backendConfigKV(t *testing.T) {
 
 	// Read our saved backend config and verify we have our settings
 	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"hello","workspace_dir":null}`; got != want {
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"hello","workspace_dir":null}`; got != want {
 		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 	}
 }
@@ -840,7 +1101,7 @@ func TestInit_backendReinitWithExtra(t *testing.T) {
 
 	// Read our saved backend config and verify we have our settings
 	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"hello","workspace_dir":null}`; got != want {
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"hello","workspace_dir":null}`; got != want {
 		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 	}
 
@@ -853,7 +1114,7 @@ func TestInit_backendReinitWithExtra(t *testing.T) {
 		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
 	}
 	state = testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"hello","workspace_dir":null}`; got != want {
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"hello","workspace_dir":null}`; got != want {
 		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 	}
 	if state.Backend.Hash != uint64(cHash) {
@@ -883,7 +1144,7 @@ func TestInit_backendReinitConfigToExtra(t *testing.T) {
 
 	// Read our saved backend config and verify we have our settings
 	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"foo","workspace_dir":null}`; got != want {
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"foo","workspace_dir":null}`; got != want {
 		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
 	}
 
@@ -910,7 +1171,7 @@ func TestInit_backendReinitConfigToExtra(t *testing.T) {
 		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
 	}
 	state = testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
-	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"foo","workspace_dir":null}`; got != want {
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"encryption":null,"path":"foo","workspace_dir":null}`; got != want {
 		t.Errorf("wrong config after moving to arg\ngot:  %s\nwant: %s", got, want)
 	}
 
@@ -1819,6 +2080,68 @@ func TestInit_cancelProviders(t *testing.T) {
 	}
 }
 
+func TestInit_offlineRegistryModule(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-registry-module"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	m := Meta{
+		testingOverrides: metaOverridesForProvider(testProvider()),
+		Ui:               ui,
+		View:             view,
+	}
+
+	c := &InitCommand{
+		Meta: m,
+	}
+
+	args := []string{"-offline"}
+	if code := c.Run(args); code == 0 {
+		t.Fatalf("succeeded; wanted error\n%s", ui.OutputWriter.String())
+	}
+
+	errStr := ui.ErrorWriter.String()
+	if want := `Module installation requires network access`; !strings.Contains(errStr, want) {
+		t.Fatalf("wrong error message\nshould contain: %s\ngot:\n%s", want, errStr)
+	}
+	if want := `registry.does.not.exist`; !strings.Contains(errStr, want) {
+		t.Fatalf("wrong error message\nshould name the registry host %s\ngot:\n%s", want, errStr)
+	}
+}
+
+func TestInit_offlineRemoteModule(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-offline-remote-module"), td)
+	defer testChdir(t, td)()
+
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	m := Meta{
+		testingOverrides: metaOverridesForProvider(testProvider()),
+		Ui:               ui,
+		View:             view,
+	}
+
+	c := &InitCommand{
+		Meta: m,
+	}
+
+	args := []string{"-offline"}
+	if code := c.Run(args); code == 0 {
+		t.Fatalf("succeeded; wanted error\n%s", ui.OutputWriter.String())
+	}
+
+	errStr := ui.ErrorWriter.String()
+	if want := `Module installation requires network access`; !strings.Contains(errStr, want) {
+		t.Fatalf("wrong error message\nshould contain: %s\ngot:\n%s", want, errStr)
+	}
+	if want := `git::https://example.com/foo.git`; !strings.Contains(errStr, want) {
+		t.Fatalf("wrong error message\nshould name the remote package %s\ngot:\n%s", want, errStr)
+	}
+}
+
 func TestInit_getUpgradePlugins(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -22,7 +22,12 @@ import (
 // FormatVersion represents the version of the json format and will be
 // incremented for any change to this format that requires changes to a
 // consuming parser.
-const FormatVersion = "1.1"
+//
+// Version 1.2 guarantees that resource changes are sorted by address (and
+// then by deposed key), and that child modules in the planned values are
+// sorted by address at every level of nesting, so the output for a given
+// plan is always byte-for-byte identical.
+const FormatVersion = "1.2"
 
 // Plan is the top-level representation of the json format of a plan. It includes
 // the complete config and current state.
@@ -102,6 +107,13 @@ type change struct {
 	// consists of one or more steps, each of which will be a number or a
 	// string.
 	ReplacePaths json.RawMessage `json:"replace_paths,omitempty"`
+
+	// ChangedPaths is an array of arrays representing the set of paths into
+	// the object value whose values differ between "before" and "after",
+	// using the same encoding as ReplacePaths. This is populated only for
+	// "update" entries in "resource_drift", so that drift can be consumed
+	// at attribute granularity without diffing the full object values.
+	ChangedPaths json.RawMessage `json:"changed_paths,omitempty"`
 }
 
 type output struct {
@@ -155,7 +167,7 @@ func Marshal(
 				}
 			}
 		}
-		output.ResourceDrift, err = output.marshalResourceChanges(driftedResources, schemas)
+		output.ResourceDrift, err = output.marshalResourceChanges(driftedResources, schemas, true)
 		if err != nil {
 			return nil, fmt.Errorf("error in marshaling resource drift: %s", err)
 		}
@@ -167,7 +179,7 @@ func Marshal(
 
 	// output.ResourceChanges
 	if p.Changes != nil {
-		output.ResourceChanges, err = output.marshalResourceChanges(p.Changes.Resources, schemas)
+		output.ResourceChanges, err = output.marshalResourceChanges(p.Changes.Resources, schemas, false)
 		if err != nil {
 			return nil, fmt.Errorf("error in marshaling resource changes: %s", err)
 		}
@@ -257,7 +269,11 @@ func (p *plan) marshalPlanVariables(vars map[string]plans.DynamicValue, decls ma
 	return nil
 }
 
-func (p *plan) marshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schemas *terraform.Schemas) ([]resourceChange, error) {
+// marshalResourceChanges produces the JSON representation of the given
+// resource instance changes. If withChangedPaths is set then each "update"
+// entry also records the paths to the attributes that differ between the
+// before and after values, which we currently do only for resource drift.
+func (p *plan) marshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schemas *terraform.Schemas, withChangedPaths bool) ([]resourceChange, error) {
 	var ret []resourceChange
 
 	for _, rc := range resources {
@@ -352,6 +368,14 @@ func (p *plan) marshalResourceChanges(resources []*plans.ResourceInstanceChangeS
 			return nil, err
 		}
 
+		var changedPaths json.RawMessage
+		if withChangedPaths && rc.Action == plans.Update {
+			changedPaths, err = encodePaths(changedValuePaths(changeV.Before, changeV.After))
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		r.Change = change{
 			Actions:         actionString(rc.Action.String()),
 			Before:          json.RawMessage(before),
@@ -360,6 +384,7 @@ func (p *plan) marshalResourceChanges(resources []*plans.ResourceInstanceChangeS
 			BeforeSensitive: json.RawMessage(beforeSensitive),
 			AfterSensitive:  json.RawMessage(afterSensitive),
 			ReplacePaths:    replacePaths,
+			ChangedPaths:    changedPaths,
 		}
 
 		if rc.DeposedKey != states.NotDeposed {
@@ -418,7 +443,14 @@ func (p *plan) marshalResourceChanges(resources []*plans.ResourceInstanceChangeS
 	}
 
 	sort.Slice(ret, func(i, j int) bool {
-		return ret[i].Address < ret[j].Address
+		if ret[i].Address != ret[j].Address {
+			return ret[i].Address < ret[j].Address
+		}
+		// A change for a deposed object shares an address with the change
+		// for the current object, so we use the deposed key to give the
+		// pair a deterministic order. A change for a current object has no
+		// deposed key and so sorts first.
+		return ret[i].Deposed < ret[j].Deposed
 	})
 
 	return ret, nil
@@ -693,6 +725,85 @@ func actionString(action string) []string {
 	}
 }
 
+// changedValuePaths returns the set of paths into the given values where
+// their contents differ. Paths point at the deepest differing values, so a
+// changed attribute nested inside an object is reported as the path to that
+// attribute rather than to the whole object, except for sets, where we
+// report the path of the set itself because set elements have no stable
+// addresses to point at.
+func changedValuePaths(before, after cty.Value) cty.PathSet {
+	ret := cty.NewPathSet()
+	collectChangedPaths(nil, before, after, ret)
+	return ret
+}
+
+func collectChangedPaths(path cty.Path, before, after cty.Value, into cty.PathSet) {
+	if before.RawEquals(after) {
+		return
+	}
+	if before.IsNull() || after.IsNull() || !before.IsKnown() || !after.IsKnown() {
+		// One side is missing (or unknown) entirely, so the whole value at
+		// this path is the difference.
+		into.Add(path.Copy())
+		return
+	}
+
+	ty := before.Type()
+	switch {
+	case ty.IsObjectType():
+		for name := range ty.AttributeTypes() {
+			collectChangedPaths(path.GetAttr(name), before.GetAttr(name), after.GetAttr(name), into)
+		}
+	case ty.IsMapType():
+		keys := make(map[string]struct{})
+		for it := before.ElementIterator(); it.Next(); {
+			k, _ := it.Element()
+			keys[k.AsString()] = struct{}{}
+		}
+		for it := after.ElementIterator(); it.Next(); {
+			k, _ := it.Element()
+			keys[k.AsString()] = struct{}{}
+		}
+		for k := range keys {
+			key := cty.StringVal(k)
+			elemTy := ty.ElementType()
+			beforeElem := cty.NullVal(elemTy)
+			if before.HasIndex(key).True() {
+				beforeElem = before.Index(key)
+			}
+			afterElem := cty.NullVal(elemTy)
+			if after.HasIndex(key).True() {
+				afterElem = after.Index(key)
+			}
+			collectChangedPaths(path.Index(key), beforeElem, afterElem, into)
+		}
+	case ty.IsListType(), ty.IsTupleType():
+		beforeLen := before.LengthInt()
+		afterLen := after.LengthInt()
+		commonLen := beforeLen
+		if afterLen < commonLen {
+			commonLen = afterLen
+		}
+		for i := 0; i < commonLen; i++ {
+			key := cty.NumberIntVal(int64(i))
+			collectChangedPaths(path.Index(key), before.Index(key), after.Index(key), into)
+		}
+		longerLen := beforeLen
+		if afterLen > longerLen {
+			longerLen = afterLen
+		}
+		for i := commonLen; i < longerLen; i++ {
+			// Elements that exist on only one side are differences in
+			// their own right.
+			into.Add(path.Index(cty.NumberIntVal(int64(i))).Copy())
+		}
+	default:
+		// Primitive values, and also sets, whose elements we can't
+		// address individually.
+		into.Add(path.Copy())
+	}
+}
+
 // encodePaths lossily encodes a cty.PathSet into an array of arrays of step
 // values, such as:
 //
@@ -305,6 +305,104 @@ func TestEncodePaths(t *testing.T) {
 	}
 }
 
+func TestChangedValuePaths(t *testing.T) {
+	tests := map[string]struct {
+		Before cty.Value
+		After  cty.Value
+		Want   cty.PathSet
+	}{
+		"no changes": {
+			cty.ObjectVal(map[string]cty.Value{
+				"ami": cty.StringVal("foo"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"ami": cty.StringVal("foo"),
+			}),
+			cty.NewPathSet(),
+		},
+		"changed attribute": {
+			cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("placeholder"),
+				"ami": cty.StringVal("foo"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"id":  cty.StringVal("placeholder"),
+				"ami": cty.StringVal("bar"),
+			}),
+			cty.NewPathSet(cty.GetAttrPath("ami")),
+		},
+		"attribute became null": {
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env": cty.StringVal("prod"),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.NullVal(cty.Map(cty.String)),
+			}),
+			cty.NewPathSet(cty.GetAttrPath("tags")),
+		},
+		"changed and added map elements": {
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env": cty.StringVal("prod"),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env":  cty.StringVal("dev"),
+					"team": cty.StringVal("platform"),
+				}),
+			}),
+			cty.NewPathSet(
+				cty.GetAttrPath("tags").IndexString("env"),
+				cty.GetAttrPath("tags").IndexString("team"),
+			),
+		},
+		"changed nested list element and changed length": {
+			cty.ObjectVal(map[string]cty.Value{
+				"network_interface": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"device_index": cty.StringVal("0"),
+					}),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"network_interface": cty.ListVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"device_index": cty.StringVal("1"),
+					}),
+					cty.ObjectVal(map[string]cty.Value{
+						"device_index": cty.StringVal("2"),
+					}),
+				}),
+			}),
+			cty.NewPathSet(
+				cty.GetAttrPath("network_interface").IndexInt(0).GetAttr("device_index"),
+				cty.GetAttrPath("network_interface").IndexInt(1),
+			),
+		},
+		"changed set reported as a whole": {
+			cty.ObjectVal(map[string]cty.Value{
+				"groups": cty.SetVal([]cty.Value{cty.StringVal("a")}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"groups": cty.SetVal([]cty.Value{cty.StringVal("b")}),
+			}),
+			cty.NewPathSet(cty.GetAttrPath("groups")),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := changedValuePaths(test.Before, test.After)
+			if !got.Equal(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got.List(), test.Want.List())
+			}
+		})
+	}
+}
+
 func deepObjectValue(depth int) cty.Value {
 	v := cty.ObjectVal(map[string]cty.Value{
 		"a": cty.StringVal("a"),
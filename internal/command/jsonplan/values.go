@@ -153,9 +153,6 @@ func marshalPlannedValues(changes *plans.Changes, schemas *terraform.Schemas) (m
 	if err != nil {
 		return ret, err
 	}
-	sort.Slice(childModules, func(i, j int) bool {
-		return childModules[i].Address < childModules[j].Address
-	})
 
 	ret.ChildModules = childModules
 
@@ -278,5 +275,11 @@ func marshalPlanModules(
 		ret = append(ret, cm)
 	}
 
+	// sort the child modules by address for consistency at every level of
+	// nesting, not just at the root.
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Address < ret[j].Address
+	})
+
 	return ret, nil
 }
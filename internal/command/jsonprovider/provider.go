@@ -3,6 +3,7 @@ package jsonprovider
 import (
 	"encoding/json"
 
+	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/terraform"
 )
 
@@ -42,6 +43,36 @@ func Marshal(s *terraform.Schemas) ([]byte, error) {
 	return ret, err
 }
 
+// singleSchema is the self-describing document produced by
+// MarshalSingleSchema when exporting schemas as one JSON file per resource
+// type.
+type singleSchema struct {
+	FormatVersion string  `json:"format_version"`
+	Provider      string  `json:"provider"`
+	Mode          string  `json:"mode"`
+	ResourceType  string  `json:"resource_type,omitempty"`
+	Schema        *schema `json:"schema,omitempty"`
+}
+
+// MarshalSingleSchema returns a JSON document describing a single schema
+// belonging to the given provider source address, for use when writing one
+// file per resource type. Mode is "managed" for a managed resource type,
+// "data" for a data source, or "provider" for the provider's own
+// configuration schema, in which case resourceType is empty.
+func MarshalSingleSchema(provider, mode, resourceType string, block *configschema.Block, version uint64) ([]byte, error) {
+	doc := &singleSchema{
+		FormatVersion: FormatVersion,
+		Provider:      provider,
+		Mode:          mode,
+		ResourceType:  resourceType,
+	}
+	if block != nil {
+		doc.Schema = marshalSchema(block)
+		doc.Schema.Version = version
+	}
+	return json.Marshal(doc)
+}
+
 func marshalProvider(tps *terraform.ProviderSchema) *Provider {
 	if tps == nil {
 		return &Provider{}
@@ -18,7 +18,12 @@ import (
 // FormatVersion represents the version of the json format and will be
 // incremented for any change to this format that requires changes to a
 // consuming parser.
-const FormatVersion = "1.0"
+//
+// Version 1.1 guarantees that resources are sorted by address (and then by
+// deposed key), and that child modules are sorted by address at every level
+// of nesting, so the output for a given state is always byte-for-byte
+// identical.
+const FormatVersion = "1.1"
 
 // state is the top-level representation of the json format of a terraform
 // state.
@@ -401,7 +406,14 @@ func marshalResources(resources map[string]*states.Resource, module addrs.Module
 	}
 
 	sort.Slice(ret, func(i, j int) bool {
-		return ret[i].Address < ret[j].Address
+		if ret[i].Address != ret[j].Address {
+			return ret[i].Address < ret[j].Address
+		}
+		// An instance and its deposed objects share an address, and the
+		// deposed objects come from ranging over a map, so we must fall
+		// back on the deposed key for a deterministic order among them.
+		// A current object has no deposed key and so sorts first.
+		return ret[i].DeposedKey < ret[j].DeposedKey
 	})
 
 	return ret, nil
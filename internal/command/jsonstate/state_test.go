@@ -1,6 +1,7 @@
 package jsonstate
 
 import (
+	"bytes"
 	"encoding/json"
 	"reflect"
 	"testing"
@@ -564,6 +565,104 @@ func TestMarshalResources(t *testing.T) {
 	}
 }
 
+func TestMarshalResources_deterministicOrder(t *testing.T) {
+	// Resources, their instances, and their deposed objects are all stored
+	// in maps, so this test re-marshals the same input many times to verify
+	// that the result is sorted by address and then by deposed key, rather
+	// than by map iteration order.
+	resources := map[string]*states.Resource{
+		"test_thing.bar": {
+			Addr: addrs.AbsResource{
+				Resource: addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_thing",
+					Name: "bar",
+				},
+			},
+			Instances: map[addrs.InstanceKey]*states.ResourceInstance{
+				addrs.NoKey: {
+					Current: &states.ResourceInstanceObjectSrc{
+						Status:    states.ObjectReady,
+						AttrsJSON: []byte(`{"woozles":"confuzles"}`),
+					},
+					Deposed: map[states.DeposedKey]*states.ResourceInstanceObjectSrc{
+						states.DeposedKey("00000002"): {
+							Status:    states.ObjectReady,
+							AttrsJSON: []byte(`{"woozles":"confuzles"}`),
+						},
+						states.DeposedKey("00000001"): {
+							Status:    states.ObjectReady,
+							AttrsJSON: []byte(`{"woozles":"confuzles"}`),
+						},
+					},
+				},
+			},
+			ProviderConfig: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		},
+		"test_thing.foo": {
+			Addr: addrs.AbsResource{
+				Resource: addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_thing",
+					Name: "foo",
+				},
+			},
+			Instances: map[addrs.InstanceKey]*states.ResourceInstance{
+				addrs.NoKey: {
+					Current: &states.ResourceInstanceObjectSrc{
+						Status:    states.ObjectReady,
+						AttrsJSON: []byte(`{"woozles":"confuzles"}`),
+					},
+				},
+			},
+			ProviderConfig: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		},
+	}
+
+	wantOrder := []struct {
+		address    string
+		deposedKey string
+	}{
+		{"test_thing.bar", ""},
+		{"test_thing.bar", "00000001"},
+		{"test_thing.bar", "00000002"},
+		{"test_thing.foo", ""},
+	}
+
+	var wantJSON []byte
+	for i := 0; i < 20; i++ {
+		got, err := marshalResources(resources, addrs.RootModuleInstance, testSchemas())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(got) != len(wantOrder) {
+			t.Fatalf("wrong number of resources %d; want %d", len(got), len(wantOrder))
+		}
+		for j, want := range wantOrder {
+			if got[j].Address != want.address || got[j].DeposedKey != want.deposedKey {
+				t.Fatalf("wrong resource at index %d: %s (deposed key %q); want %s (deposed key %q)", j, got[j].Address, got[j].DeposedKey, want.address, want.deposedKey)
+			}
+		}
+
+		gotJSON, err := json.Marshal(got)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if wantJSON == nil {
+			wantJSON = gotJSON
+		} else if !bytes.Equal(gotJSON, wantJSON) {
+			t.Fatalf("inconsistent result on iteration %d\ngot:  %s\nwant: %s", i, gotJSON, wantJSON)
+		}
+	}
+}
+
 func TestMarshalModules_basic(t *testing.T) {
 	childModule, _ := addrs.ParseModuleInstanceStr("module.child")
 	subModule, _ := addrs.ParseModuleInstanceStr("module.submodule")
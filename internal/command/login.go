@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"strings"
+	"time"
 
 	tfe "github.com/hashicorp/go-tfe"
 	svchost "github.com/hashicorp/terraform-svchost"
@@ -30,6 +31,13 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// oauthDeviceCodeGrant is the grant type keyword for the OAuth 2.0 device
+// authorization grant defined in RFC 8628. The disco library predates that
+// grant type and so doesn't define a symbol for it, but it does preserve
+// unrecognized grant type keywords from the discovery document, so we can
+// match on the keyword here ourselves.
+const oauthDeviceCodeGrant = disco.OAuthGrantType("device_code")
+
 // LoginCommand is a Command implementation that runs an interactive login
 // flow for a remote service host. It then stashes credentials in a tfrc
 // file in the user's home directory.
@@ -185,6 +193,11 @@ func (c *LoginCommand) Run(args []string) int {
 		case clientConfig.SupportedGrantTypes.Has(disco.OAuthAuthzCodeGrant):
 			// We prefer an OAuth code grant if the server supports it.
 			oauthToken, tokenDiags = c.interactiveGetTokenByCode(hostname, credsCtx, clientConfig)
+		case clientConfig.SupportedGrantTypes.Has(oauthDeviceCodeGrant):
+			// The device authorization grant is for hosts whose login pages
+			// can't redirect back to a temporary server on this computer,
+			// such as when Terraform is running in a container or over SSH.
+			oauthToken, tokenDiags = c.interactiveGetTokenByDevice(hostname, credsCtx, clientConfig)
 		case clientConfig.SupportedGrantTypes.Has(disco.OAuthOwnerPasswordGrant) && hostname == svchost.Hostname("app.terraform.io"):
 			// The password grant type is allowed only for Terraform Cloud SaaS.
 			// Note this case is purely theoretical at this point, as TFC currently uses
@@ -529,6 +542,187 @@ func (c *LoginCommand) interactiveGetTokenByCode(hostname svchost.Hostname, cred
 	return token, diags
 }
 
+func (c *LoginCommand) interactiveGetTokenByDevice(hostname svchost.Hostname, credsCtx *loginCredentialsContext, clientConfig *disco.OAuthClient) (*oauth2.Token, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	confirm, confirmDiags := c.interactiveContextConsent(hostname, oauthDeviceCodeGrant, credsCtx)
+	diags = diags.Append(confirmDiags)
+	if !confirm {
+		diags = diags.Append(errors.New("Login cancelled"))
+		return nil, diags
+	}
+
+	// The login protocol's discovery object has no separate property for a
+	// device authorization endpoint, so by convention a host offering the
+	// device_code grant type uses its "authz" URL as the device
+	// authorization endpoint described in RFC 8628 section 3.1.
+	if clientConfig.AuthorizationURL == nil || clientConfig.TokenURL == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Host does not support Terraform login",
+			fmt.Sprintf("The given hostname %q offers the OAuth device authorization grant but does not declare both the \"authz\" and \"token\" endpoints that grant type requires.", hostname.ForDisplay()),
+		))
+		return nil, diags
+	}
+
+	httpClient := httpclient.New()
+
+	authzReq := url.Values{"client_id": {clientConfig.ID}}
+	if len(clientConfig.Scopes) > 0 {
+		authzReq.Set("scope", strings.Join(clientConfig.Scopes, " "))
+	}
+	authzResp, err := httpClient.PostForm(clientConfig.AuthorizationURL.String(), authzReq)
+	var authzBody []byte
+	if err == nil {
+		authzBody, err = ioutil.ReadAll(authzResp.Body)
+		authzResp.Body.Close()
+		if err == nil && authzResp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("server returned %s", authzResp.Status)
+		}
+	}
+	var devAuthz struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	if err == nil {
+		err = json.Unmarshal(authzBody, &devAuthz)
+		if err == nil && (devAuthz.DeviceCode == "" || devAuthz.UserCode == "" || devAuthz.VerificationURI == "") {
+			err = fmt.Errorf("response is missing required properties")
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to request device authorization",
+			fmt.Sprintf("The remote server did not issue a device authorization: %s.", err),
+		))
+		return nil, diags
+	}
+
+	// Some servers offer a URL with the user code already embedded, which
+	// we'll prefer for the browser launch, but we always show the plain
+	// URL and code so that the user can type them on another device.
+	verificationURL := devAuthz.VerificationURIComplete
+	if verificationURL == "" {
+		verificationURL = devAuthz.VerificationURI
+	}
+
+	launchBrowserManually := false
+	if c.BrowserLauncher != nil {
+		err = c.BrowserLauncher.OpenURL(verificationURL)
+		if err == nil {
+			c.Ui.Output(fmt.Sprintf("Terraform must now open a web browser to the login page for %s.\n", hostname.ForDisplay()))
+			c.Ui.Output(fmt.Sprintf("If a browser does not open this automatically, open the following URL to proceed:\n    %s\n", devAuthz.VerificationURI))
+		} else {
+			// Assume we're on a platform where opening a browser isn't
+			// possible; the whole point of the device authorization grant
+			// is that the login page can be visited from another device.
+			launchBrowserManually = true
+		}
+	} else {
+		launchBrowserManually = true
+	}
+
+	if launchBrowserManually {
+		c.Ui.Output(fmt.Sprintf("Open the following URL on any device to access the login page for %s:\n    %s\n", hostname.ForDisplay(), devAuthz.VerificationURI))
+	}
+
+	c.Ui.Output(fmt.Sprintf("When prompted, enter the following code to confirm this login:\n    %s\n", devAuthz.UserCode))
+	c.Ui.Output("Terraform will now poll the host until the login is complete.\n")
+
+	interval := time.Duration(devAuthz.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second // default polling interval per RFC 8628
+	}
+	expiry := time.Duration(devAuthz.ExpiresIn) * time.Second
+	if expiry <= 0 {
+		expiry = 15 * time.Minute
+	}
+	deadline := time.Now().Add(expiry)
+
+	for {
+		if time.Now().After(deadline) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to obtain auth token",
+				"The login code expired before the login was confirmed. Run \"terraform login\" again to retry.",
+			))
+			return nil, diags
+		}
+		time.Sleep(interval)
+
+		tokenResp, err := httpClient.PostForm(clientConfig.TokenURL.String(), url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {devAuthz.DeviceCode},
+			"client_id":   {clientConfig.ID},
+		})
+		var tokenBody []byte
+		if err == nil {
+			tokenBody, err = ioutil.ReadAll(tokenResp.Body)
+			tokenResp.Body.Close()
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to obtain auth token",
+				fmt.Sprintf("The remote server did not assign an auth token: %s.", err),
+			))
+			return nil, diags
+		}
+
+		if tokenResp.StatusCode == http.StatusOK {
+			var token oauth2.Token
+			if err := json.Unmarshal(tokenBody, &token); err != nil || token.AccessToken == "" {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to obtain auth token",
+					"The remote server returned an invalid response to the token request.",
+				))
+				return nil, diags
+			}
+			return &token, diags
+		}
+
+		var tokenErr struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(tokenBody, &tokenErr) // if this fails we'll catch it as an unexpected error code below
+
+		switch tokenErr.Error {
+		case "authorization_pending":
+			// The user hasn't confirmed the login yet, so we'll keep polling.
+		case "slow_down":
+			// RFC 8628 requires adding five seconds to the polling interval.
+			interval += 5 * time.Second
+		case "expired_token":
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to obtain auth token",
+				"The login code expired before the login was confirmed. Run \"terraform login\" again to retry.",
+			))
+			return nil, diags
+		case "access_denied":
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to obtain auth token",
+				"The login request was denied.",
+			))
+			return nil, diags
+		default:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to obtain auth token",
+				fmt.Sprintf("The remote server did not assign an auth token: %s.", tokenResp.Status),
+			))
+			return nil, diags
+		}
+	}
+}
+
 func (c *LoginCommand) interactiveGetTokenByPassword(hostname svchost.Hostname, credsCtx *loginCredentialsContext, clientConfig *disco.OAuthClient) (*oauth2.Token, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -70,6 +70,17 @@ func TestLogin(t *testing.T) {
 					"scopes": []interface{}{"app1.full_access", "app2.read_only"},
 				},
 			})
+			svcs.ForceHostServices(svchost.Hostname("device.example.com"), map[string]interface{}{
+				"login.v1": map[string]interface{}{
+					// This fake hostname supports only the device
+					// authorization grant, re-using the "authz" property
+					// as the device authorization endpoint by convention.
+					"client":      "device_test",
+					"authz":       s.URL + "/device",
+					"token":       s.URL + "/token",
+					"grant_types": []interface{}{"device_code"},
+				},
+			})
 			svcs.ForceHostServices(svchost.Hostname("app.terraform.io"), map[string]interface{}{
 				// This represents Terraform Cloud, which does not yet support the
 				// login API, but does support its own bespoke tokens API.
@@ -159,6 +170,35 @@ func TestLogin(t *testing.T) {
 		}
 	}))
 
+	t.Run("device.example.com with device authorization flow", loginTestCase(func(t *testing.T, c *LoginCommand, ui *cli.MockUi) {
+		// Enter "yes" at the consent prompt. No further input is needed
+		// because the stub server treats the login as already confirmed
+		// by the time we first poll the token endpoint.
+		defer testInputMap(t, map[string]string{
+			"approve": "yes",
+		})()
+		status := c.Run([]string{"device.example.com"})
+		if status != 0 {
+			t.Fatalf("unexpected error code %d\nstderr:\n%s", status, ui.ErrorWriter.String())
+		}
+
+		credsSrc := c.Services.CredentialsSource()
+		creds, err := credsSrc.ForHost(svchost.Hostname("device.example.com"))
+		if err != nil {
+			t.Errorf("failed to retrieve credentials: %s", err)
+		}
+		if got, want := creds.Token(), "good-token"; got != want {
+			t.Errorf("wrong token %q; want %q", got, want)
+		}
+
+		if got, want := ui.OutputWriter.String(), "enter the following code to confirm this login:\n    TFTF-TEST"; !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, but was:\n%s", want, got)
+		}
+		if got, want := ui.OutputWriter.String(), "Terraform has obtained and saved an API token."; !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, but was:\n%s", want, got)
+		}
+	}))
+
 	t.Run("with-scopes.example.com with authorization code flow and scopes", loginTestCase(func(t *testing.T, c *LoginCommand, ui *cli.MockUi) {
 		// Enter "yes" at the consent prompt.
 		defer testInputMap(t, map[string]string{
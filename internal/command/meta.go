@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"github.com/hashicorp/terraform/internal/configs/configload"
 	"github.com/hashicorp/terraform/internal/getproviders"
 	legacy "github.com/hashicorp/terraform/internal/legacy/terraform"
+	tfplugin "github.com/hashicorp/terraform/internal/plugin"
 	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/provisioners"
 	"github.com/hashicorp/terraform/internal/terminal"
@@ -101,6 +103,37 @@ type Meta struct {
 	// into the given directory.
 	PluginCacheDir string
 
+	// ProviderAuditLogPath, from the provider_installation_audit_log CLI
+	// configuration setting, names a file to which the provider installer
+	// appends a JSON line describing each installation decision it makes.
+	ProviderAuditLogPath string
+
+	// ModuleMirrorDir, if non-empty, causes registry module sources to be
+	// resolved from the given local mirror directory (laid out by
+	// "terraform modules mirror") instead of from the origin registries.
+	ModuleMirrorDir string
+
+	// OperationHistoryDisabled, from the disable_operation_history CLI
+	// configuration setting, suppresses the recording of the local history
+	// of recent operations in the working directory.
+	OperationHistoryDisabled bool
+
+	// PlanGateProgram is an optional external program, from the
+	// plan_gate_program CLI configuration setting, that is run with the
+	// JSON plan on its stdin before any apply. A non-zero exit status
+	// aborts the apply with the program's output.
+	PlanGateProgram string
+
+	// ReadonlyWorkspacePatterns is a set of glob patterns, from the
+	// readonly_workspaces CLI configuration setting, for workspace names
+	// in which Terraform must refuse to run any operation that would
+	// modify the workspace's state.
+	ReadonlyWorkspacePatterns []string
+
+	// PluginGRPCClient adjusts the gRPC channels between Terraform and its
+	// plugin processes, from the plugin_grpc_* CLI configuration settings.
+	PluginGRPCClient tfplugin.GRPCClientConfig
+
 	// ProviderSource allows determining the available versions of a provider
 	// and determines where a distribution package for a particular
 	// provider version can be obtained.
@@ -121,6 +154,12 @@ type Meta struct {
 	// checksums they have.
 	ProviderDevOverrides map[addrs.Provider]getproviders.PackageLocalDir
 
+	// CommandName is the name of the CLI subcommand this Meta belongs to,
+	// such as "plan" or "state mv". It is used to decide whether
+	// command-scoped entries in a project-local development overrides file
+	// are in effect for the current run.
+	CommandName string
+
 	// UnmanagedProviders are a set of providers that exist as processes
 	// predating Terraform, which Terraform should use but not worry about the
 	// lifecycle of.
@@ -140,6 +179,13 @@ type Meta struct {
 	// This overrides all other search paths when discovering plugins.
 	pluginPath []string
 
+	// localDevOverrides caches the result of parsing the project-local
+	// development overrides file, along with any diagnostics produced while
+	// parsing it, so that we read the file at most once per run.
+	localDevOverrides       []*localDevOverride
+	localDevOverridesDiags  tfdiags.Diagnostics
+	localDevOverridesLoaded bool
+
 	// Override certain behavior for tests within this package
 	testingOverrides *testingOverrides
 
@@ -157,6 +203,7 @@ type Meta struct {
 
 	// Variables for the context (private)
 	variableArgs rawFlags
+	varProgram   string
 	input        bool
 
 	// Targets for this context (private)
@@ -185,6 +232,12 @@ type Meta struct {
 	// parallelism is used to control the number of concurrent operations
 	// allowed when walking the graph
 	//
+	// refreshParallelism, if non-zero, is a separate concurrency limit for
+	// the graph walk that refreshes existing objects during planning
+	//
+	// operationTimeouts associates resource instance addresses with deadlines
+	// for the provider calls that apply their changes
+	//
 	// provider is to specify specific resource providers
 	//
 	// stateLock is set to false to disable state locking
@@ -192,6 +245,9 @@ type Meta struct {
 	// stateLockTimeout is the optional duration to retry a state locks locks
 	// when it is already locked by another process.
 	//
+	// stateLockWait makes Terraform keep retrying a contended state lock
+	// until it is released, rather than failing on the first attempt.
+	//
 	// forceInitCopy suppresses confirmation for copying state data during
 	// init.
 	//
@@ -202,16 +258,33 @@ type Meta struct {
 	//
 	// compactWarnings (-compact-warnings) selects a more compact presentation
 	// of warnings in the output when they are not accompanied by errors.
-	statePath        string
-	stateOutPath     string
-	backupPath       string
-	parallelism      int
-	stateLock        bool
-	stateLockTimeout time.Duration
-	forceInitCopy    bool
-	reconfigure      bool
-	migrateState     bool
-	compactWarnings  bool
+	statePath          string
+	stateOutPath       string
+	backupPath         string
+	parallelism        int
+	refreshParallelism int
+	operationTimeouts  map[string]time.Duration
+	stateLock          bool
+	stateLockTimeout   time.Duration
+	stateLockWait      bool
+	forceInitCopy      bool
+	reconfigure        bool
+	migrateState       bool
+	compactWarnings    bool
+
+	// migrateStateDryRun (-migrate-state -dry-run) reports workspace by
+	// workspace what a state migration would do, without writing anything,
+	// and then aborts backend initialization.
+	migrateStateDryRun bool
+
+	// migrateWorkspaces (-migrate-workspaces) optionally limits a state
+	// migration to the named workspaces. Empty means all workspaces.
+	migrateWorkspaces []string
+
+	// noSchemaCache (-no-schema-cache) disables reuse of provider schemas
+	// cached on disk from earlier runs, forcing a fresh fetch from each
+	// provider.
+	noSchemaCache bool
 
 	// Used with the import command to allow import of state when no matching config exists.
 	allowMissingConfig bool
@@ -219,6 +292,15 @@ type Meta struct {
 	// Used with commands which write state to allow users to write remote
 	// state even if the remote and local Terraform versions don't match.
 	ignoreRemoteVersion bool
+
+	// Used with commands which install modules to allow selection of
+	// registry module versions that the registry has yanked.
+	allowYankedModules bool
+
+	// Used with "terraform init" to forbid any installation step that
+	// would require network access, so that providers and modules must
+	// come from local mirrors or what is already installed.
+	offline bool
 }
 
 type testingOverrides struct {
@@ -451,6 +533,8 @@ func (m *Meta) contextOpts() (*terraform.ContextOpts, error) {
 
 	opts.UIInput = m.UIInput()
 	opts.Parallelism = m.parallelism
+	opts.RefreshParallelism = m.refreshParallelism
+	opts.OperationTimeouts = m.operationTimeouts
 
 	// If testingOverrides are set, we'll skip the plugin discovery process
 	// and just work with what we've been given, thus allowing the tests
@@ -679,6 +763,71 @@ func (m *Meta) Workspace() (string, error) {
 	return current, nil
 }
 
+// workspaceNameIsReadOnly returns true if the given workspace name matches
+// one of the readonly_workspaces patterns from the CLI configuration, and
+// should therefore reject any operation that would modify its state.
+func (m *Meta) workspaceNameIsReadOnly(workspace string) bool {
+	for _, pattern := range m.ReadonlyWorkspacePatterns {
+		// Pattern validity was checked when the CLI configuration was
+		// loaded, so we can ignore the error here.
+		if matched, _ := path.Match(pattern, workspace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// workspaceIsReadOnly is like workspaceNameIsReadOnly but for the
+// currently-selected workspace.
+func (m *Meta) workspaceIsReadOnly() bool {
+	if len(m.ReadonlyWorkspacePatterns) == 0 {
+		return false
+	}
+	workspace, err := m.Workspace()
+	if err != nil {
+		return false
+	}
+	return m.workspaceNameIsReadOnly(workspace)
+}
+
+// requireWritableWorkspace returns an error diagnostic if the current
+// workspace is configured as read-only via the readonly_workspaces CLI
+// configuration setting, and no diagnostics at all otherwise.
+//
+// Commands that would modify the workspace's state call this before doing
+// any real work. The check is independent of state locking, so it cannot
+// be bypassed using -lock=false.
+func (m *Meta) requireWritableWorkspace(operation string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !m.workspaceIsReadOnly() {
+		return diags
+	}
+	workspace, _ := m.Workspace()
+	return diags.Append(m.readOnlyWorkspaceDiagnostic(workspace, operation))
+}
+
+// requireWritableWorkspaceName is like requireWritableWorkspace but checks a
+// specific workspace name rather than the currently-selected workspace, for
+// commands like "workspace delete" that operate on another workspace.
+func (m *Meta) requireWritableWorkspaceName(workspace, operation string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !m.workspaceNameIsReadOnly(workspace) {
+		return diags
+	}
+	return diags.Append(m.readOnlyWorkspaceDiagnostic(workspace, operation))
+}
+
+func (m *Meta) readOnlyWorkspaceDiagnostic(workspace, operation string) tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		"Workspace is read-only",
+		fmt.Sprintf(
+			"The workspace %q matches one of the readonly_workspaces patterns in the CLI configuration, so Terraform will not %s in it.\n\nSelect a different workspace, or remove the matching pattern from the CLI configuration, to allow this operation.",
+			workspace, operation,
+		),
+	)
+}
+
 // WorkspaceOverridden returns the name of the currently configured workspace,
 // corresponding to the desired named state, as well as a bool saying whether
 // this was set via the TF_WORKSPACE environment variable.
@@ -716,10 +865,13 @@ func (m *Meta) SetWorkspace(name string) error {
 	return nil
 }
 
-// isAutoVarFile determines if the file ends with .auto.tfvars or .auto.tfvars.json
+// isAutoVarFile determines if the file ends with .auto.tfvars or one of its
+// .json, .yaml or .yml variants
 func isAutoVarFile(path string) bool {
 	return strings.HasSuffix(path, ".auto.tfvars") ||
-		strings.HasSuffix(path, ".auto.tfvars.json")
+		strings.HasSuffix(path, ".auto.tfvars.json") ||
+		strings.HasSuffix(path, ".auto.tfvars.yaml") ||
+		strings.HasSuffix(path, ".auto.tfvars.yml")
 }
 
 // FIXME: as an interim refactoring step, we apply the contents of the state
@@ -728,6 +880,7 @@ func isAutoVarFile(path string) bool {
 func (m *Meta) applyStateArguments(args *arguments.State) {
 	m.stateLock = args.Lock
 	m.stateLockTimeout = args.LockTimeout
+	m.stateLockWait = args.LockWait
 	m.statePath = args.StatePath
 	m.stateOutPath = args.StateOutPath
 	m.backupPath = args.BackupPath
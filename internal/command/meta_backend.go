@@ -391,7 +391,11 @@ func (m *Meta) backendCLIOpts() (*backend.CLIOpts, error) {
 // This prepares the operation. After calling this, the caller is expected
 // to modify fields of the operation such as Sequence to specify what will
 // be called.
-func (m *Meta) Operation(b backend.Backend) *backend.Operation {
+//
+// The given view type determines how progress messages from the state
+// locker are rendered, so it should match the view the command itself is
+// rendering its output with.
+func (m *Meta) Operation(b backend.Backend, vt arguments.ViewType) *backend.Operation {
 	schema := b.ConfigSchema()
 	workspace, err := m.Workspace()
 	if err != nil {
@@ -411,8 +415,8 @@ func (m *Meta) Operation(b backend.Backend) *backend.Operation {
 
 	stateLocker := clistate.NewNoopLocker()
 	if m.stateLock {
-		view := views.NewStateLocker(arguments.ViewHuman, m.View)
-		stateLocker = clistate.NewLocker(m.stateLockTimeout, view)
+		view := views.NewStateLocker(vt, m.View)
+		stateLocker = clistate.NewLockerWithWait(m.stateLockTimeout, m.stateLockWait, view)
 	}
 
 	depLocks, diags := m.lockedDependencies()
@@ -433,6 +437,9 @@ func (m *Meta) Operation(b backend.Backend) *backend.Operation {
 		Workspace:       workspace,
 		StateLocker:     stateLocker,
 		DependencyLocks: depLocks,
+
+		// No destroy limit unless the apply command sets one explicitly.
+		AutoApproveMaxDestroy: -1,
 	}
 }
 
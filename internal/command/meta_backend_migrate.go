@@ -68,6 +68,31 @@ func (m *Meta) backendMigrateState(opts *backendMigrateOpts) error {
 	opts.destinationWorkspace = backend.DefaultStateName
 	opts.force = m.forceInitCopy
 
+	if len(m.migrateWorkspaces) > 0 {
+		if sourceTFC || destinationTFC {
+			return errors.New("The -migrate-workspaces option is not supported when migrating to or from Terraform Cloud.")
+		}
+		for _, name := range m.migrateWorkspaces {
+			found := false
+			for _, have := range sourceWorkspaces {
+				if have == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("The -migrate-workspaces option names workspace %q, which does not exist in the source backend.", name)
+			}
+		}
+	}
+
+	if m.migrateStateDryRun {
+		if sourceTFC || destinationTFC {
+			return errors.New("The -dry-run option is not supported when migrating to or from Terraform Cloud.")
+		}
+		return m.backendMigrateStateDryRun(opts, sourceWorkspaces, sourceSingleState, destinationSingleState)
+	}
+
 	// Disregard remote Terraform version for the state source backend. If it's a
 	// Terraform Cloud remote backend, we don't care about the remote version,
 	// as we are migrating away and will not break a remote workspace.
@@ -140,6 +165,124 @@ func (m *Meta) backendMigrateState(opts *backendMigrateOpts) error {
 	return nil
 }
 
+// migrateWorkspaceIncluded returns true if the given workspace name was
+// selected by the -migrate-workspaces option, or if no such limit was set.
+func (m *Meta) migrateWorkspaceIncluded(name string) bool {
+	if len(m.migrateWorkspaces) == 0 {
+		return true
+	}
+	for _, include := range m.migrateWorkspaces {
+		if include == name {
+			return true
+		}
+	}
+	return false
+}
+
+// backendMigrateStateDryRun reports, workspace by workspace, what a state
+// migration between the two backends in opts would do, without copying
+// anything. It always returns an error so that backend initialization is
+// aborted and any previously-stored backend configuration is left unchanged.
+func (m *Meta) backendMigrateStateDryRun(opts *backendMigrateOpts, sourceWorkspaces []string, sourceSingleState, destinationSingleState bool) error {
+	// Work out which (source, destination) workspace pairs the real
+	// migration would visit, mirroring the scenario selection in
+	// backendMigrateState.
+	type migratePair struct {
+		source, destination string
+	}
+	var pairs []migratePair
+	multiSource := !sourceSingleState && !(len(sourceWorkspaces) == 1 && sourceWorkspaces[0] == backend.DefaultStateName)
+	switch {
+	case !multiSource:
+		pairs = append(pairs, migratePair{backend.DefaultStateName, backend.DefaultStateName})
+	case destinationSingleState:
+		// Migrating to a single-state backend copies only the currently
+		// selected workspace.
+		current, err := m.Workspace()
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, migratePair{current, backend.DefaultStateName})
+	default:
+		sorted := make([]string, len(sourceWorkspaces))
+		copy(sorted, sourceWorkspaces)
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			if !m.migrateWorkspaceIncluded(name) {
+				continue
+			}
+			pairs = append(pairs, migratePair{name, name})
+		}
+	}
+
+	m.Ui.Output(fmt.Sprintf(
+		"Dry run: migrating state from backend %q to backend %q would do the following:\n",
+		opts.SourceType, opts.DestinationType))
+
+	for _, pair := range pairs {
+		line, err := m.backendMigrateDryRunPair(opts, pair.source, pair.destination)
+		if err != nil {
+			return err
+		}
+		m.Ui.Output("  - " + line)
+	}
+	m.Ui.Output("")
+
+	return errors.New(strings.TrimSpace(errMigrateDryRun))
+}
+
+// backendMigrateDryRunPair describes what migrating the given workspace pair
+// would do, without writing anything to either backend.
+func (m *Meta) backendMigrateDryRunPair(opts *backendMigrateOpts, sourceName, destinationName string) (string, error) {
+	sourceState, err := opts.Source.StateMgr(sourceName)
+	if err != nil {
+		return "", fmt.Errorf(strings.TrimSpace(
+			errMigrateSingleLoadDefault), opts.SourceType, err)
+	}
+	if err := sourceState.RefreshState(); err != nil {
+		return "", fmt.Errorf(strings.TrimSpace(
+			errMigrateSingleLoadDefault), opts.SourceType, err)
+	}
+	if sourceState.State().Empty() {
+		return fmt.Sprintf("workspace %q: source state is empty, so nothing would be copied", sourceName), nil
+	}
+
+	destinationState, err := opts.Destination.StateMgr(destinationName)
+	if err != nil {
+		return "", fmt.Errorf(strings.TrimSpace(
+			errMigrateSingleLoadDefault), opts.DestinationType, err)
+	}
+	if err := destinationState.RefreshState(); err != nil {
+		return "", fmt.Errorf(strings.TrimSpace(
+			errMigrateSingleLoadDefault), opts.DestinationType, err)
+	}
+
+	sourceMeta, haveSourceMeta := sourceState.(statemgr.PersistentMeta)
+	destinationMeta, haveDestinationMeta := destinationState.(statemgr.PersistentMeta)
+
+	if destinationState.State().Empty() {
+		if haveSourceMeta {
+			return fmt.Sprintf("workspace %q: would copy source state (serial %d) into the empty destination", sourceName, sourceMeta.StateSnapshotMeta().Serial), nil
+		}
+		return fmt.Sprintf("workspace %q: would copy source state into the empty destination", sourceName), nil
+	}
+
+	if haveSourceMeta && haveDestinationMeta {
+		sm := sourceMeta.StateSnapshotMeta()
+		dm := destinationMeta.StateSnapshotMeta()
+		switch {
+		case sm.Lineage != "" && dm.Lineage != "" && sm.Lineage != dm.Lineage:
+			return fmt.Sprintf("workspace %q: CONFLICT: would overwrite destination state from a different lineage (source serial %d, destination serial %d)", sourceName, sm.Serial, dm.Serial), nil
+		case dm.Serial > sm.Serial:
+			return fmt.Sprintf("workspace %q: CONFLICT: destination state (serial %d) is newer than source state (serial %d) and would be overwritten", sourceName, dm.Serial, sm.Serial), nil
+		default:
+			return fmt.Sprintf("workspace %q: would overwrite destination state (serial %d) with source state (serial %d)", sourceName, dm.Serial, sm.Serial), nil
+		}
+	}
+
+	return fmt.Sprintf("workspace %q: would overwrite the existing destination state", sourceName), nil
+}
+
 //-------------------------------------------------------------------
 // State Migration Scenarios
 //
@@ -194,6 +337,11 @@ func (m *Meta) backendMigrateState_S_S(opts *backendMigrateOpts) error {
 
 	// Go through each and migrate
 	for _, name := range sourceWorkspaces {
+		if !m.migrateWorkspaceIncluded(name) {
+			log.Printf("[INFO] backendMigrateState: skipping workspace %q, not selected by -migrate-workspaces", name)
+			continue
+		}
+
 		// Copy the same names
 		opts.sourceWorkspace = name
 		opts.destinationWorkspace = name
@@ -945,6 +1093,12 @@ source and the destination remain unmodified. Please resolve the
 above error and try again.
 `
 
+const errMigrateDryRun = `
+Dry run complete: no state was migrated and the backend configuration was
+left unchanged. Run the same command without -dry-run to perform the
+migration.
+`
+
 const errMigrateMulti = `
 Error migrating the workspace %q from the previous %q backend
 to the newly configured %q backend:
@@ -1531,6 +1531,12 @@ func TestMetaBackend_planLocal(t *testing.T) {
 	backendConfigBlock := cty.ObjectVal(map[string]cty.Value{
 		"path":          cty.NullVal(cty.String),
 		"workspace_dir": cty.NullVal(cty.String),
+		"encryption": cty.NullVal(cty.Object(map[string]cty.Type{
+			"passphrase":     cty.String,
+			"passphrase_env": cty.String,
+			"key_command":    cty.String,
+			"iterations":     cty.Number,
+		})),
 	})
 	backendConfigRaw, err := plans.NewDynamicValue(backendConfigBlock, backendConfigBlock.Type())
 	if err != nil {
@@ -1622,6 +1628,12 @@ func TestMetaBackend_planLocalStatePath(t *testing.T) {
 	backendConfigBlock := cty.ObjectVal(map[string]cty.Value{
 		"path":          cty.NullVal(cty.String),
 		"workspace_dir": cty.NullVal(cty.String),
+		"encryption": cty.NullVal(cty.Object(map[string]cty.Type{
+			"passphrase":     cty.String,
+			"passphrase_env": cty.String,
+			"key_command":    cty.String,
+			"iterations":     cty.Number,
+		})),
 	})
 	backendConfigRaw, err := plans.NewDynamicValue(backendConfigBlock, backendConfigBlock.Type())
 	if err != nil {
@@ -1721,6 +1733,12 @@ func TestMetaBackend_planLocalMatch(t *testing.T) {
 	backendConfigBlock := cty.ObjectVal(map[string]cty.Value{
 		"path":          cty.NullVal(cty.String),
 		"workspace_dir": cty.NullVal(cty.String),
+		"encryption": cty.NullVal(cty.Object(map[string]cty.Type{
+			"passphrase":     cty.String,
+			"passphrase_env": cty.String,
+			"key_command":    cty.String,
+			"iterations":     cty.Number,
+		})),
 	})
 	backendConfigRaw, err := plans.NewDynamicValue(backendConfigBlock, backendConfigBlock.Type())
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -15,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/earlyconfig"
 	"github.com/hashicorp/terraform/internal/initwd"
+	"github.com/hashicorp/terraform/internal/modsdir"
 	"github.com/hashicorp/terraform/internal/registry"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
@@ -346,7 +348,53 @@ func (m *Meta) initConfigLoader() (*configload.Loader, error) {
 // "terraform init" (directly or indirectly).
 func (m *Meta) moduleInstaller() *initwd.ModuleInstaller {
 	reg := m.registryClient()
-	return initwd.NewModuleInstaller(m.modulesDir(), reg)
+	inst := initwd.NewModuleInstaller(m.modulesDir(), reg)
+	if m.ModuleMirrorDir != "" {
+		inst.UseModuleMirror(m.ModuleMirrorDir)
+	}
+	if m.allowYankedModules {
+		inst.AllowYankedModules()
+	}
+	if m.offline {
+		inst.Offline()
+	}
+	return inst
+}
+
+// moduleDeprecationWarnings returns a condensed warning if any of the
+// modules recorded in the local modules manifest had versions marked as
+// deprecated by their origin registry at the time they were installed.
+//
+// The full deprecation details appear as warnings at install time; this
+// reminder exists because installation may happen long before whatever
+// change makes someone finally act on the deprecation.
+func (m *Meta) moduleDeprecationWarnings() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	manifest, err := modsdir.ReadManifestSnapshotForDir(m.modulesDir())
+	if err != nil {
+		// Any problem with the manifest will be reported properly during
+		// configuration loading, so it isn't our place to report it here.
+		return nil
+	}
+
+	var lines []string
+	for _, record := range manifest {
+		if record.Deprecation == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  - %s %s (%s): %s", record.Key, record.Version, record.SourceAddr, record.Deprecation))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	sort.Strings(lines)
+
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Deprecated module versions",
+		fmt.Sprintf("The following installed modules use versions that their registries have marked as deprecated:\n\n%s", strings.Join(lines, "\n")),
+	))
+	return diags
 }
 
 // registryClient instantiates and returns a new Terraform Registry client.
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/command/arguments"
+	"github.com/hashicorp/terraform/internal/command/workdir"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// recordOperationHistory adds the given entry to the working directory's
+// bounded history of recent operations, along with a saved JSON summary of
+// the entry, so that the "history" and "show -last" commands can describe
+// the operation after its output has scrolled away.
+//
+// History recording is best-effort: any failure to record is only logged,
+// because the operation itself already succeeded or failed on its own
+// terms. Recording is skipped entirely when disabled in the CLI
+// configuration.
+//
+// The given diagnostics are reduced to a condensed digest; neither they nor
+// the entry may carry variable values, state, or other sensitive data.
+func (m *Meta) recordOperationHistory(entry *workdir.OperationHistoryEntry, diags tfdiags.Diagnostics) {
+	if m.OperationHistoryDisabled || m.WorkingDir == nil {
+		return
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.DiagnosticsDigest == "" {
+		entry.DiagnosticsDigest = operationDiagnosticsDigest(diags)
+	}
+
+	summary := operationHistorySummary{
+		Command:   entry.Command,
+		Timestamp: entry.Timestamp,
+		Options:   entry.Options,
+		Result:    entry.Result,
+		Added:     entry.Added,
+		Changed:   entry.Changed,
+		Removed:   entry.Removed,
+	}
+	for _, diag := range diags {
+		desc := diag.Description()
+		severity := "warning"
+		if diag.Severity() == tfdiags.Error {
+			severity = "error"
+		}
+		summary.Diagnostics = append(summary.Diagnostics, operationHistoryDiagnostic{
+			Severity: severity,
+			Summary:  desc.Summary,
+			Detail:   desc.Detail,
+		})
+	}
+	summaryJSON, err := json.MarshalIndent(&summary, "", "  ")
+	if err != nil {
+		// Should never happen, since the summary is a plain data structure.
+		log.Printf("[WARN] failed to serialize operation history summary: %s", err)
+		summaryJSON = nil
+	}
+
+	if err := m.WorkingDir.AppendOperationHistory(entry, summaryJSON); err != nil {
+		log.Printf("[WARN] failed to record operation history: %s", err)
+	}
+}
+
+// operationHistorySummary is the structure of the JSON summary file saved
+// alongside each operation history entry.
+type operationHistorySummary struct {
+	Command     string                       `json:"command"`
+	Timestamp   time.Time                    `json:"timestamp"`
+	Options     string                       `json:"options,omitempty"`
+	Result      string                       `json:"result"`
+	Added       int                          `json:"added,omitempty"`
+	Changed     int                          `json:"changed,omitempty"`
+	Removed     int                          `json:"removed,omitempty"`
+	Diagnostics []operationHistoryDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type operationHistoryDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// operationHistoryResult translates an operation result into the result
+// string we record in operation history entries.
+func operationHistoryResult(successful bool) string {
+	if successful {
+		return "success"
+	}
+	return "failure"
+}
+
+// operationOptionsSummary produces a short human-readable summary of the
+// notable options an operation ran with, from the common operation
+// arguments plus any command-specific extras. It intentionally never
+// includes variable values.
+func operationOptionsSummary(op *arguments.Operation, extras ...string) string {
+	parts := append([]string(nil), extras...)
+	if op != nil {
+		switch op.PlanMode {
+		case plans.DestroyMode:
+			parts = append(parts, "-destroy")
+		case plans.RefreshOnlyMode:
+			parts = append(parts, "-refresh-only")
+		}
+		if !op.Refresh {
+			parts = append(parts, "-refresh=false")
+		}
+		if len(op.Targets) > 0 {
+			parts = append(parts, fmt.Sprintf("%d target(s)", len(op.Targets)))
+		}
+		if len(op.ForceReplace) > 0 {
+			parts = append(parts, fmt.Sprintf("%d forced replacement(s)", len(op.ForceReplace)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// operationDiagnosticsDigest condenses the given diagnostics into a short
+// single-line description suitable for an operation history entry.
+func operationDiagnosticsDigest(diags tfdiags.Diagnostics) string {
+	var errs, warns int
+	var firstErr string
+	for _, diag := range diags {
+		switch diag.Severity() {
+		case tfdiags.Error:
+			errs++
+			if firstErr == "" {
+				firstErr = diag.Description().Summary
+			}
+		case tfdiags.Warning:
+			warns++
+		}
+	}
+	switch {
+	case errs > 0:
+		return fmt.Sprintf("%d error(s), %d warning(s); first error: %s", errs, warns, firstErr)
+	case warns > 0:
+		return fmt.Sprintf("%d warning(s)", warns)
+	default:
+		return ""
+	}
+}
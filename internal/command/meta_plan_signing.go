@@ -0,0 +1,112 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/plans/planfile"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// keyProgramTimeout bounds how long a program named with the "cmd:" prefix
+// in -sign-key or -verify-key may run before we give up on it, so that a
+// hung key management tool cannot hang Terraform indefinitely.
+const keyProgramTimeout = 1 * time.Minute
+
+// loadKeyMaterial obtains the key material named by a -sign-key or
+// -verify-key option. Most values are the path of a file to read, but a
+// value with the "cmd:" prefix instead names a program (and optional
+// whitespace-separated arguments) to run, whose stdout is used as the key
+// material. The latter allows fetching keys from a secrets manager without
+// ever writing them to disk.
+func loadKeyMaterial(spec string) ([]byte, error) {
+	if rest := strings.TrimPrefix(spec, "cmd:"); rest != spec {
+		parts := strings.Fields(rest)
+		if len(parts) == 0 {
+			return nil, fmt.Errorf("no program name after the \"cmd:\" prefix")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), keyProgramTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+		out, err := cmd.Output()
+		if err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+				return nil, fmt.Errorf("key program failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+			}
+			return nil, fmt.Errorf("failed to run key program: %s", err)
+		}
+		return out, nil
+	}
+	return ioutil.ReadFile(spec)
+}
+
+// signPlanFile signs the saved plan file at the given path with the key
+// material named by the -sign-key option, writing a detached signature file
+// alongside it.
+func signPlanFile(planPath, keySpec string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	material, err := loadKeyMaterial(keySpec)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to load plan signing key",
+			fmt.Sprintf("Cannot load the key material given in -sign-key: %s.", err),
+		))
+		return diags
+	}
+	key, err := planfile.ParseSigningKey(material)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid plan signing key",
+			fmt.Sprintf("The key material given in -sign-key is not valid: %s.", err),
+		))
+		return diags
+	}
+	if err := planfile.Sign(planPath, key); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to sign plan file",
+			fmt.Sprintf("Cannot sign the saved plan file: %s.", err),
+		))
+	}
+	return diags
+}
+
+// verifyPlanFile checks the detached signature of the saved plan file at the
+// given path against the key material named by the -verify-key option.
+func verifyPlanFile(planPath, keySpec string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	material, err := loadKeyMaterial(keySpec)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to load plan verification key",
+			fmt.Sprintf("Cannot load the key material given in -verify-key: %s.", err),
+		))
+		return diags
+	}
+	key, err := planfile.ParseVerificationKey(material)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid plan verification key",
+			fmt.Sprintf("The key material given in -verify-key is not valid: %s.", err),
+		))
+		return diags
+	}
+	if err := planfile.Verify(planPath, key); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan signature verification failed",
+			fmt.Sprintf("Cannot verify the saved plan file: %s.", err),
+		))
+	}
+	return diags
+}
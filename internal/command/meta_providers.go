@@ -74,6 +74,9 @@ func (m *Meta) providerInstallerCustomSource(source getproviders.Source) *provid
 		unmanagedProviderTypes[ty] = struct{}{}
 	}
 	inst.SetUnmanagedProviderTypes(unmanagedProviderTypes)
+	if m.ProviderAuditLogPath != "" {
+		inst.SetAuditLog(providercache.NewAuditLog(m.ProviderAuditLogPath))
+	}
 	return inst
 }
 
@@ -155,6 +158,46 @@ func (m *Meta) providerInstallSource() getproviders.Source {
 	return m.ProviderSource
 }
 
+// providerInstallSourceWithoutNetwork returns an installation source
+// equivalent to the result of providerInstallSource but with any
+// installation methods that would require network access removed, in
+// support of "terraform init -offline".
+//
+// The result can be an empty source if no local installation methods are
+// configured, in which case only already-installed providers and the
+// plugin cache are usable.
+func (m *Meta) providerInstallSourceWithoutNetwork() getproviders.Source {
+	source := m.providerInstallSource()
+	multi, ok := source.(getproviders.MultiSource)
+	if !ok {
+		// Not a MultiSource, so this is one of our tests using a mock
+		// source directly. We'll treat it as local in that case.
+		return source
+	}
+	var local getproviders.MultiSource
+	for _, selector := range multi {
+		if providerSourceRequiresNetwork(selector.Source) {
+			continue
+		}
+		local = append(local, selector)
+	}
+	return local
+}
+
+// providerSourceRequiresNetwork decides whether the given provider
+// installation source would need to reach out over the network in order to
+// answer queries.
+func providerSourceRequiresNetwork(source getproviders.Source) bool {
+	switch s := source.(type) {
+	case *getproviders.MemoizeSource:
+		return providerSourceRequiresNetwork(s.Underlying())
+	case *getproviders.RegistrySource, *getproviders.HTTPMirrorSource:
+		return true
+	default:
+		return false
+	}
+}
+
 // providerDevOverrideInitWarnings returns a diagnostics that contains at
 // least one warning if and only if there is at least one provider development
 // override in effect. If not, the result is always empty. The result never
@@ -164,12 +207,13 @@ func (m *Meta) providerInstallSource() getproviders.Source {
 // may differ from what's expected due to the development overrides. For
 // other commands, providerDevOverrideRuntimeWarnings should be used.
 func (m *Meta) providerDevOverrideInitWarnings() tfdiags.Diagnostics {
-	if len(m.ProviderDevOverrides) == 0 {
+	overrides, _ := m.effectiveProviderDevOverrides()
+	if len(overrides) == 0 {
 		return nil
 	}
 	var detailMsg strings.Builder
-	detailMsg.WriteString("The following provider development overrides are set in the CLI configuration:\n")
-	for addr, path := range m.ProviderDevOverrides {
+	detailMsg.WriteString("The following provider development overrides are set in the CLI configuration or in the project-local overrides file:\n")
+	for addr, path := range overrides {
 		detailMsg.WriteString(fmt.Sprintf(" - %s in %s\n", addr.ForDisplay(), path))
 	}
 	detailMsg.WriteString("\nSkip terraform init when using provider development overrides. It is not necessary and may error unexpectedly.")
@@ -196,12 +240,13 @@ func (m *Meta) providerDevOverrideInitWarnings() tfdiags.Diagnostics {
 // See providerDevOverrideInitWarnings for warnings specific to the init
 // command.
 func (m *Meta) providerDevOverrideRuntimeWarnings() tfdiags.Diagnostics {
-	if len(m.ProviderDevOverrides) == 0 {
+	overrides, _ := m.effectiveProviderDevOverrides()
+	if len(overrides) == 0 {
 		return nil
 	}
 	var detailMsg strings.Builder
-	detailMsg.WriteString("The following provider development overrides are set in the CLI configuration:\n")
-	for addr, path := range m.ProviderDevOverrides {
+	detailMsg.WriteString("The following provider development overrides are set in the CLI configuration or in the project-local overrides file:\n")
+	for addr, path := range overrides {
 		detailMsg.WriteString(fmt.Sprintf(" - %s in %s\n", addr.ForDisplay(), path))
 	}
 	detailMsg.WriteString("\nThe behavior may therefore not match any released version of the provider and applying changes may cause the state to become incompatible with published releases.")
@@ -254,7 +299,9 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 	// - The CLI config can specify that a particular provider should always
 	// use a plugin from a particular local directory, ignoring anything the
 	// lock file or cache directory might have to say about it. This is useful
-	// for manual testing of local development builds.
+	// for manual testing of local development builds. A project-local
+	// overrides file can add further entries of the same sort, optionally
+	// scoped to particular workspaces or commands.
 	// - The Terraform SDK test harness (and possibly other callers in future)
 	// can ask that we use its own already-started provider servers, which we
 	// call "unmanaged" because Terraform isn't responsible for starting
@@ -265,7 +312,10 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 	// Unmanaged providers take precedence over overridden providers because
 	// overrides are typically a "session-level" setting while unmanaged
 	// providers are typically scoped to a single unattended command.
-	devOverrideProviders := m.ProviderDevOverrides
+	devOverrideProviders, overrideDiags := m.effectiveProviderDevOverrides()
+	if overrideDiags.HasErrors() {
+		return nil, fmt.Errorf("invalid %s file: %s", localDevOverridesFilename, overrideDiags.Err())
+	}
 	unmanagedProviders := m.UnmanagedProviders
 
 	factories := make(map[addrs.Provider]providers.Factory, len(providerLocks)+len(internalFactories)+len(unmanagedProviders))
@@ -316,13 +366,23 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 				continue
 			}
 		}
-		factories[provider] = providerFactory(cached)
+		// When the package matched a hash recorded in the lock file we can
+		// safely reuse schemas cached on disk by an earlier run, since the
+		// hash guarantees the provider binary is unchanged.
+		schemaCachePath := ""
+		if !m.noSchemaCache {
+			if hashes := lock.PreferredHashes(); len(hashes) > 0 {
+				schemaCachePath = providerSchemaCachePath(m.DataDir(), provider, version, hashes[0])
+			}
+		}
+
+		factories[provider] = providerFactory(cached, m.PluginGRPCClient, schemaCachePath)
 	}
 	for provider, localDir := range devOverrideProviders {
-		factories[provider] = devOverrideProviderFactory(provider, localDir)
+		factories[provider] = devOverrideProviderFactory(provider, localDir, m.PluginGRPCClient)
 	}
 	for provider, reattach := range unmanagedProviders {
-		factories[provider] = unmanagedProviderFactory(provider, reattach)
+		factories[provider] = unmanagedProviderFactory(provider, reattach, m.PluginGRPCClient)
 	}
 
 	var err error
@@ -346,7 +406,12 @@ func (m *Meta) internalProviders() map[string]providers.Factory {
 // providerFactory produces a provider factory that runs up the executable
 // file in the given cache package and uses go-plugin to implement
 // providers.Interface against it.
-func providerFactory(meta *providercache.CachedProvider) providers.Factory {
+//
+// If schemaCachePath is non-empty then the provider's schemas are cached in
+// that file and reused on later runs instead of being re-fetched from the
+// provider. Callers must only pass a path that is specific to the exact
+// package contents, such as by including a package hash in the filename.
+func providerFactory(meta *providercache.CachedProvider, grpcConfig tfplugin.GRPCClientConfig, schemaCachePath string) providers.Factory {
 	return func() (providers.Interface, error) {
 		execFile, err := meta.ExecutableFile()
 		if err != nil {
@@ -363,6 +428,7 @@ func providerFactory(meta *providercache.CachedProvider) providers.Factory {
 			VersionedPlugins: tfplugin.VersionedPlugins,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", meta.Provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", meta.Provider)),
+			GRPCDialOptions:  grpcConfig.DialOptions(),
 		}
 
 		client := plugin.NewClient(config)
@@ -382,34 +448,36 @@ func providerFactory(meta *providercache.CachedProvider) providers.Factory {
 		case 5:
 			p := raw.(*tfplugin.GRPCProvider)
 			p.PluginClient = client
-			return p, nil
+			return cachedSchemaProvider(p, schemaCachePath), nil
 		case 6:
 			p := raw.(*tfplugin6.GRPCProvider)
 			p.PluginClient = client
-			return p, nil
+			return cachedSchemaProvider(p, schemaCachePath), nil
 		default:
 			panic("unsupported protocol version")
 		}
 	}
 }
 
-func devOverrideProviderFactory(provider addrs.Provider, localDir getproviders.PackageLocalDir) providers.Factory {
+func devOverrideProviderFactory(provider addrs.Provider, localDir getproviders.PackageLocalDir, grpcConfig tfplugin.GRPCClientConfig) providers.Factory {
 	// A dev override is essentially a synthetic cache entry for our purposes
 	// here, so that's how we'll construct it. The providerFactory function
 	// doesn't actually care about the version, so we can leave it
 	// unspecified: overridden providers are not explicitly versioned.
+	// Schema caching is disabled for overridden providers, since the whole
+	// point of a dev override is that the binary may change at any time.
 	log.Printf("[DEBUG] Provider %s is overridden to load from %s", provider, localDir)
 	return providerFactory(&providercache.CachedProvider{
 		Provider:   provider,
 		Version:    getproviders.UnspecifiedVersion,
 		PackageDir: string(localDir),
-	})
+	}, grpcConfig, "")
 }
 
 // unmanagedProviderFactory produces a provider factory that uses the passed
 // reattach information to connect to go-plugin processes that are already
 // running, and implements providers.Interface against it.
-func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.ReattachConfig) providers.Factory {
+func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.ReattachConfig, grpcConfig tfplugin.GRPCClientConfig) providers.Factory {
 	return func() (providers.Interface, error) {
 		config := &plugin.ClientConfig{
 			HandshakeConfig:  tfplugin.Handshake,
@@ -419,6 +487,7 @@ func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.Reattach
 			Reattach:         reattach,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", provider)),
+			GRPCDialOptions:  grpcConfig.DialOptions(),
 		}
 
 		if reattach.ProtocolVersion == 0 {
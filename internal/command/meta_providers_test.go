@@ -0,0 +1,74 @@
+package command
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/getproviders"
+)
+
+func TestProviderInstallSourceWithoutNetwork(t *testing.T) {
+	fsSource := getproviders.NewFilesystemMirrorSource("testdata/empty")
+	mirrorURL, err := url.Parse("https://mirror.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpSource := getproviders.NewHTTPMirrorSource(mirrorURL, nil)
+	registrySource := getproviders.NewMemoizeSource(getproviders.NewRegistrySource(nil))
+
+	m := &Meta{
+		ProviderSource: getproviders.MultiSource{
+			{Source: fsSource},
+			{Source: registrySource},
+			{Source: httpSource},
+		},
+	}
+
+	got, ok := m.providerInstallSourceWithoutNetwork().(getproviders.MultiSource)
+	if !ok {
+		t.Fatalf("result is not a MultiSource")
+	}
+	if len(got) != 1 {
+		t.Fatalf("wrong number of remaining sources %d; want 1", len(got))
+	}
+	if got[0].Source != getproviders.Source(fsSource) {
+		t.Errorf("remaining source is not the filesystem mirror source")
+	}
+}
+
+func TestProviderSourceRequiresNetwork(t *testing.T) {
+	mirrorURL, err := url.Parse("https://mirror.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		source getproviders.Source
+		want   bool
+	}{
+		"filesystem mirror": {
+			getproviders.NewFilesystemMirrorSource("testdata/empty"),
+			false,
+		},
+		"http mirror": {
+			getproviders.NewHTTPMirrorSource(mirrorURL, nil),
+			true,
+		},
+		"registry": {
+			getproviders.NewRegistrySource(nil),
+			true,
+		},
+		"memoized registry": {
+			getproviders.NewMemoizeSource(getproviders.NewRegistrySource(nil)),
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := providerSourceRequiresNetwork(test.source); got != test.want {
+				t.Errorf("wrong result %t; want %t", got, test.want)
+			}
+		})
+	}
+}
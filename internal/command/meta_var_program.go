@@ -0,0 +1,205 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/terraform"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// varProgramTimeout bounds how long a program given with -var-program may
+// run before we give up on it, so that a hung inventory system cannot hang
+// Terraform indefinitely.
+const varProgramTimeout = 1 * time.Minute
+
+// varProgramVariable describes one declared root module input variable in
+// the JSON document sent to a -var-program on its stdin.
+type varProgramVariable struct {
+	Name      string          `json:"name"`
+	Type      json.RawMessage `json:"type"`
+	Required  bool            `json:"required"`
+	Sensitive bool            `json:"sensitive"`
+}
+
+// varProgramInput is the JSON document sent to a -var-program on its stdin.
+type varProgramInput struct {
+	Variables []varProgramVariable `json:"variables"`
+}
+
+// addVarsFromProgram runs the program given with -var-program, sending it
+// the root module's declared input variables as a JSON document on its
+// stdin, and adds the values from the JSON object the program prints on its
+// stdout to the given map.
+//
+// Values from the program are merged at the lowest precedence above
+// declared defaults, so callers must add them before values from any other
+// source.
+func (m *Meta) addVarsFromProgram(program string, to map[string]backend.UnparsedVariableValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// We need the root module's variable declarations to describe them to
+	// the program. If the configuration is invalid we don't report its
+	// diagnostics here, because the caller will load it again with full
+	// diagnostics soon anyway.
+	mod, modDiags := m.loadSingleModule(".")
+	if modDiags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot run variables program",
+			fmt.Sprintf("Cannot describe the declared input variables to %s because the configuration in the current directory is invalid.", program),
+		))
+		return diags
+	}
+
+	input := varProgramInput{
+		Variables: make([]varProgramVariable, 0, len(mod.Variables)),
+	}
+	names := make([]string, 0, len(mod.Variables))
+	for name := range mod.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vc := mod.Variables[name]
+		ty := vc.ConstraintType
+		if ty == cty.NilType {
+			ty = cty.DynamicPseudoType
+		}
+		tyJSON, err := ctyjson.MarshalType(ty)
+		if err != nil {
+			// Should never happen, since these types come from the
+			// configuration parser.
+			diags = diags.Append(fmt.Errorf("failed to serialize type of var.%s: %s", name, err))
+			return diags
+		}
+		input.Variables = append(input.Variables, varProgramVariable{
+			Name:      name,
+			Type:      json.RawMessage(tyJSON),
+			Required:  vc.Required(),
+			Sensitive: vc.Sensitive,
+		})
+	}
+	stdin, err := json.Marshal(&input)
+	if err != nil {
+		// Should never happen, since the input is a plain data structure.
+		diags = diags.Append(fmt.Errorf("failed to serialize variables program input: %s", err))
+		return diags
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), varProgramTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, program)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Variables program timed out",
+			fmt.Sprintf("The program %s did not produce variable values within %s.", program, varProgramTimeout),
+		))
+		return diags
+	}
+	if err != nil {
+		detail := fmt.Sprintf("The program %s failed: %s.", program, err)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			detail += "\n\nThe program produced the following error output:\n" + msg
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Variables program failed",
+			detail,
+		))
+		return diags
+	}
+
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(stdout.Bytes(), &values); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid variables program output",
+			fmt.Sprintf("The program %s must print a JSON object of variable values on its stdout: %s.", program, err),
+		))
+		return diags
+	}
+
+	valueNames := make([]string, 0, len(values))
+	for name := range values {
+		valueNames = append(valueNames, name)
+	}
+	sort.Strings(valueNames)
+	for _, name := range valueNames {
+		vc, declared := mod.Variables[name]
+		if !declared {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Value for undeclared variable",
+				fmt.Sprintf("The program %s returned a value for %q, but the root module does not declare a variable of that name, so the value was ignored.", program, name),
+			))
+			continue
+		}
+
+		ty, err := ctyjson.ImpliedType(values[name])
+		var val cty.Value
+		if err == nil {
+			val, err = ctyjson.Unmarshal(values[name], ty)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid variables program output",
+				fmt.Sprintf("The value for var.%s returned by %s is not valid JSON: %s.", name, program, err),
+			))
+			continue
+		}
+
+		wantTy := vc.ConstraintType
+		if wantTy == cty.NilType {
+			wantTy = cty.DynamicPseudoType
+		}
+		val, err = convert.Convert(val, wantTy)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid value from var-program",
+				fmt.Sprintf("The value for var.%s returned by var-program %s is not compatible with the variable's declared type: %s.", name, program, err),
+			))
+			continue
+		}
+
+		to[name] = unparsedVariableValueDecoded{value: val}
+	}
+
+	return diags
+}
+
+// unparsedVariableValueDecoded is a backend.UnparsedVariableValue
+// implementation for a value that was already decoded and converted to the
+// variable's declared type, as for values returned by a -var-program.
+type unparsedVariableValueDecoded struct {
+	value cty.Value
+}
+
+func (v unparsedVariableValueDecoded) ParseVariableValue(mode configs.VariableParsingMode) (*terraform.InputValue, tfdiags.Diagnostics) {
+	return &terraform.InputValue{
+		Value:      v.value,
+		SourceType: terraform.ValueFromCLIArg,
+	}, nil
+}
@@ -30,8 +30,19 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 	var diags tfdiags.Diagnostics
 	ret := map[string]backend.UnparsedVariableValue{}
 
-	// First we'll deal with environment variables, since they have the lowest
-	// precedence.
+	// A program given with -var-program has the lowest precedence of all,
+	// immediately above any defaults declared in the configuration, so we
+	// run it first and let every other source overwrite its values.
+	if m.varProgram != "" {
+		moreDiags := m.addVarsFromProgram(m.varProgram, ret)
+		diags = diags.Append(moreDiags)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	// Next we'll deal with environment variables, since they have the lowest
+	// precedence of the built-in sources.
 	{
 		env := os.Environ()
 		for _, raw := range env {
@@ -70,6 +81,13 @@ func (m *Meta) collectVariableValues() (map[string]backend.UnparsedVariableValue
 		moreDiags := m.addVarsFromFile(defaultVarsFilenameJSON, terraform.ValueFromAutoFile, ret)
 		diags = diags.Append(moreDiags)
 	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		defaultVarsFilenameYAML := DefaultVarsFilename + ext
+		if _, err := os.Stat(defaultVarsFilenameYAML); err == nil {
+			moreDiags := m.addVarsFromFile(defaultVarsFilenameYAML, terraform.ValueFromAutoFile, ret)
+			diags = diags.Append(moreDiags)
+		}
+	}
 	if infos, err := ioutil.ReadDir("."); err == nil {
 		// "infos" is already sorted by name, so we just need to filter it here.
 		for _, info := range infos {
@@ -152,6 +170,18 @@ func (m *Meta) addVarsFromFile(filename string, sourceType terraform.ValueSource
 	// Record the file source code for snippets in diagnostic messages.
 	loader.Parser().ForceFileSource(filename, src)
 
+	if strings.HasSuffix(filename, ".yaml") || strings.HasSuffix(filename, ".yml") {
+		vals, rngs, hclDiags := configs.ParseYAMLValues(src, filename)
+		diags = diags.Append(hclDiags)
+		for name, val := range vals {
+			to[name] = unparsedVariableValueExpression{
+				expr:       hcl.StaticExpr(val, rngs[name]),
+				sourceType: sourceType,
+			}
+		}
+		return diags
+	}
+
 	var f *hcl.File
 	if strings.HasSuffix(filename, ".json") {
 		var hclDiags hcl.Diagnostics
@@ -0,0 +1,351 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/earlyconfig"
+	"github.com/hashicorp/terraform/internal/getmodules"
+	"github.com/hashicorp/terraform/internal/registry"
+	"github.com/hashicorp/terraform/internal/registry/regsrc"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/mitchellh/cli"
+)
+
+// ModulesMirrorCommand is a Command implementation that implements the
+// "terraform modules mirror" command, which populates a directory with
+// local copies of the registry modules needed by the current configuration
+// so that the mirror can be used to work offline, or similar.
+type ModulesMirrorCommand struct {
+	Meta
+}
+
+func (c *ModulesMirrorCommand) Synopsis() string {
+	return "Save local copies of all required registry modules"
+}
+
+func (c *ModulesMirrorCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("modules mirror")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No output directory specified",
+			"The modules mirror command requires an output directory as a command-line argument.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	outputDir := args[0]
+
+	ctx, cancel := c.InterruptibleContext()
+	defer cancel()
+
+	m := &modulesMirrorer{
+		ui:        c.Ui,
+		reg:       c.registryClient(),
+		fetcher:   getmodules.NewPackageFetcher(),
+		outputDir: outputDir,
+		visited:   make(map[string]bool),
+		mirrored:  make(map[string]bool),
+	}
+
+	// We walk the configuration tree ourselves rather than using the module
+	// installer because we want to visit only the module call graph, placing
+	// each registry package in the mirror layout rather than in an
+	// installation directory, and we must also follow calls found inside
+	// the packages we just mirrored.
+	moreDiags := m.mirrorModuleCalls(ctx, ".")
+	diags = diags.Append(moreDiags)
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+// modulesMirrorer holds the state for one "terraform modules mirror" run.
+type modulesMirrorer struct {
+	ui        cli.Ui
+	reg       *registry.Client
+	fetcher   *getmodules.PackageFetcher
+	outputDir string
+
+	// visited tracks the module directories we've already scanned for
+	// module calls, so that diamond-shaped call graphs don't cause
+	// redundant work or infinite recursion.
+	visited map[string]bool
+
+	// mirrored tracks the registry package versions already present in the
+	// output directory, keyed by "<package>:<version>".
+	mirrored map[string]bool
+}
+
+// mirrorModuleCalls scans the module in the given directory for module
+// calls, mirrors any registry sources it finds, and recurses into both
+// local and freshly-mirrored modules to discover nested calls.
+func (m *modulesMirrorer) mirrorModuleCalls(ctx context.Context, dir string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	absDir, err := filepath.Abs(dir)
+	if err == nil {
+		if m.visited[absDir] {
+			return diags
+		}
+		m.visited[absDir] = true
+	}
+
+	mod, modDiags := earlyconfig.LoadModule(dir)
+	if mod == nil {
+		diags = diags.Append(modDiags)
+		return diags
+	}
+	diags = diags.Append(modDiags)
+
+	for _, call := range mod.ModuleCalls {
+		sourceAddr, err := addrs.ParseModuleSource(call.Source)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module source address",
+				fmt.Sprintf("Module %q (%s:%d) has invalid source address %q: %s.", call.Name, call.Pos.Filename, call.Pos.Line, call.Source, err),
+			))
+			continue
+		}
+
+		switch sourceAddr := sourceAddr.(type) {
+		case addrs.ModuleSourceLocal:
+			// Local modules are already part of the configuration on disk
+			// and so don't need mirroring, but they might call registry
+			// modules of their own.
+			moreDiags := m.mirrorModuleCalls(ctx, filepath.Join(dir, filepath.FromSlash(string(sourceAddr))))
+			diags = diags.Append(moreDiags)
+		case addrs.ModuleSourceRegistry:
+			modDir, moreDiags := m.mirrorRegistryModule(ctx, call, sourceAddr)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				continue
+			}
+			// The mirrored module may itself call further registry modules.
+			moreDiags = m.mirrorModuleCalls(ctx, modDir)
+			diags = diags.Append(moreDiags)
+		default:
+			// Remote (go-getter-style) sources such as Git repositories
+			// aren't available via a registry and so can't be mirrored.
+			m.ui.Output(fmt.Sprintf("- Skipping module %q with source %q because only registry modules can be mirrored", call.Name, call.Source))
+		}
+	}
+
+	return diags
+}
+
+// mirrorRegistryModule downloads the newest version of the given registry
+// module matching the call's version constraint into the mirror directory,
+// unless that version is already present, and returns the directory of the
+// module to scan for nested calls.
+func (m *modulesMirrorer) mirrorRegistryModule(ctx context.Context, call *tfconfig.ModuleCall, addr addrs.ModuleSourceRegistry) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	packageAddr := addr.PackageAddr
+	hostname := packageAddr.Host
+
+	var constraints version.Constraints
+	if call.Version != "" {
+		var err error
+		constraints, err = version.NewConstraint(call.Version)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid module version constraint",
+				fmt.Sprintf("Module %q (%s:%d) has invalid version constraint %q: %s.", call.Name, call.Pos.Filename, call.Pos.Line, call.Version, err),
+			))
+			return "", diags
+		}
+	}
+
+	m.ui.Output(fmt.Sprintf("- Mirroring %s...", packageAddr.ForDisplay()))
+
+	// Our registry client is still using the legacy model of addresses, so
+	// we'll shim it here for now.
+	regsrcAddr := regsrc.ModuleFromRegistryPackageAddr(packageAddr)
+
+	resp, err := m.reg.ModuleVersions(ctx, regsrcAddr)
+	if err != nil {
+		if registry.IsModuleNotFound(err) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Module not found",
+				fmt.Sprintf("Module %q (from %s:%d) cannot be found in the module registry at %s.", call.Name, call.Pos.Filename, call.Pos.Line, hostname),
+			))
+		} else {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error accessing remote module registry",
+				fmt.Sprintf("Failed to retrieve available versions for module %q (%s:%d) from %s: %s.", call.Name, call.Pos.Filename, call.Pos.Line, hostname, err),
+			))
+		}
+		return "", diags
+	}
+	if len(resp.Modules) < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid response from remote module registry",
+			fmt.Sprintf("The registry at %s returned an invalid response when Terraform requested available versions for module %q (%s:%d).", hostname, call.Name, call.Pos.Filename, call.Pos.Line),
+		))
+		return "", diags
+	}
+
+	var latestMatch *version.Version
+	var latestVersion *version.Version
+	for _, mv := range resp.Modules[0].Versions {
+		v, err := version.NewVersion(mv.Version)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Invalid response from remote module registry",
+				fmt.Sprintf("The registry at %s returned an invalid version string %q for module %q (%s:%d), which Terraform ignored.", hostname, mv.Version, call.Name, call.Pos.Filename, call.Pos.Line),
+			))
+			continue
+		}
+		// If we've found a pre-release version then we'll ignore it unless
+		// it was exactly requested.
+		if v.Prerelease() != "" && constraints.String() != v.String() {
+			continue
+		}
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+		}
+		if constraints.Check(v) {
+			if latestMatch == nil || v.GreaterThan(latestMatch) {
+				latestMatch = v
+			}
+		}
+	}
+
+	if latestVersion == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module has no versions",
+			fmt.Sprintf("Module %q (%s:%d) has no versions available on %s.", call.Name, call.Pos.Filename, call.Pos.Line, hostname),
+		))
+		return "", diags
+	}
+	if latestMatch == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unresolvable module version constraint",
+			fmt.Sprintf("There is no available version of module %q (%s:%d) which matches the given version constraint. The newest available version is %s.", call.Name, call.Pos.Filename, call.Pos.Line, latestVersion),
+		))
+		return "", diags
+	}
+
+	targetDir := filepath.Join(
+		m.outputDir,
+		packageAddr.Host.String(),
+		packageAddr.Namespace,
+		packageAddr.Name,
+		packageAddr.TargetSystem,
+		latestMatch.String(),
+	)
+	modDir := filepath.Join(targetDir, filepath.FromSlash(addr.Subdir))
+
+	mirrorKey := packageAddr.String() + ":" + latestMatch.String()
+	if m.mirrored[mirrorKey] {
+		return modDir, diags
+	}
+
+	if call.Version != "" {
+		m.ui.Output(fmt.Sprintf("  - Selected v%s to meet constraints %s", latestMatch.String(), call.Version))
+	} else {
+		m.ui.Output(fmt.Sprintf("  - Selected v%s with no constraints", latestMatch.String()))
+	}
+
+	realAddrRaw, err := m.reg.ModuleLocation(ctx, regsrcAddr, latestMatch.String())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Error accessing remote module registry",
+			fmt.Sprintf("Failed to retrieve a download URL for %s %s from %s: %s.", addr, latestMatch, hostname, err),
+		))
+		return "", diags
+	}
+	realAddr, err := addrs.ParseModuleSource(realAddrRaw)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid package location from module registry",
+			fmt.Sprintf("Module registry %s returned invalid source location %q for %s %s: %s.", hostname, realAddrRaw, addr, latestMatch, err),
+		))
+		return "", diags
+	}
+	remoteAddr, ok := realAddr.(addrs.ModuleSourceRemote)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid package location from module registry",
+			fmt.Sprintf("Module registry %s returned invalid source location %q for %s %s: must be a direct remote package address.", hostname, realAddrRaw, addr, latestMatch),
+		))
+		return "", diags
+	}
+
+	m.ui.Output("  - Downloading package...")
+	// We always mirror the entire package, so we don't pass a subdirectory
+	// hint here even when the call address includes one.
+	err = m.fetcher.FetchPackage(ctx, targetDir, remoteAddr.PackageAddr.String(), "")
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to download module",
+			fmt.Sprintf("Could not download module %q (%s:%d) source code from %q: %s.", call.Name, call.Pos.Filename, call.Pos.Line, remoteAddr, err),
+		))
+		return "", diags
+	}
+
+	m.mirrored[mirrorKey] = true
+	return modDir, diags
+}
+
+func (c *ModulesMirrorCommand) Help() string {
+	return `
+Usage: terraform [global options] modules mirror <target-dir>
+
+  Populates a local directory with copies of the registry modules needed
+  for the current configuration, so that the directory can be used as a
+  local filesystem mirror to install those modules without access to
+  their origin registries in future.
+
+  The command resolves each registry module call in the configuration
+  (including calls nested inside downloaded modules) against its origin
+  registry, selects the newest version matching the call's version
+  constraint, and extracts the module package into the directory
+  structure:
+
+    HOSTNAME/NAMESPACE/NAME/TARGET_SYSTEM/VERSION/
+
+  Module calls using Git, HTTP, or local path sources are skipped with a
+  note, because only registry modules can be mirrored.
+
+  To make Terraform install registry modules from the mirror, add a
+  module_installation block to the CLI configuration:
+
+    module_installation {
+      filesystem_mirror {
+        path = "/path/to/target-dir"
+      }
+    }
+`
+}
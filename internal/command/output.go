@@ -119,6 +119,12 @@ Options:
                    converted to a string, will print the raw
                    string directly, rather than a human-oriented
                    representation of the value.
+
+  -format=name     Select the output format: "json", "yaml",
+                   "raw", or "shell". The "shell" format prints
+                   NAME=value lines with shell quoting, suitable
+                   for passing to a shell's "eval". Mutually-
+                   exclusive with -json and -raw.
 `
 	return strings.TrimSpace(helpText)
 }
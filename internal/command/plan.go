@@ -2,11 +2,15 @@ package command
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/command/addrfilter"
 	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/views"
+	"github.com/hashicorp/terraform/internal/command/workdir"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
@@ -60,8 +64,11 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.refreshParallelism = args.Operation.RefreshParallelism
+	c.Meta.noSchemaCache = args.Operation.NoSchemaCache
 
 	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
+	diags = diags.Append(c.moduleDeprecationWarnings())
 
 	// Prepare the backend with the backend-specific arguments
 	be, beDiags := c.PrepareBackend(args.State)
@@ -72,7 +79,7 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	}
 
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(be, view, args.Operation, args.OutPath)
+	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, args.Operation, args.OutPath)
 	diags = diags.Append(opDiags)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
@@ -86,6 +93,16 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Resolve any -filter expression against the current state to extend
+	// the target set before the operation begins.
+	if args.Filter != nil {
+		diags = diags.Append(c.applyPlanFilter(be, opReq, args.Filter))
+		if diags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
 	// diagnostics.
@@ -100,9 +117,55 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// If the user asked for the saved plan to be signed then we'll do that
+	// now, while the plan file is still exactly as we wrote it.
+	if op.Result == backend.OperationSuccess && args.SignKey != "" {
+		moreDiags := signPlanFile(args.OutPath, args.SignKey)
+		if moreDiags.HasErrors() {
+			diags = diags.Append(moreDiags)
+			view.Diagnostics(diags)
+			return 1
+		}
+	}
+
+	// Record a condensed summary of this operation in the working
+	// directory's local history, for later review with "terraform history".
+	{
+		var extras []string
+		if args.OutPath != "" {
+			extras = append(extras, "-out")
+		}
+		if args.Filter != nil {
+			extras = append(extras, "-filter")
+		}
+		if args.DriftOnly {
+			extras = append(extras, "-drift-only")
+		}
+		if args.Vars.VarProgram != "" {
+			// Record only the program's base name, since the full path might
+			// include details that shouldn't end up in the history.
+			extras = append(extras, "-var-program="+filepath.Base(args.Vars.VarProgram))
+		}
+		entry := &workdir.OperationHistoryEntry{
+			Command: "plan",
+			Options: operationOptionsSummary(args.Operation, extras...),
+			Result:  operationHistoryResult(op.Result == backend.OperationSuccess),
+		}
+		if op.Result == backend.OperationSuccess && op.PlanEmpty {
+			entry.Result = "success (no changes)"
+		}
+		c.recordOperationHistory(entry, diags)
+	}
+
 	if op.Result != backend.OperationSuccess {
 		return op.Result.ExitStatus()
 	}
+	if args.DriftOnly && op.DriftDetected {
+		// Exit status 3 distinguishes "drift was detected" from both
+		// errors (1) and planned configuration changes (2), so automation
+		// can react to drift specifically.
+		return 3
+	}
 	if args.DetailedExitCode && !op.PlanEmpty {
 		return 2
 	}
@@ -117,6 +180,12 @@ func (c *PlanCommand) PrepareBackend(args *arguments.State) (backend.Enhanced, t
 	// difficult but would make their use easier to understand.
 	c.Meta.applyStateArguments(args)
 
+	// Planning is allowed in a read-only workspace, but we skip acquiring
+	// the state lock because we're guaranteed not to write anything.
+	if c.workspaceIsReadOnly() {
+		c.Meta.stateLock = false
+	}
+
 	backendConfig, diags := c.loadBackendConfig(".")
 	if diags.HasErrors() {
 		return nil, diags
@@ -134,16 +203,58 @@ func (c *PlanCommand) PrepareBackend(args *arguments.State) (backend.Enhanced, t
 	return be, diags
 }
 
+// applyPlanFilter resolves the -filter expression against the resource
+// instances tracked in the current workspace's state and appends the
+// matching addresses to the operation's targets.
+func (c *PlanCommand) applyPlanFilter(be backend.Enhanced, opReq *backend.Operation, filter *addrfilter.Filter) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("error selecting workspace: %s", err))
+		return diags
+	}
+	stateMgr, err := be.StateMgr(workspace)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("failed to load state: %s", err))
+		return diags
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		diags = diags.Append(fmt.Errorf("failed to load state: %s", err))
+		return diags
+	}
+
+	state := stateMgr.State()
+	var matched []addrs.AbsResourceInstance
+	if state != nil {
+		matched = filterResourceInstanceAddrs(state, filter)
+	}
+	if len(matched) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid filter expression",
+			"The filter expression does not match any resource instances tracked in the state, so there is nothing to target. The -filter option can select only resource instances that are already in the state; use -target to name objects not yet created.",
+		))
+		return diags
+	}
+
+	for _, addr := range matched {
+		opReq.Targets = append(opReq.Targets, addr)
+	}
+	return diags
+}
+
 func (c *PlanCommand) OperationRequest(
 	be backend.Enhanced,
 	view views.Plan,
+	viewType arguments.ViewType,
 	args *arguments.Operation,
 	planOutPath string,
 ) (*backend.Operation, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	// Build the operation
-	opReq := c.Operation(be)
+	opReq := c.Operation(be, viewType)
 	opReq.ConfigDir = "."
 	opReq.PlanMode = args.PlanMode
 	opReq.Hooks = view.Hooks()
@@ -151,6 +262,7 @@ func (c *PlanCommand) OperationRequest(
 	opReq.PlanOutPath = planOutPath
 	opReq.Targets = args.Targets
 	opReq.ForceReplace = args.ForceReplace
+	opReq.ForceLocalExecution = args.ForceLocalExecution
 	opReq.Type = backend.OperationTypePlan
 	opReq.View = view.Operation()
 
@@ -181,6 +293,7 @@ func (c *PlanCommand) GatherVariables(opReq *backend.Operation, args *arguments.
 		items[i].Value = varArgs[i].Value
 	}
 	c.Meta.variableArgs = rawFlags{items: &items}
+	c.Meta.varProgram = args.VarProgram
 	opReq.Variables, diags = c.collectVariableValues()
 
 	return diags
@@ -207,6 +320,11 @@ Plan Customization Options:
                       to destroy all objects currently managed by this
                       Terraform configuration instead of the usual behavior.
 
+  -drift-only         Like -refresh-only, but additionally exit with status
+                      code 3 when changes made outside of Terraform were
+                      detected, so automation can check for drift without
+                      parsing the plan output.
+
   -refresh-only       Select the "refresh only" planning mode, which checks
                       whether remote objects still match the outcome of the
                       most recent Terraform apply but does not propose any
@@ -229,6 +347,22 @@ Plan Customization Options:
                       include more than one object. This is for exceptional
                       use only.
 
+  -target-file=path   Like -target, but reads the resource addresses from
+                      the given file, one per line. Blank lines and lines
+                      beginning with "#" are ignored. You can use this
+                      option multiple times, and combine it with -target.
+
+  -filter=EXPR        Like -target, but selects the resource instances
+                      tracked in the state that match a filter expression,
+                      such as:
+                          -filter='type == "aws_iam_role" && module =~ "^module\.network"'
+                      The expression compares the address components addr,
+                      module, mode, type, name, key, and provider against
+                      quoted strings with == and != for exact comparison and
+                      =~ and !~ for regular expression matching, combined
+                      with &&, ||, ! and parentheses. This is for exceptional
+                      use only.
+
   -var 'foo=bar'      Set a value for one of the input variables in the root
                       module of the configuration. Use this option more than
                       once to set more than one variable.
@@ -238,6 +372,12 @@ Plan Customization Options:
                       Use this option more than once to include more than one
                       variables file.
 
+  -var-program=path   Run the given program to obtain values for input
+                      variables. The program receives the declared variables
+                      as JSON on its stdin and must print a JSON object of
+                      values on its stdout. Values from every other source
+                      override values from the program.
+
 Other Options:
 
   -compact-warnings   If Terraform produces any warnings that are not
@@ -250,6 +390,12 @@ Other Options:
                       1 - Errored
                       2 - Succeeded, there is a diff
 
+  -execution=local    For the cloud backend only, run this operation locally
+                      instead of in a remote run, while still using the remote
+                      system for state storage and locking. This is a fallback
+                      for when remote runs are unavailable, and must be
+                      permitted with allow_local_execution in the cloud block.
+
   -input=true         Ask for input for variables if not directly set.
 
   -lock=false         Don't hold a state lock during the operation. This is
@@ -258,12 +404,34 @@ Other Options:
 
   -lock-timeout=0s    Duration to retry a state lock.
 
+  -lock-wait          Keep waiting for the state lock until it is released,
+                      instead of failing on the first attempt. A -lock-timeout
+                      places an overall deadline on the wait.
+
   -no-color           If specified, output won't contain any color.
 
+  -no-schema-cache    Fetch fresh schemas from the providers instead of
+                      reusing schemas cached on disk from an earlier run.
+
   -out=path           Write a plan file to the given path. This can be used as
                       input to the "apply" command.
 
-  -parallelism=n      Limit the number of concurrent operations. Defaults to 10.
+  -parallelism=n      Limit the number of concurrent operations. Defaults
+                      to 10.
+
+  -raw-string-diffs   Show changes to string attribute values that parse as
+                      JSON or YAML documents as plain string diffs, instead
+                      of as structural diffs of the decoded values.
+
+  -refresh-parallelism=n  Limit the number of concurrent operations while
+                      refreshing existing objects, separately from
+                      -parallelism. Defaults to the -parallelism value.
+
+  -sign-key=source    Write a detached Ed25519 signature for the saved plan
+                      file alongside it, for later verification with
+                      "terraform apply -verify-key". The key material is read
+                      from the given file, or from the stdout of a program
+                      named with a "cmd:" prefix. Requires -out.
 
   -state=statefile    A legacy option used for the local backend only. See the
                       local backend's documentation for more information.
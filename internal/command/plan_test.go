@@ -3,11 +3,14 @@ package command
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -20,8 +23,10 @@ import (
 	backendinit "github.com/hashicorp/terraform/internal/backend/init"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/plans/planfile"
 	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/terminal"
 	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
@@ -221,6 +226,74 @@ func TestPlan_outPath(t *testing.T) {
 	testReadPlan(t, outPath) // will call t.Fatal itself if the file cannot be read
 }
 
+func TestPlan_signKey(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	outPath := filepath.Join(td, "test.plan")
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(td, "plan.key")
+	if err := ioutil.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(priv.Seed())), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-out", outPath,
+		"-sign-key", keyPath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	testReadPlan(t, outPath) // will call t.Fatal itself if the file cannot be read
+	if err := planfile.Verify(outPath, pub); err != nil {
+		t.Fatalf("signature doesn't verify: %s", err)
+	}
+}
+
+func TestPlan_signKeyWithoutOut(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-sign-key", "nonexist.key",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code == 0 {
+		t.Fatal("succeeded; want error")
+	}
+	if got, want := output.Stderr(), "only in conjunction with -out"; !strings.Contains(got, want) {
+		t.Fatalf("missing error\ngot: %s\nwant substring: %s", got, want)
+	}
+}
+
 func TestPlan_outPathNoChange(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("plan"), td)
@@ -405,6 +478,72 @@ func TestPlan_refreshFalse(t *testing.T) {
 	}
 }
 
+func TestPlan_driftOnly(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	originalState := testState()
+	statePath := testStateFile(t, originalState)
+
+	p := planFixtureProvider()
+	newCommand := func() (*PlanCommand, func(*testing.T) *terminal.TestOutput) {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+		return c, done
+	}
+
+	// With the remote object matching the state, there is no drift and the
+	// command should succeed with an ordinary zero exit status.
+	c, done := newCommand()
+	code := c.Run([]string{"-drift-only", "-state", statePath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad exit status %d without drift\n\n%s", code, output.Stderr())
+	}
+
+	// Now the provider reports that the object changed outside of Terraform,
+	// which should produce the distinct exit status 3.
+	p.ReadResourceFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+		newState := cty.ObjectVal(map[string]cty.Value{
+			"id":  cty.StringVal("bar"),
+			"ami": cty.StringVal("drifted"),
+			"network_interface": cty.NullVal(cty.List(cty.Object(map[string]cty.Type{
+				"device_index": cty.String,
+				"description":  cty.String,
+			}))),
+		})
+		return providers.ReadResourceResponse{NewState: newState}
+	}
+	c, done = newCommand()
+	code = c.Run([]string{"-drift-only", "-state", statePath})
+	output = done(t)
+	if code != 3 {
+		t.Fatalf("bad exit status %d with drift; want 3\n\n%s", code, output.Stderr())
+	}
+	if got, want := output.Stdout(), "Objects have changed outside of Terraform"; !strings.Contains(got, want) {
+		t.Fatalf("missing drift note in output\ngot:\n%s", got)
+	}
+
+	// The option chooses a planning mode itself, so it conflicts with the
+	// explicit mode options.
+	c, done = newCommand()
+	code = c.Run([]string{"-drift-only", "-destroy", "-state", statePath})
+	output = done(t)
+	if code != 1 {
+		t.Fatalf("bad exit status %d for -drift-only -destroy; want 1", code)
+	}
+	if got, want := output.Stderr(), "mutually-exclusive"; !strings.Contains(got, want) {
+		t.Fatalf("missing expected error\ngot:\n%s", got)
+	}
+}
+
 func TestPlan_state(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -575,6 +714,111 @@ func TestPlan_vars(t *testing.T) {
 	}
 }
 
+func TestPlan_varProgram(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test program is a shell script")
+	}
+
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-vars"), td)
+	defer testChdir(t, td)()
+
+	// The program echoes the input it received into a file so that we can
+	// verify the declared variables were described to it, and then returns
+	// a value for var.foo.
+	program := filepath.Join(td, "fetch-vars.sh")
+	script := "#!/bin/sh\ncat >input.json\necho '{\"foo\":\"from-program\"}'\n"
+	if err := os.WriteFile(program, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	p := planVarsFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	actual := ""
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		actual = req.ProposedNewState.GetAttr("value").AsString()
+		resp.PlannedState = req.ProposedNewState
+		return
+	}
+
+	args := []string{
+		"-var-program", program,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if actual != "from-program" {
+		t.Fatalf("wrong value for var.foo %q; want %q", actual, "from-program")
+	}
+
+	input, err := os.ReadFile("input.json")
+	if err != nil {
+		t.Fatalf("program did not receive input: %s", err)
+	}
+	if want := `"name":"foo"`; !strings.Contains(string(input), want) {
+		t.Errorf("program input %s does not describe var.foo", input)
+	}
+}
+
+func TestPlan_varProgramPrecedence(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test program is a shell script")
+	}
+
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-vars"), td)
+	defer testChdir(t, td)()
+
+	program := filepath.Join(td, "fetch-vars.sh")
+	script := "#!/bin/sh\ncat >/dev/null\necho '{\"foo\":\"from-program\"}'\n"
+	if err := os.WriteFile(program, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	p := planVarsFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	actual := ""
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+		actual = req.ProposedNewState.GetAttr("value").AsString()
+		resp.PlannedState = req.ProposedNewState
+		return
+	}
+
+	// An explicit -var must override the value from the program.
+	args := []string{
+		"-var-program", program,
+		"-var", "foo=from-cli",
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if actual != "from-cli" {
+		t.Fatalf("wrong value for var.foo %q; want %q", actual, "from-cli")
+	}
+}
+
 func TestPlan_varsUnset(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -1080,6 +1324,104 @@ func TestPlan_targeted(t *testing.T) {
 	}
 }
 
+func TestPlan_filter(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	originalState := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"bar","ami":"bar","network_interface":[{"description":"Main network interface","device_index":"0"}]}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+	})
+	statePath := testStateFile(t, originalState)
+
+	p := planFixtureProvider()
+
+	t.Run("matching filter", func(t *testing.T) {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-state", statePath,
+			`-filter=type == "test_instance" && name == "foo"`,
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+
+		// The filter resolves to targets, so the plan must carry the
+		// resource targeting warning.
+		if got, want := output.Stdout(), "Resource targeting is in effect"; !strings.Contains(got, want) {
+			t.Fatalf("missing targeting warning, want %q, got:\n%s", want, got)
+		}
+	})
+
+	t.Run("filter matching nothing", func(t *testing.T) {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-state", statePath,
+			`-filter=type == "nope"`,
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stdout())
+		}
+		if got, want := output.Stderr(), "does not match any resource instances"; !strings.Contains(got, want) {
+			t.Fatalf("missing error, want %q, got:\n%s", want, got)
+		}
+	})
+
+	t.Run("invalid filter expression", func(t *testing.T) {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		args := []string{
+			"-filter=boop",
+		}
+		code := c.Run(args)
+		output := done(t)
+		if code != 1 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stdout())
+		}
+		if got, want := output.Stderr(), "Invalid filter expression"; !strings.Contains(got, want) {
+			t.Fatalf("missing error, want %q, got:\n%s", want, got)
+		}
+	})
+}
+
 // Diagnostics for invalid -target flags
 func TestPlan_targetFlagsDiags(t *testing.T) {
 	testCases := map[string]string{
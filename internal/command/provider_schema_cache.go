@@ -0,0 +1,154 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/plugin6/convert"
+	"github.com/hashicorp/terraform/internal/providers"
+	proto6 "github.com/hashicorp/terraform/internal/tfplugin6"
+)
+
+// providerSchemaCachePath returns the file where the schemas of the given
+// provider release should be cached between runs, under the working
+// directory's data directory.
+//
+// The package hash is part of the filename so that a cache entry is
+// implicitly invalidated whenever the installed package changes.
+func providerSchemaCachePath(dataDir string, provider addrs.Provider, version getproviders.Version, hash getproviders.Hash) string {
+	// Hash strings use base64 and so can contain characters that are not
+	// safe for filenames on all platforms.
+	r := strings.NewReplacer("/", "_", ":", "-", "+", ".", "=", "")
+	name := fmt.Sprintf("%s_%s_%s.tfschema", r.Replace(provider.String()), version.String(), r.Replace(hash.String()))
+	return filepath.Join(dataDir, "schema-cache", name)
+}
+
+// cachedSchemaProvider wraps the given provider so that its GetProviderSchema
+// result is cached in the given file and reused on later runs, if cachePath
+// is non-empty.
+func cachedSchemaProvider(p providers.Interface, cachePath string) providers.Interface {
+	if cachePath == "" {
+		return p
+	}
+	return &schemaCachingProvider{
+		Interface: p,
+		cachePath: cachePath,
+	}
+}
+
+// schemaCachingProvider is a providers.Interface decorator that answers
+// GetProviderSchema from a cache file on disk when possible, to avoid
+// re-fetching large schemas from the provider on every command.
+//
+// The schemas are stored as a serialized protocol buffers message using the
+// provider wire protocol's own schema types, so the encoding is exactly as
+// expressive as what a provider can send.
+type schemaCachingProvider struct {
+	providers.Interface
+	cachePath string
+}
+
+func (p *schemaCachingProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
+	if resp, ok := p.readSchemaCache(); ok {
+		log.Printf("[TRACE] schemaCachingProvider: using cached provider schemas from %s", p.cachePath)
+		return resp
+	}
+
+	resp := p.Interface.GetProviderSchema()
+	if !resp.Diagnostics.HasErrors() {
+		p.writeSchemaCache(resp)
+	}
+	return resp
+}
+
+func (p *schemaCachingProvider) readSchemaCache() (providers.GetProviderSchemaResponse, bool) {
+	var resp providers.GetProviderSchemaResponse
+
+	src, err := ioutil.ReadFile(p.cachePath)
+	if err != nil {
+		return resp, false
+	}
+
+	var protoResp proto6.GetProviderSchema_Response
+	if err := proto.Unmarshal(src, &protoResp); err != nil || protoResp.Provider == nil {
+		// An unreadable cache entry is silently ignored; we'll fetch the
+		// schemas from the provider and overwrite it below.
+		log.Printf("[WARN] schemaCachingProvider: ignoring invalid schema cache entry %s", p.cachePath)
+		return resp, false
+	}
+
+	resp.Provider = protoToProviderSchema(protoResp.Provider)
+	if protoResp.ProviderMeta != nil {
+		resp.ProviderMeta = protoToProviderSchema(protoResp.ProviderMeta)
+	}
+	resp.ResourceTypes = make(map[string]providers.Schema, len(protoResp.ResourceSchemas))
+	for name, schema := range protoResp.ResourceSchemas {
+		resp.ResourceTypes[name] = protoToProviderSchema(schema)
+	}
+	resp.DataSources = make(map[string]providers.Schema, len(protoResp.DataSourceSchemas))
+	for name, schema := range protoResp.DataSourceSchemas {
+		resp.DataSources[name] = protoToProviderSchema(schema)
+	}
+	return resp, true
+}
+
+func (p *schemaCachingProvider) writeSchemaCache(resp providers.GetProviderSchemaResponse) {
+	protoResp := &proto6.GetProviderSchema_Response{
+		Provider:          providerSchemaToProto(resp.Provider),
+		ResourceSchemas:   make(map[string]*proto6.Schema, len(resp.ResourceTypes)),
+		DataSourceSchemas: make(map[string]*proto6.Schema, len(resp.DataSources)),
+	}
+	if resp.ProviderMeta.Block != nil {
+		protoResp.ProviderMeta = providerSchemaToProto(resp.ProviderMeta)
+	}
+	for name, schema := range resp.ResourceTypes {
+		protoResp.ResourceSchemas[name] = providerSchemaToProto(schema)
+	}
+	for name, schema := range resp.DataSources {
+		protoResp.DataSourceSchemas[name] = providerSchemaToProto(schema)
+	}
+
+	src, err := proto.Marshal(protoResp)
+	if err != nil {
+		log.Printf("[WARN] schemaCachingProvider: failed to serialize provider schemas for caching: %s", err)
+		return
+	}
+
+	// A failure to write the cache only costs us a fresh fetch on the next
+	// run, so we just log it rather than returning an error.
+	if err := os.MkdirAll(filepath.Dir(p.cachePath), 0755); err != nil {
+		log.Printf("[WARN] schemaCachingProvider: failed to create schema cache directory: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(p.cachePath, src, 0644); err != nil {
+		log.Printf("[WARN] schemaCachingProvider: failed to write schema cache entry %s: %s", p.cachePath, err)
+	}
+}
+
+func providerSchemaToProto(s providers.Schema) *proto6.Schema {
+	ret := &proto6.Schema{
+		Version: s.Version,
+	}
+	if s.Block != nil {
+		ret.Block = convert.ConfigSchemaToProto(s.Block)
+	}
+	return ret
+}
+
+func protoToProviderSchema(s *proto6.Schema) providers.Schema {
+	ret := providers.Schema{
+		Version: s.Version,
+	}
+	if s.Block != nil {
+		ret.Block = convert.ProtoToConfigSchema(s.Block)
+	}
+	return ret
+}
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/terraform"
+)
+
+func TestSchemaCachingProvider(t *testing.T) {
+	td, err := ioutil.TempDir("", "terraform-schema-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(td)
+	cachePath := filepath.Join(td, "cache", "test.tfschema")
+
+	schemaResp := &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{
+				Attributes: map[string]*configschema.Attribute{
+					"region": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Version: 2,
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":  {Type: cty.String, Computed: true},
+						"ami": {Type: cty.String, Optional: true},
+					},
+					BlockTypes: map[string]*configschema.NestedBlock{
+						"network_interface": {
+							Nesting: configschema.NestingList,
+							Block: configschema.Block{
+								Attributes: map[string]*configschema.Attribute{
+									"device_index": {Type: cty.Number, Optional: true},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		DataSources: map[string]providers.Schema{
+			"test_data_source": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+
+	inner := &terraform.MockProvider{
+		GetProviderSchemaResponse: schemaResp,
+	}
+
+	// The first call should pass through to the wrapped provider and
+	// populate the cache file.
+	p := cachedSchemaProvider(inner, cachePath)
+	got := p.GetProviderSchema()
+	if got.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected errors: %s", got.Diagnostics.Err())
+	}
+	if !inner.GetProviderSchemaCalled {
+		t.Fatal("expected call to be passed through to the wrapped provider")
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("cache file was not written: %s", err)
+	}
+
+	// A second wrapper around a provider that would fail should answer
+	// entirely from the cache file.
+	inner2 := &terraform.MockProvider{}
+	p2 := cachedSchemaProvider(inner2, cachePath)
+	got2 := p2.GetProviderSchema()
+	if inner2.GetProviderSchemaCalled {
+		t.Fatal("expected schemas to be answered from the cache")
+	}
+	if !got2.Provider.Block.ImpliedType().Equals(schemaResp.Provider.Block.ImpliedType()) {
+		t.Errorf("wrong provider schema from cache\n%s", spew.Sdump(got2.Provider))
+	}
+	rSchema := got2.ResourceTypes["test_instance"]
+	if rSchema.Version != 2 {
+		t.Errorf("wrong resource schema version %d; want 2", rSchema.Version)
+	}
+	if !rSchema.Block.ImpliedType().Equals(schemaResp.ResourceTypes["test_instance"].Block.ImpliedType()) {
+		t.Errorf("wrong resource schema from cache\n%s", spew.Sdump(rSchema))
+	}
+	if _, ok := got2.DataSources["test_data_source"]; !ok {
+		t.Errorf("missing data source schema from cache")
+	}
+
+	// A corrupt cache entry should be silently ignored.
+	if err := ioutil.WriteFile(cachePath, []byte("not a schema"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	inner3 := &terraform.MockProvider{
+		GetProviderSchemaResponse: schemaResp,
+	}
+	p3 := cachedSchemaProvider(inner3, cachePath)
+	p3.GetProviderSchema()
+	if !inner3.GetProviderSchemaCalled {
+		t.Fatal("expected corrupt cache entry to fall back to the wrapped provider")
+	}
+}
+
+func TestProviderSchemaCachePath(t *testing.T) {
+	provider := addrs.NewDefaultProvider("aws")
+	version := getproviders.MustParseVersion("1.2.3")
+	hash := getproviders.Hash("h1:ABC/def+ghi=")
+
+	got := providerSchemaCachePath(".terraform", provider, version, hash)
+	want := filepath.Join(".terraform", "schema-cache", "registry.terraform.io_hashicorp_aws_1.2.3_h1-ABC_def.ghi.tfschema")
+	if got != want {
+		t.Errorf("wrong path %q; want %q", got, want)
+	}
+}
@@ -1,11 +1,19 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 	"github.com/xlab/treeprint"
 )
@@ -25,8 +33,10 @@ func (c *ProvidersCommand) Synopsis() string {
 }
 
 func (c *ProvidersCommand) Run(args []string) int {
+	var jsonOutput bool
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("providers")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -72,33 +82,22 @@ func (c *ProvidersCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Load the backend
-	b, backendDiags := c.Backend(&BackendOpts{
-		Config: config.Module.Backend,
-	})
-	diags = diags.Append(backendDiags)
-	if backendDiags.HasErrors() {
-		c.showDiagnostics(diags)
-		return 1
-	}
-
-	// This is a read-only command
-	c.ignoreRemoteVersionConflict(b)
-
-	// Get the state
-	env, err := c.Workspace()
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
-		return 1
-	}
-	s, err := b.StateMgr(env)
-	if err != nil {
-		c.Ui.Error(fmt.Sprintf("Failed to load state: %s", err))
-		return 1
-	}
-	if err := s.RefreshState(); err != nil {
-		c.Ui.Error(fmt.Sprintf("Failed to load state: %s", err))
-		return 1
+	// The JSON output is aimed at automated inventory tooling, which often
+	// runs without any credentials for the backend, so in that mode the
+	// state-derived part is best-effort and the configuration-derived part
+	// must work without any backend or network access.
+	var state *states.State
+	if jsonOutput {
+		if s, err := c.stateForProvidersCommand(config); err == nil {
+			state = s
+		}
+	} else {
+		s, err := c.stateForProvidersCommand(config)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		state = s
 	}
 
 	reqs, reqDiags := config.ProviderRequirementsByModule()
@@ -108,14 +107,38 @@ func (c *ProvidersCommand) Run(args []string) int {
 		return 1
 	}
 
-	state := s.State()
 	var stateReqs getproviders.Requirements
 	if state != nil {
 		stateReqs = state.ProviderRequirements()
 	}
 
+	if jsonOutput {
+		doc := providersCommandJSON{
+			FormatVersion:        providersCommandJSONFormatVersion,
+			ProviderRequirements: providersCommandModuleJSON(config, reqs),
+		}
+		for fqn := range stateReqs {
+			doc.StateRequirements = append(doc.StateRequirements, fqn.String())
+		}
+		sort.Strings(doc.StateRequirements)
+
+		j, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			// Should never happen, since we fully control the input here
+			c.Ui.Error(fmt.Sprintf("Failed to serialize provider requirements as JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(j))
+
+		c.showDiagnostics(diags)
+		if diags.HasErrors() {
+			return 1
+		}
+		return 0
+	}
+
 	printRoot := treeprint.New()
-	c.populateTreeNode(printRoot, reqs)
+	c.populateTreeNode(printRoot, reqs, config)
 
 	c.Ui.Output("\nProviders required by configuration:")
 	c.Ui.Output(printRoot.String())
@@ -134,7 +157,34 @@ func (c *ProvidersCommand) Run(args []string) int {
 	return 0
 }
 
-func (c *ProvidersCommand) populateTreeNode(tree treeprint.Tree, node *configs.ModuleRequirements) {
+// stateForProvidersCommand makes a best effort to read the state for the
+// current workspace, so that we can also report the providers recorded there.
+func (c *ProvidersCommand) stateForProvidersCommand(config *configs.Config) (*states.State, error) {
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: config.Module.Backend,
+	})
+	if backendDiags.HasErrors() {
+		return nil, backendDiags.Err()
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	env, err := c.Workspace()
+	if err != nil {
+		return nil, fmt.Errorf("Error selecting workspace: %s", err)
+	}
+	s, err := b.StateMgr(env)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load state: %s", err)
+	}
+	if err := s.RefreshState(); err != nil {
+		return nil, fmt.Errorf("Failed to load state: %s", err)
+	}
+	return s.State(), nil
+}
+
+func (c *ProvidersCommand) populateTreeNode(tree treeprint.Tree, node *configs.ModuleRequirements, cfg *configs.Config) {
 	for fqn, dep := range node.Requirements {
 		versionsStr := getproviders.VersionConstraintsString(dep)
 		if versionsStr != "" {
@@ -142,14 +192,139 @@ func (c *ProvidersCommand) populateTreeNode(tree treeprint.Tree, node *configs.M
 		}
 		tree.AddNode(fmt.Sprintf("provider[%s]%s", fqn.String(), versionsStr))
 	}
+	if cfg != nil {
+		// Provider configurations synthesized from provider_override blocks
+		// are additional instances of providers already counted in the
+		// requirements above, but it's helpful to show which extra instances
+		// will exist and the override values that produced them.
+		names := make([]string, 0, len(cfg.Module.ProviderConfigs))
+		for name := range cfg.Module.ProviderConfigs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pc := cfg.Module.ProviderConfigs[name]
+			baseAlias, values, ok := configs.DecodeProviderOverrideAlias(pc.Alias)
+			if !ok {
+				continue
+			}
+			fqn := cfg.Module.ProviderForLocalConfig(addrs.LocalProviderConfig{LocalName: pc.Name})
+			baseStr := ""
+			if baseAlias != "" {
+				baseStr = "." + baseAlias
+			}
+			tree.AddNode(fmt.Sprintf("provider[%s]%s with provider_override (%s)", fqn.String(), baseStr, providerOverrideValuesString(values)))
+		}
+	}
 	for name, childNode := range node.Children {
 		branch := tree.AddBranch(fmt.Sprintf("module.%s", name))
-		c.populateTreeNode(branch, childNode)
+		var childCfg *configs.Config
+		if cfg != nil {
+			childCfg = cfg.Children[name]
+		}
+		c.populateTreeNode(branch, childNode, childCfg)
+	}
+}
+
+// providerOverrideValuesString renders the values of a provider_override
+// block compactly for display in the providers tree.
+func providerOverrideValuesString(values map[string]cty.Value) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		src, err := ctyjson.Marshal(values[name], values[name].Type())
+		if err != nil {
+			// Should never happen, because override values are always
+			// known constants.
+			src = []byte("(invalid)")
+		}
+		parts = append(parts, fmt.Sprintf("%s = %s", name, src))
 	}
+	return strings.Join(parts, ", ")
+}
+
+// providersCommandJSONFormatVersion records the current version of the
+// "terraform providers -json" output format, so that consumers can detect
+// incompatible future changes. It's versioned independently of the other
+// machine-readable formats.
+const providersCommandJSONFormatVersion = "1.0"
+
+type providersCommandJSON struct {
+	FormatVersion        string                  `json:"format_version"`
+	ProviderRequirements *providersCommandModule `json:"provider_requirements"`
+	StateRequirements    []string                `json:"state_requirements,omitempty"`
+}
+
+type providersCommandModule struct {
+	Name      string                               `json:"name,omitempty"`
+	Source    string                               `json:"source,omitempty"`
+	Providers map[string]*providersCommandProvider `json:"providers,omitempty"`
+	Children  map[string]*providersCommandModule   `json:"child_modules,omitempty"`
+}
+
+type providersCommandProvider struct {
+	// VersionConstraints is the canonical string representation of the
+	// version constraints for this provider in this module, if any.
+	VersionConstraints string `json:"version_constraints,omitempty"`
+
+	// Declared is true if this module explicitly declares this provider in
+	// one of its required_providers blocks, and false if the requirement is
+	// only implied by resource usage or inherited provider configurations.
+	Declared bool `json:"declared"`
+}
+
+// providersCommandModuleJSON builds the JSON representation of a single
+// module's provider requirements, walking the configuration tree alongside
+// the requirements tree so that we can distinguish providers that are
+// explicitly declared in required_providers from those that are only implied.
+func providersCommandModuleJSON(cfg *configs.Config, node *configs.ModuleRequirements) *providersCommandModule {
+	ret := &providersCommandModule{
+		Name: node.Name,
+	}
+	if node.SourceAddr != nil {
+		ret.Source = node.SourceAddr.String()
+	}
+
+	if len(node.Requirements) > 0 {
+		ret.Providers = make(map[string]*providersCommandProvider, len(node.Requirements))
+		for fqn, constraints := range node.Requirements {
+			declared := false
+			if cfg != nil && cfg.Module.ProviderRequirements != nil {
+				for _, rp := range cfg.Module.ProviderRequirements.RequiredProviders {
+					if rp.Type == fqn {
+						declared = true
+						break
+					}
+				}
+			}
+			ret.Providers[fqn.String()] = &providersCommandProvider{
+				VersionConstraints: getproviders.VersionConstraintsString(constraints),
+				Declared:           declared,
+			}
+		}
+	}
+
+	if len(node.Children) > 0 {
+		ret.Children = make(map[string]*providersCommandModule, len(node.Children))
+		for name, childNode := range node.Children {
+			var childCfg *configs.Config
+			if cfg != nil {
+				childCfg = cfg.Children[name]
+			}
+			ret.Children[name] = providersCommandModuleJSON(childCfg, childNode)
+		}
+	}
+
+	return ret
 }
 
 const providersCommandHelp = `
-Usage: terraform [global options] providers [dir]
+Usage: terraform [global options] providers [options] [dir]
 
   Prints out a tree of modules in the referenced configuration annotated with
   their provider requirements.
@@ -157,4 +332,10 @@ Usage: terraform [global options] providers [dir]
   This provides an overview of all of the provider requirements across all
   referenced modules, as an aid to understanding why particular provider
   plugins are needed and why particular versions are selected.
+
+Options:
+
+  -json  Produce output in a machine-readable JSON format instead of the
+         human-oriented tree, including each module's provider requirements
+         and the providers recorded in the state when it is readable.
 `
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// ProvidersCachePruneCommand is a Command implementation that implements the
+// "terraform providers cache prune" command, which removes from the shared
+// global provider cache directory any versions of the current configuration's
+// providers other than the ones selected in the dependency lock file.
+type ProvidersCachePruneCommand struct {
+	Meta
+}
+
+func (c *ProvidersCachePruneCommand) Synopsis() string {
+	return "Remove unused provider versions from the global cache"
+}
+
+func (c *ProvidersCachePruneCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("providers cache prune")
+	var dryRun bool
+	cmdFlags.BoolVar(&dryRun, "dry-run", false, "report what would be removed without removing anything")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The providers cache prune command expects no arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	cacheDir := c.providerGlobalCacheDir()
+	if cacheDir == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No global provider cache is configured",
+			"The providers cache prune command requires a shared provider cache directory, configured either with the plugin_cache_dir setting in the CLI configuration or the TF_PLUGIN_CACHE_DIR environment variable.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	locks, moreDiags := c.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	allLocks := locks.AllProviders()
+	if len(allLocks) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No dependency lock file entries",
+			"The providers cache prune command decides which versions are still needed using the dependency lock file of the current working directory, but there is no lock file here or it records no providers. Run this command in an initialized working directory.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We retain only the locked version of each of this configuration's
+	// providers. Providers that this configuration doesn't use at all are
+	// left untouched, because the cache may be shared with other
+	// configurations that still need them.
+	retain := make(map[addrs.Provider][]getproviders.Version, len(allLocks))
+	for provider, lock := range allLocks {
+		retain[provider] = append(retain[provider], lock.Version())
+	}
+
+	if dryRun {
+		var doomed []string
+		for provider, entries := range cacheDir.AllAvailablePackages() {
+			retainVersions, relevant := retain[provider]
+			if !relevant {
+				continue
+			}
+			for _, entry := range entries {
+				if entry.Version != retainVersions[0] {
+					doomed = append(doomed, fmt.Sprintf("- %s v%s", entry.Provider.ForDisplay(), entry.Version))
+				}
+			}
+		}
+		if len(doomed) == 0 {
+			c.Ui.Output("No unused provider versions to remove.")
+			return 0
+		}
+		sort.Strings(doomed)
+		c.Ui.Output(fmt.Sprintf("Would remove the following packages from %s:\n%s", cacheDir.BasePath(), strings.Join(doomed, "\n")))
+		return 0
+	}
+
+	removed, err := cacheDir.Prune(retain)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to prune the provider cache",
+			fmt.Sprintf("Error while removing unused packages from %s: %s.", cacheDir.BasePath(), err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if len(removed) == 0 {
+		c.Ui.Output("No unused provider versions to remove.")
+		return 0
+	}
+
+	lines := make([]string, len(removed))
+	for i, entry := range removed {
+		lines[i] = fmt.Sprintf("- %s v%s", entry.Provider.ForDisplay(), entry.Version)
+	}
+	sort.Strings(lines)
+	c.Ui.Output(fmt.Sprintf("Removed the following packages from %s:\n%s", cacheDir.BasePath(), strings.Join(lines, "\n")))
+	return 0
+}
+
+func (c *ProvidersCachePruneCommand) Help() string {
+	return strings.TrimSpace(`
+Usage: terraform [global options] providers cache prune [options]
+
+  Removes from the shared global provider cache directory any versions of the
+  providers that the current configuration depends on other than the versions
+  selected in the dependency lock file.
+
+  Providers that the current configuration doesn't use at all are left
+  untouched, because the cache directory may be shared with other
+  configurations that still need them. To fully garbage-collect a shared
+  cache, run this command from each of the configurations that use it.
+
+Options:
+
+  -dry-run  Report which packages would be removed without removing anything.
+`)
+}
@@ -1,10 +1,15 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/url"
 	"os"
+	"sort"
+	"strings"
+
+	"github.com/apparentlymart/go-versions/versions"
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/depsfile"
@@ -55,10 +60,43 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 
 	providerStrs := cmdFlags.Args()
 
+	// -platform=all asks us to discover the full set of platforms each
+	// selected provider version publishes, instead of naming them one by
+	// one. We resolve the actual platform list later, once we know which
+	// versions we're working with.
+	allPlatforms := false
+	for _, platformStr := range optPlatforms {
+		if platformStr == "all" {
+			allPlatforms = true
+		}
+	}
+	if allPlatforms && len(optPlatforms) != 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid target platform",
+			"The -platform=all option cannot be combined with other -platform options.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	if allPlatforms && (fsMirrorDir != "" || netMirrorURL != "") {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid target platform",
+			"The -platform=all option works by asking each provider's origin registry which platforms it publishes, so it cannot be combined with -fs-mirror or -net-mirror.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	var platforms []getproviders.Platform
-	if len(optPlatforms) == 0 {
+	switch {
+	case allPlatforms:
+		// We'll resolve the platform list below, once we know the
+		// selected version of each provider.
+	case len(optPlatforms) == 0:
 		platforms = []getproviders.Platform{getproviders.CurrentPlatform}
-	} else {
+	default:
 		platforms = make([]getproviders.Platform, 0, len(optPlatforms))
 		for _, platformStr := range optPlatforms {
 			platform, err := getproviders.ParsePlatform(platformStr)
@@ -176,6 +214,23 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 	selectedVersions := map[addrs.Provider]getproviders.Version{}
 	ctx, cancel := c.InterruptibleContext()
 	defer cancel()
+
+	// For -platform=all we ask the registry which platforms each selected
+	// provider version publishes, and lock each provider only for its own
+	// published set, since different providers support different platforms.
+	// providersForPlatform is nil when explicit platforms were given, in
+	// which case every provider is locked for every platform.
+	var providersForPlatform map[getproviders.Platform]map[addrs.Provider]struct{}
+	if allPlatforms {
+		var moreDiags tfdiags.Diagnostics
+		platforms, providersForPlatform, moreDiags = c.resolveAllPlatforms(ctx, source, reqs, oldLocks)
+		diags = diags.Append(moreDiags)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
 	for _, platform := range platforms {
 		tempDir, err := ioutil.TempDir("", "terraform-providers-lock")
 		if err != nil {
@@ -191,7 +246,7 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 		evts := &providercache.InstallerEvents{
 			// Our output from this command is minimal just to show that
 			// we're making progress, rather than just silently hanging.
-			FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, loc getproviders.PackageLocation) {
+			FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, loc getproviders.PackageLocation, sourceKind getproviders.SourceKind) {
 				c.Ui.Output(fmt.Sprintf("- Fetching %s %s for %s...", provider.ForDisplay(), version, platform))
 				if prevVersion, exists := selectedVersions[provider]; exists && version != prevVersion {
 					// This indicates a weird situation where we ended up
@@ -217,7 +272,7 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 				}
 				selectedVersions[provider] = version
 			},
-			FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, auth *getproviders.PackageAuthenticationResult) {
+			FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, auth *getproviders.PackageAuthenticationResult, hashesVerified bool) {
 				var keyID string
 				if auth != nil && auth.ThirdPartySigned() {
 					keyID = auth.KeyID
@@ -230,10 +285,22 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 		}
 		ctx := evts.OnContext(ctx)
 
+		// When -platform=all chose the platforms, each provider is locked
+		// only for the platforms it actually publishes.
+		platformReqs := reqs
+		if providersForPlatform != nil {
+			platformReqs = make(getproviders.Requirements, len(reqs))
+			for provider, constraints := range reqs {
+				if _, ok := providersForPlatform[platform][provider]; ok {
+					platformReqs[provider] = constraints
+				}
+			}
+		}
+
 		dir := providercache.NewDirWithPlatform(tempDir, platform)
 		installer := providercache.NewInstaller(dir, source)
 
-		newLocks, err := installer.EnsureProviderVersions(ctx, oldLocks, reqs, providercache.InstallNewProvidersOnly)
+		newLocks, err := installer.EnsureProviderVersions(ctx, oldLocks, platformReqs, providercache.InstallNewProvidersOnly)
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -299,6 +366,89 @@ func (c *ProvidersLockCommand) Run(args []string) int {
 	return 0
 }
 
+// resolveAllPlatforms implements the -platform=all option by asking the
+// given source which platforms each required provider publishes packages
+// for, using the already-locked version of each provider where there is one
+// and the newest version meeting the configured constraints otherwise.
+//
+// The first result is the union of every provider's published platforms, and
+// the second records which providers publish each platform so that the
+// caller can avoid requesting packages that don't exist.
+func (c *ProvidersLockCommand) resolveAllPlatforms(ctx context.Context, source getproviders.Source, reqs getproviders.Requirements, oldLocks *depsfile.Locks) ([]getproviders.Platform, map[getproviders.Platform]map[addrs.Provider]struct{}, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	lister, ok := source.(getproviders.PlatformLister)
+	if !ok {
+		// Should never happen: Run only allows -platform=all with the
+		// registry source, which implements PlatformLister.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid target platform",
+			"The selected installation source cannot enumerate the platforms each provider publishes, so -platform=all is not available here.",
+		))
+		return nil, nil, diags
+	}
+
+	providersForPlatform := map[getproviders.Platform]map[addrs.Provider]struct{}{}
+	for provider, constraints := range reqs {
+		var version getproviders.Version
+		if oldLock := oldLocks.Provider(provider); oldLock != nil {
+			version = oldLock.Version()
+		} else {
+			avail, _, err := source.AvailableVersions(ctx, provider)
+			if err == nil {
+				candidates := avail.Filter(versions.MeetingConstraints(constraints))
+				if len(candidates) == 0 {
+					err = fmt.Errorf("no releases match the configured version constraints")
+				} else {
+					version = candidates.Newest()
+				}
+			}
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Cannot select provider version",
+					fmt.Sprintf("Failed to select a version of %s to enumerate platforms for: %s.", provider.String(), err),
+				))
+				continue
+			}
+		}
+		published, err := lister.AvailablePlatforms(ctx, provider, version)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot enumerate provider platforms",
+				fmt.Sprintf("Failed to find out which platforms %s v%s is available for: %s.", provider.String(), version.String(), err),
+			))
+			continue
+		}
+		if len(published) == 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot enumerate provider platforms",
+				fmt.Sprintf("The origin registry reports no available platforms for %s v%s.", provider.String(), version.String()),
+			))
+			continue
+		}
+		platformStrs := make([]string, len(published))
+		for i, platform := range published {
+			platformStrs[i] = platform.String()
+			if providersForPlatform[platform] == nil {
+				providersForPlatform[platform] = map[addrs.Provider]struct{}{}
+			}
+			providersForPlatform[platform][provider] = struct{}{}
+		}
+		sort.Strings(platformStrs)
+		c.Ui.Output(fmt.Sprintf("- %s v%s publishes packages for: %s", provider.ForDisplay(), version.String(), strings.Join(platformStrs, ", ")))
+	}
+
+	platforms := make([]getproviders.Platform, 0, len(providersForPlatform))
+	for platform := range providersForPlatform {
+		platforms = append(platforms, platform)
+	}
+	sort.Slice(platforms, func(i, j int) bool { return platforms[i].String() < platforms[j].String() })
+	return platforms, providersForPlatform, diags
+}
+
 func (c *ProvidersLockCommand) Help() string {
 	return `
 Usage: terraform [global options] providers lock [options] [providers...]
@@ -355,5 +505,11 @@ Options:
                      Linux operating system running on an AMD64 or x86_64
                      CPU. Each provider is available only for a limited
                      set of target platforms.
+
+                     The special value "all" instead asks each provider's
+                     origin registry which platforms the selected version
+                     publishes packages for, and records checksums for all
+                     of them. It cannot be combined with other -platform
+                     options or with -fs-mirror or -net-mirror.
 `
 }
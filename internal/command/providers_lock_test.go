@@ -130,6 +130,49 @@ func TestProvidersLock_args(t *testing.T) {
 		}
 	})
 
+	t.Run("platform all with explicit platform", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersLockCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+
+		// -platform=all must stand alone
+		args := []string{"-platform=all", "-platform=linux_amd64"}
+		code := c.Run(args)
+
+		if code != 1 {
+			t.Fatalf("wrong exit code; expected 1, got %d", code)
+		}
+		output := ui.ErrorWriter.String()
+		if !strings.Contains(output, "The -platform=all option cannot be combined with other -platform options.") {
+			t.Fatalf("missing expected error message: %s", output)
+		}
+	})
+
+	t.Run("platform all with mirror", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersLockCommand{
+			Meta: Meta{
+				Ui: ui,
+			},
+		}
+
+		// -platform=all needs the origin registry's platform information,
+		// which mirrors don't provide
+		args := []string{"-platform=all", "-fs-mirror=/foo/"}
+		code := c.Run(args)
+
+		if code != 1 {
+			t.Fatalf("wrong exit code; expected 1, got %d", code)
+		}
+		output := ui.ErrorWriter.String()
+		if !strings.Contains(output, "asking each provider's origin registry") {
+			t.Fatalf("missing expected error message: %s", output)
+		}
+	})
+
 	t.Run("invalid provider argument", func(t *testing.T) {
 		ui := new(cli.MockUi)
 		c := &ProvidersLockCommand{
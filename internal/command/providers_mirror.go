@@ -1,15 +1,28 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/apparentlymart/go-versions/versions"
 	"github.com/hashicorp/go-getter"
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/copy"
 	"github.com/hashicorp/terraform/internal/getproviders"
 	"github.com/hashicorp/terraform/internal/httpclient"
 	"github.com/hashicorp/terraform/internal/tfdiags"
@@ -31,14 +44,106 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("providers mirror")
 	var optPlatforms FlagStringSlice
+	var optAlsoVersions FlagStringSlice
+	var optLayout string
+	var optKeep bool
+	var optReport string
+	var optJSON bool
+	var optIgnoreCLIConfig bool
+	var optParallelism int
+	var optSource string
+	var optPrune bool
+	var optVerifyOnly bool
+	var optLockfileOnly bool
+	var optServe string
+	var optTLSCertFile string
+	var optTLSKeyFile string
 	cmdFlags.Var(&optPlatforms, "platform", "target platform")
+	cmdFlags.Var(&optAlsoVersions, "also-version", "additional provider version to mirror")
+	cmdFlags.StringVar(&optLayout, "layout", "packed", "mirror directory layout")
+	cmdFlags.BoolVar(&optKeep, "keep", false, "skip packages already present in the mirror")
+	cmdFlags.StringVar(&optReport, "report", "", "write a JSON report of the run to the given file")
+	cmdFlags.BoolVar(&optJSON, "json", false, "machine-readable output")
+	cmdFlags.BoolVar(&optIgnoreCLIConfig, "ignore-cli-config", false, "ignore any provider_installation rules in the CLI configuration")
+	cmdFlags.IntVar(&optParallelism, "parallelism", 1, "number of packages to download concurrently")
+	cmdFlags.StringVar(&optSource, "source", "", "mirror from the given filesystem or network mirror instead of the origin registries")
+	cmdFlags.BoolVar(&optPrune, "prune", false, "remove packages that the current configuration no longer allows")
+	cmdFlags.BoolVar(&optVerifyOnly, "verify-only", false, "re-validate an existing mirror without downloading anything")
+	cmdFlags.BoolVar(&optLockfileOnly, "lockfile-only", false, "mirror exactly what the dependency lock file records, without evaluating the configuration")
+	cmdFlags.StringVar(&optServe, "serve", "", "serve the mirror directory as a network mirror on the given address")
+	cmdFlags.StringVar(&optTLSCertFile, "tls-cert-file", "", "TLS certificate for -serve")
+	cmdFlags.StringVar(&optTLSKeyFile, "tls-key-file", "", "TLS private key for -serve")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
 	}
 
+	events := &mirrorEvents{ui: c.Ui, jsonMode: optJSON}
+
+	// diags accumulates problems that make the whole run invalid, such as
+	// invalid options or an unloadable configuration, while mirrorDiags
+	// accumulates failures to mirror individual packages. The two are shown
+	// together but only the former forces the "total failure" exit status,
+	// since individual package failures are summarized separately at the end
+	// of the run.
 	var diags tfdiags.Diagnostics
+	var mirrorDiags tfdiags.Diagnostics
+
+	// Extra versions requested with -also-version are mirrored in addition
+	// to whatever the configuration's version constraints select.
+	extraVersions := map[addrs.Provider][]getproviders.Version{}
+	for _, spec := range optAlsoVersions {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid additional version",
+				fmt.Sprintf("The string %q given in the -also-version option is not valid: must be a provider source address and an exact version, separated by an equals sign, like hashicorp/aws=5.41.0.", spec),
+			))
+			continue
+		}
+		provider, moreDiags := addrs.ParseProviderSourceString(parts[0])
+		if moreDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid additional version",
+				fmt.Sprintf("The string %q given in the -also-version option does not include a valid provider source address: %s.", spec, moreDiags.Err()),
+			))
+			continue
+		}
+		version, err := getproviders.ParseVersion(parts[1])
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid additional version",
+				fmt.Sprintf("The string %q given in the -also-version option does not include a valid version number: %s.", spec, err),
+			))
+			continue
+		}
+		extraVersions[provider] = append(extraVersions[provider], version)
+	}
+
+	if optParallelism < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallelism",
+			fmt.Sprintf("The value %d given in the -parallelism option is not valid: must be at least 1.", optParallelism),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if optLayout != "packed" && optLayout != "unpacked" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid mirror layout",
+			fmt.Sprintf("The string %q given in the -layout option is not a valid mirror layout. Must be either \"packed\" or \"unpacked\".", optLayout),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	unpacked := optLayout == "unpacked"
 
 	args = cmdFlags.Args()
 	if len(args) != 1 {
@@ -52,6 +157,79 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	}
 	outputDir := args[0]
 
+	// -serve doesn't mirror anything at all: it serves a directory populated
+	// by earlier runs using the provider network mirror protocol, until
+	// interrupted.
+	if optServe != "" {
+		if optVerifyOnly {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid serve options",
+				"The -serve and -verify-only command line options are mutually-exclusive.",
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		return c.runMirrorServe(optServe, optTLSCertFile, optTLSKeyFile, outputDir, events)
+	}
+	if optTLSCertFile != "" || optTLSKeyFile != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid TLS options",
+			"The -tls-cert-file and -tls-key-file command line options are used only with -serve.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// -lockfile-only mirrors exactly what the dependency lock file records
+	// rather than re-deriving selections from the configuration, so the
+	// options that exist to adjust or clean up configuration-driven
+	// selections don't make sense with it.
+	if optLockfileOnly && (optVerifyOnly || optPrune || len(optAlsoVersions) != 0) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid lockfile-only options",
+			"The -lockfile-only command line option cannot be combined with -verify-only, -prune, or -also-version.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// A single directory must use only one of the two layouts, because
+	// mixing packed and unpacked packages for the same provider would
+	// cause ambiguous results for filesystem mirror clients.
+	if existing, err := getproviders.SearchLocalDirectory(outputDir); err == nil {
+	Existing:
+		for _, metas := range existing {
+			for _, meta := range metas {
+				if _, isDir := meta.Location.(getproviders.PackageLocalDir); isDir != unpacked {
+					haveLayout := "unpacked"
+					if !isDir {
+						haveLayout = "packed"
+					}
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Error,
+						"Incompatible mirror directory layout",
+						fmt.Sprintf("The output directory already contains packages using the %q layout, so it cannot also contain %q layout packages. Use a separate output directory for each layout.", haveLayout, optLayout),
+					))
+					break Existing
+				}
+			}
+		}
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// -verify-only re-validates what is already in the mirror directory
+	// against the hashes and checksums recorded by earlier runs, without
+	// consulting the configuration or downloading anything.
+	if optVerifyOnly {
+		return c.runMirrorVerifyOnly(outputDir, events)
+	}
+
 	var platforms []getproviders.Platform
 	if len(optPlatforms) == 0 {
 		platforms = []getproviders.Platform{getproviders.CurrentPlatform}
@@ -71,10 +249,47 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		}
 	}
 
-	config, confDiags := c.loadConfig(".")
-	diags = diags.Append(confDiags)
-	reqs, moreDiags := config.ProviderRequirements()
-	diags = diags.Append(moreDiags)
+	// With -lockfile-only the requirements come from the dependency lock
+	// file alone: each provider is pinned to exactly the locked version,
+	// and the recorded hashes are used to verify the downloaded packages.
+	// Otherwise we evaluate the configuration's version constraints as
+	// usual.
+	var reqs getproviders.Requirements
+	lockVersions := map[addrs.Provider]getproviders.Version{}
+	lockHashes := map[addrs.Provider][]getproviders.Hash{}
+	if optLockfileOnly {
+		locks, lockDiags := c.lockedDependencies()
+		diags = diags.Append(lockDiags)
+		if locks != nil && !lockDiags.HasErrors() {
+			providerLocks := locks.AllProviders()
+			if len(providerLocks) == 0 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"No locked providers to mirror",
+					fmt.Sprintf("The -lockfile-only option mirrors the provider selections recorded in %s, but that file records no provider selections in the current working directory. Run \"terraform init\" first to create it.", dependencyLockFilename),
+				))
+			}
+			reqs = make(getproviders.Requirements, len(providerLocks))
+			for provider, lock := range providerLocks {
+				reqs[provider] = lock.VersionConstraints()
+				lockVersions[provider] = lock.Version()
+				lockHashes[provider] = lock.AllHashes()
+				if len(lockHashes[provider]) == 0 {
+					diags = diags.Append(tfdiags.Sourceless(
+						tfdiags.Warning,
+						"No recorded checksums",
+						fmt.Sprintf("The dependency lock file records no checksums for %s, so its packages will be mirrored without verification against the lock file.", provider.ForDisplay()),
+					))
+				}
+			}
+		}
+	} else {
+		config, confDiags := c.loadConfig(".")
+		diags = diags.Append(confDiags)
+		configReqs, reqDiags := config.ProviderRequirements()
+		diags = diags.Append(reqDiags)
+		reqs = configReqs
+	}
 
 	// If we have any error diagnostics already then we won't proceed further.
 	if diags.HasErrors() {
@@ -82,22 +297,74 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Unlike other commands, this command always consults the origin registry
-	// for every provider so that it can be used to update a local mirror
-	// directory without needing to first disable that local mirror
-	// in the CLI configuration.
-	source := getproviders.NewMemoizeSource(
-		getproviders.NewRegistrySource(c.Services),
-	)
-
-	// Providers from registries always use HTTP, so we don't need the full
-	// generality of go-getter but it's still handy to use the HTTP getter
-	// as an easy way to download over HTTP into a file on disk.
-	httpGetter := getter.HttpGetter{
-		Client: httpclient.New(),
-		Netrc:  true,
+	// By default this command obtains packages through the same installation
+	// methods that "terraform init" would use, honoring any include and
+	// exclude rules from the provider_installation block in the CLI
+	// configuration, so that the mirror cannot violate a policy that init
+	// enforces. The -ignore-cli-config option restores the historical
+	// behavior of always consulting each provider's origin registry, which
+	// is useful when updating a local mirror directory without needing to
+	// first disable that local mirror in the CLI configuration. The -source
+	// option instead obtains everything from one specific existing mirror,
+	// which is useful for copying a mirror forward onto another system
+	// without re-downloading from the origin registries.
+	var source getproviders.Source
+	switch {
+	case optSource != "":
+		if optIgnoreCLIConfig {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid source options",
+				"The -source and -ignore-cli-config command line options are mutually-exclusive.",
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if u, err := url.Parse(optSource); err == nil && (u.Scheme == "https" || u.Scheme == "http") {
+			if u.Scheme != "https" {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid mirror source",
+					"The -source option requires either a local directory path or a valid https: URL as the network mirror base URL.",
+				))
+				c.showDiagnostics(diags)
+				return 1
+			}
+			source = getproviders.NewMemoizeSource(
+				getproviders.NewHTTPMirrorSource(u, c.Services.CredentialsSource()),
+			)
+		} else {
+			if info, err := os.Stat(optSource); err != nil || !info.IsDir() {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid mirror source",
+					fmt.Sprintf("The directory %s given in the -source option does not exist or is not a directory.", optSource),
+				))
+				c.showDiagnostics(diags)
+				return 1
+			}
+			source = getproviders.NewMemoizeSource(
+				getproviders.NewFilesystemMirrorSource(optSource),
+			)
+		}
+	case optIgnoreCLIConfig:
+		source = getproviders.NewMemoizeSource(
+			getproviders.NewRegistrySource(c.Services),
+		)
+	default:
+		source = c.providerInstallSource()
 	}
 
+	// Providers from registries always use HTTP, and we download them with
+	// getproviders.DownloadPackageArchive so that a download interrupted
+	// partway through can be resumed by a later run instead of starting
+	// over from byte zero.
+	httpClient := httpclient.New()
+
+	// For the unpacked layout we additionally need to extract each verified
+	// archive into its target directory.
+	zipDecompressor := getter.ZipDecompressor{}
+
 	// The following logic is similar to that used by the provider installer
 	// in package providercache, but different in a few ways:
 	// - It produces the packed directory layout rather than the unpacked
@@ -113,110 +380,275 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	//   infrequently to update a mirror, so it doesn't need to optimize away
 	//   fetches of packages that might already be present.
 
+	// We always collect a report of what we decided and why, so that the
+	// decisions can be logged for debugging and summarized at the end of the
+	// run; we only write the report to disk when the -report option is set.
+	report := newMirrorRunReport(events)
+
 	ctx, cancel := c.InterruptibleContext()
 	defer cancel()
+
+	// mirroredVersions records which versions this run chose for each
+	// provider, so that -prune can distinguish versions we just placed
+	// deliberately (including -also-version extras) from stale leftovers.
+	mirroredVersions := map[addrs.Provider]map[getproviders.Version]struct{}{}
+
 	for provider, constraints := range reqs {
 		if provider.IsBuiltIn() {
-			c.Ui.Output(fmt.Sprintf("- Skipping %s because it is built in to Terraform CLI", provider.ForDisplay()))
-			continue
-		}
-		constraintsStr := getproviders.VersionConstraintsString(constraints)
-		c.Ui.Output(fmt.Sprintf("- Mirroring %s...", provider.ForDisplay()))
-		// First we'll look for the latest version that matches the given
-		// constraint, which we'll then try to mirror for each target platform.
-		acceptable := versions.MeetingConstraints(constraints)
-		avail, _, err := source.AvailableVersions(ctx, provider)
-		candidates := avail.Filter(acceptable)
-		if err == nil && len(candidates) == 0 {
-			err = fmt.Errorf("no releases match the given constraints %s", constraintsStr)
-		}
-		if err != nil {
-			diags = diags.Append(tfdiags.Sourceless(
-				tfdiags.Error,
-				"Provider not available",
-				fmt.Sprintf("Failed to download %s from its origin registry: %s.", provider.String(), err),
-			))
+			events.human(fmt.Sprintf("- Skipping %s because it is built in to Terraform CLI", provider.ForDisplay()))
+			log.Printf("[DEBUG] providers mirror: skipping %s because it is built in to Terraform CLI", provider)
+			if _, ok := extraVersions[provider]; ok {
+				delete(extraVersions, provider)
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid additional version",
+					fmt.Sprintf("Cannot mirror additional versions of %s because it is built in to Terraform CLI.", provider.ForDisplay()),
+				))
+			}
 			continue
 		}
-		selected := candidates.Newest()
-		if len(constraintsStr) > 0 {
-			c.Ui.Output(fmt.Sprintf("  - Selected v%s to meet constraints %s", selected.String(), constraintsStr))
-		} else {
-			c.Ui.Output(fmt.Sprintf("  - Selected v%s with no constraints", selected.String()))
-		}
-		for _, platform := range platforms {
-			c.Ui.Output(fmt.Sprintf("  - Downloading package for %s...", platform.String()))
-			meta, err := source.PackageMeta(ctx, provider, selected, platform)
-			if err != nil {
+		if !optIgnoreCLIConfig && optSource == "" {
+			if localDir, ok := c.ProviderDevOverrides[provider]; ok {
+				// A provider with a development override is being supplied
+				// from a local build, so mirroring a release of it would
+				// silently disagree with what other commands are running.
+				events.human(fmt.Sprintf("- Skipping %s because it has a development override in the CLI configuration", provider.ForDisplay()))
+				log.Printf("[DEBUG] providers mirror: skipping %s because of its dev_overrides entry for %s", provider, localDir)
 				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Provider release not available",
-					fmt.Sprintf("Failed to download %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
+					tfdiags.Warning,
+					"Provider development override not mirrored",
+					fmt.Sprintf("The CLI configuration has a dev_overrides entry for %s pointing at %s, so it was not mirrored. Use -ignore-cli-config to mirror a release of it from its origin registry anyway.", provider.ForDisplay(), localDir),
 				))
+				delete(extraVersions, provider) // the warning covers any -also-version requests too
 				continue
 			}
-			urlStr, ok := meta.Location.(getproviders.PackageHTTPURL)
-			if !ok {
-				// We don't expect to get non-HTTP locations here because we're
-				// using the registry source, so this seems like a bug in the
-				// registry source.
+			if reason := mirrorExclusionReason(source, provider); reason != "" {
+				events.human(fmt.Sprintf("- Skipping %s because %s", provider.ForDisplay(), reason))
+				log.Printf("[DEBUG] providers mirror: skipping %s because %s", provider, reason)
 				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Provider release not available",
-					fmt.Sprintf("Failed to download %s v%s for %s: Terraform's provider registry client returned unexpected location type %T. This is a bug in Terraform.", provider.String(), selected.String(), platform.String(), meta.Location),
+					tfdiags.Warning,
+					"Provider excluded from mirror",
+					fmt.Sprintf("%s was not mirrored because %s. Use -ignore-cli-config to mirror it from its origin registry anyway.", provider.ForDisplay(), reason),
 				))
+				delete(extraVersions, provider) // the warning covers any -also-version requests too
 				continue
 			}
-			urlObj, err := url.Parse(string(urlStr))
+		}
+		constraintsStr := getproviders.VersionConstraintsString(constraints)
+		providerReport := report.providerReport(provider, constraintsStr)
+		events.human(fmt.Sprintf("- Mirroring %s...", provider.ForDisplay()))
+		var selected getproviders.Version
+		availSet := map[getproviders.Version]struct{}{}
+		if optLockfileOnly {
+			// The lock file pins an exact version, so there's no version
+			// selection to do at all.
+			selected = lockVersions[provider]
+			providerReport.CandidateVersions = []string{selected.String()}
+			providerReport.SelectedVersion = selected.String()
+			log.Printf("[DEBUG] providers mirror: selected %s v%s as recorded in the dependency lock file", provider, selected)
+			events.emit("provider_selected", map[string]interface{}{
+				"provider":    provider.String(),
+				"version":     selected.String(),
+				"constraints": constraintsStr,
+			})
+			events.human(fmt.Sprintf("  - Selected v%s recorded in the dependency lock file", selected.String()))
+		} else {
+			// First we'll look for the latest version that matches the given
+			// constraint, which we'll then try to mirror for each target platform.
+			acceptable := versions.MeetingConstraints(constraints)
+			avail, _, err := source.AvailableVersions(ctx, provider)
+			// List.Filter modifies the list in place, so we must note which
+			// versions exist upstream before filtering by the constraints.
+			for _, haveVersion := range avail {
+				availSet[haveVersion] = struct{}{}
+			}
+			candidates := avail.Filter(acceptable)
+			for _, candidate := range candidates {
+				providerReport.CandidateVersions = append(providerReport.CandidateVersions, candidate.String())
+			}
+			log.Printf("[DEBUG] providers mirror: %s has %d candidate versions matching constraints %q: %s", provider, len(candidates), constraintsStr, candidates)
+			if err == nil && len(candidates) == 0 {
+				err = fmt.Errorf("no releases match the given constraints %s", constraintsStr)
+			}
 			if err != nil {
-				// We don't expect to get non-HTTP locations here because we're
-				// using the registry source, so this seems like a bug in the
-				// registry source.
-				diags = diags.Append(tfdiags.Sourceless(
+				providerReport.Error = err.Error()
+				log.Printf("[DEBUG] providers mirror: cannot select a version of %s: %s", provider, err)
+				events.emit("provider_failed", map[string]interface{}{
+					"provider": provider.String(),
+					"error":    err.Error(),
+				})
+				mirrorDiags = mirrorDiags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
-					"Invalid URL for provider release",
-					fmt.Sprintf("The origin registry for %s returned an invalid URL for v%s on %s: %s.", provider.String(), selected.String(), platform.String(), err),
+					"Provider not available",
+					fmt.Sprintf("Failed to download %s from its origin registry: %s.", provider.String(), err),
 				))
 				continue
 			}
-			// targetPath is the path where we ultimately want to place the
-			// downloaded archive, but we'll place it initially at stagingPath
-			// so we can verify its checksums and signatures before making
-			// it discoverable to mirror clients. (stagingPath intentionally
-			// does not follow the filesystem mirror file naming convention.)
-			targetPath := meta.PackedFilePath(outputDir)
-			stagingPath := filepath.Join(filepath.Dir(targetPath), "."+filepath.Base(targetPath))
-			err = httpGetter.GetFile(stagingPath, urlObj)
-			if err != nil {
+			selected = candidates.Newest()
+			providerReport.SelectedVersion = selected.String()
+			log.Printf("[DEBUG] providers mirror: selected %s v%s as the newest of the candidate versions", provider, selected)
+			events.emit("provider_selected", map[string]interface{}{
+				"provider":    provider.String(),
+				"version":     selected.String(),
+				"constraints": constraintsStr,
+			})
+			if len(constraintsStr) > 0 {
+				events.human(fmt.Sprintf("  - Selected v%s to meet constraints %s", selected.String(), constraintsStr))
+			} else {
+				events.human(fmt.Sprintf("  - Selected v%s with no constraints", selected.String()))
+			}
+		}
+
+		// Any extra versions requested for this provider get mirrored
+		// alongside the constraint-selected version, for the same set of
+		// target platforms.
+		mirrorVersions := []getproviders.Version{selected}
+	Extras:
+		for _, extra := range extraVersions[provider] {
+			for _, existing := range mirrorVersions {
+				if extra == existing {
+					// Requesting a version that is already being mirrored
+					// is a harmless no-op.
+					log.Printf("[DEBUG] providers mirror: %s v%s was requested with -also-version but is already being mirrored", provider, extra)
+					continue Extras
+				}
+			}
+			if _, available := availSet[extra]; !available {
 				diags = diags.Append(tfdiags.Sourceless(
 					tfdiags.Error,
-					"Cannot download provider release",
-					fmt.Sprintf("Failed to download %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
+					"Provider version not available",
+					fmt.Sprintf("The version %s requested for %s with -also-version is not available from the provider's origin registry.", extra.String(), provider.String()),
 				))
 				continue
 			}
-			if meta.Authentication != nil {
-				result, err := meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(stagingPath))
-				if err != nil {
-					diags = diags.Append(tfdiags.Sourceless(
-						tfdiags.Error,
-						"Invalid provider package",
-						fmt.Sprintf("Failed to authenticate %s v%s for %s: %s.", provider.String(), selected.String(), platform.String(), err),
-					))
-					continue
-				}
-				c.Ui.Output(fmt.Sprintf("  - Package authenticated: %s", result))
+			events.human(fmt.Sprintf("  - Also including v%s as requested", extra.String()))
+			log.Printf("[DEBUG] providers mirror: also mirroring %s v%s as requested with -also-version", provider, extra)
+			mirrorVersions = append(mirrorVersions, extra)
+		}
+		delete(extraVersions, provider)
+
+		mirroredVersions[provider] = make(map[getproviders.Version]struct{}, len(mirrorVersions))
+		for _, version := range mirrorVersions {
+			mirroredVersions[provider][version] = struct{}{}
+		}
+
+		// The packages for this provider can download concurrently, up to the
+		// limit set with -parallelism. We register all of the report objects
+		// up front, because registration mutates the report maps and so must
+		// stay on this goroutine, while each package's own outcome fields are
+		// touched only by the worker handling that package. Only the package
+		// downloads run concurrently: version selection above and the index
+		// writes below remain serialized per provider.
+		type mirrorPackageJob struct {
+			version  getproviders.Version
+			platform getproviders.Platform
+			report   *mirrorPlatformReport
+		}
+		jobs := make([]mirrorPackageJob, 0, len(mirrorVersions)*len(platforms))
+		for _, version := range mirrorVersions {
+			for _, platform := range platforms {
+				jobs = append(jobs, mirrorPackageJob{version, platform, providerReport.platformReport(version, platform)})
+			}
+		}
+		var wg sync.WaitGroup
+		var mirrorDiagsMu sync.Mutex
+		sem := make(chan struct{}, optParallelism)
+		for _, job := range jobs {
+			job := job // capture a per-iteration copy for the goroutine below
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				moreDiags := c.mirrorOnePackage(ctx, source, httpClient, zipDecompressor, events, job.report, provider, job.version, job.platform, outputDir, unpacked, optKeep, lockHashes[provider])
+				mirrorDiagsMu.Lock()
+				mirrorDiags = mirrorDiags.Append(moreDiags)
+				mirrorDiagsMu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		// If any package's authentication verified a signed checksums
+		// document then we archive the document and its signature alongside
+		// the packages, so that consumers of the mirror retain the origin
+		// registry's chain of trust. The document covers every platform of
+		// its version, so one copy per version suffices.
+		recordedSums := map[getproviders.Version]struct{}{}
+		for _, job := range jobs {
+			document, signature := job.report.signedDocument, job.report.signedSignature
+			if len(document) == 0 || len(signature) == 0 {
+				continue
+			}
+			if _, done := recordedSums[job.version]; done {
+				continue
+			}
+			recordedSums[job.version] = struct{}{}
+			var pkgPath string
+			if unpacked {
+				pkgPath = getproviders.UnpackedDirectoryPathForPackage(outputDir, provider, job.version, job.platform)
+			} else {
+				pkgPath = getproviders.PackedFilePathForPackage(outputDir, provider, job.version, job.platform)
+			}
+			sumsPath := filepath.Join(filepath.Dir(pkgPath), fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", provider.Type, job.version.String()))
+			err := ioutil.WriteFile(sumsPath, document, 0644)
+			if err == nil {
+				err = ioutil.WriteFile(sumsPath+".sig", signature, 0644)
 			}
-			os.Remove(targetPath) // okay if it fails because we're going to try to rename over it next anyway
-			err = os.Rename(stagingPath, targetPath)
 			if err != nil {
+				// Failing to archive the signing metadata doesn't invalidate
+				// the packages themselves, so this is only a warning.
+				log.Printf("[WARN] providers mirror: failed to record signed checksums for %s v%s: %s", provider, job.version, err)
 				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Error,
-					"Cannot download provider release",
-					fmt.Sprintf("Failed to place %s package into mirror directory: %s.", provider.String(), err),
+					tfdiags.Warning,
+					"Failed to record signed checksums",
+					fmt.Sprintf("Could not write the signed checksums document for %s v%s alongside the mirrored packages: %s.", provider.ForDisplay(), job.version.String(), err),
 				))
 				continue
 			}
+			events.human(fmt.Sprintf("  - Recorded signed checksums for v%s", job.version.String()))
+		}
+	}
+
+	// Any remaining entries in extraVersions name providers that the
+	// configuration doesn't require at all, which we treat as an error so
+	// that a typo in a provider source address can't silently produce an
+	// incomplete mirror.
+	remaining := make([]addrs.Provider, 0, len(extraVersions))
+	for provider := range extraVersions {
+		remaining = append(remaining, provider)
+	}
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i].String() < remaining[j].String() })
+	for _, provider := range remaining {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid additional version",
+			fmt.Sprintf("Cannot mirror additional versions of %s with -also-version because the current configuration does not require that provider.", provider.ForDisplay()),
+		))
+	}
+
+	// Pruning happens after the downloads so that a package which merely
+	// moved between versions is never missing from the mirror partway
+	// through the run, and before the index regeneration below so that the
+	// rewritten indexes describe only what remains.
+	if optPrune {
+		pruneDiags := c.pruneStaleMirrorPackages(outputDir, reqs, mirroredVersions, events, report)
+		diags = diags.Append(pruneDiags)
+	}
+
+	if optReport != "" {
+		reportJSON, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			// Should never happen because the input here is entirely under
+			// our control.
+			panic(fmt.Sprintf("failed to encode mirror report: %s", err))
+		}
+		err = ioutil.WriteFile(optReport, append(reportJSON, '\n'), 0644)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write mirror report",
+				fmt.Sprintf("Could not write a report of this run to %s: %s.", optReport, err),
+			))
 		}
 	}
 
@@ -232,6 +664,26 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 			"Failed to update indexes",
 			fmt.Sprintf("Could not scan the output directory to get package metadata for the JSON indexes: %s.", err),
 		))
+		available = nil // the following loops will be no-ops
+	}
+
+	// The top-level providers index summarizes everything in the mirror for
+	// the benefit of tooling that wants to enumerate the mirror's contents
+	// without walking the directory structure. It's informational only, so
+	// we maintain it for both layouts even though mirror clients themselves
+	// will never read it.
+	if err := updateMirrorProvidersIndex(outputDir, available); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to update indexes",
+			fmt.Sprintf("Failed to write the top-level provider index %s: %s.", mirrorProvidersIndexFilename, err),
+		))
+	}
+
+	// The remaining JSON indexes describe only packed (archive) packages,
+	// since they exist to support network mirrors, so we skip them entirely
+	// for the unpacked layout.
+	if unpacked {
 		available = nil // the following loop will be a no-op
 	}
 	for provider, metas := range available {
@@ -320,10 +772,1086 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		}
 	}
 
-	c.showDiagnostics(diags)
+	// Every run ends with a summary of what was and wasn't mirrored, so
+	// that automation can distinguish a complete mirror from a partial one
+	// without parsing the individual error messages.
+	summary := report.summary()
+	c.showMirrorSummary(events, summary)
+
+	c.showDiagnostics(diags.Append(mirrorDiags))
 	if diags.HasErrors() {
+		// Problems with the run as a whole, such as invalid options or an
+		// unwritable index, always take the "total failure" exit status.
 		return 1
 	}
+	switch {
+	case summary.Failed == 0 && summary.PartiallyMirrored == 0:
+		return 0 // complete success
+	case summary.FullyMirrored > 0 || summary.PartiallyMirrored > 0:
+		return 2 // partial success: some packages were mirrored, some were not
+	default:
+		return 1 // total failure: nothing was mirrored at all
+	}
+}
+
+// mirrorOnePackage fetches the package for a single (provider, version,
+// platform) triple into the mirror directory, recording the outcome in the
+// given report object. It returns diagnostics rather than showing them so
+// that Run can accumulate them as "mirrorDiags", which don't force the
+// total-failure exit status.
+//
+// Run may call this from several goroutines at once, up to the -parallelism
+// limit, so everything this method touches must be safe for concurrent use:
+// the report object belongs to this package alone, and the events object
+// serializes its own output.
+func (c *ProvidersMirrorCommand) mirrorOnePackage(ctx context.Context, source getproviders.Source, httpClient *http.Client, zipDecompressor getter.ZipDecompressor, events *mirrorEvents, platformReport *mirrorPlatformReport, provider addrs.Provider, version getproviders.Version, platform getproviders.Platform, outputDir string, unpacked, optKeep bool, lockHashes []getproviders.Hash) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	meta, err := source.PackageMeta(ctx, provider, version, platform)
+	if err != nil {
+		platformReport.fail(err)
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider release not available",
+			fmt.Sprintf("Failed to download %s v%s for %s: %s.", provider.String(), version.String(), platform.String(), err),
+		))
+		return diags
+	}
+	targetPath := meta.PackedFilePath(outputDir)
+	if unpacked {
+		targetPath = meta.UnpackedDirectoryPath(outputDir)
+	}
+	if optKeep {
+		if _, err := os.Stat(targetPath); err == nil {
+			events.human(fmt.Sprintf("  - Package for %s is already present; skipping", platform.String()))
+			platformReport.keptExisting()
+			log.Printf("[DEBUG] providers mirror: %s v%s for %s is already present at %s; keeping the existing package", provider, version, platform, targetPath)
+			return diags
+		}
+	}
+	// targetPath is the path where we ultimately want to place the
+	// package, but we'll fetch the archive initially to stagingPath
+	// so we can verify its checksums and signatures before making it
+	// discoverable to mirror clients. (stagingPath intentionally does
+	// not follow the filesystem mirror file naming convention.)
+	stagingPath := filepath.Join(filepath.Dir(targetPath), "."+filepath.Base(targetPath))
+	if unpacked {
+		stagingPath += ".zip"
+	}
+	switch location := meta.Location.(type) {
+	case getproviders.PackageHTTPURL:
+		events.human(fmt.Sprintf("  - Downloading package for %s...", platform.String()))
+		urlObj, err := url.Parse(string(location))
+		if err != nil {
+			// We don't expect invalid URLs here because they come
+			// from a registry or network mirror client, so this
+			// seems like a bug in that client.
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid URL for provider release",
+				fmt.Sprintf("The origin registry for %s returned an invalid URL for v%s on %s: %s.", provider.String(), version.String(), platform.String(), err),
+			))
+			return diags
+		}
+		err = getproviders.DownloadPackageArchive(ctx, httpClient, urlObj.String(), stagingPath)
+		if err != nil {
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot download provider release",
+				fmt.Sprintf("Failed to download %s v%s for %s: %s.", provider.String(), version.String(), platform.String(), err),
+			))
+			return diags
+		}
+	case getproviders.PackageLocalArchive:
+		// A filesystem mirror selected by the provider_installation
+		// configuration provides its packages as local archives,
+		// which we copy rather than download.
+		events.human(fmt.Sprintf("  - Copying package for %s...", platform.String()))
+		err := os.MkdirAll(filepath.Dir(stagingPath), 0755)
+		if err == nil {
+			err = copy.CopyFile(string(location), stagingPath)
+		}
+		if err != nil {
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot copy provider release",
+				fmt.Sprintf("Failed to copy %s v%s for %s from %s: %s.", provider.String(), version.String(), platform.String(), location, err),
+			))
+			return diags
+		}
+	default:
+		// A filesystem mirror can also provide packages as unpacked
+		// directories, which we can't copy into either mirror layout
+		// without repacking them.
+		err := fmt.Errorf("the selected installation method provides this package in a form that cannot be copied into a mirror")
+		platformReport.fail(err)
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot mirror provider release",
+			fmt.Sprintf("Cannot mirror %s v%s for %s: %s.", provider.String(), version.String(), platform.String(), err),
+		))
+		return diags
+	}
+	if info, err := os.Stat(stagingPath); err == nil {
+		platformReport.SizeBytes = info.Size()
+	}
+	// In -lockfile-only mode each package must match one of the checksums
+	// recorded in the dependency lock file, so that the mirror can't end
+	// up containing anything other than what "terraform init" verified.
+	if len(lockHashes) > 0 {
+		matches, err := getproviders.PackageMatchesAnyHash(getproviders.PackageLocalArchive(stagingPath), lockHashes)
+		if err == nil && !matches {
+			err = fmt.Errorf("package does not match any of the checksums recorded in the dependency lock file")
+		}
+		if err != nil {
+			os.Remove(stagingPath) // okay if it fails because it only leaves a stray staging file behind
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider package",
+				fmt.Sprintf("Failed to verify %s v%s for %s against the dependency lock file: %s.", provider.String(), version.String(), platform.String(), err),
+			))
+			return diags
+		}
+		events.human(fmt.Sprintf("  - Package for %s matches the dependency lock file", platform.String()))
+	}
+	if meta.Authentication != nil {
+		result, err := meta.Authentication.AuthenticatePackage(getproviders.PackageLocalArchive(stagingPath))
+		if err != nil {
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider package",
+				fmt.Sprintf("Failed to authenticate %s v%s for %s: %s.", provider.String(), version.String(), platform.String(), err),
+			))
+			return diags
+		}
+		events.human(fmt.Sprintf("  - Package authenticated: %s", result))
+		if signed, ok := meta.Authentication.(getproviders.PackageAuthenticationSignedDocument); ok {
+			// The signature was verified as part of the authentication
+			// above, so we can archive the document for the mirror's
+			// consumers. Run writes it out once the workers are done.
+			platformReport.signedDocument, platformReport.signedSignature = signed.SignedDocument()
+		}
+	}
+	if unpacked {
+		// For the unpacked layout we extract the verified archive
+		// into the target directory, preserving the file modes
+		// recorded in the archive, and then discard the archive.
+		os.RemoveAll(targetPath) // okay if it fails because Decompress will then fail below anyway
+		err = zipDecompressor.Decompress(targetPath, stagingPath, true, 0000)
+		os.Remove(stagingPath) // okay if it fails because it only leaves a stray staging file behind
+		if err != nil {
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot extract provider release",
+				fmt.Sprintf("Failed to extract %s package into mirror directory: %s.", provider.String(), err),
+			))
+			return diags
+		}
+		hash, err := getproviders.PackageHashV1(getproviders.PackageLocalDir(targetPath))
+		if err != nil {
+			platformReport.fail(err)
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot extract provider release",
+				fmt.Sprintf("Failed to calculate a hash for the extracted %s package: %s.", provider.String(), err),
+			))
+			return diags
+		}
+		events.human(fmt.Sprintf("  - Extracted package with %s", hash))
+		platformReport.downloaded(hash)
+		return diags
+	}
+	os.Remove(targetPath) // okay if it fails because we're going to try to rename over it next anyway
+	err = os.Rename(stagingPath, targetPath)
+	if err != nil {
+		platformReport.fail(err)
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot download provider release",
+			fmt.Sprintf("Failed to place %s package into mirror directory: %s.", provider.String(), err),
+		))
+		return diags
+	}
+	hash, err := getproviders.PackageHashV1(getproviders.PackageLocalArchive(targetPath))
+	if err != nil {
+		// The package is in place at this point, so a hashing failure
+		// only leaves a gap in the report rather than failing the
+		// whole mirror operation.
+		log.Printf("[WARN] providers mirror: failed to calculate a hash for %s v%s on %s: %s", provider, version, platform, err)
+	}
+	platformReport.downloaded(hash)
+	return diags
+}
+
+// runMirrorVerifyOnly implements the -verify-only mode, which re-validates
+// the packages already present in the mirror directory against the hashes
+// recorded in the per-version JSON index files and, where present, the
+// archived SHA256SUMS documents, without downloading anything.
+//
+// The exit status is 0 when no package failed to verify and 1 when at least
+// one did. Packages with no recorded hashes at all, such as those in the
+// "unpacked" layout, are counted separately and don't affect the exit status.
+func (c *ProvidersMirrorCommand) runMirrorVerifyOnly(outputDir string, events *mirrorEvents) int {
+	var diags tfdiags.Diagnostics
+	available, err := getproviders.SearchLocalDirectory(outputDir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to verify mirror directory",
+			fmt.Sprintf("Could not scan the output directory to find packages to verify: %s.", err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	providers := make([]addrs.Provider, 0, len(available))
+	for provider := range available {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].String() < providers[j].String() })
+
+	var verified, failed, unchecked int
+	for _, provider := range providers {
+		events.human(fmt.Sprintf("- Verifying %s...", provider.ForDisplay()))
+		for _, meta := range available[provider] {
+			archivePath, isArchive := meta.Location.(getproviders.PackageLocalArchive)
+			if !isArchive {
+				// The unpacked layout records no hashes, so there is nothing
+				// to verify such a package against.
+				unchecked++
+				events.human(fmt.Sprintf("  - %s v%s: no recorded hashes to verify against", meta.TargetPlatform.String(), meta.Version.String()))
+				continue
+			}
+			var problems []string
+			checkedAnything := false
+
+			// The per-version JSON index records the package hashes that
+			// earlier runs computed.
+			indexPath := filepath.Join(filepath.Dir(string(archivePath)), meta.Version.String()+".json")
+			if indexSrc, err := ioutil.ReadFile(indexPath); err == nil {
+				var index struct {
+					Archives map[string]struct {
+						Hashes []string `json:"hashes"`
+					} `json:"archives"`
+				}
+				if err := json.Unmarshal(indexSrc, &index); err != nil {
+					problems = append(problems, fmt.Sprintf("invalid index file %s: %s", indexPath, err))
+				} else if archive, ok := index.Archives[meta.TargetPlatform.String()]; !ok {
+					problems = append(problems, fmt.Sprintf("no entry for this platform in %s", indexPath))
+				} else {
+					hashes := make([]getproviders.Hash, 0, len(archive.Hashes))
+					for _, hashStr := range archive.Hashes {
+						if hash, err := getproviders.ParseHash(hashStr); err == nil {
+							hashes = append(hashes, hash)
+						}
+					}
+					checkedAnything = true
+					if matches, err := meta.MatchesAnyHash(hashes); err != nil {
+						problems = append(problems, fmt.Sprintf("failed to calculate checksum: %s", err))
+					} else if !matches {
+						problems = append(problems, "package does not match the hashes recorded in the JSON index")
+					}
+				}
+			}
+
+			// If a signed checksums document was archived alongside the
+			// packages then we also check the archive's SHA-256 sum against
+			// its entry in the document.
+			sumsPath := filepath.Join(filepath.Dir(string(archivePath)), fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", provider.Type, meta.Version.String()))
+			if document, err := ioutil.ReadFile(sumsPath); err == nil {
+				wantFilename := filepath.Base(string(archivePath))
+				found := false
+				for _, line := range strings.Split(string(document), "\n") {
+					fields := strings.Fields(line)
+					if len(fields) != 2 || fields[1] != wantFilename {
+						continue
+					}
+					found = true
+					checkedAnything = true
+					gotHash, err := getproviders.PackageHashLegacyZipSHA(archivePath)
+					if err != nil {
+						problems = append(problems, fmt.Sprintf("failed to calculate SHA-256 checksum: %s", err))
+					} else if gotHash != getproviders.HashSchemeZip.New(fields[0]) {
+						problems = append(problems, "package does not match the archived SHA256SUMS document")
+					}
+					break
+				}
+				if !found {
+					problems = append(problems, "no entry for this package in the archived SHA256SUMS document")
+				}
+			}
+
+			switch {
+			case len(problems) > 0:
+				failed++
+				for _, problem := range problems {
+					events.human(fmt.Sprintf("  - %s v%s: %s", meta.TargetPlatform.String(), meta.Version.String(), problem))
+				}
+				events.emit("package_verify_failed", map[string]interface{}{
+					"provider": provider.String(),
+					"version":  meta.Version.String(),
+					"platform": meta.TargetPlatform.String(),
+					"problems": problems,
+				})
+			case !checkedAnything:
+				unchecked++
+				events.human(fmt.Sprintf("  - %s v%s: no recorded hashes to verify against", meta.TargetPlatform.String(), meta.Version.String()))
+				events.emit("package_unverified", map[string]interface{}{
+					"provider": provider.String(),
+					"version":  meta.Version.String(),
+					"platform": meta.TargetPlatform.String(),
+				})
+			default:
+				verified++
+				events.human(fmt.Sprintf("  - %s v%s: verified", meta.TargetPlatform.String(), meta.Version.String()))
+				events.emit("package_verified", map[string]interface{}{
+					"provider": provider.String(),
+					"version":  meta.Version.String(),
+					"platform": meta.TargetPlatform.String(),
+				})
+			}
+		}
+	}
+
+	events.emit("summary", map[string]interface{}{
+		"verified":   verified,
+		"failed":     failed,
+		"unverified": unchecked,
+	})
+	events.human("")
+	events.human(fmt.Sprintf("Summary: %d packages verified, %d failed, %d without recorded hashes.", verified, failed, unchecked))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runMirrorServe implements the -serve option, which serves an existing
+// mirror directory over HTTP using the provider network mirror protocol
+// until the process is interrupted.
+//
+// The JSON index files that this command maintains in the "packed" layout
+// are exactly the documents the protocol requires, and the protocol locates
+// package archives through relative URLs within those documents, so serving
+// a mirror is a matter of careful static file serving rather than of any
+// dynamic request handling.
+func (c *ProvidersMirrorCommand) runMirrorServe(listenAddr, certFile, keyFile, outputDir string, events *mirrorEvents) int {
+	var diags tfdiags.Diagnostics
+	if (certFile == "") != (keyFile == "") {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid TLS options",
+			"The -tls-cert-file and -tls-key-file command line options must be used together.",
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	if info, err := os.Stat(outputDir); err != nil || !info.IsDir() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid mirror directory",
+			fmt.Sprintf("The directory %s does not exist or is not a directory.", outputDir),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot serve mirror directory",
+			fmt.Sprintf("Failed to listen on %s: %s.", listenAddr, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	server := &http.Server{
+		Handler: &mirrorServeHandler{dir: outputDir, events: events},
+	}
+
+	// An interrupt stops accepting new requests, lets any in-flight ones
+	// finish within a grace period, and then makes the Serve call below
+	// return so that we can exit cleanly.
+	ctx, cancel := c.InterruptibleContext()
+	defer cancel()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	scheme := "http"
+	if certFile != "" {
+		scheme = "https"
+	}
+	events.human(fmt.Sprintf("Serving the provider mirror in %s at %s://%s/", outputDir, scheme, listener.Addr()))
+	if certFile == "" {
+		events.human("\nNote: Terraform requires network mirrors to be reachable over HTTPS, so to use\nthis mirror you must either pass -tls-cert-file and -tls-key-file or place the\nserver behind a TLS-terminating reverse proxy.")
+	}
+	events.emit("serving", map[string]interface{}{
+		"address": listener.Addr().String(),
+		"tls":     certFile != "",
+	})
+
+	if certFile != "" {
+		err = server.ServeTLS(listener, certFile, keyFile)
+	} else {
+		err = server.Serve(listener)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot serve mirror directory",
+			fmt.Sprintf("The mirror server failed: %s.", err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	<-shutdownDone
+	events.human("Mirror server stopped.")
+	return 0
+}
+
+// mirrorServeHandler serves the files of a mirror directory for the benefit
+// of provider network mirror clients. It permits only GET and HEAD requests,
+// never renders directory listings, and rejects any path that would escape
+// the mirror directory.
+type mirrorServeHandler struct {
+	dir    string
+	events *mirrorEvents
+}
+
+func (h *mirrorServeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		h.logRequest(req, http.StatusMethodNotAllowed)
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// Cleaning the rooted path resolves any ".." segments against the URL
+	// namespace, so the result can never traverse above the mirror directory.
+	urlPath := path.Clean("/" + req.URL.Path)
+	filePath := filepath.Join(h.dir, filepath.FromSlash(urlPath))
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		h.logRequest(req, http.StatusNotFound)
+		http.NotFound(w, req)
+		return
+	}
+	h.logRequest(req, http.StatusOK)
+	http.ServeFile(w, req, filePath)
+}
+
+// logRequest reports a handled request both to the debug log and, when the
+// -json option is set, to the event stream.
+func (h *mirrorServeHandler) logRequest(req *http.Request, status int) {
+	log.Printf("[DEBUG] providers mirror: serve %s %s from %s: %d", req.Method, req.URL.Path, req.RemoteAddr, status)
+	h.events.emit("request", map[string]interface{}{
+		"method": req.Method,
+		"path":   req.URL.Path,
+		"remote": req.RemoteAddr,
+		"status": status,
+	})
+}
+
+// pruneStaleMirrorPackages implements the -prune option by removing from the
+// mirror directory any package that the current configuration's version
+// constraints no longer allow, along with any per-version JSON index files
+// and directories left empty by the removals. Versions that this run just
+// mirrored deliberately, including -also-version extras, are always kept.
+//
+// This runs before the index regeneration in Run, which then rewrites the
+// remaining index files to describe only what survived the pruning.
+func (c *ProvidersMirrorCommand) pruneStaleMirrorPackages(outputDir string, reqs getproviders.Requirements, mirrored map[addrs.Provider]map[getproviders.Version]struct{}, events *mirrorEvents, report *mirrorRunReport) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	available, err := getproviders.SearchLocalDirectory(outputDir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to prune mirror directory",
+			fmt.Sprintf("Could not scan the output directory to find stale packages: %s.", err),
+		))
+		return diags
+	}
+
+	// First we decide what to remove, so that we can also notice providers
+	// that will have no packages left at all and so need their main index
+	// file removed too.
+	type stalePackage struct {
+		provider addrs.Provider
+		meta     getproviders.PackageMeta
+	}
+	var stale []stalePackage
+	keptCount := map[addrs.Provider]int{}
+	indexDirs := map[addrs.Provider]string{}
+	for provider, metas := range available {
+		constraints, required := reqs[provider]
+		acceptable := versions.MeetingConstraints(constraints)
+		for _, meta := range metas {
+			if archivePath, ok := meta.Location.(getproviders.PackageLocalArchive); ok {
+				// Only the packed layout maintains JSON index files.
+				indexDirs[provider] = filepath.Dir(string(archivePath))
+			}
+			if required {
+				_, justMirrored := mirrored[provider][meta.Version]
+				if acceptable.Has(meta.Version) || justMirrored {
+					keptCount[provider]++
+					continue
+				}
+			}
+			stale = append(stale, stalePackage{provider, meta})
+		}
+	}
+
+	// SearchLocalDirectory returns maps, so we sort the removals to make the
+	// output and the report deterministic.
+	sort.Slice(stale, func(i, j int) bool {
+		if a, b := stale[i].provider.String(), stale[j].provider.String(); a != b {
+			return a < b
+		}
+		if a, b := stale[i].meta.Version, stale[j].meta.Version; a != b {
+			return a.LessThan(b)
+		}
+		return stale[i].meta.TargetPlatform.String() < stale[j].meta.TargetPlatform.String()
+	})
+
+	for _, s := range stale {
+		provider, meta := s.provider, s.meta
+		path := meta.Location.String()
+		log.Printf("[DEBUG] providers mirror: pruning %s v%s for %s from %s", provider, meta.Version, meta.TargetPlatform, path)
+		if err := os.RemoveAll(path); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to prune mirror directory",
+				fmt.Sprintf("Could not remove the stale package for %s v%s on %s: %s.", provider.String(), meta.Version.String(), meta.TargetPlatform.String(), err),
+			))
+			continue
+		}
+		// In the packed layout the per-version JSON index lives alongside
+		// the archive. We remove it in case this was the version's last
+		// package; if other platforms of the version survive, the index
+		// regeneration will recreate it. (In the unpacked layout this file
+		// never exists, so the removal is a harmless no-op.)
+		os.Remove(filepath.Join(filepath.Dir(path), meta.Version.String()+".json"))
+		events.human(fmt.Sprintf("- Pruned %s v%s for %s", provider.ForDisplay(), meta.Version.String(), meta.TargetPlatform.String()))
+		events.emit("package_pruned", map[string]interface{}{
+			"provider": provider.String(),
+			"version":  meta.Version.String(),
+			"platform": meta.TargetPlatform.String(),
+		})
+		report.Pruned = append(report.Pruned, &mirrorPrunedPackage{
+			Provider: provider.String(),
+			Version:  meta.Version.String(),
+			Platform: meta.TargetPlatform.String(),
+		})
+	}
+
+	// A provider with no packages left would otherwise leave behind a stale
+	// main index file describing packages that no longer exist.
+	for provider, indexDir := range indexDirs {
+		if keptCount[provider] == 0 {
+			os.Remove(filepath.Join(indexDir, "index.json"))
+		}
+	}
+
+	// Finally we remove any directories that the pruning left empty, walking
+	// upward from each removed package until we reach the output directory
+	// or a directory that still has contents.
+	for _, s := range stale {
+		for dir := filepath.Dir(s.meta.Location.String()); ; dir = filepath.Dir(dir) {
+			if rel, err := filepath.Rel(outputDir, dir); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+				break
+			}
+			if os.Remove(dir) != nil {
+				break // the first non-empty directory ends the walk
+			}
+		}
+	}
+
+	return diags
+}
+
+// showMirrorSummary renders the end-of-run summary, either as human-readable
+// text or as the final object of the -json event stream.
+func (c *ProvidersMirrorCommand) showMirrorSummary(events *mirrorEvents, summary *mirrorRunSummary) {
+	fields := map[string]interface{}{
+		"providers":          summary.Providers,
+		"fully_mirrored":     summary.FullyMirrored,
+		"partially_mirrored": summary.PartiallyMirrored,
+		"failed":             summary.Failed,
+	}
+	if len(summary.Missing) > 0 {
+		fields["missing"] = summary.Missing
+	}
+	events.emit("summary", fields)
+
+	events.human("")
+	events.human(fmt.Sprintf("Summary: %d providers selected, %d fully mirrored, %d partially mirrored, %d failed.", summary.Providers, summary.FullyMirrored, summary.PartiallyMirrored, summary.Failed))
+	if len(summary.Missing) > 0 {
+		providers := make([]string, 0, len(summary.Missing))
+		for provider := range summary.Missing {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+		for _, provider := range providers {
+			events.human(fmt.Sprintf("- %s is missing packages for: %s", provider, strings.Join(summary.Missing[provider], ", ")))
+		}
+	}
+}
+
+// mirrorReportFormatVersion is the format version string recorded in reports
+// written by the -report option. This will change only if the report
+// structure changes in a way that is not backward-compatible, so consumers
+// archiving these reports can rely on the current structure as long as the
+// version string is unchanged.
+const mirrorReportFormatVersion = "1.0"
+
+// The possible "outcome" values in a mirrorPlatformReport.
+const (
+	mirrorOutcomeDownloaded   = "downloaded"
+	mirrorOutcomeKeptExisting = "kept-existing"
+	mirrorOutcomeFailed       = "failed"
+)
+
+// mirrorRunReport is the root object of the JSON report optionally written
+// at the end of a "terraform providers mirror" run, recording which version
+// was selected for each required provider and what happened for each of the
+// target platforms. It exists so that the decisions made during a mirror run
+// can be archived alongside the mirror contents, so the structure is part of
+// the command's external interface.
+type mirrorRunReport struct {
+	FormatVersion string                           `json:"format_version"`
+	Timestamp     string                           `json:"timestamp"`
+	Providers     map[string]*mirrorProviderReport `json:"providers"`
+
+	// Pruned records any packages removed by the -prune option, in the
+	// order they were removed.
+	Pruned []*mirrorPrunedPackage `json:"pruned,omitempty"`
+
+	events *mirrorEvents
+}
+
+// mirrorPrunedPackage identifies one package removed by the -prune option.
+type mirrorPrunedPackage struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+	Platform string `json:"platform"`
+}
+
+type mirrorProviderReport struct {
+	Constraints       string                           `json:"constraints"`
+	CandidateVersions []string                         `json:"candidate_versions"`
+	SelectedVersion   string                           `json:"selected_version,omitempty"`
+	Error             string                           `json:"error,omitempty"`
+	Platforms         map[string]*mirrorPlatformReport `json:"platforms,omitempty"`
+
+	// AlsoVersions records the per-platform outcomes for any extra versions
+	// mirrored via the -also-version option, keyed by version and then by
+	// platform.
+	AlsoVersions map[string]map[string]*mirrorPlatformReport `json:"also_versions,omitempty"`
+
+	provider addrs.Provider
+	events   *mirrorEvents
+}
+
+type mirrorPlatformReport struct {
+	Outcome   string `json:"outcome"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	provider addrs.Provider
+	version  getproviders.Version
+	platform getproviders.Platform
+	events   *mirrorEvents
+
+	// signedDocument and signedSignature capture the registry's signed
+	// checksums document and its detached signature when the package's
+	// authentication chain verified one, so that the documents can be
+	// archived alongside the mirrored packages.
+	signedDocument  []byte
+	signedSignature []byte
+}
+
+func newMirrorRunReport(events *mirrorEvents) *mirrorRunReport {
+	return &mirrorRunReport{
+		FormatVersion: mirrorReportFormatVersion,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Providers:     make(map[string]*mirrorProviderReport),
+		events:        events,
+	}
+}
+
+// providerReport registers and returns the report object for the given
+// provider, recording the version constraints that the run will evaluate.
+func (r *mirrorRunReport) providerReport(provider addrs.Provider, constraintsStr string) *mirrorProviderReport {
+	ret := &mirrorProviderReport{
+		Constraints: constraintsStr,
+		provider:    provider,
+		events:      r.events,
+	}
+	r.Providers[provider.String()] = ret
+	return ret
+}
+
+// platformReport registers and returns the report object for the given
+// version and target platform. Outcomes for the constraint-selected version
+// are stored directly under the provider, while extra versions requested
+// with -also-version are stored separately per version.
+func (r *mirrorProviderReport) platformReport(version getproviders.Version, platform getproviders.Platform) *mirrorPlatformReport {
+	ret := &mirrorPlatformReport{
+		provider: r.provider,
+		version:  version,
+		platform: platform,
+		events:   r.events,
+	}
+	if v := version.String(); v != r.SelectedVersion {
+		if r.AlsoVersions == nil {
+			r.AlsoVersions = make(map[string]map[string]*mirrorPlatformReport)
+		}
+		if r.AlsoVersions[v] == nil {
+			r.AlsoVersions[v] = make(map[string]*mirrorPlatformReport)
+		}
+		r.AlsoVersions[v][platform.String()] = ret
+		return ret
+	}
+	if r.Platforms == nil {
+		r.Platforms = make(map[string]*mirrorPlatformReport)
+	}
+	r.Platforms[platform.String()] = ret
+	return ret
+}
+
+// downloaded records that a new package was placed in the mirror directory,
+// along with the hash of the final package. The byte count is expected to
+// have been recorded separately, once the download completed.
+func (r *mirrorPlatformReport) downloaded(hash getproviders.Hash) {
+	r.Outcome = mirrorOutcomeDownloaded
+	r.Hash = hash.String()
+	log.Printf("[DEBUG] providers mirror: downloaded %s package for %s (%d bytes, %s)", r.provider, r.platform, r.SizeBytes, hash)
+	r.emitEvent()
+}
+
+// keptExisting records that the -keep option left an already-present package
+// in place rather than downloading it again.
+func (r *mirrorPlatformReport) keptExisting() {
+	r.Outcome = mirrorOutcomeKeptExisting
+	r.emitEvent()
+}
+
+// fail records that the package for this platform could not be mirrored.
+// The corresponding user-facing error is reported separately, as a
+// diagnostic.
+func (r *mirrorPlatformReport) fail(err error) {
+	r.Outcome = mirrorOutcomeFailed
+	r.Error = err.Error()
+	log.Printf("[DEBUG] providers mirror: failed to mirror %s for %s: %s", r.provider, r.platform, err)
+	r.emitEvent()
+}
+
+// emitEvent reports the outcome for this package to the -json event stream,
+// if enabled.
+func (r *mirrorPlatformReport) emitEvent() {
+	fields := map[string]interface{}{
+		"provider": r.provider.String(),
+		"version":  r.version.String(),
+		"platform": r.platform.String(),
+		"outcome":  r.Outcome,
+	}
+	if r.Hash != "" {
+		fields["hash"] = r.Hash
+	}
+	if r.SizeBytes != 0 {
+		fields["size_bytes"] = r.SizeBytes
+	}
+	if r.Error != "" {
+		fields["error"] = r.Error
+	}
+	r.events.emit("package", fields)
+}
+
+// mirrorRunSummary is the overall outcome of a mirror run, distinguishing
+// providers whose packages were all mirrored successfully from those where
+// only some of the requested packages could be mirrored and those where
+// nothing could be mirrored at all. It appears both at the end of the
+// human-readable output and as the final object of the -json event stream.
+type mirrorRunSummary struct {
+	Providers         int `json:"providers"`
+	FullyMirrored     int `json:"fully_mirrored"`
+	PartiallyMirrored int `json:"partially_mirrored"`
+	Failed            int `json:"failed"`
+
+	// Missing lists, for each partially-mirrored provider, the packages
+	// that could not be mirrored, as "platform (vVERSION)" strings.
+	Missing map[string][]string `json:"missing,omitempty"`
+}
+
+// summary derives the overall run outcome from the per-package outcomes
+// recorded in the report.
+func (r *mirrorRunReport) summary() *mirrorRunSummary {
+	ret := &mirrorRunSummary{}
+	for providerStr, providerReport := range r.Providers {
+		ret.Providers++
+		if providerReport.Error != "" {
+			// We couldn't even select a version, so no packages were mirrored.
+			ret.Failed++
+			continue
+		}
+		var missing []string
+		var succeeded int
+		collect := func(version string, platforms map[string]*mirrorPlatformReport) {
+			for platform, platformReport := range platforms {
+				if platformReport.Outcome == mirrorOutcomeFailed {
+					missing = append(missing, fmt.Sprintf("%s (v%s)", platform, version))
+				} else {
+					succeeded++
+				}
+			}
+		}
+		collect(providerReport.SelectedVersion, providerReport.Platforms)
+		for version, platforms := range providerReport.AlsoVersions {
+			collect(version, platforms)
+		}
+		switch {
+		case len(missing) == 0:
+			ret.FullyMirrored++
+		case succeeded == 0:
+			ret.Failed++
+		default:
+			ret.PartiallyMirrored++
+			sort.Strings(missing)
+			if ret.Missing == nil {
+				ret.Missing = make(map[string][]string)
+			}
+			ret.Missing[providerStr] = missing
+		}
+	}
+	return ret
+}
+
+// mirrorExclusionReason checks whether any of the given source's installation
+// methods is allowed to provide the given provider, returning a non-empty
+// human-readable reason when none of them is, so that the provider can be
+// skipped rather than mirrored against the configured policy.
+func mirrorExclusionReason(source getproviders.Source, provider addrs.Provider) string {
+	multi, ok := source.(getproviders.MultiSource)
+	if !ok {
+		// A source of any other type has no include/exclude rules, so it
+		// cannot exclude anything.
+		return ""
+	}
+	for _, selector := range multi {
+		if selector.CanHandleProvider(provider) {
+			return ""
+		}
+	}
+	// No method can provide this provider, so we'll find the pattern
+	// responsible in order to name it in the reason.
+	for _, selector := range multi {
+		for _, pattern := range selector.Exclude {
+			if (getproviders.MultiSourceMatchingPatterns{pattern}).MatchesProvider(provider) {
+				return fmt.Sprintf("it is excluded by the provider_installation pattern %q", pattern.ForDisplay())
+			}
+		}
+	}
+	return "it is not included by any provider_installation method"
+}
+
+// mirrorEvents adapts the progress output of the mirror command so that the
+// same run logic can produce either the usual human-oriented text or, when
+// the -json option is set, a stream of JSON objects with one object per
+// line, ending with a "summary" object.
+//
+// The download workers started for -parallelism report their progress and
+// outcomes through this object concurrently, so it serializes all of its
+// writes with a mutex; each message or event object is emitted atomically,
+// though messages from concurrent downloads may interleave with one another.
+type mirrorEvents struct {
+	ui       cli.Ui
+	jsonMode bool
+	mu       sync.Mutex
+}
+
+// human emits the given message only when producing human-oriented output.
+func (e *mirrorEvents) human(message string) {
+	if !e.jsonMode {
+		e.mu.Lock()
+		e.ui.Output(message)
+		e.mu.Unlock()
+	}
+}
+
+// emit writes an object of the given type to the -json event stream, and
+// does nothing at all when producing human-oriented output.
+func (e *mirrorEvents) emit(eventType string, fields map[string]interface{}) {
+	if !e.jsonMode {
+		return
+	}
+	obj := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		obj[k] = v
+	}
+	obj["type"] = eventType
+	j, err := json.Marshal(obj)
+	if err != nil {
+		// Should never happen because the input here is entirely under
+		// our control.
+		panic(fmt.Sprintf("failed to encode mirror event: %s", err))
+	}
+	e.mu.Lock()
+	e.ui.Output(string(j))
+	e.mu.Unlock()
+}
+
+// mirrorProvidersIndexFilename is the name of the top-level index file
+// maintained at the root of a mirror directory.
+const mirrorProvidersIndexFilename = "providers-index.json"
+
+// mirrorProvidersIndexFormatVersion is the format version string recorded
+// in the top-level providers index. As with the run report, this changes
+// only for backward-incompatible changes to the structure.
+const mirrorProvidersIndexFormatVersion = "1.0"
+
+// mirrorProvidersIndex is the document written to providers-index.json at
+// the root of a mirror directory, summarizing everything the mirror
+// contains.
+//
+// This index is informational only: it is not part of the provider network
+// mirror protocol and Terraform itself never reads it, so mirror clients
+// will ignore it. It exists so that tooling can enumerate the contents of
+// a mirror (for example, one served over plain HTTP) without walking its
+// directory structure.
+type mirrorProvidersIndex struct {
+	FormatVersion string                                `json:"format_version"`
+	Providers     map[string]*mirrorProvidersIndexEntry `json:"providers"`
+}
+
+type mirrorProvidersIndexEntry struct {
+	Versions       map[string]*mirrorProvidersIndexVersion `json:"versions"`
+	TotalSizeBytes int64                                   `json:"total_size_bytes"`
+
+	// LastRefreshed is an RFC 3339 timestamp recording when this provider's
+	// entry last changed. Entries whose contents are unchanged from the
+	// previous index keep their previous timestamp.
+	LastRefreshed string `json:"last_refreshed"`
+}
+
+type mirrorProvidersIndexVersion struct {
+	Platforms []string `json:"platforms"`
+	SizeBytes int64    `json:"size_bytes"`
+}
+
+// updateMirrorProvidersIndex writes the top-level providers index for the
+// mirror directory, describing the given set of packages found by scanning
+// the directory.
+//
+// Because the index is always rebuilt from a full scan, it reflects any
+// packages added by earlier runs and any removals made by other means. The
+// per-provider "last_refreshed" timestamps are merged from the previous
+// index where a provider's entry is unchanged, so they record when each
+// entry last actually changed rather than when the command last ran.
+func updateMirrorProvidersIndex(outputDir string, available map[addrs.Provider]getproviders.PackageMetaList) error {
+	indexPath := filepath.Join(outputDir, mirrorProvidersIndexFilename)
+
+	// If there's an existing index then we'll use it to preserve the
+	// last_refreshed timestamps of unchanged entries. An unreadable or
+	// invalid existing index is treated the same as no index at all,
+	// since we're about to replace it anyway.
+	var prev mirrorProvidersIndex
+	if prevSrc, err := ioutil.ReadFile(indexPath); err == nil {
+		if err := json.Unmarshal(prevSrc, &prev); err != nil {
+			prev = mirrorProvidersIndex{}
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	index := mirrorProvidersIndex{
+		FormatVersion: mirrorProvidersIndexFormatVersion,
+		Providers:     make(map[string]*mirrorProvidersIndexEntry, len(available)),
+	}
+	for provider, metas := range available {
+		entry := &mirrorProvidersIndexEntry{
+			Versions:      make(map[string]*mirrorProvidersIndexVersion),
+			LastRefreshed: now,
+		}
+		for _, meta := range metas {
+			version := entry.Versions[meta.Version.String()]
+			if version == nil {
+				version = &mirrorProvidersIndexVersion{}
+				entry.Versions[meta.Version.String()] = version
+			}
+			size := mirrorPackageSizeBytes(meta.Location)
+			version.Platforms = append(version.Platforms, meta.TargetPlatform.String())
+			version.SizeBytes += size
+			entry.TotalSizeBytes += size
+		}
+		for _, version := range entry.Versions {
+			sort.Strings(version.Platforms)
+		}
+		if prevEntry := prev.Providers[provider.String()]; prevEntry != nil && entry.sameContents(prevEntry) {
+			entry.LastRefreshed = prevEntry.LastRefreshed
+		}
+		index.Providers[provider.String()] = entry
+	}
+
+	indexJSON, err := json.MarshalIndent(&index, "", "  ")
+	if err != nil {
+		// Should never happen because the input here is entirely under
+		// our control.
+		panic(fmt.Sprintf("failed to encode providers index: %s", err))
+	}
+	return ioutil.WriteFile(indexPath, append(indexJSON, '\n'), 0644)
+}
+
+// sameContents returns true if the receiver describes the same versions,
+// platforms, and sizes as the other entry, disregarding the last_refreshed
+// timestamps.
+func (e *mirrorProvidersIndexEntry) sameContents(other *mirrorProvidersIndexEntry) bool {
+	if e.TotalSizeBytes != other.TotalSizeBytes || len(e.Versions) != len(other.Versions) {
+		return false
+	}
+	for versionStr, version := range e.Versions {
+		otherVersion := other.Versions[versionStr]
+		if otherVersion == nil || version.SizeBytes != otherVersion.SizeBytes || len(version.Platforms) != len(otherVersion.Platforms) {
+			return false
+		}
+		for i, platform := range version.Platforms {
+			if otherVersion.Platforms[i] != platform {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// mirrorPackageSizeBytes makes a best effort to determine the size on disk
+// of the given package, returning zero if it cannot be determined.
+func mirrorPackageSizeBytes(location getproviders.PackageLocation) int64 {
+	switch location := location.(type) {
+	case getproviders.PackageLocalArchive:
+		if info, err := os.Stat(string(location)); err == nil {
+			return info.Size()
+		}
+	case getproviders.PackageLocalDir:
+		var total int64
+		filepath.Walk(string(location), func(_ string, info os.FileInfo, err error) error {
+			if err == nil && info.Mode().IsRegular() {
+				total += info.Size()
+			}
+			return nil
+		})
+		return total
+	}
 	return 0
 }
 
@@ -341,8 +1869,71 @@ Usage: terraform [global options] providers mirror [options] <target-dir>
   a network mirror. Those index files will be ignored if the directory is
   used instead as a local filesystem mirror.
 
+  Packages are obtained through the same installation methods that
+  "terraform init" would use, honoring any include and exclude rules from
+  the provider_installation block in the CLI configuration. Providers that
+  no installation method is allowed to provide, and providers with a
+  dev_overrides entry, are skipped with a warning rather than mirrored.
+
+  Every run ends with a summary of how many providers were fully mirrored,
+  partially mirrored, or failed, and the exit status reflects that summary:
+  0 if every requested package was mirrored, 2 if some packages were
+  mirrored but others were not, and 1 if nothing could be mirrored or if
+  the run failed outright for some other reason.
+
 Options:
 
+  -also-version=source=version
+                     Mirror the given exact version of a provider in
+                     addition to whatever version the configuration's
+                     version constraints select, using the same set of
+                     target platforms. For example,
+                     -also-version=hashicorp/aws=5.41.0 includes v5.41.0
+                     of hashicorp/aws alongside the selected version. Use
+                     this flag multiple times to include several extra
+                     versions. Requesting a version that is already
+                     selected has no additional effect.
+
+  -layout=packed     Choose which directory layout to produce: "packed"
+                     (the default) stores each provider package as the
+                     original release .zip archive along with the JSON
+                     index files needed by a network mirror, while
+                     "unpacked" extracts each package into a directory
+                     per version and platform, as expected by the
+                     filesystem mirror installation method and the
+                     -plugin-dir option to "terraform init". A single
+                     directory can use only one of the two layouts.
+
+  -ignore-cli-config Ignore any provider_installation rules in the CLI
+                     configuration and obtain every provider directly from
+                     its origin registry, as earlier versions of this
+                     command always did.
+
+  -json              Produce output as a stream of JSON objects, one per
+                     line, describing the outcome for each package and
+                     ending with a "summary" object. Errors are still
+                     reported as ordinary diagnostics on stderr.
+
+  -keep              Skip downloading any package whose target file or
+                     directory is already present in the mirror, rather
+                     than downloading and replacing it.
+
+  -lockfile-only     Mirror exactly the provider versions recorded in the
+                     dependency lock file (.terraform.lock.hcl), without
+                     evaluating the configuration at all, and verify each
+                     downloaded package against the checksums the lock file
+                     records. This is convenient for a minimal CI job that
+                     refreshes a mirror from only the lock file. Cannot be
+                     combined with -also-version, -prune, or -verify-only.
+
+  -parallelism=n     Limit the number of packages to download concurrently.
+                     The default is 1, which downloads one package at a
+                     time as earlier versions of this command always did.
+                     Raising the limit can considerably speed up a run that
+                     mirrors many providers or many target platforms. The
+                     JSON index files are still written only after all of
+                     the downloads have completed.
+
   -platform=os_arch  Choose which target platform to build a mirror for.
                      By default Terraform will obtain plugin packages
                      suitable for the platform where you run this command.
@@ -354,5 +1945,58 @@ Options:
                      Linux operating system running on an AMD64 or x86_64
                      CPU. Each provider is available only for a limited
                      set of target platforms.
+
+  -prune             Remove any package in the mirror directory whose
+                     version the current configuration's constraints no
+                     longer allow, and rewrite the JSON index files to
+                     match. Versions mirrored by this same run, including
+                     any requested with -also-version, are always kept.
+                     Use this to stop old provider releases from
+                     accumulating in a long-lived mirror.
+
+  -report=path.json  Write a JSON report of this run to the given file,
+                     recording for each provider the version constraints
+                     that were evaluated, the candidate versions that were
+                     considered, the version that was selected, and the
+                     outcome for each target platform. The report can be
+                     archived alongside the mirror contents as a record of
+                     how they were produced.
+
+  -serve=addr        Instead of mirroring anything, serve the existing
+                     contents of the target directory on the given listen
+                     address (such as ":8080" or "127.0.0.1:8080") using
+                     the provider network mirror protocol, until
+                     interrupted. The directory must use the "packed"
+                     layout, whose JSON index files are exactly the
+                     documents the protocol requires. Note that Terraform
+                     only uses network mirrors over HTTPS, so unless TLS
+                     is configured with the options below the server is
+                     only useful behind a TLS-terminating reverse proxy.
+
+  -tls-cert-file=path
+                     Serve HTTPS using the certificate in the given PEM
+                     file, which may also contain any required intermediate
+                     certificates. Valid only with -serve, and requires
+                     -tls-key-file.
+
+  -tls-key-file=path Serve HTTPS using the private key in the given PEM
+                     file. Valid only with -serve, and requires
+                     -tls-cert-file.
+
+  -source=dir-or-url Obtain every package from the given existing mirror
+                     instead of through the usual installation methods. The
+                     source may be either a local directory containing a
+                     filesystem mirror in the "packed" layout, or an https:
+                     URL of a network mirror. This is useful for copying a
+                     mirror forward onto another system, such as into an
+                     air-gapped network, without re-downloading anything
+                     from the origin registries.
+
+  -verify-only       Re-validate the packages already present in the mirror
+                     directory against the hashes recorded in the JSON index
+                     files and any archived SHA256SUMS documents, without
+                     downloading anything, and then exit. The exit status is
+                     0 when every package verified successfully and 1 when
+                     any package failed to verify.
 `
 }
@@ -1,6 +1,15 @@
 package command
 
 import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -11,11 +20,510 @@ import (
 func TestProvidersMirror(t *testing.T) {
 	// noop example
 	t.Run("noop", func(t *testing.T) {
-		c := &ProvidersMirrorCommand{}
-		code := c.Run([]string{"."})
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{t.TempDir()})
 		if code != 0 {
 			t.Fatalf("wrong exit code. expected 0, got %d", code)
 		}
+
+		// Even a run with nothing to mirror ends with a summary.
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "Summary: 0 providers selected") {
+			t.Fatalf("missing summary from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("invalid parallelism error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-parallelism=0", t.TempDir()})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: Invalid parallelism") {
+			t.Fatalf("missing parallelism error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("from filesystem mirror source", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		config := `
+terraform {
+  required_providers {
+    foo = {
+      source  = "example.com/test/foo"
+      version = "1.0.0"
+    }
+  }
+}
+`
+		if err := ioutil.WriteFile(filepath.Join(td, "main.tf"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// A minimal source mirror in the "packed" layout, containing just
+		// the one package the configuration requires. The archive must be
+		// a valid zip file so that the index update can hash its contents,
+		// but what's inside it doesn't matter for this test.
+		srcDir := t.TempDir()
+		pkgDir := filepath.Join(srcDir, "example.com", "test", "foo")
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		var archive bytes.Buffer
+		zw := zip.NewWriter(&archive)
+		f, err := zw.Create("terraform-provider-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte("not a real executable")); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(pkgDir, "terraform-provider-foo_1.0.0_linux_amd64.zip"), archive.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		targetDir := t.TempDir()
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-source=" + srcDir, "-platform=linux_amd64", targetDir})
+		if code != 0 {
+			t.Fatalf("wrong exit code. expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+		}
+
+		mirrored := filepath.Join(targetDir, "example.com", "test", "foo", "terraform-provider-foo_1.0.0_linux_amd64.zip")
+		if _, err := os.Stat(mirrored); err != nil {
+			t.Errorf("expected mirrored package at %s: %s", mirrored, err)
+		}
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "1 fully mirrored") {
+			t.Errorf("missing summary from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("prune stale versions", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		config := `
+terraform {
+  required_providers {
+    foo = {
+      source  = "example.com/test/foo"
+      version = "1.0.0"
+    }
+  }
+}
+`
+		if err := ioutil.WriteFile(filepath.Join(td, "main.tf"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var archive bytes.Buffer
+		zw := zip.NewWriter(&archive)
+		f, err := zw.Create("terraform-provider-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte("not a real executable")); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		srcDir := t.TempDir()
+		srcPkgDir := filepath.Join(srcDir, "example.com", "test", "foo")
+		if err := os.MkdirAll(srcPkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(srcPkgDir, "terraform-provider-foo_1.0.0_linux_amd64.zip"), archive.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// The target directory starts off with a stale version that the
+		// configuration's constraints no longer allow, as if left behind
+		// by an earlier run against an older configuration.
+		targetDir := t.TempDir()
+		targetPkgDir := filepath.Join(targetDir, "example.com", "test", "foo")
+		if err := os.MkdirAll(targetPkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		stalePkg := filepath.Join(targetPkgDir, "terraform-provider-foo_0.9.0_linux_amd64.zip")
+		if err := ioutil.WriteFile(stalePkg, archive.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		staleIndex := filepath.Join(targetPkgDir, "0.9.0.json")
+		if err := ioutil.WriteFile(staleIndex, []byte(`{"archives":{}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-source=" + srcDir, "-platform=linux_amd64", "-prune", targetDir})
+		if code != 0 {
+			t.Fatalf("wrong exit code. expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+		}
+
+		if _, err := os.Stat(stalePkg); !os.IsNotExist(err) {
+			t.Errorf("stale package still present at %s", stalePkg)
+		}
+		if _, err := os.Stat(staleIndex); !os.IsNotExist(err) {
+			t.Errorf("stale version index still present at %s", staleIndex)
+		}
+		if _, err := os.Stat(filepath.Join(targetPkgDir, "terraform-provider-foo_1.0.0_linux_amd64.zip")); err != nil {
+			t.Errorf("expected current package to remain: %s", err)
+		}
+		mainIndex, err := ioutil.ReadFile(filepath.Join(targetPkgDir, "index.json"))
+		if err != nil {
+			t.Fatalf("failed to read main index: %s", err)
+		}
+		if strings.Contains(string(mainIndex), "0.9.0") {
+			t.Errorf("main index still mentions the pruned version:\n%s", mainIndex)
+		}
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "Pruned example.com/test/foo v0.9.0 for linux_amd64") {
+			t.Errorf("missing prune message from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("lockfile only", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		// No configuration at all: -lockfile-only must work from just the
+		// dependency lock file.
+		var archive bytes.Buffer
+		zw := zip.NewWriter(&archive)
+		f, err := zw.Create("terraform-provider-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte("not a real executable")); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		srcDir := t.TempDir()
+		srcPkgDir := filepath.Join(srcDir, "example.com", "test", "foo")
+		if err := os.MkdirAll(srcPkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(srcPkgDir, "terraform-provider-foo_1.0.0_linux_amd64.zip"), archive.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		zipHash := sha256.Sum256(archive.Bytes())
+		lockFile := fmt.Sprintf(`provider "example.com/test/foo" {
+  version = "1.0.0"
+  hashes = [
+    "zh:%x",
+  ]
+}
+`, zipHash)
+		if err := ioutil.WriteFile(filepath.Join(td, ".terraform.lock.hcl"), []byte(lockFile), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		targetDir := t.TempDir()
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-lockfile-only", "-source=" + srcDir, "-platform=linux_amd64", targetDir})
+		if code != 0 {
+			t.Fatalf("wrong exit code. expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+		}
+
+		mirrored := filepath.Join(targetDir, "example.com", "test", "foo", "terraform-provider-foo_1.0.0_linux_amd64.zip")
+		if _, err := os.Stat(mirrored); err != nil {
+			t.Errorf("expected mirrored package at %s: %s", mirrored, err)
+		}
+		got := ui.OutputWriter.String()
+		if !strings.Contains(got, "Selected v1.0.0 recorded in the dependency lock file") {
+			t.Errorf("missing lock file selection message from output, got:\n%s\n", got)
+		}
+		if !strings.Contains(got, "matches the dependency lock file") {
+			t.Errorf("missing lock file verification message from output, got:\n%s\n", got)
+		}
+
+		// A package that doesn't match the recorded checksums must be
+		// rejected rather than mirrored.
+		badLockFile := strings.Replace(lockFile, fmt.Sprintf("%x", zipHash), strings.Repeat("0", 64), 1)
+		if err := ioutil.WriteFile(filepath.Join(td, ".terraform.lock.hcl"), []byte(badLockFile), 0644); err != nil {
+			t.Fatal(err)
+		}
+		badTargetDir := t.TempDir()
+		ui = new(cli.MockUi)
+		c = &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code = c.Run([]string{"-lockfile-only", "-source=" + srcDir, "-platform=linux_amd64", badTargetDir})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, "does not match any of the checksums") {
+			t.Errorf("missing checksum mismatch error from output, got:\n%s\n", got)
+		}
+		if _, err := os.Stat(filepath.Join(badTargetDir, "example.com", "test", "foo", "terraform-provider-foo_1.0.0_linux_amd64.zip")); !os.IsNotExist(err) {
+			t.Errorf("rejected package was placed into the mirror anyway")
+		}
+	})
+
+	t.Run("lockfile only option errors", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-lockfile-only", "-prune", t.TempDir()})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, "Error: Invalid lockfile-only options") {
+			t.Fatalf("missing option conflict error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("verify only", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		config := `
+terraform {
+  required_providers {
+    foo = {
+      source  = "example.com/test/foo"
+      version = "1.0.0"
+    }
+  }
+}
+`
+		if err := ioutil.WriteFile(filepath.Join(td, "main.tf"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		var archive bytes.Buffer
+		zw := zip.NewWriter(&archive)
+		f, err := zw.Create("terraform-provider-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte("not a real executable")); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		srcDir := t.TempDir()
+		srcPkgDir := filepath.Join(srcDir, "example.com", "test", "foo")
+		if err := os.MkdirAll(srcPkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(srcPkgDir, "terraform-provider-foo_1.0.0_linux_amd64.zip"), archive.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// First populate the mirror, so that the index files record hashes
+		// for the verification to check against.
+		targetDir := t.TempDir()
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		if code := c.Run([]string{"-source=" + srcDir, "-platform=linux_amd64", targetDir}); code != 0 {
+			t.Fatalf("mirror run failed with exit code %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+		}
+
+		// An untouched mirror verifies cleanly.
+		ui = new(cli.MockUi)
+		c = &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		if code := c.Run([]string{"-verify-only", targetDir}); code != 0 {
+			t.Fatalf("wrong exit code. expected 0, got %d\nstdout:\n%s\nstderr:\n%s", code, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "1 packages verified, 0 failed") {
+			t.Errorf("missing verification summary from output, got:\n%s\n", got)
+		}
+
+		// Replacing a package with a different (but still valid) archive
+		// must make verification fail.
+		var tampered bytes.Buffer
+		zw = zip.NewWriter(&tampered)
+		f, err = zw.Create("terraform-provider-foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte("a different executable")); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		mirrored := filepath.Join(targetDir, "example.com", "test", "foo", "terraform-provider-foo_1.0.0_linux_amd64.zip")
+		if err := ioutil.WriteFile(mirrored, tampered.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		ui = new(cli.MockUi)
+		c = &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		if code := c.Run([]string{"-verify-only", targetDir}); code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d\nstdout:\n%s", code, ui.OutputWriter.String())
+		}
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "does not match the hashes recorded in the JSON index") {
+			t.Errorf("missing verification failure from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("serve handler", func(t *testing.T) {
+		// The -serve option itself blocks until interrupted, so we exercise
+		// its request handler directly against a hand-made mirror directory.
+		baseDir := t.TempDir()
+		dir := filepath.Join(baseDir, "mirror")
+		pkgDir := filepath.Join(dir, "example.com", "test", "foo")
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(pkgDir, "index.json"), []byte(`{"versions":{"1.0.0":{}}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// A file next to the mirror directory must never be reachable.
+		if err := ioutil.WriteFile(filepath.Join(baseDir, "secret"), []byte(`not served`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		handler := &mirrorServeHandler{dir: dir, events: &mirrorEvents{ui: new(cli.MockUi)}}
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/example.com/test/foo/index.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 200 {
+			t.Fatalf("wrong status %d for index.json; want 200", resp.StatusCode)
+		}
+		if got := resp.Header.Get("Content-Type"); !strings.Contains(got, "application/json") {
+			t.Errorf("wrong Content-Type %q for index.json", got)
+		}
+		if !strings.Contains(string(body), "1.0.0") {
+			t.Errorf("wrong index.json body:\n%s", body)
+		}
+
+		// Directories never produce listings.
+		resp, err = http.Get(server.URL + "/example.com/test/foo/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 404 {
+			t.Errorf("wrong status %d for directory request; want 404", resp.StatusCode)
+		}
+
+		// Traversal outside the mirror directory is impossible, because the
+		// path is resolved within the URL namespace first.
+		req, err := http.NewRequest("GET", server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.URL.Path = "/../secret"
+		req.URL.RawPath = "/../secret"
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if strings.Contains(string(body), "not served") {
+			t.Fatalf("traversal request escaped the mirror directory")
+		}
+
+		// Only GET and HEAD are allowed.
+		resp, err = http.Post(server.URL+"/example.com/test/foo/index.json", "text/plain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 405 {
+			t.Errorf("wrong status %d for POST request; want 405", resp.StatusCode)
+		}
+	})
+
+	t.Run("serve option errors", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-serve=:0", "-verify-only", t.TempDir()})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, "Error: Invalid serve options") {
+			t.Fatalf("missing serve options error from output, got:\n%s\n", got)
+		}
+
+		ui = new(cli.MockUi)
+		c = &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code = c.Run([]string{"-serve=:0", "-tls-cert-file=cert.pem", t.TempDir()})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, "Error: Invalid TLS options") {
+			t.Fatalf("missing TLS options error from output, got:\n%s\n", got)
+		}
+
+		ui = new(cli.MockUi)
+		c = &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code = c.Run([]string{"-tls-cert-file=cert.pem", "-tls-key-file=key.pem", t.TempDir()})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, "used only with") {
+			t.Fatalf("missing TLS options error from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("invalid source error", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		c := &ProvidersMirrorCommand{
+			Meta: Meta{Ui: ui},
+		}
+		code := c.Run([]string{"-source=http://example.com/mirror", t.TempDir()})
+		if code != 1 {
+			t.Fatalf("wrong exit code. expected 1, got %d", code)
+		}
+
+		got := ui.ErrorWriter.String()
+		if !strings.Contains(got, "Error: Invalid mirror source") {
+			t.Fatalf("missing source error from output, got:\n%s\n", got)
+		}
 	})
 
 	t.Run("missing arg error", func(t *testing.T) {
@@ -3,9 +3,20 @@ package command
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/jsonprovider"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/depsfile"
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/providercache"
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/terraform"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
@@ -27,7 +38,11 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("providers schema")
 	var jsonOutput bool
+	var providerStr, resourceTypeStr, outDir string
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.StringVar(&providerStr, "provider", "", "show only the schema of this provider")
+	cmdFlags.StringVar(&resourceTypeStr, "resource-type", "", "show only the schema of this resource type or data source")
+	cmdFlags.StringVar(&outDir, "out-dir", "", "write one JSON file per resource type to this directory")
 
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
@@ -42,6 +57,55 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
+	var diags tfdiags.Diagnostics
+
+	// If the user asked for a single provider then we'll parse the address
+	// (and optional "@version" suffix) before doing anything expensive.
+	var providerAddr addrs.Provider
+	var providerVersion getproviders.VersionConstraints
+	if providerStr != "" {
+		sourceStr := providerStr
+		if at := strings.Index(providerStr, "@"); at != -1 {
+			sourceStr = providerStr[:at]
+			versionStr := providerStr[at+1:]
+			var err error
+			providerVersion, err = getproviders.ParseVersionConstraints(versionStr)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid provider version",
+					fmt.Sprintf("The version %q given in the -provider option is not valid: %s.", versionStr, err),
+				))
+				c.showDiagnostics(diags)
+				return 1
+			}
+		}
+		var moreDiags tfdiags.Diagnostics
+		providerAddr, moreDiags = addrs.ParseProviderSourceString(sourceStr)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		// If we're in a directory with no configuration at all then we can't
+		// rely on "terraform init" having installed the provider, so we'll
+		// fetch and launch just the one requested provider directly.
+		if empty, err := configs.IsEmptyDir("."); err == nil && empty {
+			return c.showStandaloneProviderSchema(providerAddr, providerVersion, resourceTypeStr, outDir)
+		}
+
+		if len(providerVersion) != 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid provider version",
+				"The @version suffix in the -provider option can be used only in a directory with no Terraform configuration, because in a configuration directory the provider version is decided by the configuration and the dependency lock file.",
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
 	// Check for user-supplied plugin path
 	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
@@ -49,8 +113,6 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
-	var diags tfdiags.Diagnostics
-
 	// Load the backend
 	b, backendDiags := c.Backend(nil)
 	diags = diags.Append(backendDiags)
@@ -78,7 +140,7 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 	}
 
 	// Build the operation
-	opReq := c.Operation(b)
+	opReq := c.Operation(b, arguments.ViewHuman)
 	opReq.ConfigDir = cwd
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	opReq.AllowUnsetVariables = true
@@ -103,6 +165,56 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 		return 1
 	}
 
+	if providerStr != "" {
+		schema, ok := schemas.Providers[providerAddr]
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Provider schema not available",
+				fmt.Sprintf("Provider %s is not required by the current configuration or state, so its schema is not available here. To inspect the schema of an arbitrary provider, run this command in a directory with no Terraform configuration.", providerAddr.ForDisplay()),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		schemas = &terraform.Schemas{
+			Providers: map[addrs.Provider]*providers.Schemas{
+				providerAddr: schema,
+			},
+		}
+	}
+
+	return c.outputSchemas(schemas, resourceTypeStr, outDir)
+}
+
+// outputSchemas applies the -resource-type filter to the given schemas and
+// then either prints them as a single JSON document or, with -out-dir,
+// writes one JSON file per resource type under the given directory.
+func (c *ProvidersSchemaCommand) outputSchemas(schemas *terraform.Schemas, resourceType, outDir string) int {
+	var diags tfdiags.Diagnostics
+
+	if resourceType != "" {
+		schemas = filterSchemasByResourceType(schemas, resourceType)
+		if schemas == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Resource type not found",
+				fmt.Sprintf("None of the selected providers define a resource type or data source named %q.", resourceType),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	if outDir != "" {
+		count, err := writeProviderSchemaFiles(outDir, schemas)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write schema files: %s", err))
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("Wrote %d schema files to %s.", count, outDir))
+		return 0
+	}
+
 	jsonSchemas, err := jsonprovider.Marshal(schemas)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to marshal provider schemas to json: %s", err))
@@ -113,9 +225,213 @@ func (c *ProvidersSchemaCommand) Run(args []string) int {
 	return 0
 }
 
+// filterSchemasByResourceType reduces the given schemas to only the managed
+// resource types and data sources with the given name, keeping only the
+// providers that define one. It returns nil if no provider does.
+func filterSchemasByResourceType(schemas *terraform.Schemas, name string) *terraform.Schemas {
+	filtered := &terraform.Schemas{
+		Providers: make(map[addrs.Provider]*providers.Schemas),
+	}
+	for addr, s := range schemas.Providers {
+		if s == nil {
+			continue
+		}
+		ret := &providers.Schemas{}
+		found := false
+		if block, ok := s.ResourceTypes[name]; ok {
+			ret.ResourceTypes = map[string]*configschema.Block{name: block}
+			if version, ok := s.ResourceTypeSchemaVersions[name]; ok {
+				ret.ResourceTypeSchemaVersions = map[string]uint64{name: version}
+			}
+			found = true
+		}
+		if block, ok := s.DataSources[name]; ok {
+			ret.DataSources = map[string]*configschema.Block{name: block}
+			found = true
+		}
+		if found {
+			filtered.Providers[addr] = ret
+		}
+	}
+	if len(filtered.Providers) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// writeProviderSchemaFiles writes the given schemas under the given
+// directory as one JSON file per resource type, in a directory per provider
+// mirroring the provider's source address, and returns how many files it
+// wrote. Each provider directory contains a provider.json for the
+// provider's own configuration schema, plus resource_types and data_sources
+// subdirectories holding one file per schema.
+func writeProviderSchemaFiles(baseDir string, schemas *terraform.Schemas) (int, error) {
+	count := 0
+	for addr, ps := range schemas.Providers {
+		if ps == nil {
+			continue
+		}
+		providerDir := filepath.Join(baseDir, addr.Hostname.ForDisplay(), addr.Namespace, addr.Type)
+		if err := os.MkdirAll(providerDir, 0755); err != nil {
+			return count, err
+		}
+
+		writeOne := func(path, mode, name string, block *configschema.Block, version uint64) error {
+			src, err := jsonprovider.MarshalSingleSchema(addr.String(), mode, name, block, version)
+			if err != nil {
+				return fmt.Errorf("failed to marshal schema for %s: %s", path, err)
+			}
+			return os.WriteFile(path, src, 0644)
+		}
+
+		if ps.Provider != nil {
+			if err := writeOne(filepath.Join(providerDir, "provider.json"), "provider", "", ps.Provider, 0); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(ps.ResourceTypes) > 0 {
+			dir := filepath.Join(providerDir, "resource_types")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return count, err
+			}
+			for name, block := range ps.ResourceTypes {
+				if err := writeOne(filepath.Join(dir, name+".json"), "managed", name, block, ps.ResourceTypeSchemaVersions[name]); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+		if len(ps.DataSources) > 0 {
+			dir := filepath.Join(providerDir, "data_sources")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return count, err
+			}
+			for name, block := range ps.DataSources {
+				if err := writeOne(filepath.Join(dir, name+".json"), "data", name, block, 0); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// showStandaloneProviderSchema installs the given provider into a temporary
+// directory, launches it, and prints its schema in the same JSON format we'd
+// use for providers belonging to a configuration.
+//
+// This doesn't interact with the working directory at all, and so it's used
+// only when the current directory contains no configuration and thus there's
+// no "terraform init" result to consult.
+func (c *ProvidersSchemaCommand) showStandaloneProviderSchema(providerAddr addrs.Provider, constraints getproviders.VersionConstraints, resourceType, outDir string) int {
+	var diags tfdiags.Diagnostics
+
+	ctx, cancel := c.InterruptibleContext()
+	defer cancel()
+
+	tempDir, err := os.MkdirTemp("", "terraform-providers-schema")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to create temporary directory: %s", err))
+		return 1
+	}
+	defer os.RemoveAll(tempDir)
+
+	// We install just the one requested provider into our temporary
+	// directory, using whatever installation methods the CLI configuration
+	// selects, and then run it up directly from there.
+	source := c.providerInstallSource()
+	targetDir := providercache.NewDir(tempDir)
+	installer := providercache.NewInstaller(targetDir, source)
+	reqs := getproviders.Requirements{
+		providerAddr: constraints,
+	}
+	_, err = installer.EnsureProviderVersions(ctx, depsfile.NewLocks(), reqs, providercache.InstallNewProvidersOnly)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to install provider",
+			fmt.Sprintf("Could not install provider %s: %s.", providerAddr.ForDisplay(), err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	cached := targetDir.ProviderLatestVersion(providerAddr)
+	if cached == nil {
+		// Shouldn't get here if EnsureProviderVersions succeeded, but we'll
+		// be cautious since we're about to execute the package.
+		c.Ui.Error(fmt.Sprintf("Failed to install provider %s: installer did not produce a usable package.", providerAddr.ForDisplay()))
+		return 1
+	}
+
+	factory := providerFactory(cached, c.PluginGRPCClient, "")
+	provider, err := factory()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to start provider plugin",
+			fmt.Sprintf("Could not launch provider %s v%s: %s.", providerAddr.ForDisplay(), cached.Version, err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+	defer provider.Close()
+
+	resp := provider.GetProviderSchema()
+	diags = diags.Append(resp.Diagnostics)
+	if resp.Diagnostics.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	schema := &providers.Schemas{
+		Provider:                   resp.Provider.Block,
+		ProviderMeta:               resp.ProviderMeta.Block,
+		ResourceTypes:              make(map[string]*configschema.Block),
+		DataSources:                make(map[string]*configschema.Block),
+		ResourceTypeSchemaVersions: make(map[string]uint64),
+	}
+	for t, r := range resp.ResourceTypes {
+		schema.ResourceTypes[t] = r.Block
+		schema.ResourceTypeSchemaVersions[t] = uint64(r.Version)
+	}
+	for t, d := range resp.DataSources {
+		schema.DataSources[t] = d.Block
+	}
+
+	schemas := &terraform.Schemas{
+		Providers: map[addrs.Provider]*providers.Schemas{
+			providerAddr: schema,
+		},
+	}
+
+	c.showDiagnostics(diags) // possibly just warnings
+	return c.outputSchemas(schemas, resourceType, outDir)
+}
+
 const providersSchemaCommandHelp = `
-Usage: terraform [global options] providers schema -json
+Usage: terraform [global options] providers schema -json [options]
 
-  Prints out a json representation of the schemas for all providers used 
+  Prints out a json representation of the schemas for all providers used
   in the current configuration.
+
+Options:
+
+  -provider=SOURCE[@VERSION]  Print only the schema for the given provider,
+                              such as "hashicorp/null". In a directory with
+                              no configuration at all, Terraform will instead
+                              install the given provider (the latest version,
+                              unless @VERSION selects another) into a
+                              temporary directory and print its schema,
+                              without needing "terraform init".
+
+  -resource-type=NAME         Print only the schema for the resource type or
+                              data source with the given name, from whichever
+                              of the selected providers define it.
+
+  -out-dir=DIR                Instead of printing to stdout, write one JSON
+                              file per resource type under the given
+                              directory, in a subdirectory per provider.
 `
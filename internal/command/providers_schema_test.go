@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -100,6 +101,178 @@ func TestProvidersSchema_output(t *testing.T) {
 	}
 }
 
+func TestProvidersSchema_singleProvider(t *testing.T) {
+	td := t.TempDir()
+	inputDir := filepath.Join("testdata/providers-schema", "required")
+	testCopyDir(t, inputDir, td)
+	defer testChdir(t, td)()
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.2.3"},
+	})
+	defer close()
+
+	p := providersSchemaFixtureProvider()
+	ui := new(cli.MockUi)
+	m := Meta{
+		testingOverrides: metaOverridesForProvider(p),
+		Ui:               ui,
+		ProviderSource:   providerSource,
+	}
+
+	ic := &InitCommand{
+		Meta: m,
+	}
+	if code := ic.Run([]string{}); code != 0 {
+		t.Fatalf("init failed\n%s", ui.ErrorWriter)
+	}
+	ui.OutputWriter.Reset()
+
+	t.Run("provider in configuration", func(t *testing.T) {
+		defer ui.OutputWriter.Reset()
+
+		pc := &ProvidersSchemaCommand{Meta: m}
+		if code := pc.Run([]string{"-json", "-provider=hashicorp/test"}); code != 0 {
+			t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+		}
+
+		var got providerSchemas
+		json.Unmarshal([]byte(ui.OutputWriter.String()), &got)
+		if len(got.Schemas) != 1 {
+			t.Fatalf("wrong number of provider schemas %d; want 1", len(got.Schemas))
+		}
+		if _, ok := got.Schemas["registry.terraform.io/hashicorp/test"]; !ok {
+			t.Fatalf("missing schema for registry.terraform.io/hashicorp/test")
+		}
+	})
+
+	t.Run("provider not in configuration", func(t *testing.T) {
+		defer ui.ErrorWriter.Reset()
+
+		pc := &ProvidersSchemaCommand{Meta: m}
+		if code := pc.Run([]string{"-json", "-provider=hashicorp/nonexist"}); code != 1 {
+			t.Fatalf("wrong exit status %d; want 1", code)
+		}
+		if got, want := ui.ErrorWriter.String(), "Provider schema not available"; !strings.Contains(got, want) {
+			t.Fatalf("missing error\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+
+	t.Run("version suffix rejected in configuration directory", func(t *testing.T) {
+		defer ui.ErrorWriter.Reset()
+
+		pc := &ProvidersSchemaCommand{Meta: m}
+		if code := pc.Run([]string{"-json", "-provider=hashicorp/test@1.2.3"}); code != 1 {
+			t.Fatalf("wrong exit status %d; want 1", code)
+		}
+		if got, want := ui.ErrorWriter.String(), "Invalid provider version"; !strings.Contains(got, want) {
+			t.Fatalf("missing error\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+}
+
+func TestProvidersSchema_resourceTypeAndOutDir(t *testing.T) {
+	td := t.TempDir()
+	inputDir := filepath.Join("testdata/providers-schema", "required")
+	testCopyDir(t, inputDir, td)
+	defer testChdir(t, td)()
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.2.3"},
+	})
+	defer close()
+
+	p := providersSchemaFixtureProvider()
+	ui := new(cli.MockUi)
+	m := Meta{
+		testingOverrides: metaOverridesForProvider(p),
+		Ui:               ui,
+		ProviderSource:   providerSource,
+	}
+
+	ic := &InitCommand{
+		Meta: m,
+	}
+	if code := ic.Run([]string{}); code != 0 {
+		t.Fatalf("init failed\n%s", ui.ErrorWriter)
+	}
+	ui.OutputWriter.Reset()
+
+	t.Run("resource type filter", func(t *testing.T) {
+		defer ui.OutputWriter.Reset()
+
+		pc := &ProvidersSchemaCommand{Meta: m}
+		if code := pc.Run([]string{"-json", "-resource-type=test_instance"}); code != 0 {
+			t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+		}
+
+		var got providerSchemas
+		json.Unmarshal([]byte(ui.OutputWriter.String()), &got)
+		schema, ok := got.Schemas["registry.terraform.io/hashicorp/test"]
+		if !ok {
+			t.Fatalf("missing schema for registry.terraform.io/hashicorp/test")
+		}
+		if len(schema.ResourceSchemas) != 1 {
+			t.Fatalf("wrong number of resource schemas %d; want 1", len(schema.ResourceSchemas))
+		}
+		if _, ok := schema.ResourceSchemas["test_instance"]; !ok {
+			t.Fatalf("missing schema for test_instance")
+		}
+		if schema.Provider != nil {
+			t.Fatalf("unexpected provider configuration schema in filtered output")
+		}
+	})
+
+	t.Run("resource type not found", func(t *testing.T) {
+		defer ui.ErrorWriter.Reset()
+
+		pc := &ProvidersSchemaCommand{Meta: m}
+		if code := pc.Run([]string{"-json", "-resource-type=test_nonexist"}); code != 1 {
+			t.Fatalf("wrong exit status %d; want 1", code)
+		}
+		if got, want := ui.ErrorWriter.String(), "Resource type not found"; !strings.Contains(got, want) {
+			t.Fatalf("missing error\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+
+	t.Run("out dir", func(t *testing.T) {
+		defer ui.OutputWriter.Reset()
+
+		outDir := filepath.Join(td, "schemas")
+		pc := &ProvidersSchemaCommand{Meta: m}
+		if code := pc.Run([]string{"-json", "-out-dir=" + outDir}); code != 0 {
+			t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+		}
+
+		if got, want := ui.OutputWriter.String(), "Wrote 2 schema files"; !strings.Contains(got, want) {
+			t.Fatalf("missing summary\ngot: %s\nwant substring: %s", got, want)
+		}
+
+		providerDir := filepath.Join(outDir, "registry.terraform.io", "hashicorp", "test")
+		src, err := os.ReadFile(filepath.Join(providerDir, "resource_types", "test_instance.json"))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var doc struct {
+			FormatVersion string      `json:"format_version"`
+			Provider      string      `json:"provider"`
+			Mode          string      `json:"mode"`
+			ResourceType  string      `json:"resource_type"`
+			Schema        interface{} `json:"schema"`
+		}
+		if err := json.Unmarshal(src, &doc); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if doc.Mode != "managed" || doc.ResourceType != "test_instance" || doc.Provider != "registry.terraform.io/hashicorp/test" || doc.Schema == nil {
+			t.Fatalf("wrong schema document:\n%s", src)
+		}
+
+		if _, err := os.Stat(filepath.Join(providerDir, "provider.json")); err != nil {
+			t.Fatalf("missing provider.json: %s", err)
+		}
+	})
+}
+
 type providerSchemas struct {
 	FormatVersion string                    `json:"format_version"`
 	Schemas       map[string]providerSchema `json:"provider_schemas"`
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -163,3 +164,72 @@ func TestProviders_state(t *testing.T) {
 		}
 	}
 }
+
+func TestProviders_json(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := os.Chdir(testFixturePath("providers/state")); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Chdir(cwd)
+
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	args := []string{"-json"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got providersCommandJSON
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+
+	if got.FormatVersion != providersCommandJSONFormatVersion {
+		t.Errorf("wrong format_version %q; want %q", got.FormatVersion, providersCommandJSONFormatVersion)
+	}
+
+	root := got.ProviderRequirements
+	if root == nil {
+		t.Fatalf("missing provider_requirements in output:\n%s", ui.OutputWriter.String())
+	}
+
+	foo, ok := root.Providers["registry.terraform.io/hashicorp/foo"]
+	if !ok {
+		t.Fatalf("missing hashicorp/foo in root module providers:\n%s", ui.OutputWriter.String())
+	}
+	if foo.VersionConstraints != "1.0.0" {
+		t.Errorf("wrong constraints for hashicorp/foo %q; want %q", foo.VersionConstraints, "1.0.0")
+	}
+	if !foo.Declared {
+		t.Errorf("hashicorp/foo should be marked as declared; it's in required_providers")
+	}
+
+	bar, ok := root.Providers["registry.terraform.io/hashicorp/bar"]
+	if !ok {
+		t.Fatalf("missing hashicorp/bar in root module providers:\n%s", ui.OutputWriter.String())
+	}
+	if bar.Declared {
+		t.Errorf("hashicorp/bar should not be marked as declared; it's only implied by a provider block")
+	}
+
+	wantStateReqs := []string{"registry.terraform.io/hashicorp/baz"}
+	for _, want := range wantStateReqs {
+		found := false
+		for _, gotReq := range got.StateRequirements {
+			if gotReq == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing %s in state_requirements %#v", want, got.StateRequirements)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// ProvidersVerifyCommand is a Command implementation that implements the
+// "terraform providers verify" command, which recomputes the checksums of
+// the provider packages installed in the local cache directory and compares
+// them against the dependency lock file, without installing anything.
+type ProvidersVerifyCommand struct {
+	Meta
+}
+
+func (c *ProvidersVerifyCommand) Synopsis() string {
+	return "Check installed providers against the dependency lock file"
+}
+
+// providersVerifyFormatVersion is the format version string recorded in the
+// -json output of "terraform providers verify". This will change only if the
+// output structure changes in a way that is not backward-compatible.
+const providersVerifyFormatVersion = "1.0"
+
+// The possible "status" values in a providerVerifyResult.
+const (
+	providerVerifyOK           = "ok"
+	providerVerifyTampered     = "tampered"
+	providerVerifyMissing      = "missing"
+	providerVerifyExtraneous   = "extraneous"
+	providerVerifyUnverifiable = "unverifiable"
+)
+
+// providerVerifyResult describes the outcome for a single provider package,
+// and is also the per-package JSON representation used with the -json option,
+// so the structure is part of the command's external interface.
+type providerVerifyResult struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version,omitempty"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func (c *ProvidersVerifyCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("providers verify")
+	var jsonOutput bool
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "machine-readable output")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The providers verify command expects no arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	locks, moreDiags := c.lockedDependencies()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	dir := c.providerLocalCacheDir()
+
+	var results []*providerVerifyResult
+
+	// Every locked provider must be installed at its locked version with
+	// contents matching one of the recorded checksums.
+	for provider, lock := range locks.AllProviders() {
+		cached := dir.ProviderVersion(provider, lock.Version())
+		if cached == nil {
+			results = append(results, &providerVerifyResult{
+				Provider: provider.String(),
+				Version:  lock.Version().String(),
+				Status:   providerVerifyMissing,
+				Detail:   "locked provider is not installed in the local cache directory",
+			})
+			continue
+		}
+		result := &providerVerifyResult{
+			Provider: provider.String(),
+			Version:  lock.Version().String(),
+		}
+		// Legacy "zh:" checksums cover the original release archive rather
+		// than the unpacked package we have on disk, so an entry with only
+		// those can't be verified here.
+		preferred := getproviders.PreferredHashes(lock.AllHashes())
+		if len(preferred) == 0 {
+			result.Status = providerVerifyUnverifiable
+			result.Detail = "the lock file records only legacy zip archive checksums, which cannot verify an unpacked package"
+			results = append(results, result)
+			continue
+		}
+		matches, err := cached.MatchesAnyHash(preferred)
+		switch {
+		case err != nil:
+			result.Status = providerVerifyTampered
+			result.Detail = fmt.Sprintf("failed to calculate checksum: %s", err)
+		case !matches:
+			result.Status = providerVerifyTampered
+			result.Detail = "package contents do not match any checksum recorded in the lock file"
+		default:
+			result.Status = providerVerifyOK
+		}
+		results = append(results, result)
+	}
+
+	// Installed packages that the lock file doesn't call for at all are
+	// reported too, since a compliance scan needs to know about packages
+	// that could be executed but were never locked.
+	for provider, cacheds := range dir.AllAvailablePackages() {
+		lock := locks.Provider(provider)
+		for i := range cacheds {
+			cached := &cacheds[i]
+			if lock != nil && cached.Version == lock.Version() {
+				continue // already checked above
+			}
+			detail := "installed provider is not recorded in the lock file"
+			if lock != nil {
+				detail = fmt.Sprintf("installed version is not the locked version (v%s)", lock.Version().String())
+			}
+			results = append(results, &providerVerifyResult{
+				Provider: provider.String(),
+				Version:  cached.Version.String(),
+				Status:   providerVerifyExtraneous,
+				Detail:   detail,
+			})
+		}
+	}
+
+	// The maps we visited above have no defined order, so we sort the
+	// results to keep the output deterministic.
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Provider != results[j].Provider {
+			return results[i].Provider < results[j].Provider
+		}
+		return results[i].Version < results[j].Version
+	})
+
+	problems := 0
+	for _, result := range results {
+		switch result.Status {
+		case providerVerifyTampered, providerVerifyMissing, providerVerifyExtraneous:
+			problems++
+		}
+	}
+
+	if jsonOutput {
+		doc := map[string]interface{}{
+			"format_version": providersVerifyFormatVersion,
+			"valid":          problems == 0,
+			"providers":      results,
+		}
+		j, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			// Should never happen because the input here is entirely under
+			// our control.
+			panic(fmt.Sprintf("failed to encode verification result: %s", err))
+		}
+		c.Ui.Output(string(j))
+	} else {
+		for _, result := range results {
+			switch result.Status {
+			case providerVerifyOK:
+				c.Ui.Output(fmt.Sprintf("- %s v%s: verified", result.Provider, result.Version))
+			default:
+				c.Ui.Output(fmt.Sprintf("- %s v%s: %s (%s)", result.Provider, result.Version, result.Status, result.Detail))
+			}
+		}
+		c.Ui.Output("")
+		if problems == 0 {
+			c.Ui.Output(fmt.Sprintf("Verified %d providers against the dependency lock file; no problems found.", len(results)))
+		} else {
+			c.Ui.Output(fmt.Sprintf("Verified %d providers against the dependency lock file; found %d problems.", len(results), problems))
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if problems > 0 {
+		return 1
+	}
+	return 0
+}
+
+func (c *ProvidersVerifyCommand) Help() string {
+	return `
+Usage: terraform [global options] providers verify [options]
+
+  Recomputes the checksums of the provider packages installed in the local
+  cache directory (.terraform/providers) and compares them against the
+  checksums recorded in the dependency lock file (.terraform.lock.hcl),
+  without downloading or installing anything.
+
+  Each locked provider is reported as "ok" when its installed package
+  matches a recorded checksum, "tampered" when the contents differ from
+  every recorded checksum, "missing" when the locked version is not
+  installed at all, or "unverifiable" when the lock entry records only
+  legacy zip archive checksums that cannot verify an unpacked package.
+  Installed packages that the lock file doesn't call for are reported as
+  "extraneous".
+
+  The exit status is 0 when no package is tampered, missing, or extraneous,
+  and 1 otherwise, so the command is suitable for automated compliance
+  scans of systems that run Terraform.
+
+Options:
+
+  -json  Produce the verification results in JSON format, suitable for
+         processing by other software.
+`
+}
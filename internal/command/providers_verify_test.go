@@ -0,0 +1,142 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/terraform/internal/getproviders"
+)
+
+func TestProvidersVerify(t *testing.T) {
+	// installFakeProvider places a fake provider package in the local cache
+	// directory and returns its h1: hash, so the test can write a matching
+	// lock file.
+	installFakeProvider := func(t *testing.T, td, name, version, content string) getproviders.Hash {
+		t.Helper()
+		pkgDir := filepath.Join(td, ".terraform", "providers", "registry.terraform.io", "hashicorp", name, version, fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH))
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(pkgDir, "terraform-provider-"+name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := getproviders.PackageHashV1(getproviders.PackageLocalDir(pkgDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	writeLockFile := func(t *testing.T, td, name, version string, hash getproviders.Hash) {
+		t.Helper()
+		lock := fmt.Sprintf(`provider "registry.terraform.io/hashicorp/%s" {
+  version = %q
+  hashes = [
+    %q,
+  ]
+}
+`, name, version, hash.String())
+		if err := ioutil.WriteFile(filepath.Join(td, ".terraform.lock.hcl"), []byte(lock), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		hash := installFakeProvider(t, td, "test", "1.0.0", "fake provider")
+		writeLockFile(t, td, "test", "1.0.0", hash)
+
+		ui := new(cli.MockUi)
+		c := &ProvidersVerifyCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run(nil); code != 0 {
+			t.Fatalf("wrong exit code; expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+		}
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "no problems found") {
+			t.Errorf("missing success summary from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		hash := installFakeProvider(t, td, "test", "1.0.0", "fake provider")
+		writeLockFile(t, td, "test", "1.0.0", hash)
+		// Modify the package after "locking" it.
+		exe := filepath.Join(td, ".terraform", "providers", "registry.terraform.io", "hashicorp", "test", "1.0.0", fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH), "terraform-provider-test")
+		if err := ioutil.WriteFile(exe, []byte("tampered"), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		ui := new(cli.MockUi)
+		c := &ProvidersVerifyCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run(nil); code != 1 {
+			t.Fatalf("wrong exit code; expected 1, got %d", code)
+		}
+		if got := ui.OutputWriter.String(); !strings.Contains(got, "tampered") {
+			t.Errorf("missing tampered status from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("missing and extraneous", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		// The lock file calls for "test" v1.0.0, but only an unlocked
+		// "other" provider is actually installed.
+		hash := installFakeProvider(t, td, "test", "1.0.0", "fake provider")
+		writeLockFile(t, td, "test", "1.0.0", hash)
+		pkgDir := filepath.Join(td, ".terraform", "providers", "registry.terraform.io", "hashicorp", "test", "1.0.0")
+		if err := os.RemoveAll(pkgDir); err != nil {
+			t.Fatal(err)
+		}
+		installFakeProvider(t, td, "other", "2.0.0", "another provider")
+
+		ui := new(cli.MockUi)
+		c := &ProvidersVerifyCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run(nil); code != 1 {
+			t.Fatalf("wrong exit code; expected 1, got %d", code)
+		}
+		got := ui.OutputWriter.String()
+		if !strings.Contains(got, "registry.terraform.io/hashicorp/test v1.0.0: missing") {
+			t.Errorf("missing \"missing\" status from output, got:\n%s\n", got)
+		}
+		if !strings.Contains(got, "registry.terraform.io/hashicorp/other v2.0.0: extraneous") {
+			t.Errorf("missing \"extraneous\" status from output, got:\n%s\n", got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		td := testTempDir(t)
+		defer os.RemoveAll(td)
+		defer testChdir(t, td)()
+
+		hash := installFakeProvider(t, td, "test", "1.0.0", "fake provider")
+		writeLockFile(t, td, "test", "1.0.0", hash)
+
+		ui := new(cli.MockUi)
+		c := &ProvidersVerifyCommand{Meta: Meta{Ui: ui}}
+		if code := c.Run([]string{"-json"}); code != 0 {
+			t.Fatalf("wrong exit code; expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+		}
+		got := ui.OutputWriter.String()
+		if !strings.Contains(got, `"valid": true`) {
+			t.Errorf("missing valid property from JSON output, got:\n%s\n", got)
+		}
+		if !strings.Contains(got, `"status": "ok"`) {
+			t.Errorf("missing ok status from JSON output, got:\n%s\n", got)
+		}
+	})
+}
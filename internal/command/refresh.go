@@ -62,6 +62,8 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 	// clear path to pass this value down, so we continue to mutate the Meta
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
+	c.Meta.refreshParallelism = args.Operation.RefreshParallelism
+	c.Meta.noSchemaCache = args.Operation.NoSchemaCache
 
 	// Prepare the backend with the backend-specific arguments
 	be, beDiags := c.PrepareBackend(args.State)
@@ -72,7 +74,7 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 	}
 
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(be, view, args.Operation)
+	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, args.Operation)
 	diags = diags.Append(opDiags)
 	if diags.HasErrors() {
 		view.Diagnostics(diags)
@@ -131,12 +133,12 @@ func (c *RefreshCommand) PrepareBackend(args *arguments.State) (backend.Enhanced
 	return be, diags
 }
 
-func (c *RefreshCommand) OperationRequest(be backend.Enhanced, view views.Refresh, args *arguments.Operation,
+func (c *RefreshCommand) OperationRequest(be backend.Enhanced, view views.Refresh, viewType arguments.ViewType, args *arguments.Operation,
 ) (*backend.Operation, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	// Build the operation
-	opReq := c.Operation(be)
+	opReq := c.Operation(be, viewType)
 	opReq.ConfigDir = "."
 	opReq.Hooks = view.Hooks()
 	opReq.Targets = args.Targets
@@ -170,6 +172,7 @@ func (c *RefreshCommand) GatherVariables(opReq *backend.Operation, args *argumen
 		items[i].Value = varArgs[i].Value
 	}
 	c.Meta.variableArgs = rawFlags{items: &items}
+	c.Meta.varProgram = args.VarProgram
 	opReq.Variables, diags = c.collectVariableValues()
 
 	return diags
@@ -200,8 +203,19 @@ Options:
 
   -lock-timeout=0s    Duration to retry a state lock.
 
+  -lock-wait          Keep waiting for the state lock until it is released,
+                      instead of failing on the first attempt. A -lock-timeout
+                      places an overall deadline on the wait.
+
   -no-color           If specified, output won't contain any color.
 
+  -no-schema-cache    Fetch fresh schemas from the providers instead of
+                      reusing schemas cached on disk from an earlier run.
+
+  -refresh-parallelism=n  Limit the number of concurrent operations while
+                      refreshing existing objects, separately from
+                      -parallelism. Defaults to the -parallelism value.
+
   -target=resource    Resource to target. Operation will be limited to this
                       resource and its dependencies. This flag can be used
                       multiple times.
@@ -0,0 +1,234 @@
+// Package sarif translates the JSON plan representation produced by package
+// jsonplan into a SARIF (Static Analysis Results Interchange Format) version
+// 2.1.0 document, so that planned resource changes can be ingested by code
+// scanning and policy tooling that understands SARIF, such as GitHub code
+// scanning, without a custom converter.
+//
+// Each planned resource change becomes one SARIF result whose rule identifies
+// the kind of action Terraform intends to take, and whose logical location
+// names the affected resource instance by its absolute address.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// schemaURI is the canonical location of the JSON schema for SARIF 2.1.0
+// documents, included in each document so consumers can validate it.
+const schemaURI = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// The following types describe the subset of SARIF 2.1.0 that we produce.
+// SARIF has many more optional properties than these, but a document
+// containing only this subset is still valid per the schema above.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string           `json:"id"`
+	Name                 string           `json:"name,omitempty"`
+	ShortDescription     sarifMessage     `json:"shortDescription"`
+	DefaultConfiguration *sarifRuleConfig `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind,omitempty"`
+}
+
+// rules describes the fixed rule set, one rule per kind of planned action.
+// The order here is load-bearing: each result's ruleIndex is an index into
+// this slice, as SARIF requires.
+var rules = []sarifRule{
+	{
+		ID:                   "terraform.plan.create",
+		Name:                 "PlannedCreate",
+		ShortDescription:     sarifMessage{Text: "Terraform plans to create this object."},
+		DefaultConfiguration: &sarifRuleConfig{Level: "note"},
+	},
+	{
+		ID:                   "terraform.plan.read",
+		Name:                 "PlannedRead",
+		ShortDescription:     sarifMessage{Text: "Terraform plans to read this object during apply."},
+		DefaultConfiguration: &sarifRuleConfig{Level: "note"},
+	},
+	{
+		ID:                   "terraform.plan.update",
+		Name:                 "PlannedUpdate",
+		ShortDescription:     sarifMessage{Text: "Terraform plans to update this object in-place."},
+		DefaultConfiguration: &sarifRuleConfig{Level: "note"},
+	},
+	{
+		ID:                   "terraform.plan.replace",
+		Name:                 "PlannedReplace",
+		ShortDescription:     sarifMessage{Text: "Terraform plans to destroy this object and create a replacement."},
+		DefaultConfiguration: &sarifRuleConfig{Level: "warning"},
+	},
+	{
+		ID:                   "terraform.plan.delete",
+		Name:                 "PlannedDelete",
+		ShortDescription:     sarifMessage{Text: "Terraform plans to destroy this object."},
+		DefaultConfiguration: &sarifRuleConfig{Level: "warning"},
+	},
+}
+
+// ruleIndices maps each action keyword to its index in rules.
+var ruleIndices = map[string]int{
+	"create":  0,
+	"read":    1,
+	"update":  2,
+	"replace": 3,
+	"delete":  4,
+}
+
+// planDoc describes the subset of the jsonplan representation that we need
+// in order to build results. We intentionally parse the serialized form
+// rather than depending on jsonplan's internal types, so this package works
+// with any input conforming to the documented plan representation.
+type planDoc struct {
+	TerraformVersion string `json:"terraform_version"`
+	ResourceChanges  []struct {
+		Address string `json:"address"`
+		Deposed string `json:"deposed"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+		ActionReason string `json:"action_reason"`
+	} `json:"resource_changes"`
+}
+
+// FromPlanJSON converts a JSON plan representation, as produced by
+// jsonplan.Marshal, into a SARIF 2.1.0 document. Resource changes whose
+// action is "no-op" produce no result, so an empty plan yields a document
+// with an empty results array.
+func FromPlanJSON(planJSON []byte) ([]byte, error) {
+	var plan planDoc
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan representation: %s", err)
+	}
+
+	results := make([]sarifResult, 0, len(plan.ResourceChanges))
+	for _, rc := range plan.ResourceChanges {
+		action := actionKeyword(rc.Change.Actions)
+		if action == "" {
+			continue // no-op changes are not worth reporting
+		}
+		ruleIndex, known := ruleIndices[action]
+		if !known {
+			// An action keyword from a future Terraform version; we can't
+			// assign it a rule, so we skip it rather than misreport it.
+			continue
+		}
+		rule := rules[ruleIndex]
+
+		addr := rc.Address
+		if rc.Deposed != "" {
+			addr = fmt.Sprintf("%s (deposed object %s)", addr, rc.Deposed)
+		}
+		text := fmt.Sprintf("Terraform plans to %s %s.", actionVerb(action), addr)
+		if rc.ActionReason != "" {
+			text = fmt.Sprintf("%s (Reason: %s.)", text, rc.ActionReason)
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    rule.ID,
+			RuleIndex: ruleIndex,
+			Level:     rule.DefaultConfiguration.Level,
+			Message:   sarifMessage{Text: text},
+			Locations: []sarifLocation{
+				{
+					LogicalLocations: []sarifLogicalLocation{
+						{
+							FullyQualifiedName: rc.Address,
+							Kind:               "resource",
+						},
+					},
+				},
+			},
+		})
+	}
+
+	doc := sarifLog{
+		Schema:  schemaURI,
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "terraform",
+						Version:        plan.TerraformVersion,
+						InformationURI: "https://www.terraform.io/",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+	return json.Marshal(doc)
+}
+
+// actionKeyword reduces a jsonplan actions list to a single keyword naming
+// one of the rules above, or "" for changes that need no result.
+func actionKeyword(actions []string) string {
+	switch {
+	case len(actions) == 0:
+		return ""
+	case len(actions) > 1:
+		// Both ["create", "delete"] and ["delete", "create"] mean replace.
+		return "replace"
+	case actions[0] == "no-op":
+		return ""
+	default:
+		return actions[0]
+	}
+}
+
+// actionVerb returns the phrase used for an action keyword in result
+// messages. Most keywords read naturally as verbs already.
+func actionVerb(action string) string {
+	if action == "delete" {
+		return "destroy"
+	}
+	return action
+}
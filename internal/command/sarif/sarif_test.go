@@ -0,0 +1,166 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFromPlanJSON(t *testing.T) {
+	planJSON := []byte(`{
+		"format_version": "1.2",
+		"terraform_version": "1.3.0",
+		"resource_changes": [
+			{
+				"address": "test_resource.create",
+				"change": {"actions": ["create"]}
+			},
+			{
+				"address": "test_resource.update",
+				"change": {"actions": ["update"]}
+			},
+			{
+				"address": "test_resource.replace",
+				"change": {"actions": ["delete", "create"]},
+				"action_reason": "replace_because_cannot_update"
+			},
+			{
+				"address": "test_resource.delete",
+				"change": {"actions": ["delete"]}
+			},
+			{
+				"address": "test_resource.noop",
+				"change": {"actions": ["no-op"]}
+			},
+			{
+				"address": "test_resource.deposed",
+				"deposed": "byebye",
+				"change": {"actions": ["delete"]}
+			}
+		]
+	}`)
+
+	got, err := FromPlanJSON(planJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name    string `json:"name"`
+					Version string `json:"version"`
+					Rules   []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Message   struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+						Kind               string `json:"kind"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %s", err)
+	}
+
+	if doc.Version != "2.1.0" {
+		t.Errorf("wrong SARIF version %q; want %q", doc.Version, "2.1.0")
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("wrong number of runs %d; want 1", len(doc.Runs))
+	}
+	run := doc.Runs[0]
+	if got, want := run.Tool.Driver.Name, "terraform"; got != want {
+		t.Errorf("wrong driver name %q; want %q", got, want)
+	}
+	if got, want := run.Tool.Driver.Version, "1.3.0"; got != want {
+		t.Errorf("wrong driver version %q; want %q", got, want)
+	}
+	if got, want := len(run.Tool.Driver.Rules), len(rules); got != want {
+		t.Errorf("wrong number of rules %d; want %d", got, want)
+	}
+
+	// The no-op change must not produce a result.
+	if got, want := len(run.Results), 5; got != want {
+		t.Fatalf("wrong number of results %d; want %d", got, want)
+	}
+
+	wantResults := []struct {
+		ruleID  string
+		level   string
+		address string
+		message string
+	}{
+		{"terraform.plan.create", "note", "test_resource.create", "Terraform plans to create test_resource.create."},
+		{"terraform.plan.update", "note", "test_resource.update", "Terraform plans to update test_resource.update."},
+		{"terraform.plan.replace", "warning", "test_resource.replace", "Terraform plans to replace test_resource.replace. (Reason: replace_because_cannot_update.)"},
+		{"terraform.plan.delete", "warning", "test_resource.delete", "Terraform plans to destroy test_resource.delete."},
+		{"terraform.plan.delete", "warning", "test_resource.deposed", "Terraform plans to destroy test_resource.deposed (deposed object byebye)."},
+	}
+	for i, want := range wantResults {
+		result := run.Results[i]
+		if result.RuleID != want.ruleID {
+			t.Errorf("result %d: wrong ruleId %q; want %q", i, result.RuleID, want.ruleID)
+		}
+		if got := run.Tool.Driver.Rules[result.RuleIndex].ID; got != want.ruleID {
+			t.Errorf("result %d: ruleIndex %d refers to rule %q; want %q", i, result.RuleIndex, got, want.ruleID)
+		}
+		if result.Level != want.level {
+			t.Errorf("result %d: wrong level %q; want %q", i, result.Level, want.level)
+		}
+		if result.Message.Text != want.message {
+			t.Errorf("result %d: wrong message %q; want %q", i, result.Message.Text, want.message)
+		}
+		if len(result.Locations) != 1 || len(result.Locations[0].LogicalLocations) != 1 {
+			t.Fatalf("result %d: wrong location structure: %#v", i, result.Locations)
+		}
+		loc := result.Locations[0].LogicalLocations[0]
+		if loc.FullyQualifiedName != want.address {
+			t.Errorf("result %d: wrong logical location %q; want %q", i, loc.FullyQualifiedName, want.address)
+		}
+		if loc.Kind != "resource" {
+			t.Errorf("result %d: wrong logical location kind %q; want %q", i, loc.Kind, "resource")
+		}
+	}
+}
+
+func TestFromPlanJSON_empty(t *testing.T) {
+	got, err := FromPlanJSON([]byte(`{"format_version": "1.2", "terraform_version": "1.3.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Runs []struct {
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("invalid JSON output: %s", err)
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("wrong number of runs %d; want 1", len(doc.Runs))
+	}
+	if len(doc.Runs[0].Results) != 0 {
+		t.Errorf("wrong number of results %d; want 0", len(doc.Runs[0].Results))
+	}
+}
+
+func TestFromPlanJSON_invalid(t *testing.T) {
+	if _, err := FromPlanJSON([]byte(`ceci n'est pas un plan`)); err == nil {
+		t.Fatal("succeeded; want error")
+	}
+}
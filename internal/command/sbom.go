@@ -0,0 +1,623 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/sumdb/dirhash"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+	"github.com/hashicorp/terraform/internal/modsdir"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	tfversion "github.com/hashicorp/terraform/version"
+)
+
+// SbomCommand is a Command implementation that produces a software bill of
+// materials describing the providers and modules installed for the current
+// working directory, or verifies the installed artifacts against a
+// previously-generated bill of materials.
+type SbomCommand struct {
+	Meta
+}
+
+// The component kinds we record in a bill of materials. These appear in the
+// generated documents (as a CycloneDX property or in the SPDX sourceInfo
+// field) so that -verify can tell how to re-check each component.
+const (
+	sbomKindTerraform = "terraform"
+	sbomKindProvider  = "provider"
+	sbomKindModule    = "module"
+)
+
+// sbomComponent is our format-neutral description of one component, from
+// which we render both of the supported document formats and against which
+// -verify compares the installed artifacts.
+type sbomComponent struct {
+	// Kind is one of the sbomKind... constants above.
+	Kind string
+
+	// Name identifies the component: the fully-qualified source address for
+	// a provider, the module path key (as in the module manifest) for a
+	// module, and literally "terraform" for the CLI itself.
+	Name string
+
+	// Version is the exact selected version, or the empty string for
+	// unversioned modules.
+	Version string
+
+	// Source records where the component came from: the registry hostname
+	// for a provider, or the configured source address for a module.
+	Source string
+
+	// Hashes are Terraform's own scheme-prefixed hash strings ("h1:", "zh:")
+	// for the component's content, as recorded in the dependency lock file
+	// for providers or computed over the installed directory for modules.
+	Hashes []string
+}
+
+func (c *SbomCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("sbom")
+	var format, outPath, verifyPath string
+	cmdFlags.StringVar(&format, "format", "cyclonedx-json", "output format")
+	cmdFlags.StringVar(&outPath, "out", "", "write the document to this file")
+	cmdFlags.StringVar(&verifyPath, "verify", "", "verify installed artifacts against this document")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	if verifyPath != "" {
+		src, err := os.ReadFile(verifyPath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read %s: %s", verifyPath, err))
+			return 1
+		}
+		components, err := parseSbom(src)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse %s: %s", verifyPath, err))
+			return 1
+		}
+		diags = c.verifySbomComponents(components)
+		c.showDiagnostics(diags)
+		if diags.HasErrors() {
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("Verified %d components against the installed artifacts. No mismatches found.", len(components)))
+		return 0
+	}
+
+	switch format {
+	case "cyclonedx-json", "spdx-json":
+		// ok
+	default:
+		c.Ui.Error(fmt.Sprintf("Unsupported format %q: must be either \"cyclonedx-json\" or \"spdx-json\".", format))
+		return 1
+	}
+
+	components, moreDiags := c.collectSbomComponents()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	var doc []byte
+	var err error
+	switch format {
+	case "cyclonedx-json":
+		doc, err = renderSbomCycloneDX(components, time.Now().UTC())
+	case "spdx-json":
+		doc, err = renderSbomSPDX(components, time.Now().UTC())
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to render the bill of materials: %s", err))
+		return 1
+	}
+
+	if outPath != "" {
+		if err := os.WriteFile(outPath, doc, 0644); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to write %s: %s", outPath, err))
+			return 1
+		}
+	} else {
+		c.Ui.Output(string(doc))
+	}
+
+	c.showDiagnostics(diags) // possibly just warnings
+	return 0
+}
+
+// collectSbomComponents assembles the component list from the dependency
+// lock file and the module manifest, without any network access. The
+// returned components are in a stable order: the CLI itself first, then
+// providers sorted by address, then modules sorted by their manifest keys.
+func (c *SbomCommand) collectSbomComponents() ([]sbomComponent, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := []sbomComponent{
+		{
+			Kind:    sbomKindTerraform,
+			Name:    "terraform",
+			Version: tfversion.String(),
+		},
+	}
+
+	locks, lockDiags := c.lockedDependencies()
+	diags = diags.Append(lockDiags)
+	if lockDiags.HasErrors() {
+		return nil, diags
+	}
+
+	providerLocks := locks.AllProviders()
+	providerAddrs := make([]addrs.Provider, 0, len(providerLocks))
+	for addr := range providerLocks {
+		providerAddrs = append(providerAddrs, addr)
+	}
+	sort.Slice(providerAddrs, func(i, j int) bool {
+		return providerAddrs[i].String() < providerAddrs[j].String()
+	})
+	for _, addr := range providerAddrs {
+		lock := providerLocks[addr]
+		var hashes []string
+		for _, hash := range lock.AllHashes() {
+			hashes = append(hashes, hash.String())
+		}
+		ret = append(ret, sbomComponent{
+			Kind:    sbomKindProvider,
+			Name:    addr.String(),
+			Version: lock.Version().String(),
+			Source:  addr.Hostname.ForDisplay(),
+			Hashes:  hashes,
+		})
+	}
+
+	manifest, err := modsdir.ReadManifestSnapshotForDir(c.modulesDir())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read module manifest",
+			fmt.Sprintf("Could not read the module manifest for this working directory: %s.", err),
+		))
+		return nil, diags
+	}
+	moduleKeys := make([]string, 0, len(manifest))
+	for key := range manifest {
+		if key == "" {
+			// The root module is the configuration itself, not an installed
+			// dependency, so it doesn't belong in the bill of materials.
+			continue
+		}
+		moduleKeys = append(moduleKeys, key)
+	}
+	sort.Strings(moduleKeys)
+	for _, key := range moduleKeys {
+		record := manifest[key]
+		hash, err := dirhash.HashDir(record.Dir, "", dirhash.Hash1)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to hash installed module",
+				fmt.Sprintf("Could not compute a content hash for module %q at %s: %s. Run \"terraform init\" to reinstall the configuration's modules.", key, record.Dir, err),
+			))
+			continue
+		}
+		ret = append(ret, sbomComponent{
+			Kind:    sbomKindModule,
+			Name:    key,
+			Version: record.VersionStr,
+			Source:  record.SourceAddr,
+			Hashes:  []string{hash},
+		})
+	}
+
+	return ret, diags
+}
+
+// verifySbomComponents re-checks the installed artifacts against the given
+// previously-recorded components, returning error diagnostics for anything
+// on disk that no longer matches.
+func (c *SbomCommand) verifySbomComponents(components []sbomComponent) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	manifest, err := modsdir.ReadManifestSnapshotForDir(c.modulesDir())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read module manifest",
+			fmt.Sprintf("Could not read the module manifest for this working directory: %s.", err),
+		))
+		return diags
+	}
+	providerCache := c.providerLocalCacheDir()
+
+	for _, component := range components {
+		switch component.Kind {
+		case sbomKindTerraform:
+			if got := tfversion.String(); got != component.Version {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Terraform CLI version mismatch",
+					fmt.Sprintf("The bill of materials was generated with Terraform v%s, but this is Terraform v%s.", component.Version, got),
+				))
+			}
+		case sbomKindProvider:
+			addr, addrDiags := addrs.ParseProviderSourceString(component.Name)
+			if addrDiags.HasErrors() {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid provider in bill of materials",
+					fmt.Sprintf("The bill of materials records a provider with invalid source address %q.", component.Name),
+				))
+				continue
+			}
+			version, err := getproviders.ParseVersion(component.Version)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid provider in bill of materials",
+					fmt.Sprintf("The bill of materials records provider %s with invalid version %q.", addr.ForDisplay(), component.Version),
+				))
+				continue
+			}
+			cached := providerCache.ProviderVersion(addr, version)
+			if cached == nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Provider not installed",
+					fmt.Sprintf("The bill of materials records provider %s v%s, but that provider version is not installed in this working directory.", addr.ForDisplay(), version),
+				))
+				continue
+			}
+			var hashes []getproviders.Hash
+			for _, str := range component.Hashes {
+				hash, err := getproviders.ParseHash(str)
+				if err != nil {
+					continue // ignore hashes in formats we don't know
+				}
+				hashes = append(hashes, hash)
+			}
+			if matches, err := cached.MatchesAnyHash(hashes); err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to verify provider",
+					fmt.Sprintf("Could not re-hash the installed package for provider %s v%s: %s.", addr.ForDisplay(), version, err),
+				))
+			} else if !matches {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Provider package has changed",
+					fmt.Sprintf("The installed package for provider %s v%s does not match any of the hashes recorded in the bill of materials.", addr.ForDisplay(), version),
+				))
+			}
+		case sbomKindModule:
+			record, exists := manifest[component.Name]
+			if !exists {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Module not installed",
+					fmt.Sprintf("The bill of materials records module %q, but no module of that name is installed in this working directory.", component.Name),
+				))
+				continue
+			}
+			if record.VersionStr != component.Version {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Module version has changed",
+					fmt.Sprintf("The bill of materials records module %q at version %q, but version %q is installed.", component.Name, component.Version, record.VersionStr),
+				))
+				continue
+			}
+			hash, err := dirhash.HashDir(record.Dir, "", dirhash.Hash1)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to verify module",
+					fmt.Sprintf("Could not re-hash module %q at %s: %s.", component.Name, record.Dir, err),
+				))
+				continue
+			}
+			matched := false
+			for _, want := range component.Hashes {
+				if want == hash {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Module content has changed",
+					fmt.Sprintf("The installed directory for module %q does not match the content hash recorded in the bill of materials.", component.Name),
+				))
+			}
+		default:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Unknown component kind",
+				fmt.Sprintf("The bill of materials records component %q with unknown kind %q, which this version of Terraform cannot verify.", component.Name, component.Kind),
+			))
+		}
+	}
+
+	return diags
+}
+
+func (c *SbomCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] sbom [options]
+
+  Produces a software bill of materials describing every provider and
+  module installed for the current working directory, along with the
+  Terraform CLI itself.
+
+  The document is assembled entirely from the dependency lock file, the
+  module manifest, and the installed packages, without any network access,
+  so it reflects exactly what "terraform init" installed.
+
+Options:
+
+  -format=FORMAT  The document format to produce: either "cyclonedx-json"
+                  (the default) or "spdx-json".
+
+  -out=FILE       Write the document to the given file instead of to stdout.
+
+  -verify=FILE    Instead of producing a document, read a previously
+                  generated one from the given file and re-hash the
+                  installed artifacts against it, failing if anything on
+                  disk no longer matches.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SbomCommand) Synopsis() string {
+	return "Generate or verify a software bill of materials"
+}
+
+// What follows are the serialization structures for the two supported
+// document formats. Both formats have far more optional structure than we
+// populate here; we record Terraform's own scheme-prefixed hashes in a
+// format-specific extension spot (CycloneDX properties, SPDX sourceInfo) so
+// that -verify can recover them from either format.
+
+type cycloneDXDocument struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    *cycloneDXMetadata   `json:"metadata,omitempty"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string `json:"timestamp"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// The CycloneDX property names under which we record our own metadata.
+const (
+	cycloneDXPropKind   = "terraform:kind"
+	cycloneDXPropSource = "terraform:source"
+	cycloneDXPropHash   = "terraform:hash"
+)
+
+func renderSbomCycloneDX(components []sbomComponent, now time.Time) ([]byte, error) {
+	doc := cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.4",
+		Version:     1,
+		Metadata: &cycloneDXMetadata{
+			Timestamp: now.Format(time.RFC3339),
+		},
+		Components: make([]cycloneDXComponent, 0, len(components)),
+	}
+	for _, component := range components {
+		out := cycloneDXComponent{
+			Type:    "library",
+			Name:    component.Name,
+			Version: component.Version,
+			Properties: []cycloneDXProperty{
+				{Name: cycloneDXPropKind, Value: component.Kind},
+			},
+		}
+		if component.Kind == sbomKindTerraform {
+			out.Type = "application"
+		}
+		if component.Source != "" {
+			out.Properties = append(out.Properties, cycloneDXProperty{
+				Name: cycloneDXPropSource, Value: component.Source,
+			})
+		}
+		for _, hash := range component.Hashes {
+			out.Properties = append(out.Properties, cycloneDXProperty{
+				Name: cycloneDXPropHash, Value: hash,
+			})
+			// The legacy "zh:" scheme is a plain SHA-256 of the release
+			// archive, so we can also record it in the standard hash list
+			// for consumers that don't know Terraform's own schemes.
+			if strings.HasPrefix(hash, "zh:") {
+				out.Hashes = append(out.Hashes, cycloneDXHash{
+					Alg:     "SHA-256",
+					Content: strings.TrimPrefix(hash, "zh:"),
+				})
+			}
+		}
+		doc.Components = append(doc.Components, out)
+	}
+	return json.MarshalIndent(&doc, "", "  ")
+}
+
+type spdxDocument struct {
+	SPDXVersion       string           `json:"spdxVersion"`
+	DataLicense       string           `json:"dataLicense"`
+	SPDXID            string           `json:"SPDXID"`
+	Name              string           `json:"name"`
+	DocumentNamespace string           `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo `json:"creationInfo"`
+	Packages          []spdxPackage    `json:"packages"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string         `json:"SPDXID"`
+	Name             string         `json:"name"`
+	VersionInfo      string         `json:"versionInfo,omitempty"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	SourceInfo       string         `json:"sourceInfo,omitempty"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+func renderSbomSPDX(components []sbomComponent, now time.Time) ([]byte, error) {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.2",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "terraform-working-directory",
+		DocumentNamespace: "https://spdx.org/spdxdocs/terraform-" + now.Format("20060102T150405Z"),
+		CreationInfo: spdxCreationInfo{
+			Created:  now.Format(time.RFC3339),
+			Creators: []string{"Tool: terraform-" + tfversion.String()},
+		},
+		Packages: make([]spdxPackage, 0, len(components)),
+	}
+	for i, component := range components {
+		out := spdxPackage{
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i),
+			Name:             component.Name,
+			VersionInfo:      component.Version,
+			DownloadLocation: "NOASSERTION",
+			FilesAnalyzed:    false,
+			SourceInfo:       encodeSPDXSourceInfo(component),
+		}
+		for _, hash := range component.Hashes {
+			// As in the CycloneDX renderer, only the legacy "zh:" scheme
+			// maps onto a standard checksum algorithm.
+			if strings.HasPrefix(hash, "zh:") {
+				out.Checksums = append(out.Checksums, spdxChecksum{
+					Algorithm:     "SHA256",
+					ChecksumValue: strings.TrimPrefix(hash, "zh:"),
+				})
+			}
+		}
+		doc.Packages = append(doc.Packages, out)
+	}
+	return json.MarshalIndent(&doc, "", "  ")
+}
+
+// encodeSPDXSourceInfo packs our Terraform-specific component metadata into
+// the free-text SPDX sourceInfo field, in a form that parseSPDXSourceInfo
+// can recover.
+func encodeSPDXSourceInfo(component sbomComponent) string {
+	parts := []string{"terraform-kind=" + component.Kind}
+	if component.Source != "" {
+		parts = append(parts, "terraform-source="+component.Source)
+	}
+	for _, hash := range component.Hashes {
+		parts = append(parts, "terraform-hash="+hash)
+	}
+	return strings.Join(parts, " ")
+}
+
+func parseSPDXSourceInfo(sourceInfo string, component *sbomComponent) {
+	for _, part := range strings.Fields(sourceInfo) {
+		switch {
+		case strings.HasPrefix(part, "terraform-kind="):
+			component.Kind = strings.TrimPrefix(part, "terraform-kind=")
+		case strings.HasPrefix(part, "terraform-source="):
+			component.Source = strings.TrimPrefix(part, "terraform-source=")
+		case strings.HasPrefix(part, "terraform-hash="):
+			component.Hashes = append(component.Hashes, strings.TrimPrefix(part, "terraform-hash="))
+		}
+	}
+}
+
+// parseSbom reads back a document generated by either of our renderers,
+// detecting the format from the document itself, and returns the recorded
+// components.
+func parseSbom(src []byte) ([]sbomComponent, error) {
+	var probe struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(src, &probe); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case probe.BOMFormat == "CycloneDX":
+		var doc cycloneDXDocument
+		if err := json.Unmarshal(src, &doc); err != nil {
+			return nil, err
+		}
+		ret := make([]sbomComponent, 0, len(doc.Components))
+		for _, in := range doc.Components {
+			component := sbomComponent{
+				Name:    in.Name,
+				Version: in.Version,
+			}
+			for _, prop := range in.Properties {
+				switch prop.Name {
+				case cycloneDXPropKind:
+					component.Kind = prop.Value
+				case cycloneDXPropSource:
+					component.Source = prop.Value
+				case cycloneDXPropHash:
+					component.Hashes = append(component.Hashes, prop.Value)
+				}
+			}
+			ret = append(ret, component)
+		}
+		return ret, nil
+	case strings.HasPrefix(probe.SPDXVersion, "SPDX-"):
+		var doc spdxDocument
+		if err := json.Unmarshal(src, &doc); err != nil {
+			return nil, err
+		}
+		ret := make([]sbomComponent, 0, len(doc.Packages))
+		for _, in := range doc.Packages {
+			component := sbomComponent{
+				Name:    in.Name,
+				Version: in.VersionInfo,
+			}
+			parseSPDXSourceInfo(in.SourceInfo, &component)
+			ret = append(ret, component)
+		}
+		return ret, nil
+	default:
+		return nil, fmt.Errorf("not a CycloneDX or SPDX JSON document")
+	}
+}
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSbomRoundTrip(t *testing.T) {
+	components := []sbomComponent{
+		{
+			Kind:    sbomKindTerraform,
+			Name:    "terraform",
+			Version: "1.3.0",
+		},
+		{
+			Kind:    sbomKindProvider,
+			Name:    "registry.terraform.io/hashicorp/null",
+			Version: "3.1.0",
+			Source:  "registry.terraform.io",
+			Hashes: []string{
+				"h1:YvH6gTaQzGdNv+SKTZujU1O0bO+Pw6vJHOPhqgN8XNs=",
+				"zh:063466f41f1d9fd0dd93722840c1314f046d8760b1812fa67c34de0afcba5597",
+			},
+		},
+		{
+			Kind:    sbomKindModule,
+			Name:    "child",
+			Version: "1.0.0",
+			Source:  "example.com/test/child/generic",
+			Hashes:  []string{"h1:qjsREM4DqEWECz43wmrLvyXflxC8hjbbUFBKNrT2i/A="},
+		},
+	}
+	now := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, format := range []string{"cyclonedx-json", "spdx-json"} {
+		t.Run(format, func(t *testing.T) {
+			var doc []byte
+			var err error
+			switch format {
+			case "cyclonedx-json":
+				doc, err = renderSbomCycloneDX(components, now)
+			case "spdx-json":
+				doc, err = renderSbomSPDX(components, now)
+			}
+			if err != nil {
+				t.Fatalf("failed to render: %s", err)
+			}
+
+			got, err := parseSbom(doc)
+			if err != nil {
+				t.Fatalf("failed to parse rendered document: %s", err)
+			}
+			if !reflect.DeepEqual(got, components) {
+				t.Errorf("components did not survive the round trip\ngot:  %#v\nwant: %#v", got, components)
+			}
+		})
+	}
+}
+
+func TestSbomModules(t *testing.T) {
+	// Create a temporary working directory containing an installed module
+	// and a module manifest describing it.
+	testCwd(t)
+	if err := os.MkdirAll(filepath.Join(".terraform", "modules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("child", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("child", "main.tf"), []byte("# empty module\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"child","Source":"./child","Dir":"child"}]}`
+	if err := os.WriteFile(filepath.Join(".terraform", "modules", "modules.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &SbomCommand{}
+
+	components, diags := c.collectSbomComponents()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	// The CLI itself plus the one installed module.
+	if len(components) != 2 {
+		t.Fatalf("got %d components; want 2", len(components))
+	}
+	mod := components[1]
+	if mod.Kind != sbomKindModule || mod.Name != "child" || mod.Source != "./child" {
+		t.Fatalf("wrong module component %#v", mod)
+	}
+	if len(mod.Hashes) != 1 {
+		t.Fatalf("module component has %d hashes; want 1", len(mod.Hashes))
+	}
+
+	// The installed artifacts still match what we just recorded.
+	if diags := c.verifySbomComponents(components); diags.HasErrors() {
+		t.Errorf("verification failed against unchanged artifacts: %s", diags.Err())
+	}
+
+	// After the module's content changes, verification must fail.
+	if err := os.WriteFile(filepath.Join("child", "main.tf"), []byte("# changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if diags := c.verifySbomComponents(components); !diags.HasErrors() {
+		t.Error("verification succeeded against modified module content")
+	}
+}
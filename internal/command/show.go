@@ -1,9 +1,13 @@
 package command
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/backend"
 	"github.com/hashicorp/terraform/internal/command/arguments"
@@ -39,6 +43,24 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 	// Set up view
 	view := views.NewShow(args.ViewType, c.View)
 
+	// The -last option displays the recorded summary of the most recent
+	// operation instead of a state or plan.
+	if args.Last {
+		return c.showLastOperation(view)
+	}
+
+	// The -timings option summarizes per-resource timings from a JSON log
+	// file captured from a command run with the -json option.
+	if args.Timings {
+		return c.showTimings(view, args.Path)
+	}
+
+	// The -metadata option displays the run environment record embedded in
+	// a saved plan file instead of the plan itself.
+	if args.Metadata {
+		return c.showMetadata(view, args.Path)
+	}
+
 	// Check for user-supplied plugin path
 	var err error
 	if c.pluginPath, err = c.loadPluginPath(); err != nil {
@@ -59,6 +81,257 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 	return view.Display(config, plan, stateFile, schemas)
 }
 
+// showLastOperation prints the saved JSON summary of the most recent entry
+// in the working directory's local operation history.
+func (c *ShowCommand) showLastOperation(view views.Show) int {
+	var diags tfdiags.Diagnostics
+
+	entries, err := c.WorkingDir.ReadOperationHistory()
+	if err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+	if len(entries) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No operation history",
+			"This working directory has no recorded operation history. History is recorded when running commands such as \"terraform plan\" and \"terraform apply\", unless disabled in the CLI configuration.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	last := entries[len(entries)-1]
+	if path := c.WorkingDir.OperationHistorySummaryPath(last); path != "" {
+		if src, err := os.ReadFile(path); err == nil {
+			c.Ui.Output(strings.TrimSpace(string(src)))
+			return 0
+		}
+	}
+
+	// If the summary file is missing for some reason then the entry itself
+	// is the next best thing.
+	buf, err := json.MarshalIndent(last, "", "  ")
+	if err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+	c.Ui.Output(string(buf))
+	return 0
+}
+
+// showTimings reads a JSON log file written by a command run with the -json
+// option and prints a per-resource timing summary based on the
+// apply_complete and apply_errored messages in it, slowest first.
+func (c *ShowCommand) showTimings(view views.Show, path string) int {
+	var diags tfdiags.Diagnostics
+
+	if path == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Log file required",
+			"The -timings option summarizes the JSON log produced by a command run with the -json option, so the path of a captured log file is required.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("failed to open log file: %s", err))
+		view.Diagnostics(diags)
+		return 1
+	}
+	defer f.Close()
+
+	type timingEntry struct {
+		addr      string
+		action    string
+		elapsedMS int64
+		waitingMS int64
+		retries   int
+		errored   bool
+	}
+	type logLine struct {
+		Type string `json:"type"`
+		Hook struct {
+			Resource struct {
+				Addr string `json:"addr"`
+			} `json:"resource"`
+			Action    string  `json:"action"`
+			Elapsed   float64 `json:"elapsed_seconds"`
+			ElapsedMS *int64  `json:"elapsed_milliseconds"`
+			WaitingMS int64   `json:"waiting_milliseconds"`
+			Retries   int     `json:"retries"`
+		} `json:"hook"`
+	}
+
+	var entries []timingEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var ll logLine
+		if err := json.Unmarshal([]byte(line), &ll); err != nil {
+			// Captured logs sometimes have other output interleaved with the
+			// JSON messages, so we just skip anything we can't understand.
+			continue
+		}
+		if ll.Type != "apply_complete" && ll.Type != "apply_errored" {
+			continue
+		}
+		entry := timingEntry{
+			addr:      ll.Hook.Resource.Addr,
+			action:    ll.Hook.Action,
+			waitingMS: ll.Hook.WaitingMS,
+			retries:   ll.Hook.Retries,
+			errored:   ll.Type == "apply_errored",
+		}
+		if ll.Hook.ElapsedMS != nil {
+			entry.elapsedMS = *ll.Hook.ElapsedMS
+		} else {
+			// Logs from older Terraform versions report only whole seconds.
+			entry.elapsedMS = int64(ll.Hook.Elapsed * 1000)
+		}
+		entries = append(entries, entry)
+	}
+	if err := sc.Err(); err != nil {
+		diags = diags.Append(fmt.Errorf("failed to read log file: %s", err))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	if len(entries) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No timing information found",
+			fmt.Sprintf("The file %s has no apply_complete or apply_errored messages, so there are no resource timings to summarize. Capture the log by redirecting the output of a command run with the -json option.", path),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].elapsedMS != entries[j].elapsedMS {
+			return entries[i].elapsedMS > entries[j].elapsedMS
+		}
+		return entries[i].addr < entries[j].addr
+	})
+
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("%10s %10s %8s  %-18s %s\n", "ELAPSED", "WAITING", "RETRIES", "ACTION", "RESOURCE"))
+	for _, entry := range entries {
+		action := entry.action
+		if entry.errored {
+			action += " (errored)"
+		}
+		buf.WriteString(fmt.Sprintf(
+			"%10s %10s %8d  %-18s %s\n",
+			time.Duration(entry.elapsedMS)*time.Millisecond,
+			time.Duration(entry.waitingMS)*time.Millisecond,
+			entry.retries,
+			action,
+			entry.addr,
+		))
+	}
+	c.Ui.Output(strings.TrimRight(buf.String(), "\n"))
+	return 0
+}
+
+// showMetadata prints the run environment record embedded in the given
+// saved plan file: the Terraform version, workspace, provider selections,
+// and input variable values the plan was created with.
+func (c *ShowCommand) showMetadata(view views.Show, path string) int {
+	var diags tfdiags.Diagnostics
+
+	if path == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file required",
+			"The -metadata option displays the run environment recorded in a saved plan file, so the path of a plan file is required.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	pf, err := planfile.Open(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("The file %q could not be read as a plan file: %s.", path, err),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	defer pf.Close()
+
+	env, err := pf.ReadEnvironment()
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read plan file",
+			fmt.Sprintf("Couldn't read the run environment record from %q: %s.", path, err),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	if env == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No run environment recorded",
+			fmt.Sprintf("The plan file %q doesn't record the environment it was created in, because it was created by an earlier version of Terraform.", path),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("Terraform version: %s\n", env.TerraformVersion))
+	buf.WriteString(fmt.Sprintf("Workspace:         %s\n", env.Workspace))
+
+	if len(env.Providers) > 0 {
+		buf.WriteString("\nProviders:\n")
+		addrs := make([]string, 0, len(env.Providers))
+		for addr := range env.Providers {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		for _, addr := range addrs {
+			provider := env.Providers[addr]
+			buf.WriteString(fmt.Sprintf("  %s v%s\n", addr, provider.Version))
+			for _, hash := range provider.Hashes {
+				buf.WriteString(fmt.Sprintf("    %s\n", hash))
+			}
+		}
+	}
+
+	if len(env.Variables) > 0 {
+		buf.WriteString("\nVariables:\n")
+		names := make([]string, 0, len(env.Variables))
+		for name := range env.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			variable := env.Variables[name]
+			if variable.ValueSHA256 != "" {
+				buf.WriteString(fmt.Sprintf("  %s = (sensitive value, sha256:%s)\n", name, variable.ValueSHA256))
+			} else {
+				buf.WriteString(fmt.Sprintf("  %s = %s\n", name, variable.Value))
+			}
+		}
+	}
+
+	c.Ui.Output(strings.TrimRight(buf.String(), "\n"))
+	return 0
+}
+
 func (c *ShowCommand) Help() string {
 	helpText := `
 Usage: terraform [global options] show [options] [path]
@@ -71,6 +344,28 @@ Options:
   -no-color           If specified, output won't contain any color.
   -json               If specified, output the Terraform plan or state in
                       a machine-readable form.
+  -sarif              If specified, output the planned resource changes as
+                      a SARIF 2.1.0 document, suitable for ingestion by
+                      code scanning tools. Only supported when showing a
+                      saved plan file.
+  -html               If specified, output the planned resource changes as
+                      a self-contained HTML report with collapsible diffs,
+                      suitable for review outside the terminal. Only
+                      supported when showing a saved plan file.
+  -last               If specified, output the saved JSON summary of the
+                      most recent operation recorded in this working
+                      directory's local history instead of a state or plan.
+                      See the "terraform history" command for the full list
+                      of recorded operations.
+  -timings            If specified, read the given path as a JSON log file
+                      captured from a command run with the -json option and
+                      print a per-resource timing summary, slowest first,
+                      including each resource's elapsed and waiting time.
+  -metadata           If specified, output the run environment recorded in
+                      the given saved plan file: the Terraform version,
+                      workspace, provider selections, and input variable
+                      values the plan was created with, with sensitive
+                      variable values recorded only as a hash.
 
 `
 	return strings.TrimSpace(helpText)
@@ -44,6 +44,127 @@ func TestShow_badArgs(t *testing.T) {
 	}
 }
 
+func TestShow_timings(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "apply.log")
+	err := os.WriteFile(logPath, []byte(`
+{"@level":"info","@message":"test_instance.fast: Creating...","type":"apply_start","hook":{"resource":{"addr":"test_instance.fast"},"action":"create"}}
+{"@level":"info","@message":"test_instance.slow: Creation complete after 12s","type":"apply_complete","hook":{"resource":{"addr":"test_instance.slow"},"action":"create","elapsed_seconds":12,"elapsed_milliseconds":12000,"waiting_milliseconds":3000}}
+{"@level":"info","@message":"test_instance.fast: Creation complete after 1s","type":"apply_complete","hook":{"resource":{"addr":"test_instance.fast"},"action":"create","elapsed_seconds":1,"elapsed_milliseconds":1000,"waiting_milliseconds":0}}
+{"@level":"info","@message":"test_instance.bad: Destruction errored after 2s","type":"apply_errored","hook":{"resource":{"addr":"test_instance.bad"},"action":"delete","elapsed_seconds":2,"elapsed_milliseconds":2000,"waiting_milliseconds":500,"retries":1}}
+not json at all
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-timings", logPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\nstderr: %s", code, output.Stderr())
+	}
+
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		"test_instance.slow",
+		"test_instance.fast",
+		"delete (errored)",
+		"12s",
+		"500ms",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+
+	// The summary is sorted slowest-first.
+	if slow, fast := strings.Index(got, "test_instance.slow"), strings.Index(got, "test_instance.fast"); slow > fast {
+		t.Errorf("wrong ordering; the slowest resource should be listed first:\n%s", got)
+	}
+}
+
+func TestShow_timingsNoData(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "empty.log")
+	if err := os.WriteFile(logPath, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-timings", logPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("unexpected exit status %d; want 1", code)
+	}
+	if got, want := output.Stderr(), "No timing information found"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in stderr:\n%s", want, got)
+	}
+}
+
+func TestShow_metadata(t *testing.T) {
+	planPath := testPlanFileNoop(t)
+
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-metadata", planPath})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+
+	got := ui.OutputWriter.String()
+	for _, want := range []string{
+		"Terraform version: " + version.String(),
+		"Workspace:         default",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestShow_metadataNoPath(t *testing.T) {
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-metadata"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("unexpected exit status %d; want 1", code)
+	}
+	if got, want := output.Stderr(), "Plan file required"; !strings.Contains(got, want) {
+		t.Errorf("missing %q in stderr:\n%s", want, got)
+	}
+}
+
 func TestShow_noArgsNoState(t *testing.T) {
 	view, done := testView(t)
 	c := &ShowCommand{
@@ -575,8 +696,9 @@ func TestShow_json_output(t *testing.T) {
 			}
 			json.Unmarshal([]byte(byteValue), &want)
 
-			// Disregard format version to reduce needless test fixture churn
+			// Disregard format versions to reduce needless test fixture churn
 			want.FormatVersion = got.FormatVersion
+			want.PriorState.FormatVersion = got.PriorState.FormatVersion
 
 			if !cmp.Equal(got, want) {
 				t.Fatalf("wrong result:\n %v\n", cmp.Diff(got, want))
@@ -585,6 +707,100 @@ func TestShow_json_output(t *testing.T) {
 	}
 }
 
+func TestShow_json_output_deterministic(t *testing.T) {
+	// The JSON plan output promises a deterministic ordering of resources
+	// and child modules, so rendering the same plan repeatedly must always
+	// produce byte-for-byte identical output.
+	fixtureNames := []string{
+		"basic-create",
+		"modules",
+		"nested-modules",
+	}
+
+	for _, fixtureName := range fixtureNames {
+		t.Run(fixtureName, func(t *testing.T) {
+			td := t.TempDir()
+			inputDir := filepath.Join("testdata/show-json", fixtureName)
+			testCopyDir(t, inputDir, td)
+			defer testChdir(t, td)()
+
+			providerSource, close := newMockProviderSource(t, map[string][]string{
+				"test": {"1.2.3"},
+			})
+			defer close()
+
+			p := showFixtureProvider()
+
+			// init
+			ui := new(cli.MockUi)
+			ic := &InitCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					Ui:               ui,
+					ProviderSource:   providerSource,
+				},
+			}
+			if code := ic.Run([]string{}); code != 0 {
+				t.Fatalf("init failed\n%s", ui.ErrorWriter)
+			}
+
+			// plan
+			planView, planDone := testView(t)
+			pc := &PlanCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					View:             planView,
+					ProviderSource:   providerSource,
+				},
+			}
+
+			args := []string{
+				"-out=terraform.plan",
+			}
+
+			code := pc.Run(args)
+			planOutput := planDone(t)
+
+			if code != 0 {
+				t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, planOutput.Stderr())
+			}
+			defer os.Remove("terraform.plan")
+
+			// show the same plan several times, expecting identical output
+			// each time
+			var want string
+			for i := 0; i < 3; i++ {
+				showView, showDone := testView(t)
+				sc := &ShowCommand{
+					Meta: Meta{
+						testingOverrides: metaOverridesForProvider(p),
+						View:             showView,
+						ProviderSource:   providerSource,
+					},
+				}
+
+				args = []string{
+					"-json",
+					"terraform.plan",
+				}
+				code = sc.Run(args)
+				showOutput := showDone(t)
+
+				if code != 0 {
+					t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, showOutput.Stderr())
+				}
+
+				got := showOutput.Stdout()
+				if i == 0 {
+					want = got
+				} else if got != want {
+					t.Fatalf("inconsistent output on run %d\ngot:  %s\nwant: %s", i, got, want)
+				}
+			}
+		})
+	}
+}
+
 func TestShow_json_output_sensitive(t *testing.T) {
 	td := t.TempDir()
 	inputDir := "testdata/show-json-sensitive"
@@ -668,8 +884,9 @@ func TestShow_json_output_sensitive(t *testing.T) {
 	}
 	json.Unmarshal([]byte(byteValue), &want)
 
-	// Disregard format version to reduce needless test fixture churn
+	// Disregard format versions to reduce needless test fixture churn
 	want.FormatVersion = got.FormatVersion
+	want.PriorState.FormatVersion = got.PriorState.FormatVersion
 
 	if !cmp.Equal(got, want) {
 		t.Fatalf("wrong result:\n %v\n", cmp.Diff(got, want))
@@ -764,8 +981,9 @@ func TestShow_json_output_conditions_refresh_only(t *testing.T) {
 	}
 	json.Unmarshal([]byte(byteValue), &want)
 
-	// Disregard format version to reduce needless test fixture churn
+	// Disregard format versions to reduce needless test fixture churn
 	want.FormatVersion = got.FormatVersion
+	want.PriorState.FormatVersion = got.PriorState.FormatVersion
 
 	if !cmp.Equal(got, want) {
 		t.Fatalf("wrong result:\n %v\n", cmp.Diff(got, want))
@@ -851,6 +1069,9 @@ func TestShow_json_output_state(t *testing.T) {
 			}
 			json.Unmarshal([]byte(byteValue), &want)
 
+			// Disregard format version to reduce needless test fixture churn
+			want.FormatVersion = got.FormatVersion
+
 			if !cmp.Equal(got, want) {
 				t.Fatalf("wrong result:\n %v\n", cmp.Diff(got, want))
 			}
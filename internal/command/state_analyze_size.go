@@ -0,0 +1,196 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+	"github.com/mitchellh/cli"
+)
+
+// StateAnalyzeSizeCommand is a Command implementation that reports where the
+// bulk of a state snapshot's serialized size comes from, to help users track
+// down state bloat without external tooling.
+type StateAnalyzeSizeCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateAnalyzeSizeCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var statePath string
+	var top int
+	cmdFlags := c.Meta.defaultFlagSet("state analyze-size")
+	cmdFlags.StringVar(&statePath, "state", "", "path")
+	cmdFlags.IntVar(&top, "top", 10, "how many entries to show in each section")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return cli.RunResultHelp
+	}
+
+	if statePath != "" {
+		c.Meta.statePath = statePath
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	// Get the state
+	env, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+	stateMgr, err := b.StateMgr(env)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+		return 1
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to load state: %s", err))
+		return 1
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		c.Ui.Error(errStateNotFound)
+		return 1
+	}
+
+	c.Ui.Output(analyzeStateSize(state, top))
+	return 0
+}
+
+// analyzeStateSize produces the report text for "terraform state analyze-size",
+// aggregating the serialized attribute sizes of all resource instances by
+// resource type, by module, and by individual attribute.
+func analyzeStateSize(state *states.State, top int) string {
+	byType := make(map[string]int)
+	instancesByType := make(map[string]int)
+	byModule := make(map[string]int)
+	byAttr := make(map[string]int)
+	byInstance := make(map[string]int)
+
+	for _, ms := range state.Modules {
+		moduleKey := ms.Addr.String()
+		if ms.Addr.IsRoot() {
+			moduleKey = "(root module)"
+		}
+		for _, rs := range ms.Resources {
+			typeName := rs.Addr.Resource.Type
+			for key, is := range rs.Instances {
+				objs := []*states.ResourceInstanceObjectSrc{is.Current}
+				for _, dobj := range is.Deposed {
+					objs = append(objs, dobj)
+				}
+				size := 0
+				for _, obj := range objs {
+					if obj == nil {
+						continue
+					}
+					size += len(obj.AttrsJSON)
+					for k, v := range obj.AttrsFlat {
+						size += len(k) + len(v)
+					}
+
+					// Break the attribute data down by top-level attribute,
+					// where possible, since a single large attribute is the
+					// most common cause of state bloat.
+					var attrs map[string]json.RawMessage
+					if err := json.Unmarshal(obj.AttrsJSON, &attrs); err == nil {
+						for name, raw := range attrs {
+							byAttr[fmt.Sprintf("%s.%s", typeName, name)] += len(raw)
+						}
+					}
+				}
+				byType[typeName] += size
+				instancesByType[typeName]++
+				byModule[moduleKey] += size
+				byInstance[rs.Addr.Instance(key).String()] += size
+			}
+		}
+	}
+
+	// Total serialized size includes all of the bookkeeping too, so we
+	// measure it from a full snapshot serialization.
+	totalSize := 0
+	var buf bytes.Buffer
+	if err := statefile.Write(statefile.New(state, "-", 0), &buf); err == nil {
+		totalSize = buf.Len()
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Serialized state snapshot size: %d bytes\n", totalSize)
+
+	writeSection := func(header string, counts map[string]int, format func(name string, size int) string) {
+		fmt.Fprintf(&report, "\n%s\n", header)
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if counts[names[i]] != counts[names[j]] {
+				return counts[names[i]] > counts[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		if len(names) > top {
+			names = names[:top]
+		}
+		for _, name := range names {
+			fmt.Fprintf(&report, "  %s\n", format(name, counts[name]))
+		}
+	}
+
+	writeSection("Largest resource types:", byType, func(name string, size int) string {
+		return fmt.Sprintf("%s: %d bytes across %d instances", name, size, instancesByType[name])
+	})
+	writeSection("Largest modules:", byModule, func(name string, size int) string {
+		return fmt.Sprintf("%s: %d bytes", name, size)
+	})
+	writeSection("Largest resource instances:", byInstance, func(name string, size int) string {
+		return fmt.Sprintf("%s: %d bytes", name, size)
+	})
+	writeSection("Largest attributes (aggregated by resource type):", byAttr, func(name string, size int) string {
+		return fmt.Sprintf("%s: %d bytes", name, size)
+	})
+
+	return report.String()
+}
+
+func (c *StateAnalyzeSizeCommand) Help() string {
+	return stateAnalyzeSizeCommandHelp
+}
+
+func (c *StateAnalyzeSizeCommand) Synopsis() string {
+	return "Report which parts of the state contribute most to its size"
+}
+
+const stateAnalyzeSizeCommandHelp = `
+Usage: terraform [global options] state analyze-size [options]
+
+  Reports the serialized size of the current state broken down by resource
+  type, module, resource instance, and attribute, to help identify what is
+  making a state file large.
+
+Options:
+
+  -state=statefile    Path to a Terraform state file to use to look
+                      up Terraform-managed resources. By default, Terraform
+                      will consult the state of the currently-selected
+                      workspace.
+
+  -top=n              How many entries to show in each section of the
+                      report. Defaults to 10.
+`
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/states/encryption"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+)
+
+// StateDecryptCommand is a Command implementation that decrypts an
+// encrypted local state file back to plaintext, using the encryption
+// configured for the local backend.
+type StateDecryptCommand struct {
+	Meta
+}
+
+func (c *StateDecryptCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var statePath string
+	cmdFlags := c.Meta.defaultFlagSet("state decrypt")
+	cmdFlags.StringVar(&statePath, "state", "", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) > 0 {
+		c.Ui.Error("This command does not expect any positional arguments.")
+		return 1
+	}
+
+	enc, path, diags := c.stateEncryptionSetup(statePath)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.Ui.Error(fmt.Sprintf("There is no state file at %s.", path))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Failed to read state file %s: %s.", path, err))
+		return 1
+	}
+
+	if !encryption.IsEncrypted(raw) {
+		c.Ui.Output(fmt.Sprintf("State file %s is not encrypted.", path))
+		return 0
+	}
+
+	plaintext, err := enc.Decrypt(raw)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to decrypt state file %s: %s.", path, err))
+		return 1
+	}
+
+	// The decrypted content should be a state file; if it isn't then the
+	// envelope didn't come from state encryption and we should leave it be.
+	if _, err := statefile.Read(bytes.NewReader(plaintext)); err != nil {
+		c.Ui.Error(fmt.Sprintf("The decrypted content of %s is not a valid state file: %s.", path, err))
+		return 1
+	}
+
+	if err := replaceStateFile(path, plaintext); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write decrypted state file: %s.", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("State file %s is now stored as plaintext.", path))
+	return 0
+}
+
+func (c *StateDecryptCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] state decrypt [options]
+
+  Decrypt an encrypted local state file back to plaintext, using the
+  encryption settings from the local backend's "encryption" block.
+
+  This is the counterpart to "terraform state encrypt", for migrating a
+  working directory away from state encryption. After decrypting, also
+  remove the "encryption" block from the backend configuration and run
+  "terraform init" again; otherwise the next state write will encrypt
+  the file again.
+
+  If the state file is not encrypted, this command does nothing.
+
+Options:
+
+  -state=PATH         Path to the state file to decrypt. Defaults to the
+                      current workspace's state file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateDecryptCommand) Synopsis() string {
+	return "Decrypt an encrypted local state file"
+}
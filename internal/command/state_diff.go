@@ -0,0 +1,590 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// StateDiffCommand is a Command implementation that compares two state
+// snapshots and describes the differences between them, as a way to answer
+// "what did that apply actually change?" after the fact.
+type StateDiffCommand struct {
+	StateMeta
+}
+
+func (c *StateDiffCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var jsonOutput bool
+	var fromSerial, toSerial int
+	cmdFlags := c.Meta.defaultFlagSet("state diff")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.IntVar(&fromSerial, "from-serial", -1, "earlier state serial to compare")
+	cmdFlags.IntVar(&toSerial, "to-serial", -1, "later state serial to compare")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	args = cmdFlags.Args()
+	var beforeState, afterState *states.State
+	if fromSerial >= 0 || toSerial >= 0 {
+		// Retrieving historical snapshots requires support from the backend,
+		// so the serial options are only available against backends that
+		// retain state version history.
+		b, backendDiags := c.Backend(nil)
+		if backendDiags.HasErrors() {
+			c.showDiagnostics(backendDiags)
+			return 1
+		}
+
+		// This is a read-only command
+		c.ignoreRemoteVersionConflict(b)
+
+		vh, ok := b.(backend.VersionHistory)
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"State versions not supported",
+				"The currently-selected backend does not retain state version history, so the -from-serial and -to-serial options cannot be used. Pass the paths of two local state snapshot files as arguments instead.",
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		workspace, err := c.Workspace()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+			return 1
+		}
+
+		if fromSerial >= 0 {
+			beforeState, diags = c.readStateDiffVersion(vh, workspace, uint64(fromSerial))
+		} else {
+			if len(args) < 1 {
+				c.Ui.Error("The \"before\" snapshot must be given either as a file argument or with -from-serial.\n")
+				return cli.RunResultHelp
+			}
+			var beforeFile *statefile.File
+			beforeFile, diags = c.readStateDiffSnapshot(args[0])
+			if beforeFile != nil {
+				beforeState = beforeFile.State
+			}
+			args = args[1:]
+		}
+
+		switch {
+		case toSerial >= 0:
+			var moreDiags tfdiags.Diagnostics
+			afterState, moreDiags = c.readStateDiffVersion(vh, workspace, uint64(toSerial))
+			diags = diags.Append(moreDiags)
+		case len(args) > 0:
+			afterFile, moreDiags := c.readStateDiffSnapshot(args[0])
+			diags = diags.Append(moreDiags)
+			if afterFile != nil {
+				afterState = afterFile.State
+			}
+			args = args[1:]
+		default:
+			// With no "after" snapshot specified, compare against the
+			// current state of the selected workspace.
+			stateMgr, err := b.StateMgr(workspace)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+				return 1
+			}
+			if err := stateMgr.RefreshState(); err != nil {
+				c.Ui.Error(fmt.Sprintf("Failed to load state: %s", err))
+				return 1
+			}
+			afterState = stateMgr.State()
+		}
+
+		if len(args) != 0 {
+			c.Ui.Error("Unexpected extra arguments.\n")
+			return cli.RunResultHelp
+		}
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	} else {
+		if len(args) != 2 {
+			c.Ui.Error("Expected two arguments: the \"before\" and \"after\" state snapshot files.\n")
+			return cli.RunResultHelp
+		}
+
+		beforeFile, moreDiags := c.readStateDiffSnapshot(args[0])
+		diags = diags.Append(moreDiags)
+		afterFile, moreDiags := c.readStateDiffSnapshot(args[1])
+		diags = diags.Append(moreDiags)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		beforeState = beforeFile.State
+		afterState = afterFile.State
+	}
+
+	diff := diffStates(beforeState, afterState)
+
+	if jsonOutput {
+		jsonDiff, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			// Should never happen because the diff is entirely under our
+			// control here.
+			c.Ui.Error(fmt.Sprintf("Failed to marshal state diff to json: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(jsonDiff))
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(diff.humanString()))
+	return 0
+}
+
+func (c *StateDiffCommand) readStateDiffSnapshot(path string) (*statefile.File, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	f, err := os.Open(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state snapshot",
+			fmt.Sprintf("Cannot read the state snapshot from %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+	defer f.Close()
+	stateFile, err := statefile.Read(f)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state snapshot",
+			fmt.Sprintf("Cannot read the state snapshot from %s: %s.", path, err),
+		))
+		return nil, diags
+	}
+	return stateFile, diags
+}
+
+// readStateDiffVersion retrieves a historical state snapshot retained by a
+// backend with version history, identified by its serial.
+func (c *StateDiffCommand) readStateDiffVersion(vh backend.VersionHistory, workspace string, serial uint64) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	data, err := vh.StateVersion(workspace, serial)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to retrieve state version",
+			fmt.Sprintf("Cannot retrieve the state snapshot with serial %d for workspace %q: %s.", serial, workspace, err),
+		))
+		return nil, diags
+	}
+	stateFile, err := statefile.Read(bytes.NewReader(data))
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read state snapshot",
+			fmt.Sprintf("Cannot read the state snapshot with serial %d for workspace %q: %s.", serial, workspace, err),
+		))
+		return nil, diags
+	}
+	return stateFile.State, diags
+}
+
+const stateDiffFormatVersion = "1.0"
+
+// stateDiffJSON is the top-level object describing the differences between
+// two state snapshots, shared between the human-readable and JSON output.
+type stateDiffJSON struct {
+	FormatVersion   string                    `json:"format_version"`
+	ResourceChanges []stateDiffResourceChange `json:"resource_changes"`
+	OutputChanges   []stateDiffOutputChange   `json:"output_changes"`
+}
+
+type stateDiffResourceChange struct {
+	Address string `json:"address"`
+
+	// Action is one of "create", "update", or "delete", matching the action
+	// names used in the plan JSON output.
+	Action string `json:"action"`
+
+	AttributeChanges []stateDiffAttrChange `json:"attribute_changes,omitempty"`
+}
+
+type stateDiffAttrChange struct {
+	Attribute string `json:"attribute"`
+
+	// Before and After are the JSON representations of the attribute value
+	// in each of the two snapshots, or null when the attribute is absent
+	// from one of them. Sensitive values are masked.
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+type stateDiffOutputChange struct {
+	Name      string          `json:"name"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Sensitive bool            `json:"sensitive,omitempty"`
+}
+
+var sensitiveValueJSON = json.RawMessage(`"(sensitive value)"`)
+
+// diffStates compares two state snapshots and produces a description of the
+// resource instances and output values that differ between them.
+//
+// This comparison is made from the stored attribute data alone, without the
+// provider schemas that would be needed to interpret the snapshots more
+// deeply, so it describes changes per top-level attribute rather than at
+// arbitrary nesting depth.
+func diffStates(before, after *states.State) *stateDiffJSON {
+	diff := &stateDiffJSON{
+		FormatVersion:   stateDiffFormatVersion,
+		ResourceChanges: []stateDiffResourceChange{},
+		OutputChanges:   []stateDiffOutputChange{},
+	}
+
+	beforeObjs := currentResourceObjects(before)
+	afterObjs := currentResourceObjects(after)
+
+	addrs := make([]string, 0, len(beforeObjs))
+	for addr := range beforeObjs {
+		addrs = append(addrs, addr)
+	}
+	for addr := range afterObjs {
+		if _, ok := beforeObjs[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Strings(addrs)
+
+	for _, addr := range addrs {
+		beforeObj := beforeObjs[addr]
+		afterObj := afterObjs[addr]
+		switch {
+		case beforeObj == nil:
+			diff.ResourceChanges = append(diff.ResourceChanges, stateDiffResourceChange{
+				Address:          addr,
+				Action:           "create",
+				AttributeChanges: diffResourceAttrs(nil, afterObj),
+			})
+		case afterObj == nil:
+			diff.ResourceChanges = append(diff.ResourceChanges, stateDiffResourceChange{
+				Address:          addr,
+				Action:           "delete",
+				AttributeChanges: diffResourceAttrs(beforeObj, nil),
+			})
+		default:
+			attrChanges := diffResourceAttrs(beforeObj, afterObj)
+			if len(attrChanges) == 0 {
+				continue
+			}
+			diff.ResourceChanges = append(diff.ResourceChanges, stateDiffResourceChange{
+				Address:          addr,
+				Action:           "update",
+				AttributeChanges: attrChanges,
+			})
+		}
+	}
+
+	diff.OutputChanges = diffOutputValues(before, after)
+
+	return diff
+}
+
+// currentResourceObjects returns the current object for each resource
+// instance in the given state, keyed by the instance address. Deposed
+// objects are not included, since they are transient by definition.
+func currentResourceObjects(state *states.State) map[string]*states.ResourceInstanceObjectSrc {
+	ret := make(map[string]*states.ResourceInstanceObjectSrc)
+	if state == nil {
+		return ret
+	}
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				if is.Current == nil {
+					continue
+				}
+				ret[rs.Addr.Instance(key).String()] = is.Current
+			}
+		}
+	}
+	return ret
+}
+
+// diffResourceAttrs compares the top-level attributes of two resource
+// instance objects, either of which may be nil to describe an object that
+// is present in only one of the two snapshots.
+func diffResourceAttrs(before, after *states.ResourceInstanceObjectSrc) []stateDiffAttrChange {
+	beforeAttrs := resourceObjectAttrs(before)
+	afterAttrs := resourceObjectAttrs(after)
+
+	names := make([]string, 0, len(beforeAttrs))
+	for name := range beforeAttrs {
+		names = append(names, name)
+	}
+	for name := range afterAttrs {
+		if _, ok := beforeAttrs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var ret []stateDiffAttrChange
+	for _, name := range names {
+		beforeVal, inBefore := beforeAttrs[name]
+		afterVal, inAfter := afterAttrs[name]
+		if inBefore && inAfter && string(beforeVal) == string(afterVal) {
+			continue
+		}
+		sensitive := attrIsSensitive(before, name) || attrIsSensitive(after, name)
+		change := stateDiffAttrChange{
+			Attribute: name,
+			Before:    beforeVal,
+			After:     afterVal,
+			Sensitive: sensitive,
+		}
+		if sensitive {
+			if inBefore {
+				change.Before = sensitiveValueJSON
+			}
+			if inAfter {
+				change.After = sensitiveValueJSON
+			}
+		}
+		ret = append(ret, change)
+	}
+	return ret
+}
+
+// resourceObjectAttrs returns the top-level attributes of the given object
+// as raw JSON values, or nil if the object is absent or stores its
+// attributes in the legacy flatmap format that we can't interpret here.
+func resourceObjectAttrs(obj *states.ResourceInstanceObjectSrc) map[string]json.RawMessage {
+	if obj == nil || len(obj.AttrsJSON) == 0 {
+		return nil
+	}
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(obj.AttrsJSON, &attrs); err != nil {
+		return nil
+	}
+	return attrs
+}
+
+// attrIsSensitive returns true if any of the sensitive paths recorded for
+// the given object begins at the named top-level attribute.
+func attrIsSensitive(obj *states.ResourceInstanceObjectSrc, name string) bool {
+	if obj == nil {
+		return false
+	}
+	for _, pvm := range obj.AttrSensitivePaths {
+		if len(pvm.Path) == 0 {
+			continue
+		}
+		if step, ok := pvm.Path[0].(cty.GetAttrStep); ok && step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func diffOutputValues(before, after *states.State) []stateDiffOutputChange {
+	beforeOutputs := rootOutputValues(before)
+	afterOutputs := rootOutputValues(after)
+
+	names := make([]string, 0, len(beforeOutputs))
+	for name := range beforeOutputs {
+		names = append(names, name)
+	}
+	for name := range afterOutputs {
+		if _, ok := beforeOutputs[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	ret := []stateDiffOutputChange{}
+	for _, name := range names {
+		beforeOutput := beforeOutputs[name]
+		afterOutput := afterOutputs[name]
+		switch {
+		case beforeOutput == nil:
+			ret = append(ret, stateDiffOutputChange{
+				Name:      name,
+				Action:    "create",
+				After:     outputValueJSON(afterOutput),
+				Sensitive: afterOutput.Sensitive,
+			})
+		case afterOutput == nil:
+			ret = append(ret, stateDiffOutputChange{
+				Name:      name,
+				Action:    "delete",
+				Before:    outputValueJSON(beforeOutput),
+				Sensitive: beforeOutput.Sensitive,
+			})
+		default:
+			if beforeOutput.Value.RawEquals(afterOutput.Value) && beforeOutput.Sensitive == afterOutput.Sensitive {
+				continue
+			}
+			ret = append(ret, stateDiffOutputChange{
+				Name:      name,
+				Action:    "update",
+				Before:    outputValueJSON(beforeOutput),
+				After:     outputValueJSON(afterOutput),
+				Sensitive: beforeOutput.Sensitive || afterOutput.Sensitive,
+			})
+		}
+	}
+	return ret
+}
+
+func rootOutputValues(state *states.State) map[string]*states.OutputValue {
+	if state == nil {
+		return nil
+	}
+	root := state.RootModule()
+	if root == nil {
+		return nil
+	}
+	return root.OutputValues
+}
+
+// outputValueJSON returns the JSON representation of the given output value,
+// masking it if it is marked as sensitive.
+func outputValueJSON(output *states.OutputValue) json.RawMessage {
+	if output.Sensitive {
+		return sensitiveValueJSON
+	}
+	src, err := ctyjson.Marshal(output.Value, output.Value.Type())
+	if err != nil {
+		// Should never happen because the value was previously stored in
+		// a state snapshot, and so must be serializable.
+		return json.RawMessage(`null`)
+	}
+	return json.RawMessage(src)
+}
+
+// humanString renders the diff in a human-oriented format modelled on the
+// plan renderer's change symbols.
+func (d *stateDiffJSON) humanString() string {
+	if len(d.ResourceChanges) == 0 && len(d.OutputChanges) == 0 {
+		return "No differences between the two state snapshots."
+	}
+
+	var buf strings.Builder
+	if len(d.ResourceChanges) > 0 {
+		buf.WriteString("Resource changes:\n")
+		for _, change := range d.ResourceChanges {
+			buf.WriteString(fmt.Sprintf("  %s %s\n", stateDiffActionSymbol(change.Action), change.Address))
+			if change.Action != "update" {
+				continue // attribute detail is most useful for updates
+			}
+			for _, attrChange := range change.AttributeChanges {
+				buf.WriteString(fmt.Sprintf(
+					"      [yellow]~[reset] %s: %s [yellow]->[reset] %s\n",
+					attrChange.Attribute,
+					stateDiffValueStr(attrChange.Before),
+					stateDiffValueStr(attrChange.After),
+				))
+			}
+		}
+	}
+	if len(d.OutputChanges) > 0 {
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("Output changes:\n")
+		for _, change := range d.OutputChanges {
+			switch change.Action {
+			case "create":
+				buf.WriteString(fmt.Sprintf("  [green]+[reset] %s: %s\n", change.Name, stateDiffValueStr(change.After)))
+			case "delete":
+				buf.WriteString(fmt.Sprintf("  [red]-[reset] %s: %s\n", change.Name, stateDiffValueStr(change.Before)))
+			default:
+				buf.WriteString(fmt.Sprintf(
+					"  [yellow]~[reset] %s: %s [yellow]->[reset] %s\n",
+					change.Name,
+					stateDiffValueStr(change.Before),
+					stateDiffValueStr(change.After),
+				))
+			}
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func stateDiffActionSymbol(action string) string {
+	switch action {
+	case "create":
+		return "[green]+[reset]"
+	case "delete":
+		return "[red]-[reset]"
+	default:
+		return "[yellow]~[reset]"
+	}
+}
+
+func stateDiffValueStr(raw json.RawMessage) string {
+	if raw == nil {
+		return "(none)"
+	}
+	return string(raw)
+}
+
+func (c *StateDiffCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] state diff [options] [BEFORE-FILE [AFTER-FILE]]
+
+  Compare two state snapshots and describe the differences between them:
+  resource instances added or removed, attribute-level changes to resource
+  instances present in both, and changes to root module output values.
+  Sensitive values are masked.
+
+  This is useful for answering, after the fact, what a particular apply
+  actually changed, given state snapshots from before and after it.
+
+  By default the snapshots to compare are given as two local state file
+  arguments. If the currently-selected backend retains state version
+  history, such as the "pg" backend with its "history" option enabled,
+  either snapshot can instead be retrieved from the backend by its serial
+  using the -from-serial and -to-serial options. When -from-serial is used
+  and no "after" snapshot is given, the comparison is against the current
+  state of the selected workspace.
+
+Options:
+
+  -json                 Produce output in a machine-readable JSON format.
+
+  -from-serial=SERIAL   Retrieve the "before" snapshot from the backend's
+                        version history instead of a local file.
+
+  -to-serial=SERIAL     Retrieve the "after" snapshot from the backend's
+                        version history instead of a local file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateDiffCommand) Synopsis() string {
+	return "Show the differences between two state snapshots"
+}
@@ -0,0 +1,249 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/lang/marks"
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestStateDiff(t *testing.T) {
+	before := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "unchanged",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"keep"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "removed",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"old"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "changed",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"ami":"ami-old","id":"same","password":"hunter2"}`),
+				AttrSensitivePaths: []cty.PathValueMarks{
+					{
+						Path:  cty.GetAttrPath("password"),
+						Marks: cty.NewValueMarks(marks.Sensitive),
+					},
+				},
+				Status: states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "endpoint"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("old.example.com"), false,
+		)
+	})
+	after := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "unchanged",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"keep"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "added",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"new"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "changed",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"ami":"ami-new","id":"same","password":"correcthorse"}`),
+				AttrSensitivePaths: []cty.PathValueMarks{
+					{
+						Path:  cty.GetAttrPath("password"),
+						Marks: cty.NewValueMarks(marks.Sensitive),
+					},
+				},
+				Status: states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetOutputValue(
+			addrs.OutputValue{Name: "endpoint"}.Absolute(addrs.RootModuleInstance),
+			cty.StringVal("new.example.com"), false,
+		)
+	})
+
+	td := t.TempDir()
+	beforePath := filepath.Join(td, "before.tfstate")
+	afterPath := filepath.Join(td, "after.tfstate")
+	for path, state := range map[string]*states.State{
+		beforePath: before,
+		afterPath:  after,
+	} {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writeStateForTesting(state, f); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	t.Run("human", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &StateDiffCommand{
+			StateMeta: StateMeta{
+				Meta: Meta{Ui: ui},
+			},
+		}
+
+		if code := c.Run([]string{beforePath, afterPath}); code != 0 {
+			t.Fatalf("return code %d, errors:\n%s", code, ui.ErrorWriter.String())
+		}
+
+		output := ui.OutputWriter.String()
+		for _, want := range []string{
+			"+ test_instance.added",
+			"- test_instance.removed",
+			"~ test_instance.changed",
+			`~ ami: "ami-old" -> "ami-new"`,
+			`~ password: "(sensitive value)" -> "(sensitive value)"`,
+			`~ endpoint: "old.example.com" -> "new.example.com"`,
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output does not contain %q:\n%s", want, output)
+			}
+		}
+		for _, unwant := range []string{
+			"test_instance.unchanged",
+			"hunter2",
+			"correcthorse",
+		} {
+			if strings.Contains(output, unwant) {
+				t.Errorf("output should not contain %q:\n%s", unwant, output)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &StateDiffCommand{
+			StateMeta: StateMeta{
+				Meta: Meta{Ui: ui},
+			},
+		}
+
+		if code := c.Run([]string{"-json", beforePath, afterPath}); code != 0 {
+			t.Fatalf("return code %d, errors:\n%s", code, ui.ErrorWriter.String())
+		}
+
+		var got stateDiffJSON
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+			t.Fatalf("failed to parse json output: %s", err)
+		}
+		if got.FormatVersion != stateDiffFormatVersion {
+			t.Errorf("wrong format_version %q; want %q", got.FormatVersion, stateDiffFormatVersion)
+		}
+		if len(got.ResourceChanges) != 3 {
+			t.Fatalf("wrong number of resource changes %d; want 3", len(got.ResourceChanges))
+		}
+		actions := map[string]string{}
+		for _, change := range got.ResourceChanges {
+			actions[change.Address] = change.Action
+		}
+		want := map[string]string{
+			"test_instance.added":   "create",
+			"test_instance.removed": "delete",
+			"test_instance.changed": "update",
+		}
+		for addr, action := range want {
+			if actions[addr] != action {
+				t.Errorf("wrong action %q for %s; want %q", actions[addr], addr, action)
+			}
+		}
+		if len(got.OutputChanges) != 1 || got.OutputChanges[0].Name != "endpoint" || got.OutputChanges[0].Action != "update" {
+			t.Errorf("wrong output changes %#v", got.OutputChanges)
+		}
+		if strings.Contains(ui.OutputWriter.String(), "hunter2") {
+			t.Errorf("json output includes a sensitive value:\n%s", ui.OutputWriter.String())
+		}
+	})
+
+	t.Run("serials unsupported", func(t *testing.T) {
+		// The local backend does not retain state version history, so the
+		// serial options should fail with a clear error.
+		testCwd(t)
+
+		ui := cli.NewMockUi()
+		c := &StateDiffCommand{
+			StateMeta: StateMeta{
+				Meta: Meta{Ui: ui},
+			},
+		}
+
+		if code := c.Run([]string{"-from-serial=41", "-to-serial=42"}); code != 1 {
+			t.Fatalf("return code %d; want 1", code)
+		}
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, "State versions not supported") {
+			t.Errorf("wrong error:\n%s", got)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backendLocal "github.com/hashicorp/terraform/internal/backend/local"
+	"github.com/hashicorp/terraform/internal/states/encryption"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// StateEncryptCommand is a Command implementation that encrypts an existing
+// plaintext local state file in place, using the encryption configured for
+// the local backend.
+type StateEncryptCommand struct {
+	Meta
+}
+
+func (c *StateEncryptCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var statePath string
+	cmdFlags := c.Meta.defaultFlagSet("state encrypt")
+	cmdFlags.StringVar(&statePath, "state", "", "path")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) > 0 {
+		c.Ui.Error("This command does not expect any positional arguments.")
+		return 1
+	}
+
+	enc, path, diags := c.stateEncryptionSetup(statePath)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.Ui.Error(fmt.Sprintf("There is no state file at %s.", path))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Failed to read state file %s: %s.", path, err))
+		return 1
+	}
+
+	if encryption.IsEncrypted(raw) {
+		c.Ui.Output(fmt.Sprintf("State file %s is already encrypted.", path))
+		return 0
+	}
+
+	// Make sure what we're about to replace really is a state file, so a
+	// mistaken path doesn't irreversibly scramble some other file.
+	if _, err := statefile.Read(bytes.NewReader(raw)); err != nil {
+		c.Ui.Error(fmt.Sprintf("The file at %s is not a valid state file: %s.", path, err))
+		return 1
+	}
+
+	encrypted, err := enc.Encrypt(raw)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to encrypt state: %s.", err))
+		return 1
+	}
+
+	if err := replaceStateFile(path, encrypted); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write encrypted state file: %s.", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("State file %s is now encrypted.", path))
+	return 0
+}
+
+// stateEncryptionSetup loads the backend to obtain the configured state
+// encryption and the path of the current workspace's state file. It fails
+// unless the working directory uses the local backend and its configuration
+// includes an "encryption" block.
+func (m *Meta) stateEncryptionSetup(overridePath string) (*encryption.Encryptor, string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	b, backendDiags := m.Backend(nil)
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return nil, "", diags
+	}
+
+	local, ok := b.(*backendLocal.Local)
+	if !ok || local.Backend != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State encryption is not supported by this backend",
+			"State encryption at rest is available only for state files stored by the local backend.",
+		))
+		return nil, "", diags
+	}
+	if local.StateEncryption == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"State encryption is not configured",
+			`The local backend configuration has no "encryption" block, so there is no key to encrypt or decrypt state with. Add an "encryption" block to the backend configuration and run "terraform init" first.`,
+		))
+		return nil, "", diags
+	}
+
+	path := overridePath
+	if path == "" {
+		env, err := m.Workspace()
+		if err != nil {
+			diags = diags.Append(fmt.Errorf("error selecting workspace: %s", err))
+			return nil, "", diags
+		}
+		path, _, _ = local.StatePaths(env)
+	}
+
+	return local.StateEncryption, path, diags
+}
+
+// replaceStateFile writes the given content to a temporary file next to
+// path and then renames it into place, so that a failure partway through
+// leaves the original file intact. The original file's permissions are
+// preserved.
+func replaceStateFile(path string, content []byte) error {
+	mode := os.FileMode(0600)
+	if stat, err := os.Stat(path); err == nil {
+		mode = stat.Mode()
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+func (c *StateEncryptCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] state encrypt [options]
+
+  Encrypt an existing plaintext local state file in place, using the
+  encryption settings from the local backend's "encryption" block.
+
+  This is a one-time migration command for adopting state encryption on
+  a working directory that already has state: once the "encryption" block
+  has been added to the backend configuration, Terraform transparently
+  encrypts new state snapshots as it writes them, but the existing
+  snapshot on disk remains plaintext until it is next written. This
+  command encrypts it immediately.
+
+  If the state file is already encrypted, this command does nothing.
+
+Options:
+
+  -state=PATH         Path to the state file to encrypt. Defaults to the
+                      current workspace's state file.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateEncryptCommand) Synopsis() string {
+	return "Encrypt a local state file at rest"
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/encryption"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+)
+
+func TestStateEncrypt_notConfigured(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	testStateFileDefault(t, testState())
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &StateEncryptCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("wrong exit code; expected 1, got %d", code)
+	}
+	if got := ui.ErrorWriter.String(); !strings.Contains(got, "State encryption is not configured") {
+		t.Errorf("missing expected error, got:\n%s", got)
+	}
+}
+
+func TestStateEncryptDecrypt_roundTrip(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	// An empty state avoids any provider installation during init while
+	// still exercising the encrypt/decrypt round trip.
+	state := states.NewState()
+	testStateFileDefault(t, state)
+
+	config := `
+terraform {
+  backend "local" {
+    encryption {
+      passphrase = "correct horse battery staple"
+      iterations = 1000
+    }
+  }
+}
+`
+	if err := ioutil.WriteFile("main.tf", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	ic := &InitCommand{Meta: Meta{Ui: ui, View: view, testingOverrides: metaOverridesForProvider(testProvider())}}
+	if code := ic.Run([]string{"-backend=true", "-input=false"}); code != 0 {
+		t.Fatalf("init failed: %s", ui.ErrorWriter.String())
+	}
+
+	// Encrypt the existing plaintext state file in place.
+	ui = new(cli.MockUi)
+	c := &StateEncryptCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("wrong exit code; expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+	}
+	raw, err := ioutil.ReadFile(DefaultStateFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encryption.IsEncrypted(raw) {
+		t.Fatalf("state file is not encrypted after \"state encrypt\":\n%s", raw)
+	}
+
+	// Running again should be a no-op.
+	ui = new(cli.MockUi)
+	c = &StateEncryptCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("wrong exit code; expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "already encrypted") {
+		t.Errorf("missing already-encrypted message, got:\n%s", got)
+	}
+
+	// Decrypt it back to plaintext.
+	ui = new(cli.MockUi)
+	dc := &StateDecryptCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := dc.Run(nil); code != 0 {
+		t.Fatalf("wrong exit code; expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+	}
+	raw, err = ioutil.ReadFile(DefaultStateFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encryption.IsEncrypted(raw) {
+		t.Fatal("state file is still encrypted after \"state decrypt\"")
+	}
+	f, err := statefile.Read(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("decrypted state file is not readable: %s", err)
+	}
+	if !f.State.Equal(state) {
+		t.Fatal("decrypted state doesn't match the original state")
+	}
+}
+
+func TestStateDecrypt_notEncrypted(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	testStateFileDefault(t, states.NewState())
+
+	config := `
+terraform {
+  backend "local" {
+    encryption {
+      passphrase = "correct horse battery staple"
+      iterations = 1000
+    }
+  }
+}
+`
+	if err := ioutil.WriteFile("main.tf", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	ic := &InitCommand{Meta: Meta{Ui: ui, View: view, testingOverrides: metaOverridesForProvider(testProvider())}}
+	if code := ic.Run([]string{"-backend=true", "-input=false"}); code != 0 {
+		t.Fatalf("init failed: %s", ui.ErrorWriter.String())
+	}
+
+	ui = new(cli.MockUi)
+	dc := &StateDecryptCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := dc.Run(nil); code != 0 {
+		t.Fatalf("wrong exit code; expected 0, got %d\nstderr:\n%s", code, ui.ErrorWriter.String())
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "not encrypted") {
+		t.Errorf("missing not-encrypted message, got:\n%s", got)
+	}
+}
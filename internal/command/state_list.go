@@ -1,10 +1,12 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/command/addrfilter"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 	"github.com/mitchellh/cli"
@@ -23,6 +25,8 @@ func (c *StateListCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("state list")
 	cmdFlags.StringVar(&statePath, "state", "", "path")
 	lookupId := cmdFlags.String("id", "", "Restrict output to paths with a resource having the specified ID.")
+	jsonOutput := cmdFlags.Bool("json", false, "produce JSON output")
+	filterExpr := cmdFlags.String("filter", "", "filter expression")
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return cli.RunResultHelp
@@ -77,6 +81,45 @@ func (c *StateListCommand) Run(args []string) int {
 		return 1
 	}
 
+	if *filterExpr != "" {
+		filter, err := addrfilter.Parse(*filterExpr)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid filter expression: %s", err))
+			return 1
+		}
+		kept := addrs[:0]
+		for _, addr := range addrs {
+			if matchesResourceInstanceFilter(state, filter, addr) {
+				kept = append(kept, addr)
+			}
+		}
+		addrs = kept
+	}
+
+	if *jsonOutput {
+		output := stateListOutput{
+			FormatVersion: "1.0",
+			Resources:     []stateListResource{},
+		}
+		for _, addr := range addrs {
+			if is := state.ResourceInstance(addr); is != nil {
+				if *lookupId == "" || *lookupId == states.LegacyInstanceObjectID(is.Current) {
+					output.Resources = append(output.Resources, stateListResourceForAddr(state, addr))
+				}
+			}
+		}
+		j, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			// Should never happen, since the output is a plain data structure.
+			c.Ui.Error(fmt.Sprintf("Failed to serialize output as JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(j))
+
+		c.showDiagnostics(diags)
+		return 0
+	}
+
 	for _, addr := range addrs {
 		if is := state.ResourceInstance(addr); is != nil {
 			if *lookupId == "" || *lookupId == states.LegacyInstanceObjectID(is.Current) {
@@ -90,6 +133,59 @@ func (c *StateListCommand) Run(args []string) int {
 	return 0
 }
 
+// stateListOutput is the top-level object for the -json output of
+// "terraform state list".
+type stateListOutput struct {
+	FormatVersion string              `json:"format_version"`
+	Resources     []stateListResource `json:"resources"`
+}
+
+// stateListResource describes one listed resource instance, using the same
+// property names as the jsonstate resource representation so that consumers
+// can share field handling between the two.
+type stateListResource struct {
+	// Address is the absolute resource instance address.
+	Address string `json:"address"`
+
+	// ModuleAddress is the module portion of the address. Omitted for
+	// instances in the root module.
+	ModuleAddress string `json:"module_address,omitempty"`
+
+	// Mode is either "managed" or "data".
+	Mode string `json:"mode"`
+
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// Index is omitted for a resource not using "count" or "for_each".
+	Index addrs.InstanceKey `json:"index,omitempty"`
+
+	ProviderName string `json:"provider_name"`
+}
+
+// stateListResourceForAddr builds the -json representation of one resource
+// instance in the given state.
+func stateListResourceForAddr(state *states.State, addr addrs.AbsResourceInstance) stateListResource {
+	rs := state.Resource(addr.ContainingResource())
+	r := stateListResource{
+		Address:      addr.String(),
+		Type:         addr.Resource.Resource.Type,
+		Name:         addr.Resource.Resource.Name,
+		Index:        addr.Resource.Key,
+		ProviderName: rs.ProviderConfig.Provider.String(),
+	}
+	if !addr.Module.IsRoot() {
+		r.ModuleAddress = addr.Module.String()
+	}
+	switch addr.Resource.Resource.Mode {
+	case addrs.DataResourceMode:
+		r.Mode = "data"
+	default:
+		r.Mode = "managed"
+	}
+	return r
+}
+
 func (c *StateListCommand) Help() string {
 	helpText := `
 Usage: terraform [global options] state list [options] [address...]
@@ -121,6 +217,20 @@ Options:
                       resource types have an attribute named "id" whose value
                       equals the given id string.
 
+  -filter=EXPR        Filters the results to include only instances matching
+                      the given filter expression, which compares address
+                      components against quoted strings, such as:
+                          -filter='type == "aws_iam_role" && module =~ "^module\.network"'
+                      The components are addr, module, mode, type, name, key,
+                      and provider. The operators are == and != for exact
+                      comparison, =~ and !~ for regular expression matching,
+                      combined with &&, ||, ! and parentheses.
+
+  -json               If specified, output each resource instance's address,
+                      provider, mode, module path, and instance key as a
+                      machine-readable JSON document instead of bare
+                      addresses.
+
 `
 	return strings.TrimSpace(helpText)
 }
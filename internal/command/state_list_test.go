@@ -35,6 +35,34 @@ func TestStateList(t *testing.T) {
 	}
 }
 
+func TestStateListJSON(t *testing.T) {
+	state := testState()
+	statePath := testStateFile(t, state)
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	c := &StateListCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-json",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	expected := strings.TrimSpace(testStateListJSONOutput) + "\n"
+	actual := ui.OutputWriter.String()
+	if actual != expected {
+		t.Fatalf("Expected:\n%q\n\nTo equal: %q", actual, expected)
+	}
+}
+
 func TestStateListWithID(t *testing.T) {
 	state := testState()
 	statePath := testStateFile(t, state)
@@ -268,3 +296,71 @@ func TestStateList_modules(t *testing.T) {
 const testStateListOutput = `
 test_instance.foo
 `
+
+const testStateListJSONOutput = `
+{
+  "format_version": "1.0",
+  "resources": [
+    {
+      "address": "test_instance.foo",
+      "mode": "managed",
+      "type": "test_instance",
+      "name": "foo",
+      "provider_name": "registry.terraform.io/hashicorp/test"
+    }
+  ]
+}
+`
+
+func TestStateList_filter(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("state-list-nested-modules"), td)
+	defer testChdir(t, td)()
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	c := &StateListCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+		},
+	}
+
+	t.Run("filter by module", func(t *testing.T) {
+		args := []string{`-filter=module =~ "^module\.nest"`}
+		if code := c.Run(args); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+		}
+
+		expected := "module.nest.test_instance.nest\nmodule.nest.module.subnest.test_instance.subnest\n"
+		actual := ui.OutputWriter.String()
+		if actual != expected {
+			t.Fatalf("Expected:\n%q\n\nTo equal: %q", actual, expected)
+		}
+	})
+
+	t.Run("filter by name and module instance", func(t *testing.T) {
+		ui.OutputWriter.Reset()
+		args := []string{`-filter=name == "count" && module == "module.count[1]"`}
+		if code := c.Run(args); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+		}
+
+		expected := "module.count[1].test_instance.count\n"
+		actual := ui.OutputWriter.String()
+		if actual != expected {
+			t.Fatalf("Expected:\n%q\n\nTo equal: %q", actual, expected)
+		}
+	})
+
+	t.Run("invalid filter expression", func(t *testing.T) {
+		args := []string{`-filter=boop`}
+		if code := c.Run(args); code != 1 {
+			t.Fatalf("bad: %d", code)
+		}
+		if msg := ui.ErrorWriter.String(); !strings.Contains(msg, "Invalid filter expression") {
+			t.Fatalf("unexpected error output:\n%s", msg)
+		}
+	})
+}
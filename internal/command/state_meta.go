@@ -91,6 +91,48 @@ func (c *StateMeta) State() (statemgr.Full, error) {
 	return realState, nil
 }
 
+// StateForWorkspace returns the state manager for the given workspace of the
+// currently-selected backend, configured with timestamped backups in the same
+// way as State configures the manager for the current workspace.
+func (c *StateMeta) StateForWorkspace(workspace string) (statemgr.Full, error) {
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		return nil, backendDiags.Err()
+	}
+
+	// Check remote Terraform version is compatible
+	remoteVersionDiags := c.remoteVersionCheck(b, workspace)
+	c.showDiagnostics(remoteVersionDiags)
+	if remoteVersionDiags.HasErrors() {
+		return nil, fmt.Errorf("Error checking remote Terraform version")
+	}
+
+	s, err := b.StateMgr(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get a local backend
+	localRaw, backendDiags := c.Backend(&BackendOpts{ForceLocal: true})
+	if backendDiags.HasErrors() {
+		// This should never fail
+		panic(backendDiags.Err())
+	}
+	localB := localRaw.(*backendLocal.Local)
+	_, stateOutPath, _ := localB.StatePaths(workspace)
+
+	if lb, ok := s.(*statemgr.Filesystem); ok {
+		backupPath := fmt.Sprintf(
+			"%s.%d%s",
+			stateOutPath,
+			time.Now().UTC().Unix(),
+			DefaultBackupExtension)
+		lb.SetBackupPath(backupPath)
+	}
+
+	return s, nil
+}
+
 func (c *StateMeta) lookupResourceInstanceAddr(state *states.State, allowMissing bool, addrStr string) ([]addrs.AbsResourceInstance, tfdiags.Diagnostics) {
 	target, diags := addrs.ParseTargetStr(addrStr)
 	if diags.HasErrors() {
@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform/internal/addrs"
@@ -25,14 +26,18 @@ func (c *StateMvCommand) Run(args []string) int {
 	var backupPathOut, statePathOut string
 
 	var dryRun bool
+	var pattern bool
+	var toWorkspace string
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state mv")
 	cmdFlags.BoolVar(&dryRun, "dry-run", false, "dry run")
+	cmdFlags.BoolVar(&pattern, "pattern", false, "treat the addresses as wildcard patterns")
 	cmdFlags.StringVar(&c.backupPath, "backup", "-", "backup")
 	cmdFlags.StringVar(&backupPathOut, "backup-out", "-", "backup")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock states")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
 	cmdFlags.StringVar(&c.statePath, "state", "", "path")
 	cmdFlags.StringVar(&statePathOut, "state-out", "", "path")
+	cmdFlags.StringVar(&toWorkspace, "to-workspace", "", "destination workspace")
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
@@ -48,6 +53,18 @@ func (c *StateMvCommand) Run(args []string) int {
 		return 1
 	}
 
+	if diags := c.requireWritableWorkspace("move resource instances"); diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if toWorkspace != "" {
+		if diags := c.validateToWorkspace(toWorkspace, statePathOut); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
 	// If backup or backup-out options are set
 	// and the state option is not set, make sure
 	// the backend is local
@@ -154,14 +171,70 @@ func (c *StateMvCommand) Run(args []string) int {
 		}
 	}
 
+	if toWorkspace != "" {
+		stateToMgr, err = c.StateForWorkspace(toWorkspace)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
+			return 1
+		}
+
+		if c.stateLock {
+			stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+			if diags := stateLocker.Lock(stateToMgr, "state-mv"); diags.HasErrors() {
+				c.showDiagnostics(diags)
+				return 1
+			}
+			defer func() {
+				if diags := stateLocker.Unlock(); diags.HasErrors() {
+					c.showDiagnostics(diags)
+				}
+			}()
+		}
+
+		if err := stateToMgr.RefreshState(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to refresh destination state: %s", err))
+			return 1
+		}
+
+		stateTo = stateToMgr.State()
+		if stateTo == nil {
+			stateTo = states.NewState()
+		}
+	}
+
 	var diags tfdiags.Diagnostics
-	sourceAddr, moreDiags := c.lookupSingleStateObjectAddr(stateFrom, args[0])
-	diags = diags.Append(moreDiags)
-	destAddr, moreDiags := c.lookupSingleStateObjectAddr(stateFrom, args[1])
-	diags = diags.Append(moreDiags)
-	if diags.HasErrors() {
-		c.showDiagnostics(diags)
-		return 1
+
+	const msgInvalidSource = "Invalid source address"
+	const msgInvalidTarget = "Invalid target address"
+
+	var moves []stateMovePair
+	if pattern {
+		var moreDiags tfdiags.Diagnostics
+		moves, moreDiags = c.expandMovePattern(stateFrom, args[0], args[1])
+		diags = diags.Append(moreDiags)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if len(moves) == 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidSource,
+				fmt.Sprintf("Cannot move %s: the pattern does not match anything in the current state.", args[0]),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+	} else {
+		sourceAddr, moreDiags := c.lookupSingleStateObjectAddr(stateFrom, args[0])
+		diags = diags.Append(moreDiags)
+		destAddr, moreDiags := c.lookupSingleStateObjectAddr(stateFrom, args[1])
+		diags = diags.Append(moreDiags)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		moves = []stateMovePair{{From: sourceAddr, To: destAddr}}
 	}
 
 	prefix := "Move"
@@ -169,25 +242,36 @@ func (c *StateMvCommand) Run(args []string) int {
 		prefix = "Would move"
 	}
 
-	const msgInvalidSource = "Invalid source address"
-	const msgInvalidTarget = "Invalid target address"
-
 	var moved int
 	ssFrom := stateFrom.SyncWrapper()
-	sourceAddrs := c.sourceObjectAddrs(stateFrom, sourceAddr)
-	if len(sourceAddrs) == 0 {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			msgInvalidSource,
-			fmt.Sprintf("Cannot move %s: does not match anything in the current state.", sourceAddr),
-		))
-		c.showDiagnostics(diags)
-		return 1
+	var expandedMoves []stateMovePair
+	for _, move := range moves {
+		expanded := c.sourceObjectAddrs(stateFrom, move.From)
+		if len(expanded) == 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidSource,
+				fmt.Sprintf("Cannot move %s: does not match anything in the current state.", move.From),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		for _, addr := range expanded {
+			// Each expanded source keeps the requested (pre-expansion) source
+			// too, since module child moves need it to compute the target.
+			expandedMoves = append(expandedMoves, stateMovePair{
+				Search: move.From,
+				From:   addr,
+				To:     move.To,
+			})
+		}
 	}
-	for _, rawAddrFrom := range sourceAddrs {
+	for _, move := range expandedMoves {
+		rawAddrFrom := move.From
+		destAddr := move.To
 		switch addrFrom := rawAddrFrom.(type) {
 		case addrs.ModuleInstance:
-			search := sourceAddr.(addrs.ModuleInstance)
+			search := move.Search.(addrs.ModuleInstance)
 			addrTo, ok := destAddr.(addrs.ModuleInstance)
 			if !ok {
 				diags = diags.Append(tfdiags.Sourceless(
@@ -322,7 +406,7 @@ func (c *StateMvCommand) Run(args []string) int {
 			}
 
 			moved++
-			c.Ui.Output(fmt.Sprintf("%s %q to %q", prefix, addrFrom.String(), args[1]))
+			c.Ui.Output(fmt.Sprintf("%s %q to %q", prefix, addrFrom.String(), addrTo.String()))
 			if !dryRun {
 				fromResourceAddr := addrFrom.ContainingResource()
 				fromResource := ssFrom.Resource(fromResourceAddr)
@@ -460,6 +544,231 @@ func (c *StateMvCommand) sourceObjectAddrs(state *states.State, matched addrs.Ta
 	return ret
 }
 
+// stateMovePair is one requested move from a source address to a destination
+// address. Search records the source as originally requested, before
+// expansion by sourceObjectAddrs, which module moves need in order to
+// compute the targets for child modules.
+type stateMovePair struct {
+	Search addrs.Targetable
+	From   addrs.Targetable
+	To     addrs.Targetable
+}
+
+// expandMovePattern interprets the two given address patterns, where each "*"
+// matches any sequence of characters, against the modules and resources
+// tracked in the given state, and returns one concrete move for each matched
+// object with the matched text substituted into the destination pattern.
+func (c *StateMvCommand) expandMovePattern(state *states.State, fromPattern, toPattern string) ([]stateMovePair, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	const msgInvalidPattern = "Invalid move pattern"
+
+	fromCount := strings.Count(fromPattern, "*")
+	if fromCount == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			msgInvalidPattern,
+			fmt.Sprintf("The source pattern %q does not contain any \"*\" wildcards. Omit the -pattern option to move a single object.", fromPattern),
+		))
+		return nil, diags
+	}
+	if toCount := strings.Count(toPattern, "*"); toCount != fromCount {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			msgInvalidPattern,
+			fmt.Sprintf("The destination pattern %q must contain the same number of \"*\" wildcards as the source pattern, so that each matched portion of a source address has a place in the destination address.", toPattern),
+		))
+		return nil, diags
+	}
+
+	// We match against whole modules and whole resources, since those are
+	// the units that "terraform state mv" knows how to move. Matched modules
+	// take their resources and child modules with them, so we don't also
+	// match the objects inside them separately.
+	var allMatchedModules []addrs.ModuleInstance
+	for _, mod := range state.Modules {
+		if mod.Addr.IsRoot() {
+			continue
+		}
+		if _, ok := matchAddrPattern(fromPattern, mod.Addr.String()); ok {
+			allMatchedModules = append(allMatchedModules, mod.Addr)
+		}
+	}
+	var matchedModules []addrs.ModuleInstance
+	var candidates []string
+	for _, modAddr := range allMatchedModules {
+		contained := false
+		for _, other := range allMatchedModules {
+			if !other.Equal(modAddr) && other.TargetContains(modAddr) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			matchedModules = append(matchedModules, modAddr)
+			candidates = append(candidates, modAddr.String())
+		}
+	}
+	for _, mod := range state.Modules {
+		for _, rs := range mod.Resources {
+			addrStr := rs.Addr.String()
+			if _, ok := matchAddrPattern(fromPattern, addrStr); !ok {
+				continue
+			}
+			contained := false
+			for _, modAddr := range matchedModules {
+				if modAddr.TargetContains(rs.Addr) {
+					contained = true
+					break
+				}
+			}
+			if !contained {
+				candidates = append(candidates, addrStr)
+			}
+		}
+	}
+	sort.Strings(candidates)
+
+	var ret []stateMovePair
+	seenDests := make(map[string]string, len(candidates))
+	for _, fromStr := range candidates {
+		caps, _ := matchAddrPattern(fromPattern, fromStr)
+		toStr := substAddrPattern(toPattern, caps)
+
+		fromAddr, moreDiags := c.lookupSingleStateObjectAddr(state, fromStr)
+		diags = diags.Append(moreDiags)
+		toAddr, moreDiags := c.lookupSingleStateObjectAddr(state, toStr)
+		if moreDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidPattern,
+				fmt.Sprintf("Moving %s produces the invalid destination address %q. Adjust the destination pattern so that each matched object maps to a valid address.", fromStr, toStr),
+			))
+		}
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		if prev, exists := seenDests[toStr]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				msgInvalidPattern,
+				fmt.Sprintf("Both %s and %s would move to %s. Adjust the patterns so that each matched object maps to a distinct destination address.", prev, fromStr, toStr),
+			))
+			return nil, diags
+		}
+		seenDests[toStr] = fromStr
+
+		ret = append(ret, stateMovePair{From: fromAddr, To: toAddr})
+	}
+	return ret, diags
+}
+
+// matchAddrPattern matches the given address against a pattern where each "*"
+// matches any sequence of characters, returning the text matched by each
+// wildcard in order.
+func matchAddrPattern(pattern, addr string) ([]string, bool) {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(addr, parts[0]) {
+		return nil, false
+	}
+	rest := addr[len(parts[0]):]
+	caps := make([]string, 0, len(parts)-1)
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 {
+			// The final literal must anchor at the end of the address.
+			if !strings.HasSuffix(rest, part) {
+				return nil, false
+			}
+			caps = append(caps, rest[:len(rest)-len(part)])
+			rest = ""
+			continue
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return nil, false
+		}
+		caps = append(caps, rest[:idx])
+		rest = rest[idx+len(part):]
+	}
+	return caps, true
+}
+
+// substAddrPattern replaces each "*" in the given pattern with the
+// corresponding capture, which must be the same length as the number of
+// wildcards in the pattern.
+func substAddrPattern(pattern string, caps []string) string {
+	parts := strings.Split(pattern, "*")
+	var buf strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			buf.WriteString(caps[i-1])
+		}
+		buf.WriteString(part)
+	}
+	return buf.String()
+}
+
+// validateToWorkspace checks that the given -to-workspace option value names
+// a suitable destination workspace in the currently-selected backend.
+func (c *StateMvCommand) validateToWorkspace(toWorkspace, statePathOut string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if c.statePath != "" || statePathOut != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid command line options",
+			"The -to-workspace option moves objects to another workspace of the current backend, so it cannot be combined with the legacy -state and -state-out options.",
+		))
+		return diags
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Error selecting workspace: %s", err))
+		return diags
+	}
+	if toWorkspace == workspace {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid destination workspace",
+			fmt.Sprintf("The -to-workspace option must name a workspace other than the currently-selected workspace %q.", workspace),
+		))
+		return diags
+	}
+
+	b, backendDiags := c.Backend(nil)
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return diags
+	}
+
+	workspaces, err := b.Workspaces()
+	if err == backend.ErrWorkspacesNotSupported {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Workspaces not supported",
+			"The -to-workspace option cannot be used because the currently-selected backend does not support multiple workspaces.",
+		))
+		return diags
+	}
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Error listing workspaces: %s", err))
+		return diags
+	}
+	for _, ws := range workspaces {
+		if ws == toWorkspace {
+			return diags
+		}
+	}
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Invalid destination workspace",
+		fmt.Sprintf("The current backend has no workspace named %q. Create it first using \"terraform workspace new %s\".", toWorkspace, toWorkspace),
+	))
+	return diags
+}
+
 func (c *StateMvCommand) validateResourceMove(addrFrom, addrTo addrs.AbsResource) tfdiags.Diagnostics {
 	const msgInvalidRequest = "Invalid state move request"
 
@@ -522,6 +831,14 @@ Options:
   -dry-run                If set, prints out what would've been moved but doesn't
                           actually move anything.
 
+  -pattern                Treat SOURCE and DESTINATION as patterns in which
+                          each "*" wildcard matches any sequence of characters
+                          in an address. Every module or resource in the state
+                          whose address matches the source pattern is moved to
+                          the address produced by substituting the matched text
+                          into the destination pattern. Both patterns must
+                          contain the same number of wildcards.
+
   -lock=false             Don't hold a state lock during the operation. This is
                           dangerous if others might concurrently run commands
                           against the same workspace.
@@ -531,6 +848,13 @@ Options:
   -ignore-remote-version  A rare option used for the remote backend only. See
                           the remote backend documentation for more information.
 
+  -to-workspace=name      Move the matched objects to the given workspace of
+                          the current backend instead of within the current
+                          workspace. Both workspaces are locked for the
+                          duration of the operation, and the destination
+                          workspace must already exist. This option cannot be
+                          combined with -state or -state-out.
+
   -state, state-out, and -backup are legacy options supported for the local
   backend only. For more information, see the local backend's documentation.
 
@@ -43,6 +43,11 @@ func (c *StatePushCommand) Run(args []string) int {
 		return 1
 	}
 
+	if diags := c.requireWritableWorkspace("push state snapshots"); diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	// Determine our reader for the input state. This is the filepath
 	// or stdin if "-" is given.
 	var r io.Reader = os.Stdin
@@ -49,6 +49,12 @@ func (c *StateReplaceProviderCommand) Run(args []string) int {
 
 	var diags tfdiags.Diagnostics
 
+	diags = diags.Append(c.requireWritableWorkspace("replace providers"))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	// Parse from/to arguments into providers
 	from, fromDiags := addrs.ParseProviderSourceString(args[0])
 	if fromDiags.HasErrors() {
@@ -0,0 +1,189 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/command/arguments"
+	"github.com/hashicorp/terraform/internal/command/clistate"
+	"github.com/hashicorp/terraform/internal/command/views"
+	"github.com/hashicorp/terraform/internal/states/statefile"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
+	"github.com/mitchellh/cli"
+)
+
+// StateRestoreVersionCommand is a Command implementation that restores a
+// prior state snapshot retained by a backend with version history.
+type StateRestoreVersionCommand struct {
+	Meta
+	StateMeta
+}
+
+func (c *StateRestoreVersionCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	var flagVersion int64
+	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state restore-version")
+	cmdFlags.Int64Var(&flagVersion, "version", -1, "state serial to restore")
+	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	args = cmdFlags.Args()
+
+	if len(args) != 0 {
+		c.Ui.Error("No arguments expected.\n")
+		return cli.RunResultHelp
+	}
+
+	if diags := c.Meta.checkRequiredVersion(); diags != nil {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if diags := c.requireWritableWorkspace("restore state snapshots"); diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(nil)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(backendDiags)
+		return 1
+	}
+
+	vh, ok := b.(backend.VersionHistory)
+	if !ok {
+		c.Ui.Error("The currently-selected backend does not retain state version history.")
+		return 1
+	}
+
+	// Determine the workspace name
+	workspace, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+
+	// Without -version, list the available snapshots instead of restoring.
+	if flagVersion < 0 {
+		versions, err := vh.StateVersions(workspace)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to list state versions: %s", err))
+			return 1
+		}
+		if len(versions) == 0 {
+			c.Ui.Output(fmt.Sprintf("No state snapshots are recorded for workspace %q.", workspace))
+			return 0
+		}
+
+		c.Ui.Output(fmt.Sprintf("State snapshots recorded for workspace %q:\n", workspace))
+		for _, version := range versions {
+			c.Ui.Output(fmt.Sprintf("  serial %-6d recorded %s", version.Serial, version.Created.Format("2006-01-02 15:04:05 MST")))
+		}
+		c.Ui.Output("\nUse -version=SERIAL to restore one of these snapshots.")
+		return 0
+	}
+
+	// Check remote Terraform version is compatible
+	remoteVersionDiags := c.remoteVersionCheck(b, workspace)
+	c.showDiagnostics(remoteVersionDiags)
+	if remoteVersionDiags.HasErrors() {
+		return 1
+	}
+
+	data, err := vh.StateVersion(workspace, uint64(flagVersion))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to retrieve state version %d: %s", flagVersion, err))
+		return 1
+	}
+
+	srcStateFile, err := statefile.Read(bytes.NewReader(data))
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading state snapshot with serial %d: %s", flagVersion, err))
+		return 1
+	}
+
+	// Get the state manager for the currently-selected workspace
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to load destination state: %s", err))
+		return 1
+	}
+
+	if c.stateLock {
+		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "state-restore-version"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to refresh destination state: %s", err))
+		return 1
+	}
+
+	// Import it, forcing through the lineage/serial checks since the
+	// snapshot being restored is by definition older than the current state.
+	if err := statemgr.Import(srcStateFile, stateMgr, true); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write state: %s", err))
+		return 1
+	}
+	if err := stateMgr.WriteState(srcStateFile.State); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to write state: %s", err))
+		return 1
+	}
+	if err := stateMgr.PersistState(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to persist state: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Restored the state for workspace %q to the snapshot with serial %d.", workspace, flagVersion))
+	return 0
+}
+
+func (c *StateRestoreVersionCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] state restore-version [options]
+
+  Restore a prior state snapshot retained by the backend.
+
+  Some backends can record a history of state snapshots as the state is
+  written; for example, the "pg" backend does so when its "history" option
+  is enabled. This command overwrites the current state of the selected
+  workspace with one of those snapshots, identified by its serial.
+
+  When run without -version, this command lists the recorded snapshots for
+  the selected workspace instead of restoring anything.
+
+  Restoring bypasses the usual protection against writing an older serial,
+  so subsequent writes may reuse serial numbers that appear later in the
+  history.
+
+Options:
+
+  -version=SERIAL     The serial of the state snapshot to restore.
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *StateRestoreVersionCommand) Synopsis() string {
+	return "Restore a prior state snapshot retained by the backend"
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestStateRestoreVersion_unsupportedBackend(t *testing.T) {
+	// The local backend does not retain state version history, so the
+	// command should fail with a clear error.
+	testCwd(t)
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	c := &StateRestoreVersionCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{"-version=1"}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("expected error, got: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+
+	if got := ui.ErrorWriter.String(); !strings.Contains(got, "does not retain state version history") {
+		t.Fatalf("unexpected error output:\n%s", got)
+	}
+}
+
+func TestStateRestoreVersion_extraArgs(t *testing.T) {
+	testCwd(t)
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	c := &StateRestoreVersionCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{"-version=1", "bad"}
+	if code := c.Run(args); code != cli.RunResultHelp {
+		t.Fatalf("expected help result, got: %d", code)
+	}
+}
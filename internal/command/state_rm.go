@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/command/addrfilter"
 	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/clistate"
 	"github.com/hashicorp/terraform/internal/command/views"
@@ -20,8 +21,10 @@ type StateRmCommand struct {
 func (c *StateRmCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	var dryRun bool
+	var filterExpr string
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state rm")
 	cmdFlags.BoolVar(&dryRun, "dry-run", false, "dry run")
+	cmdFlags.StringVar(&filterExpr, "filter", "", "filter expression")
 	cmdFlags.StringVar(&c.backupPath, "backup", "-", "backup")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
@@ -32,7 +35,20 @@ func (c *StateRmCommand) Run(args []string) int {
 	}
 
 	args = cmdFlags.Args()
-	if len(args) < 1 {
+	var filter *addrfilter.Filter
+	switch {
+	case filterExpr != "":
+		if len(args) > 0 {
+			c.Ui.Error("The -filter option cannot be combined with positional addresses.\n")
+			return cli.RunResultHelp
+		}
+		var err error
+		filter, err = addrfilter.Parse(filterExpr)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid filter expression: %s", err))
+			return 1
+		}
+	case len(args) < 1:
 		c.Ui.Error("At least one address is required.\n")
 		return cli.RunResultHelp
 	}
@@ -42,6 +58,11 @@ func (c *StateRmCommand) Run(args []string) int {
 		return 1
 	}
 
+	if diags := c.requireWritableWorkspace("remove resource instances"); diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	// Get the state
 	stateMgr, err := c.State()
 	if err != nil {
@@ -77,14 +98,18 @@ func (c *StateRmCommand) Run(args []string) int {
 	// also clean up any modules and resources left empty by actions it takes.
 	var addrs []addrs.AbsResourceInstance
 	var diags tfdiags.Diagnostics
-	for _, addrStr := range args {
-		moreAddrs, moreDiags := c.lookupResourceInstanceAddr(state, true, addrStr)
-		addrs = append(addrs, moreAddrs...)
-		diags = diags.Append(moreDiags)
-	}
-	if diags.HasErrors() {
-		c.showDiagnostics(diags)
-		return 1
+	if filter != nil {
+		addrs = filterResourceInstanceAddrs(state, filter)
+	} else {
+		for _, addrStr := range args {
+			moreAddrs, moreDiags := c.lookupResourceInstanceAddr(state, true, addrStr)
+			addrs = append(addrs, moreAddrs...)
+			diags = diags.Append(moreDiags)
+		}
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
 	}
 
 	prefix := "Removed "
@@ -124,11 +149,19 @@ func (c *StateRmCommand) Run(args []string) int {
 	}
 
 	if isCount == 0 {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Invalid target address",
-			"No matching objects found. To view the available instances, use \"terraform state list\". Please modify the address to reference a specific instance.",
-		))
+		if filter != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid filter expression",
+				"The filter expression does not match any resource instances in the state. To view the available instances, use \"terraform state list\".",
+			))
+		} else {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid target address",
+				"No matching objects found. To view the available instances, use \"terraform state list\". Please modify the address to reference a specific instance.",
+			))
+		}
 		c.showDiagnostics(diags)
 		return 1
 	}
@@ -159,6 +192,18 @@ Options:
   -dry-run                If set, prints out what would've been removed but
                           doesn't actually remove anything.
 
+  -filter=EXPR            Remove the instances matching the given filter
+                          expression instead of naming addresses, such as:
+                              -filter='type == "aws_iam_role"'
+                          The expression compares the address components addr,
+                          module, mode, type, name, key, and provider against
+                          quoted strings with == and != for exact comparison
+                          and =~ and !~ for regular expression matching,
+                          combined with &&, ||, ! and parentheses. Cannot be
+                          combined with positional addresses. Consider
+                          previewing the matches with -dry-run or with
+                          "terraform state list -filter=EXPR" first.
+
   -backup=PATH            Path where Terraform should write the backup
                           state.
 
@@ -577,3 +577,89 @@ test_instance.bar:
   bar = value
   foo = value
 `
+
+func TestStateRm_filter(t *testing.T) {
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"bar","foo":"value","bar":"value"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "bar",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"foo","foo":"value","bar":"value"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+	})
+	statePath := testStateFile(t, state)
+
+	p := testProvider()
+	view, _ := testView(t)
+	newCommand := func(ui *cli.MockUi) *StateRmCommand {
+		return &StateRmCommand{
+			StateMeta{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					Ui:               ui,
+					View:             view,
+				},
+			},
+		}
+	}
+
+	// combining -filter with an address is not allowed
+	ui := cli.NewMockUi()
+	args := []string{
+		"-state", statePath,
+		`-filter=name == "foo"`,
+		"test_instance.foo",
+	}
+	if code := newCommand(ui).Run(args); code == 0 {
+		t.Fatalf("expected failure when combining -filter with an address\n\n%s", ui.OutputWriter.String())
+	}
+
+	// a filter that matches nothing must not modify the state
+	ui = cli.NewMockUi()
+	args = []string{
+		"-state", statePath,
+		`-filter=type == "nope"`,
+	}
+	if code := newCommand(ui).Run(args); code != 1 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.OutputWriter.String())
+	}
+	if msg := ui.ErrorWriter.String(); !strings.Contains(msg, "does not match any resource instances") {
+		t.Fatalf("unexpected error output:\n%s", msg)
+	}
+
+	// remove one instance by filter
+	ui = cli.NewMockUi()
+	args = []string{
+		"-state", statePath,
+		`-filter=name == "foo"`,
+	}
+	if code := newCommand(ui).Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	testStateOutput(t, statePath, testStateRmOutput)
+}
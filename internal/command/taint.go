@@ -44,6 +44,14 @@ func (c *TaintCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Tainting writes to the state, so it isn't allowed in a read-only
+	// workspace.
+	diags = diags.Append(c.requireWritableWorkspace("taint objects"))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	addr, addrDiags := addrs.ParseAbsResourceInstanceStr(args[0])
 	diags = diags.Append(addrDiags)
 	if addrDiags.HasErrors() {
@@ -2,13 +2,17 @@ package command
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/hashicorp/terraform/internal/addrs"
@@ -54,7 +58,7 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	ctx, cancel := c.InterruptibleContext()
 	defer cancel()
 
-	results, moreDiags := c.run(ctx, args)
+	results, moreDiags := c.run(ctx, args, view)
 	diags = diags.Append(moreDiags)
 
 	initFailed := diags.HasErrors()
@@ -81,7 +85,7 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	return 0
 }
 
-func (c *TestCommand) run(ctx context.Context, args arguments.Test) (results map[string]*moduletest.Suite, diags tfdiags.Diagnostics) {
+func (c *TestCommand) run(ctx context.Context, args arguments.Test, view views.Test) (results map[string]*moduletest.Suite, diags tfdiags.Diagnostics) {
 	suiteNames, err := c.collectSuiteNames()
 	if err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -92,28 +96,97 @@ func (c *TestCommand) run(ctx context.Context, args arguments.Test) (results map
 		return nil, diags
 	}
 
+	// If there's a shared setup fixture then we must create its objects
+	// before any of the suites run, so that the suites can refer to the
+	// fixture's output values.
+	setup, moreDiags := c.runSharedSetup(ctx, args)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		// The setup may have partially completed before failing, so we
+		// still need to clean up whatever it managed to create.
+		diags = diags.Append(c.teardownSharedSetup(ctx, setup, args))
+		return nil, diags
+	}
+
+	parallel := args.Parallel
+	if parallel > len(suiteNames) {
+		parallel = len(suiteNames)
+	}
+
+	// Each suite is self-contained -- it has its own working directory,
+	// in-memory state, and test provider instance -- so suites can safely
+	// run concurrently, limited by a semaphore to the requested parallelism.
+	// We only emit the labeled progress messages when actually running
+	// concurrently, so that the serial output remains as before.
+	streaming := parallel > 1
+
+	type suiteResult struct {
+		suite *moduletest.Suite
+		diags tfdiags.Diagnostics
+	}
+	suiteResults := make([]suiteResult, len(suiteNames))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, suiteName := range suiteNames {
+		wg.Add(1)
+		go func(i int, suiteName string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				// If the context has already failed in some way then we'll
+				// halt early and report whatever's already happened.
+				return
+			}
+
+			if streaming {
+				view.SuiteStarting(suiteName)
+			}
+			suite, moreDiags := c.runSuite(ctx, suiteName, args, setup.outputValues())
+			if streaming {
+				view.SuiteComplete(suiteName)
+			}
+			suiteResults[i] = suiteResult{suite, moreDiags}
+		}(i, suiteName)
+	}
+	wg.Wait()
+
+	// Collect the results in the order the suites were discovered, so that
+	// the diagnostics are reported deterministically regardless of the
+	// order in which the suites finished.
 	ret := make(map[string]*moduletest.Suite, len(suiteNames))
-	for _, suiteName := range suiteNames {
-		if ctx.Err() != nil {
-			// If the context has already failed in some way then we'll
-			// halt early and report whatever's already happened.
-			break
+	for i, suiteName := range suiteNames {
+		result := suiteResults[i]
+		if result.suite == nil {
+			// The suite was skipped due to early cancellation.
+			continue
 		}
-		suite, moreDiags := c.runSuite(ctx, suiteName)
-		diags = diags.Append(moreDiags)
-		ret[suiteName] = suite
+		diags = diags.Append(result.diags)
+		ret[suiteName] = result.suite
 	}
 
+	// Once all of the suites are done with the shared fixture we can
+	// destroy its objects.
+	diags = diags.Append(c.teardownSharedSetup(ctx, setup, args))
+
 	return ret, diags
 }
 
-func (c *TestCommand) runSuite(ctx context.Context, suiteName string) (*moduletest.Suite, tfdiags.Diagnostics) {
+func (c *TestCommand) runSuite(ctx context.Context, suiteName string, args arguments.Test, setupOutputs map[string]cty.Value) (*moduletest.Suite, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	ret := moduletest.Suite{
 		Name:       suiteName,
 		Components: map[string]*moduletest.Component{},
 	}
 
+	// We record how long the suite took to run, including preparing its
+	// working directory, so that report files can include the timing.
+	startTime := time.Now()
+	defer func() {
+		ret.Duration = time.Since(startTime)
+	}()
+
 	// In order to make this initial round of "terraform test" pretty self
 	// contained while it's experimental, it's largely just mimicking what
 	// would happen when running the main Terraform workflow commands, which
@@ -139,7 +212,7 @@ func (c *TestCommand) runSuite(ctx context.Context, suiteName string) (*modulete
 	// we're doing just to make it work without doing any disruptive
 	// refactoring.
 
-	suiteDirs, moreDiags := c.prepareSuiteDir(ctx, suiteName)
+	suiteDirs, moreDiags := c.prepareSuiteDir(ctx, suiteName, args.Replay)
 	diags = diags.Append(moreDiags)
 	if diags.HasErrors() {
 		// Generate a special failure representing the test initialization
@@ -162,7 +235,7 @@ func (c *TestCommand) runSuite(ctx context.Context, suiteName string) (*modulete
 	// with individual components, so ret.Components may or may not contain
 	// failed/errored components after runTestSuite returns.
 	var finalState *states.State
-	ret.Components, finalState = c.runTestSuite(ctx, suiteDirs)
+	ret.Components, finalState = c.runTestSuite(ctx, suiteDirs, args, setupOutputs)
 
 	// Regardless of the success or failure of the test suite, if there are
 	// any objects left in the state then we'll generate a top-level error
@@ -227,7 +300,7 @@ func (c *TestCommand) runSuite(ctx context.Context, suiteName string) (*modulete
 	return &ret, diags
 }
 
-func (c *TestCommand) prepareSuiteDir(ctx context.Context, suiteName string) (testCommandSuiteDirs, tfdiags.Diagnostics) {
+func (c *TestCommand) prepareSuiteDir(ctx context.Context, suiteName string, replay bool) (testCommandSuiteDirs, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	configDir := filepath.Join("tests", suiteName)
 	log.Printf("[TRACE] terraform test: Prepare directory for suite %q in %s", suiteName, configDir)
@@ -279,6 +352,14 @@ func (c *TestCommand) prepareSuiteDir(ctx context.Context, suiteName string) (te
 	}
 	suiteDirs.Config = cfg
 
+	// In replay mode we don't need any real providers: all of the non-builtin
+	// provider calls will be answered from the recorded fixture files, so we
+	// can skip provider installation entirely and run hermetically.
+	if replay {
+		suiteDirs.ProviderLocks = depsfile.NewLocks()
+		return suiteDirs, diags
+	}
+
 	// With the full configuration tree available, we can now install
 	// the necessary providers. We'll use a separate local cache directory
 	// here, because the test configuration might have additional requirements
@@ -346,7 +427,7 @@ func (c *TestCommand) prepareSuiteDir(ctx context.Context, suiteName string) (te
 	return suiteDirs, diags
 }
 
-func (c *TestCommand) runTestSuite(ctx context.Context, suiteDirs testCommandSuiteDirs) (map[string]*moduletest.Component, *states.State) {
+func (c *TestCommand) runTestSuite(ctx context.Context, suiteDirs testCommandSuiteDirs, args arguments.Test, setupOutputs map[string]cty.Value) (map[string]*moduletest.Component, *states.State) {
 	log.Printf("[TRACE] terraform test: Run test suite %q", suiteDirs.SuiteName)
 
 	ret := make(map[string]*moduletest.Component)
@@ -383,7 +464,13 @@ func (c *TestCommand) runTestSuite(ctx context.Context, suiteDirs testCommandSui
 	// here we're associating each set of diagnostics with the specific
 	// operation it belongs to.
 
-	providerFactories, diags := c.testSuiteProviders(suiteDirs, testProvider)
+	var providerFactories map[addrs.Provider]providers.Factory
+	var diags tfdiags.Diagnostics
+	if args.Replay {
+		providerFactories, diags = c.testSuiteReplayProviders(suiteDirs, testProvider)
+	} else {
+		providerFactories, diags = c.testSuiteProviders(suiteDirs, testProvider)
+	}
 	if diags.HasErrors() {
 		// It should be unusual to get in here, because testSuiteProviders
 		// should rely only on things guaranteed by prepareSuiteDir, but
@@ -398,7 +485,36 @@ func (c *TestCommand) runTestSuite(ctx context.Context, suiteDirs testCommandSui
 		)
 	}
 
-	plan, diags := c.testSuitePlan(ctx, suiteDirs, providerFactories)
+	// In record mode we wrap each non-builtin provider factory so that
+	// every instance feeds the same per-provider recorder, whose contents
+	// are written out as replay fixture files once the suite completes.
+	var recorders map[addrs.Provider]*moduletest.Recorder
+	if args.Record {
+		recorders = make(map[addrs.Provider]*moduletest.Recorder)
+		for provider, factory := range providerFactories {
+			if provider.IsBuiltIn() {
+				// Built-in providers run in-process and are always available,
+				// so there's no need to record their calls.
+				continue
+			}
+			recorder := moduletest.NewRecorder()
+			recorders[provider] = recorder
+			inner := factory
+			providerFactories[provider] = func() (providers.Interface, error) {
+				p, err := inner()
+				if err != nil {
+					return nil, err
+				}
+				return recorder.Provider(p), nil
+			}
+		}
+	}
+
+	// Any of the suite's declared input variables whose names match output
+	// values of the shared setup fixture are assigned the fixture's values.
+	variables := testSuiteVariables(suiteDirs.Config, setupOutputs)
+
+	plan, diags := c.testSuitePlan(ctx, suiteDirs, providerFactories, variables)
 	if diags.HasErrors() {
 		// It should be unusual to get in here, because testSuitePlan
 		// should rely only on things guaranteed by prepareSuiteDir, but
@@ -437,7 +553,7 @@ func (c *TestCommand) runTestSuite(ctx context.Context, suiteDirs testCommandSui
 	// the destroy operation below won't get tripped up on stale results.
 	ret = testProvider.Reset()
 
-	state, diags = c.testSuiteDestroy(ctx, state, suiteDirs, providerFactories)
+	state, diags = c.testSuiteDestroy(ctx, state, suiteDirs, providerFactories, variables)
 	if diags.HasErrors() {
 		synthError(
 			"destroy",
@@ -447,6 +563,17 @@ func (c *TestCommand) runTestSuite(ctx context.Context, suiteDirs testCommandSui
 		)
 	}
 
+	if args.Record {
+		if moreDiags := c.writeTestRecordings(suiteDirs, recorders); moreDiags.HasErrors() {
+			synthError(
+				"record",
+				"record provider calls",
+				"failed to write the replay fixture files",
+				moreDiags,
+			)
+		}
+	}
+
 	return ret, state
 }
 
@@ -483,7 +610,7 @@ func (c *TestCommand) testSuiteProviders(suiteDirs testCommandSuiteDirs, testPro
 		// because we're creating a fresh "lock file" each time we run anyway
 		// and so they wouldn't actually guarantee anything useful.
 
-		ret[provider] = providerFactory(cached)
+		ret[provider] = providerFactory(cached, c.PluginGRPCClient, "")
 	}
 
 	// We'll replace the test provider instance with the one our caller
@@ -495,6 +622,147 @@ func (c *TestCommand) testSuiteProviders(suiteDirs testCommandSuiteDirs, testPro
 	return ret, diags
 }
 
+// testSuiteReplayProviders is the replay-mode equivalent of
+// testSuiteProviders: instead of real installed providers, each non-builtin
+// provider is served from a replay fixture file recorded by an earlier
+// "terraform test -record" run.
+func (c *TestCommand) testSuiteReplayProviders(suiteDirs testCommandSuiteDirs, testProvider *moduletest.Provider) (map[addrs.Provider]providers.Factory, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	ret := make(map[addrs.Provider]providers.Factory)
+
+	// The built-in providers are always available in-process, just as in a
+	// normal run.
+	for name, factory := range c.internalProviders() {
+		ret[addrs.NewBuiltInProvider(name)] = factory
+	}
+	ret[addrs.NewBuiltInProvider("test")] = func() (providers.Interface, error) {
+		return testProvider, nil
+	}
+
+	// The fixture files are laid out by provider address under the suite's
+	// replay directory. A missing directory just means there's nothing to
+	// replay, which is fine for suites using only built-in providers; a
+	// suite that needs a provider with no fixture will then fail with the
+	// usual "provider not available" error from Terraform Core.
+	replayDir := testReplayDir(suiteDirs.ConfigDir)
+	err := filepath.Walk(replayDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		rel, err := filepath.Rel(replayDir, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 3 {
+			// Not in the hostname/namespace/type.json layout, so not a
+			// fixture file.
+			return nil
+		}
+		source := parts[0] + "/" + parts[1] + "/" + strings.TrimSuffix(parts[2], ".json")
+		provider, moreDiags := addrs.ParseProviderSourceString(source)
+		if moreDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid replay fixture filename",
+				fmt.Sprintf("The replay fixture file %s does not correspond to a valid provider source address.", path),
+			))
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var recording moduletest.Recording
+		if err := json.Unmarshal(data, &recording); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid replay fixture file",
+				fmt.Sprintf("Failed to parse the replay fixture file %s: %s.", path, err),
+			))
+			return nil
+		}
+		replayer, err := moduletest.NewReplayer(&recording)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid replay fixture file",
+				fmt.Sprintf("Cannot replay the fixture file %s: %s.", path, err),
+			))
+			return nil
+		}
+		ret[provider] = func() (providers.Interface, error) {
+			return replayer.Provider(), nil
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read replay fixtures",
+			fmt.Sprintf("Error while searching %s for replay fixture files: %s.", replayDir, err),
+		))
+	}
+
+	return ret, diags
+}
+
+// writeTestRecordings saves the calls captured by the given recorders as
+// replay fixture files under the suite's replay directory.
+func (c *TestCommand) writeTestRecordings(suiteDirs testCommandSuiteDirs, recorders map[addrs.Provider]*moduletest.Recorder) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for provider, recorder := range recorders {
+		recording := recorder.Recording()
+		if recording.Schema == nil {
+			// The provider was never started, so there's nothing to record.
+			continue
+		}
+
+		path := testReplayFixturePath(suiteDirs.ConfigDir, provider)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write replay fixture",
+				fmt.Sprintf("Could not create the replay fixture directory for %s: %s.", provider.ForDisplay(), err),
+			))
+			continue
+		}
+		data, err := json.MarshalIndent(recording, "", "  ")
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write replay fixture",
+				fmt.Sprintf("Could not serialize the recorded calls for %s: %s.", provider.ForDisplay(), err),
+			))
+			continue
+		}
+		data = append(data, '\n')
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to write replay fixture",
+				fmt.Sprintf("Could not write the replay fixture file %s: %s.", path, err),
+			))
+		}
+	}
+	return diags
+}
+
+// testReplayDir is the directory within a test suite's configuration
+// directory where replay fixture files are stored. Unlike the .terraform
+// artifacts, these are intended to be committed to version control.
+func testReplayDir(configDir string) string {
+	return filepath.Join(configDir, "replay")
+}
+
+// testReplayFixturePath is the replay fixture file location for the given
+// provider, mirroring the hostname/namespace/type layout used for
+// installed providers.
+func testReplayFixturePath(configDir string, provider addrs.Provider) string {
+	return filepath.Join(testReplayDir(configDir), provider.Hostname.String(), provider.Namespace, provider.Type+".json")
+}
+
 type testSuiteRunContext struct {
 	Core *terraform.Context
 
@@ -541,7 +809,7 @@ func (c *TestCommand) testSuiteContext(suiteDirs testCommandSuiteDirs, providerF
 	}, diags
 }
 
-func (c *TestCommand) testSuitePlan(ctx context.Context, suiteDirs testCommandSuiteDirs, providerFactories map[addrs.Provider]providers.Factory) (*plans.Plan, tfdiags.Diagnostics) {
+func (c *TestCommand) testSuitePlan(ctx context.Context, suiteDirs testCommandSuiteDirs, providerFactories map[addrs.Provider]providers.Factory, variables terraform.InputValues) (*plans.Plan, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] terraform test: create plan for suite %q", suiteDirs.SuiteName)
 	runCtx, diags := c.testSuiteContext(suiteDirs, providerFactories, nil, nil, false)
 	if diags.HasErrors() {
@@ -561,7 +829,7 @@ func (c *TestCommand) testSuitePlan(ctx context.Context, suiteDirs testCommandSu
 	}
 
 	plan, moreDiags := runCtx.Core.Plan(
-		runCtx.Config, runCtx.InputState, &terraform.PlanOpts{Mode: runCtx.PlanMode},
+		runCtx.Config, runCtx.InputState, &terraform.PlanOpts{Mode: runCtx.PlanMode, SetVariables: variables},
 	)
 	diags = diags.Append(moreDiags)
 	return plan, diags
@@ -581,7 +849,7 @@ func (c *TestCommand) testSuiteApply(ctx context.Context, plan *plans.Plan, suit
 	return state, diags
 }
 
-func (c *TestCommand) testSuiteDestroy(ctx context.Context, state *states.State, suiteDirs testCommandSuiteDirs, providerFactories map[addrs.Provider]providers.Factory) (*states.State, tfdiags.Diagnostics) {
+func (c *TestCommand) testSuiteDestroy(ctx context.Context, state *states.State, suiteDirs testCommandSuiteDirs, providerFactories map[addrs.Provider]providers.Factory, variables terraform.InputValues) (*states.State, tfdiags.Diagnostics) {
 	log.Printf("[TRACE] terraform test: plan to destroy any existing objects for suite %q", suiteDirs.SuiteName)
 	runCtx, diags := c.testSuiteContext(suiteDirs, providerFactories, state, nil, true)
 	if diags.HasErrors() {
@@ -589,7 +857,7 @@ func (c *TestCommand) testSuiteDestroy(ctx context.Context, state *states.State,
 	}
 
 	plan, moreDiags := runCtx.Core.Plan(
-		runCtx.Config, runCtx.InputState, &terraform.PlanOpts{Mode: runCtx.PlanMode},
+		runCtx.Config, runCtx.InputState, &terraform.PlanOpts{Mode: runCtx.PlanMode, SetVariables: variables},
 	)
 	diags = diags.Append(moreDiags)
 	if diags.HasErrors() {
@@ -608,6 +876,189 @@ func (c *TestCommand) testSuiteDestroy(ctx context.Context, state *states.State,
 	return state, diags
 }
 
+// testSuiteVariables builds the set of root module input variable values for
+// a test configuration, assigning the shared setup fixture's output values to
+// any declared variables with matching names. All of the other declared
+// variables get explicit "no value" entries so that their defaults apply, or
+// so that the plan can report required variables that nothing assigned.
+func testSuiteVariables(config *configs.Config, setupOutputs map[string]cty.Value) terraform.InputValues {
+	decls := config.Module.Variables
+	if len(decls) == 0 {
+		return nil
+	}
+	ret := make(terraform.InputValues, len(decls))
+	for name := range decls {
+		if v, ok := setupOutputs[name]; ok {
+			ret[name] = &terraform.InputValue{
+				Value:      v,
+				SourceType: terraform.ValueFromCaller,
+			}
+			continue
+		}
+		ret[name] = &terraform.InputValue{
+			Value:      cty.NilVal,
+			SourceType: terraform.ValueFromCaller,
+		}
+	}
+	return ret
+}
+
+// testSetupDirName is the name of the reserved directory under "tests" that
+// holds the optional shared setup fixture configuration. It is deliberately
+// not a valid suite name, so it can never collide with a real suite.
+const testSetupDirName = "_setup"
+
+// testCommandSharedSetup tracks the objects and output values of the shared
+// setup fixture for the duration of one "terraform test" run.
+type testCommandSharedSetup struct {
+	suiteDirs         testCommandSuiteDirs
+	providerFactories map[addrs.Provider]providers.Factory
+	testProvider      *moduletest.Provider
+	recorders         map[addrs.Provider]*moduletest.Recorder
+	state             *states.State
+	outputs           map[string]cty.Value
+}
+
+// outputValues returns the fixture's root module output values, or nil if
+// there is no shared setup fixture at all.
+func (s *testCommandSharedSetup) outputValues() map[string]cty.Value {
+	if s == nil {
+		return nil
+	}
+	return s.outputs
+}
+
+// runSharedSetup applies the shared setup fixture in tests/_setup, if there
+// is one, so that its output values can be assigned to matching input
+// variables in each of the test suites. It returns nil if the fixture
+// doesn't exist. The caller is responsible for passing whatever this returns
+// to teardownSharedSetup once the suites are done, even if this returns
+// errors, because a failed setup may still have created some objects.
+func (c *TestCommand) runSharedSetup(ctx context.Context, args arguments.Test) (*testCommandSharedSetup, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	setupDir := filepath.Join("tests", testSetupDirName)
+	tfFiles, _ := filepath.Glob(filepath.Join(setupDir, "*.tf"))
+	tfJSONFiles, _ := filepath.Glob(filepath.Join(setupDir, "*.tf.json"))
+	if (len(tfFiles) + len(tfJSONFiles)) == 0 {
+		// No shared setup fixture, then.
+		return nil, diags
+	}
+
+	log.Printf("[TRACE] terraform test: Apply the shared setup fixture in %s", setupDir)
+
+	suiteDirs, moreDiags := c.prepareSuiteDir(ctx, testSetupDirName, args.Replay)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	setup := &testCommandSharedSetup{
+		suiteDirs: suiteDirs,
+		state:     states.NewState(),
+
+		// The fixture doesn't participate in test assertions, but the
+		// provider wiring expects a test provider instance regardless.
+		testProvider: moduletest.NewProvider(),
+	}
+
+	if args.Replay {
+		setup.providerFactories, moreDiags = c.testSuiteReplayProviders(suiteDirs, setup.testProvider)
+	} else {
+		setup.providerFactories, moreDiags = c.testSuiteProviders(suiteDirs, setup.testProvider)
+	}
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return setup, diags
+	}
+
+	// In record mode the fixture's provider calls are captured too, just as
+	// for the suites themselves, so that a later -replay run can reproduce
+	// the fixture's outputs without real providers.
+	if args.Record {
+		setup.recorders = make(map[addrs.Provider]*moduletest.Recorder)
+		for provider, factory := range setup.providerFactories {
+			if provider.IsBuiltIn() {
+				continue
+			}
+			recorder := moduletest.NewRecorder()
+			setup.recorders[provider] = recorder
+			inner := factory
+			setup.providerFactories[provider] = func() (providers.Interface, error) {
+				p, err := inner()
+				if err != nil {
+					return nil, err
+				}
+				return recorder.Provider(p), nil
+			}
+		}
+	}
+
+	variables := testSuiteVariables(suiteDirs.Config, nil)
+	plan, moreDiags := c.testSuitePlan(ctx, suiteDirs, setup.providerFactories, variables)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return setup, diags
+	}
+
+	state, moreDiags := c.testSuiteApply(ctx, plan, suiteDirs, setup.providerFactories)
+	setup.state = state
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		return setup, diags
+	}
+
+	outputs := make(map[string]cty.Value)
+	for name, os := range state.RootModule().OutputValues {
+		outputs[name] = os.Value
+	}
+	setup.outputs = outputs
+
+	return setup, diags
+}
+
+// teardownSharedSetup destroys the objects created by runSharedSetup. It is
+// a no-op when given a nil setup.
+func (c *TestCommand) teardownSharedSetup(ctx context.Context, setup *testCommandSharedSetup, args arguments.Test) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if setup == nil {
+		return diags
+	}
+
+	log.Printf("[TRACE] terraform test: Destroy the shared setup fixture")
+
+	// Discard any assertion results the fixture might've produced, so that
+	// the destroy below won't get tripped up on stale results.
+	setup.testProvider.Reset()
+
+	variables := testSuiteVariables(setup.suiteDirs.Config, nil)
+	state, moreDiags := c.testSuiteDestroy(ctx, setup.state, setup.suiteDirs, setup.providerFactories, variables)
+	diags = diags.Append(moreDiags)
+
+	// As for suite states, we're loud about any objects left behind by a
+	// failed destroy because they might have an ongoing cost.
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for instanceKey := range rs.Instances {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to clean up after tests",
+					fmt.Sprintf(
+						"Due to errors during destroy, the shared setup fixture has left behind an object for %s. You will need to delete this object manually in the remote system, or else it may have an ongoing cost.",
+						rs.Addr.Instance(instanceKey),
+					),
+				))
+			}
+		}
+	}
+
+	if args.Record && len(setup.recorders) > 0 {
+		diags = diags.Append(c.writeTestRecordings(setup.suiteDirs, setup.recorders))
+	}
+
+	return diags
+}
+
 func (c *TestCommand) collectSuiteNames() ([]string, error) {
 	items, err := ioutil.ReadDir("tests")
 	if err != nil {
@@ -623,6 +1074,12 @@ func (c *TestCommand) collectSuiteNames() ([]string, error) {
 			continue
 		}
 		name := item.Name()
+		if name == testSetupDirName || strings.HasPrefix(name, ".") {
+			// The "_setup" directory holds the shared setup fixture rather
+			// than a test suite, and hidden directories are skipped in case
+			// something like a version control system created them.
+			continue
+		}
 		suitePath := filepath.Join("tests", name)
 		tfFiles, err := filepath.Glob(filepath.Join(suitePath, "*.tf"))
 		if err != nil {
@@ -663,14 +1120,23 @@ Usage: terraform test [options]
       terraform apply
       terraform destroy
 
-  The test configurations should not declare any input variables and
-  should at least contain a call to the module being tested, which
-  will always be available at the path ../.. due to the expected
-  filesystem layout.
+  The test configurations should at least contain a call to the module
+  being tested, which will always be available at the path ../.. due to
+  the expected filesystem layout.
 
   The tests are considered to be successful if all of the above steps
   succeed.
 
+  If the "tests" directory contains a subdirectory named "_setup" with
+  ".tf" or ".tf.json" files then that configuration describes a shared
+  fixture rather than a test suite: it is applied once before any of
+  the suites run and destroyed once after all of them have finished,
+  so expensive supporting infrastructure need not be re-created by
+  each suite. The fixture's output values are assigned to any input
+  variables of the same names declared in the test suite
+  configurations. Aside from those, the test configurations should
+  not declare any input variables.
+
   Test configurations may optionally include uses of the special
   built-in test provider terraform.io/builtin/test, which allows
   writing explicit test assertions which must also all pass in order
@@ -701,6 +1167,12 @@ Options:
   -compact-warnings  Use a more compact representation for warnings, if
                      this command produces only warnings and no errors.
 
+  -json-report=FILE  In addition to the usual output, also write test
+                     results to the given file path as JSON, using a schema
+                     that follows the suite/component/assertion structure
+                     of the tests directly, including per-suite durations
+                     and any failure diagnostics.
+
   -junit-xml=FILE    In addition to the usual output, also write test
                      results to the given file path in JUnit XML format.
                      This format is commonly supported by CI systems, and
@@ -709,6 +1181,28 @@ Options:
 
   -no-color          Don't include virtual terminal formatting sequences in
                      the output.
+
+  -parallel=n        Run up to n test suites concurrently. Each suite is
+                     self-contained, with its own working directory and
+                     provider instances, so independent suites can safely
+                     run at the same time. When running concurrently,
+                     progress lines labeled with the suite name report
+                     each suite starting and finishing, and the summary
+                     at the end remains deterministic. Defaults to 1,
+                     running the suites serially.
+
+  -record            Capture the provider calls made while running the
+                     tests into fixture files, stored in a "replay"
+                     directory inside each suite directory. Commit those
+                     files to version control to allow later hermetic
+                     runs with -replay.
+
+  -replay            Answer provider calls from the fixture files written
+                     by an earlier -record run, instead of installing and
+                     running real providers. This allows the tests to run
+                     without network access or credentials, such as in CI,
+                     but they will exercise the recorded API behavior
+                     rather than the current behavior of the real system.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -3,6 +3,7 @@ package command
 import (
 	"bytes"
 	"io/ioutil"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -11,6 +12,14 @@ import (
 	"github.com/hashicorp/terraform/internal/terminal"
 )
 
+// The report files record how long each suite took to run, which of course
+// varies between runs, so tests replace the durations with a fixed value
+// before comparing against expected file contents.
+var (
+	durationAttrPattern = regexp.MustCompile(`time="[^"]*"`)
+	durationPropPattern = regexp.MustCompile(`"duration_seconds": [0-9.e+-]+`)
+)
+
 // These are the main tests for the "terraform test" command.
 func TestTest(t *testing.T) {
 	t.Run("passes", func(t *testing.T) {
@@ -25,7 +34,7 @@ func TestTest(t *testing.T) {
 				View:    views.NewView(streams),
 			},
 		}
-		exitStatus := cmd.Run([]string{"-junit-xml=junit.xml", "-no-color"})
+		exitStatus := cmd.Run([]string{"-junit-xml=junit.xml", "-json-report=report.json", "-no-color"})
 		outp := close(t)
 		if got, want := exitStatus, 0; got != want {
 			t.Fatalf("wrong exit status %d; want %d\nstderr:\n%s", got, want, outp.Stderr())
@@ -59,13 +68,16 @@ Success! All of the test assertions passed.
 		if err != nil {
 			t.Fatal(err)
 		}
+		// The durations in the report files vary from run to run, so we'll
+		// normalize them before comparing against the expected output.
 		gotXML := string(bytes.TrimSpace(gotXMLSrc))
+		gotXML = durationAttrPattern.ReplaceAllString(gotXML, `time="0"`)
 		wantXML := strings.TrimSpace(`
 <testsuites>
   <errors>0</errors>
   <failures>0</failures>
   <tests>1</tests>
-  <testsuite>
+  <testsuite time="0">
     <name>hello</name>
     <tests>1</tests>
     <skipped>0</skipped>
@@ -81,6 +93,134 @@ Success! All of the test assertions passed.
 		if diff := cmp.Diff(wantXML, gotXML); diff != "" {
 			t.Errorf("wrong JUnit XML\n%s", diff)
 		}
+
+		gotJSONSrc, err := ioutil.ReadFile("report.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotJSON := string(bytes.TrimSpace(gotJSONSrc))
+		gotJSON = durationPropPattern.ReplaceAllString(gotJSON, `"duration_seconds": 0`)
+		wantJSON := strings.TrimSpace(`
+{
+  "format_version": "1.0",
+  "tests": 1,
+  "passed": 1,
+  "failed": 0,
+  "errored": 0,
+  "pending": 0,
+  "suites": [
+    {
+      "name": "hello",
+      "duration_seconds": 0,
+      "tests": 1,
+      "passed": 1,
+      "failed": 0,
+      "errored": 0,
+      "pending": 0,
+      "components": [
+        {
+          "name": "foo",
+          "assertions": [
+            {
+              "name": "output",
+              "outcome": "passed",
+              "description": "output \"foo\" value",
+              "message": "correct value\n    got: \"foo value boop\"\n"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`)
+		if diff := cmp.Diff(wantJSON, gotJSON); diff != "" {
+			t.Errorf("wrong JSON report\n%s", diff)
+		}
+	})
+	t.Run("passes in parallel", func(t *testing.T) {
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("test-passes-parallel"), td)
+		defer testChdir(t, td)()
+
+		streams, close := terminal.StreamsForTesting(t)
+		cmd := &TestCommand{
+			Meta: Meta{
+				Streams: streams,
+				View:    views.NewView(streams),
+			},
+		}
+		exitStatus := cmd.Run([]string{"-parallel=2", "-no-color"})
+		outp := close(t)
+		if got, want := exitStatus, 0; got != want {
+			t.Fatalf("wrong exit status %d; want %d\nstderr:\n%s", got, want, outp.Stderr())
+		}
+
+		// The progress lines from the two suites may interleave in any
+		// order, but each suite must be reported as starting and completing,
+		// and the overall result must still be a success.
+		gotStderr := outp.Stderr()
+		for _, want := range []string{
+			"hello: starting...\n",
+			"hello: complete\n",
+			"world: starting...\n",
+			"world: complete\n",
+			"Success! All of the test assertions passed.\n",
+		} {
+			if !strings.Contains(gotStderr, want) {
+				t.Errorf("missing expected output %q\nstderr:\n%s", want, gotStderr)
+			}
+		}
+	})
+	t.Run("passes with shared setup fixture", func(t *testing.T) {
+		// The tests/_setup directory holds a shared fixture whose output
+		// values are assigned to the matching input variables declared by
+		// the test suites.
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("test-shared-setup"), td)
+		defer testChdir(t, td)()
+
+		streams, close := terminal.StreamsForTesting(t)
+		cmd := &TestCommand{
+			Meta: Meta{
+				Streams: streams,
+				View:    views.NewView(streams),
+			},
+		}
+		exitStatus := cmd.Run([]string{"-no-color"})
+		outp := close(t)
+		if got, want := exitStatus, 0; got != want {
+			t.Fatalf("wrong exit status %d; want %d\nstderr:\n%s", got, want, outp.Stderr())
+		}
+
+		if got, want := outp.Stderr(), "Success! All of the test assertions passed.\n"; !strings.Contains(got, want) {
+			t.Errorf("missing expected output %q\nstderr:\n%s", want, got)
+		}
+	})
+	t.Run("passes in replay mode", func(t *testing.T) {
+		// This suite uses only the built-in test provider, which is always
+		// available in-process, so replay mode should succeed even though
+		// there are no fixture files and provider installation is skipped.
+		td := t.TempDir()
+		testCopyDir(t, testFixturePath("test-passes"), td)
+		defer testChdir(t, td)()
+
+		streams, close := terminal.StreamsForTesting(t)
+		cmd := &TestCommand{
+			Meta: Meta{
+				Streams: streams,
+				View:    views.NewView(streams),
+			},
+		}
+		exitStatus := cmd.Run([]string{"-replay", "-no-color"})
+		outp := close(t)
+		if got, want := exitStatus, 0; got != want {
+			t.Fatalf("wrong exit status %d; want %d\nstderr:\n%s", got, want, outp.Stderr())
+		}
+
+		if got, want := outp.Stderr(), "Success! All of the test assertions passed.\n"; !strings.Contains(got, want) {
+			t.Errorf("missing expected output %q\nstderr:\n%s", want, got)
+		}
 	})
 	t.Run("fails", func(t *testing.T) {
 		td := t.TempDir()
@@ -134,12 +274,13 @@ wrong value
 			t.Fatal(err)
 		}
 		gotXML := string(bytes.TrimSpace(gotXMLSrc))
+		gotXML = durationAttrPattern.ReplaceAllString(gotXML, `time="0"`)
 		wantXML := strings.TrimSpace(`
 <testsuites>
   <errors>0</errors>
   <failures>1</failures>
   <tests>1</tests>
-  <testsuite>
+  <testsuite time="0">
     <name>hello</name>
     <tests>1</tests>
     <skipped>0</skipped>
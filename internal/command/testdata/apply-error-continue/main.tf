@@ -0,0 +1,11 @@
+resource "test_instance" "ok" {
+    ami = "bar"
+}
+
+resource "test_instance" "fail" {
+    error = "true"
+}
+
+resource "test_instance" "downstream" {
+    ami = test_instance.fail.id
+}
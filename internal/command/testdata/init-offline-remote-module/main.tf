@@ -0,0 +1,3 @@
+module "foo" {
+  source = "git::https://example.com/foo.git"
+}
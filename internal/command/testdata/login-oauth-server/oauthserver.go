@@ -16,9 +16,10 @@ import (
 // Handler is an implementation of net/http.Handler that provides a stub
 // OAuth server implementation with the following endpoints:
 //
-//     /authz  - authorization endpoint
-//     /token  - token endpoint
-//     /revoke - token revocation (logout) endpoint
+//	/authz  - authorization endpoint
+//	/device - device authorization endpoint
+//	/token  - token endpoint
+//	/revoke - token revocation (logout) endpoint
 //
 // The authorization endpoint returns HTML per normal OAuth conventions, but
 // it also includes an HTTP header X-Redirect-To giving the same URL that the
@@ -32,6 +33,10 @@ func (h handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	switch req.URL.Path {
 	case "/authz":
 		h.serveAuthz(resp, req)
+	case "/device":
+		h.serveDeviceAuthz(resp, req)
+	case "/login":
+		h.serveDeviceLogin(resp, req)
 	case "/token":
 		h.serveToken(resp, req)
 	case "/revoke":
@@ -83,6 +88,35 @@ func (h handler) serveAuthz(resp http.ResponseWriter, req *http.Request) {
 	resp.Write([]byte(respBody))
 }
 
+func (h handler) serveDeviceAuthz(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		resp.WriteHeader(405)
+		log.Printf("/device: unsupported request method %q", req.Method)
+		return
+	}
+
+	// NOTE: This is not a suitable implementation for a real OAuth server
+	// because the device code is fixed and the user never actually confirms
+	// anything. This is just a simple implementation for this stub server,
+	// which behaves as if the user had already confirmed the login before
+	// the client's first poll of the token endpoint.
+	respBody := fmt.Sprintf(`{"device_code":"pending-device-code","user_code":"TFTF-TEST","verification_uri":"http://%s/login","expires_in":60,"interval":1}`, req.Host)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(200)
+	resp.Write([]byte(respBody))
+	log.Println("/device: issued device code")
+}
+
+func (h handler) serveDeviceLogin(resp http.ResponseWriter, req *http.Request) {
+	// This is the page that a real user would visit to enter their user
+	// code and confirm the login. Our stub client never reads it, so a
+	// placeholder suffices.
+	respBody := `<p>Enter your user code to log in.</p>`
+	resp.Header().Set("Content-Type", "text/html")
+	resp.WriteHeader(200)
+	resp.Write([]byte(respBody))
+}
+
 func (h handler) serveToken(resp http.ResponseWriter, req *http.Request) {
 	if req.Method != "POST" {
 		resp.WriteHeader(405)
@@ -164,6 +198,21 @@ func (h handler) serveToken(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(`{"access_token":"good-token","token_type":"bearer"}`))
 		log.Println("/token: successful request")
 
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		deviceCode := req.Form.Get("device_code")
+		if deviceCode != "pending-device-code" {
+			resp.Header().Set("Content-Type", "application/json")
+			resp.WriteHeader(400)
+			resp.Write([]byte(`{"error":"invalid_grant"}`))
+			log.Printf("/token: unknown device code %q", deviceCode)
+			return
+		}
+
+		resp.Header().Set("Content-Type", "application/json")
+		resp.WriteHeader(200)
+		resp.Write([]byte(`{"access_token":"good-token","token_type":"bearer"}`))
+		log.Println("/token: successful request")
+
 	default:
 		resp.WriteHeader(400)
 		log.Printf("/token: unsupported grant type %q", grantType)
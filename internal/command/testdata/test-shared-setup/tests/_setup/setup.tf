@@ -0,0 +1,5 @@
+# A shared fixture for all of the test suites. In a real module this might
+# declare expensive supporting infrastructure; here it just exports a value.
+output "prefix" {
+  value = "fixture"
+}
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    test = {
+      source = "terraform.io/builtin/test"
+    }
+  }
+}
+
+variable "prefix" {
+  type = string
+}
+
+module "main" {
+  source = "../.."
+
+  input = var.prefix
+}
+
+resource "test_assertions" "foo" {
+  component = "foo"
+
+  equal "output" {
+    description = "output \"foo\" value"
+    got         = module.main.foo
+    want        = "foo value fixture"
+  }
+}
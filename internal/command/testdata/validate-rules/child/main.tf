@@ -0,0 +1,3 @@
+output "real" {
+  value = "ok"
+}
@@ -0,0 +1,19 @@
+variable "used" {
+  default = "ami-1234"
+}
+
+variable "unused" {
+  default = "never referenced"
+}
+
+resource "test_instance" "foo" {
+  ami = var.used
+}
+
+module "child" {
+  source = "./child"
+}
+
+output "declared" {
+  value = module.child.real
+}
@@ -21,8 +21,10 @@ type UnlockCommand struct {
 func (c *UnlockCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	var force bool
+	var list bool
 	cmdFlags := c.Meta.defaultFlagSet("force-unlock")
 	cmdFlags.BoolVar(&force, "force", false, "force")
+	cmdFlags.BoolVar(&list, "list", false, "list")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -30,14 +32,22 @@ func (c *UnlockCommand) Run(args []string) int {
 	}
 
 	args = cmdFlags.Args()
-	if len(args) != 1 {
+
+	var lockID string
+	switch {
+	case list:
+		if len(args) != 0 {
+			c.Ui.Error("The -list option does not take a LOCK_ID argument")
+			return cli.RunResultHelp
+		}
+	case len(args) == 1:
+		lockID = args[0]
+		args = args[1:]
+	default:
 		c.Ui.Error("Expected a single argument: LOCK_ID")
 		return cli.RunResultHelp
 	}
 
-	lockID := args[0]
-	args = args[1:]
-
 	// assume everything is initialized. The user can manually init if this is
 	// required.
 	configPath, err := ModulePath(args)
@@ -76,6 +86,10 @@ func (c *UnlockCommand) Run(args []string) int {
 		return 1
 	}
 
+	if list {
+		return c.listLock(stateMgr, env)
+	}
+
 	_, isLocal := stateMgr.(*statemgr.Filesystem)
 
 	if !force {
@@ -114,9 +128,44 @@ func (c *UnlockCommand) Run(args []string) int {
 	return 0
 }
 
+// listLock reports whether the state for the given workspace is currently
+// locked and, if so, the metadata recorded by the lock holder.
+//
+// There is no general way to read lock metadata without participating in the
+// locking protocol, so this briefly attempts to take the lock itself: if the
+// attempt succeeds then nobody else was holding the lock and the probe lock
+// is released again immediately, and if it fails because the lock is held
+// then the holder's metadata from the failure is reported.
+func (c *UnlockCommand) listLock(stateMgr statemgr.Full, workspace string) int {
+	info := statemgr.NewLockInfo()
+	info.Operation = "lock status"
+
+	id, err := stateMgr.Lock(info)
+	if err == nil {
+		if err := stateMgr.Unlock(id); err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to release the temporary lock used to check the lock status: %s", err))
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("The state for workspace %q is not currently locked.", workspace))
+		return 0
+	}
+
+	lockErr, ok := err.(*statemgr.LockError)
+	if !ok || lockErr.Info == nil {
+		c.Ui.Error(fmt.Sprintf("Failed to check the lock status: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf(
+		"The state for workspace %q is currently locked.\n\n%s",
+		workspace, strings.TrimSpace(lockErr.Info.String()),
+	))
+	return 0
+}
+
 func (c *UnlockCommand) Help() string {
 	helpText := `
-Usage: terraform [global options] force-unlock LOCK_ID
+Usage: terraform [global options] force-unlock [-list | LOCK_ID]
 
   Manually unlock the state for the defined configuration.
 
@@ -128,6 +177,11 @@ Usage: terraform [global options] force-unlock LOCK_ID
 Options:
 
   -force                 Don't ask for input for unlock confirmation.
+
+  -list                  Instead of unlocking, report whether the state for
+                         the current workspace is currently locked, along with
+                         the lock ID, holder, operation, and creation time
+                         when it is. No LOCK_ID argument is expected.
 `
 	return strings.TrimSpace(helpText)
 }
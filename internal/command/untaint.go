@@ -44,6 +44,14 @@ func (c *UntaintCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Untainting writes to the state, so it isn't allowed in a read-only
+	// workspace.
+	diags = diags.Append(c.requireWritableWorkspace("untaint objects"))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	addr, addrDiags := addrs.ParseAbsResourceInstanceStr(args[0])
 	diags = diags.Append(addrDiags)
 	if addrDiags.HasErrors() {
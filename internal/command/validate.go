@@ -31,6 +31,8 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 
 	view := views.NewValidate(args.ViewType, c.View)
 
+	c.Meta.noSchemaCache = args.NoSchemaCache
+
 	// After this point, we must only produce JSON output if JSON mode is
 	// enabled, so all errors should be accumulated into diags and we'll
 	// print out a suitable result at the end, depending on the format
@@ -49,7 +51,7 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 		return view.Results(diags)
 	}
 
-	validateDiags := c.validate(dir)
+	validateDiags := c.validate(dir, args.Rules)
 	diags = diags.Append(validateDiags)
 
 	// Validating with dev overrides in effect means that the result might
@@ -61,7 +63,7 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 	return view.Results(diags)
 }
 
-func (c *ValidateCommand) validate(dir string) tfdiags.Diagnostics {
+func (c *ValidateCommand) validate(dir string, ruleNames []string) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
 	cfg, cfgDiags := c.loadConfig(dir)
@@ -71,6 +73,15 @@ func (c *ValidateCommand) validate(dir string) tfdiags.Diagnostics {
 		return diags
 	}
 
+	if len(ruleNames) != 0 {
+		rules, ruleDiags := selectValidateRules(ruleNames)
+		diags = diags.Append(ruleDiags)
+		if ruleDiags.HasErrors() {
+			return diags
+		}
+		diags = diags.Append(runValidateRules(cfg, rules))
+	}
+
 	opts, err := c.contextOpts()
 	if err != nil {
 		diags = diags.Append(err)
@@ -125,6 +136,15 @@ Options:
                Always disables color.
 
   -no-color    If specified, output won't contain any color.
+
+  -no-schema-cache  Fetch fresh schemas from the providers instead of reusing
+               schemas cached on disk from an earlier run.
+
+  -rules=a,b   Also run the named opt-in lint rules against the configuration
+               and report their findings as warnings. Available rules are
+               provider-version, undeclared-output and unused-variable, and
+               the special name "all" selects every rule. Each finding's
+               detail ends with the rule name as a machine-readable code.
 `
 	return strings.TrimSpace(helpText)
 }
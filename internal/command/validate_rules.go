@@ -0,0 +1,339 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// validateRule is one opt-in lint rule for the validate command, selected
+// with the -rules option. The rule name doubles as the machine-readable
+// code included in each diagnostic the rule produces.
+type validateRule struct {
+	name  string
+	check func(cfg *configs.Config) tfdiags.Diagnostics
+}
+
+// validateRuleSet is the set of all available opt-in validation rules.
+var validateRuleSet = []validateRule{
+	{"provider-version", checkRuleProviderVersions},
+	{"undeclared-output", checkRuleUndeclaredOutputs},
+	{"unused-variable", checkRuleUnusedVariables},
+}
+
+// selectValidateRules resolves the rule names given in the -rules option,
+// where the special name "all" selects every available rule.
+func selectValidateRules(names []string) ([]validateRule, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var ret []validateRule
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name == "all" {
+			for _, rule := range validateRuleSet {
+				if !selected[rule.name] {
+					selected[rule.name] = true
+					ret = append(ret, rule)
+				}
+			}
+			continue
+		}
+
+		found := false
+		for _, rule := range validateRuleSet {
+			if rule.name == name {
+				found = true
+				if !selected[name] {
+					selected[name] = true
+					ret = append(ret, rule)
+				}
+				break
+			}
+		}
+		if !found {
+			var available []string
+			for _, rule := range validateRuleSet {
+				available = append(available, rule.name)
+			}
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Unknown validation rule",
+				fmt.Sprintf("There is no validation rule named %q. Available rules are: %s. The special name \"all\" selects every rule.", name, strings.Join(available, ", ")),
+			))
+		}
+	}
+	return ret, diags
+}
+
+// runValidateRules applies the given rules to the given configuration and
+// returns their findings as warning diagnostics.
+func runValidateRules(cfg *configs.Config, rules []validateRule) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, rule := range rules {
+		diags = diags.Append(rule.check(cfg))
+	}
+	return diags
+}
+
+// validateRuleDiag constructs the warning diagnostic for one finding of the
+// named rule, appending the rule's machine-readable code to the detail so
+// that automation consuming the JSON output can classify the finding.
+func validateRuleDiag(name, summary, detail string, rng hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  summary,
+		Detail:   fmt.Sprintf("%s\n\n(rule: %s)", detail, name),
+		Subject:  rng.Ptr(),
+	}
+}
+
+// checkRuleUnusedVariables reports input variables that are declared but
+// never referenced within their module.
+func checkRuleUnusedVariables(cfg *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	cfg.DeepEach(func(c *configs.Config) {
+		refs, opaque := moduleLintReferences(c.Module)
+		if opaque {
+			// The module contains bodies we cannot analyze without a schema,
+			// such as JSON syntax files, so we can't tell which variables
+			// are used and must stay quiet rather than report false
+			// positives.
+			return
+		}
+
+		used := make(map[string]bool)
+		for _, traversal := range refs {
+			if traversal.RootName() != "var" || len(traversal) < 2 {
+				continue
+			}
+			if step, ok := traversal[1].(hcl.TraverseAttr); ok {
+				used[step.Name] = true
+			}
+		}
+
+		names := make([]string, 0, len(c.Module.Variables))
+		for name := range c.Module.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if used[name] {
+				continue
+			}
+			v := c.Module.Variables[name]
+			diags = diags.Append(validateRuleDiag(
+				"unused-variable",
+				"Unused variable",
+				fmt.Sprintf("The variable %q is declared but not used within its module. Consider removing the declaration.", name),
+				v.DeclRange,
+			))
+		}
+	})
+	return diags
+}
+
+// checkRuleUndeclaredOutputs reports references to output values that the
+// referenced child module does not declare.
+func checkRuleUndeclaredOutputs(cfg *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	cfg.DeepEach(func(c *configs.Config) {
+		refs, opaque := moduleLintReferences(c.Module)
+		if opaque {
+			return
+		}
+
+		for _, traversal := range refs {
+			if traversal.RootName() != "module" || len(traversal) < 2 {
+				continue
+			}
+			callStep, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+			child, exists := c.Children[callStep.Name]
+			if !exists {
+				// A call to a module that isn't in the configuration tree is
+				// an error that core validation will report.
+				continue
+			}
+
+			// The output name is the next attribute step, skipping over any
+			// index steps from count or for_each expansion.
+			var outputName string
+			for _, rawStep := range traversal[2:] {
+				if step, ok := rawStep.(hcl.TraverseAttr); ok {
+					outputName = step.Name
+					break
+				}
+			}
+			if outputName == "" {
+				continue
+			}
+
+			if _, declared := child.Module.Outputs[outputName]; !declared {
+				diags = diags.Append(validateRuleDiag(
+					"undeclared-output",
+					"Reference to undeclared output",
+					fmt.Sprintf("The module %q does not declare an output value named %q.", callStep.Name, outputName),
+					traversal.SourceRange(),
+				))
+			}
+		}
+	})
+	return diags
+}
+
+// checkRuleProviderVersions reports providers that are used by a module
+// without a version constraint in its required_providers block.
+func checkRuleProviderVersions(cfg *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	cfg.DeepEach(func(c *configs.Config) {
+		m := c.Module
+
+		type providerUse struct {
+			provider addrs.Provider
+			rng      hcl.Range
+		}
+		seen := make(map[addrs.Provider]bool)
+		var uses []providerUse
+		recordUse := func(provider addrs.Provider, rng hcl.Range) {
+			if !seen[provider] {
+				seen[provider] = true
+				uses = append(uses, providerUse{provider, rng})
+			}
+		}
+		for _, pc := range m.ProviderConfigs {
+			recordUse(m.ProviderForLocalConfig(addrs.LocalProviderConfig{LocalName: pc.Name}), pc.DeclRange)
+		}
+		for _, r := range m.ManagedResources {
+			recordUse(r.Provider, r.DeclRange)
+		}
+		for _, r := range m.DataResources {
+			recordUse(r.Provider, r.DeclRange)
+		}
+		sort.Slice(uses, func(i, j int) bool {
+			return uses[i].provider.String() < uses[j].provider.String()
+		})
+
+		for _, use := range uses {
+			if use.provider.IsBuiltIn() {
+				// Built-in providers always match the version of Terraform
+				// itself, so a version constraint would be meaningless.
+				continue
+			}
+			constrained := false
+			if m.ProviderRequirements != nil {
+				for _, rp := range m.ProviderRequirements.RequiredProviders {
+					if rp.Type.Equals(use.provider) && len(rp.Requirement.Required) > 0 {
+						constrained = true
+						break
+					}
+				}
+			}
+			if !constrained {
+				diags = diags.Append(validateRuleDiag(
+					"provider-version",
+					"Provider without version constraint",
+					fmt.Sprintf("The module uses provider %s without a version constraint in its required_providers block, so different runs may select different and possibly incompatible provider versions.", use.provider.ForDisplay()),
+					use.rng,
+				))
+			}
+		}
+	})
+	return diags
+}
+
+// moduleLintReferences collects every traversal referenced by expressions in
+// the given module, for use by lint rules. The second return value is true
+// if the module contains configuration bodies that cannot be analyzed
+// without a provider schema, such as JSON syntax files, in which case the
+// returned references are incomplete and rules must not report absences.
+func moduleLintReferences(m *configs.Module) ([]hcl.Traversal, bool) {
+	var refs []hcl.Traversal
+	opaque := false
+
+	appendExpr := func(expr hcl.Expression) {
+		if expr == nil {
+			return
+		}
+		refs = append(refs, expr.Variables()...)
+	}
+	var appendBody func(body hcl.Body)
+	appendBody = func(body hcl.Body) {
+		if body == nil {
+			return
+		}
+		sb, ok := body.(*hclsyntax.Body)
+		if !ok {
+			opaque = true
+			return
+		}
+		for _, attr := range sb.Attributes {
+			appendExpr(attr.Expr)
+		}
+		for _, block := range sb.Blocks {
+			appendBody(block.Body)
+		}
+	}
+	appendCheckRules := func(rules []*configs.CheckRule) {
+		for _, rule := range rules {
+			appendExpr(rule.Condition)
+			appendExpr(rule.ErrorMessage)
+		}
+	}
+
+	for _, pc := range m.ProviderConfigs {
+		appendBody(pc.Config)
+	}
+	for _, l := range m.Locals {
+		appendExpr(l.Expr)
+	}
+	for _, o := range m.Outputs {
+		appendExpr(o.Expr)
+		appendCheckRules(o.Preconditions)
+		refs = append(refs, o.DependsOn...)
+	}
+	for _, mc := range m.ModuleCalls {
+		appendBody(mc.Config)
+		appendExpr(mc.Count)
+		appendExpr(mc.ForEach)
+		refs = append(refs, mc.DependsOn...)
+	}
+	appendResource := func(r *configs.Resource) {
+		appendBody(r.Config)
+		appendExpr(r.Count)
+		appendExpr(r.ForEach)
+		for _, expr := range r.TriggersReplacement {
+			appendExpr(expr)
+		}
+		appendCheckRules(r.Preconditions)
+		appendCheckRules(r.Postconditions)
+		refs = append(refs, r.DependsOn...)
+		if r.Managed != nil {
+			if r.Managed.Connection != nil {
+				appendBody(r.Managed.Connection.Config)
+			}
+			for _, p := range r.Managed.Provisioners {
+				appendBody(p.Config)
+				if p.Connection != nil {
+					appendBody(p.Connection.Config)
+				}
+			}
+		}
+	}
+	for _, r := range m.ManagedResources {
+		appendResource(r)
+	}
+	for _, r := range m.DataResources {
+		appendResource(r)
+	}
+
+	return refs, opaque
+}
@@ -264,3 +264,103 @@ func TestValidate_json(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_rules(t *testing.T) {
+	// The lint rules need the module manifest from the fixture's .terraform
+	// directory, so we must run from inside a copy of the fixture.
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("validate-rules"), td)
+	defer testChdir(t, td)()
+
+	run := func(t *testing.T, args ...string) (*terminal.TestOutput, int) {
+		view, done := testView(t)
+		p := testProvider()
+		p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+			ResourceTypes: map[string]providers.Schema{
+				"test_instance": {
+					Block: &configschema.Block{
+						Attributes: map[string]*configschema.Attribute{
+							"ami": {Type: cty.String, Optional: true},
+						},
+					},
+				},
+			},
+		}
+		c := &ValidateCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+		args = append(args, "-no-color")
+		code := c.Run(args)
+		return done(t), code
+	}
+
+	t.Run("all rules", func(t *testing.T) {
+		output, code := run(t, "-rules=all")
+		if code != 0 {
+			t.Fatalf("unexpected exit code %d\n\n%s", code, output.Stderr())
+		}
+		wants := []string{
+			"Unused variable",
+			`The variable "unused" is declared but not used`,
+			"(rule: unused-variable)",
+			"Provider without version constraint",
+			"(rule: provider-version)",
+		}
+		got := output.Stdout()
+		for _, want := range wants {
+			if !strings.Contains(got, want) {
+				t.Errorf("output is missing %q:\n%s", want, got)
+			}
+		}
+		if strings.Contains(got, "undeclared-output") {
+			t.Errorf("output mentions undeclared-output, but all references are declared:\n%s", got)
+		}
+	})
+
+	t.Run("no rules requested", func(t *testing.T) {
+		output, code := run(t)
+		if code != 0 {
+			t.Fatalf("unexpected exit code %d\n\n%s", code, output.Stderr())
+		}
+		if got := output.Stdout(); strings.Contains(got, "rule:") {
+			t.Errorf("output mentions rules although none were requested:\n%s", got)
+		}
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		output, code := run(t, "-rules=nonsense")
+		if code != 1 {
+			t.Fatalf("expected exit code 1, got %d", code)
+		}
+		if got := output.Stderr(); !strings.Contains(got, "Unknown validation rule") {
+			t.Errorf("expected unknown rule error, got:\n%s", got)
+		}
+	})
+
+	t.Run("undeclared output", func(t *testing.T) {
+		// Point the output at an output the child module doesn't declare.
+		main, err := ioutil.ReadFile("main.tf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		broken := strings.Replace(string(main), "module.child.real", "module.child.missing", 1)
+		if err := ioutil.WriteFile("main.tf", []byte(broken), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer ioutil.WriteFile("main.tf", main, 0644)
+
+		// Core validation reports its own error for the reference, so we
+		// only care that the rule's finding is present too.
+		output, _ := run(t, "-rules=undeclared-output")
+		got := output.Stdout() + output.Stderr()
+		if !strings.Contains(got, `The module "child" does not declare an output value named "missing"`) {
+			t.Errorf("output is missing the undeclared-output finding:\n%s", got)
+		}
+		if !strings.Contains(got, "(rule: undeclared-output)") {
+			t.Errorf("output is missing the rule code:\n%s", got)
+		}
+	})
+}
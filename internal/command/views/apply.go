@@ -14,6 +14,7 @@ import (
 // The Apply view is used for the apply command.
 type Apply interface {
 	ResourceCount(stateOutPath string)
+	ResourceCounts() (added, changed, removed int)
 	Outputs(outputValues map[string]*states.OutputValue)
 
 	Operation() Operation
@@ -95,6 +96,10 @@ func (v *ApplyHuman) Hooks() []terraform.Hook {
 	}
 }
 
+func (v *ApplyHuman) ResourceCounts() (added, changed, removed int) {
+	return v.countHook.Added, v.countHook.Changed, v.countHook.Removed
+}
+
 func (v *ApplyHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -154,6 +159,10 @@ func (v *ApplyJSON) Hooks() []terraform.Hook {
 	}
 }
 
+func (v *ApplyJSON) ResourceCounts() (added, changed, removed int) {
+	return v.countHook.Added, v.countHook.Changed, v.countHook.Removed
+}
+
 func (v *ApplyJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
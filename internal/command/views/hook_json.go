@@ -21,10 +21,11 @@ const heartbeatInterval = 10 * time.Second
 
 func newJSONHook(view *JSONView) *jsonHook {
 	return &jsonHook{
-		view:      view,
-		applying:  make(map[string]applyProgress),
-		timeNow:   time.Now,
-		timeAfter: time.After,
+		view:        view,
+		applying:    make(map[string]applyProgress),
+		applyCounts: make(map[string]int),
+		timeNow:     time.Now,
+		timeAfter:   time.After,
 	}
 }
 
@@ -38,9 +39,22 @@ type jsonHook struct {
 	applying     map[string]applyProgress
 	applyingLock sync.Mutex
 
+	// applyPhaseStart is the time of the first PreApply call, used as the
+	// baseline when reporting how long each later resource instance waited
+	// before its own work began. applyCounts tracks how many times we've
+	// seen PreApply for the same instance and action, so that repeated
+	// attempts within one run can be reported as retries. Both are guarded
+	// by applyingLock.
+	applyPhaseStart time.Time
+	applyCounts     map[string]int
+
 	// Mockable functions for testing the progress timer goroutine
 	timeNow   func() time.Time
 	timeAfter func(time.Duration) <-chan time.Time
+
+	refreshTotal int
+	refreshCount int
+	refreshLock  sync.Mutex
 }
 
 var _ terraform.Hook = (*jsonHook)(nil)
@@ -50,6 +64,12 @@ type applyProgress struct {
 	action plans.Action
 	start  time.Time
 
+	// waiting is how long the instance waited between the start of the
+	// apply phase and the start of its own work, and retries is how many
+	// earlier attempts this run already made at the same action.
+	waiting time.Duration
+	retries int
+
 	// done is used for post-apply to stop the progress goroutine
 	done chan struct{}
 
@@ -69,7 +89,14 @@ func (h *jsonHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generatio
 		done:          make(chan struct{}),
 		heartbeatDone: make(chan struct{}),
 	}
+	countKey := addr.String() + "|" + action.String()
 	h.applyingLock.Lock()
+	if h.applyPhaseStart.IsZero() {
+		h.applyPhaseStart = progress.start
+	}
+	progress.waiting = progress.start.Sub(h.applyPhaseStart)
+	progress.retries = h.applyCounts[countKey]
+	h.applyCounts[countKey]++
 	h.applying[addr.String()] = progress
 	h.applyingLock.Unlock()
 
@@ -107,10 +134,10 @@ func (h *jsonHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generati
 		// Errors are collected and displayed post-apply, so no need to
 		// re-render them here. Instead just signal that this resource failed
 		// to apply.
-		h.view.Hook(json.NewApplyErrored(addr, progress.action, elapsed))
+		h.view.Hook(json.NewApplyErrored(addr, progress.action, elapsed, progress.waiting, progress.retries))
 	} else {
 		idKey, idValue := format.ObjectValueID(newState)
-		h.view.Hook(json.NewApplyComplete(addr, progress.action, idKey, idValue, elapsed))
+		h.view.Hook(json.NewApplyComplete(addr, progress.action, idKey, idValue, elapsed, progress.waiting, progress.retries))
 	}
 	return terraform.HookActionContinue, nil
 }
@@ -149,8 +176,25 @@ func (h *jsonHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generat
 	return terraform.HookActionContinue, nil
 }
 
+func (h *jsonHook) RefreshTotal(total int) (terraform.HookAction, error) {
+	h.refreshLock.Lock()
+	h.refreshTotal = total
+	h.refreshCount = 0
+	h.refreshLock.Unlock()
+	return terraform.HookActionContinue, nil
+}
+
 func (h *jsonHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value, newState cty.Value) (terraform.HookAction, error) {
 	idKey, idValue := format.ObjectValueID(newState)
 	h.view.Hook(json.NewRefreshComplete(addr, idKey, idValue))
+
+	h.refreshLock.Lock()
+	h.refreshCount++
+	count, total := h.refreshCount, h.refreshTotal
+	h.refreshLock.Unlock()
+
+	if total >= refreshProgressEvery && (count%refreshProgressEvery == 0 || count == total) {
+		h.view.Hook(json.NewRefreshProgress(count, total))
+	}
 	return terraform.HookActionContinue, nil
 }
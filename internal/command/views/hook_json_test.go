@@ -147,9 +147,10 @@ func TestJSONHook_create(t *testing.T) {
 			"@module":  "terraform.ui",
 			"type":     "apply_progress",
 			"hook": map[string]interface{}{
-				"action":          string("create"),
-				"elapsed_seconds": float64(10),
-				"resource":        wantResource,
+				"action":               string("create"),
+				"elapsed_seconds":      float64(10),
+				"elapsed_milliseconds": float64(10000),
+				"resource":             wantResource,
 			},
 		},
 		{
@@ -158,9 +159,10 @@ func TestJSONHook_create(t *testing.T) {
 			"@module":  "terraform.ui",
 			"type":     "apply_progress",
 			"hook": map[string]interface{}{
-				"action":          string("create"),
-				"elapsed_seconds": float64(20),
-				"resource":        wantResource,
+				"action":               string("create"),
+				"elapsed_seconds":      float64(20),
+				"elapsed_milliseconds": float64(20000),
+				"resource":             wantResource,
 			},
 		},
 		{
@@ -169,11 +171,13 @@ func TestJSONHook_create(t *testing.T) {
 			"@module":  "terraform.ui",
 			"type":     "apply_complete",
 			"hook": map[string]interface{}{
-				"action":          string("create"),
-				"elapsed_seconds": float64(22),
-				"id_key":          "id",
-				"id_value":        "test",
-				"resource":        wantResource,
+				"action":               string("create"),
+				"elapsed_seconds":      float64(22),
+				"elapsed_milliseconds": float64(22000),
+				"waiting_milliseconds": float64(0),
+				"id_key":               "id",
+				"id_value":             "test",
+				"resource":             wantResource,
 			},
 		},
 	}
@@ -257,9 +261,11 @@ func TestJSONHook_errors(t *testing.T) {
 			"@module":  "terraform.ui",
 			"type":     "apply_errored",
 			"hook": map[string]interface{}{
-				"action":          string("delete"),
-				"elapsed_seconds": float64(0),
-				"resource":        wantResource,
+				"action":               string("delete"),
+				"elapsed_seconds":      float64(0),
+				"elapsed_milliseconds": float64(0),
+				"waiting_milliseconds": float64(0),
+				"resource":             wantResource,
 			},
 		},
 	}
@@ -326,6 +332,67 @@ func TestJSONHook_refresh(t *testing.T) {
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
 
+func TestJSONHook_refreshProgress(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	hook := newJSONHook(NewJSONView(NewView(streams)))
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "boop",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	state := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("honk"),
+	})
+
+	action, err := hook.RefreshTotal(10)
+	testHookReturnValues(t, action, err)
+
+	for i := 0; i < 10; i++ {
+		action, err = hook.PostRefresh(addr, states.CurrentGen, state, state)
+		testHookReturnValues(t, action, err)
+	}
+
+	// Every completion emits a refresh_complete message, and the tenth
+	// also emits a refresh_progress message.
+	want := make([]map[string]interface{}, 0, 11)
+	for i := 0; i < 10; i++ {
+		want = append(want, map[string]interface{}{
+			"@level":   "info",
+			"@message": "test_instance.boop: Refresh complete [id=honk]",
+			"@module":  "terraform.ui",
+			"type":     "refresh_complete",
+			"hook": map[string]interface{}{
+				"resource": map[string]interface{}{
+					"addr":             string("test_instance.boop"),
+					"implied_provider": string("test"),
+					"module":           string(""),
+					"resource":         string("test_instance.boop"),
+					"resource_key":     nil,
+					"resource_name":    string("boop"),
+					"resource_type":    string("test_instance"),
+				},
+				"id_key":   "id",
+				"id_value": "honk",
+			},
+		})
+		if i == 9 {
+			want = append(want, map[string]interface{}{
+				"@level":   "info",
+				"@message": "10 of 10 resources refreshed",
+				"@module":  "terraform.ui",
+				"type":     "refresh_progress",
+				"hook": map[string]interface{}{
+					"refreshed": float64(10),
+					"total":     float64(10),
+				},
+			})
+		}
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
 func testHookReturnValues(t *testing.T, action terraform.HookAction, err error) {
 	t.Helper()
 
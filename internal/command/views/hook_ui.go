@@ -22,6 +22,11 @@ import (
 const defaultPeriodicUiTimer = 10 * time.Second
 const maxIdLen = 80
 
+// How many refresh completions to wait between "N of M resources refreshed"
+// progress messages. Progress reporting is suppressed entirely for walks
+// refreshing fewer instances than this, where it would just be noise.
+const refreshProgressEvery = 10
+
 func NewUiHook(view *View) *UiHook {
 	return &UiHook{
 		view:            view,
@@ -40,6 +45,10 @@ type UiHook struct {
 
 	resources     map[string]uiResourceState
 	resourcesLock sync.Mutex
+
+	refreshTotal int
+	refreshCount int
+	refreshLock  sync.Mutex
 }
 
 var _ terraform.Hook = (*UiHook)(nil)
@@ -268,6 +277,29 @@ func (h *UiHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generatio
 	return terraform.HookActionContinue, nil
 }
 
+func (h *UiHook) RefreshTotal(total int) (terraform.HookAction, error) {
+	h.refreshLock.Lock()
+	h.refreshTotal = total
+	h.refreshCount = 0
+	h.refreshLock.Unlock()
+	return terraform.HookActionContinue, nil
+}
+
+func (h *UiHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value, newState cty.Value) (terraform.HookAction, error) {
+	h.refreshLock.Lock()
+	h.refreshCount++
+	count, total := h.refreshCount, h.refreshTotal
+	h.refreshLock.Unlock()
+
+	if total >= refreshProgressEvery && (count%refreshProgressEvery == 0 || count == total) {
+		h.println(fmt.Sprintf(
+			h.view.colorize.Color("[reset][bold]%d of %d resources refreshed"),
+			count, total,
+		))
+	}
+	return terraform.HookActionContinue, nil
+}
+
 func (h *UiHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (terraform.HookAction, error) {
 	h.println(fmt.Sprintf(
 		h.view.colorize.Color("[reset][bold]%s: Importing from ID %q..."),
@@ -296,6 +296,77 @@ func TestUiHookPostApply_emptyState(t *testing.T) {
 	}
 }
 
+func TestUiHookRefreshProgress(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	h := NewUiHook(view)
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "foo",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	state := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("test"),
+	})
+
+	action, err := h.RefreshTotal(25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action != terraform.HookActionContinue {
+		t.Fatalf("Expected hook to continue, given: %#v", action)
+	}
+
+	for i := 0; i < 25; i++ {
+		action, err := h.PostRefresh(addr, states.CurrentGen, state, state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if action != terraform.HookActionContinue {
+			t.Fatalf("Expected hook to continue, given: %#v", action)
+		}
+	}
+
+	result := done(t)
+	expectedOutput := "10 of 25 resources refreshed\n20 of 25 resources refreshed\n25 of 25 resources refreshed\n"
+	output := result.Stdout()
+	if output != expectedOutput {
+		t.Fatalf("Output didn't match.\nExpected: %q\nGiven: %q", expectedOutput, output)
+	}
+}
+
+func TestUiHookRefreshProgress_smallTotal(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	h := NewUiHook(view)
+
+	addr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "foo",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	state := cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("test"),
+	})
+
+	// Progress reporting is suppressed for small refreshes, where it
+	// would just be noise.
+	if _, err := h.RefreshTotal(3); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := h.PostRefresh(addr, states.CurrentGen, state, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := done(t)
+	if output := result.Stdout(); output != "" {
+		t.Fatalf("Expected no output, given: %q", output)
+	}
+}
+
 func TestPreProvisionInstanceStep(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	view := NewView(streams)
@@ -0,0 +1,197 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/internal/command/arguments"
+	"github.com/hashicorp/terraform/internal/command/views/json"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// The Init view is used by the init command.
+//
+// The human-oriented output of init predates the views abstraction and is
+// still rendered directly by the command via cli.Ui, so the human
+// implementation of this view is mostly a no-op. The JSON implementation
+// emits typed machine-readable messages for the main initialization steps,
+// along with log messages for the command's free-form text output.
+type Init interface {
+	Diagnostics(diags tfdiags.Diagnostics)
+
+	// Log and LogError emit free-form informational and error messages
+	// respectively, from output that has no more specific message type.
+	Log(message string)
+	LogError(message string)
+
+	// BackendInitialized reports that the backend of the given type was
+	// successfully configured.
+	BackendInitialized(backendType string)
+
+	// ModuleDownload reports that the module at the given path within the
+	// configuration is being fetched from the given remote package.
+	// The version is empty for non-registry modules.
+	ModuleDownload(modulePath, packageAddr, version string)
+
+	// ModuleInstalled reports that the module at the given path was
+	// installed into the given local directory.
+	ModuleInstalled(modulePath, version, localDir string)
+
+	// ProviderVersionSelected reports that the given version of a provider
+	// was selected for installation.
+	ProviderVersionSelected(provider, version string)
+
+	// ProviderDownload reports that a provider package is being fetched
+	// from the given location. The source argument classifies what kind
+	// of source is delivering the package — "registry", "network_mirror",
+	// or "filesystem_mirror" — and is empty when the source didn't say.
+	ProviderDownload(provider, version, location, source string)
+
+	// ProviderInstalled reports that a provider package was installed,
+	// along with the signature verification result for its checksums
+	// ("unauthenticated" when verification wasn't possible) and whether
+	// the package was verified against hashes already recorded in the
+	// dependency lock file.
+	ProviderInstalled(provider, version, authResult string, hashesVerified bool)
+
+	// LockFileUpdated reports that the dependency lock file at the given
+	// path was created or modified.
+	LockFileUpdated(path string)
+}
+
+// NewInit returns an initialized Init implementation for the given ViewType.
+func NewInit(vt arguments.ViewType, view *View) Init {
+	switch vt {
+	case arguments.ViewJSON:
+		return &InitJSON{view: NewJSONView(view)}
+	case arguments.ViewHuman:
+		return &InitHuman{view: view}
+	default:
+		panic(fmt.Sprintf("unknown view type %v", vt))
+	}
+}
+
+// The InitHuman implementation renders only diagnostics; all other output
+// for the human UI is produced directly by the init command itself.
+type InitHuman struct {
+	view *View
+}
+
+var _ Init = (*InitHuman)(nil)
+
+func (v *InitHuman) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *InitHuman) Log(message string)                    {}
+func (v *InitHuman) LogError(message string)               {}
+func (v *InitHuman) BackendInitialized(backendType string) {}
+func (v *InitHuman) ModuleDownload(modulePath, packageAddr, version string) {
+}
+func (v *InitHuman) ModuleInstalled(modulePath, version, localDir string) {}
+func (v *InitHuman) ProviderVersionSelected(provider, version string)     {}
+func (v *InitHuman) ProviderDownload(provider, version, location, source string) {
+}
+func (v *InitHuman) ProviderInstalled(provider, version, authResult string, hashesVerified bool) {
+}
+func (v *InitHuman) LockFileUpdated(path string) {}
+
+// The InitJSON implementation renders streaming JSON logs, suitable for
+// integrating with other software.
+type InitJSON struct {
+	view *JSONView
+}
+
+var _ Init = (*InitJSON)(nil)
+
+func (v *InitJSON) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+func (v *InitJSON) Log(message string) {
+	v.view.Log(message)
+}
+
+func (v *InitJSON) LogError(message string) {
+	v.view.log.Error(message, "type", json.MessageLog)
+}
+
+func (v *InitJSON) BackendInitialized(backendType string) {
+	v.view.log.Info(
+		fmt.Sprintf("Initialized the %q backend", backendType),
+		"type", json.MessageInitBackend,
+		"backend_type", backendType,
+	)
+}
+
+func (v *InitJSON) ModuleDownload(modulePath, packageAddr, version string) {
+	message := fmt.Sprintf("Downloading %s for %s...", packageAddr, modulePath)
+	args := []interface{}{
+		"type", json.MessageInitModuleDownload,
+		"module", modulePath,
+		"source", packageAddr,
+	}
+	if version != "" {
+		message = fmt.Sprintf("Downloading %s %s for %s...", packageAddr, version, modulePath)
+		args = append(args, "version", version)
+	}
+	v.view.log.Info(message, args...)
+}
+
+func (v *InitJSON) ModuleInstalled(modulePath, version, localDir string) {
+	args := []interface{}{
+		"type", json.MessageInitModuleInstall,
+		"module", modulePath,
+		"local_dir", localDir,
+	}
+	if version != "" {
+		args = append(args, "version", version)
+	}
+	v.view.log.Info(
+		fmt.Sprintf("Installed %s in %s", modulePath, localDir),
+		args...,
+	)
+}
+
+func (v *InitJSON) ProviderVersionSelected(provider, version string) {
+	v.view.log.Info(
+		fmt.Sprintf("Selected %s v%s", provider, version),
+		"type", json.MessageInitProviderSelection,
+		"provider", provider,
+		"version", version,
+	)
+}
+
+func (v *InitJSON) ProviderDownload(provider, version, location, source string) {
+	args := []interface{}{
+		"type", json.MessageInitProviderDownload,
+		"provider", provider,
+		"version", version,
+		"location", location,
+	}
+	if source != "" {
+		args = append(args, "source", source)
+	}
+	v.view.log.Info(
+		fmt.Sprintf("Downloading %s v%s from %s", provider, version, location),
+		args...,
+	)
+}
+
+func (v *InitJSON) ProviderInstalled(provider, version, authResult string, hashesVerified bool) {
+	v.view.log.Info(
+		fmt.Sprintf("Installed %s v%s (%s)", provider, version, authResult),
+		"type", json.MessageInitProviderInstall,
+		"provider", provider,
+		"version", version,
+		"auth_result", authResult,
+		"hashes_verified", hashesVerified,
+	)
+}
+
+func (v *InitJSON) LockFileUpdated(path string) {
+	v.view.log.Info(
+		fmt.Sprintf("Updated the dependency lock file at %s", path),
+		"type", json.MessageInitLockFile,
+		"path", path,
+	)
+}
@@ -15,6 +15,12 @@ type ChangeSummary struct {
 	Change    int       `json:"change"`
 	Remove    int       `json:"remove"`
 	Operation Operation `json:"operation"`
+
+	// Drift counts the resource instances whose remote objects were found
+	// to have changed outside of Terraform during planning, matching the
+	// number of resource_drift messages emitted beforehand. It is set only
+	// for plan operations and omitted when zero.
+	Drift int `json:"drift,omitempty"`
 }
 
 // The summary strings for apply and plan are accidentally a public interface
@@ -54,6 +54,7 @@ type applyProgress struct {
 	Resource   ResourceAddr `json:"resource"`
 	Action     ChangeAction `json:"action"`
 	Elapsed    float64      `json:"elapsed_seconds"`
+	ElapsedMS  int64        `json:"elapsed_milliseconds"`
 	actionVerb string
 	elapsed    time.Duration
 }
@@ -73,6 +74,7 @@ func NewApplyProgress(addr addrs.AbsResourceInstance, action plans.Action, elaps
 		Resource:   newResourceAddr(addr),
 		Action:     changeAction(action),
 		Elapsed:    elapsed.Seconds(),
+		ElapsedMS:  elapsed.Milliseconds(),
 		actionVerb: progressActionVerb(action),
 		elapsed:    elapsed,
 	}
@@ -80,11 +82,24 @@ func NewApplyProgress(addr addrs.AbsResourceInstance, action plans.Action, elaps
 
 // ApplyComplete: triggered by PostApply hook
 type applyComplete struct {
-	Resource   ResourceAddr `json:"resource"`
-	Action     ChangeAction `json:"action"`
-	IDKey      string       `json:"id_key,omitempty"`
-	IDValue    string       `json:"id_value,omitempty"`
-	Elapsed    float64      `json:"elapsed_seconds"`
+	Resource  ResourceAddr `json:"resource"`
+	Action    ChangeAction `json:"action"`
+	IDKey     string       `json:"id_key,omitempty"`
+	IDValue   string       `json:"id_value,omitempty"`
+	Elapsed   float64      `json:"elapsed_seconds"`
+	ElapsedMS int64        `json:"elapsed_milliseconds"`
+
+	// WaitingMS is how long the resource instance waited between the start
+	// of the apply phase and the start of its own work, which is mostly
+	// time spent waiting for its dependencies and for a free slot under
+	// the configured parallelism.
+	WaitingMS int64 `json:"waiting_milliseconds"`
+
+	// Retries is how many times this resource instance's work was attempted
+	// again after an earlier attempt in the same run, when that information
+	// is available. It is omitted when zero.
+	Retries int `json:"retries,omitempty"`
+
 	actionNoun string
 	elapsed    time.Duration
 }
@@ -103,13 +118,16 @@ func (h *applyComplete) String() string {
 	return fmt.Sprintf("%s: %s complete after %s%s", h.Resource.Addr, h.actionNoun, h.elapsed, id)
 }
 
-func NewApplyComplete(addr addrs.AbsResourceInstance, action plans.Action, idKey, idValue string, elapsed time.Duration) Hook {
+func NewApplyComplete(addr addrs.AbsResourceInstance, action plans.Action, idKey, idValue string, elapsed, waiting time.Duration, retries int) Hook {
 	return &applyComplete{
 		Resource:   newResourceAddr(addr),
 		Action:     changeAction(action),
 		IDKey:      idKey,
 		IDValue:    idValue,
 		Elapsed:    elapsed.Seconds(),
+		ElapsedMS:  elapsed.Milliseconds(),
+		WaitingMS:  waiting.Milliseconds(),
+		Retries:    retries,
 		actionNoun: actionNoun(action),
 		elapsed:    elapsed,
 	}
@@ -118,9 +136,15 @@ func NewApplyComplete(addr addrs.AbsResourceInstance, action plans.Action, idKey
 // ApplyErrored: triggered by PostApply hook on failure. This will be followed
 // by diagnostics when the apply finishes.
 type applyErrored struct {
-	Resource   ResourceAddr `json:"resource"`
-	Action     ChangeAction `json:"action"`
-	Elapsed    float64      `json:"elapsed_seconds"`
+	Resource  ResourceAddr `json:"resource"`
+	Action    ChangeAction `json:"action"`
+	Elapsed   float64      `json:"elapsed_seconds"`
+	ElapsedMS int64        `json:"elapsed_milliseconds"`
+
+	// WaitingMS and Retries have the same meaning as for applyComplete.
+	WaitingMS int64 `json:"waiting_milliseconds"`
+	Retries   int   `json:"retries,omitempty"`
+
 	actionNoun string
 	elapsed    time.Duration
 }
@@ -135,11 +159,14 @@ func (h *applyErrored) String() string {
 	return fmt.Sprintf("%s: %s errored after %s", h.Resource.Addr, h.actionNoun, h.elapsed)
 }
 
-func NewApplyErrored(addr addrs.AbsResourceInstance, action plans.Action, elapsed time.Duration) Hook {
+func NewApplyErrored(addr addrs.AbsResourceInstance, action plans.Action, elapsed, waiting time.Duration, retries int) Hook {
 	return &applyErrored{
 		Resource:   newResourceAddr(addr),
 		Action:     changeAction(action),
 		Elapsed:    elapsed.Seconds(),
+		ElapsedMS:  elapsed.Milliseconds(),
+		WaitingMS:  waiting.Milliseconds(),
+		Retries:    retries,
 		actionNoun: actionNoun(action),
 		elapsed:    elapsed,
 	}
@@ -298,6 +325,30 @@ func NewRefreshComplete(addr addrs.AbsResourceInstance, idKey, idValue string) H
 	}
 }
 
+// RefreshProgress: triggered periodically by the PostRefresh hook to report
+// overall progress through a large refresh
+type refreshProgress struct {
+	Refreshed int `json:"refreshed"`
+	Total     int `json:"total"`
+}
+
+var _ Hook = (*refreshProgress)(nil)
+
+func (h *refreshProgress) HookType() MessageType {
+	return MessageRefreshProgress
+}
+
+func (h *refreshProgress) String() string {
+	return fmt.Sprintf("%d of %d resources refreshed", h.Refreshed, h.Total)
+}
+
+func NewRefreshProgress(refreshed, total int) Hook {
+	return &refreshProgress{
+		Refreshed: refreshed,
+		Total:     total,
+	}
+}
+
 // Convert the subset of plans.Action values we expect to receive into a
 // present-tense verb for the applyStart hook message.
 func startActionVerb(action plans.Action) string {
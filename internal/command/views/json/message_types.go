@@ -24,5 +24,15 @@ const (
 	MessageProvisionComplete MessageType = "provision_complete"
 	MessageProvisionErrored  MessageType = "provision_errored"
 	MessageRefreshStart      MessageType = "refresh_start"
+	MessageRefreshProgress   MessageType = "refresh_progress"
 	MessageRefreshComplete   MessageType = "refresh_complete"
+
+	// Init messages
+	MessageInitBackend           MessageType = "init_backend"
+	MessageInitModuleDownload    MessageType = "init_module_download"
+	MessageInitModuleInstall     MessageType = "init_module_install"
+	MessageInitProviderSelection MessageType = "init_provider_selection"
+	MessageInitProviderDownload  MessageType = "init_provider_download"
+	MessageInitProviderInstall   MessageType = "init_provider_install"
+	MessageInitLockFile          MessageType = "init_lock_file"
 )
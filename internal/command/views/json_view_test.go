@@ -221,7 +221,7 @@ func TestJSONView_Hook(t *testing.T) {
 	}
 	managed := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "bar"}
 	addr := managed.Instance(addrs.StringKey("boop")).Absolute(foo)
-	hook := viewsjson.NewApplyComplete(addr, plans.Create, "id", "boop-beep", 34*time.Second)
+	hook := viewsjson.NewApplyComplete(addr, plans.Create, "id", "boop-beep", 34*time.Second, 5*time.Second, 1)
 
 	jv.Hook(hook)
 
@@ -241,10 +241,13 @@ func TestJSONView_Hook(t *testing.T) {
 					"resource_name":    "bar",
 					"resource_type":    "test_instance",
 				},
-				"action":          "create",
-				"id_key":          "id",
-				"id_value":        "boop-beep",
-				"elapsed_seconds": float64(34),
+				"action":               "create",
+				"id_key":               "id",
+				"id_value":             "boop-beep",
+				"elapsed_seconds":      float64(34),
+				"elapsed_milliseconds": float64(34000),
+				"waiting_milliseconds": float64(5000),
+				"retries":              float64(1),
 			},
 		},
 	}
@@ -160,6 +160,7 @@ func (v *OperationJSON) EmergencyDumpState(stateFile *statefile.File) error {
 // Log a change summary and a series of "planned" messages for the changes in
 // the plan.
 func (v *OperationJSON) Plan(plan *plans.Plan, schemas *terraform.Schemas) {
+	var driftCount int
 	for _, dr := range plan.DriftedResources {
 		// In refresh-only mode, we output all resources marked as drifted,
 		// including those which have moved without other changes. In other plan
@@ -167,11 +168,13 @@ func (v *OperationJSON) Plan(plan *plans.Plan, schemas *terraform.Schemas) {
 		// we skip them here.
 		if dr.Action != plans.NoOp || plan.UIMode == plans.RefreshOnlyMode {
 			v.view.ResourceDrift(json.NewResourceInstanceChange(dr))
+			driftCount++
 		}
 	}
 
 	cs := &json.ChangeSummary{
 		Operation: json.OperationPlanned,
+		Drift:     driftCount,
 	}
 	for _, change := range plan.Changes.Resources {
 		if change.Action == plans.Delete && change.Addr.Resource.Resource.Mode == addrs.DataResourceMode {
@@ -806,6 +806,7 @@ func TestOperationJSON_planDriftWithMove(t *testing.T) {
 				"add":       float64(0),
 				"change":    float64(0),
 				"remove":    float64(0),
+				"drift":     float64(2),
 			},
 		},
 	}
@@ -936,6 +937,7 @@ func TestOperationJSON_planDriftWithMoveRefreshOnly(t *testing.T) {
 				"add":       float64(0),
 				"change":    float64(0),
 				"remove":    float64(0),
+				"drift":     float64(3),
 			},
 		},
 	}
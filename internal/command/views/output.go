@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
@@ -31,6 +32,10 @@ func NewOutput(vt arguments.ViewType, view *View) Output {
 		return &OutputJSON{view: view}
 	case arguments.ViewRaw:
 		return &OutputRaw{view: view}
+	case arguments.ViewYAML:
+		return &OutputYAML{view: view}
+	case arguments.ViewShell:
+		return &OutputShell{view: view}
 	case arguments.ViewHuman:
 		return &OutputHuman{view: view}
 	default:
@@ -254,6 +259,170 @@ func (v *OutputJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
+// The OutputYAML implementation renders outputs as YAML documents. When
+// rendering a single output, only the value is displayed. When rendering all
+// outputs, the result is a YAML mapping with keys matching the output names.
+type OutputYAML struct {
+	view *View
+}
+
+var _ Output = (*OutputYAML)(nil)
+
+func (v *OutputYAML) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if name != "" {
+		output, ok := outputs[name]
+		if !ok {
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
+
+		yamlOutput, err := ctyyaml.Marshal(output.Value)
+		if err != nil {
+			diags = diags.Append(err)
+			return diags
+		}
+
+		v.view.streams.Print(string(yamlOutput))
+
+		return nil
+	}
+
+	values := make(map[string]cty.Value, len(outputs))
+	for n, os := range outputs {
+		values[n] = os.Value
+	}
+
+	yamlOutputs, err := ctyyaml.Marshal(cty.ObjectVal(values))
+	if err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+
+	v.view.streams.Print(string(yamlOutputs))
+
+	return nil
+}
+
+func (v *OutputYAML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// The OutputShell implementation renders outputs as NAME=value lines with
+// shell quoting, so that the result can safely be passed to a shell's "eval"
+// to set environment variables from output values. Values of complex types
+// are rendered as their compact JSON representation.
+type OutputShell struct {
+	view *View
+}
+
+var _ Output = (*OutputShell)(nil)
+
+func (v *OutputShell) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if len(outputs) == 0 {
+		diags = diags.Append(noOutputsWarning())
+		return diags
+	}
+
+	names := make([]string, 0, len(outputs))
+	if name != "" {
+		if _, ok := outputs[name]; !ok {
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
+		names = append(names, name)
+	} else {
+		for n := range outputs {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+	}
+
+	for _, n := range names {
+		if !validShellName(n) {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Invalid name for shell output",
+				fmt.Sprintf("The output name %q is not a valid shell variable name, so it is excluded from the shell format output.", n),
+			))
+			continue
+		}
+
+		value, err := shellOutputValue(outputs[n].Value)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Unsupported value for shell output",
+				fmt.Sprintf("Cannot render output value %q in shell format: %s.", n, err),
+			))
+			return diags
+		}
+
+		v.view.streams.Println(fmt.Sprintf("%s=%s", n, shellQuote(value)))
+	}
+
+	return diags
+}
+
+func (v *OutputShell) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+// shellOutputValue produces the raw string to assign to a shell variable for
+// the given output value: primitive values are converted directly to string,
+// while complex values use their compact JSON representation.
+func shellOutputValue(value cty.Value) (string, error) {
+	if value.IsNull() {
+		return "", nil
+	}
+	if value.Type().IsPrimitiveType() {
+		strV, err := convert.Convert(value, cty.String)
+		if err != nil {
+			return "", err
+		}
+		return strV.AsString(), nil
+	}
+	jsonV, err := ctyjson.Marshal(value, value.Type())
+	if err != nil {
+		return "", err
+	}
+	return string(jsonV), nil
+}
+
+// validShellName returns true if the given output name is also a valid
+// POSIX shell variable name, and can therefore appear on the left side of
+// an assignment. Output names may contain characters such as dashes that
+// shell variable names cannot.
+func validShellName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case r >= 'A' && r <= 'Z':
+		case r == '_':
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// shellQuote wraps the given string in single quotes, escaping any single
+// quotes within it, so that the result always parses as a single shell word
+// regardless of the characters it contains.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // For text and raw output modes, an empty map of outputs is considered a
 // separate and higher priority failure mode than an output not being present
 // in a non-empty map. This warning diagnostic explains how this might have
@@ -325,6 +325,84 @@ func TestOutputRaw_all(t *testing.T) {
 	}
 }
 
+// YAML output renders a single value as a bare YAML document, and all
+// outputs as a YAML mapping of the values.
+func TestOutputYAML(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"str": {Value: cty.StringVal("bar")},
+		"obj": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"boop": cty.NumberIntVal(5),
+				"beep": cty.StringVal("true"),
+			}),
+		},
+	}
+
+	t.Run("single", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewOutput(arguments.ViewYAML, NewView(streams))
+
+		diags := v.Output("obj", outputs)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+
+		want := "\"beep\": \"true\"\n\"boop\": 5\n"
+		if got := done(t).Stdout(); got != want {
+			t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		v := NewOutput(arguments.ViewYAML, NewView(streams))
+
+		diags := v.Output("", outputs)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %s", diags)
+		}
+
+		want := "\"obj\":\n  \"beep\": \"true\"\n  \"boop\": 5\n\"str\": \"bar\"\n"
+		if got := done(t).Stdout(); got != want {
+			t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+		}
+	})
+}
+
+// Shell output renders NAME=value lines with shell quoting, using compact
+// JSON for complex values. Output names that aren't valid shell variable
+// names are excluded with a warning.
+func TestOutputShell(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"str":      {Value: cty.StringVal("it's here")},
+		"num":      {Value: cty.NumberIntVal(2)},
+		"list":     {Value: cty.ListVal([]cty.Value{cty.True, cty.False})},
+		"bad-name": {Value: cty.StringVal("nope")},
+	}
+
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOutput(arguments.ViewShell, NewView(streams))
+
+	diags := v.Output("", outputs)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags)
+	}
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 warning diagnostic, got %d", len(diags))
+	}
+	if got, want := diags[0].Description().Summary, "Invalid name for shell output"; got != want {
+		t.Errorf("unexpected diagnostics: %s", diags)
+	}
+
+	want := `list='[true,false]'
+num='2'
+str='it'\''s here'
+`
+	if got := done(t).Stdout(); got != want {
+		t.Errorf("wrong result\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
 // All outputs render an error if a specific output is requested which is
 // missing from the map of outputs.
 func TestOutput_missing(t *testing.T) {
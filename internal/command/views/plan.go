@@ -100,6 +100,8 @@ func (v *PlanJSON) HelpPrompt() {
 // The plan renderer is used by the Operation view (for plan and apply
 // commands) and the Show view (for the show command).
 func renderPlan(plan *plans.Plan, schemas *terraform.Schemas, view *View) {
+	applyRedaction(plan, schemas, view.redactionRules)
+
 	haveRefreshChanges := renderChangesDetectedByRefresh(plan, schemas, view)
 
 	counts := map[plans.Action]int{}
@@ -282,6 +284,7 @@ func renderPlan(plan *plans.Plan, schemas *terraform.Schemas, view *View) {
 				rSchema,
 				view.colorize,
 				format.DiffLanguageProposedChange,
+				format.DiffOpts{RawStringDiffs: view.rawStringDiffs},
 			))
 		}
 
@@ -429,6 +432,7 @@ func renderChangesDetectedByRefresh(plan *plans.Plan, schemas *terraform.Schemas
 			rSchema,
 			view.colorize,
 			format.DiffLanguageDetectedDrift,
+			format.DiffOpts{RawStringDiffs: view.rawStringDiffs},
 		))
 	}
 
@@ -0,0 +1,135 @@
+package views
+
+import (
+	"path"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/lang/marks"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/terraform"
+)
+
+// RedactionRules describes values that should be redacted in plan renderings
+// in addition to anything the provider schemas mark as sensitive, as
+// configured by the redact_providers and redact_attributes settings in the
+// CLI configuration.
+type RedactionRules struct {
+	// Providers is a set of glob patterns for provider source addresses.
+	// All values belonging to resources of a matching provider are redacted.
+	Providers []string
+
+	// Attributes is a set of dot-separated attribute path patterns, where
+	// the first label matches the resource type and the remaining labels
+	// match the attribute path within the resource. Each label is a glob
+	// pattern. Labels beyond the first can traverse into single-nested
+	// blocks; a label naming a collection-nested block redacts the whole
+	// block value.
+	Attributes []string
+}
+
+// Empty returns true if the rules would never redact anything.
+func (r RedactionRules) Empty() bool {
+	return len(r.Providers) == 0 && len(r.Attributes) == 0
+}
+
+// applyRedaction adds sensitivity marks to the resource changes in the given
+// plan for anything matched by the given rules, so that the subsequent
+// rendering (human-readable or JSON) will redact those values in the same way
+// as values the provider schema marks as sensitive.
+//
+// This modifies the plan in place, which is safe because additional
+// sensitivity marks only ever cause the renderings to disclose less.
+func applyRedaction(plan *plans.Plan, schemas *terraform.Schemas, rules RedactionRules) {
+	if rules.Empty() || plan == nil || schemas == nil {
+		return
+	}
+
+	for _, rcs := range plan.Changes.Resources {
+		providerSchema := schemas.ProviderSchema(rcs.ProviderAddr.Provider)
+		if providerSchema == nil {
+			continue
+		}
+		rSchema, _ := providerSchema.SchemaForResourceAddr(rcs.Addr.Resource.Resource)
+		if rSchema == nil {
+			continue
+		}
+
+		var paths []cty.Path
+		if rules.matchesProvider(rcs.ProviderAddr.Provider.String(), rcs.ProviderAddr.Provider.ForDisplay()) {
+			// A nil path marks the entire object, redacting every attribute
+			// of the resource.
+			paths = append(paths, nil)
+		} else {
+			resourceType := rcs.Addr.Resource.Resource.Type
+			for _, pattern := range rules.Attributes {
+				labels := strings.Split(pattern, ".")
+				if len(labels) < 2 {
+					continue // must name at least a type and an attribute
+				}
+				if matched, _ := path.Match(labels[0], resourceType); !matched {
+					continue
+				}
+				paths = append(paths, redactionPaths(rSchema, labels[1:], nil)...)
+			}
+		}
+
+		for _, p := range paths {
+			pvm := cty.PathValueMarks{
+				Path:  p,
+				Marks: cty.NewValueMarks(marks.Sensitive),
+			}
+			rcs.BeforeValMarks = append(rcs.BeforeValMarks, pvm)
+			rcs.AfterValMarks = append(rcs.AfterValMarks, pvm)
+		}
+	}
+}
+
+func (r RedactionRules) matchesProvider(fullAddr, displayAddr string) bool {
+	for _, pattern := range r.Providers {
+		if matched, _ := path.Match(pattern, fullAddr); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, displayAddr); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// redactionPaths resolves the given attribute path pattern labels against a
+// block schema, returning the paths of all of the matching values. Patterns
+// can traverse through single-nested blocks; a label that matches a
+// collection-nested block selects the whole block value, since cty paths
+// cannot describe "every element" of a collection.
+func redactionPaths(schema *configschema.Block, labels []string, prefix cty.Path) []cty.Path {
+	var ret []cty.Path
+
+	for name := range schema.Attributes {
+		if matched, _ := path.Match(labels[0], name); matched && len(labels) == 1 {
+			ret = append(ret, append(prefix.Copy(), cty.GetAttrStep{Name: name}))
+		}
+	}
+
+	for name, blockType := range schema.BlockTypes {
+		matched, _ := path.Match(labels[0], name)
+		if !matched {
+			continue
+		}
+		blockPath := append(prefix.Copy(), cty.GetAttrStep{Name: name})
+		switch {
+		case len(labels) == 1:
+			ret = append(ret, blockPath)
+		case blockType.Nesting == configschema.NestingSingle || blockType.Nesting == configschema.NestingGroup:
+			ret = append(ret, redactionPaths(&blockType.Block, labels[1:], blockPath)...)
+		default:
+			// We can't address individual values inside collection-nested
+			// blocks, so we conservatively redact the whole block value.
+			ret = append(ret, blockPath)
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,83 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/terminal"
+)
+
+func TestApplyRedaction(t *testing.T) {
+	tests := map[string]struct {
+		rules     RedactionRules
+		wantPaths []cty.Path
+	}{
+		"no rules": {
+			RedactionRules{},
+			nil,
+		},
+		"provider match": {
+			RedactionRules{Providers: []string{"registry.terraform.io/hashicorp/test"}},
+			[]cty.Path{nil},
+		},
+		"provider match on display address": {
+			RedactionRules{Providers: []string{"hashicorp/*"}},
+			[]cty.Path{nil},
+		},
+		"provider no match": {
+			RedactionRules{Providers: []string{"example.com/foo/*"}},
+			nil,
+		},
+		"attribute match": {
+			RedactionRules{Attributes: []string{"test_resource.foo"}},
+			[]cty.Path{cty.GetAttrPath("foo")},
+		},
+		"attribute glob match": {
+			RedactionRules{Attributes: []string{"test_*.f*"}},
+			[]cty.Path{cty.GetAttrPath("foo")},
+		},
+		"attribute no match": {
+			RedactionRules{Attributes: []string{"other_resource.foo"}},
+			nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			plan := testPlan(t)
+			applyRedaction(plan, testSchemas(), tc.rules)
+
+			rcs := plan.Changes.Resources[0]
+			if got, want := len(rcs.AfterValMarks), len(tc.wantPaths); got != want {
+				t.Fatalf("wrong number of marks %d; want %d", got, want)
+			}
+			for i, want := range tc.wantPaths {
+				if got := rcs.AfterValMarks[i].Path; !got.Equals(want) {
+					t.Errorf("wrong path for mark %d: %#v; want %#v", i, got, want)
+				}
+			}
+			if len(rcs.BeforeValMarks) != len(rcs.AfterValMarks) {
+				t.Errorf("before and after marks differ in length")
+			}
+		})
+	}
+}
+
+func TestRenderPlan_redaction(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams).SetRedactionRules(RedactionRules{
+		Attributes: []string{"test_resource.foo"},
+	})
+
+	renderPlan(testPlan(t), testSchemas(), view)
+
+	got := done(t).Stdout()
+	if !strings.Contains(got, `foo = (sensitive)`) {
+		t.Errorf("expected redacted foo attribute in output:\n%s", got)
+	}
+	if strings.Contains(got, `"bar"`) {
+		t.Errorf("expected value %q to be hidden in output:\n%s", "bar", got)
+	}
+}
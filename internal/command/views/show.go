@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/internal/command/arguments"
 	"github.com/hashicorp/terraform/internal/command/format"
+	"github.com/hashicorp/terraform/internal/command/htmlplan"
 	"github.com/hashicorp/terraform/internal/command/jsonplan"
 	"github.com/hashicorp/terraform/internal/command/jsonstate"
+	"github.com/hashicorp/terraform/internal/command/sarif"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/plans"
 	"github.com/hashicorp/terraform/internal/states/statefile"
@@ -27,6 +29,10 @@ func NewShow(vt arguments.ViewType, view *View) Show {
 		return &ShowJSON{view: view}
 	case arguments.ViewHuman:
 		return &ShowHuman{view: view}
+	case arguments.ViewSARIF:
+		return &ShowSARIF{view: view}
+	case arguments.ViewHTML:
+		return &ShowHTML{view: view}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -68,6 +74,7 @@ var _ Show = (*ShowJSON)(nil)
 
 func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, stateFile *statefile.File, schemas *terraform.Schemas) int {
 	if plan != nil {
+		applyRedaction(plan, schemas, v.view.redactionRules)
 		jsonPlan, err := jsonplan.Marshal(config, plan, stateFile, schemas)
 
 		if err != nil {
@@ -94,3 +101,69 @@ func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, stateFile *
 func (v *ShowJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
+
+type ShowSARIF struct {
+	view *View
+}
+
+var _ Show = (*ShowSARIF)(nil)
+
+func (v *ShowSARIF) Display(config *configs.Config, plan *plans.Plan, stateFile *statefile.File, schemas *terraform.Schemas) int {
+	if plan == nil {
+		v.view.streams.Eprintln("The -sarif option is only supported when showing a saved plan file, because SARIF results describe planned resource changes.")
+		return 1
+	}
+
+	applyRedaction(plan, schemas, v.view.redactionRules)
+	jsonPlan, err := jsonplan.Marshal(config, plan, stateFile, schemas)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+		return 1
+	}
+	sarifLog, err := sarif.FromPlanJSON(jsonPlan)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to build SARIF document: %s", err)
+		return 1
+	}
+	v.view.streams.Println(string(sarifLog))
+	return 0
+}
+
+// Diagnostics for the SARIF view are rendered as human-readable output,
+// since SARIF has no standard representation for them.
+func (v *ShowSARIF) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+type ShowHTML struct {
+	view *View
+}
+
+var _ Show = (*ShowHTML)(nil)
+
+func (v *ShowHTML) Display(config *configs.Config, plan *plans.Plan, stateFile *statefile.File, schemas *terraform.Schemas) int {
+	if plan == nil {
+		v.view.streams.Eprintln("The -html option is only supported when showing a saved plan file, because the report describes planned resource changes.")
+		return 1
+	}
+
+	applyRedaction(plan, schemas, v.view.redactionRules)
+	jsonPlan, err := jsonplan.Marshal(config, plan, stateFile, schemas)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+		return 1
+	}
+	report, err := htmlplan.FromPlanJSON(jsonPlan)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to build HTML report: %s", err)
+		return 1
+	}
+	v.view.streams.Println(string(report))
+	return 0
+}
+
+// Diagnostics for the HTML view are rendered as human-readable output,
+// because they belong on the terminal rather than inside the report.
+func (v *ShowHTML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
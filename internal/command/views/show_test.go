@@ -159,6 +159,125 @@ func TestShowJSON(t *testing.T) {
 	}
 }
 
+func TestShowSARIF(t *testing.T) {
+	config, _, configCleanup := initwd.MustLoadConfigForTests(t, "./testdata/show")
+	defer configCleanup()
+
+	schemas := &terraform.Schemas{
+		Providers: map[addrs.Provider]*terraform.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]*configschema.Block{
+					"test_resource": {
+						Attributes: map[string]*configschema.Attribute{
+							"id":  {Type: cty.String, Optional: true, Computed: true},
+							"foo": {Type: cty.String, Optional: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("plan file", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: true})
+		v := NewShow(arguments.ViewSARIF, view)
+
+		code := v.Display(config, testPlan(t), nil, schemas)
+		if code != 0 {
+			t.Errorf("expected 0 return code, got %d", code)
+		}
+
+		var result map[string]interface{}
+		got := done(t).All()
+		t.Logf("output: %s", got)
+		if err := json.Unmarshal([]byte(got), &result); err != nil {
+			t.Fatal(err)
+		}
+		if version, ok := result["version"].(string); !ok || version != "2.1.0" {
+			t.Errorf("wrong SARIF version; got %#v, want %q", result["version"], "2.1.0")
+		}
+		if !strings.Contains(got, `"terraform.plan.create"`) {
+			t.Errorf("missing create rule result from output, got:\n%s", got)
+		}
+		if !strings.Contains(got, `"fullyQualifiedName":"test_resource.foo"`) {
+			t.Errorf("missing resource logical location from output, got:\n%s", got)
+		}
+	})
+
+	t.Run("no plan", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: true})
+		v := NewShow(arguments.ViewSARIF, view)
+
+		code := v.Display(config, nil, nil, schemas)
+		if code != 1 {
+			t.Errorf("expected 1 return code, got %d", code)
+		}
+		if got, want := done(t).Stderr(), "only supported when showing a saved plan file"; !strings.Contains(got, want) {
+			t.Errorf("unexpected error output\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+}
+
+func TestShowHTML(t *testing.T) {
+	config, _, configCleanup := initwd.MustLoadConfigForTests(t, "./testdata/show")
+	defer configCleanup()
+
+	schemas := &terraform.Schemas{
+		Providers: map[addrs.Provider]*terraform.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]*configschema.Block{
+					"test_resource": {
+						Attributes: map[string]*configschema.Attribute{
+							"id":  {Type: cty.String, Optional: true, Computed: true},
+							"foo": {Type: cty.String, Optional: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("plan file", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: true})
+		v := NewShow(arguments.ViewHTML, view)
+
+		code := v.Display(config, testPlan(t), nil, schemas)
+		if code != 0 {
+			t.Errorf("expected 0 return code, got %d", code)
+		}
+
+		got := done(t).All()
+		t.Logf("output: %s", got)
+		if !strings.Contains(got, "<!DOCTYPE html>") {
+			t.Errorf("missing HTML doctype from output, got:\n%s", got)
+		}
+		if !strings.Contains(got, "test_resource.foo") {
+			t.Errorf("missing resource change section from output, got:\n%s", got)
+		}
+	})
+
+	t.Run("no plan", func(t *testing.T) {
+		streams, done := terminal.StreamsForTesting(t)
+		view := NewView(streams)
+		view.Configure(&arguments.View{NoColor: true})
+		v := NewShow(arguments.ViewHTML, view)
+
+		code := v.Display(config, nil, nil, schemas)
+		if code != 1 {
+			t.Errorf("expected 1 return code, got %d", code)
+		}
+		if got, want := done(t).Stderr(), "only supported when showing a saved plan file"; !strings.Contains(got, want) {
+			t.Errorf("unexpected error output\ngot: %s\nwant substring: %s", got, want)
+		}
+	})
+}
+
 // testState returns a test State structure.
 func testState() *states.State {
 	return states.BuildState(func(s *states.SyncState) {
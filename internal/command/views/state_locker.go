@@ -1,9 +1,12 @@
 package views
 
 import (
+	encJson "encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/command/arguments"
+	"github.com/hashicorp/terraform/internal/states/statemgr"
 )
 
 // The StateLocker view is used to display locking/unlocking status messages
@@ -11,6 +14,13 @@ import (
 type StateLocker interface {
 	Locking()
 	Unlocking()
+
+	// Waiting reports that we are still waiting for the state lock after
+	// the given elapsed time. If the current holder of the lock is known
+	// then its lock info is given so we can say who we are waiting on;
+	// held may be nil for locking mechanisms that cannot report their
+	// current holder.
+	Waiting(held *statemgr.LockInfo, elapsed time.Duration)
 }
 
 // NewStateLocker returns an initialized StateLocker implementation for the given ViewType.
@@ -18,6 +28,8 @@ func NewStateLocker(vt arguments.ViewType, view *View) StateLocker {
 	switch vt {
 	case arguments.ViewHuman:
 		return &StateLockerHuman{view: view}
+	case arguments.ViewJSON:
+		return &StateLockerJSON{view: view}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -38,3 +50,71 @@ func (v *StateLockerHuman) Locking() {
 func (v *StateLockerHuman) Unlocking() {
 	v.view.streams.Println("Releasing state lock. This may take a few moments...")
 }
+
+func (v *StateLockerHuman) Waiting(held *statemgr.LockInfo, elapsed time.Duration) {
+	elapsed = elapsed.Round(time.Second)
+	if held != nil {
+		v.view.streams.Printf(
+			"Still waiting for the state lock; currently held by %s (operation %q, created %s). %s elapsed...\n",
+			held.Who, held.Operation, held.Created.Format(time.RFC3339), elapsed,
+		)
+	} else {
+		v.view.streams.Printf("Still waiting for the state lock. %s elapsed...\n", elapsed)
+	}
+}
+
+// StateLockerJSON is an implementation of StateLocker which prints the state
+// lock status to a terminal in machine-readable JSON form.
+type StateLockerJSON struct {
+	view *View
+}
+
+var _ StateLocker = (*StateLockerJSON)(nil)
+
+func (v *StateLockerJSON) Locking() {
+	v.logJSON("Acquiring state lock. This may take a few moments...",
+		"state_lock_acquire", nil)
+}
+
+func (v *StateLockerJSON) Unlocking() {
+	v.logJSON("Releasing state lock. This may take a few moments...",
+		"state_lock_release", nil)
+}
+
+func (v *StateLockerJSON) Waiting(held *statemgr.LockInfo, elapsed time.Duration) {
+	elapsed = elapsed.Round(time.Second)
+	extra := map[string]interface{}{
+		"elapsed_seconds": int(elapsed.Seconds()),
+	}
+	if held != nil {
+		extra["holder"] = held.Who
+		extra["holder_operation"] = held.Operation
+		extra["lock_id"] = held.ID
+		extra["lock_created"] = held.Created.Format(time.RFC3339)
+	}
+	v.logJSON(fmt.Sprintf("Still waiting for the state lock. %s elapsed...", elapsed),
+		"state_lock_waiting", extra)
+}
+
+// logJSON prints a single JSON log line in the same shape as the messages
+// produced by JSONView, so that state lock messages can interleave with the
+// rest of the machine-readable UI output.
+func (v *StateLockerJSON) logJSON(message string, messageType string, extra map[string]interface{}) {
+	fields := map[string]interface{}{
+		"@level":     "info",
+		"@message":   message,
+		"@module":    "terraform.ui",
+		"@timestamp": time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		"type":       messageType,
+	}
+	for k, val := range extra {
+		fields[k] = val
+	}
+
+	jsonMessage, err := encJson.Marshal(fields)
+	if err != nil {
+		// Should never happen, since we fully control the fields above.
+		panic(err)
+	}
+	v.view.streams.Println(string(jsonMessage))
+}
@@ -1,6 +1,7 @@
 package views
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -25,6 +26,13 @@ type Test interface {
 	// errors are considered to be test failures rather than mechanism failures,
 	// and so those will be reported via Results rather than via Diagnostics.
 	Diagnostics(tfdiags.Diagnostics)
+
+	// SuiteStarting and SuiteComplete report progress as individual test
+	// suites start and finish running. The command calls these only when
+	// running suites concurrently, where labeled progress lines are the
+	// only way to follow the otherwise-interleaved work.
+	SuiteStarting(suiteName string)
+	SuiteComplete(suiteName string)
 }
 
 // NewTest returns an implementation of Test configured to respect the
@@ -35,6 +43,7 @@ func NewTest(base *View, args arguments.TestOutput) Test {
 		showDiagnostics: base.Diagnostics,
 		colorize:        base.colorize,
 		junitXMLFile:    args.JUnitXMLFile,
+		jsonReportFile:  args.JSONReportFile,
 	}
 }
 
@@ -47,6 +56,11 @@ type testHuman struct {
 	// If junitXMLFile is not empty then results will be written to
 	// the given file path in addition to the usual output.
 	junitXMLFile string
+
+	// If jsonReportFile is not empty then a JSON report of the results
+	// will be written to the given file path in addition to the usual
+	// output.
+	jsonReportFile string
 }
 
 func (v *testHuman) Results(results map[string]*moduletest.Suite) tfdiags.Diagnostics {
@@ -67,6 +81,11 @@ func (v *testHuman) Results(results map[string]*moduletest.Suite) tfdiags.Diagno
 		diags = diags.Append(moreDiags)
 	}
 
+	if v.jsonReportFile != "" {
+		moreDiags := v.jsonReportResults(results, v.jsonReportFile)
+		diags = diags.Append(moreDiags)
+	}
+
 	return diags
 }
 
@@ -77,6 +96,14 @@ func (v *testHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.showDiagnostics(diags)
 }
 
+func (v *testHuman) SuiteStarting(suiteName string) {
+	v.streams.Eprintf("%s: starting...\n", suiteName)
+}
+
+func (v *testHuman) SuiteComplete(suiteName string) {
+	v.streams.Eprintf("%s: complete\n", suiteName)
+}
+
 func (v *testHuman) humanResults(results map[string]*moduletest.Suite) {
 	failCount := 0
 	width := v.streams.Stderr.Columns()
@@ -224,6 +251,7 @@ func (v *testHuman) junitXMLResults(results map[string]*moduletest.Suite, filena
 	// in a JUnit XML document.
 	type TestSuite struct {
 		Name         string      `xml:"name"`
+		TimeSeconds  float64     `xml:"time,attr"`
 		TotalCount   int         `xml:"tests"`
 		SkippedCount int         `xml:"skipped"`
 		ErrorCount   int         `xml:"errors"`
@@ -250,7 +278,8 @@ func (v *testHuman) junitXMLResults(results map[string]*moduletest.Suite, filena
 		suite := results[suiteName]
 
 		xmlSuite := &TestSuite{
-			Name: suiteName,
+			Name:        suiteName,
+			TimeSeconds: suite.Duration.Seconds(),
 		}
 		xmlSuites.Suites = append(xmlSuites.Suites, xmlSuite)
 
@@ -294,6 +323,7 @@ func (v *testHuman) junitXMLResults(results map[string]*moduletest.Suite, filena
 					xmlCase.Failure = &Outcome{
 						Message: assertion.Message,
 					}
+					xmlCase.Stderr = renderDiagnosticsPlain(assertion.Diagnostics)
 				case moduletest.Error:
 					xmlSuites.ErrorCount++
 					xmlSuite.ErrorCount++
@@ -304,12 +334,7 @@ func (v *testHuman) junitXMLResults(results map[string]*moduletest.Suite, filena
 					// We'll also include the diagnostics in the "stderr"
 					// portion of the output, so they'll hopefully be visible
 					// in a test log viewer in JUnit-XML-Consuming CI systems.
-					var buf strings.Builder
-					for _, diag := range assertion.Diagnostics {
-						diagStr := format.DiagnosticPlain(diag, nil, 68)
-						buf.WriteString(diagStr)
-					}
-					xmlCase.Stderr = buf.String()
+					xmlCase.Stderr = renderDiagnosticsPlain(assertion.Diagnostics)
 				}
 
 			}
@@ -340,6 +365,184 @@ func (v *testHuman) junitXMLResults(results map[string]*moduletest.Suite, filena
 	return diags
 }
 
+func (v *testHuman) jsonReportResults(results map[string]*moduletest.Suite, filename string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// Unlike the JUnit XML output, which contorts our results to fit the
+	// Java-flavored expectations of existing consumers, the JSON report
+	// follows our own suite/component/assertion model directly so that
+	// purpose-built tooling can consume it without guesswork. The
+	// format_version property allows us to evolve this schema in future
+	// while allowing consumers to detect incompatible changes.
+
+	type Diagnostic struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail,omitempty"`
+	}
+
+	type Assertion struct {
+		Name        string       `json:"name"`
+		Outcome     string       `json:"outcome"`
+		Description string       `json:"description,omitempty"`
+		Message     string       `json:"message,omitempty"`
+		Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+	}
+
+	type Component struct {
+		Name       string       `json:"name"`
+		Assertions []*Assertion `json:"assertions"`
+	}
+
+	type Suite struct {
+		Name            string       `json:"name"`
+		DurationSeconds float64      `json:"duration_seconds"`
+		TotalCount      int          `json:"tests"`
+		PassedCount     int          `json:"passed"`
+		FailedCount     int          `json:"failed"`
+		ErrorCount      int          `json:"errored"`
+		PendingCount    int          `json:"pending"`
+		Components      []*Component `json:"components"`
+	}
+
+	type Report struct {
+		FormatVersion string   `json:"format_version"`
+		TotalCount    int      `json:"tests"`
+		PassedCount   int      `json:"passed"`
+		FailedCount   int      `json:"failed"`
+		ErrorCount    int      `json:"errored"`
+		PendingCount  int      `json:"pending"`
+		Suites        []*Suite `json:"suites"`
+	}
+
+	report := Report{
+		FormatVersion: "1.0",
+		Suites:        make([]*Suite, 0, len(results)),
+	}
+
+	suiteNames := make([]string, 0, len(results))
+	for suiteName := range results {
+		suiteNames = append(suiteNames, suiteName)
+	}
+	sort.Strings(suiteNames)
+	for _, suiteName := range suiteNames {
+		suite := results[suiteName]
+
+		jsonSuite := &Suite{
+			Name:            suiteName,
+			DurationSeconds: suite.Duration.Seconds(),
+			Components:      make([]*Component, 0, len(suite.Components)),
+		}
+		report.Suites = append(report.Suites, jsonSuite)
+
+		componentNames := make([]string, 0, len(suite.Components))
+		for componentName := range suite.Components {
+			componentNames = append(componentNames, componentName)
+		}
+		sort.Strings(componentNames)
+		for _, componentName := range componentNames {
+			component := suite.Components[componentName]
+
+			jsonComponent := &Component{
+				Name:       componentName,
+				Assertions: make([]*Assertion, 0, len(component.Assertions)),
+			}
+			jsonSuite.Components = append(jsonSuite.Components, jsonComponent)
+
+			assertionNames := make([]string, 0, len(component.Assertions))
+			for assertionName := range component.Assertions {
+				assertionNames = append(assertionNames, assertionName)
+			}
+			sort.Strings(assertionNames)
+
+			for _, assertionName := range assertionNames {
+				assertion := component.Assertions[assertionName]
+				report.TotalCount++
+				jsonSuite.TotalCount++
+
+				jsonAssertion := &Assertion{
+					Name:        assertionName,
+					Description: assertion.Description,
+					Message:     assertion.Message,
+				}
+				jsonComponent.Assertions = append(jsonComponent.Assertions, jsonAssertion)
+
+				switch assertion.Outcome {
+				case moduletest.Passed:
+					jsonAssertion.Outcome = "passed"
+					report.PassedCount++
+					jsonSuite.PassedCount++
+				case moduletest.Failed:
+					jsonAssertion.Outcome = "failed"
+					report.FailedCount++
+					jsonSuite.FailedCount++
+				case moduletest.Error:
+					jsonAssertion.Outcome = "error"
+					report.ErrorCount++
+					jsonSuite.ErrorCount++
+				default:
+					// "Pending" outcomes are assertions that we never got to
+					// check at all, due to an upstream error.
+					jsonAssertion.Outcome = "pending"
+					report.PendingCount++
+					jsonSuite.PendingCount++
+				}
+
+				for _, diag := range assertion.Diagnostics {
+					var severity string
+					switch diag.Severity() {
+					case tfdiags.Warning:
+						severity = "warning"
+					default:
+						severity = "error"
+					}
+					desc := diag.Description()
+					jsonAssertion.Diagnostics = append(jsonAssertion.Diagnostics, Diagnostic{
+						Severity: severity,
+						Summary:  desc.Summary,
+						Detail:   desc.Detail,
+					})
+				}
+			}
+		}
+	}
+
+	jsonOut, err := json.MarshalIndent(&report, "", "  ")
+	if err != nil {
+		// If marshalling fails then that's a bug in the code above,
+		// because we should always be producing a value that is
+		// accepted by encoding/json.
+		panic(fmt.Sprintf("invalid values to marshal as JSON report: %s", err))
+	}
+	jsonOut = append(jsonOut, '\n')
+
+	err = ioutil.WriteFile(filename, jsonOut, 0644)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write JSON report file",
+			fmt.Sprintf(
+				"Could not create %s to record the test results as JSON: %s.",
+				filename,
+				err,
+			),
+		))
+	}
+
+	return diags
+}
+
+// renderDiagnosticsPlain renders the given diagnostics as uncolored text
+// suitable for inclusion in a machine-readable report file.
+func renderDiagnosticsPlain(diags tfdiags.Diagnostics) string {
+	var buf strings.Builder
+	for _, diag := range diags {
+		diagStr := format.DiagnosticPlain(diag, nil, 68)
+		buf.WriteString(diagStr)
+	}
+	return buf.String()
+}
+
 func (v *testHuman) eprintRuleHeading(color, prefix, extra string) {
 	const lineCell string = "─"
 	textLen := len(prefix) + len(": ") + len(extra)
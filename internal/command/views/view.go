@@ -17,6 +17,15 @@ type View struct {
 
 	compactWarnings bool
 
+	// When this is true, plan renderings show string attribute values that
+	// parse as JSON or YAML documents as plain string diffs, instead of as
+	// structural diffs of the decoded values.
+	rawStringDiffs bool
+
+	// redactionRules describes additional values to redact in plan
+	// renderings, as configured in the CLI configuration.
+	redactionRules RedactionRules
+
 	// When this is true it's a hint that Terraform is being run indirectly
 	// via a wrapper script or other automation and so we may wish to replace
 	// direct examples of commands to run with more conceptual directions.
@@ -61,10 +70,21 @@ func (v *View) RunningInAutomation() bool {
 	return v.runningInAutomation
 }
 
+// SetRedactionRules configures additional values to redact in plan
+// renderings, beyond what the provider schemas mark as sensitive.
+//
+// For convenient use during initialization (in conjunction with NewView),
+// SetRedactionRules returns the reciever after modifying it.
+func (v *View) SetRedactionRules(rules RedactionRules) *View {
+	v.redactionRules = rules
+	return v
+}
+
 // Configure applies the global view configuration flags.
 func (v *View) Configure(view *arguments.View) {
 	v.colorize.Disable = view.NoColor
 	v.compactWarnings = view.CompactWarnings
+	v.rawStringDiffs = view.RawStringDiffs
 }
 
 // SetConfigSources overrides the default no-op callback with a new function
@@ -0,0 +1,96 @@
+package workdir
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EphemeralWorkspace records that a workspace was created as ephemeral, so
+// that "terraform workspace reap" can later destroy and delete it once it
+// outlives its welcome.
+type EphemeralWorkspace struct {
+	// Name is the workspace name.
+	Name string `json:"name"`
+
+	// Created is when the workspace was created.
+	Created time.Time `json:"created"`
+}
+
+func (d *Dir) ephemeralWorkspacesFile() string {
+	return filepath.Join(d.dataDir, "ephemeral-workspaces.json")
+}
+
+// ReadEphemeralWorkspaces returns the ephemeral workspaces recorded for this
+// working directory, in the order they were recorded. A working directory
+// with no recorded ephemeral workspaces yields an empty result with no error.
+func (d *Dir) ReadEphemeralWorkspaces() ([]*EphemeralWorkspace, error) {
+	src, err := os.ReadFile(d.ephemeralWorkspacesFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ephemeral workspace records: %w", err)
+	}
+	var ret []*EphemeralWorkspace
+	if err := json.Unmarshal(src, &ret); err != nil {
+		return nil, fmt.Errorf("failed to parse ephemeral workspace records: %w", err)
+	}
+	return ret, nil
+}
+
+// RecordEphemeralWorkspace records that the workspace of the given name is
+// ephemeral, with its creation time set to now. Recording a name that is
+// already recorded resets its creation time.
+func (d *Dir) RecordEphemeralWorkspace(name string) error {
+	entries, err := d.ReadEphemeralWorkspaces()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name == name {
+			entry.Created = time.Now().UTC()
+			return d.writeEphemeralWorkspaces(entries)
+		}
+	}
+	entries = append(entries, &EphemeralWorkspace{
+		Name:    name,
+		Created: time.Now().UTC(),
+	})
+	return d.writeEphemeralWorkspaces(entries)
+}
+
+// ForgetEphemeralWorkspace removes any record of the workspace of the given
+// name being ephemeral. Forgetting a name that isn't recorded is a no-op.
+func (d *Dir) ForgetEphemeralWorkspace(name string) error {
+	entries, err := d.ReadEphemeralWorkspaces()
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, entry := range entries {
+		if entry.Name != name {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	return d.writeEphemeralWorkspaces(kept)
+}
+
+func (d *Dir) writeEphemeralWorkspaces(entries []*EphemeralWorkspace) error {
+	if err := d.ensureDataDir(); err != nil {
+		return fmt.Errorf("failed to prepare data directory: %w", err)
+	}
+	buf, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize ephemeral workspace records: %w", err)
+	}
+	if err := os.WriteFile(d.ephemeralWorkspacesFile(), buf, 0644); err != nil {
+		return fmt.Errorf("failed to write ephemeral workspace records: %w", err)
+	}
+	return nil
+}
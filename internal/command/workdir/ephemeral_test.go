@@ -0,0 +1,66 @@
+package workdir
+
+import (
+	"testing"
+)
+
+func TestDirEphemeralWorkspaces(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	dir := NewDir(tmpDir)
+
+	got, err := dir.ReadEphemeralWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("wrong initial record count %d; want 0", len(got))
+	}
+
+	if err := dir.RecordEphemeralWorkspace("pr-123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dir.RecordEphemeralWorkspace("pr-456"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = dir.ReadEphemeralWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("wrong record count %d; want 2", len(got))
+	}
+	if got[0].Name != "pr-123" || got[1].Name != "pr-456" {
+		t.Errorf("wrong recorded names %q, %q", got[0].Name, got[1].Name)
+	}
+	if got[0].Created.IsZero() {
+		t.Errorf("creation time was not recorded")
+	}
+
+	// Recording an existing name again must not duplicate it.
+	if err := dir.RecordEphemeralWorkspace("pr-123"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = dir.ReadEphemeralWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("wrong record count after re-record %d; want 2", len(got))
+	}
+
+	if err := dir.ForgetEphemeralWorkspace("pr-123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dir.ForgetEphemeralWorkspace("never-recorded"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = dir.ReadEphemeralWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "pr-456" {
+		t.Fatalf("wrong records after forget: %#v", got)
+	}
+}
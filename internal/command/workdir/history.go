@@ -0,0 +1,133 @@
+package workdir
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// maxOperationHistoryEntries is the number of operation history entries we
+// retain in a working directory. Appending a new entry once the history is
+// full discards the oldest entry, along with its summary file if any.
+const maxOperationHistoryEntries = 10
+
+// OperationHistoryEntry describes one entry in a working directory's local
+// history of recent operations.
+//
+// Entries intentionally record only a broad summary of each operation:
+// they must never contain variable values, full state, or anything else
+// that might be sensitive.
+type OperationHistoryEntry struct {
+	// Command is the name of the CLI command that ran the operation, such
+	// as "plan" or "apply".
+	Command string `json:"command"`
+
+	// Timestamp is when the operation completed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Options is a short human-readable summary of the notable options the
+	// command ran with, such as "-destroy". It never includes variable
+	// values.
+	Options string `json:"options,omitempty"`
+
+	// Result describes how the operation finished, such as "success" or
+	// "failure".
+	Result string `json:"result"`
+
+	// Added, Changed and Removed count the resource instance changes the
+	// operation made, for operations that track this.
+	Added   int `json:"added,omitempty"`
+	Changed int `json:"changed,omitempty"`
+	Removed int `json:"removed,omitempty"`
+
+	// DiagnosticsDigest is a condensed description of any diagnostics the
+	// operation produced, such as the summary of the first error.
+	DiagnosticsDigest string `json:"diagnostics_digest,omitempty"`
+
+	// SummaryFile is the path, relative to the history directory, of a
+	// saved JSON summary of the operation, if one was recorded.
+	SummaryFile string `json:"summary_file,omitempty"`
+}
+
+// historyDir returns the directory where the receiver keeps its operation
+// history.
+func (d *Dir) historyDir() string {
+	return filepath.Join(d.dataDir, "history")
+}
+
+func (d *Dir) historyManifestFile() string {
+	return filepath.Join(d.historyDir(), "history.json")
+}
+
+// OperationHistorySummaryPath returns the absolute path of the summary file
+// recorded in the given entry, or an empty string if the entry has no
+// summary file.
+func (d *Dir) OperationHistorySummaryPath(entry *OperationHistoryEntry) string {
+	if entry.SummaryFile == "" {
+		return ""
+	}
+	return filepath.Join(d.historyDir(), entry.SummaryFile)
+}
+
+// ReadOperationHistory returns the recorded operation history for this
+// working directory, oldest entry first. A working directory with no
+// recorded history yields an empty result with no error.
+func (d *Dir) ReadOperationHistory() ([]*OperationHistoryEntry, error) {
+	src, err := os.ReadFile(d.historyManifestFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation history: %w", err)
+	}
+	var entries []*OperationHistoryEntry
+	if err := json.Unmarshal(src, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse operation history: %w", err)
+	}
+	return entries, nil
+}
+
+// AppendOperationHistory adds a new entry to the working directory's
+// operation history, discarding the oldest entries if the history exceeds
+// its bound.
+//
+// If summaryJSON is non-nil then it is saved as the entry's summary file
+// and the entry's SummaryFile field is populated to refer to it.
+func (d *Dir) AppendOperationHistory(entry *OperationHistoryEntry, summaryJSON []byte) error {
+	entries, err := d.ReadOperationHistory()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(d.historyDir(), 0755); err != nil {
+		return fmt.Errorf("failed to prepare operation history directory: %w", err)
+	}
+
+	if summaryJSON != nil {
+		entry.SummaryFile = fmt.Sprintf("%s-%s.json", entry.Timestamp.UTC().Format("20060102-150405.000000000"), entry.Command)
+		if err := os.WriteFile(d.OperationHistorySummaryPath(entry), summaryJSON, 0644); err != nil {
+			return fmt.Errorf("failed to write operation summary: %w", err)
+		}
+	}
+
+	entries = append(entries, entry)
+	for len(entries) > maxOperationHistoryEntries {
+		oldest := entries[0]
+		entries = entries[1:]
+		if path := d.OperationHistorySummaryPath(oldest); path != "" {
+			// Best effort only: a leftover summary file is harmless.
+			os.Remove(path)
+		}
+	}
+
+	buf, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize operation history: %w", err)
+	}
+	if err := os.WriteFile(d.historyManifestFile(), buf, 0644); err != nil {
+		return fmt.Errorf("failed to write operation history: %w", err)
+	}
+	return nil
+}
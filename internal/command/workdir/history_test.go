@@ -0,0 +1,68 @@
+package workdir
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDirOperationHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	dir := NewDir(tmpDir)
+	// We'll use the default convention of a data dir nested inside the
+	// working directory, so we don't need to override anything on "dir".
+
+	got, err := dir.ReadOperationHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("wrong initial history length %d; want 0", len(got))
+	}
+
+	// We'll append a few more entries than the history bound allows, so
+	// that we can verify the rotation behavior below.
+	baseTime := time.Date(2022, 8, 29, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < maxOperationHistoryEntries+2; i++ {
+		entry := &OperationHistoryEntry{
+			Command:   "plan",
+			Timestamp: baseTime.Add(time.Duration(i) * time.Minute),
+			Result:    "success",
+		}
+		summary := []byte(fmt.Sprintf(`{"n":%d}`, i))
+		if err := dir.AppendOperationHistory(entry, summary); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err = dir.ReadOperationHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != maxOperationHistoryEntries {
+		t.Fatalf("wrong history length %d; want %d", len(got), maxOperationHistoryEntries)
+	}
+
+	// The oldest two entries must have been discarded, so the first
+	// remaining entry is the third one we appended.
+	if want := baseTime.Add(2 * time.Minute); !got[0].Timestamp.Equal(want) {
+		t.Errorf("wrong oldest timestamp %s; want %s", got[0].Timestamp, want)
+	}
+
+	// Every remaining entry's summary file must still exist, and the
+	// discarded entries' summary files must be gone.
+	for _, entry := range got {
+		if _, err := os.Stat(dir.OperationHistorySummaryPath(entry)); err != nil {
+			t.Errorf("summary file for entry at %s is not readable: %s", entry.Timestamp, err)
+		}
+	}
+	historyFiles, err := os.ReadDir(dir.historyDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := maxOperationHistoryEntries + 1; len(historyFiles) != want { // summaries plus the manifest
+		t.Errorf("wrong number of files in history dir %d; want %d", len(historyFiles), want)
+	}
+}
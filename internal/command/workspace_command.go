@@ -29,7 +29,8 @@ func (c *WorkspaceCommand) Help() string {
 	helpText := `
 Usage: terraform [global options] workspace
 
-  new, list, show, select and delete Terraform workspaces.
+  new, list, show, select, delete, reap, tag and describe Terraform
+  workspaces.
 
 `
 	return strings.TrimSpace(helpText)
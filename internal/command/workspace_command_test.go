@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -435,3 +436,201 @@ func TestWorkspace_deleteWithState(t *testing.T) {
 		t.Fatal("env 'test' still exists!")
 	}
 }
+
+func TestWorkspace_createEphemeralAndReap(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	// create an ephemeral workspace and a regular one
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	newCmd := &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"-ephemeral", "pr-1"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	ui = new(cli.MockUi)
+	newCmd = &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"keep"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	// a dry run must list the candidate without deleting anything
+	ui = new(cli.MockUi)
+	reapCmd := &WorkspaceReapCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := reapCmd.Run([]string{"-dry-run"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "pr-1") {
+		t.Fatalf("dry run output should mention pr-1:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "pr-1")); err != nil {
+		t.Fatalf("workspace pr-1 should still exist after dry run: %s", err)
+	}
+
+	// a TTL longer than the workspace's age must leave it alone
+	ui = new(cli.MockUi)
+	reapCmd = &WorkspaceReapCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := reapCmd.Run([]string{"-ttl=1h", "-auto-approve"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "No ephemeral workspaces are old enough") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+
+	// reaping for real must destroy and delete only the ephemeral workspace
+	ui = new(cli.MockUi)
+	reapCmd = &WorkspaceReapCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := reapCmd.Run([]string{"-auto-approve"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "pr-1")); !os.IsNotExist(err) {
+		t.Fatalf("workspace pr-1 should have been deleted")
+	}
+	if _, err := os.Stat(filepath.Join(local.DefaultWorkspaceDir, "keep")); err != nil {
+		t.Fatalf("workspace keep should still exist: %s", err)
+	}
+
+	// the ephemeral record must be gone, so another reap has nothing to do
+	ui = new(cli.MockUi)
+	reapCmd = &WorkspaceReapCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := reapCmd.Run([]string{"-auto-approve"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "No ephemeral workspaces are recorded") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+}
+
+func TestWorkspace_tagAndDescribe(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	view, _ := testView(t)
+
+	// create a workspace to attach metadata to
+	ui := new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := newCmd.Run([]string{"staging"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	// a workspace starts with no tags
+	ui = new(cli.MockUi)
+	tagCmd := &WorkspaceTagCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := tagCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "has no tags") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+
+	// set some tags and read them back
+	ui = new(cli.MockUi)
+	tagCmd = &WorkspaceTagCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := tagCmd.Run([]string{"team=platform", "env=staging"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	tagCmd = &WorkspaceTagCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := tagCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	got := ui.OutputWriter.String()
+	if !strings.Contains(got, "env = staging") || !strings.Contains(got, "team = platform") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+
+	// a malformed tag argument must fail before writing anything
+	ui = new(cli.MockUi)
+	tagCmd = &WorkspaceTagCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := tagCmd.Run([]string{"noequals"}); code == 0 {
+		t.Fatalf("expected failure for malformed tag\n\n%s", ui.OutputWriter)
+	}
+
+	// unset one tag
+	ui = new(cli.MockUi)
+	tagCmd = &WorkspaceTagCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := tagCmd.Run([]string{"-unset", "env"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	// set and show a description
+	ui = new(cli.MockUi)
+	descCmd := &WorkspaceDescribeCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := descCmd.Run([]string{"Staging environment for the platform team"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	descCmd = &WorkspaceDescribeCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := descCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "Staging environment for the platform team") {
+		t.Fatalf("unexpected output:\n%s", got)
+	}
+
+	// the metadata must appear in the JSON listing
+	ui = new(cli.MockUi)
+	listCmd := &WorkspaceListCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := listCmd.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	var list []struct {
+		Name        string            `json:"name"`
+		Current     bool              `json:"current"`
+		Description string            `json:"description"`
+		Tags        map[string]string `json:"tags"`
+	}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &list); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 workspaces, got %d:\n%s", len(list), ui.OutputWriter)
+	}
+	if list[0].Name != "default" || list[0].Current || list[0].Description != "" || len(list[0].Tags) != 0 {
+		t.Fatalf("unexpected default workspace entry: %#v", list[0])
+	}
+	if list[1].Name != "staging" || !list[1].Current {
+		t.Fatalf("unexpected staging workspace entry: %#v", list[1])
+	}
+	if list[1].Description != "Staging environment for the platform team" {
+		t.Fatalf("wrong description: %q", list[1].Description)
+	}
+	if len(list[1].Tags) != 1 || list[1].Tags["team"] != "platform" {
+		t.Fatalf("wrong tags: %#v", list[1].Tags)
+	}
+
+	// clearing the description and remaining tag removes the metadata file
+	ui = new(cli.MockUi)
+	descCmd = &WorkspaceDescribeCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := descCmd.Run([]string{"-unset"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	ui = new(cli.MockUi)
+	tagCmd = &WorkspaceTagCommand{Meta: Meta{Ui: ui, View: view}}
+	if code := tagCmd.Run([]string{"-unset", "team"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	metaPath := filepath.Join(local.DefaultWorkspaceDir, "staging", "workspace-meta.json")
+	if _, err := os.Stat(metaPath); !os.IsNotExist(err) {
+		t.Fatalf("metadata file still exists at %s", metaPath)
+	}
+}
@@ -50,6 +50,14 @@ func (c *WorkspaceDeleteCommand) Run(args []string) int {
 
 	var diags tfdiags.Diagnostics
 
+	// Deleting a workspace discards its state, so refuse if the target
+	// workspace is configured as read-only.
+	diags = diags.Append(c.requireWritableWorkspaceName(args[0], "delete the workspace"))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
 	backendConfig, backendDiags := c.loadBackendConfig(configPath)
 	diags = diags.Append(backendDiags)
 	if diags.HasErrors() {
@@ -171,6 +179,12 @@ func (c *WorkspaceDeleteCommand) Run(args []string) int {
 		return 1
 	}
 
+	// If the workspace was recorded as ephemeral then the record is now
+	// moot. A failure to remove it is harmless, since reaping tolerates
+	// records for workspaces that no longer exist.
+	c.fixupMissingWorkingDir()
+	c.WorkingDir.ForgetEphemeralWorkspace(workspace)
+
 	c.Ui.Output(
 		c.Colorize().Color(
 			fmt.Sprintf(envDeleted, workspace),
@@ -0,0 +1,141 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type WorkspaceDescribeCommand struct {
+	Meta
+	LegacyName bool
+}
+
+func (c *WorkspaceDescribeCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	envCommandShowWarning(c.Ui, c.LegacyName)
+
+	var unset bool
+	cmdFlags := c.Meta.defaultFlagSet("workspace describe")
+	cmdFlags.BoolVar(&unset, "unset", false, "unset")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("Expected at most one argument: the workspace description")
+		return cli.RunResultHelp
+	}
+	if unset && len(args) != 0 {
+		c.Ui.Error("The -unset option does not take a description argument")
+		return cli.RunResultHelp
+	}
+
+	configPath, err := ModulePath(nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	})
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// This command will not write state
+	c.ignoreRemoteVersionConflict(b)
+
+	store, ok := b.(backend.WorkspaceMetadataStore)
+	if !ok {
+		c.Ui.Error("The currently selected backend does not support workspace metadata")
+		return 1
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+
+	meta, err := store.WorkspaceMetadata(workspace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading workspace metadata: %s", err))
+		return 1
+	}
+
+	// With no arguments we just show the current description.
+	if !unset && len(args) == 0 {
+		if meta.Description == "" {
+			c.Ui.Output(fmt.Sprintf("Workspace %q has no description.", workspace))
+			return 0
+		}
+		c.Ui.Output(meta.Description)
+		return 0
+	}
+
+	if unset {
+		meta.Description = ""
+	} else {
+		meta.Description = args[0]
+	}
+
+	if err := store.SetWorkspaceMetadata(workspace, meta); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing workspace metadata: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+func (c *WorkspaceDescribeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WorkspaceDescribeCommand) AutocompleteFlags() complete.Flags {
+	return nil
+}
+
+func (c *WorkspaceDescribeCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] workspace describe [-unset] [DESCRIPTION]
+
+  Manage the description of the current workspace.
+
+  The description is free text stored alongside the workspace's state, for
+  explaining the workspace's purpose. It has no effect on Terraform's
+  behavior.
+
+  With no arguments, the current workspace's description is shown. With a
+  DESCRIPTION argument, the description is replaced.
+
+Options:
+
+    -unset              Remove the description instead of setting one.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceDescribeCommand) Synopsis() string {
+	return "Manage the description of the current workspace"
+}
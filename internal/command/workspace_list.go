@@ -2,9 +2,11 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform/internal/backend"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 	"github.com/posener/complete"
 )
@@ -18,7 +20,9 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	envCommandShowWarning(c.Ui, c.LegacyName)
 
+	var jsonOutput bool
 	cmdFlags := c.Meta.defaultFlagSet("workspace list")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -62,6 +66,45 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 
 	env, isOverridden := c.WorkspaceOverridden()
 
+	if jsonOutput {
+		type workspaceJSON struct {
+			Name        string            `json:"name"`
+			Current     bool              `json:"current"`
+			Description string            `json:"description,omitempty"`
+			Tags        map[string]string `json:"tags,omitempty"`
+		}
+
+		store, _ := b.(backend.WorkspaceMetadataStore)
+
+		list := make([]workspaceJSON, 0, len(states))
+		for _, s := range states {
+			entry := workspaceJSON{
+				Name:    s,
+				Current: s == env,
+			}
+			if store != nil {
+				meta, err := store.WorkspaceMetadata(s)
+				if err != nil {
+					c.Ui.Error(fmt.Sprintf("Error reading metadata for workspace %q: %s", s, err))
+					return 1
+				}
+				entry.Description = meta.Description
+				entry.Tags = meta.Tags
+			}
+			list = append(list, entry)
+		}
+
+		buf, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			// Should never happen, since the value is built above from
+			// marshalable types only.
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(string(buf))
+		return 0
+	}
+
 	var out bytes.Buffer
 	for _, s := range states {
 		if s == env {
@@ -95,6 +138,12 @@ Usage: terraform [global options] workspace list
 
   List Terraform workspaces.
 
+Options:
+
+    -json               List the workspaces in a machine-readable JSON
+                        format, including each workspace's description and
+                        tags when the backend stores workspace metadata.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -27,7 +27,9 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 	var stateLock bool
 	var stateLockTimeout time.Duration
 	var statePath string
+	var ephemeral bool
 	cmdFlags := c.Meta.defaultFlagSet("workspace new")
+	cmdFlags.BoolVar(&ephemeral, "ephemeral", false, "mark the workspace as ephemeral")
 	cmdFlags.BoolVar(&stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&stateLockTimeout, "lock-timeout", 0, "lock timeout")
 	cmdFlags.StringVar(&statePath, "state", "", "terraform state file")
@@ -109,9 +111,21 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 		return 1
 	}
 
+	if ephemeral {
+		c.fixupMissingWorkingDir()
+		if err := c.WorkingDir.RecordEphemeralWorkspace(workspace); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error recording workspace as ephemeral: %s", err))
+			return 1
+		}
+	}
+
 	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
 		strings.TrimSpace(envCreated), workspace)))
 
+	if ephemeral {
+		c.Ui.Output(`The workspace is marked as ephemeral, so a later "terraform workspace reap" run in this working directory can destroy and delete it.`)
+	}
+
 	if statePath == "" {
 		// if we're not loading a state, then we're done
 		return 0
@@ -186,6 +200,11 @@ Usage: terraform [global options] workspace new [OPTIONS] NAME
 
 Options:
 
+    -ephemeral          Mark the workspace as ephemeral, so that a later
+                        "terraform workspace reap" run in this working
+                        directory can destroy and delete it once it is older
+                        than the reap TTL. Useful for preview environments.
+
     -lock=false         Don't hold a state lock during the operation. This is
                         dangerous if others might concurrently run commands
                         against the same workspace.
@@ -0,0 +1,322 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/command/arguments"
+	"github.com/hashicorp/terraform/internal/command/views"
+	"github.com/hashicorp/terraform/internal/command/workdir"
+	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/terraform"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type WorkspaceReapCommand struct {
+	Meta
+	LegacyName bool
+}
+
+func (c *WorkspaceReapCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	envCommandShowWarning(c.Ui, c.LegacyName)
+
+	var ttl time.Duration
+	var dryRun bool
+	var autoApprove bool
+	cmdFlags := c.Meta.extendedFlagSet("workspace reap")
+	cmdFlags.DurationVar(&ttl, "ttl", 0, "minimum workspace age")
+	cmdFlags.BoolVar(&dryRun, "dry-run", false, "only show what would be reaped")
+	cmdFlags.BoolVar(&autoApprove, "auto-approve", false, "skip interactive approval before destroying")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 0 {
+		c.Ui.Error("Expected no positional arguments.\n")
+		return cli.RunResultHelp
+	}
+
+	c.fixupMissingWorkingDir()
+
+	records, err := c.WorkingDir.ReadEphemeralWorkspaces()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if len(records) == 0 {
+		c.Ui.Output("No ephemeral workspaces are recorded in this working directory.")
+		return 0
+	}
+
+	var diags tfdiags.Diagnostics
+
+	backendConfig, backendDiags := c.loadBackendConfig(".")
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	})
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to get configured named states: %s", err))
+		return 1
+	}
+	exists := make(map[string]bool, len(workspaces))
+	for _, ws := range workspaces {
+		exists[ws] = true
+	}
+
+	now := time.Now()
+	var candidates []*workdir.EphemeralWorkspace
+	for _, record := range records {
+		if record.Name == backend.DefaultStateName {
+			// The default workspace is never reaped, no matter what the
+			// records claim.
+			continue
+		}
+		if !exists[record.Name] {
+			// The workspace was deleted by some other means, so the record
+			// is moot and we can discard it.
+			c.WorkingDir.ForgetEphemeralWorkspace(record.Name)
+			continue
+		}
+		if now.Sub(record.Created) < ttl {
+			continue
+		}
+		candidates = append(candidates, record)
+	}
+
+	if len(candidates) == 0 {
+		c.Ui.Output("No ephemeral workspaces are old enough to reap.")
+		return 0
+	}
+
+	var buf strings.Builder
+	for _, record := range candidates {
+		age := now.Sub(record.Created).Round(time.Minute)
+		fmt.Fprintf(&buf, "\n  - %s (created %s ago)", record.Name, age)
+	}
+	c.Ui.Output(fmt.Sprintf("The following ephemeral workspaces will be destroyed and deleted:%s\n", buf.String()))
+
+	if dryRun {
+		c.Ui.Output("Dry run requested, so no workspaces were reaped.")
+		return 0
+	}
+
+	if !autoApprove {
+		v, err := c.confirm(&terraform.InputOpts{
+			Id:          "reap",
+			Query:       "Do you really want to reap these workspaces?",
+			Description: "Terraform will destroy all objects managed by each workspace listed above and then delete the workspace. There is no undo. Only 'yes' will be accepted to confirm.",
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error asking for approval: %s", err))
+			return 1
+		}
+		if !v {
+			c.Ui.Output("Reap cancelled.")
+			return 1
+		}
+	}
+
+	// We'll need somewhere safe to stand while deleting workspaces, since
+	// the current workspace cannot be deleted. If the currently-selected
+	// workspace is itself being reaped then we'll fall back on the default
+	// workspace.
+	home, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+	for _, record := range candidates {
+		if record.Name == home {
+			home = backend.DefaultStateName
+			break
+		}
+	}
+
+	for _, record := range candidates {
+		if reapDiags := c.reapWorkspace(b, record.Name, home); reapDiags.HasErrors() {
+			diags = diags.Append(reapDiags)
+			c.showDiagnostics(diags)
+			return 1
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][green]Reaped workspace %q.", record.Name)))
+	}
+
+	return 0
+}
+
+// reapWorkspace destroys all of the objects managed by the given workspace
+// and then deletes it, leaving the given home workspace selected.
+func (c *WorkspaceReapCommand) reapWorkspace(b backend.Enhanced, workspace, home string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// Reaping destroys the workspace's objects and discards its state, so
+	// refuse if the workspace is configured as read-only.
+	diags = diags.Append(c.requireWritableWorkspaceName(workspace, "reap the workspace"))
+	if diags.HasErrors() {
+		return diags
+	}
+
+	// The destroy operation runs against the currently-selected workspace,
+	// so we must switch to the victim first.
+	if err := c.SetWorkspace(workspace); err != nil {
+		diags = diags.Append(fmt.Errorf("error selecting workspace %q: %s", workspace, err))
+		return diags
+	}
+
+	c.Ui.Output(fmt.Sprintf("Destroying objects managed by workspace %q...", workspace))
+
+	view := views.NewApply(arguments.ViewHuman, true, c.View)
+
+	opReq := c.Operation(b, arguments.ViewHuman)
+	opReq.AutoApprove = true
+	opReq.ConfigDir = "."
+	opReq.PlanMode = plans.DestroyMode
+	opReq.PlanRefresh = true
+	opReq.Hooks = view.Hooks()
+	opReq.Type = backend.OperationTypeApply
+	opReq.View = view.Operation()
+
+	var err error
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to initialize config loader: %s", err))
+		return diags
+	}
+
+	var varDiags tfdiags.Diagnostics
+	opReq.Variables, varDiags = c.collectVariableValues()
+	diags = diags.Append(varDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	op, err := c.RunOperation(b, opReq)
+	if err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+	if op.Result != backend.OperationSuccess {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to destroy workspace",
+			fmt.Sprintf("The destroy operation for workspace %q did not succeed, so the workspace was not deleted.", workspace),
+		))
+		return diags
+	}
+
+	// Move back to safe ground before deleting the workspace, since the
+	// currently-selected workspace cannot be deleted.
+	if err := c.SetWorkspace(home); err != nil {
+		diags = diags.Append(fmt.Errorf("error selecting workspace %q: %s", home, err))
+		return diags
+	}
+
+	// The destroy operation should have left the state empty, but we'll
+	// check before deleting in case something slipped through, since
+	// deleting a non-empty workspace would lose track of remote objects.
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+	if stateMgr.State().HasManagedResourceInstanceObjects() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Workspace is not empty after destroy",
+			fmt.Sprintf("Workspace %q still tracks resource instances after the destroy operation, so the workspace was not deleted.", workspace),
+		))
+		return diags
+	}
+
+	if err := b.DeleteWorkspace(workspace); err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+	if err := c.WorkingDir.ForgetEphemeralWorkspace(workspace); err != nil {
+		diags = diags.Append(err)
+		return diags
+	}
+
+	return diags
+}
+
+func (c *WorkspaceReapCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WorkspaceReapCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-ttl":          complete.PredictAnything,
+		"-dry-run":      complete.PredictNothing,
+		"-auto-approve": complete.PredictNothing,
+	}
+}
+
+func (c *WorkspaceReapCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] workspace reap [OPTIONS]
+
+  Destroy and delete ephemeral workspaces.
+
+  Reap considers every workspace of the current configuration that was
+  created with "terraform workspace new -ephemeral" and is older than the
+  given TTL. For each one, Terraform destroys all of the objects the
+  workspace manages and then deletes the workspace, as if by running
+  "terraform destroy" followed by "terraform workspace delete".
+
+  Ephemeral workspace records are local to the working directory, so reap
+  must run in the same working directory (or from the same preserved
+  working directory contents) that created the workspaces.
+
+Options:
+
+  -ttl=duration       Only reap workspaces older than the given duration,
+                      such as "24h". The default is to reap all ephemeral
+                      workspaces regardless of age.
+
+  -dry-run            Show which workspaces would be reaped without
+                      destroying or deleting anything.
+
+  -auto-approve       Skip interactive approval before destroying. Intended
+                      for use in automation such as CI.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration, for the destroy runs. Use
+                      this option more than once to set more than one
+                      variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceReapCommand) Synopsis() string {
+	return "Destroy and delete ephemeral workspaces"
+}
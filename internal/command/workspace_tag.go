@@ -0,0 +1,169 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/backend"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type WorkspaceTagCommand struct {
+	Meta
+	LegacyName bool
+}
+
+func (c *WorkspaceTagCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	envCommandShowWarning(c.Ui, c.LegacyName)
+
+	var unset bool
+	cmdFlags := c.Meta.defaultFlagSet("workspace tag")
+	cmdFlags.BoolVar(&unset, "unset", false, "unset")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+
+	// Parse the arguments up front so that a malformed argument fails
+	// before we touch the backend.
+	set := make(map[string]string)
+	var remove []string
+	for _, arg := range args {
+		if unset {
+			if strings.Contains(arg, "=") {
+				c.Ui.Error(fmt.Sprintf("The -unset option expects tag names only, but %q includes a value", arg))
+				return cli.RunResultHelp
+			}
+			remove = append(remove, arg)
+			continue
+		}
+		eq := strings.Index(arg, "=")
+		if eq < 1 {
+			c.Ui.Error(fmt.Sprintf("Invalid tag %q: expected KEY=VALUE", arg))
+			return cli.RunResultHelp
+		}
+		set[arg[:eq]] = arg[eq+1:]
+	}
+
+	configPath, err := ModulePath(nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	})
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// This command will not write state
+	c.ignoreRemoteVersionConflict(b)
+
+	store, ok := b.(backend.WorkspaceMetadataStore)
+	if !ok {
+		c.Ui.Error("The currently selected backend does not support workspace metadata")
+		return 1
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error selecting workspace: %s", err))
+		return 1
+	}
+
+	meta, err := store.WorkspaceMetadata(workspace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading workspace metadata: %s", err))
+		return 1
+	}
+
+	// With no arguments we just list the current tags.
+	if len(set) == 0 && len(remove) == 0 {
+		if len(meta.Tags) == 0 {
+			c.Ui.Output(fmt.Sprintf("Workspace %q has no tags.", workspace))
+			return 0
+		}
+		keys := make([]string, 0, len(meta.Tags))
+		for k := range meta.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var out bytes.Buffer
+		for _, k := range keys {
+			fmt.Fprintf(&out, "%s = %s\n", k, meta.Tags[k])
+		}
+		c.Ui.Output(strings.TrimSuffix(out.String(), "\n"))
+		return 0
+	}
+
+	if meta.Tags == nil {
+		meta.Tags = make(map[string]string)
+	}
+	for k, v := range set {
+		meta.Tags[k] = v
+	}
+	for _, k := range remove {
+		delete(meta.Tags, k)
+	}
+
+	if err := store.SetWorkspaceMetadata(workspace, meta); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing workspace metadata: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+func (c *WorkspaceTagCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *WorkspaceTagCommand) AutocompleteFlags() complete.Flags {
+	return nil
+}
+
+func (c *WorkspaceTagCommand) Help() string {
+	helpText := `
+Usage: terraform [global options] workspace tag [-unset] [KEY=VALUE ...]
+
+  Manage tags on the current workspace.
+
+  Tags are arbitrary key/value pairs stored alongside the workspace's state,
+  for organizing workspaces. They have no effect on Terraform's behavior.
+
+  With no arguments, the current workspace's tags are listed. Each KEY=VALUE
+  argument sets a tag, replacing any existing tag of the same name.
+
+Options:
+
+    -unset              Remove the tags named by the arguments instead of
+                        setting them.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceTagCommand) Synopsis() string {
+	return "Manage tags on the current workspace"
+}
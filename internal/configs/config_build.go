@@ -32,6 +32,7 @@ func BuildConfig(root *Module, walker ModuleWalker) (*Config, hcl.Diagnostics) {
 	}
 
 	diags = append(diags, validateProviderConfigs(nil, cfg, nil)...)
+	diags = append(diags, checkDeprecatedUsage(cfg)...)
 
 	return cfg, diags
 }
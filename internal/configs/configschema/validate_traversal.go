@@ -100,9 +100,9 @@ func (b *Block) StaticValidateTraversal(traversal hcl.Traversal) tfdiags.Diagnos
 		suggestions = append(suggestions, name)
 	}
 	sort.Strings(suggestions)
-	suggestion := didyoumean.NameSuggestion(name, suggestions)
-	if suggestion != "" {
-		suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+	var suggestion string
+	if candidates := didyoumean.NameSuggestions(name, suggestions, 3); len(candidates) > 0 {
+		suggestion = fmt.Sprintf(" Did you mean %s?", didyoumean.SuggestionSentence(candidates))
 	}
 	diags = diags.Append(&hcl.Diagnostic{
 		Severity: hcl.DiagError,
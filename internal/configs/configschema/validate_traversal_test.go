@@ -100,6 +100,10 @@ func TestStaticValidateTraversal(t *testing.T) {
 			`obj.atr`,
 			`Unsupported attribute: This object has no argument, nested block, or exported attribute named "atr". Did you mean "str"?`,
 		},
+		{
+			`obj.lst`,
+			`Unsupported attribute: This object has no argument, nested block, or exported attribute named "lst". Did you mean "list" or "str"?`,
+		},
 		{
 			`obj.single_block`,
 			``,
@@ -0,0 +1,184 @@
+package configs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// checkDeprecatedUsage inspects the given configuration tree for callers
+// that set a deprecated input variable or refer to a deprecated output
+// value of one of their child modules, and returns a warning for each such
+// usage quoting the module author's deprecation message.
+//
+// The warnings point at the caller's own source, since the caller is the
+// one who needs to act on them. Modules written in JSON syntax are skipped,
+// because their bodies cannot be analyzed without provider schemas.
+func checkDeprecatedUsage(cfg *Config) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	cfg.DeepEach(func(c *Config) {
+		// Deprecated variables: look at the arguments each module call in
+		// this module passes to its child.
+		callNames := make([]string, 0, len(c.Module.ModuleCalls))
+		for name := range c.Module.ModuleCalls {
+			callNames = append(callNames, name)
+		}
+		sort.Strings(callNames)
+		for _, name := range callNames {
+			call := c.Module.ModuleCalls[name]
+			child, exists := c.Children[name]
+			if !exists {
+				continue
+			}
+			body, ok := call.Config.(*hclsyntax.Body)
+			if !ok {
+				continue
+			}
+			attrNames := make([]string, 0, len(body.Attributes))
+			for attrName := range body.Attributes {
+				attrNames = append(attrNames, attrName)
+			}
+			sort.Strings(attrNames)
+			for _, attrName := range attrNames {
+				v, declared := child.Module.Variables[attrName]
+				if !declared || !v.DeprecatedSet {
+					continue
+				}
+				attr := body.Attributes[attrName]
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "Deprecated variable",
+					Detail:   fmt.Sprintf("The variable %q in module %q is deprecated: %s", attrName, name, v.Deprecated),
+					Subject:  attr.NameRange.Ptr(),
+				})
+			}
+		}
+
+		// Deprecated outputs: look at every reference this module makes to
+		// an output value of one of its children.
+		for _, traversal := range moduleReferenceTraversals(c.Module) {
+			if traversal.RootName() != "module" || len(traversal) < 2 {
+				continue
+			}
+			callStep, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+			child, exists := c.Children[callStep.Name]
+			if !exists {
+				continue
+			}
+
+			// The output name is the next attribute step, skipping over any
+			// index steps from count or for_each expansion.
+			var outputName string
+			for _, rawStep := range traversal[2:] {
+				if step, ok := rawStep.(hcl.TraverseAttr); ok {
+					outputName = step.Name
+					break
+				}
+			}
+			if outputName == "" {
+				continue
+			}
+
+			o, declared := child.Module.Outputs[outputName]
+			if !declared || !o.DeprecatedSet {
+				continue
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Deprecated output value",
+				Detail:   fmt.Sprintf("The output value %q in module %q is deprecated: %s", outputName, callStep.Name, o.Deprecated),
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+		}
+	})
+
+	return diags
+}
+
+// moduleReferenceTraversals collects every traversal referenced by the
+// native-syntax expressions in the given module. Bodies that are not native
+// syntax, such as JSON configuration files, contribute nothing, so callers
+// must only use the result for advisory purposes.
+func moduleReferenceTraversals(m *Module) []hcl.Traversal {
+	var refs []hcl.Traversal
+
+	appendExpr := func(expr hcl.Expression) {
+		if expr == nil {
+			return
+		}
+		refs = append(refs, expr.Variables()...)
+	}
+	var appendBody func(body hcl.Body)
+	appendBody = func(body hcl.Body) {
+		sb, ok := body.(*hclsyntax.Body)
+		if !ok {
+			return
+		}
+		for _, attr := range sb.Attributes {
+			appendExpr(attr.Expr)
+		}
+		for _, block := range sb.Blocks {
+			appendBody(block.Body)
+		}
+	}
+	appendCheckRules := func(rules []*CheckRule) {
+		for _, rule := range rules {
+			appendExpr(rule.Condition)
+			appendExpr(rule.ErrorMessage)
+		}
+	}
+
+	for _, pc := range m.ProviderConfigs {
+		appendBody(pc.Config)
+	}
+	for _, l := range m.Locals {
+		appendExpr(l.Expr)
+	}
+	for _, o := range m.Outputs {
+		appendExpr(o.Expr)
+		appendCheckRules(o.Preconditions)
+		refs = append(refs, o.DependsOn...)
+	}
+	for _, mc := range m.ModuleCalls {
+		appendBody(mc.Config)
+		appendExpr(mc.Count)
+		appendExpr(mc.ForEach)
+		refs = append(refs, mc.DependsOn...)
+	}
+	appendResource := func(r *Resource) {
+		appendBody(r.Config)
+		appendExpr(r.Count)
+		appendExpr(r.ForEach)
+		for _, expr := range r.TriggersReplacement {
+			appendExpr(expr)
+		}
+		appendCheckRules(r.Preconditions)
+		appendCheckRules(r.Postconditions)
+		refs = append(refs, r.DependsOn...)
+		if r.Managed != nil {
+			if r.Managed.Connection != nil {
+				appendBody(r.Managed.Connection.Config)
+			}
+			for _, p := range r.Managed.Provisioners {
+				appendBody(p.Config)
+				if p.Connection != nil {
+					appendBody(p.Connection.Config)
+				}
+			}
+		}
+	}
+	for _, r := range m.ManagedResources {
+		appendResource(r)
+	}
+	for _, r := range m.DataResources {
+		appendResource(r)
+	}
+
+	return refs
+}
@@ -0,0 +1,70 @@
+package configs
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestCheckDeprecatedUsage(t *testing.T) {
+	parser := NewParser(nil)
+	mod, diags := parser.LoadConfigDir("testdata/deprecated")
+	assertNoDiagnostics(t, diags)
+	if mod == nil {
+		t.Fatal("got nil root module; want non-nil")
+	}
+
+	cfg, diags := BuildConfig(mod, ModuleWalkerFunc(
+		func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+			// For the sake of this test we're going to just treat our
+			// SourceAddr as a path relative to our fixture directory.
+			sourcePath := filepath.Join("testdata/deprecated", req.SourceAddr.String())
+			mod, diags := parser.LoadConfigDir(sourcePath)
+			return mod, nil, diags
+		},
+	))
+	if cfg == nil {
+		t.Fatal("got nil config; want non-nil")
+	}
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	if len(diags) != 2 {
+		t.Fatalf("wrong number of warnings %d; want 2\n%s", len(diags), diags)
+	}
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagWarning {
+			t.Errorf("wrong severity for %q; want warning", diag.Summary)
+		}
+	}
+
+	wantDetails := []string{
+		`The variable "old_name" in module "child" is deprecated: Use new_name instead.`,
+		`The output value "old_id" in module "child" is deprecated: Use new_id instead.`,
+	}
+	for _, want := range wantDetails {
+		found := false
+		for _, diag := range diags {
+			if diag.Detail == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing warning with detail %q\ngot: %s", want, diags)
+		}
+	}
+	for _, diag := range diags {
+		if diag.Subject == nil {
+			t.Errorf("warning %q has no source range", diag.Summary)
+			continue
+		}
+		if !strings.HasSuffix(diag.Subject.Filename, "root.tf") {
+			t.Errorf("warning %q points at %s; want the caller's root.tf", diag.Summary, diag.Subject.Filename)
+		}
+	}
+}
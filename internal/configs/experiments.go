@@ -196,6 +196,19 @@ func checkModuleExperiments(m *Module) hcl.Diagnostics {
 		}
 	*/
 
+	if !m.ActiveExperiments.Has(experiments.ProviderOverrides) {
+		for _, rc := range m.ManagedResources {
+			if rc.ProviderOverride != nil {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Per-resource provider overrides are experimental",
+					Detail:   "This feature is currently an opt-in experiment, subject to change in future releases based on feedback.\n\nActivate the feature for this module by adding provider_overrides to the list of active experiments.",
+					Subject:  rc.ProviderOverride.DeclRange.Ptr(),
+				})
+			}
+		}
+	}
+
 	if !m.ActiveExperiments.Has(experiments.ModuleVariableOptionalAttrs) {
 		for _, v := range m.Variables {
 			if typeConstraintHasOptionalAttrs(v.ConstraintType) {
@@ -45,6 +45,8 @@ type Module struct {
 	DataResources    map[string]*Resource
 
 	Moved []*Moved
+
+	Preconditions []*CheckRule
 }
 
 // File describes the contents of a single configuration file.
@@ -79,6 +81,8 @@ type File struct {
 	DataResources    []*Resource
 
 	Moved []*Moved
+
+	Preconditions []*CheckRule
 }
 
 // NewModule takes a list of primary files and a list of override files and
@@ -148,6 +152,12 @@ func NewModule(primaryFiles, overrideFiles []*File) (*Module, hcl.Diagnostics) {
 		diags = append(diags, fileDiags...)
 	}
 
+	// Resources with provider_override blocks need synthetic provider
+	// configurations generated for each distinct override, which must happen
+	// after all of the files are merged so that the base provider blocks are
+	// fully resolved.
+	diags = append(diags, mod.synthesizeProviderOverrides()...)
+
 	diags = append(diags, checkModuleExperiments(mod)...)
 
 	// Generate the FQN -> LocalProviderName map
@@ -362,6 +372,10 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 	// them at runtime.)
 	m.Moved = append(m.Moved, file.Moved...)
 
+	// Module-level precondition blocks also just append, because each one
+	// is an independent assertion.
+	m.Preconditions = append(m.Preconditions, file.Preconditions...)
+
 	return diags
 }
 
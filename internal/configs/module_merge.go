@@ -60,6 +60,10 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 		v.Nullable = ov.Nullable
 		v.NullableSet = ov.NullableSet
 	}
+	if ov.DeprecatedSet {
+		v.Deprecated = ov.Deprecated
+		v.DeprecatedSet = ov.DeprecatedSet
+	}
 
 	// If the override file overrode type without default or vice-versa then
 	// it may have created an invalid situation, which we'll catch now by
@@ -145,6 +149,10 @@ func (o *Output) merge(oo *Output) hcl.Diagnostics {
 		o.Sensitive = oo.Sensitive
 		o.SensitiveSet = oo.SensitiveSet
 	}
+	if oo.DeprecatedSet {
+		o.Deprecated = oo.Deprecated
+		o.DeprecatedSet = oo.DeprecatedSet
+	}
 
 	// We don't allow depends_on to be overridden because that is likely to
 	// cause confusing misbehavior.
@@ -249,6 +257,10 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 			r.Managed.PreventDestroy = or.Managed.PreventDestroy
 			r.Managed.PreventDestroySet = or.Managed.PreventDestroySet
 		}
+		if or.Managed.ApplyPrioritySet {
+			r.Managed.ApplyPriority = or.Managed.ApplyPriority
+			r.Managed.ApplyPrioritySet = or.Managed.ApplyPrioritySet
+		}
 		if len(or.Managed.Provisioners) != 0 {
 			r.Managed.Provisioners = or.Managed.Provisioners
 		}
@@ -41,6 +41,12 @@ type Variable struct {
 	Nullable    bool
 	NullableSet bool
 
+	// Deprecated is an optional message from the module author indicating
+	// that callers should no longer set this variable. Terraform emits a
+	// warning including this message whenever a caller sets the variable.
+	Deprecated    string
+	DeprecatedSet bool
+
 	DeclRange hcl.Range
 }
 
@@ -115,6 +121,12 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		v.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["deprecated"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Deprecated)
+		diags = append(diags, valDiags...)
+		v.DeprecatedSet = true
+	}
+
 	if attr, exists := content.Attributes["nullable"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Nullable)
 		diags = append(diags, valDiags...)
@@ -386,6 +398,12 @@ type Output struct {
 	DescriptionSet bool
 	SensitiveSet   bool
 
+	// Deprecated is an optional message from the module author indicating
+	// that callers should no longer read this output value. Terraform emits
+	// a warning including this message whenever a caller refers to it.
+	Deprecated    string
+	DeprecatedSet bool
+
 	DeclRange hcl.Range
 }
 
@@ -430,6 +448,12 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 		o.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["deprecated"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Deprecated)
+		diags = append(diags, valDiags...)
+		o.DeprecatedSet = true
+	}
+
 	if attr, exists := content.Attributes["depends_on"]; exists {
 		deps, depsDiags := decodeDependsOn(attr)
 		diags = append(diags, depsDiags...)
@@ -520,6 +544,9 @@ var variableBlockSchema = &hcl.BodySchema{
 		{
 			Name: "nullable",
 		},
+		{
+			Name: "deprecated",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -543,6 +570,9 @@ var outputBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "deprecated",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
@@ -162,6 +162,13 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 				file.Moved = append(file.Moved, cfg)
 			}
 
+		case "precondition":
+			cfg, cfgDiags := decodeCheckRuleBlock(block, override)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				file.Preconditions = append(file.Preconditions, cfg)
+			}
+
 		default:
 			// Should never happen because the above cases should be exhaustive
 			// for all block type names in our schema.
@@ -252,6 +259,9 @@ var configFileSchema = &hcl.BodySchema{
 		{
 			Type: "moved",
 		},
+		{
+			Type: "precondition",
+		},
 	},
 }
 
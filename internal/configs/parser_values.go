@@ -1,6 +1,9 @@
 package configs
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -19,8 +22,27 @@ import (
 // static analysis.
 //
 // This method wraps LoadHCLFile, and so it inherits the syntax selection
-// behaviors documented for that method.
+// behaviors documented for that method. Additionally, if the filename ends
+// in ".yaml" or ".yml" then the file is parsed as YAML instead, with its
+// top-level mapping keys treated as the key/value pairs.
 func (p *Parser) LoadValuesFile(path string) (map[string]cty.Value, hcl.Diagnostics) {
+	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+		src, err := p.fs.ReadFile(path)
+		if err != nil {
+			return nil, hcl.Diagnostics{
+				{
+					Severity: hcl.DiagError,
+					Summary:  "Failed to read file",
+					Detail:   fmt.Sprintf("The file %q could not be read.", path),
+				},
+			}
+		}
+		// Record the source so that diagnostics can include snippets.
+		p.ForceFileSource(path, src)
+		vals, _, diags := ParseYAMLValues(src, path)
+		return vals, diags
+	}
+
 	body, diags := p.LoadHCLFile(path)
 	if body == nil {
 		return nil, diags
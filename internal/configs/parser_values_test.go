@@ -77,6 +77,44 @@ func TestParserLoadValuesFile(t *testing.T) {
 			},
 			1, // variables cannot be referenced here
 		},
+		"empty.tfvars.yaml": {
+			"",
+			map[string]cty.Value{},
+			0,
+		},
+		"scalars.tfvars.yml": {
+			"str: hello\nnum: 12\nflag: true\nnothing: null\n",
+			map[string]cty.Value{
+				"str":     cty.StringVal("hello"),
+				"num":     cty.NumberIntVal(12),
+				"flag":    cty.True,
+				"nothing": cty.NullVal(cty.DynamicPseudoType),
+			},
+			0,
+		},
+		"structural.tfvars.yaml": {
+			"list:\n  - a\n  - 2\nmap:\n  inner: true\n",
+			map[string]cty.Value{
+				"list": cty.TupleVal([]cty.Value{
+					cty.StringVal("a"),
+					cty.NumberIntVal(2),
+				}),
+				"map": cty.ObjectVal(map[string]cty.Value{
+					"inner": cty.True,
+				}),
+			},
+			0,
+		},
+		"not-mapping.tfvars.yaml": {
+			"- a\n- b\n",
+			map[string]cty.Value{},
+			1, // top level must be a mapping
+		},
+		"invalid-syntax.tfvars.yaml": {
+			"foo: [\n",
+			map[string]cty.Value{},
+			1, // YAML syntax error
+		},
 	}
 
 	for name, test := range tests {
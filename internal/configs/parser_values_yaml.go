@@ -0,0 +1,203 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ParseYAMLValues parses the given source code as a YAML "values file",
+// whose top-level mapping keys are treated as arbitrary key/value pairs in
+// the same way as the top-level attributes of an HCL values file.
+//
+// Along with the values themselves it returns the source location of each
+// top-level key, so that callers can produce diagnostics that refer back
+// into the original file. The given filename is used only to annotate those
+// locations.
+//
+// If the returned diagnostics has errors then the value map may be
+// incomplete, but is always valid to use.
+func ParseYAMLValues(src []byte, filename string) (map[string]cty.Value, map[string]hcl.Range, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	vals := make(map[string]cty.Value)
+	rngs := make(map[string]hcl.Range)
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid YAML syntax",
+			Detail:   fmt.Sprintf("The file %q could not be parsed as YAML: %s.", filename, err),
+		})
+		return vals, rngs, diags
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		// An empty document defines no values at all.
+		return vals, rngs, diags
+	}
+
+	root := doc.Content[0]
+	for root.Kind == yaml.AliasNode && root.Alias != nil {
+		root = root.Alias
+	}
+	if root.Kind != yaml.MappingNode {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid variables file",
+			Detail:   "A YAML variables file must have a mapping at its top level, with one key per variable.",
+			Subject:  yamlNodeRange(root, filename).Ptr(),
+		})
+		return vals, rngs, diags
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		keyNode := root.Content[i]
+		valNode := root.Content[i+1]
+		if keyNode.Kind != yaml.ScalarNode {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid variable name",
+				Detail:   "Each top-level key in a YAML variables file must be a string naming a variable.",
+				Subject:  yamlNodeRange(keyNode, filename).Ptr(),
+			})
+			continue
+		}
+		name := keyNode.Value
+		if _, exists := vals[name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate value definition",
+				Detail:   fmt.Sprintf("A value for %q was already defined earlier in this file.", name),
+				Subject:  yamlNodeRange(keyNode, filename).Ptr(),
+			})
+			continue
+		}
+		val, valDiags := yamlNodeValue(valNode, filename)
+		diags = append(diags, valDiags...)
+		vals[name] = val
+		rngs[name] = yamlNodeRange(keyNode, filename)
+	}
+
+	return vals, rngs, diags
+}
+
+// yamlNodeValue converts a single YAML node into the equivalent cty value,
+// using tuple and object types for sequences and mappings in the same way
+// that HCL JSON syntax does.
+func yamlNodeValue(node *yaml.Node, filename string) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return cty.NullVal(cty.DynamicPseudoType), diags
+		case "!!bool":
+			var b bool
+			if err := node.Decode(&b); err != nil {
+				diags = append(diags, yamlValueError(node, filename, err))
+				return cty.DynamicVal, diags
+			}
+			return cty.BoolVal(b), diags
+		case "!!int", "!!float":
+			if v, err := cty.ParseNumberVal(node.Value); err == nil {
+				return v, diags
+			}
+			// The scalar might use a representation that cty can't parse
+			// directly, such as a hexadecimal integer, so we'll fall back
+			// on letting the YAML decoder interpret it.
+			var f float64
+			if err := node.Decode(&f); err != nil {
+				diags = append(diags, yamlValueError(node, filename, err))
+				return cty.DynamicVal, diags
+			}
+			return cty.NumberFloatVal(f), diags
+		case "!!str", "!!timestamp":
+			return cty.StringVal(node.Value), diags
+		default:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported YAML value",
+				Detail:   fmt.Sprintf("Values with YAML tag %s cannot be used as Terraform variable values.", node.Tag),
+				Subject:  yamlNodeRange(node, filename).Ptr(),
+			})
+			return cty.DynamicVal, diags
+		}
+
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			return cty.EmptyTupleVal, diags
+		}
+		elems := make([]cty.Value, len(node.Content))
+		for i, elemNode := range node.Content {
+			elem, elemDiags := yamlNodeValue(elemNode, filename)
+			diags = append(diags, elemDiags...)
+			elems[i] = elem
+		}
+		return cty.TupleVal(elems), diags
+
+	case yaml.MappingNode:
+		attrs := make(map[string]cty.Value)
+		for i := 0; i < len(node.Content); i += 2 {
+			keyNode := node.Content[i]
+			valNode := node.Content[i+1]
+			if keyNode.Kind != yaml.ScalarNode {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid mapping key",
+					Detail:   "Mapping keys in a YAML variables file must be strings.",
+					Subject:  yamlNodeRange(keyNode, filename).Ptr(),
+				})
+				continue
+			}
+			if _, exists := attrs[keyNode.Value]; exists {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate mapping key",
+					Detail:   fmt.Sprintf("The key %q was already used earlier in this mapping.", keyNode.Value),
+					Subject:  yamlNodeRange(keyNode, filename).Ptr(),
+				})
+				continue
+			}
+			val, valDiags := yamlNodeValue(valNode, filename)
+			diags = append(diags, valDiags...)
+			attrs[keyNode.Value] = val
+		}
+		if len(attrs) == 0 {
+			return cty.EmptyObjectVal, diags
+		}
+		return cty.ObjectVal(attrs), diags
+
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported YAML value",
+			Detail:   "This YAML construct cannot be used as a Terraform variable value.",
+			Subject:  yamlNodeRange(node, filename).Ptr(),
+		})
+		return cty.DynamicVal, diags
+	}
+}
+
+func yamlValueError(node *yaml.Node, filename string, err error) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid YAML value",
+		Detail:   fmt.Sprintf("The value %q is not valid: %s.", node.Value, err),
+		Subject:  yamlNodeRange(node, filename).Ptr(),
+	}
+}
+
+// yamlNodeRange approximates an hcl.Range for the given YAML node. The YAML
+// parser doesn't track byte offsets or end positions, so the range marks
+// only the point where the node begins.
+func yamlNodeRange(node *yaml.Node, filename string) hcl.Range {
+	pos := hcl.Pos{Line: node.Line, Column: node.Column}
+	return hcl.Range{Filename: filename, Start: pos, End: pos}
+}
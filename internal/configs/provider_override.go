@@ -0,0 +1,217 @@
+package configs
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ProviderOverride represents a "provider_override" block inside a resource
+// block, which overrides a small set of provider configuration arguments for
+// that one resource without requiring a separate aliased provider block.
+//
+// This is part of the "provider_overrides" experiment and is subject to
+// change or removal in future releases.
+type ProviderOverride struct {
+	// Values are the provider configuration arguments being overridden.
+	// These are always constant values, because each distinct combination
+	// of values implies a distinct provider configuration and so the values
+	// must be decided before expression evaluation is possible.
+	Values map[string]cty.Value
+
+	DeclRange hcl.Range
+}
+
+func decodeProviderOverrideBlock(block *hcl.Block) (*ProviderOverride, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ret := &ProviderOverride{
+		Values:    make(map[string]cty.Value),
+		DeclRange: block.DefRange,
+	}
+
+	attrs, moreDiags := block.Body.JustAttributes()
+	diags = append(diags, moreDiags...)
+
+	for name, attr := range attrs {
+		// We evaluate with no evaluation context here because override
+		// values must be constants: they decide how many provider
+		// configurations exist, which must be known before we can set up
+		// the evaluation scopes that references would require.
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+		if val.IsNull() || !val.IsWhollyKnown() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider override value",
+				Detail:   "Provider override arguments must be known, non-null constant values, because each distinct combination of override values selects a distinct provider configuration.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			continue
+		}
+		ret.Values[name] = val
+	}
+
+	if len(ret.Values) == 0 && !diags.HasErrors() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Empty provider_override block",
+			Detail:   "A provider_override block must set at least one provider configuration argument.",
+			Subject:  &ret.DeclRange,
+		})
+	}
+
+	return ret, diags
+}
+
+// providerOverrideAliasPrefix marks a provider configuration alias as having
+// been synthesized from a provider_override block, rather than declared by
+// the module author.
+const providerOverrideAliasPrefix = "override_"
+
+// SyntheticAlias returns the provider configuration alias that represents
+// this override when applied on top of the configuration with the given
+// base alias, which may be empty for the default configuration.
+//
+// The result is deterministic for a given base alias and set of values, so
+// that resources with identical overrides share a single synthesized
+// provider configuration, and it encodes the override values themselves so
+// that the effective configuration can be recovered from an address recorded
+// in the state even after the originating resource block has been removed.
+// See DecodeProviderOverrideAlias for the reverse operation.
+func (po *ProviderOverride) SyntheticAlias(baseAlias string) string {
+	return providerOverrideAlias(baseAlias, po.Values)
+}
+
+// providerOverrideAliasDoc is the JSON document encoded into a synthetic
+// provider configuration alias.
+type providerOverrideAliasDoc struct {
+	Base   string                     `json:"base,omitempty"`
+	Values map[string]json.RawMessage `json:"values"`
+}
+
+func providerOverrideAlias(baseAlias string, values map[string]cty.Value) string {
+	doc := providerOverrideAliasDoc{
+		Base:   baseAlias,
+		Values: make(map[string]json.RawMessage, len(values)),
+	}
+	for name, val := range values {
+		src, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			// Should never happen, because the values are always known
+			// constants by the time we get here.
+			panic(fmt.Sprintf("failed to encode provider override value %q: %s", name, err))
+		}
+		doc.Values[name] = json.RawMessage(src)
+	}
+	src, err := json.Marshal(doc) // deterministic: encoding/json sorts map keys
+	if err != nil {
+		panic(fmt.Sprintf("failed to encode provider override alias: %s", err))
+	}
+
+	// Base32 (lowercased, unpadded) keeps the alias a valid HCL identifier,
+	// which is required for the address to round-trip through the state.
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	return providerOverrideAliasPrefix + strings.ToLower(enc.EncodeToString(src))
+}
+
+// DecodeProviderOverrideAlias recognizes a provider configuration alias
+// created by ProviderOverride.SyntheticAlias, returning the base alias it
+// was derived from and the override values it encodes.
+//
+// If the given alias was not produced by SyntheticAlias then ok is false and
+// the other return values are meaningless.
+func DecodeProviderOverrideAlias(alias string) (baseAlias string, values map[string]cty.Value, ok bool) {
+	if !strings.HasPrefix(alias, providerOverrideAliasPrefix) {
+		return "", nil, false
+	}
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	src, err := enc.DecodeString(strings.ToUpper(alias[len(providerOverrideAliasPrefix):]))
+	if err != nil {
+		return "", nil, false
+	}
+	var doc providerOverrideAliasDoc
+	if err := json.Unmarshal(src, &doc); err != nil {
+		return "", nil, false
+	}
+	values = make(map[string]cty.Value, len(doc.Values))
+	for name, raw := range doc.Values {
+		ty, err := ctyjson.ImpliedType([]byte(raw))
+		if err != nil {
+			return "", nil, false
+		}
+		val, err := ctyjson.Unmarshal([]byte(raw), ty)
+		if err != nil {
+			return "", nil, false
+		}
+		values[name] = val
+	}
+	return doc.Base, values, true
+}
+
+// synthesizeProviderOverrides generates additional entries in the module's
+// ProviderConfigs table for each distinct provider override used by the
+// module's managed resources, merging the override values over the
+// configuration of the base provider block where one is declared.
+//
+// Resources whose overrides produce the same synthetic alias share a single
+// generated configuration, so each distinct combination of override values
+// results in exactly one additional provider instance.
+func (m *Module) synthesizeProviderOverrides() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, r := range m.ManagedResources {
+		if r.ProviderOverride == nil {
+			continue
+		}
+
+		base := r.baseProviderConfigAddr()
+		synthAddr := r.ProviderConfigAddr()
+		key := synthAddr.LocalName + "." + synthAddr.Alias
+		if _, exists := m.ProviderConfigs[key]; exists {
+			// Another resource with an identical override already caused
+			// this configuration to be synthesized, so they will share it.
+			continue
+		}
+
+		baseKey := base.LocalName
+		if base.Alias != "" {
+			baseKey = fmt.Sprintf("%s.%s", base.LocalName, base.Alias)
+		}
+		baseProvider, hasBase := m.ProviderConfigs[baseKey]
+		if !hasBase && base.Alias != "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Provider override for undeclared provider configuration",
+				Detail:   fmt.Sprintf("This provider_override block applies to provider configuration %s.%s, but no such provider configuration is declared in this module.", base.LocalName, base.Alias),
+				Subject:  &r.ProviderOverride.DeclRange,
+			})
+			continue
+		}
+
+		body := SynthBody(fmt.Sprintf("%s provider_override", r.Addr()), r.ProviderOverride.Values)
+		declRange := r.ProviderOverride.DeclRange
+		if hasBase {
+			body = MergeBodies(baseProvider.Config, body)
+			declRange = baseProvider.DeclRange
+		}
+
+		m.ProviderConfigs[key] = &Provider{
+			Name:       base.LocalName,
+			NameRange:  r.ProviderOverride.DeclRange,
+			Alias:      synthAddr.Alias,
+			AliasRange: r.ProviderOverride.DeclRange.Ptr(),
+			Config:     body,
+			DeclRange:  declRange,
+		}
+	}
+
+	return diags
+}
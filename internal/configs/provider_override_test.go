@@ -0,0 +1,154 @@
+package configs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestProviderOverrideAliasRoundTrip(t *testing.T) {
+	tests := []struct {
+		BaseAlias string
+		Values    map[string]cty.Value
+	}{
+		{
+			"",
+			map[string]cty.Value{
+				"region": cty.StringVal("eu-west-1"),
+			},
+		},
+		{
+			"east",
+			map[string]cty.Value{
+				"region":      cty.StringVal("ap-southeast-2"),
+				"max_retries": cty.NumberIntVal(3),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		alias := providerOverrideAlias(test.BaseAlias, test.Values)
+
+		if !hclsyntax.ValidIdentifier(alias) {
+			t.Errorf("alias %q is not a valid identifier", alias)
+		}
+		if again := providerOverrideAlias(test.BaseAlias, test.Values); again != alias {
+			t.Errorf("alias is not deterministic: %q then %q", alias, again)
+		}
+
+		gotBase, gotValues, ok := DecodeProviderOverrideAlias(alias)
+		if !ok {
+			t.Fatalf("failed to decode alias %q", alias)
+		}
+		if gotBase != test.BaseAlias {
+			t.Errorf("wrong base alias %q; want %q", gotBase, test.BaseAlias)
+		}
+		if len(gotValues) != len(test.Values) {
+			t.Fatalf("wrong number of values %d; want %d", len(gotValues), len(test.Values))
+		}
+		for name, want := range test.Values {
+			if got, exists := gotValues[name]; !exists || !want.RawEquals(got) {
+				t.Errorf("wrong value for %q: %#v; want %#v", name, got, want)
+			}
+		}
+	}
+
+	if _, _, ok := DecodeProviderOverrideAlias("east"); ok {
+		t.Error("decoded an ordinary alias as an override alias")
+	}
+	if _, _, ok := DecodeProviderOverrideAlias("override_notvalidbase32!"); ok {
+		t.Error("decoded a malformed override alias")
+	}
+}
+
+func TestModuleProviderOverrides(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/main.tf": `
+terraform {
+  experiments = [provider_overrides]
+}
+
+provider "aws" {
+  region = "us-west-2"
+}
+
+resource "aws_instance" "a" {
+  provider_override {
+    region = "eu-west-1"
+  }
+}
+
+resource "aws_instance" "b" {
+  provider_override {
+    region = "eu-west-1"
+  }
+}
+
+resource "aws_instance" "c" {
+  provider_override {
+    region = "ap-southeast-2"
+  }
+}
+`,
+	})
+
+	mod, diags := parser.LoadConfigDir("mod")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	// Resources "a" and "b" have identical overrides and so must share one
+	// synthesized configuration, while "c" gets its own.
+	aAddr := mod.ManagedResources["aws_instance.a"].ProviderConfigAddr()
+	bAddr := mod.ManagedResources["aws_instance.b"].ProviderConfigAddr()
+	cAddr := mod.ManagedResources["aws_instance.c"].ProviderConfigAddr()
+	if aAddr != bAddr {
+		t.Errorf("resources with identical overrides have different provider addresses: %s vs %s", aAddr, bAddr)
+	}
+	if aAddr == cAddr {
+		t.Errorf("resources with different overrides share provider address %s", aAddr)
+	}
+
+	synthCount := 0
+	for _, pc := range mod.ProviderConfigs {
+		if _, _, ok := DecodeProviderOverrideAlias(pc.Alias); ok {
+			synthCount++
+		}
+	}
+	if got, want := synthCount, 2; got != want {
+		t.Errorf("wrong number of synthesized provider configurations %d; want %d", got, want)
+	}
+
+	baseAlias, values, ok := DecodeProviderOverrideAlias(aAddr.Alias)
+	if !ok {
+		t.Fatalf("provider address %s does not have an override alias", aAddr)
+	}
+	if baseAlias != "" {
+		t.Errorf("wrong base alias %q; want default configuration", baseAlias)
+	}
+	if got, want := values["region"], cty.StringVal("eu-west-1"); !want.RawEquals(got) {
+		t.Errorf("wrong region value %#v; want %#v", got, want)
+	}
+}
+
+func TestModuleProviderOverridesExperimentGate(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/main.tf": `
+resource "aws_instance" "a" {
+  provider_override {
+    region = "eu-west-1"
+  }
+}
+`,
+	})
+
+	_, diags := parser.LoadConfigDir("mod")
+	if !diags.HasErrors() {
+		t.Fatal("unexpected success; want experiment gate error")
+	}
+	if got, want := diags.Error(), "Per-resource provider overrides are experimental"; !strings.Contains(got, want) {
+		t.Errorf("wrong error %q; want substring %q", got, want)
+	}
+}
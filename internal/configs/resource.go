@@ -25,6 +25,12 @@ type Resource struct {
 	ProviderConfigRef *ProviderConfigRef
 	Provider          addrs.Provider
 
+	// ProviderOverride is set only when the resource block contains a
+	// provider_override block, which is allowed only for managed resources
+	// and only when the "provider_overrides" experiment is active for the
+	// containing module.
+	ProviderOverride *ProviderOverride
+
 	Preconditions  []*CheckRule
 	Postconditions []*CheckRule
 
@@ -51,8 +57,16 @@ type ManagedResource struct {
 	IgnoreChanges       []hcl.Traversal
 	IgnoreAllChanges    bool
 
+	// ApplyPriority biases when this resource's instances are scheduled
+	// relative to otherwise-unordered work during the apply graph walk.
+	// Higher values are scheduled later among ready nodes, while zero (the
+	// default) preserves the usual scheduling. It never overrides real
+	// dependency ordering.
+	ApplyPriority int
+
 	CreateBeforeDestroySet bool
 	PreventDestroySet      bool
+	ApplyPrioritySet       bool
 }
 
 func (r *Resource) moduleUniqueKey() string {
@@ -72,7 +86,21 @@ func (r *Resource) Addr() addrs.Resource {
 // ProviderConfigAddr returns the address for the provider configuration that
 // should be used for this resource. This function returns a default provider
 // config addr if an explicit "provider" argument was not provided.
+//
+// If the resource has a provider_override block then the returned address
+// uses the synthetic alias representing the override applied on top of the
+// base configuration, rather than the base configuration's own alias.
 func (r *Resource) ProviderConfigAddr() addrs.LocalProviderConfig {
+	addr := r.baseProviderConfigAddr()
+	if r.ProviderOverride != nil {
+		addr.Alias = r.ProviderOverride.SyntheticAlias(addr.Alias)
+	}
+	return addr
+}
+
+// baseProviderConfigAddr is the address of the provider configuration the
+// resource block refers to directly, disregarding any provider_override.
+func (r *Resource) baseProviderConfigAddr() addrs.LocalProviderConfig {
 	if r.ProviderConfigRef == nil {
 		// If no specific "provider" argument is given, we want to look up the
 		// provider config where the local name matches the implied provider
@@ -188,6 +216,12 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 				r.Managed.PreventDestroySet = true
 			}
 
+			if attr, exists := lcContent.Attributes["apply_priority"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Managed.ApplyPriority)
+				diags = append(diags, valDiags...)
+				r.Managed.ApplyPrioritySet = true
+			}
+
 			if attr, exists := lcContent.Attributes["replace_triggered_by"]; exists {
 				exprs, hclDiags := decodeReplaceTriggeredBy(attr.Expr)
 				diags = diags.Extend(hclDiags)
@@ -296,6 +330,23 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 				DeclRange: block.DefRange,
 			}
 
+		case "provider_override":
+			if r.ProviderOverride != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate provider_override block",
+					Detail:   fmt.Sprintf("This resource already has a provider_override block at %s.", r.ProviderOverride.DeclRange),
+					Subject:  &block.DefRange,
+				})
+				continue
+			}
+
+			po, poDiags := decodeProviderOverrideBlock(block)
+			diags = append(diags, poDiags...)
+			if !poDiags.HasErrors() {
+				r.ProviderOverride = po
+			}
+
 		case "provisioner":
 			pv, pvDiags := decodeProvisionerBlock(block)
 			diags = append(diags, pvDiags...)
@@ -737,6 +788,7 @@ var resourceBlockSchema = &hcl.BodySchema{
 		{Type: "locals"}, // reserved for future use
 		{Type: "lifecycle"},
 		{Type: "connection"},
+		{Type: "provider_override"}, // experimental
 		{Type: "provisioner", LabelNames: []string{"type"}},
 		{Type: "_"}, // meta-argument escaping block
 	},
@@ -763,6 +815,9 @@ var resourceLifecycleBlockSchema = &hcl.BodySchema{
 		{
 			Name: "prevent_destroy",
 		},
+		{
+			Name: "apply_priority",
+		},
 		{
 			Name: "ignore_changes",
 		},
@@ -0,0 +1,19 @@
+variable "old_name" {
+  type       = string
+  default    = null
+  deprecated = "Use new_name instead."
+}
+
+variable "new_name" {
+  type    = string
+  default = null
+}
+
+output "old_id" {
+  value      = "old"
+  deprecated = "Use new_id instead."
+}
+
+output "new_id" {
+  value = "new"
+}
@@ -0,0 +1,14 @@
+module "child" {
+  source = "./child"
+
+  old_name = "a"
+  new_name = "b"
+}
+
+output "from_old" {
+  value = module.child.old_id
+}
+
+output "from_new" {
+  value = module.child.new_id
+}
@@ -0,0 +1,12 @@
+variable "tier" {
+  type = string
+}
+
+variable "instance_count" {
+  type = number
+}
+
+precondition {
+  condition     = var.tier != "prod" || var.instance_count >= 3
+  error_message = "Production deployments need at least three instances."
+}
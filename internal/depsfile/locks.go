@@ -17,6 +17,7 @@ import (
 // with reads.
 type Locks struct {
 	providers map[addrs.Provider]*ProviderLock
+	modules   map[string]*ModuleLock
 
 	// overriddenProviders is a subset of providers which we might be tracking
 	// in field providers but whose lock information we're disregarding for
@@ -32,12 +33,6 @@ type Locks struct {
 	// settings, environment variables, or whatever similar sources.
 	overriddenProviders map[addrs.Provider]struct{}
 
-	// TODO: In future we'll also have module locks, but the design of that
-	// still needs some more work and we're deferring that to get the
-	// provider locking capability out sooner, because it's more common to
-	// directly depend on providers maintained outside your organization than
-	// modules maintained outside your organization.
-
 	// sources is a copy of the map of source buffers produced by the HCL
 	// parser during loading, which we retain only so that the caller can
 	// use it to produce source code snippets in error messages.
@@ -49,6 +44,7 @@ type Locks struct {
 func NewLocks() *Locks {
 	return &Locks{
 		providers: make(map[addrs.Provider]*ProviderLock),
+		modules:   make(map[string]*ModuleLock),
 
 		// no "sources" here, because that's only for locks objects loaded
 		// from files.
@@ -115,6 +111,53 @@ func (l *Locks) RemoveProvider(addr addrs.Provider) {
 	delete(l.providers, addr)
 }
 
+// Module returns the stored lock for the module with the given key, or nil
+// if that module currently has no lock.
+//
+// The key is the module instance path string used in the module manifest,
+// such as "consul" or "consul.child", which uniquely identifies a module
+// call within the static module tree.
+func (l *Locks) Module(key string) *ModuleLock {
+	return l.modules[key]
+}
+
+// AllModules returns a map describing all of the module locks in the
+// receiver, keyed by module manifest key.
+func (l *Locks) AllModules() map[string]*ModuleLock {
+	// We return a copy of our internal map so that future calls to
+	// SetModule won't modify the map we're returning, or vice-versa.
+	ret := make(map[string]*ModuleLock, len(l.modules))
+	for k, v := range l.modules {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SetModule creates a new lock or replaces the existing lock for the module
+// with the given key.
+//
+// SetModule returns the newly-created module lock object, which invalidates
+// any ModuleLock object previously returned from Module or SetModule for
+// the same key.
+//
+// The ownership of the backing array for the slice of hashes passes to this
+// function, and so the caller must not read or write that backing array
+// after calling SetModule.
+func (l *Locks) SetModule(key string, source string, version string, hashes []getproviders.Hash) *ModuleLock {
+	new := NewModuleLock(key, source, version, hashes)
+	l.modules[new.key] = new
+	return new
+}
+
+// RemoveModule removes any existing lock file entry for the module with the
+// given key.
+//
+// If the given module did not already have a lock entry, RemoveModule is
+// a no-op.
+func (l *Locks) RemoveModule(key string) {
+	delete(l.modules, key)
+}
+
 // SetProviderOverridden records that this particular Terraform process will
 // not pay attention to the recorded lock entry for the given provider, and
 // will instead access that provider's functionality in some other special
@@ -209,6 +252,40 @@ func NewProviderLock(addr addrs.Provider, version getproviders.Version, constrai
 	}
 }
 
+// NewModuleLock creates a new ModuleLock object that isn't associated with
+// any Locks object.
+//
+// This is here primarily for testing. Most callers should use
+// Locks.SetModule to construct a new module lock and insert it into a Locks
+// object at the same time.
+//
+// The ownership of the backing array for the slice of hashes passes to this
+// function, and so the caller must not read or write that backing array
+// after calling NewModuleLock.
+func NewModuleLock(key string, source string, version string, hashes []getproviders.Hash) *ModuleLock {
+	// Normalize the hashes into lexical order, for the same reason as in
+	// NewProviderLock: so that equality tests between locks for the same
+	// module are straightforward.
+	sort.Slice(hashes, func(i, j int) bool {
+		return string(hashes[i]) < string(hashes[j])
+	})
+	dedupeHashes := hashes[:0]
+	prevHash := getproviders.NilHash
+	for _, hash := range hashes {
+		if hash != prevHash {
+			dedupeHashes = append(dedupeHashes, hash)
+			prevHash = hash
+		}
+	}
+
+	return &ModuleLock{
+		key:     key,
+		source:  source,
+		version: version,
+		hashes:  dedupeHashes,
+	}
+}
+
 // ProviderIsLockable returns true if the given provider is eligible for
 // version locking.
 //
@@ -273,6 +350,33 @@ func (l *Locks) Equal(other *Locks) bool {
 	// We don't need to worry about providers that are in "other" but not
 	// in the receiver, because we tested the lengths being equal above.
 
+	if len(l.modules) != len(other.modules) {
+		return false
+	}
+	for key, thisLock := range l.modules {
+		otherLock, ok := other.modules[key]
+		if !ok {
+			return false
+		}
+		if thisLock.source != otherLock.source {
+			return false
+		}
+		if thisLock.version != otherLock.version {
+			return false
+		}
+
+		// As with provider hashes, module hashes are normalized on
+		// initialization and so a simple item-by-item comparison suffices.
+		if len(thisLock.hashes) != len(otherLock.hashes) {
+			return false
+		}
+		for i := range thisLock.hashes {
+			if thisLock.hashes[i] != otherLock.hashes[i] {
+				return false
+			}
+		}
+	}
+
 	return true
 }
 
@@ -298,7 +402,7 @@ func (l *Locks) EqualProviderAddress(other *Locks) bool {
 // UI code might wish to use this to distinguish a lock file being
 // written for the first time from subsequent updates to that lock file.
 func (l *Locks) Empty() bool {
-	return len(l.providers) == 0
+	return len(l.providers) == 0 && len(l.modules) == 0
 }
 
 // DeepCopy creates a new Locks that represents the same information as the
@@ -319,6 +423,14 @@ func (l *Locks) DeepCopy() *Locks {
 		}
 		ret.SetProvider(addr, lock.version, lock.versionConstraints, hashes)
 	}
+	for key, lock := range l.modules {
+		var hashes []getproviders.Hash
+		if len(lock.hashes) > 0 {
+			hashes = make([]getproviders.Hash, len(lock.hashes))
+			copy(hashes, lock.hashes)
+		}
+		ret.SetModule(key, lock.source, lock.version, hashes)
+	}
 	return ret
 }
 
@@ -413,3 +525,56 @@ func (l *ProviderLock) AllHashes() []getproviders.Hash {
 func (l *ProviderLock) PreferredHashes() []getproviders.Hash {
 	return getproviders.PreferredHashes(l.hashes)
 }
+
+// ModuleLock represents lock information for a specific module call.
+//
+// Module locks apply only to modules installed from remote packages,
+// including registry modules; modules with local path sources are part of
+// the calling configuration and so have nothing to lock.
+type ModuleLock struct {
+	// key is the module instance path string used in the module manifest,
+	// which uniquely identifies the module call this lock applies to.
+	key string
+
+	// source is the source address given for this module in configuration,
+	// in the normalized form produced by addrs.ModuleSource.String. It is
+	// recorded so that a change of source address invalidates the lock.
+	source string
+
+	// version is the exact version that was previously selected for a
+	// registry module, as a normalized version string, or an empty string
+	// for modules that don't have versions.
+	version string
+
+	// hashes contains zero or more hashes of the content of the installed
+	// module directory. These use the same versioned hash schemes as
+	// provider package hashes; currently only the "h1:" directory hash
+	// scheme is generated for modules, since module packages are always
+	// verified as local directories.
+	hashes []getproviders.Hash
+}
+
+// Key returns the module manifest key of the module call this lock applies to.
+func (l *ModuleLock) Key() string {
+	return l.key
+}
+
+// Source returns the normalized source address the lock was recorded for.
+func (l *ModuleLock) Source() string {
+	return l.source
+}
+
+// Version returns the version string recorded for the corresponding module,
+// or an empty string for modules that don't have versions.
+func (l *ModuleLock) Version() string {
+	return l.version
+}
+
+// AllHashes returns all of the content hashes that were recorded when this
+// lock was created. If no hashes were recorded, the result is a zero-length
+// slice.
+//
+// Do not modify the backing array of the returned slice.
+func (l *ModuleLock) AllHashes() []getproviders.Hash {
+	return l.hashes
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/hashicorp/terraform/internal/getproviders"
 	"github.com/hashicorp/terraform/internal/replacefile"
 	"github.com/hashicorp/terraform/internal/tfdiags"
-	"github.com/hashicorp/terraform/version"
 )
 
 // LoadLocksFromFile reads locks from the given file, expecting it to be a
@@ -158,6 +157,27 @@ func SaveLocksToBytes(locks *Locks) ([]byte, tfdiags.Diagnostics) {
 		}
 	}
 
+	moduleKeys := make([]string, 0, len(locks.modules))
+	for key := range locks.modules {
+		moduleKeys = append(moduleKeys, key)
+	}
+	sort.Strings(moduleKeys)
+
+	for _, key := range moduleKeys {
+		lock := locks.modules[key]
+		rootBody.AppendNewline()
+		block := rootBody.AppendNewBlock("module", []string{lock.key})
+		body := block.Body()
+		body.SetAttributeValue("source", cty.StringVal(lock.source))
+		if lock.version != "" {
+			body.SetAttributeValue("version", cty.StringVal(lock.version))
+		}
+		if len(lock.hashes) != 0 {
+			hashToks := encodeHashSetTokens(lock.hashes)
+			body.SetAttributeRaw("hashes", hashToks)
+		}
+	}
+
 	return f.Bytes(), diags
 }
 
@@ -171,10 +191,6 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 				LabelNames: []string{"source_addr"},
 			},
 
-			// "module" is just a placeholder for future enhancement, so we
-			// can mostly-ignore the this block type we intend to add in
-			// future, but warn in case someone tries to use one e.g. if they
-			// downgraded to an earlier version of Terraform.
 			{
 				Type:       "module",
 				LabelNames: []string{"path"},
@@ -184,7 +200,7 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 	diags = diags.Append(hclDiags)
 
 	seenProviders := make(map[addrs.Provider]hcl.Range)
-	seenModule := false
+	seenModules := make(map[string]hcl.Range)
 	for _, block := range content.Blocks {
 
 		switch block.Type {
@@ -207,19 +223,22 @@ func decodeLocksFromHCL(locks *Locks, body hcl.Body) tfdiags.Diagnostics {
 			seenProviders[lock.addr] = block.DefRange
 
 		case "module":
-			// We'll just take the first module block to use for a single warning,
-			// because that's sufficient to get the point across without swamping
-			// the output with warning noise.
-			if !seenModule {
-				currentVersion := version.SemVer.String()
+			lock, moreDiags := decodeModuleLockFromHCL(block)
+			diags = diags.Append(moreDiags)
+			if lock == nil {
+				continue
+			}
+			if previousRng, exists := seenModules[lock.key]; exists {
 				diags = diags.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagWarning,
-					Summary:  "Dependency locks for modules are not yet supported",
-					Detail:   fmt.Sprintf("Terraform v%s only supports dependency locks for providers, not for modules. This configuration may be intended for a later version of Terraform that also supports dependency locks for modules.", currentVersion),
+					Severity: hcl.DiagError,
+					Summary:  "Duplicate module lock",
+					Detail:   fmt.Sprintf("This lockfile already declared a lock for module %q at %s.", lock.key, previousRng.String()),
 					Subject:  block.TypeRange.Ptr(),
 				})
-				seenModule = true
+				continue
 			}
+			locks.modules[lock.key] = lock
+			seenModules[lock.key] = block.DefRange
 
 		default:
 			// Shouldn't get here because this should be exhaustive for
@@ -315,6 +334,88 @@ func decodeProviderLockFromHCL(block *hcl.Block) (*ProviderLock, tfdiags.Diagnos
 	return ret, diags
 }
 
+func decodeModuleLockFromHCL(block *hcl.Block) (*ModuleLock, tfdiags.Diagnostics) {
+	ret := &ModuleLock{}
+	var diags tfdiags.Diagnostics
+
+	ret.key = block.Labels[0]
+
+	content, hclDiags := block.Body.Content(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "source", Required: true},
+			{Name: "version"},
+			{Name: "hashes"},
+		},
+	})
+	diags = diags.Append(hclDiags)
+
+	if attr, exists := content.Attributes["source"]; exists {
+		hclDiags := gohcl.DecodeExpression(attr.Expr, nil, &ret.source)
+		diags = diags.Append(hclDiags)
+	}
+	if attr, exists := content.Attributes["version"]; exists {
+		hclDiags := gohcl.DecodeExpression(attr.Expr, nil, &ret.version)
+		diags = diags.Append(hclDiags)
+	}
+
+	hashes, moreDiags := decodeModuleHashesArgument(ret.key, content.Attributes["hashes"])
+	ret.hashes = hashes
+	diags = diags.Append(moreDiags)
+
+	return ret, diags
+}
+
+func decodeModuleHashesArgument(key string, attr *hcl.Attribute) ([]getproviders.Hash, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if attr == nil {
+		// It's okay to omit this argument.
+		return nil, diags
+	}
+	expr := attr.Expr
+
+	// As with provider hashes, we decode this using HCL static analysis so
+	// that we can give precise feedback on individual invalid elements.
+	hashExprs, hclDiags := hcl.ExprList(expr)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+	if len(hashExprs) == 0 {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid module hash set",
+			Detail:   "The \"hashes\" argument must either be omitted or contain at least one hash value.",
+			Subject:  expr.Range().Ptr(),
+		})
+		return nil, diags
+	}
+
+	ret := make([]getproviders.Hash, 0, len(hashExprs))
+	for _, hashExpr := range hashExprs {
+		var raw string
+		hclDiags := gohcl.DecodeExpression(hashExpr, nil, &raw)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+
+		hash, err := getproviders.ParseHash(raw)
+		if err != nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module hash string",
+				Detail:   fmt.Sprintf("Cannot interpret %q as a module hash: %s.", raw, err),
+				Subject:  expr.Range().Ptr(),
+			})
+			continue
+		}
+
+		ret = append(ret, hash)
+	}
+
+	return ret, diags
+}
+
 func decodeProviderVersionArgument(provider addrs.Provider, attr *hcl.Attribute) (getproviders.Version, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 	if attr == nil {
@@ -103,6 +103,47 @@ func TestLoadLocksFromFile(t *testing.T) {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
 				}
 
+			case "valid-module-locks.hcl":
+				if got, want := len(locks.modules), 2; got != want {
+					t.Errorf("wrong number of modules %d; want %d", got, want)
+				}
+
+				t.Run("source-only", func(t *testing.T) {
+					if lock := locks.Module("source-only"); lock != nil {
+						if got, want := lock.Source(), "git::https://example.com/foo.git"; got != want {
+							t.Errorf("wrong source\ngot:  %s\nwant: %s", got, want)
+						}
+						if got, want := lock.Version(), ""; got != want {
+							t.Errorf("wrong version\ngot:  %s\nwant: %s", got, want)
+						}
+						if got, want := len(lock.hashes), 0; got != want {
+							t.Errorf("wrong number of hashes %d; want %d", got, want)
+						}
+					} else {
+						t.Errorf("no lock for module \"source-only\"")
+					}
+				})
+
+				t.Run("all-the-things", func(t *testing.T) {
+					if lock := locks.Module("all-the-things"); lock != nil {
+						if got, want := lock.Source(), "registry.terraform.io/test/foo/bar"; got != want {
+							t.Errorf("wrong source\ngot:  %s\nwant: %s", got, want)
+						}
+						if got, want := lock.Version(), "1.2.0"; got != want {
+							t.Errorf("wrong version\ngot:  %s\nwant: %s", got, want)
+						}
+						wantHashes := []getproviders.Hash{
+							getproviders.MustParseHash("test:placeholder-hash-1"),
+							getproviders.MustParseHash("test:placeholder-hash-2"),
+						}
+						if diff := cmp.Diff(wantHashes, lock.hashes); diff != "" {
+							t.Errorf("wrong hashes\n%s", diff)
+						}
+					} else {
+						t.Errorf("no lock for module \"all-the-things\"")
+					}
+				})
+
 			case "valid-provider-locks.hcl":
 				if got, want := len(locks.providers), 3; got != want {
 					t.Errorf("wrong number of providers %d; want %d", got, want)
@@ -219,6 +260,11 @@ func TestSaveLocksToFile(t *testing.T) {
 	locks.SetProvider(barProvider, oneDotTwo, pessimisticOneDotOh, nil)
 	locks.SetProvider(bazProvider, oneDotTwo, nil, nil)
 	locks.SetProvider(booProvider, oneDotTwo, abbreviatedOneDotTwo, nil)
+	moduleHashes := []getproviders.Hash{
+		getproviders.MustParseHash("test:dddddddddddddddddddddddddddddddddddddddddddddddd"),
+	}
+	locks.SetModule("consul", "registry.terraform.io/test/consul/aws", "0.11.0", moduleHashes)
+	locks.SetModule("consul.child", "git::https://example.com/child.git", "", nil)
 
 	dir := t.TempDir()
 
@@ -267,6 +313,18 @@ provider "registry.terraform.io/test/foo" {
     "test:cccccccccccccccccccccccccccccccccccccccccccccccc",
   ]
 }
+
+module "consul" {
+  source  = "registry.terraform.io/test/consul/aws"
+  version = "0.11.0"
+  hashes = [
+    "test:dddddddddddddddddddddddddddddddddddddddddddddddd",
+  ]
+}
+
+module "consul.child" {
+  source = "git::https://example.com/child.git"
+}
 `
 	if diff := cmp.Diff(wantContent, gotContent); diff != "" {
 		t.Errorf("wrong result\n%s", diff)
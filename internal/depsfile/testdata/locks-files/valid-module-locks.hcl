@@ -0,0 +1,12 @@
+module "source-only" {
+  source = "git::https://example.com/foo.git"
+}
+
+module "all-the-things" {
+  source  = "registry.terraform.io/test/foo/bar"
+  version = "1.2.0"
+  hashes = [
+    "test:placeholder-hash-1",
+    "test:placeholder-hash-2",
+  ]
+}
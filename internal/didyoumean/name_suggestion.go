@@ -1,6 +1,10 @@
 package didyoumean
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/agext/levenshtein"
 )
 
@@ -22,3 +26,60 @@ func NameSuggestion(given string, suggestions []string) string {
 	}
 	return ""
 }
+
+// NameSuggestions is like NameSuggestion except that it can return several
+// close matches, up to the given limit, for error messages that can offer
+// more than one candidate.
+//
+// The result is ordered by increasing edit distance, with ties broken by the
+// original order of the suggestions. If nothing is close enough to the given
+// name, returns an empty slice.
+//
+// As with NameSuggestion, this function is intended for a relatively-small
+// number of suggestions and is not optimized for hundreds or thousands of
+// them.
+func NameSuggestions(given string, suggestions []string, limit int) []string {
+	type scored struct {
+		name string
+		dist int
+	}
+	var close []scored
+	for _, suggestion := range suggestions {
+		dist := levenshtein.Distance(given, suggestion, nil)
+		if dist < 3 { // same threshold as NameSuggestion
+			close = append(close, scored{suggestion, dist})
+		}
+	}
+	sort.SliceStable(close, func(i, j int) bool {
+		return close[i].dist < close[j].dist
+	})
+	if len(close) > limit {
+		close = close[:limit]
+	}
+	ret := make([]string, 0, len(close))
+	for _, c := range close {
+		ret = append(ret, c.name)
+	}
+	return ret
+}
+
+// SuggestionSentence formats the given names as an English-language list of
+// quoted alternatives, for interpolation into a "Did you mean ...?" message.
+// Returns the empty string if no names are given.
+func SuggestionSentence(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return fmt.Sprintf("%q", names[0])
+	case 2:
+		return fmt.Sprintf("%q or %q", names[0], names[1])
+	default:
+		var buf strings.Builder
+		for _, name := range names[:len(names)-1] {
+			fmt.Fprintf(&buf, "%q, ", name)
+		}
+		fmt.Fprintf(&buf, "or %q", names[len(names)-1])
+		return buf.String()
+	}
+}
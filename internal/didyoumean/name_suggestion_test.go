@@ -1,6 +1,8 @@
 package didyoumean
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -51,3 +53,59 @@ func TestNameSuggestion(t *testing.T) {
 		})
 	}
 }
+
+func TestNameSuggestions(t *testing.T) {
+	var names = []string{"sir", "str", "set", "public_ip"}
+
+	tests := []struct {
+		Input string
+		Limit int
+		Want  []string
+	}{
+		// Results are ordered by increasing edit distance, with ties broken
+		// by the original order of the given names.
+		{"st", 3, []string{"str", "set", "sir"}},
+
+		// The limit caps the number of results after sorting.
+		{"st", 2, []string{"str", "set"}},
+		{"st", 1, []string{"str"}},
+
+		{"publicip", 3, []string{"public_ip"}},
+		{"bananas", 3, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%d", test.Input, test.Limit), func(t *testing.T) {
+			got := NameSuggestions(test.Input, names, test.Limit)
+			if len(got) == 0 && len(test.Want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, test.Want) {
+				t.Errorf(
+					"wrong result\ninput: %q\ngot:   %#v\nwant:  %#v",
+					test.Input, got, test.Want,
+				)
+			}
+		})
+	}
+}
+
+func TestSuggestionSentence(t *testing.T) {
+	tests := []struct {
+		Names []string
+		Want  string
+	}{
+		{nil, ``},
+		{[]string{"a"}, `"a"`},
+		{[]string{"a", "b"}, `"a" or "b"`},
+		{[]string{"a", "b", "c"}, `"a", "b", or "c"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Want, func(t *testing.T) {
+			if got := SuggestionSentence(test.Names); got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
@@ -2,12 +2,14 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"testing"
 
 	"github.com/hashicorp/terraform/internal/plans"
@@ -116,7 +118,13 @@ func (b *binary) AddEnv(entry string) {
 // The returned object can be mutated by the caller to customize how the
 // process will be run, before calling Run.
 func (b *binary) Cmd(args ...string) *exec.Cmd {
-	cmd := exec.Command(b.binPath, args...)
+	return b.CmdContext(context.Background(), args...)
+}
+
+// CmdContext is like Cmd except that the process will be killed if the
+// given context becomes done before the process exits on its own.
+func (b *binary) CmdContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, b.binPath, args...)
 	cmd.Dir = b.workDir
 	cmd.Env = os.Environ()
 
@@ -137,7 +145,36 @@ func (b *binary) Cmd(args ...string) *exec.Cmd {
 // that don't need any special environment variables. For more complex
 // situations, use Cmd and customize the command before running it.
 func (b *binary) Run(args ...string) (stdout, stderr string, err error) {
-	cmd := b.Cmd(args...)
+	return b.run(b.Cmd(args...))
+}
+
+// RunContext is like Run except that the child process will be killed if
+// the given context becomes done before it exits on its own, in which case
+// the returned error wraps the context's error so that callers can use
+// errors.Is to distinguish cancellation or timeout from ordinary failure.
+func (b *binary) RunContext(ctx context.Context, args ...string) (stdout, stderr string, err error) {
+	return b.RunContextWithEnv(ctx, nil, args...)
+}
+
+// RunWithEnv is like Run except that the given environment variables are
+// additionally set for this child process alone, overriding any variables
+// of the same name from either the test process environment or AddEnv.
+func (b *binary) RunWithEnv(env map[string]string, args ...string) (stdout, stderr string, err error) {
+	return b.RunContextWithEnv(context.Background(), env, args...)
+}
+
+// RunContextWithEnv combines the behaviors of RunContext and RunWithEnv.
+func (b *binary) RunContextWithEnv(ctx context.Context, env map[string]string, args ...string) (stdout, stderr string, err error) {
+	cmd := b.CmdContext(ctx, args...)
+	cmd.Env = append(cmd.Env, envEntries(env)...)
+	stdout, stderr, err = b.run(cmd)
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("process did not complete: %w", ctx.Err())
+	}
+	return
+}
+
+func (b *binary) run(cmd *exec.Cmd) (stdout, stderr string, err error) {
 	cmd.Stdin = nil
 	cmd.Stdout = &bytes.Buffer{}
 	cmd.Stderr = &bytes.Buffer{}
@@ -147,6 +184,17 @@ func (b *binary) Run(args ...string) (stdout, stderr string, err error) {
 	return
 }
 
+// envEntries converts a map of environment variable names to values into
+// the KEY=VALUE entries expected by exec.Cmd, in a deterministic order.
+func envEntries(env map[string]string) []string {
+	entries := make([]string, 0, len(env))
+	for k, v := range env {
+		entries = append(entries, k+"="+v)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
 // Path returns a file path within the temporary working directory by
 // appending the given arguments as path segments.
 func (b *binary) Path(parts ...string) string {
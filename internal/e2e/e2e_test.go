@@ -0,0 +1,98 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The following tests exercise the process-running helpers using common
+// Unix utilities in place of a real Terraform binary, since building one
+// here would be too expensive for a unit test. The e2etest packages cover
+// these helpers against real Terraform executables.
+
+func TestRunContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test relies on Unix command line utilities")
+	}
+
+	t.Run("completes before the deadline", func(t *testing.T) {
+		b := NewBinary(t, "sleep", t.TempDir())
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, _, err := b.RunContext(ctx, "0"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("killed at the deadline", func(t *testing.T) {
+		b := NewBinary(t, "sleep", t.TempDir())
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, _, err := b.RunContext(ctx, "60")
+		if err == nil {
+			t.Fatal("process succeeded; want timeout error")
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("wrong error %q; want context.DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 30*time.Second {
+			t.Fatalf("process took %s to fail; should've been killed at the deadline", elapsed)
+		}
+	})
+}
+
+func TestRunWithEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test relies on Unix command line utilities")
+	}
+
+	b := NewBinary(t, "sh", t.TempDir())
+	b.AddEnv("TF_E2E_TEST_VALUE=persistent")
+
+	// The per-run environment should override an AddEnv entry of the same
+	// name, but only for the one process.
+	stdout, _, err := b.RunWithEnv(
+		map[string]string{"TF_E2E_TEST_VALUE": "per-run"},
+		"-c", `echo "$TF_E2E_TEST_VALUE"`,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := strings.TrimSpace(stdout), "per-run"; got != want {
+		t.Errorf("wrong value %q with per-run environment; want %q", got, want)
+	}
+
+	stdout, _, err = b.Run("-c", `echo "$TF_E2E_TEST_VALUE"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := strings.TrimSpace(stdout), "persistent"; got != want {
+		t.Errorf("wrong value %q after per-run environment discarded; want %q", got, want)
+	}
+}
+
+func TestRunContextWithEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test relies on Unix command line utilities")
+	}
+
+	b := NewBinary(t, "sh", t.TempDir())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stdout, _, err := b.RunContextWithEnv(
+		ctx,
+		map[string]string{"TF_E2E_TEST_VALUE": "composed"},
+		"-c", `echo "$TF_E2E_TEST_VALUE"`,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := strings.TrimSpace(stdout), "composed"; got != want {
+		t.Errorf("wrong value %q; want %q", got, want)
+	}
+}
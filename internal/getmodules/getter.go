@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	getter "github.com/hashicorp/go-getter"
@@ -78,6 +80,7 @@ var goGetterGetters = map[string]getter.Getter{
 	"s3":    new(getter.S3Getter),
 	"http":  getterHTTPGetter,
 	"https": getterHTTPGetter,
+	"oci":   new(ociGetter),
 }
 
 var getterHTTPClient = cleanhttp.DefaultClient()
@@ -92,22 +95,51 @@ var getterHTTPGetter = &getter.HttpGetter{
 // asked to install, and will copy from a prior installation directory if
 // it has the same resolved source address.
 //
-// The keys in a reusingGetter are the normalized (post-detection) package
+// The keys in the installed map are the normalized (post-detection) package
 // addresses, and the values are the paths where each source was previously
 // installed. (Users of this map should treat the keys as addrs.ModulePackage
 // values, but we can't type them that way because the addrs package
 // imports getmodules in order to indirectly access our go-getter
-// configuration.)
-type reusingGetter map[string]string
+// configuration.) A package that was installed sparsely, containing only
+// one subdirectory of interest, is tracked under a synthetic key from
+// sparseInstallKey instead so that it can never satisfy a request for a
+// different part of the same package.
+type reusingGetter struct {
+	installed map[string]string
+
+	// sparse records which of the directories we've installed contain only
+	// a sparse checkout of their package, so that the installer can widen
+	// them later if a module turns out to refer to a sibling directory that
+	// the sparse checkout skipped.
+	sparse map[string]bool
+}
+
+func newReusingGetter() *reusingGetter {
+	return &reusingGetter{
+		installed: make(map[string]string),
+		sparse:    make(map[string]bool),
+	}
+}
+
+// sparseInstallKey returns the key under which a reusingGetter remembers a
+// sparse installation of just one subdirectory of the given package. The
+// result can never collide with a normalized package address because those
+// never contain a "//" subdirectory delimiter.
+func sparseInstallKey(packageAddr, subDir string) string {
+	return packageAddr + "//" + subDir
+}
 
 // getWithGoGetter fetches the package at the given address into the given
 // target directory. The given address must already be in normalized form
 // (using NormalizePackageAddress) or else the behavior is undefined.
 //
-// This function deals only in entire packages, so it's always the caller's
-// responsibility to handle any subdirectory specification and select a
-// suitable subdirectory of the given installation directory after installation
-// has succeeded.
+// It remains the caller's responsibility to handle any subdirectory
+// specification and select a suitable subdirectory of the given installation
+// directory after installation has succeeded. However, the caller may pass
+// a non-empty subDir as a hint of which subdirectory it intends to use, in
+// which case this function may fetch only the parts of the package needed
+// to populate that subdirectory, as long as it can do so while still placing
+// everything it does fetch at its normal location within the package.
 //
 // This function would ideally accept packageAddr as a value of type
 // addrs.ModulePackage, but we can't do that because the addrs package
@@ -120,44 +152,128 @@ type reusingGetter map[string]string
 // end-user-actionable error messages. At this time we do not have any
 // reasonable way to improve these error messages at this layer because
 // the underlying errors are not separately recognizable.
-func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAddr string) error {
+func (g *reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAddr, subDir string) error {
 	var err error
 
-	if prevDir, exists := g[packageAddr]; exists {
+	// We fetch into a staging directory next to the requested path and only
+	// move the result into place once the fetch has fully succeeded, so that
+	// an interrupted fetch can never leave a partial package where the module
+	// installer expects a complete one. The staging directory's name is
+	// InstallStagingPath of the final name, which "terraform init" knows to
+	// clean up if an earlier run was interrupted before the rename.
+	stagePath := InstallStagingPath(instPath)
+	if err := os.RemoveAll(stagePath); err != nil {
+		return fmt.Errorf("failed to remove stale staging directory %s: %s", stagePath, err)
+	}
+
+	// We'll remember the result under the full package address by default,
+	// but a sparse fetch below must instead use a key that records which
+	// subdirectory it was sparse for.
+	storeKey := packageAddr
+
+	// A previous full installation of the package can satisfy any request,
+	// but a previous sparse installation only a request for the same
+	// subdirectory.
+	prevDir, exists := g.installed[packageAddr]
+	if !exists && subDir != "" {
+		if dir, ok := g.installed[sparseInstallKey(packageAddr, subDir)]; ok {
+			prevDir, exists = dir, true
+			storeKey = sparseInstallKey(packageAddr, subDir)
+		}
+	}
+
+	if exists {
 		log.Printf("[TRACE] getmodules: copying previous install of %q from %s to %s", packageAddr, prevDir, instPath)
-		err := os.Mkdir(instPath, os.ModePerm)
+		err := os.Mkdir(stagePath, os.ModePerm)
 		if err != nil {
-			return fmt.Errorf("failed to create directory %s: %s", instPath, err)
+			return fmt.Errorf("failed to create directory %s: %s", stagePath, err)
 		}
-		err = copy.CopyDir(instPath, prevDir)
+		err = copy.CopyDir(stagePath, prevDir)
 		if err != nil {
-			return fmt.Errorf("failed to copy from %s to %s: %s", prevDir, instPath, err)
+			return fmt.Errorf("failed to copy from %s to %s: %s", prevDir, stagePath, err)
 		}
 	} else {
-		log.Printf("[TRACE] getmodules: fetching %q to %q", packageAddr, instPath)
-		client := getter.Client{
-			Src: packageAddr,
-			Dst: instPath,
-			Pwd: instPath,
-
-			Mode: getter.ClientModeDir,
-
-			Detectors:     goGetterNoDetectors, // our caller should've already done detection
-			Decompressors: goGetterDecompressors,
-			Getters:       goGetterGetters,
-			Ctx:           ctx,
+		fetched := false
+		if subDir != "" && sparseGitFetchEligible(packageAddr, subDir) {
+			log.Printf("[TRACE] getmodules: sparse git fetch of %q for subdirectory %q", packageAddr, subDir)
+			if err := sparseGitFetch(ctx, stagePath, packageAddr, subDir); err != nil {
+				log.Printf("[WARN] getmodules: sparse git fetch of %q failed, so will fall back on a full fetch: %s", packageAddr, err)
+				if err := os.RemoveAll(stagePath); err != nil {
+					return fmt.Errorf("failed to remove staging directory %s after failed sparse fetch: %s", stagePath, err)
+				}
+			} else {
+				fetched = true
+				storeKey = sparseInstallKey(packageAddr, subDir)
+			}
 		}
-		err = client.Get()
-		if err != nil {
-			return err
+		if !fetched {
+			log.Printf("[TRACE] getmodules: fetching %q to %q", packageAddr, instPath)
+			client := getter.Client{
+				Src: packageAddr,
+				Dst: stagePath,
+				Pwd: stagePath,
+
+				Mode: getter.ClientModeDir,
+
+				Detectors:     goGetterNoDetectors, // our caller should've already done detection
+				Decompressors: goGetterDecompressors,
+				Getters:       goGetterGetters,
+				Ctx:           ctx,
+			}
+			err = client.Get()
+			if err != nil {
+				os.RemoveAll(stagePath)
+				return err
+			}
 		}
-		// Remember where we installed this so we might reuse this directory
-		// on subsequent calls to avoid re-downloading.
-		g[packageAddr] = instPath
+	}
+
+	// The fetch succeeded, so we can now atomically move the result into
+	// its final location.
+	if err := os.RemoveAll(instPath); err != nil {
+		return fmt.Errorf("failed to remove existing directory %s: %s", instPath, err)
+	}
+	if err := os.Rename(stagePath, instPath); err != nil {
+		return fmt.Errorf("failed to move %s into place at %s: %s", stagePath, instPath, err)
+	}
+
+	// Remember where we installed this so we might reuse this directory
+	// on subsequent calls to avoid re-downloading.
+	g.installed[storeKey] = instPath
+	if storeKey != packageAddr {
+		g.sparse[instPath] = true
 	}
 
 	// If we get down here then we've either downloaded the package or
-	// copied a previous tree we downloaded, and so either way we should
-	// have got the full module package structure written into instPath.
+	// copied a previous tree we downloaded, and so either way everything
+	// the caller asked for should now be at its expected location under
+	// instPath.
 	return nil
 }
+
+// expandSparseDir attempts to make the given directory appear by widening
+// the sparse checkout of whichever previously-installed package contains it,
+// if any. It returns true only if the directory belongs to a sparse package
+// and the checkout was successfully widened to include it.
+func (g *reusingGetter) expandSparseDir(ctx context.Context, dir string) (bool, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	for instPath := range g.sparse {
+		absRoot, err := filepath.Abs(instPath)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(absRoot, absDir)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		log.Printf("[TRACE] getmodules: widening sparse checkout in %s to include %q", instPath, rel)
+		if err := runGitCommand(ctx, instPath, "sparse-checkout", "add", filepath.ToSlash(rel)); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
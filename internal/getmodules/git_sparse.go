@@ -0,0 +1,162 @@
+package getmodules
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// This file implements an optional optimization for fetching a git-hosted
+// module package when the caller only needs one subdirectory of it: a
+// partial ("blobless") clone combined with a sparse checkout of just that
+// subdirectory. For a module in a large monorepo this avoids downloading
+// and writing out most of the repository's content.
+//
+// This is only ever an optimization and never a requirement. If anything
+// about the source address, the local git tool, or the remote server
+// prevents a sparse fetch then the installer falls back to the full
+// go-getter fetch codepath that earlier Terraform versions always used.
+// A server that doesn't understand the partial clone filter is not even
+// an error, because git itself degrades to a full fetch in that case.
+
+// sparseCheckoutDisableEnv is an environment variable that, when set to any
+// non-empty value, disables sparse git fetching entirely, as an escape hatch
+// in case the optimization misbehaves with a particular git server.
+const sparseCheckoutDisableEnv = "TF_DISABLE_MODULE_SPARSE_CHECKOUT"
+
+// sparseGitFetchEligible decides whether we can plausibly attempt a sparse
+// git fetch for the given package address and subdirectory. Returning true
+// here doesn't guarantee that the fetch will succeed, only that it's worth
+// trying before falling back on a full fetch.
+func sparseGitFetchEligible(packageAddr, subDir string) bool {
+	if !strings.HasPrefix(packageAddr, "git::") {
+		return false
+	}
+	if subDir == "" || subDir == "." || subDir == ".." || strings.HasPrefix(subDir, "../") {
+		// A subdirectory that doesn't descend into the package can't
+		// select anything to be sparse about.
+		return false
+	}
+	if strings.ContainsAny(subDir, "*?[") {
+		// Subdirectory globs are expanded against the installed tree, so
+		// we need the whole package available to expand them.
+		return false
+	}
+	if os.Getenv(sparseCheckoutDisableEnv) != "" {
+		return false
+	}
+	_, query := splitAddrQuery(strings.TrimPrefix(packageAddr, "git::"))
+	if query.Get("sshkey") != "" {
+		// go-getter has its own special mechanism for passing an inline
+		// SSH key to git, which we don't replicate here.
+		return false
+	}
+	return haveSparseCapableGit()
+}
+
+// sparseGitFetch fetches just the given subdirectory of the git package at
+// the given address into stagePath, placing it at its normal location
+// relative to the package root. The caller is responsible for cleaning up
+// stagePath if this returns an error, and should then retry using the
+// normal full-fetch codepath.
+func sparseGitFetch(ctx context.Context, stagePath, packageAddr, subDir string) error {
+	repoURL, query := splitAddrQuery(strings.TrimPrefix(packageAddr, "git::"))
+
+	args := []string{"clone", "--no-checkout", "--filter=blob:none"}
+	if depth := query.Get("depth"); depth != "" {
+		if _, err := strconv.Atoi(depth); err != nil {
+			return fmt.Errorf("invalid depth %q in module source address", depth)
+		}
+		args = append(args, "--depth", depth)
+	}
+	if ref := query.Get("ref"); ref != "" {
+		// --branch accepts both branch and tag names. If the ref is instead
+		// a raw commit id then this clone fails and we fall back to the
+		// full fetch, which does know how to check out an arbitrary commit.
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, "--", repoURL, stagePath)
+	if err := runGitCommand(ctx, "", args...); err != nil {
+		return err
+	}
+	if err := runGitCommand(ctx, stagePath, "sparse-checkout", "set", path.Clean(subDir)); err != nil {
+		return err
+	}
+	if err := runGitCommand(ctx, stagePath, "checkout"); err != nil {
+		return err
+	}
+	// Any submodules that appear within the sparse checkout still need to
+	// be populated, matching what go-getter would've done for a full fetch.
+	if err := runGitCommand(ctx, stagePath, "submodule", "update", "--init", "--recursive"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// splitAddrQuery separates the query string portion of a package address
+// from the repository URL portion, tolerating the scp-style addresses
+// (user@host:path) that git accepts but net/url cannot fully parse.
+func splitAddrQuery(addr string) (string, url.Values) {
+	if idx := strings.Index(addr, "?"); idx >= 0 {
+		query, err := url.ParseQuery(addr[idx+1:])
+		if err != nil {
+			return addr[:idx], url.Values{}
+		}
+		return addr[:idx], query
+	}
+	return addr, url.Values{}
+}
+
+var sparseGitCheck struct {
+	once sync.Once
+	ok   bool
+}
+
+// haveSparseCapableGit reports whether there's a "git" executable available
+// that is new enough to support both partial clone and the sparse-checkout
+// subcommand. We require 2.27 because that's the first release where the
+// sparse-checkout command's cone mode became the default.
+func haveSparseCapableGit() bool {
+	sparseGitCheck.once.Do(func() {
+		out, err := exec.Command("git", "version").Output()
+		if err != nil {
+			return
+		}
+		m := regexp.MustCompile(`(\d+)\.(\d+)`).FindStringSubmatch(string(out))
+		if m == nil {
+			return
+		}
+		major, _ := strconv.Atoi(m[1])
+		minor, _ := strconv.Atoi(m[2])
+		sparseGitCheck.ok = major > 2 || (major == 2 && minor >= 27)
+	})
+	return sparseGitCheck.ok
+}
+
+// runGitCommand runs git with the given arguments in the given directory,
+// blocking until it exits and including any of its output in the error
+// message on failure. An empty dir runs git in the current directory, for
+// subcommands like clone that take an explicit target path.
+func runGitCommand(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	return nil
+}
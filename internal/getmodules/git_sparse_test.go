@@ -0,0 +1,142 @@
+package getmodules
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSparseGitFetchEligible(t *testing.T) {
+	tests := []struct {
+		packageAddr string
+		subDir      string
+		want        bool
+	}{
+		// Only git packages can be fetched sparsely.
+		{"https://example.com/foo.zip", "modules/a", false},
+		{"s3::https://example.com/foo", "modules/a", false},
+
+		// A subdirectory that doesn't descend into the package selects
+		// nothing to be sparse about.
+		{"git::https://example.com/foo.git", "", false},
+		{"git::https://example.com/foo.git", ".", false},
+		{"git::https://example.com/foo.git", "../other", false},
+
+		// Globs are expanded against the installed tree, so they need the
+		// whole package present.
+		{"git::https://example.com/foo.git", "modules/*", false},
+		{"git::https://example.com/foo.git", "modules/a?", false},
+
+		// go-getter's inline SSH key mechanism isn't replicated by the
+		// sparse codepath.
+		{"git::ssh://git@example.com/foo.git?sshkey=abc123", "modules/a", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.packageAddr+" "+test.subDir, func(t *testing.T) {
+			got := sparseGitFetchEligible(test.packageAddr, test.subDir)
+			if got != test.want {
+				t.Errorf("wrong result %v; want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSparseGitFetch(t *testing.T) {
+	repoDir := testGitRepo(t, map[string]string{
+		"main.tf":           `# root module`,
+		"modules/a/main.tf": `# module a`,
+		"modules/b/main.tf": `# module b`,
+	})
+	packageAddr := "git::file://" + filepath.ToSlash(repoDir)
+
+	if !sparseGitFetchEligible(packageAddr, "modules/a") {
+		t.Skip("no sparse-capable git executable available")
+	}
+
+	ctx := context.Background()
+	fetcher := NewPackageFetcher()
+
+	instPath := filepath.Join(t.TempDir(), "pkg")
+	if err := fetcher.FetchPackage(ctx, instPath, packageAddr, "modules/a"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(instPath, "modules", "a", "main.tf")); err != nil {
+		t.Fatalf("requested subdirectory wasn't installed: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(instPath, "modules", "b")); !os.IsNotExist(err) {
+		t.Errorf("sparse fetch unexpectedly installed modules/b")
+	}
+
+	// Widening the sparse checkout should make the sibling directory
+	// appear in place.
+	widened, err := fetcher.ExpandSparsePackage(ctx, filepath.Join(instPath, "modules", "b"))
+	if err != nil {
+		t.Fatalf("unexpected error widening checkout: %s", err)
+	}
+	if !widened {
+		t.Fatalf("ExpandSparsePackage didn't recognize the sparse package")
+	}
+	if _, err := os.Stat(filepath.Join(instPath, "modules", "b", "main.tf")); err != nil {
+		t.Errorf("widened checkout is missing modules/b: %s", err)
+	}
+
+	// A directory that isn't inside any sparsely-fetched package isn't
+	// eligible for widening at all.
+	widened, err = fetcher.ExpandSparsePackage(ctx, t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if widened {
+		t.Errorf("ExpandSparsePackage claimed to widen an unrelated directory")
+	}
+
+	// A request for the whole package must not reuse the sparse tree.
+	fullPath := filepath.Join(t.TempDir(), "pkg")
+	if err := fetcher.FetchPackage(ctx, fullPath, packageAddr, ""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(fullPath, "modules", "b", "main.tf")); err != nil {
+		t.Errorf("full fetch is missing modules/b: %s", err)
+	}
+}
+
+// testGitRepo constructs a local git repository containing the given files
+// so that tests can fetch from it with a file:// package address, skipping
+// the test if there's no git executable to construct it with.
+func testGitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("no git executable available")
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		fullName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullName), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullName, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %s\n%s", args[0], err, out)
+		}
+	}
+	run("init", ".")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Terraform Test")
+	run("add", "-A")
+	run("commit", "-m", "initial commit")
+
+	return dir
+}
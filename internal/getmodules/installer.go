@@ -2,14 +2,17 @@ package getmodules
 
 import (
 	"context"
+	"path/filepath"
 )
 
 // PackageFetcher is a low-level utility for fetching remote module packages
 // into local filesystem directories in preparation for use by higher-level
 // module installer functionality implemented elsewhere.
 //
-// A PackageFetcher works only with entire module packages and never with
-// the individual modules within a package.
+// A PackageFetcher works only with module packages and never with the
+// individual modules within a package, although a caller can optionally
+// hint at which subdirectory of a package it cares about so that the
+// fetcher can skip retrieving the rest when the source supports that.
 //
 // A particular PackageFetcher instance remembers the target directory of
 // any successfully-installed package so that it can optimize future calls
@@ -18,12 +21,12 @@ import (
 // no way to reset this cache, so a particular PackageFetcher instance should
 // live only for the duration of a single initialization process.
 type PackageFetcher struct {
-	getter reusingGetter
+	getter *reusingGetter
 }
 
 func NewPackageFetcher() *PackageFetcher {
 	return &PackageFetcher{
-		getter: reusingGetter{},
+		getter: newReusingGetter(),
 	}
 }
 
@@ -35,10 +38,45 @@ func NewPackageFetcher() *PackageFetcher {
 // because the getmodules package can't import the addrs package due to
 // that creating a package dependency cycle.
 //
-// PackageFetcher only works with entire packages. If the caller is processing
-// a module source address which includes a subdirectory portion then the
-// caller must resolve that itself, possibly with the help of the
-// getmodules.SplitPackageSubdir and getmodules.ExpandSubdirGlobs functions.
-func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string) error {
-	return f.getter.getWithGoGetter(ctx, instDir, packageAddr)
+// If the caller is processing a module source address which includes a
+// subdirectory portion then the caller must resolve that itself, possibly
+// with the help of the getmodules.SplitPackageSubdir and
+// getmodules.ExpandSubdirGlobs functions. However, the caller may pass the
+// subdirectory it intends to use as subDir, in which case the fetcher may
+// retrieve only the parts of the package needed to populate that
+// subdirectory where the source type supports that, such as through a
+// sparse checkout of a git repository. Anything that is fetched still
+// appears at its normal location relative to instDir, but other parts of
+// the package might be absent; ExpandSparsePackage can retrieve more of the
+// package later if needed. Callers that need the entire package must pass
+// an empty subDir.
+func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string, subDir string) error {
+	return f.getter.getWithGoGetter(ctx, instDir, packageAddr, subDir)
+}
+
+// ExpandSparsePackage attempts to make the given directory available within
+// a package that a previous FetchPackage call installed sparsely, because
+// the caller passed a non-empty subDir.
+//
+// The module installer calls this when a module inside a sparsely-fetched
+// package refers to another directory of the same package that the sparse
+// checkout skipped. The return value is true if dir now exists as part of
+// a widened checkout, and false if dir doesn't belong to any
+// sparsely-fetched package at all, in which case its absence has some other
+// cause that the caller must diagnose itself.
+func (f *PackageFetcher) ExpandSparsePackage(ctx context.Context, dir string) (bool, error) {
+	return f.getter.expandSparseDir(ctx, dir)
+}
+
+// InstallStagingPath returns the path of the temporary staging directory
+// that FetchPackage uses while fetching a package destined for the given
+// path, before moving the completed result into place.
+//
+// The staging name begins with a dot so that it can never collide with an
+// installation path, because those always derive from configuration
+// identifiers which cannot begin with a dot. An interrupted fetch can leave
+// a staging directory behind, so "terraform init" removes any it finds
+// before installing.
+func InstallStagingPath(instPath string) string {
+	return filepath.Join(filepath.Dir(instPath), "."+filepath.Base(instPath)+".partial")
 }
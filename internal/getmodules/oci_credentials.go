@@ -0,0 +1,138 @@
+package getmodules
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ociRegistryCredentials looks up credentials for the given registry
+// hostname in the Docker CLI configuration, so that a registry that
+// "docker pull" can authenticate to is also usable as a module source
+// without any Terraform-specific setup.
+//
+// This consults any credential helper the configuration nominates for the
+// registry before falling back on credentials stored directly in the
+// configuration file. If no credentials are available at all then it
+// returns empty strings with no error, because many registries allow
+// anonymous pulls.
+func ociRegistryCredentials(registryHost string) (username, password string, err error) {
+	config, err := readDockerCLIConfig()
+	if err != nil {
+		return "", "", err
+	}
+	if config == nil {
+		return "", "", nil // no Docker CLI configuration at all
+	}
+
+	helper := config.CredHelpers[registryHost]
+	if helper == "" {
+		helper = config.CredsStore
+	}
+	if helper != "" {
+		username, password, err := dockerCredentialHelperGet(helper, registryHost)
+		if err == nil {
+			return username, password, nil
+		}
+		// A helper that doesn't know the registry is not a hard error,
+		// because the configuration file itself might still have an entry.
+		log.Printf("[WARN] getmodules: docker credential helper %q failed for %s: %s", helper, registryHost, err)
+	}
+
+	for _, key := range []string{registryHost, "https://" + registryHost} {
+		auth, ok := config.Auths[key]
+		if !ok {
+			continue
+		}
+		if auth.Username != "" {
+			return auth.Username, auth.Password, nil
+		}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid auth entry for %s in Docker CLI configuration: %s", registryHost, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return "", "", fmt.Errorf("invalid auth entry for %s in Docker CLI configuration", registryHost)
+			}
+			return parts[0], parts[1], nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// dockerCLIConfig is the subset of the Docker CLI's config.json that
+// relates to registry credentials.
+type dockerCLIConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// readDockerCLIConfig reads the Docker CLI configuration file, honoring the
+// DOCKER_CONFIG environment variable the same way the Docker CLI does. A
+// missing file returns a nil config with no error.
+func readDockerCLIConfig() (*dockerCLIConfig, error) {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil // no home directory, so no Docker configuration
+		}
+		configDir = filepath.Join(homeDir, ".docker")
+	}
+
+	src, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker CLI configuration: %s", err)
+	}
+
+	var config dockerCLIConfig
+	if err := json.Unmarshal(src, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse Docker CLI configuration: %s", err)
+	}
+	return &config, nil
+}
+
+// dockerCredentialHelperGet runs the given Docker credential helper program
+// to obtain credentials for the given registry, using the simple
+// stdin/stdout protocol the helpers all implement.
+func dockerCredentialHelperGet(helper, registryHost string) (username, password string, err error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registryHost)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", "", fmt.Errorf("%s", message)
+	}
+
+	var result struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return "", "", fmt.Errorf("invalid response: %s", err)
+	}
+	return result.Username, result.Secret, nil
+}
@@ -0,0 +1,370 @@
+package getmodules
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	getter "github.com/hashicorp/go-getter"
+)
+
+// ociGetter is a go-getter "getter" implementation that fetches a module
+// package that was published to an OCI registry as an artifact, using the
+// standard OCI distribution protocol.
+//
+// The address form is oci://REGISTRY_HOST/REPOSITORY_NAME, optionally
+// followed by either :TAG or @DIGEST to select a specific artifact. With
+// neither, the "latest" tag is selected. The artifact must have exactly one
+// recognized archive layer, which is extracted as the module package.
+//
+// Authentication reuses the Docker CLI's configuration, including any
+// configured credential helpers, so a registry that "docker pull" can reach
+// is usable here without any extra setup.
+type ociGetter struct {
+	client *getter.Client
+}
+
+func (g *ociGetter) SetClient(c *getter.Client) {
+	g.client = c
+}
+
+// These are the layer media types we know how to extract a module package
+// from, in the order we prefer them if an artifact offers several.
+var ociLayerMediaTypes = map[string]getter.Decompressor{
+	"archive/zip":            new(getter.ZipDecompressor),
+	"archive/tar+gzip":       new(getter.TarGzipDecompressor),
+	"application/zip":        new(getter.ZipDecompressor),
+	"application/x-tar+gzip": new(getter.TarGzipDecompressor),
+}
+
+const (
+	ociMediaTypeImageManifest    = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeImageIndex       = "application/vnd.oci.image.index.v1+json"
+	dockerMediaTypeImageManifest = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// ociDescriptor is the subset of an OCI content descriptor that we need in
+// order to locate and verify manifests and layer blobs.
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// ociManifest is the subset of an OCI image manifest or image index that we
+// need. A manifest has layers; an index has manifests.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+func (g *ociGetter) ClientMode(u *url.URL) (getter.ClientMode, error) {
+	// OCI artifacts always produce a whole module package directory.
+	return getter.ClientModeDir, nil
+}
+
+func (g *ociGetter) Get(dst string, u *url.URL) error {
+	host := u.Host
+	if host == "" {
+		return fmt.Errorf("OCI source address must include a registry hostname")
+	}
+
+	name, reference, err := splitOCIReference(u.Path)
+	if err != nil {
+		return err
+	}
+
+	client := &ociRegistryClient{
+		host:       host,
+		httpClient: cleanhttp.DefaultClient(),
+	}
+
+	manifest, err := client.getManifest(name, reference)
+	if err != nil {
+		return err
+	}
+
+	// If we were given an image index then we'll use its first entry,
+	// because a module package is platform-agnostic and so a multi-entry
+	// index is just several copies of the same thing as far as we're
+	// concerned.
+	if len(manifest.Manifests) > 0 {
+		manifest, err = client.getManifest(name, manifest.Manifests[0].Digest)
+		if err != nil {
+			return err
+		}
+	}
+
+	var layer *ociDescriptor
+	var decomp getter.Decompressor
+	for i, candidate := range manifest.Layers {
+		if d, ok := ociLayerMediaTypes[candidate.MediaType]; ok {
+			layer = &manifest.Layers[i]
+			decomp = d
+			break
+		}
+	}
+	if layer == nil {
+		return fmt.Errorf("OCI artifact %s in %s has no layer with a supported archive media type", reference, name)
+	}
+
+	archiveFile, err := os.CreateTemp("", "terraform-oci-module")
+	if err != nil {
+		return err
+	}
+	archiveFilename := archiveFile.Name()
+	defer os.Remove(archiveFilename)
+
+	err = client.getBlob(name, layer, archiveFile)
+	archiveFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return decomp.Decompress(dst, archiveFilename, true, 0000)
+}
+
+func (g *ociGetter) GetFile(dst string, u *url.URL) error {
+	return fmt.Errorf("OCI sources can only be used for whole module packages, not single files")
+}
+
+// splitOCIReference separates the URL path of an oci:// address into the
+// repository name and the tag or digest reference that selects an artifact
+// within it.
+func splitOCIReference(urlPath string) (name, reference string, err error) {
+	name = strings.TrimPrefix(urlPath, "/")
+	reference = "latest"
+
+	if idx := strings.Index(name, "@"); idx >= 0 {
+		name, reference = name[:idx], name[idx+1:]
+	} else if idx := strings.LastIndex(path.Base(name), ":"); idx >= 0 {
+		cut := len(name) - len(path.Base(name)) + idx
+		name, reference = name[:cut], name[cut+1:]
+	}
+
+	if name == "" {
+		return "", "", fmt.Errorf("OCI source address must include a repository name after the registry hostname")
+	}
+	if reference == "" {
+		return "", "", fmt.Errorf("OCI source address has an empty tag or digest reference")
+	}
+	return name, reference, nil
+}
+
+// ociRegistryClient is a minimal client for the pull-related parts of the
+// OCI distribution protocol. We implement this ourselves rather than using
+// a full OCI client library because we need only two read-only endpoints,
+// and this way the set of remote operations module installation can perform
+// stays explicit.
+type ociRegistryClient struct {
+	host       string
+	httpClient *http.Client
+
+	// authHeader is the Authorization header value to use for subsequent
+	// requests, established on the first request that gets an authentication
+	// challenge from the registry.
+	authHeader string
+}
+
+func (c *ociRegistryClient) getManifest(name, reference string) (*ociManifest, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", c.host, name, reference)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join([]string{
+		ociMediaTypeImageManifest,
+		ociMediaTypeImageIndex,
+		dockerMediaTypeImageManifest,
+	}, ", "))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch manifest for %s from %s: %s", reference, c.host, resp.Status)
+	}
+
+	var manifest ociManifest
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4*1024*1024)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest for %s from %s: %s", reference, c.host, err)
+	}
+	return &manifest, nil
+}
+
+// getBlob downloads the blob described by the given descriptor into the
+// given file, verifying that its contents match the descriptor's digest
+// since manifests refer to blobs by content address.
+func (c *ociRegistryClient) getBlob(name string, desc *ociDescriptor, target *os.File) error {
+	digest := desc.Digest
+	if !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("unsupported digest scheme in %q: only sha256 digests are supported", digest)
+	}
+
+	url := fmt.Sprintf("https://%s/v2/%s/blobs/%s", c.host, name, digest)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch blob %s from %s: %s", digest, c.host, resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(target, hash), resp.Body); err != nil {
+		return fmt.Errorf("failed to download blob %s from %s: %s", digest, c.host, err)
+	}
+
+	if got := "sha256:" + hex.EncodeToString(hash.Sum(nil)); got != digest {
+		return fmt.Errorf("blob from %s does not match the digest %s recorded in the manifest", c.host, digest)
+	}
+	return nil
+}
+
+// do performs the given request, responding to an authentication challenge
+// from the registry by obtaining suitable credentials and then retrying.
+func (c *ociRegistryClient) do(req *http.Request) (*http.Response, error) {
+	if c.authHeader != "" {
+		req.Header.Set("Authorization", c.authHeader)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized || c.authHeader != "" {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authHeader, err := c.authenticate(challenge)
+	if err != nil {
+		return nil, err
+	}
+	c.authHeader = authHeader
+
+	retry := req.Clone(req.Context())
+	retry.Header.Set("Authorization", c.authHeader)
+	return c.httpClient.Do(retry)
+}
+
+// authenticate responds to the given WWW-Authenticate challenge, using any
+// credentials the Docker CLI configuration has for this registry.
+func (c *ociRegistryClient) authenticate(challenge string) (string, error) {
+	username, password, err := ociRegistryCredentials(c.host)
+	if err != nil {
+		return "", err
+	}
+
+	scheme, params := parseAuthChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if username == "" {
+			return "", fmt.Errorf("registry %s requires authentication but no credentials for it are available in the Docker CLI configuration", c.host)
+		}
+		creds := username + ":" + password
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(creds)), nil
+	case "bearer":
+		return c.fetchBearerToken(params, username, password)
+	default:
+		return "", fmt.Errorf("registry %s requested unsupported authentication scheme %q", c.host, scheme)
+	}
+}
+
+// fetchBearerToken obtains a bearer token from the token service indicated
+// by a bearer challenge's parameters, per the Docker registry token
+// authentication specification.
+func (c *ociRegistryClient) fetchBearerToken(params map[string]string, username, password string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry %s sent a bearer authentication challenge without a realm", c.host)
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("registry %s sent an invalid authentication realm %q: %s", c.host, realm, err)
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope := params["scope"]; scope != "" {
+		query.Set("scope", scope)
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to obtain an authentication token for %s: %s", c.host, resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse authentication token response for %s: %s", c.host, err)
+	}
+	token := body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("authentication token service for %s returned no token", c.host)
+	}
+	return "Bearer " + token, nil
+}
+
+// parseAuthChallenge does a simple parse of a WWW-Authenticate header value
+// into its scheme and parameters. The scheme is returned in lowercase.
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme := challenge
+	rest := ""
+	if idx := strings.IndexByte(challenge, ' '); idx >= 0 {
+		scheme, rest = challenge[:idx], challenge[idx+1:]
+	}
+
+	params := make(map[string]string)
+	for _, part := range strings.Split(rest, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+	return strings.ToLower(scheme), params
+}
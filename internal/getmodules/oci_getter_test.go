@@ -0,0 +1,64 @@
+package getmodules
+
+import (
+	"testing"
+)
+
+func TestSplitOCIReference(t *testing.T) {
+	tests := []struct {
+		path          string
+		wantName      string
+		wantReference string
+		wantErr       bool
+	}{
+		{"/modules/vpc:1.0.0", "modules/vpc", "1.0.0", false},
+		{"/modules/vpc", "modules/vpc", "latest", false},
+		{"/modules/vpc@sha256:abc123", "modules/vpc", "sha256:abc123", false},
+		{"/vpc:1.0.0", "vpc", "1.0.0", false},
+		{"/deeply/nested/name:v2", "deeply/nested/name", "v2", false},
+		{"/modules/vpc:", "", "", true},
+		{"/", "", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			name, reference, err := splitOCIReference(test.path)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("unexpected success; want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if name != test.wantName {
+				t.Errorf("wrong name %q; want %q", name, test.wantName)
+			}
+			if reference != test.wantReference {
+				t.Errorf("wrong reference %q; want %q", reference, test.wantReference)
+			}
+		})
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:modules/vpc:pull"`)
+	if scheme != "bearer" {
+		t.Errorf("wrong scheme %q; want %q", scheme, "bearer")
+	}
+	if got, want := params["realm"], "https://auth.example.com/token"; got != want {
+		t.Errorf("wrong realm %q; want %q", got, want)
+	}
+	if got, want := params["service"], "registry.example.com"; got != want {
+		t.Errorf("wrong service %q; want %q", got, want)
+	}
+	if got, want := params["scope"], "repository:modules/vpc:pull"; got != want {
+		t.Errorf("wrong scope %q; want %q", got, want)
+	}
+
+	scheme, _ = parseAuthChallenge(`Basic realm="Registry"`)
+	if scheme != "basic" {
+		t.Errorf("wrong scheme %q; want %q", scheme, "basic")
+	}
+}
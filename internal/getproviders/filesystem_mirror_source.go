@@ -82,7 +82,9 @@ func (s *FilesystemMirrorSource) PackageMeta(ctx context.Context, provider addrs
 	// available in the filesystem, if e.g. there's both a packed and an
 	// unpacked variant. For now we assume that the decision between them
 	// is arbitrary and just take the first one in the result.
-	return relevantPkgs[0], nil
+	meta := relevantPkgs[0]
+	meta.SourceKind = SourceKindFilesystemMirror
+	return meta, nil
 }
 
 // AllAvailablePackages scans the directory structure under the source's base
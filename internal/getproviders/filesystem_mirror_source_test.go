@@ -139,6 +139,7 @@ func TestFilesystemMirrorSourcePackageMeta(t *testing.T) {
 			TargetPlatform: Platform{"linux", "amd64"},
 			Filename:       "terraform-provider-null_2.0.0_linux_amd64.zip",
 			Location:       PackageLocalDir("testdata/filesystem-mirror/registry.terraform.io/hashicorp/null/2.0.0/linux_amd64"),
+			SourceKind:     SourceKindFilesystemMirror,
 		}
 
 		if diff := cmp.Diff(want, got); diff != "" {
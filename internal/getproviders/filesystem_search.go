@@ -47,6 +47,17 @@ func SearchLocalDirectory(baseDir string) (map[addrs.Provider]PackageMetaList, e
 			return fmt.Errorf("cannot search %s: %s", fullPath, err)
 		}
 
+		// Dot-prefixed entries are our own bookkeeping files, such as the
+		// staging directories and lock files that the installer uses while
+		// populating a cache directory, so we won't consider those as
+		// candidate packages at all.
+		if fullPath != baseDir && strings.HasPrefix(filepath.Base(fullPath), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// There are two valid directory structures that we support here...
 		// Unpacked: registry.terraform.io/hashicorp/aws/2.0.0/linux_amd64 (a directory)
 		// Packed:   registry.terraform.io/hashicorp/aws/terraform-provider-aws_2.0.0_linux_amd64.zip (a file)
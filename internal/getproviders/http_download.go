@@ -0,0 +1,165 @@
+package getproviders
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bgentry/go-netrc/netrc"
+	getter "github.com/hashicorp/go-getter"
+)
+
+// stalePartialDownloadAge is how old an abandoned partial download can be
+// before we discard it and start over, rather than attempting to resume it.
+// A partial file that old most likely belongs to a run that nobody intends
+// to retry, and the origin may well have re-released the file since.
+const stalePartialDownloadAge = 24 * time.Hour
+
+// DownloadPackageArchive downloads the package archive at the given URL to
+// the given path on the local filesystem, resuming an interrupted earlier
+// download of the same file when possible.
+//
+// A download in progress is written to the target path with a ".partial"
+// suffix and renamed into place only once it is complete, so an interrupted
+// run leaves the partial file behind. On a later attempt we issue an HTTP
+// Range request to continue from the existing offset, using If-Range with
+// the ETag recorded from the earlier response so that we can never splice
+// together content from two different releases of the file. Servers that
+// don't support ranges, or that report changed content, just cause a full
+// re-download. Partial files older than stalePartialDownloadAge are
+// discarded rather than resumed.
+//
+// Resuming doesn't change any verification requirements: callers must still
+// verify the checksum of the complete file before using it, as usual.
+func DownloadPackageArchive(ctx context.Context, client *http.Client, packageURL string, targetPath string) error {
+	partialPath := targetPath + ".partial"
+	etagPath := partialPath + ".etag"
+
+	// If an earlier download of this same file was interrupted then we'll
+	// try to continue where it left off, as long as we know which response
+	// the existing bytes came from.
+	var offset int64
+	var etag string
+	if info, err := os.Stat(partialPath); err == nil {
+		if time.Since(info.ModTime()) > stalePartialDownloadAge {
+			os.Remove(partialPath)
+			os.Remove(etagPath)
+		} else if raw, err := ioutil.ReadFile(etagPath); err == nil {
+			offset = info.Size()
+			etag = strings.TrimSpace(string(raw))
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", packageURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid provider download request: %s", err)
+	}
+	if err := addNetrcAuth(req); err != nil {
+		return err
+	}
+	if offset > 0 && etag != "" {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			// "context canceled" is not a user-friendly error message,
+			// so we'll return a more appropriate one here.
+			return fmt.Errorf("provider download was interrupted")
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	var f *os.File
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// The server agreed to resume our earlier download, so we'll
+		// append the remainder to the bytes we already have.
+		f, err = os.OpenFile(partialPath, os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return fmt.Errorf("failed to reopen partial download of %s: %s", packageURL, err)
+		}
+	case http.StatusOK:
+		// Either we weren't resuming or the server declined our range
+		// request, so we're downloading the whole file from the start.
+		if err := os.MkdirAll(filepath.Dir(partialPath), 0755); err != nil {
+			return err
+		}
+		f, err = os.Create(partialPath)
+		if err != nil {
+			return fmt.Errorf("failed to open file to download from %s: %s", packageURL, err)
+		}
+		// Record the response's validator so that a later run can resume
+		// this download if we get interrupted, but only if the server
+		// would accept a range request for it.
+		if etag := resp.Header.Get("Etag"); etag != "" && resp.Header.Get("Accept-Ranges") == "bytes" {
+			ioutil.WriteFile(etagPath, []byte(etag), 0644) // okay if it fails; we just can't resume later
+		} else {
+			os.Remove(etagPath)
+		}
+	default:
+		return fmt.Errorf("unsuccessful request to %s: %s", packageURL, resp.Status)
+	}
+
+	// We'll borrow go-getter's "cancelable copy" implementation here so that
+	// the download can potentially be interrupted partway through.
+	n, err := getter.Copy(ctx, f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && resp.ContentLength >= 0 && n < resp.ContentLength {
+		err = fmt.Errorf("incorrect response size: expected %d bytes, but got %d bytes", resp.ContentLength, n)
+	}
+	if err != nil {
+		// We intentionally leave the partial file and its recorded ETag in
+		// place here, so that a later run can pick up where we left off.
+		return err
+	}
+
+	os.Remove(etagPath) // okay if it fails; a future download will overwrite it
+	return os.Rename(partialPath, targetPath)
+}
+
+// addNetrcAuth adds HTTP basic authentication credentials from the user's
+// netrc file to the given request, if the file has an entry for the request's
+// host and the request doesn't already have credentials. This mimics the
+// behavior of the go-getter HTTP getter that this download path previously
+// used.
+func addNetrcAuth(req *http.Request) error {
+	if req.Header.Get("Authorization") != "" || req.URL.User != nil {
+		return nil
+	}
+
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	machine, err := netrc.FindMachine(path, req.URL.Hostname())
+	if err != nil {
+		return fmt.Errorf("error reading netrc file: %s", err)
+	}
+	if machine == nil || machine.IsDefault() {
+		return nil
+	}
+
+	req.SetBasicAuth(machine.Login, machine.Password)
+	return nil
+}
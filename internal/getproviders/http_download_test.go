@@ -0,0 +1,250 @@
+package getproviders
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rangeTestServer serves a fixed body with ETag and Range support, and can
+// be configured to drop the connection partway through a full response so
+// that we can exercise the resume codepath.
+type rangeTestServer struct {
+	body         []byte
+	etag         string
+	dropAfter    int  // if > 0, full responses end abruptly after this many bytes
+	ignoreRanges bool // if set, Range requests get a full 200 response
+
+	requests []*http.Request
+}
+
+func (s *rangeTestServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	s.requests = append(s.requests, req.Clone(context.Background()))
+
+	resp.Header().Set("Etag", s.etag)
+	resp.Header().Set("Accept-Ranges", "bytes")
+
+	if rangeHeader := req.Header.Get("Range"); rangeHeader != "" && !s.ignoreRanges {
+		if req.Header.Get("If-Range") != s.etag {
+			// The client's existing bytes are from a different release,
+			// so we must send the whole new file.
+			resp.Header().Set("Content-Length", fmt.Sprintf("%d", len(s.body)))
+			resp.WriteHeader(http.StatusOK)
+			resp.Write(s.body)
+			return
+		}
+		var offset int
+		fmt.Sscanf(rangeHeader, "bytes=%d-", &offset)
+		remain := s.body[offset:]
+		resp.Header().Set("Content-Length", fmt.Sprintf("%d", len(remain)))
+		resp.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(s.body)-1, len(s.body)))
+		resp.WriteHeader(http.StatusPartialContent)
+		resp.Write(remain)
+		return
+	}
+
+	resp.Header().Set("Content-Length", fmt.Sprintf("%d", len(s.body)))
+	resp.WriteHeader(http.StatusOK)
+	if s.dropAfter > 0 {
+		// Write only a prefix and then let the handler return, so that the
+		// client sees the connection close before the promised length.
+		resp.Write(s.body[:s.dropAfter])
+		if f, ok := resp.(http.Flusher); ok {
+			f.Flush()
+		}
+		if hj, ok := resp.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+			}
+		}
+		return
+	}
+	resp.Write(s.body)
+}
+
+func TestDownloadPackageArchiveResume(t *testing.T) {
+	body := bytes.Repeat([]byte("terraform provider bytes! "), 100)
+	handler := &rangeTestServer{
+		body:      body,
+		etag:      `"test-etag-1"`,
+		dropAfter: 700,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	targetPath := filepath.Join(t.TempDir(), "package.zip")
+	ctx := context.Background()
+
+	// The first attempt sees the connection drop mid-body and must fail,
+	// leaving a partial file behind for the next attempt.
+	err := DownloadPackageArchive(ctx, server.Client(), server.URL, targetPath)
+	if err == nil {
+		t.Fatal("first download succeeded; should have failed partway through")
+	}
+	if info, err := os.Stat(targetPath + ".partial"); err != nil {
+		t.Fatalf("no partial file after interrupted download: %s", err)
+	} else if info.Size() != 700 {
+		t.Fatalf("partial file has %d bytes; want 700", info.Size())
+	}
+
+	// The second attempt should resume from the existing offset.
+	handler.dropAfter = 0
+	err = DownloadPackageArchive(ctx, server.Client(), server.URL, targetPath)
+	if err != nil {
+		t.Fatalf("resumed download failed: %s", err)
+	}
+
+	if len(handler.requests) != 2 {
+		t.Fatalf("server saw %d requests; want 2", len(handler.requests))
+	}
+	resumeReq := handler.requests[1]
+	if got, want := resumeReq.Header.Get("Range"), "bytes=700-"; got != want {
+		t.Errorf("wrong Range header on resume %q; want %q", got, want)
+	}
+	if got, want := resumeReq.Header.Get("If-Range"), `"test-etag-1"`; got != want {
+		t.Errorf("wrong If-Range header on resume %q; want %q", got, want)
+	}
+
+	got, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("resumed file content doesn't match the served body")
+	}
+	if _, err := os.Stat(targetPath + ".partial"); !os.IsNotExist(err) {
+		t.Errorf("partial file still present after successful download")
+	}
+	if _, err := os.Stat(targetPath + ".partial.etag"); !os.IsNotExist(err) {
+		t.Errorf("etag file still present after successful download")
+	}
+}
+
+func TestDownloadPackageArchiveResumeContentChanged(t *testing.T) {
+	body := bytes.Repeat([]byte("terraform provider bytes! "), 100)
+	handler := &rangeTestServer{
+		body:      body,
+		etag:      `"test-etag-1"`,
+		dropAfter: 700,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	targetPath := filepath.Join(t.TempDir(), "package.zip")
+	ctx := context.Background()
+
+	err := DownloadPackageArchive(ctx, server.Client(), server.URL, targetPath)
+	if err == nil {
+		t.Fatal("first download succeeded; should have failed partway through")
+	}
+
+	// The file was re-released before the retry, so the server must decline
+	// our range request and we must not splice the two bodies together.
+	newBody := bytes.Repeat([]byte("completely different content! "), 80)
+	handler.body = newBody
+	handler.etag = `"test-etag-2"`
+	handler.dropAfter = 0
+
+	err = DownloadPackageArchive(ctx, server.Client(), server.URL, targetPath)
+	if err != nil {
+		t.Fatalf("second download failed: %s", err)
+	}
+
+	got, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, newBody) {
+		t.Errorf("file content doesn't match the re-released body")
+	}
+}
+
+func TestDownloadPackageArchiveNoRangeSupport(t *testing.T) {
+	body := bytes.Repeat([]byte("terraform provider bytes! "), 100)
+	handler := &rangeTestServer{
+		body:         body,
+		etag:         `"test-etag-1"`,
+		dropAfter:    700,
+		ignoreRanges: true,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	targetPath := filepath.Join(t.TempDir(), "package.zip")
+	ctx := context.Background()
+
+	err := DownloadPackageArchive(ctx, server.Client(), server.URL, targetPath)
+	if err == nil {
+		t.Fatal("first download succeeded; should have failed partway through")
+	}
+
+	// A server that ignores our range request sends a full 200 response,
+	// which must replace the partial file rather than append to it.
+	handler.dropAfter = 0
+	err = DownloadPackageArchive(ctx, server.Client(), server.URL, targetPath)
+	if err != nil {
+		t.Fatalf("second download failed: %s", err)
+	}
+
+	got, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("file content doesn't match the served body")
+	}
+}
+
+func TestDownloadPackageArchiveStalePartial(t *testing.T) {
+	body := bytes.Repeat([]byte("terraform provider bytes! "), 100)
+	handler := &rangeTestServer{
+		body: body,
+		etag: `"test-etag-1"`,
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	targetPath := filepath.Join(t.TempDir(), "package.zip")
+	partialPath := targetPath + ".partial"
+
+	// Simulate a partial download abandoned long ago; it must be discarded
+	// rather than resumed.
+	if err := ioutil.WriteFile(partialPath, []byte(strings.Repeat("x", 700)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(partialPath+".etag", []byte(`"test-etag-1"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * stalePartialDownloadAge)
+	if err := os.Chtimes(partialPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	err := DownloadPackageArchive(context.Background(), server.Client(), server.URL, targetPath)
+	if err != nil {
+		t.Fatalf("download failed: %s", err)
+	}
+
+	if len(handler.requests) != 1 {
+		t.Fatalf("server saw %d requests; want 1", len(handler.requests))
+	}
+	if rangeHeader := handler.requests[0].Header.Get("Range"); rangeHeader != "" {
+		t.Errorf("request for a stale partial file had Range header %q; want none", rangeHeader)
+	}
+
+	got, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("file content doesn't match the served body")
+	}
+}
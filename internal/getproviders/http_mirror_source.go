@@ -216,8 +216,9 @@ func (s *HTTPMirrorSource) PackageMeta(ctx context.Context, provider addrs.Provi
 		Version:        version,
 		TargetPlatform: target,
 
-		Location: PackageHTTPURL(absURL.String()),
-		Filename: path.Base(absURL.Path),
+		Location:   PackageHTTPURL(absURL.String()),
+		Filename:   path.Base(absURL.Path),
+		SourceKind: SourceKindNetworkMirror,
 	}
 	// A network mirror might not provide any hashes at all, in which case
 	// the package has no source-defined authentication whatsoever.
@@ -123,6 +123,7 @@ func TestHTTPMirrorSource(t *testing.T) {
 			TargetPlatform: tosPlatform,
 			Filename:       "terraform-provider-test_v1.0.0_tos_m68k.zip",
 			Location:       PackageHTTPURL(httpServer.URL + "/terraform.io/test/exists/terraform-provider-test_v1.0.0_tos_m68k.zip"),
+			SourceKind:     SourceKindNetworkMirror,
 			Authentication: packageHashAuthentication{
 				RequiredHashes: []Hash{"h1:placeholder-hash"},
 				AllHashes:      []Hash{"h1:placeholder-hash", "h0:unacceptable-hash"},
@@ -152,6 +153,7 @@ func TestHTTPMirrorSource(t *testing.T) {
 			TargetPlatform: tosPlatform,
 			Filename:       "terraform-provider-test_v1.0.1_tos_m68k.zip",
 			Location:       PackageHTTPURL(httpServer.URL + "/terraform.io/test/exists/terraform-provider-test_v1.0.1_tos_m68k.zip"),
+			SourceKind:     SourceKindNetworkMirror,
 			Authentication: nil,
 		}
 		if diff := cmp.Diff(want, got); diff != "" {
@@ -191,7 +193,8 @@ func TestHTTPMirrorSource(t *testing.T) {
 
 			// NOTE: The final URL is interpreted relative to the redirect
 			// target, not relative to what we originally requested.
-			Location: PackageHTTPURL(httpServer.URL + "/redirect-target/terraform-provider-test.zip"),
+			Location:   PackageHTTPURL(httpServer.URL + "/redirect-target/terraform-provider-test.zip"),
+			SourceKind: SourceKindNetworkMirror,
 		}
 		if diff := cmp.Diff(want, got); diff != "" {
 			t.Errorf("wrong result\n%s", diff)
@@ -54,6 +54,12 @@ func NewMemoizeSource(underlying Source) *MemoizeSource {
 	}
 }
 
+// Underlying returns the source that this MemoizeSource is wrapping, so
+// that callers can inspect what kind of source it will delegate to.
+func (s *MemoizeSource) Underlying() Source {
+	return s.underlying
+}
+
 // AvailableVersions requests the available versions from the underlying source
 // and caches them before returning them, or on subsequent calls returns the
 // result directly from the cache.
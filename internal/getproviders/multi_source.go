@@ -3,6 +3,7 @@ package getproviders
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 
 	svchost "github.com/hashicorp/terraform-svchost"
@@ -38,6 +39,7 @@ func (s MultiSource) AvailableVersions(ctx context.Context, provider addrs.Provi
 	// sources that have matching patterns that accept the given provider.
 	vs := make(map[Version]struct{})
 	var registryError bool
+	var fallbackErr error
 	var warnings []string
 	for _, selector := range s {
 		if !selector.CanHandleProvider(provider) {
@@ -53,6 +55,17 @@ func (s MultiSource) AvailableVersions(ctx context.Context, provider addrs.Provi
 		case ErrProviderNotFound:
 			continue // ignore, then
 		default:
+			if selector.FallbackOnError {
+				// This selector is configured to let later selectors in
+				// the sequence take over when it fails, rather than
+				// blocking installation altogether.
+				log.Printf("[WARN] failed to query %s for available versions of %s: %s; falling back to the next installation method", selector.Source.ForDisplay(provider), provider, err)
+				warnings = append(warnings, fmt.Sprintf("Failed to query %s: %s", selector.Source.ForDisplay(provider), err))
+				if fallbackErr == nil {
+					fallbackErr = err
+				}
+				continue
+			}
 			return nil, nil, err
 		}
 		for _, v := range thisSourceVersions {
@@ -64,6 +77,12 @@ func (s MultiSource) AvailableVersions(ctx context.Context, provider addrs.Provi
 	}
 
 	if len(vs) == 0 {
+		if fallbackErr != nil {
+			// A selector failed and we fell through to others that couldn't
+			// satisfy the request either, so the original failure is the
+			// most useful thing to report.
+			return nil, nil, fallbackErr
+		}
 		if registryError {
 			return nil, nil, ErrRegistryProviderNotKnown{provider}
 		} else {
@@ -86,6 +105,7 @@ func (s MultiSource) PackageMeta(ctx context.Context, provider addrs.Provider, v
 		return PackageMeta{}, ErrProviderNotFound{provider, s.sourcesForProvider(provider)}
 	}
 
+	var fallbackErr error
 	for _, selector := range s {
 		if !selector.CanHandleProvider(provider) {
 			continue // doesn't match the given patterns
@@ -97,10 +117,24 @@ func (s MultiSource) PackageMeta(ctx context.Context, provider addrs.Provider, v
 		case ErrProviderNotFound, ErrRegistryProviderNotKnown, ErrPlatformNotSupported:
 			continue // ignore, then
 		default:
+			if selector.FallbackOnError {
+				log.Printf("[WARN] failed to query %s for the %s %s package for %s: %s; falling back to the next installation method", selector.Source.ForDisplay(provider), provider, version, target, err)
+				if fallbackErr == nil {
+					fallbackErr = err
+				}
+				continue
+			}
 			return PackageMeta{}, err
 		}
 	}
 
+	if fallbackErr != nil {
+		// A selector failed and we fell through to others that couldn't
+		// satisfy the request either, so the original failure is the most
+		// useful thing to report.
+		return PackageMeta{}, fallbackErr
+	}
+
 	// If we fall out here then none of the sources have the requested
 	// package.
 	return PackageMeta{}, ErrPlatformNotSupported{
@@ -121,6 +155,12 @@ type MultiSourceSelector struct {
 	// together define which providers are eligible to be potentially
 	// installed from the corresponding Source.
 	Include, Exclude MultiSourceMatchingPatterns
+
+	// FallbackOnError, if set, causes errors from this selector's source to
+	// be treated the same as the requested provider not being available from
+	// it, so that a later selector in the sequence can satisfy the request
+	// instead. The error is still reported if no later selector can.
+	FallbackOnError bool
 }
 
 // MultiSourceMatchingPatterns is a set of patterns that together define a
@@ -2,6 +2,7 @@ package getproviders
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -547,3 +548,83 @@ func mustParseMultiSourceMatchingPatterns(strs ...string) MultiSourceMatchingPat
 	}
 	return ret
 }
+
+func TestMultiSourceFallbackOnError(t *testing.T) {
+	platform1 := Platform{OS: "amigaos", Arch: "m68k"}
+	provider := addrs.NewDefaultProvider("foo")
+	queryErr := ErrQueryFailed{
+		Provider: provider,
+		Wrapped:  errors.New("mirror is on fire"),
+	}
+	newBroken := func() *flakySource {
+		return &flakySource{
+			wrapped:  NewMockSource(nil, nil),
+			err:      queryErr,
+			failures: 100, // more than we'll ever call it, so it always fails
+		}
+	}
+	newWorking := func() Source {
+		return NewMockSource([]PackageMeta{
+			FakePackageMeta(
+				provider,
+				MustParseVersion("1.0.0"),
+				VersionList{MustParseVersion("5.0")},
+				platform1,
+			),
+		},
+			nil,
+		)
+	}
+
+	t.Run("fallback enabled", func(t *testing.T) {
+		multi := MultiSource{
+			{Source: newBroken(), FallbackOnError: true},
+			{Source: newWorking()},
+		}
+
+		got, warnings, err := multi.AvailableVersions(context.Background(), provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := VersionList{MustParseVersion("1.0.0")}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+		if len(warnings) != 1 {
+			t.Errorf("expected one warning about the failed source, got %#v", warnings)
+		}
+
+		meta, err := multi.PackageMeta(context.Background(), provider, MustParseVersion("1.0.0"), platform1)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if meta.Provider != provider {
+			t.Errorf("wrong provider %s in package meta", meta.Provider)
+		}
+	})
+
+	t.Run("fallback disabled", func(t *testing.T) {
+		multi := MultiSource{
+			{Source: newBroken()},
+			{Source: newWorking()},
+		}
+
+		_, _, err := multi.AvailableVersions(context.Background(), provider)
+		if want, ok := err.(ErrQueryFailed); !ok {
+			t.Fatalf("wrong error type:\ngot:  %T\nwant: %T", err, want)
+		}
+	})
+
+	t.Run("all sources fail", func(t *testing.T) {
+		multi := MultiSource{
+			{Source: newBroken(), FallbackOnError: true},
+		}
+
+		// When every selector fails we should report the underlying error
+		// rather than claiming the provider doesn't exist.
+		_, _, err := multi.AvailableVersions(context.Background(), provider)
+		if want, ok := err.(ErrQueryFailed); !ok {
+			t.Fatalf("wrong error type:\ngot:  %T\nwant: %T", err, want)
+		}
+	})
+}
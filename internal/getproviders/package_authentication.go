@@ -156,6 +156,23 @@ type PackageAuthenticationHashes interface {
 	AcceptableHashes() []Hash
 }
 
+// PackageAuthenticationSignedDocument is an optional interface implemented by
+// PackageAuthentication implementations that verify a detached signature over
+// a checksums document, allowing a caller to retrieve the document and
+// signature so that they can be archived alongside the package they describe.
+//
+// As with AcceptableHashes, the result should only be used after an earlier
+// call to AuthenticatePackage for the corresponding package succeeded, since
+// only that call actually checks that the signature is valid.
+type PackageAuthenticationSignedDocument interface {
+	PackageAuthentication
+
+	// SignedDocument returns the checksums document and the detached
+	// signature over it. Both results are nil if this authenticator has no
+	// signed document, in which case there is nothing to archive.
+	SignedDocument() (document, signature []byte)
+}
+
 type packageAuthenticationAll []PackageAuthentication
 
 // PackageAuthenticationAll combines several authentications together into a
@@ -207,6 +224,22 @@ func (checks packageAuthenticationAll) AcceptableHashes() []Hash {
 	return nil
 }
 
+func (checks packageAuthenticationAll) SignedDocument() (document, signature []byte) {
+	// As with AcceptableHashes, we visit the checks in reverse order and
+	// take the first one that has a signed document to offer.
+	for i := len(checks) - 1; i >= 0; i-- {
+		check, ok := checks[i].(PackageAuthenticationSignedDocument)
+		if !ok {
+			continue
+		}
+		document, signature := check.SignedDocument()
+		if len(document) > 0 && len(signature) > 0 {
+			return document, signature
+		}
+	}
+	return nil, nil
+}
+
 type packageHashAuthentication struct {
 	RequiredHashes []Hash
 	AllHashes      []Hash
@@ -493,6 +526,10 @@ func (s signatureAuthentication) AcceptableHashes() []Hash {
 	return ret
 }
 
+func (s signatureAuthentication) SignedDocument() (document, signature []byte) {
+	return s.Document, s.Signature
+}
+
 // findSigningKey attempts to verify the signature using each of the keys
 // returned by the registry. If a valid signature is found, it returns the
 // signing key.
@@ -156,6 +156,78 @@ func (c *registryClient) ProviderVersions(ctx context.Context, addr addrs.Provid
 	return ret, body.Warnings, nil
 }
 
+// ProviderPlatforms returns the target platforms for which the given
+// provider version has packages available, by consulting the same "versions"
+// endpoint that ProviderVersions uses and this time reading the platforms
+// portion of the response that ProviderVersions ignores.
+//
+// The error conditions are the same as for ProviderVersions, with the
+// addition of ErrQueryFailed if the response has no entry for the requested
+// version at all.
+func (c *registryClient) ProviderPlatforms(ctx context.Context, addr addrs.Provider, version Version) ([]Platform, error) {
+	endpointPath, err := url.Parse(path.Join(addr.Namespace, addr.Type, "versions"))
+	if err != nil {
+		// Should never happen because we're constructing this from
+		// already-validated components.
+		return nil, err
+	}
+	endpointURL := c.baseURL.ResolveReference(endpointPath)
+	req, err := retryablehttp.NewRequest("GET", endpointURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	c.addHeadersToRequest(req.Request)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, c.errQueryFailed(addr, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Great!
+	case http.StatusNotFound:
+		return nil, ErrRegistryProviderNotKnown{
+			Provider: addr,
+		}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, c.errUnauthorized(addr.Hostname)
+	default:
+		return nil, c.errQueryFailed(addr, errors.New(resp.Status))
+	}
+
+	type ResponseBody struct {
+		Versions []struct {
+			Version   string `json:"version"`
+			Platforms []struct {
+				OS   string `json:"os"`
+				Arch string `json:"arch"`
+			} `json:"platforms"`
+		} `json:"versions"`
+	}
+	var body ResponseBody
+
+	dec := json.NewDecoder(resp.Body)
+	if err := dec.Decode(&body); err != nil {
+		return nil, c.errQueryFailed(addr, err)
+	}
+
+	wantVersion := version.String()
+	for _, v := range body.Versions {
+		if v.Version != wantVersion {
+			continue
+		}
+		ret := make([]Platform, 0, len(v.Platforms))
+		for _, p := range v.Platforms {
+			ret = append(ret, Platform{OS: p.OS, Arch: p.Arch})
+		}
+		return ret, nil
+	}
+	return nil, c.errQueryFailed(addr, fmt.Errorf("the registry has no platform information for version %s", wantVersion))
+}
+
 // PackageMeta returns metadata about a distribution package for a provider.
 //
 // The returned error will be one of the following:
@@ -76,6 +76,19 @@ func (s *RegistrySource) AvailableVersions(ctx context.Context, provider addrs.P
 	return ret, warnings, nil
 }
 
+// AvailablePlatforms returns the target platforms for which the given
+// provider version has official packages available in its origin registry,
+// implementing the optional PlatformLister interface.
+//
+// The error conditions are the same as for AvailableVersions.
+func (s *RegistrySource) AvailablePlatforms(ctx context.Context, provider addrs.Provider, version Version) ([]Platform, error) {
+	client, err := s.registryClient(provider.Hostname)
+	if err != nil {
+		return nil, err
+	}
+	return client.ProviderPlatforms(ctx, provider, version)
+}
+
 // PackageMeta returns metadata about the location and capabilities of
 // a distribution package for a particular provider at a particular version
 // targeting a particular platform.
@@ -101,7 +114,12 @@ func (s *RegistrySource) PackageMeta(ctx context.Context, provider addrs.Provide
 		return PackageMeta{}, err
 	}
 
-	return client.PackageMeta(ctx, provider, version, target)
+	meta, err := client.PackageMeta(ctx, provider, version, target)
+	if err != nil {
+		return PackageMeta{}, err
+	}
+	meta.SourceKind = SourceKindRegistry
+	return meta, nil
 }
 
 func (s *RegistrySource) registryClient(hostname svchost.Hostname) (*registryClient, error) {
@@ -0,0 +1,107 @@
+package getproviders
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// RetrySource is a Source that wraps another Source and retries requests
+// that fail in ways that are likely to be transient, such as network
+// timeouts, waiting between attempts with exponential backoff.
+//
+// Requests that fail because the requested provider, version, or platform
+// simply isn't available from the underlying source are not retried, because
+// repeating those would always produce the same answer.
+//
+// When wrapping a source in both a RetrySource and a MemoizeSource, the
+// RetrySource must be the inner of the two, because MemoizeSource caches
+// errors and would otherwise just replay the original failure on each
+// retry.
+type RetrySource struct {
+	underlying Source
+	retries    int
+	baseWait   time.Duration
+}
+
+var _ Source = (*RetrySource)(nil)
+
+// NewRetrySource constructs and returns a new RetrySource that wraps the
+// given underlying source, making up to the given number of additional
+// attempts after a failed request, waiting baseWait before the first retry
+// and doubling the wait before each subsequent one.
+func NewRetrySource(underlying Source, retries int, baseWait time.Duration) *RetrySource {
+	return &RetrySource{
+		underlying: underlying,
+		retries:    retries,
+		baseWait:   baseWait,
+	}
+}
+
+// Underlying returns the source that this RetrySource is wrapping, so
+// that callers can inspect what kind of source it will delegate to.
+func (s *RetrySource) Underlying() Source {
+	return s.underlying
+}
+
+// AvailableVersions requests the available versions from the underlying
+// source, retrying if it fails with an error that might be transient.
+func (s *RetrySource) AvailableVersions(ctx context.Context, provider addrs.Provider) (VersionList, Warnings, error) {
+	for attempt := 0; ; attempt++ {
+		ret, warnings, err := s.underlying.AvailableVersions(ctx, provider)
+		if err == nil || attempt >= s.retries || !retryableSourceError(err) {
+			return ret, warnings, err
+		}
+		log.Printf("[WARN] failed to query %s for available versions of %s: %s; will retry", s.underlying.ForDisplay(provider), provider, err)
+		if err := s.wait(ctx, attempt); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// PackageMeta requests package metadata from the underlying source, retrying
+// if it fails with an error that might be transient.
+func (s *RetrySource) PackageMeta(ctx context.Context, provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	for attempt := 0; ; attempt++ {
+		ret, err := s.underlying.PackageMeta(ctx, provider, version, target)
+		if err == nil || attempt >= s.retries || !retryableSourceError(err) {
+			return ret, err
+		}
+		log.Printf("[WARN] failed to query %s for the %s %s package for %s: %s; will retry", s.underlying.ForDisplay(provider), provider, version, target, err)
+		if err := s.wait(ctx, attempt); err != nil {
+			return PackageMeta{}, err
+		}
+	}
+}
+
+func (s *RetrySource) ForDisplay(provider addrs.Provider) string {
+	return s.underlying.ForDisplay(provider)
+}
+
+// wait sleeps for the backoff interval appropriate to the given zero-based
+// attempt number, unless the given context is cancelled first.
+func (s *RetrySource) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(s.baseWait << uint(attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ErrRequestCanceled{}
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryableSourceError distinguishes errors that describe a definitive
+// answer from the source, which retrying could never change, from errors
+// that might've been caused by a transient problem such as a network
+// outage.
+func retryableSourceError(err error) bool {
+	switch err.(type) {
+	case ErrProviderNotFound, ErrRegistryProviderNotKnown, ErrPlatformNotSupported, ErrHostNoProviders, ErrUnauthorized, ErrRequestCanceled:
+		return false
+	default:
+		return true
+	}
+}
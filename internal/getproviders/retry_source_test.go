@@ -0,0 +1,126 @@
+package getproviders
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform/internal/addrs"
+)
+
+// flakySource is a Source for testing that fails with the given error for a
+// set number of calls before delegating to a wrapped source.
+type flakySource struct {
+	wrapped  Source
+	err      error
+	failures int
+	calls    int
+}
+
+var _ Source = (*flakySource)(nil)
+
+func (s *flakySource) AvailableVersions(ctx context.Context, provider addrs.Provider) (VersionList, Warnings, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return nil, nil, s.err
+	}
+	return s.wrapped.AvailableVersions(ctx, provider)
+}
+
+func (s *flakySource) PackageMeta(ctx context.Context, provider addrs.Provider, version Version, target Platform) (PackageMeta, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return PackageMeta{}, s.err
+	}
+	return s.wrapped.PackageMeta(ctx, provider, version, target)
+}
+
+func (s *flakySource) ForDisplay(provider addrs.Provider) string {
+	return s.wrapped.ForDisplay(provider)
+}
+
+func TestRetrySource(t *testing.T) {
+	provider := addrs.NewDefaultProvider("foo")
+	platform := Platform{OS: "gardenos", Arch: "mulch"}
+	queryErr := ErrQueryFailed{
+		Provider: provider,
+		Wrapped:  errors.New("splines unreticulated"),
+	}
+	newWorking := func() Source {
+		return NewMockSource([]PackageMeta{
+			FakePackageMeta(
+				provider,
+				MustParseVersion("1.0.0"),
+				VersionList{MustParseVersion("5.0")},
+				platform,
+			),
+		},
+			nil,
+		)
+	}
+
+	t.Run("success after transient failures", func(t *testing.T) {
+		flaky := &flakySource{wrapped: newWorking(), err: queryErr, failures: 2}
+		source := NewRetrySource(flaky, 2, 1*time.Millisecond)
+
+		got, _, err := source.AvailableVersions(context.Background(), provider)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := VersionList{MustParseVersion("1.0.0")}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+		if flaky.calls != 3 {
+			t.Errorf("wrong number of calls %d; want 3", flaky.calls)
+		}
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		flaky := &flakySource{wrapped: newWorking(), err: queryErr, failures: 5}
+		source := NewRetrySource(flaky, 1, 1*time.Millisecond)
+
+		_, _, err := source.AvailableVersions(context.Background(), provider)
+		if want, ok := err.(ErrQueryFailed); !ok {
+			t.Fatalf("wrong error type:\ngot:  %T\nwant: %T", err, want)
+		}
+		if flaky.calls != 2 {
+			t.Errorf("wrong number of calls %d; want 2", flaky.calls)
+		}
+	})
+
+	t.Run("definitive errors are not retried", func(t *testing.T) {
+		flaky := &flakySource{
+			wrapped:  newWorking(),
+			err:      ErrRegistryProviderNotKnown{provider},
+			failures: 5,
+		}
+		source := NewRetrySource(flaky, 2, 1*time.Millisecond)
+
+		_, _, err := source.AvailableVersions(context.Background(), provider)
+		if want, ok := err.(ErrRegistryProviderNotKnown); !ok {
+			t.Fatalf("wrong error type:\ngot:  %T\nwant: %T", err, want)
+		}
+		if flaky.calls != 1 {
+			t.Errorf("wrong number of calls %d; want 1", flaky.calls)
+		}
+	})
+
+	t.Run("package meta", func(t *testing.T) {
+		flaky := &flakySource{wrapped: newWorking(), err: queryErr, failures: 1}
+		source := NewRetrySource(flaky, 1, 1*time.Millisecond)
+
+		meta, err := source.PackageMeta(context.Background(), provider, MustParseVersion("1.0.0"), platform)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if meta.Provider != provider {
+			t.Errorf("wrong provider %s in package meta", meta.Provider)
+		}
+		if flaky.calls != 2 {
+			t.Errorf("wrong number of calls %d; want 2", flaky.calls)
+		}
+	})
+}
@@ -13,3 +13,15 @@ type Source interface {
 	PackageMeta(ctx context.Context, provider addrs.Provider, version Version, target Platform) (PackageMeta, error)
 	ForDisplay(provider addrs.Provider) string
 }
+
+// PlatformLister is an optional interface implemented by sources that can
+// report which target platforms a particular provider version has packages
+// available for, without needing to fetch any of those packages.
+//
+// Callers should pass a version previously returned by AvailableVersions
+// from the same source. Sources that cannot enumerate platforms simply
+// don't implement this interface, and callers must check for it with a
+// type assertion before use.
+type PlatformLister interface {
+	AvailablePlatforms(ctx context.Context, provider addrs.Provider, version Version) ([]Platform, error)
+}
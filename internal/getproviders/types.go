@@ -165,6 +165,21 @@ var CurrentPlatform = Platform{
 	Arch: runtime.GOARCH,
 }
 
+// SourceKind is an enumeration of the kinds of source that can produce
+// provider package metadata, distinguishing in particular a provider's
+// origin registry from the mirror types, since a package retrieved over
+// HTTP could have come from either.
+//
+// The underlying values are stable identifiers suitable for inclusion in
+// machine-readable output.
+type SourceKind string
+
+const (
+	SourceKindRegistry         SourceKind = "registry"
+	SourceKindNetworkMirror    SourceKind = "network_mirror"
+	SourceKindFilesystemMirror SourceKind = "filesystem_mirror"
+)
+
 // PackageMeta represents the metadata related to a particular downloadable
 // provider package targeting a single platform.
 //
@@ -183,6 +198,13 @@ type PackageMeta struct {
 	Filename string
 	Location PackageLocation
 
+	// SourceKind describes what kind of source produced this metadata,
+	// so that the UI can report how a package is being installed — in
+	// particular, whether it came from its origin registry or from a
+	// mirror. It is informational only and not part of the package's
+	// identity; a zero value means the source didn't say.
+	SourceKind SourceKind
+
 	// Authentication, if non-nil, is a request from the source that produced
 	// this meta for verification of the target package after it has been
 	// retrieved from the indicated Location.
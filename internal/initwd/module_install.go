@@ -26,6 +26,21 @@ type ModuleInstaller struct {
 	modsDir string
 	reg     *registry.Client
 
+	// mirrorDir is an optional local directory to resolve registry module
+	// sources from instead of the origin registries, set via
+	// UseModuleMirror.
+	mirrorDir string
+
+	// allowYanked makes the installer willing to freshly select registry
+	// module versions that the registry has yanked, set via
+	// AllowYankedModules.
+	allowYanked bool
+
+	// offline forbids any installation step that would require network
+	// access, so modules must come from local paths, a local module mirror,
+	// or what is already installed. Set via Offline.
+	offline bool
+
 	// The keys in moduleVersions are resolved and trimmed registry source
 	// addresses and the values are the registry response.
 	registryPackageVersions map[addrs.ModuleRegistryPackage]*response.ModuleVersions
@@ -49,6 +64,22 @@ func NewModuleInstaller(modsDir string, reg *registry.Client) *ModuleInstaller {
 	}
 }
 
+// AllowYankedModules configures the installer to treat registry module
+// versions that the registry has yanked as still selectable, rather than
+// refusing them for fresh installation. This is an explicit escape hatch for
+// when a yanked version is the only one that can satisfy a configuration.
+func (i *ModuleInstaller) AllowYankedModules() {
+	i.allowYanked = true
+}
+
+// Offline configures the installer to refuse any installation step that
+// would require network access. Modules must then come from local paths,
+// from a local module mirror, or from packages already present in the
+// modules directory.
+func (i *ModuleInstaller) Offline() {
+	i.offline = true
+}
+
 // InstallModules analyses the root module in the given directory and installs
 // all of its direct and transitive dependencies into the given modules
 // directory, which must already exist.
@@ -204,7 +235,7 @@ func (i *ModuleInstaller) installDescendentModules(ctx context.Context, rootMod
 
 			case addrs.ModuleSourceLocal:
 				log.Printf("[TRACE] ModuleInstaller: %s has local path %q", key, addr.String())
-				mod, mDiags := i.installLocalModule(req, key, manifest, hooks)
+				mod, mDiags := i.installLocalModule(ctx, req, key, manifest, hooks, fetcher)
 				mDiags = maybeImproveLocalInstallError(req, mDiags)
 				diags = append(diags, mDiags...)
 				return mod, nil, diags
@@ -243,7 +274,7 @@ func (i *ModuleInstaller) installDescendentModules(ctx context.Context, rootMod
 	return cfg, diags
 }
 
-func (i *ModuleInstaller) installLocalModule(req *earlyconfig.ModuleRequest, key string, manifest modsdir.Manifest, hooks ModuleInstallHooks) (*tfconfig.Module, tfdiags.Diagnostics) {
+func (i *ModuleInstaller) installLocalModule(ctx context.Context, req *earlyconfig.ModuleRequest, key string, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*tfconfig.Module, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	parentKey := manifest.ModuleKey(req.Parent.Path)
@@ -266,6 +297,20 @@ func (i *ModuleInstaller) installLocalModule(req *earlyconfig.ModuleRequest, key
 	// the files we need, and so we just load up what's already here.
 	newDir := filepath.Join(parentRecord.Dir, req.SourceAddr.String())
 
+	// If the parent module belongs to a package that was fetched sparsely
+	// then this directory might be missing only because the sparse checkout
+	// skipped it, in which case we can ask the fetcher to widen the
+	// checkout to include it before we try to load it.
+	if _, err := os.Stat(newDir); os.IsNotExist(err) && fetcher != nil {
+		widened, wErr := fetcher.ExpandSparsePackage(ctx, newDir)
+		switch {
+		case wErr != nil:
+			log.Printf("[WARN] ModuleInstaller: failed to widen sparse package checkout for %s: %s", newDir, wErr)
+		case widened:
+			log.Printf("[TRACE] ModuleInstaller: widened sparse package checkout to include %s", newDir)
+		}
+	}
+
 	log.Printf("[TRACE] ModuleInstaller: %s uses directory from parent: %s", key, newDir)
 	// it is possible that the local directory is a symlink
 	newDir, err := filepath.EvalSymlinks(newDir)
@@ -304,8 +349,24 @@ func (i *ModuleInstaller) installLocalModule(req *earlyconfig.ModuleRequest, key
 }
 
 func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *earlyconfig.ModuleRequest, key string, instPath string, addr addrs.ModuleSourceRegistry, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*tfconfig.Module, *version.Version, tfdiags.Diagnostics) {
+	if i.mirrorDir != "" {
+		return i.installRegistryModuleFromMirror(req, key, instPath, addr, manifest, hooks)
+	}
+
 	var diags tfdiags.Diagnostics
 
+	if i.offline {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module installation requires network access",
+			fmt.Sprintf(
+				"Module %q (from %s:%d) must be installed from the module registry at %s, which is not possible in offline mode and no local module mirror is configured.\n\nEither configure a local module mirror containing this module or install it before initializing with -offline.",
+				req.Name, req.CallPos.Filename, req.CallPos.Line, addr.PackageAddr.Host,
+			),
+		))
+		return nil, nil, diags
+	}
+
 	hostname := addr.PackageAddr.Host
 	reg := i.reg
 	var resp *response.ModuleVersions
@@ -369,55 +430,9 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *earlyc
 
 	modMeta := resp.Modules[0]
 
-	var latestMatch *version.Version
-	var latestVersion *version.Version
-	for _, mv := range modMeta.Versions {
-		v, err := version.NewVersion(mv.Version)
-		if err != nil {
-			// Should never happen if the registry server is compliant with
-			// the protocol, but we'll warn if not to assist someone who
-			// might be developing a module registry server.
-			diags = diags.Append(tfdiags.Sourceless(
-				tfdiags.Warning,
-				"Invalid response from remote module registry",
-				fmt.Sprintf("The registry at %s returned an invalid version string %q for module %q (%s:%d), which Terraform ignored.", hostname, mv.Version, req.Name, req.CallPos.Filename, req.CallPos.Line),
-			))
-			continue
-		}
-
-		// If we've found a pre-release version then we'll ignore it unless
-		// it was exactly requested.
-		if v.Prerelease() != "" && req.VersionConstraints.String() != v.String() {
-			log.Printf("[TRACE] ModuleInstaller: %s ignoring %s because it is a pre-release and was not requested exactly", key, v)
-			continue
-		}
-
-		if latestVersion == nil || v.GreaterThan(latestVersion) {
-			latestVersion = v
-		}
-
-		if req.VersionConstraints.Check(v) {
-			if latestMatch == nil || v.GreaterThan(latestMatch) {
-				latestMatch = v
-			}
-		}
-	}
-
-	if latestVersion == nil {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Module has no versions",
-			fmt.Sprintf("Module %q (%s:%d) has no versions available on %s.", addr, req.CallPos.Filename, req.CallPos.Line, hostname),
-		))
-		return nil, nil, diags
-	}
-
-	if latestMatch == nil {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Unresolvable module version constraint",
-			fmt.Sprintf("There is no available version of module %q (%s:%d) which matches the given version constraint. The newest available version is %s.", addr, req.CallPos.Filename, req.CallPos.Line, latestVersion),
-		))
+	latestMatch, latestMatchMeta, selDiags := i.selectRegistryModuleVersion(req, key, addr, modMeta, manifest)
+	diags = append(diags, selDiags...)
+	if selDiags.HasErrors() {
 		return nil, nil, diags
 	}
 
@@ -471,7 +486,13 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *earlyc
 
 	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.PackageAddr)
 
-	err := fetcher.FetchPackage(ctx, instPath, dlAddr.PackageAddr.String())
+	// Incorporate any subdir information from the original path into the
+	// address returned by the registry, both to find the final directory
+	// of the target module below and to let the fetcher skip the rest of
+	// the package if the source supports fetching just that subdirectory.
+	finalAddr := dlAddr.FromRegistry(addr)
+
+	err := fetcher.FetchPackage(ctx, instPath, dlAddr.PackageAddr.String(), finalAddr.Subdir)
 	if errors.Is(err, context.Canceled) {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -496,10 +517,6 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *earlyc
 
 	log.Printf("[TRACE] ModuleInstaller: %s %q was downloaded to %s", key, dlAddr.PackageAddr, instPath)
 
-	// Incorporate any subdir information from the original path into the
-	// address returned by the registry in order to find the final directory
-	// of the target module.
-	finalAddr := dlAddr.FromRegistry(addr)
 	subDir := filepath.FromSlash(finalAddr.Subdir)
 	modDir := filepath.Join(instPath, subDir)
 
@@ -522,25 +539,173 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *earlyc
 		diags = append(diags, mDiags...)
 	}
 
-	// Note the local location in our manifest.
-	manifest[key] = modsdir.Record{
+	// Note the local location in our manifest, along with any deprecation
+	// message so that later commands can warn about it without contacting
+	// the registry again.
+	record := modsdir.Record{
 		Key:        key,
 		Version:    latestMatch,
 		Dir:        modDir,
 		SourceAddr: req.SourceAddr.String(),
 	}
+	if latestMatchMeta != nil && latestMatchMeta.IsDeprecated() {
+		record.Deprecation = latestMatchMeta.DeprecationText()
+		if record.Deprecation == "" {
+			record.Deprecation = "marked as deprecated by the registry"
+		}
+	}
+	manifest[key] = record
 	log.Printf("[DEBUG] Module installer: %s installed at %s", key, modDir)
 	hooks.Install(key, latestMatch, modDir)
 
 	return mod, latestMatch, diags
 }
 
+// selectRegistryModuleVersion chooses the newest version from the given
+// registry response that satisfies the version constraints in req, following
+// the usual selection rules: pre-release versions are ignored unless
+// requested exactly, and yanked versions are refused for fresh selection
+// unless the same version is already recorded in the manifest or the
+// installer was configured to allow them.
+//
+// Alongside the selected version it returns the registry's metadata for that
+// version, and emits warnings if the selection is deprecated or yanked.
+func (i *ModuleInstaller) selectRegistryModuleVersion(req *earlyconfig.ModuleRequest, key string, addr addrs.ModuleSourceRegistry, modMeta *response.ModuleProviderVersions, manifest modsdir.Manifest) (*version.Version, *response.ModuleVersion, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	hostname := addr.PackageAddr.Host
+
+	// If this module is already installed then its recorded version remains
+	// usable even if the registry has since yanked it.
+	var prevVersion *version.Version
+	if record, exists := manifest[key]; exists && record.SourceAddr == req.SourceAddr.String() {
+		prevVersion = record.Version
+	}
+
+	var latestMatch *version.Version
+	var latestVersion *version.Version
+	var latestYankedMatch *version.Version
+	metaByVersion := make(map[string]*response.ModuleVersion)
+	for _, mv := range modMeta.Versions {
+		v, err := version.NewVersion(mv.Version)
+		if err != nil {
+			// Should never happen if the registry server is compliant with
+			// the protocol, but we'll warn if not to assist someone who
+			// might be developing a module registry server.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Invalid response from remote module registry",
+				fmt.Sprintf("The registry at %s returned an invalid version string %q for module %q (%s:%d), which Terraform ignored.", hostname, mv.Version, req.Name, req.CallPos.Filename, req.CallPos.Line),
+			))
+			continue
+		}
+		metaByVersion[v.String()] = mv
+
+		// If we've found a pre-release version then we'll ignore it unless
+		// it was exactly requested.
+		if v.Prerelease() != "" && req.VersionConstraints.String() != v.String() {
+			log.Printf("[TRACE] ModuleInstaller: %s ignoring %s because it is a pre-release and was not requested exactly", key, v)
+			continue
+		}
+
+		// A yanked version is not a candidate for fresh selection, but we
+		// keep using it if it's what we already have installed, and the
+		// user can explicitly override the refusal.
+		if mv.Yanked && !i.allowYanked && (prevVersion == nil || !prevVersion.Equal(v)) {
+			log.Printf("[TRACE] ModuleInstaller: %s ignoring %s because the registry has yanked it", key, v)
+			if req.VersionConstraints.Check(v) {
+				if latestYankedMatch == nil || v.GreaterThan(latestYankedMatch) {
+					latestYankedMatch = v
+				}
+			}
+			continue
+		}
+
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+		}
+
+		if req.VersionConstraints.Check(v) {
+			if latestMatch == nil || v.GreaterThan(latestMatch) {
+				latestMatch = v
+			}
+		}
+	}
+
+	if latestMatch == nil && latestYankedMatch != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unresolvable module version constraint",
+			fmt.Sprintf("The only available versions of module %q (%s:%d) which match the given version constraint have been yanked by the registry at %s. The newest yanked match is %s. To select it anyway, re-run with the -allow-yanked-modules option.", addr, req.CallPos.Filename, req.CallPos.Line, hostname, latestYankedMatch),
+		))
+		return nil, nil, diags
+	}
+
+	if latestVersion == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module has no versions",
+			fmt.Sprintf("Module %q (%s:%d) has no versions available on %s.", addr, req.CallPos.Filename, req.CallPos.Line, hostname),
+		))
+		return nil, nil, diags
+	}
+
+	if latestMatch == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unresolvable module version constraint",
+			fmt.Sprintf("There is no available version of module %q (%s:%d) which matches the given version constraint. The newest available version is %s.", addr, req.CallPos.Filename, req.CallPos.Line, latestVersion),
+		))
+		return nil, nil, diags
+	}
+
+	latestMatchMeta := metaByVersion[latestMatch.String()]
+	if latestMatchMeta != nil {
+		if latestMatchMeta.Yanked {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Yanked module version",
+				fmt.Sprintf("Version %s of module %q (%s:%d) has been yanked by the registry at %s, but is selected anyway because it is already installed or because yanked versions were explicitly allowed. Consider selecting a different version.", latestMatch, req.Name, req.CallPos.Filename, req.CallPos.Line, hostname),
+			))
+		}
+		if latestMatchMeta.IsDeprecated() {
+			detail := fmt.Sprintf("Version %s of module %q (%s:%d) is marked as deprecated by the registry at %s.", latestMatch, req.Name, req.CallPos.Filename, req.CallPos.Line, hostname)
+			if msg := latestMatchMeta.DeprecationText(); msg != "" {
+				detail += fmt.Sprintf("\n\nRegistry message: %s", msg)
+			}
+			if replacement := latestMatchMeta.DeprecationReplacement(); replacement != "" {
+				detail += fmt.Sprintf("\n\nThe registry suggests using version %s instead.", replacement)
+			}
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Deprecated module version",
+				detail,
+			))
+		}
+	}
+
+	return latestMatch, latestMatchMeta, diags
+}
+
 func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *earlyconfig.ModuleRequest, key string, instPath string, manifest modsdir.Manifest, hooks ModuleInstallHooks, fetcher *getmodules.PackageFetcher) (*tfconfig.Module, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
-	// Report up to the caller that we're about to start downloading.
 	addr := req.SourceAddr.(addrs.ModuleSourceRemote)
 	packageAddr := addr.PackageAddr
+
+	if i.offline {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module installation requires network access",
+			fmt.Sprintf(
+				"Module %q (from %s:%d) must be fetched from the remote package %q, which is not possible in offline mode.\n\nEither change this module to a local source address or install it before initializing with -offline.",
+				req.Name, req.CallPos.Filename, req.CallPos.Line, packageAddr.String(),
+			),
+		))
+		return nil, diags
+	}
+
+	// Report up to the caller that we're about to start downloading.
 	hooks.Download(key, packageAddr.String(), nil)
 
 	if len(req.VersionConstraints) != 0 {
@@ -552,7 +717,7 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *earlyc
 		return nil, diags
 	}
 
-	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String())
+	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String(), addr.Subdir)
 	if err != nil {
 		// go-getter generates a poor error for an invalid relative path, so
 		// we'll detect that case and generate a better one.
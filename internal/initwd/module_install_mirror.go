@@ -0,0 +1,183 @@
+package initwd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/copy"
+	"github.com/hashicorp/terraform/internal/earlyconfig"
+	"github.com/hashicorp/terraform/internal/modsdir"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// UseModuleMirror configures the installer to resolve registry module
+// sources from the given local mirror directory instead of contacting the
+// origin registries, for working in environments without network access.
+//
+// The mirror directory uses the layout produced by the
+// "terraform modules mirror" command:
+//
+//	HOSTNAME/NAMESPACE/NAME/TARGET_SYSTEM/VERSION/
+//
+// where each VERSION directory contains the extracted source code of that
+// module package version.
+func (i *ModuleInstaller) UseModuleMirror(dir string) {
+	i.mirrorDir = dir
+}
+
+// mirroredModulePackageDir returns the directory within the mirror that
+// contains the version directories for the given module package.
+func (i *ModuleInstaller) mirroredModulePackageDir(packageAddr addrs.ModuleRegistryPackage) string {
+	return filepath.Join(
+		i.mirrorDir,
+		packageAddr.Host.String(),
+		packageAddr.Namespace,
+		packageAddr.Name,
+		packageAddr.TargetSystem,
+	)
+}
+
+// mirroredModuleVersions returns the versions of the given module package
+// that are present in the mirror directory, based on the names of the
+// version directories.
+func (i *ModuleInstaller) mirroredModuleVersions(packageAddr addrs.ModuleRegistryPackage) ([]*version.Version, error) {
+	entries, err := os.ReadDir(i.mirroredModulePackageDir(packageAddr))
+	if err != nil {
+		return nil, err
+	}
+	var ret []*version.Version
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, err := version.NewVersion(entry.Name())
+		if err != nil {
+			// A non-version directory name is not necessarily a problem:
+			// it might just be something else sharing the directory, so
+			// we'll ignore it.
+			log.Printf("[TRACE] ModuleInstaller: ignoring non-version mirror directory %q for %s", entry.Name(), packageAddr)
+			continue
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// installRegistryModuleFromMirror is the variant of installRegistryModule
+// used when a local module mirror directory is configured, resolving both
+// the version list and the package contents from the mirror so that no
+// network access is needed.
+func (i *ModuleInstaller) installRegistryModuleFromMirror(req *earlyconfig.ModuleRequest, key string, instPath string, addr addrs.ModuleSourceRegistry, manifest modsdir.Manifest, hooks ModuleInstallHooks) (*tfconfig.Module, *version.Version, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	packageAddr := addr.PackageAddr
+
+	available, err := i.mirroredModuleVersions(packageAddr)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module not present in mirror",
+			fmt.Sprintf("Module %q (from %s:%d) is not available in the module mirror directory at %s: %s. Run \"terraform modules mirror\" in an environment with network access to add it.", req.Name, req.CallPos.Filename, req.CallPos.Line, i.mirrorDir, err),
+		))
+		return nil, nil, diags
+	}
+
+	var latestMatch *version.Version
+	var latestVersion *version.Version
+	for _, v := range available {
+		// If we've found a pre-release version then we'll ignore it unless
+		// it was exactly requested.
+		if v.Prerelease() != "" && req.VersionConstraints.String() != v.String() {
+			log.Printf("[TRACE] ModuleInstaller: %s ignoring %s because it is a pre-release and was not requested exactly", key, v)
+			continue
+		}
+
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+		}
+
+		if req.VersionConstraints.Check(v) {
+			if latestMatch == nil || v.GreaterThan(latestMatch) {
+				latestMatch = v
+			}
+		}
+	}
+
+	if latestVersion == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Module has no versions",
+			fmt.Sprintf("Module %q (%s:%d) has no versions available in the module mirror directory at %s.", addr, req.CallPos.Filename, req.CallPos.Line, i.mirrorDir),
+		))
+		return nil, nil, diags
+	}
+
+	if latestMatch == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unresolvable module version constraint",
+			fmt.Sprintf("There is no version of module %q (%s:%d) in the module mirror directory which matches the given version constraint. The newest mirrored version is %s.", addr, req.CallPos.Filename, req.CallPos.Line, latestVersion),
+		))
+		return nil, nil, diags
+	}
+
+	// Report up to the caller that we're about to start "downloading",
+	// which here is only a local directory copy.
+	hooks.Download(key, packageAddr.String(), latestMatch)
+
+	packageDir := filepath.Join(i.mirroredModulePackageDir(packageAddr), latestMatch.String())
+	if err := os.MkdirAll(instPath, os.ModePerm); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to copy module from mirror",
+			fmt.Sprintf("Could not create module installation directory %s: %s.", instPath, err),
+		))
+		return nil, nil, diags
+	}
+	if err := copy.CopyDir(instPath, packageDir); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to copy module from mirror",
+			fmt.Sprintf("Could not copy module %q (%s:%d) from the mirror directory %s: %s.", req.Name, req.CallPos.Filename, req.CallPos.Line, packageDir, err),
+		))
+		return nil, nil, diags
+	}
+
+	log.Printf("[TRACE] ModuleInstaller: %s %s %s was copied from the mirror at %s", key, packageAddr, latestMatch, packageDir)
+
+	subDir := filepath.FromSlash(addr.Subdir)
+	modDir := filepath.Join(instPath, subDir)
+
+	log.Printf("[TRACE] ModuleInstaller: %s should now be at %s", key, modDir)
+
+	mod, mDiags := earlyconfig.LoadModule(modDir)
+	if mod == nil {
+		// nil indicates missing or unreadable directory, so we'll
+		// discard the returned diags and return a more specific
+		// error message here.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unreadable module directory",
+			fmt.Sprintf("The directory %s could not be read, suggesting that the mirrored package for %s %s is incomplete.", modDir, packageAddr, latestMatch),
+		))
+	} else {
+		diags = append(diags, mDiags...)
+	}
+
+	// Note the local location in our manifest.
+	manifest[key] = modsdir.Record{
+		Key:        key,
+		Version:    latestMatch,
+		Dir:        modDir,
+		SourceAddr: req.SourceAddr.String(),
+	}
+	log.Printf("[DEBUG] Module installer: %s installed at %s", key, modDir)
+	hooks.Install(key, latestMatch, modDir)
+
+	return mod, latestMatch, diags
+}
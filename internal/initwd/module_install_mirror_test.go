@@ -0,0 +1,66 @@
+package initwd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+)
+
+func TestModuleInstaller_mirror(t *testing.T) {
+	mirrorDir, err := filepath.Abs("testdata/module-mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtureDir := filepath.Clean("testdata/mirror-modules")
+	dir, done := tempChdir(t, fixtureDir)
+	defer done()
+
+	hooks := &testInstallHooks{}
+
+	modulesDir := filepath.Join(dir, ".terraform/modules")
+	inst := NewModuleInstaller(modulesDir, nil)
+	inst.UseModuleMirror(mirrorDir)
+	_, diags := inst.InstallModules(context.Background(), ".", false, hooks)
+	assertNoDiagnostics(t, diags)
+
+	v := version.Must(version.NewVersion("1.1.0"))
+	wantCalls := []testInstallHookCall{
+		{
+			Name:        "Download",
+			ModuleAddr:  "child",
+			PackageAddr: "example.com/test/child/generic",
+			Version:     v,
+		},
+		{
+			Name:       "Install",
+			ModuleAddr: "child",
+			Version:    v,
+			LocalPath:  filepath.Join(dir, ".terraform/modules/child"),
+		},
+	}
+
+	assertResultDeepEqual(t, hooks.Calls, wantCalls)
+}
+
+func TestModuleInstaller_mirrorNoMatchingVersion(t *testing.T) {
+	mirrorDir, err := filepath.Abs("testdata/module-mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtureDir := filepath.Clean("testdata/mirror-modules-unresolvable")
+	dir, done := tempChdir(t, fixtureDir)
+	defer done()
+
+	hooks := &testInstallHooks{}
+
+	modulesDir := filepath.Join(dir, ".terraform/modules")
+	inst := NewModuleInstaller(modulesDir, nil)
+	inst.UseModuleMirror(mirrorDir)
+	_, diags := inst.InstallModules(context.Background(), ".", false, hooks)
+
+	assertDiagnosticSummary(t, diags, "Unresolvable module version constraint")
+}
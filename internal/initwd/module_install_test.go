@@ -15,12 +15,16 @@ import (
 	"github.com/go-test/deep"
 	"github.com/google/go-cmp/cmp"
 	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
 	svchost "github.com/hashicorp/terraform-svchost"
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/configs/configload"
 	"github.com/hashicorp/terraform/internal/copy"
+	"github.com/hashicorp/terraform/internal/earlyconfig"
+	"github.com/hashicorp/terraform/internal/modsdir"
 	"github.com/hashicorp/terraform/internal/registry"
+	"github.com/hashicorp/terraform/internal/registry/response"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 
 	_ "github.com/hashicorp/terraform/internal/logging"
@@ -308,6 +312,98 @@ func TestModuleInstaller_symlink(t *testing.T) {
 	assertResultDeepEqual(t, gotTraces, wantTraces)
 }
 
+func TestModuleInstaller_selectRegistryModuleVersion(t *testing.T) {
+	sourceAddr, err := addrs.ParseModuleSource("example.com/test/child/generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	registryAddr := sourceAddr.(addrs.ModuleSourceRegistry)
+
+	newReq := func(constraint string) *earlyconfig.ModuleRequest {
+		constraints, err := version.NewConstraint(constraint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &earlyconfig.ModuleRequest{
+			Name:               "child",
+			SourceAddr:         sourceAddr,
+			VersionConstraints: constraints,
+			CallPos:            tfconfig.SourcePos{Filename: "main.tf", Line: 1},
+		}
+	}
+
+	modMeta := &response.ModuleProviderVersions{
+		Source: "test/child/generic",
+		Versions: []*response.ModuleVersion{
+			{Version: "1.0.0", Deprecated: true, DeprecationMessage: "buggy", ReplacementVersion: "1.1.0"},
+			{Version: "1.1.0"},
+			{Version: "1.2.0", Yanked: true},
+		},
+	}
+
+	t.Run("yanked version is not selected", func(t *testing.T) {
+		inst := NewModuleInstaller(t.TempDir(), nil)
+		selected, _, diags := inst.selectRegistryModuleVersion(newReq("~> 1.0"), "child", registryAddr, modMeta, modsdir.Manifest{})
+		assertNoDiagnostics(t, diags)
+		if got, want := selected.String(), "1.1.0"; got != want {
+			t.Errorf("wrong selected version %s; want %s", got, want)
+		}
+	})
+
+	t.Run("deprecated version warns", func(t *testing.T) {
+		inst := NewModuleInstaller(t.TempDir(), nil)
+		selected, meta, diags := inst.selectRegistryModuleVersion(newReq("1.0.0"), "child", registryAddr, modMeta, modsdir.Manifest{})
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		assertDiagnosticSummary(t, diags, "Deprecated module version")
+		if got, want := selected.String(), "1.0.0"; got != want {
+			t.Errorf("wrong selected version %s; want %s", got, want)
+		}
+		if got, want := meta.DeprecationText(), "buggy"; got != want {
+			t.Errorf("wrong deprecation text %q; want %q", got, want)
+		}
+	})
+
+	t.Run("only yanked versions match", func(t *testing.T) {
+		inst := NewModuleInstaller(t.TempDir(), nil)
+		_, _, diags := inst.selectRegistryModuleVersion(newReq("1.2.0"), "child", registryAddr, modMeta, modsdir.Manifest{})
+		assertDiagnosticSummary(t, diags, "Unresolvable module version constraint")
+	})
+
+	t.Run("yanked version allowed by override", func(t *testing.T) {
+		inst := NewModuleInstaller(t.TempDir(), nil)
+		inst.AllowYankedModules()
+		selected, _, diags := inst.selectRegistryModuleVersion(newReq("1.2.0"), "child", registryAddr, modMeta, modsdir.Manifest{})
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		assertDiagnosticSummary(t, diags, "Yanked module version")
+		if got, want := selected.String(), "1.2.0"; got != want {
+			t.Errorf("wrong selected version %s; want %s", got, want)
+		}
+	})
+
+	t.Run("yanked version allowed when already installed", func(t *testing.T) {
+		inst := NewModuleInstaller(t.TempDir(), nil)
+		manifest := modsdir.Manifest{
+			"child": modsdir.Record{
+				Key:        "child",
+				SourceAddr: sourceAddr.String(),
+				Version:    version.Must(version.NewVersion("1.2.0")),
+			},
+		}
+		selected, _, diags := inst.selectRegistryModuleVersion(newReq("1.2.0"), "child", registryAddr, modMeta, manifest)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		assertDiagnosticSummary(t, diags, "Yanked module version")
+		if got, want := selected.String(), "1.2.0"; got != want {
+			t.Errorf("wrong selected version %s; want %s", got, want)
+		}
+	})
+}
+
 func TestLoaderInstallModules_registry(t *testing.T) {
 	if os.Getenv("TF_ACC") == "" {
 		t.Skip("this test accesses registry.terraform.io and github.com; set TF_ACC=1 to run it")
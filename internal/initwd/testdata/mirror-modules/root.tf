@@ -0,0 +1,4 @@
+module "child" {
+  source  = "example.com/test/child/generic"
+  version = "~> 1.0"
+}
@@ -0,0 +1,4 @@
+# fake child module v1.0.0
+output "version" {
+  value = "1.0.0"
+}
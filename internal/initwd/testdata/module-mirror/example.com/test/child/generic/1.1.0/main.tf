@@ -0,0 +1,4 @@
+# fake child module v1.1.0
+output "version" {
+  value = "1.1.0"
+}
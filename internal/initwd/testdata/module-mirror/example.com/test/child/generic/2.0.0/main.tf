@@ -0,0 +1,4 @@
+# fake child module v2.0.0
+output "version" {
+  value = "2.0.0"
+}
@@ -44,7 +44,39 @@ var CidrHostFunc = function.New(&function.Spec{
 	},
 })
 
-// CidrNetmaskFunc contructs a function that converts an IPv4 address prefix given
+// CidrContainsFunc contructs a function that decides whether a given IP
+// address belongs to a given IP network address prefix.
+var CidrContainsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix",
+			Type: cty.String,
+		},
+		{
+			Name: "address",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, network, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+
+		ip := ipaddr.ParseIP(args[1].AsString())
+		if ip == nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid IP address: %s", args[1].AsString())
+		}
+
+		// An address of a different family than the prefix is never
+		// contained in it, rather than being an error, so that this
+		// function can conveniently classify mixed address sets.
+		return cty.BoolVal(network.Contains(ip)), nil
+	},
+})
+
+// CidrNetmaskFunc contructs a function that converts an IP address prefix given
 // in CIDR notation into a subnet mask address.
 var CidrNetmaskFunc = function.New(&function.Spec{
 	Params: []function.Parameter{
@@ -60,11 +92,44 @@ var CidrNetmaskFunc = function.New(&function.Spec{
 			return cty.UnknownVal(cty.String), fmt.Errorf("invalid CIDR expression: %s", err)
 		}
 
-		if network.IP.To4() == nil {
-			return cty.UnknownVal(cty.String), fmt.Errorf("IPv6 addresses cannot have a netmask: %s", args[0].AsString())
+		return cty.StringVal(ipaddr.IP(network.Mask).String()), nil
+	},
+})
+
+// CidrOverlapsFunc contructs a function that decides whether two IP network
+// address prefixes share any addresses.
+var CidrOverlapsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "a",
+			Type: cty.String,
+		},
+		{
+			Name: "b",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, a, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		_, b, err := ipaddr.ParseCIDR(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid CIDR expression: %s", err)
+		}
+
+		// Prefixes of different address families never overlap, rather than
+		// being an error, so that this function can conveniently compare
+		// mixed prefix sets.
+		if len(a.IP) != len(b.IP) {
+			return cty.False, nil
 		}
 
-		return cty.StringVal(ipaddr.IP(network.Mask).String()), nil
+		// Two prefixes overlap if either contains the base address of the
+		// other, since prefixes cannot partially overlap.
+		return cty.BoolVal(a.Contains(b.IP) || b.Contains(a.IP)), nil
 	},
 })
 
@@ -190,16 +255,28 @@ var CidrSubnetsFunc = function.New(&function.Spec{
 	},
 })
 
+// CidrContains decides whether a given IP address belongs to a given IP
+// network address prefix.
+func CidrContains(prefix, address cty.Value) (cty.Value, error) {
+	return CidrContainsFunc.Call([]cty.Value{prefix, address})
+}
+
 // CidrHost calculates a full host IP address within a given IP network address prefix.
 func CidrHost(prefix, hostnum cty.Value) (cty.Value, error) {
 	return CidrHostFunc.Call([]cty.Value{prefix, hostnum})
 }
 
-// CidrNetmask converts an IPv4 address prefix given in CIDR notation into a subnet mask address.
+// CidrNetmask converts an IP address prefix given in CIDR notation into a subnet mask address.
 func CidrNetmask(prefix cty.Value) (cty.Value, error) {
 	return CidrNetmaskFunc.Call([]cty.Value{prefix})
 }
 
+// CidrOverlaps decides whether two IP network address prefixes share any
+// addresses.
+func CidrOverlaps(a, b cty.Value) (cty.Value, error) {
+	return CidrOverlapsFunc.Call([]cty.Value{a, b})
+}
+
 // CidrSubnet calculates a subnet address within a given IP network address prefix.
 func CidrSubnet(prefix, newbits, netnum cty.Value) (cty.Value, error) {
 	return CidrSubnetFunc.Call([]cty.Value{prefix, newbits, netnum})
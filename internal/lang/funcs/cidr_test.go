@@ -97,6 +97,85 @@ func TestCidrHost(t *testing.T) {
 	}
 }
 
+func TestCidrContains(t *testing.T) {
+	tests := []struct {
+		Prefix  cty.Value
+		Address cty.Value
+		Want    cty.Value
+		Err     bool
+	}{
+		{
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("192.168.1.5"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("192.168.2.5"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.StringVal("10.255.255.255"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("2001:db8::/48"),
+			cty.StringVal("2001:db8:0:1::1"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("2001:db8::/48"),
+			cty.StringVal("2001:db9::1"),
+			cty.False,
+			false,
+		},
+		{
+			// An address of a different family is never contained in a
+			// prefix, rather than being an error.
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("2001:db8::1"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("not-a-cidr"),
+			cty.StringVal("192.168.1.5"),
+			cty.UnknownVal(cty.Bool),
+			true, // not a valid CIDR prefix
+		},
+		{
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("not-an-address"),
+			cty.UnknownVal(cty.Bool),
+			true, // not a valid IP address
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidrcontains(%#v, %#v)", test.Prefix, test.Address), func(t *testing.T) {
+			got, err := CidrContains(test.Prefix, test.Address)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestCidrNetmask(t *testing.T) {
 	tests := []struct {
 		Prefix cty.Value
@@ -141,8 +220,13 @@ func TestCidrNetmask(t *testing.T) {
 		},
 		{
 			cty.StringVal("1::/64"),
-			cty.UnknownVal(cty.String),
-			true, // IPv6 is invalid
+			cty.StringVal("ffff:ffff:ffff:ffff::"),
+			false,
+		},
+		{
+			cty.StringVal("2001:db8::/48"),
+			cty.StringVal("ffff:ffff:ffff::"),
+			false,
 		},
 	}
 
@@ -166,6 +250,91 @@ func TestCidrNetmask(t *testing.T) {
 	}
 }
 
+func TestCidrOverlaps(t *testing.T) {
+	tests := []struct {
+		A    cty.Value
+		B    cty.Value
+		Want cty.Value
+		Err  bool
+	}{
+		{
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("192.168.1.128/25"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("192.168.1.128/25"),
+			cty.StringVal("192.168.1.0/24"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("192.168.2.0/24"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("10.0.0.0/8"),
+			cty.StringVal("10.0.0.0/8"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("2001:db8::/48"),
+			cty.StringVal("2001:db8:0:1::/64"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("2001:db8::/48"),
+			cty.StringVal("2001:db9::/48"),
+			cty.False,
+			false,
+		},
+		{
+			// Prefixes of different address families never overlap, rather
+			// than being an error.
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("2001:db8::/48"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("not-a-cidr"),
+			cty.StringVal("192.168.1.0/24"),
+			cty.UnknownVal(cty.Bool),
+			true, // not a valid CIDR prefix
+		},
+		{
+			cty.StringVal("192.168.1.0/24"),
+			cty.StringVal("not-a-cidr"),
+			cty.UnknownVal(cty.Bool),
+			true, // not a valid CIDR prefix
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidroverlaps(%#v, %#v)", test.A, test.B), func(t *testing.T) {
+			got, err := CidrOverlaps(test.A, test.B)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
 func TestCidrSubnet(t *testing.T) {
 	tests := []struct {
 		Prefix  cty.Value
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/url"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"golang.org/x/text/encoding/ianaindex"
 )
 
@@ -53,6 +56,97 @@ var Base64EncodeFunc = function.New(&function.Spec{
 	},
 })
 
+// Base64URLDecodeFunc constructs a function that decodes a string containing
+// a base64 sequence in the URL-safe alphabet.
+var Base64URLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:        "str",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		str, strMarks := args[0].Unmark()
+		// We accept both the padded and unpadded forms, since both are in
+		// common use and the padding carries no information.
+		s := strings.TrimRight(str.AsString(), "=")
+		sDec, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return cty.UnknownVal(cty.String), fmt.Errorf("failed to decode base64 data %s", redactIfSensitive(s, strMarks))
+		}
+		if !utf8.Valid([]byte(sDec)) {
+			log.Printf("[DEBUG] the result of decoding the provided string is not valid UTF-8: %s", redactIfSensitive(sDec, strMarks))
+			return cty.UnknownVal(cty.String), fmt.Errorf("the result of decoding the provided string is not valid UTF-8")
+		}
+		return cty.StringVal(string(sDec)).WithMarks(strMarks), nil
+	},
+})
+
+// Base64URLEncodeFunc constructs a function that encodes a string to a base64
+// sequence in the URL-safe alphabet, without padding.
+var Base64URLEncodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.StringVal(base64.RawURLEncoding.EncodeToString([]byte(args[0].AsString()))), nil
+	},
+})
+
+// JWTDecodeFunc constructs a function that decodes the claims from a JSON Web
+// Token without verifying its signature.
+var JWTDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "token",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		buf, err := jwtClaimsJSON(args[0].AsString())
+		if err != nil {
+			return cty.NilType, err
+		}
+		return ctyjson.ImpliedType(buf)
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		buf, err := jwtClaimsJSON(args[0].AsString())
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return ctyjson.Unmarshal(buf, retType)
+	},
+})
+
+// jwtClaimsJSON extracts the raw JSON representation of the claims from the
+// payload of the given JWT, without verifying the token's signature.
+//
+// The error messages intentionally don't include any of the token's content,
+// since tokens are often sensitive.
+func jwtClaimsJSON(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid JWT: must have three dot-separated parts, not %d", len(parts))
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT: payload is not valid base64url data")
+	}
+	if !json.Valid(buf) || len(buf) == 0 || buf[0] != '{' {
+		return nil, fmt.Errorf("invalid JWT: payload is not a JSON object")
+	}
+	return buf, nil
+}
+
 // TextEncodeBase64Func constructs a function that encodes a string to a target encoding and then to a base64 sequence.
 var TextEncodeBase64Func = function.New(&function.Spec{
 	Params: []function.Parameter{
@@ -207,6 +301,33 @@ func Base64Encode(str cty.Value) (cty.Value, error) {
 	return Base64EncodeFunc.Call([]cty.Value{str})
 }
 
+// Base64URLDecode decodes a string containing a base64 sequence in the
+// URL-safe alphabet defined in RFC 4648 section 5, with or without padding.
+//
+// Strings in the Terraform language are sequences of unicode characters rather
+// than bytes, so this function will also interpret the resulting bytes as
+// UTF-8. If the bytes after Base64 decoding are _not_ valid UTF-8, this function
+// produces an error.
+func Base64URLDecode(str cty.Value) (cty.Value, error) {
+	return Base64URLDecodeFunc.Call([]cty.Value{str})
+}
+
+// Base64URLEncode applies Base64 encoding to a string, using the URL-safe
+// alphabet defined in RFC 4648 section 5 and omitting the padding.
+//
+// Strings in the Terraform language are sequences of unicode characters rather
+// than bytes, so this function will first encode the characters from the string
+// as UTF-8, and then apply Base64 encoding to the result.
+func Base64URLEncode(str cty.Value) (cty.Value, error) {
+	return Base64URLEncodeFunc.Call([]cty.Value{str})
+}
+
+// JWTDecode decodes the claims from the payload of a JSON Web Token, without
+// verifying the token's signature.
+func JWTDecode(token cty.Value) (cty.Value, error) {
+	return JWTDecodeFunc.Call([]cty.Value{token})
+}
+
 // Base64Gzip compresses a string with gzip and then encodes the result in
 // Base64 encoding.
 //
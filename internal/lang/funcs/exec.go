@@ -0,0 +1,68 @@
+package funcs
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform/internal/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+// SecretExecFunc constructs a function that runs a given program and returns
+// its standard output as a sensitive string, so that secrets can be fetched
+// from external tools such as secret manager CLIs without writing them to
+// disk or requiring a custom provider.
+//
+// This is an impure function, so the language runtime defers its evaluation
+// until the apply phase. The result therefore appears as an unknown value in
+// a saved plan, and the fetched secret is never recorded there.
+var SecretExecFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "command",
+			Type: cty.List(cty.String),
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		var argv []string
+		if err := gocty.FromCtyValue(args[0], &argv); err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid command: %s", err)
+		}
+		if len(argv) == 0 {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "must provide at least the name of a program to run")
+		}
+
+		cmd := exec.Command(argv[0], argv[1:]...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		stdout, err := cmd.Output()
+		if err != nil {
+			if stderr.Len() > 0 {
+				return cty.UnknownVal(cty.String), fmt.Errorf("program %q failed: %s\n%s", argv[0], err, strings.TrimSpace(stderr.String()))
+			}
+			return cty.UnknownVal(cty.String), fmt.Errorf("program %q failed: %s", argv[0], err)
+		}
+		if !utf8.Valid(stdout) {
+			return cty.UnknownVal(cty.String), fmt.Errorf("program %q produced output that is not valid UTF-8", argv[0])
+		}
+
+		// We discard one trailing newline, if present, because most
+		// command-line tools emit one after their result and a literal
+		// newline is rarely part of the secret itself.
+		result := strings.TrimSuffix(strings.TrimSuffix(string(stdout), "\n"), "\r")
+
+		return cty.StringVal(result).Mark(marks.Sensitive), nil
+	},
+})
+
+// SecretExec runs the program described by the given command line and returns
+// its standard output, minus one trailing newline, as a sensitive string.
+func SecretExec(command cty.Value) (cty.Value, error) {
+	return SecretExecFunc.Call([]cty.Value{command})
+}
@@ -0,0 +1,74 @@
+package funcs
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSecretExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("these tests run standard unix tools, which are not available on Windows")
+	}
+
+	tests := []struct {
+		Command cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("echo"), cty.StringVal("hello")}),
+			cty.StringVal("hello").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			// Only one trailing newline is discarded; any other whitespace
+			// in the output is retained verbatim.
+			cty.ListVal([]cty.Value{cty.StringVal("printf"), cty.StringVal("beep\nboop\n")}),
+			cty.StringVal("beep\nboop").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.ListValEmpty(cty.String),
+			cty.NilVal,
+			`must provide at least the name of a program to run`,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("false")}),
+			cty.NilVal,
+			`program "false" failed`,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("terraform-nonexistent-program")}),
+			cty.NilVal,
+			`program "terraform-nonexistent-program" failed`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("secretexec(%#v)", test.Command), func(t *testing.T) {
+			got, err := SecretExec(test.Command)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error %q; want substring %q", err.Error(), test.Err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 var impureFunctions = []string{
 	"bcrypt",
+	"secretexec",
 	"timestamp",
 	"uuid",
 }
@@ -40,14 +41,18 @@ func (s *Scope) Functions() map[string]function.Function {
 			"base64decode":     funcs.Base64DecodeFunc,
 			"base64encode":     funcs.Base64EncodeFunc,
 			"base64gzip":       funcs.Base64GzipFunc,
+			"base64urldecode":  funcs.Base64URLDecodeFunc,
+			"base64urlencode":  funcs.Base64URLEncodeFunc,
 			"base64sha256":     funcs.Base64Sha256Func,
 			"base64sha512":     funcs.Base64Sha512Func,
 			"bcrypt":           funcs.BcryptFunc,
 			"can":              tryfunc.CanFunc,
 			"ceil":             stdlib.CeilFunc,
 			"chomp":            stdlib.ChompFunc,
+			"cidrcontains":     funcs.CidrContainsFunc,
 			"cidrhost":         funcs.CidrHostFunc,
 			"cidrnetmask":      funcs.CidrNetmaskFunc,
+			"cidroverlaps":     funcs.CidrOverlapsFunc,
 			"cidrsubnet":       funcs.CidrSubnetFunc,
 			"cidrsubnets":      funcs.CidrSubnetsFunc,
 			"coalesce":         funcs.CoalesceFunc,
@@ -80,6 +85,7 @@ func (s *Scope) Functions() map[string]function.Function {
 			"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
 			"join":             stdlib.JoinFunc,
 			"jsondecode":       stdlib.JSONDecodeFunc,
+			"jwtdecode":        funcs.JWTDecodeFunc,
 			"jsonencode":       stdlib.JSONEncodeFunc,
 			"keys":             stdlib.KeysFunc,
 			"length":           funcs.LengthFunc,
@@ -103,6 +109,7 @@ func (s *Scope) Functions() map[string]function.Function {
 			"replace":          funcs.ReplaceFunc,
 			"reverse":          stdlib.ReverseListFunc,
 			"rsadecrypt":       funcs.RsaDecryptFunc,
+			"secretexec":       funcs.SecretExecFunc,
 			"sensitive":        funcs.SensitiveFunc,
 			"nonsensitive":     funcs.NonsensitiveFunc,
 			"setintersection":  stdlib.SetIntersectionFunc,
@@ -42,6 +42,12 @@ type Record struct {
 
 	// Dir is the path to the local directory where the module is installed.
 	Dir string `json:"Dir"`
+
+	// Deprecation is the deprecation message that the origin registry
+	// reported for the installed version at install time, if any. Commands
+	// that run after installation use this to repeat a condensed warning
+	// without needing to contact the registry again.
+	Deprecation string `json:"Deprecation,omitempty"`
 }
 
 // Manifest is a map used to keep track of the filesystem locations
@@ -0,0 +1,567 @@
+package moduletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/hashicorp/terraform/internal/providers"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// This file implements recording and replaying of provider calls for
+// "terraform test", so that module tests recorded against real remote APIs
+// can later re-run hermetically, without network access or credentials.
+//
+// A Recorder wraps real provider instances and captures the provider schema
+// along with the request and response of every substantive resource
+// operation, producing a Recording that can be saved as a JSON fixture file.
+// A Replayer serves a saved Recording back through a shim provider:
+// responses are replayed to requests of the same method and resource type in
+// the order they were recorded.
+
+// RecordingFormatVersion identifies the current fixture file format,
+// for detecting fixtures recorded by incompatible future versions.
+const RecordingFormatVersion = "1.0"
+
+// Recording is the serializable form of a sequence of recorded provider
+// calls, as stored in a replay fixture file.
+type Recording struct {
+	FormatVersion string `json:"format_version"`
+
+	// Schema is the recorded response from GetProviderSchema, which a
+	// Replayer serves in place of asking a real provider.
+	Schema *recordedSchema `json:"schema"`
+
+	// Entries are the recorded resource operations, in call order.
+	Entries []*RecordingEntry `json:"entries"`
+}
+
+// RecordingEntry is one recorded provider call.
+type RecordingEntry struct {
+	// Method is the provider method that was called, such as
+	// "apply_resource_change".
+	Method string `json:"method"`
+
+	// TypeName is the resource or data source type the call concerned.
+	TypeName string `json:"type_name"`
+
+	// Response is the method-specific recorded response.
+	Response json.RawMessage `json:"response"`
+}
+
+// Method names used in RecordingEntry.
+const (
+	methodReadResource        = "read_resource"
+	methodPlanResourceChange  = "plan_resource_change"
+	methodApplyResourceChange = "apply_resource_change"
+	methodReadDataSource      = "read_data_source"
+)
+
+// A Recorder accumulates provider calls from one or more provider instances
+// into a single Recording. The same Recorder should be shared between all
+// of the instances of one provider within a test suite run, since Terraform
+// may start separate instances for the plan, apply, and destroy steps.
+type Recorder struct {
+	mu        sync.Mutex
+	recording Recording
+}
+
+// NewRecorder returns a Recorder ready to wrap provider instances.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		recording: Recording{
+			FormatVersion: RecordingFormatVersion,
+		},
+	}
+}
+
+// Provider returns a provider which passes all calls through to the given
+// provider while recording them into the recorder.
+func (r *Recorder) Provider(wrapped providers.Interface) providers.Interface {
+	return &recordingProvider{recorder: r, wrapped: wrapped}
+}
+
+// Recording returns the calls recorded so far, for saving to a fixture file.
+func (r *Recorder) Recording() *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &r.recording
+}
+
+func (r *Recorder) setSchema(schema *recordedSchema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.recording.Schema == nil {
+		r.recording.Schema = schema
+	}
+}
+
+func (r *Recorder) appendEntry(method, typeName string, response interface{}) {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		// A response we can't serialize (which should not happen for any
+		// value a provider can legally return) just leaves a gap in the
+		// recording, which will surface as a missing response during replay.
+		log.Printf("[WARN] moduletest: failed to record %s response for %s: %s", method, typeName, err)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recording.Entries = append(r.recording.Entries, &RecordingEntry{
+		Method:   method,
+		TypeName: typeName,
+		Response: raw,
+	})
+}
+
+// A Replayer serves the calls in a Recording back through shim providers.
+// As with Recorder, one Replayer should be shared between all of the
+// instances of one provider within a test suite run, so that the replay
+// position carries across the plan, apply, and destroy steps.
+type Replayer struct {
+	mu        sync.Mutex
+	recording *Recording
+	consumed  []bool
+}
+
+// NewReplayer returns a Replayer serving the given recording.
+func NewReplayer(recording *Recording) (*Replayer, error) {
+	if recording.FormatVersion != RecordingFormatVersion {
+		return nil, fmt.Errorf("unsupported replay fixture format version %q", recording.FormatVersion)
+	}
+	if recording.Schema == nil {
+		return nil, fmt.Errorf("replay fixture has no recorded provider schema")
+	}
+	return &Replayer{
+		recording: recording,
+		consumed:  make([]bool, len(recording.Entries)),
+	}, nil
+}
+
+// Provider returns a provider which answers calls from the recording
+// instead of contacting any remote system.
+func (r *Replayer) Provider() providers.Interface {
+	return &replayProvider{replayer: r}
+}
+
+// nextEntry consumes and returns the earliest unconsumed entry with the
+// given method and type name, or nil if none remain.
+func (r *Replayer) nextEntry(method, typeName string) *RecordingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, entry := range r.recording.Entries {
+		if r.consumed[i] || entry.Method != method || entry.TypeName != typeName {
+			continue
+		}
+		r.consumed[i] = true
+		return entry
+	}
+	return nil
+}
+
+// recordedSchema is the serializable form of a GetProviderSchemaResponse.
+// The configschema types it refers to are all plain data and so serialize
+// directly, and cty.Type has its own JSON encoding.
+type recordedSchema struct {
+	Provider      providers.Schema            `json:"provider"`
+	ProviderMeta  providers.Schema            `json:"provider_meta,omitempty"`
+	ResourceTypes map[string]providers.Schema `json:"resource_types,omitempty"`
+	DataSources   map[string]providers.Schema `json:"data_sources,omitempty"`
+}
+
+// recordedDiagnostic is the serializable form of a single diagnostic.
+// Source location information is not preserved, since it refers to
+// configuration that may have changed by the time of replay.
+type recordedDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func recordDiagnostics(diags tfdiags.Diagnostics) []recordedDiagnostic {
+	if len(diags) == 0 {
+		return nil
+	}
+	ret := make([]recordedDiagnostic, 0, len(diags))
+	for _, diag := range diags {
+		recorded := recordedDiagnostic{
+			Summary: diag.Description().Summary,
+			Detail:  diag.Description().Detail,
+		}
+		switch diag.Severity() {
+		case tfdiags.Warning:
+			recorded.Severity = "warning"
+		default:
+			recorded.Severity = "error"
+		}
+		ret = append(ret, recorded)
+	}
+	return ret
+}
+
+func replayDiagnostics(recorded []recordedDiagnostic) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, rd := range recorded {
+		severity := tfdiags.Error
+		if rd.Severity == "warning" {
+			severity = tfdiags.Warning
+		}
+		diags = diags.Append(tfdiags.Sourceless(severity, rd.Summary, rd.Detail))
+	}
+	return diags
+}
+
+// recordedValue is the serializable form of a cty.Value of any type,
+// encoded with msgpack so that unknown values (as appear in planned states)
+// round-trip correctly. It appears in fixture files as a base64 string.
+type recordedValue []byte
+
+func recordValue(v cty.Value) (recordedValue, error) {
+	if v == cty.NilVal {
+		return nil, nil
+	}
+	return ctymsgpack.Marshal(v, cty.DynamicPseudoType)
+}
+
+func (rv recordedValue) value() (cty.Value, error) {
+	if rv == nil {
+		return cty.NilVal, nil
+	}
+	return ctymsgpack.Unmarshal(rv, cty.DynamicPseudoType)
+}
+
+// The method-specific response representations. Private blobs serialize as
+// base64 strings. RequiresReplace is intentionally not preserved for
+// planned changes: it only influences how a change is classified for
+// display, and recording it would complicate the fixture format for
+// little benefit in a test context.
+type recordedReadResourceResponse struct {
+	NewState    recordedValue        `json:"new_state"`
+	Private     []byte               `json:"private,omitempty"`
+	Diagnostics []recordedDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type recordedPlanResourceChangeResponse struct {
+	PlannedState     recordedValue        `json:"planned_state"`
+	PlannedPrivate   []byte               `json:"planned_private,omitempty"`
+	LegacyTypeSystem bool                 `json:"legacy_type_system,omitempty"`
+	Diagnostics      []recordedDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type recordedApplyResourceChangeResponse struct {
+	NewState         recordedValue        `json:"new_state"`
+	Private          []byte               `json:"private,omitempty"`
+	LegacyTypeSystem bool                 `json:"legacy_type_system,omitempty"`
+	Diagnostics      []recordedDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type recordedReadDataSourceResponse struct {
+	State       recordedValue        `json:"state"`
+	Diagnostics []recordedDiagnostic `json:"diagnostics,omitempty"`
+}
+
+// recordingProvider passes calls through to a real provider, recording the
+// substantive ones.
+type recordingProvider struct {
+	recorder *Recorder
+	wrapped  providers.Interface
+}
+
+var _ providers.Interface = (*recordingProvider)(nil)
+
+func (p *recordingProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
+	resp := p.wrapped.GetProviderSchema()
+	if !resp.Diagnostics.HasErrors() {
+		p.recorder.setSchema(&recordedSchema{
+			Provider:      resp.Provider,
+			ProviderMeta:  resp.ProviderMeta,
+			ResourceTypes: resp.ResourceTypes,
+			DataSources:   resp.DataSources,
+		})
+	}
+	return resp
+}
+
+func (p *recordingProvider) ValidateProviderConfig(req providers.ValidateProviderConfigRequest) providers.ValidateProviderConfigResponse {
+	return p.wrapped.ValidateProviderConfig(req)
+}
+
+func (p *recordingProvider) ValidateResourceConfig(req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
+	return p.wrapped.ValidateResourceConfig(req)
+}
+
+func (p *recordingProvider) ValidateDataResourceConfig(req providers.ValidateDataResourceConfigRequest) providers.ValidateDataResourceConfigResponse {
+	return p.wrapped.ValidateDataResourceConfig(req)
+}
+
+func (p *recordingProvider) UpgradeResourceState(req providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
+	return p.wrapped.UpgradeResourceState(req)
+}
+
+func (p *recordingProvider) ConfigureProvider(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	return p.wrapped.ConfigureProvider(req)
+}
+
+func (p *recordingProvider) Stop() error {
+	return p.wrapped.Stop()
+}
+
+func (p *recordingProvider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	resp := p.wrapped.ReadResource(req)
+	newState, err := recordValue(resp.NewState)
+	if err != nil {
+		log.Printf("[WARN] moduletest: failed to record state from read_resource for %s: %s", req.TypeName, err)
+		return resp
+	}
+	p.recorder.appendEntry(methodReadResource, req.TypeName, recordedReadResourceResponse{
+		NewState:    newState,
+		Private:     resp.Private,
+		Diagnostics: recordDiagnostics(resp.Diagnostics),
+	})
+	return resp
+}
+
+func (p *recordingProvider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	resp := p.wrapped.PlanResourceChange(req)
+	plannedState, err := recordValue(resp.PlannedState)
+	if err != nil {
+		log.Printf("[WARN] moduletest: failed to record state from plan_resource_change for %s: %s", req.TypeName, err)
+		return resp
+	}
+	p.recorder.appendEntry(methodPlanResourceChange, req.TypeName, recordedPlanResourceChangeResponse{
+		PlannedState:     plannedState,
+		PlannedPrivate:   resp.PlannedPrivate,
+		LegacyTypeSystem: resp.LegacyTypeSystem,
+		Diagnostics:      recordDiagnostics(resp.Diagnostics),
+	})
+	return resp
+}
+
+func (p *recordingProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	resp := p.wrapped.ApplyResourceChange(req)
+	newState, err := recordValue(resp.NewState)
+	if err != nil {
+		log.Printf("[WARN] moduletest: failed to record state from apply_resource_change for %s: %s", req.TypeName, err)
+		return resp
+	}
+	p.recorder.appendEntry(methodApplyResourceChange, req.TypeName, recordedApplyResourceChangeResponse{
+		NewState:         newState,
+		Private:          resp.Private,
+		LegacyTypeSystem: resp.LegacyTypeSystem,
+		Diagnostics:      recordDiagnostics(resp.Diagnostics),
+	})
+	return resp
+}
+
+func (p *recordingProvider) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	// Imports are not recorded, and are rejected during replay.
+	return p.wrapped.ImportResourceState(req)
+}
+
+func (p *recordingProvider) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	resp := p.wrapped.ReadDataSource(req)
+	state, err := recordValue(resp.State)
+	if err != nil {
+		log.Printf("[WARN] moduletest: failed to record state from read_data_source for %s: %s", req.TypeName, err)
+		return resp
+	}
+	p.recorder.appendEntry(methodReadDataSource, req.TypeName, recordedReadDataSourceResponse{
+		State:       state,
+		Diagnostics: recordDiagnostics(resp.Diagnostics),
+	})
+	return resp
+}
+
+func (p *recordingProvider) Close() error {
+	return p.wrapped.Close()
+}
+
+// replayProvider answers calls from a recording instead of a remote system.
+type replayProvider struct {
+	replayer *Replayer
+}
+
+var _ providers.Interface = (*replayProvider)(nil)
+
+func (p *replayProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
+	schema := p.replayer.recording.Schema
+	return providers.GetProviderSchemaResponse{
+		Provider:      schema.Provider,
+		ProviderMeta:  schema.ProviderMeta,
+		ResourceTypes: schema.ResourceTypes,
+		DataSources:   schema.DataSources,
+	}
+}
+
+func (p *replayProvider) ValidateProviderConfig(req providers.ValidateProviderConfigRequest) providers.ValidateProviderConfigResponse {
+	return providers.ValidateProviderConfigResponse{PreparedConfig: req.Config}
+}
+
+func (p *replayProvider) ValidateResourceConfig(req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
+	return providers.ValidateResourceConfigResponse{}
+}
+
+func (p *replayProvider) ValidateDataResourceConfig(req providers.ValidateDataResourceConfigRequest) providers.ValidateDataResourceConfigResponse {
+	return providers.ValidateDataResourceConfigResponse{}
+}
+
+func (p *replayProvider) UpgradeResourceState(req providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
+	// State within a replayed run is always produced by the same recorded
+	// schema, so upgrading is just decoding against the current schema.
+	var resp providers.UpgradeResourceStateResponse
+	schema, ok := p.replayer.recording.Schema.ResourceTypes[req.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unknown resource type in replay fixture",
+			fmt.Sprintf("The replay fixture's provider schema has no resource type %q.", req.TypeName),
+		))
+		return resp
+	}
+	v, err := ctyjson.Unmarshal(req.RawStateJSON, schema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to decode state during replay",
+			fmt.Sprintf("Could not decode the state for %s against the recorded schema: %s.", req.TypeName, err),
+		))
+		return resp
+	}
+	resp.UpgradedState = v
+	return resp
+}
+
+func (p *replayProvider) ConfigureProvider(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	return providers.ConfigureProviderResponse{}
+}
+
+func (p *replayProvider) Stop() error {
+	return nil
+}
+
+// replayEntry decodes the next recorded response for the given method and
+// type name into the given method-specific response struct, returning
+// error diagnostics if no recorded response remains or it cannot be decoded.
+func (p *replayProvider) replayEntry(method, typeName string, into interface{}) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	entry := p.replayer.nextEntry(method, typeName)
+	if entry == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No recorded response remaining in replay fixture",
+			fmt.Sprintf("The test requires a %s call for %s, but the replay fixture has no further recorded response for it. The fixture may have been recorded against a different version of the module; re-run the tests with -record to refresh it.", method, typeName),
+		))
+		return diags
+	}
+	if err := json.Unmarshal(entry.Response, into); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid recorded response in replay fixture",
+			fmt.Sprintf("Failed to decode the recorded %s response for %s: %s.", method, typeName, err),
+		))
+	}
+	return diags
+}
+
+func replayValueError(err error) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Invalid recorded value in replay fixture",
+		fmt.Sprintf("Failed to decode a recorded value: %s.", err),
+	))
+	return diags
+}
+
+func (p *replayProvider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	var resp providers.ReadResourceResponse
+	var recorded recordedReadResourceResponse
+	if diags := p.replayEntry(methodReadResource, req.TypeName, &recorded); diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+	newState, err := recorded.NewState.value()
+	if err != nil {
+		resp.Diagnostics = replayValueError(err)
+		return resp
+	}
+	resp.NewState = newState
+	resp.Private = recorded.Private
+	resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+	return resp
+}
+
+func (p *replayProvider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	var resp providers.PlanResourceChangeResponse
+	var recorded recordedPlanResourceChangeResponse
+	if diags := p.replayEntry(methodPlanResourceChange, req.TypeName, &recorded); diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+	plannedState, err := recorded.PlannedState.value()
+	if err != nil {
+		resp.Diagnostics = replayValueError(err)
+		return resp
+	}
+	resp.PlannedState = plannedState
+	resp.PlannedPrivate = recorded.PlannedPrivate
+	resp.LegacyTypeSystem = recorded.LegacyTypeSystem
+	resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+	return resp
+}
+
+func (p *replayProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	var resp providers.ApplyResourceChangeResponse
+	var recorded recordedApplyResourceChangeResponse
+	if diags := p.replayEntry(methodApplyResourceChange, req.TypeName, &recorded); diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+	newState, err := recorded.NewState.value()
+	if err != nil {
+		resp.Diagnostics = replayValueError(err)
+		return resp
+	}
+	resp.NewState = newState
+	resp.Private = recorded.Private
+	resp.LegacyTypeSystem = recorded.LegacyTypeSystem
+	resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+	return resp
+}
+
+func (p *replayProvider) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	var resp providers.ImportResourceStateResponse
+	resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Import is not supported during replay",
+		"Provider calls are being replayed from a recorded fixture, which does not support importing resources.",
+	))
+	return resp
+}
+
+func (p *replayProvider) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	var resp providers.ReadDataSourceResponse
+	var recorded recordedReadDataSourceResponse
+	if diags := p.replayEntry(methodReadDataSource, req.TypeName, &recorded); diags.HasErrors() {
+		resp.Diagnostics = diags
+		return resp
+	}
+	state, err := recorded.State.value()
+	if err != nil {
+		resp.Diagnostics = replayValueError(err)
+		return resp
+	}
+	resp.State = state
+	resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+	return resp
+}
+
+func (p *replayProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,157 @@
+package moduletest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
+)
+
+// stubProvider is a minimal provider implementation with canned responses,
+// standing in for a real provider when testing recording and replay.
+type stubProvider struct {
+	providers.Interface
+}
+
+func (p *stubProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
+	return providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{
+			Block: &configschema.Block{},
+		},
+		ResourceTypes: map[string]providers.Schema{
+			"stub_thing": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":   {Type: cty.String, Computed: true},
+						"name": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+		DataSources: map[string]providers.Schema{
+			"stub_data": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"value": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *stubProvider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	return providers.PlanResourceChangeResponse{
+		PlannedState: cty.ObjectVal(map[string]cty.Value{
+			"id":   cty.UnknownVal(cty.String),
+			"name": cty.StringVal("boop"),
+		}),
+	}
+}
+
+func (p *stubProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	return providers.ApplyResourceChangeResponse{
+		NewState: cty.ObjectVal(map[string]cty.Value{
+			"id":   cty.StringVal("placed"),
+			"name": cty.StringVal("boop"),
+		}),
+		Private: []byte("private data"),
+	}
+}
+
+func (p *stubProvider) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	return providers.ReadDataSourceResponse{
+		State: cty.ObjectVal(map[string]cty.Value{
+			"value": cty.StringVal("from remote"),
+		}),
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	stub := &stubProvider{}
+	recorder := NewRecorder()
+	recording := recorder.Provider(stub)
+
+	// Record a schema fetch and one call of each supported kind, keeping
+	// the original responses for comparison after replay.
+	wantSchema := recording.GetProviderSchema()
+	wantPlan := recording.PlanResourceChange(providers.PlanResourceChangeRequest{TypeName: "stub_thing"})
+	wantApply := recording.ApplyResourceChange(providers.ApplyResourceChangeRequest{TypeName: "stub_thing"})
+	wantRead := recording.ReadDataSource(providers.ReadDataSourceRequest{TypeName: "stub_data"})
+
+	// Round-trip the recording through its JSON fixture file form.
+	data, err := json.Marshal(recorder.Recording())
+	if err != nil {
+		t.Fatalf("failed to marshal recording: %s", err)
+	}
+	var loaded Recording
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("failed to unmarshal recording: %s", err)
+	}
+
+	replayer, err := NewReplayer(&loaded)
+	if err != nil {
+		t.Fatalf("failed to create replayer: %s", err)
+	}
+	replay := replayer.Provider()
+
+	gotSchema := replay.GetProviderSchema()
+	if got, want := len(gotSchema.ResourceTypes), len(wantSchema.ResourceTypes); got != want {
+		t.Errorf("wrong number of resource types %d; want %d", got, want)
+	}
+	wantThingTy := wantSchema.ResourceTypes["stub_thing"].Block.ImpliedType()
+	if got := gotSchema.ResourceTypes["stub_thing"].Block.ImpliedType(); !got.Equals(wantThingTy) {
+		t.Errorf("wrong replayed schema type %#v; want %#v", got, wantThingTy)
+	}
+
+	gotPlan := replay.PlanResourceChange(providers.PlanResourceChangeRequest{TypeName: "stub_thing"})
+	if gotPlan.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error from replayed plan: %s", gotPlan.Diagnostics.Err())
+	}
+	if !gotPlan.PlannedState.RawEquals(wantPlan.PlannedState) {
+		t.Errorf("wrong replayed planned state\ngot:  %#v\nwant: %#v", gotPlan.PlannedState, wantPlan.PlannedState)
+	}
+
+	gotApply := replay.ApplyResourceChange(providers.ApplyResourceChangeRequest{TypeName: "stub_thing"})
+	if gotApply.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error from replayed apply: %s", gotApply.Diagnostics.Err())
+	}
+	if !gotApply.NewState.RawEquals(wantApply.NewState) {
+		t.Errorf("wrong replayed new state\ngot:  %#v\nwant: %#v", gotApply.NewState, wantApply.NewState)
+	}
+	if got, want := string(gotApply.Private), string(wantApply.Private); got != want {
+		t.Errorf("wrong replayed private data %q; want %q", got, want)
+	}
+
+	gotRead := replay.ReadDataSource(providers.ReadDataSourceRequest{TypeName: "stub_data"})
+	if gotRead.Diagnostics.HasErrors() {
+		t.Fatalf("unexpected error from replayed read: %s", gotRead.Diagnostics.Err())
+	}
+	if !gotRead.State.RawEquals(wantRead.State) {
+		t.Errorf("wrong replayed data source state\ngot:  %#v\nwant: %#v", gotRead.State, wantRead.State)
+	}
+
+	// The recorded responses are now all consumed, so a further call must
+	// fail rather than silently inventing a response.
+	gotExtra := replay.ApplyResourceChange(providers.ApplyResourceChangeRequest{TypeName: "stub_thing"})
+	if !gotExtra.Diagnostics.HasErrors() {
+		t.Fatal("expected error from extra replayed apply, got success")
+	}
+	if got := gotExtra.Diagnostics.Err().Error(); !strings.Contains(got, "No recorded response remaining") {
+		t.Errorf("unexpected error message: %s", got)
+	}
+}
+
+func TestReplayerFormatVersion(t *testing.T) {
+	_, err := NewReplayer(&Recording{FormatVersion: "2.0", Schema: &recordedSchema{}})
+	if err == nil {
+		t.Fatal("expected error for unsupported format version, got none")
+	}
+	if got := err.Error(); !strings.Contains(got, "unsupported replay fixture format version") {
+		t.Errorf("unexpected error message: %s", got)
+	}
+}
@@ -1,7 +1,13 @@
 package moduletest
 
+import "time"
+
 // A Suite is a set of tests run together as a single Terraform configuration.
 type Suite struct {
 	Name       string
 	Components map[string]*Component
+
+	// Duration is how long the suite took to run, including preparing its
+	// working directory and installing its dependencies.
+	Duration time.Duration
 }
@@ -0,0 +1,84 @@
+package planfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+const environmentFilename = "tfmeta.json"
+
+// Environment describes the run environment in which a saved plan was
+// created, so that later commands can display it for auditing purposes and
+// optionally refuse to apply the plan if the environment has changed in
+// the meantime.
+type Environment struct {
+	// TerraformVersion is the version of Terraform that created the plan.
+	TerraformVersion string `json:"terraform_version"`
+
+	// Workspace is the name of the workspace the plan was created in.
+	Workspace string `json:"workspace"`
+
+	// Providers describes the provider versions that were selected when
+	// the plan was created, keyed by provider source address.
+	Providers map[string]*EnvironmentProvider `json:"providers"`
+
+	// Variables records the input variable values the plan was created
+	// with, keyed by variable name. Values of variables marked as
+	// sensitive in the configuration are recorded only as a hash.
+	Variables map[string]*EnvironmentVariable `json:"variables"`
+}
+
+// EnvironmentProvider describes the version of one provider that was
+// selected when a plan was created.
+type EnvironmentProvider struct {
+	// Version is the exact provider version recorded in the dependency
+	// lock file when the plan was created.
+	Version string `json:"version"`
+
+	// Hashes are the package checksums recorded for the provider in the
+	// dependency lock file, in lexical order.
+	Hashes []string `json:"hashes,omitempty"`
+}
+
+// EnvironmentVariable records the value of one input variable that a plan
+// was created with.
+type EnvironmentVariable struct {
+	// Value is the JSON encoding of the variable's value. It is omitted
+	// for variables marked as sensitive in the configuration.
+	Value json.RawMessage `json:"value,omitempty"`
+
+	// ValueSHA256 is the lowercase hex SHA-256 digest of the serialized
+	// value of a variable marked as sensitive in the configuration,
+	// recorded instead of the value itself so that the plan file doesn't
+	// disclose the value while still allowing it to be compared.
+	ValueSHA256 string `json:"value_sha256,omitempty"`
+}
+
+// ReadEnvironment reads the run environment record embedded in the plan
+// file.
+//
+// Plan files created by earlier versions of Terraform don't contain this
+// record, in which case ReadEnvironment returns a nil Environment with no
+// error and it's up to the caller to decide whether that's acceptable.
+func (r *Reader) ReadEnvironment() (*Environment, error) {
+	for _, file := range r.zip.File {
+		if file.Name == environmentFilename {
+			fr, err := file.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to extract run environment from plan file: %s", err)
+			}
+			defer fr.Close()
+			src, err := ioutil.ReadAll(fr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read run environment from plan file: %s", err)
+			}
+			var env Environment
+			if err := json.Unmarshal(src, &env); err != nil {
+				return nil, fmt.Errorf("invalid run environment record in plan file: %s", err)
+			}
+			return &env, nil
+		}
+	}
+	return nil, nil
+}
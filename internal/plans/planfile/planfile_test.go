@@ -1,6 +1,7 @@
 package planfile
 
 import (
+	"encoding/json"
 	"path/filepath"
 	"testing"
 
@@ -84,6 +85,23 @@ func TestRoundtrip(t *testing.T) {
 		},
 	)
 
+	environmentIn := &Environment{
+		TerraformVersion: "1.3.0",
+		Workspace:        "default",
+		Providers: map[string]*EnvironmentProvider{
+			"registry.terraform.io/hashicorp/boop": {
+				Version: "1.0.0",
+				Hashes:  []string{"fake:hello"},
+			},
+		},
+		Variables: map[string]*EnvironmentVariable{
+			"foo": {Value: json.RawMessage(`"bar"`)},
+			"secret": {
+				ValueSHA256: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			},
+		},
+	}
+
 	planFn := filepath.Join(t.TempDir(), "tfplan")
 
 	err = Create(planFn, CreateArgs{
@@ -92,6 +110,7 @@ func TestRoundtrip(t *testing.T) {
 		StateFile:            stateFileIn,
 		Plan:                 planIn,
 		DependencyLocks:      locksIn,
+		Environment:          environmentIn,
 	})
 	if err != nil {
 		t.Fatalf("failed to create plan file: %s", err)
@@ -163,4 +182,14 @@ func TestRoundtrip(t *testing.T) {
 			t.Errorf("provider locks did not survive round-trip\n%s", diff)
 		}
 	})
+
+	t.Run("ReadEnvironment", func(t *testing.T) {
+		environmentOut, err := pr.ReadEnvironment()
+		if err != nil {
+			t.Fatalf("failed to read run environment: %s", err)
+		}
+		if diff := cmp.Diff(environmentIn, environmentOut); diff != "" {
+			t.Errorf("run environment did not survive round-trip\n%s", diff)
+		}
+	})
 }
@@ -0,0 +1,97 @@
+package planfile
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// SignatureFilenameSuffix is appended to a plan file's path to produce the
+// path of its detached signature file.
+const SignatureFilenameSuffix = ".sig"
+
+// Sign creates a detached Ed25519 signature over the plan file at the given
+// path, writing it alongside the plan file with SignatureFilenameSuffix
+// appended to the filename.
+//
+// The signature covers the raw bytes of the plan file, so any later change
+// to the file invalidates it.
+func Sign(path string, key ed25519.PrivateKey) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read plan file: %s", err)
+	}
+
+	sig := ed25519.Sign(key, src)
+	enc := base64.StdEncoding.EncodeToString(sig) + "\n"
+	if err := ioutil.WriteFile(path+SignatureFilenameSuffix, []byte(enc), 0644); err != nil {
+		return fmt.Errorf("failed to write signature file: %s", err)
+	}
+	return nil
+}
+
+// Verify checks the detached signature created by Sign for the plan file at
+// the given path against the given public key, returning an error if the
+// signature file is missing or if the signature doesn't match the file's
+// current content.
+func Verify(path string, key ed25519.PublicKey) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read plan file: %s", err)
+	}
+
+	sigPath := path + SignatureFilenameSuffix
+	enc, err := ioutil.ReadFile(sigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("the plan file has no detached signature %s; sign the plan when creating it with \"terraform plan -out=... -sign-key=...\"", sigPath)
+		}
+		return fmt.Errorf("failed to read signature file: %s", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(enc)))
+	if err != nil {
+		return fmt.Errorf("invalid signature file %s: %s", sigPath, err)
+	}
+
+	if !ed25519.Verify(key, src, sig) {
+		return fmt.Errorf("the signature in %s is not valid for the current content of the plan file; either the plan file was modified after it was signed or it was signed with a different key", sigPath)
+	}
+	return nil
+}
+
+// ParseSigningKey interprets the given key material as an Ed25519 private
+// key for use with Sign. The material must be the base64 encoding of either
+// a 32-byte Ed25519 seed or a 64-byte Ed25519 private key, optionally
+// surrounded by whitespace.
+func ParseSigningKey(src []byte) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(src)))
+	if err != nil {
+		return nil, fmt.Errorf("signing key must be base64-encoded: %s", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("signing key must decode to either a %d-byte Ed25519 seed or a %d-byte Ed25519 private key, but has %d bytes", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// ParseVerificationKey interprets the given key material as an Ed25519
+// public key for use with Verify. The material must be the base64 encoding
+// of a 32-byte Ed25519 public key, optionally surrounded by whitespace.
+func ParseVerificationKey(src []byte) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(src)))
+	if err != nil {
+		return nil, fmt.Errorf("verification key must be base64-encoded: %s", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("verification key must decode to a %d-byte Ed25519 public key, but has %d bytes", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
@@ -0,0 +1,99 @@
+package planfile
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.tfplan")
+	if err := ioutil.WriteFile(path, []byte("not a real plan, but signable"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Verify(path, pub); err == nil {
+		t.Fatal("Verify succeeded without a signature; want error")
+	} else if !strings.Contains(err.Error(), "no detached signature") {
+		t.Fatalf("wrong error for missing signature: %s", err)
+	}
+
+	if err := Sign(path, priv); err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	if _, err := os.Stat(path + SignatureFilenameSuffix); err != nil {
+		t.Fatalf("missing signature file: %s", err)
+	}
+
+	if err := Verify(path, pub); err != nil {
+		t.Fatalf("Verify failed: %s", err)
+	}
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(path, otherPub); err == nil {
+		t.Fatal("Verify succeeded with the wrong key; want error")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(path, pub); err == nil {
+		t.Fatal("Verify succeeded after tampering; want error")
+	} else if !strings.Contains(err.Error(), "not valid") {
+		t.Fatalf("wrong error for tampered plan: %s", err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A base64-encoded seed and a base64-encoded full private key must both
+	// produce the same signing key.
+	seedSrc := base64.StdEncoding.EncodeToString(priv.Seed()) + "\n"
+	fromSeed, err := ParseSigningKey([]byte(seedSrc))
+	if err != nil {
+		t.Fatalf("ParseSigningKey failed for seed: %s", err)
+	}
+	fullSrc := base64.StdEncoding.EncodeToString(priv)
+	fromFull, err := ParseSigningKey([]byte(fullSrc))
+	if err != nil {
+		t.Fatalf("ParseSigningKey failed for full key: %s", err)
+	}
+	if !fromSeed.Equal(fromFull) {
+		t.Fatal("seed and full key forms produced different keys")
+	}
+
+	gotPub, err := ParseVerificationKey([]byte(base64.StdEncoding.EncodeToString(pub)))
+	if err != nil {
+		t.Fatalf("ParseVerificationKey failed: %s", err)
+	}
+	msg := []byte("message")
+	if !ed25519.Verify(gotPub, msg, ed25519.Sign(fromSeed, msg)) {
+		t.Fatal("parsed keys don't form a valid pair")
+	}
+
+	if _, err := ParseSigningKey([]byte("not base64!")); err == nil {
+		t.Fatal("ParseSigningKey succeeded with invalid base64; want error")
+	}
+	if _, err := ParseSigningKey([]byte(base64.StdEncoding.EncodeToString([]byte("short")))); err == nil {
+		t.Fatal("ParseSigningKey succeeded with a short key; want error")
+	}
+	if _, err := ParseVerificationKey([]byte(base64.StdEncoding.EncodeToString([]byte("short")))); err == nil {
+		t.Fatal("ParseVerificationKey succeeded with a short key; want error")
+	}
+}
@@ -2,6 +2,7 @@ package planfile
 
 import (
 	"archive/zip"
+	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -39,6 +40,10 @@ type CreateArgs struct {
 	// checked prior to creating the plan, so we can make sure that all of the
 	// same dependencies are still available when applying the plan.
 	DependencyLocks *depsfile.Locks
+
+	// Environment optionally records the run environment the plan was
+	// created in, for later auditing and environment-change detection.
+	Environment *Environment
 }
 
 // Create creates a new plan file with the given filename, overwriting any
@@ -135,5 +140,26 @@ func Create(filename string, args CreateArgs) error {
 		}
 	}
 
+	// tfmeta.json file, containing the run environment record
+	if args.Environment != nil { // (this was a later addition, so not all callers set it, but main callers should)
+		src, err := json.MarshalIndent(args.Environment, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize run environment record: %s", err)
+		}
+
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     environmentFilename,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create embedded run environment record: %s", err)
+		}
+		_, err = w.Write(src)
+		if err != nil {
+			return fmt.Errorf("failed to write embedded run environment record: %s", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// GRPCClientConfig adjusts the gRPC channel between Terraform and its plugin
+// processes, from the plugin_grpc_* settings in the CLI configuration. The
+// zero value leaves the channel with its default behavior.
+type GRPCClientConfig struct {
+	// Compression enables gzip compression of the messages exchanged with
+	// the plugin, which reduces the cost of transferring large provider
+	// schemas at the expense of some CPU time. The plugin's gRPC server
+	// must also support gzip, which servers built with the official plugin
+	// SDKs do.
+	Compression bool
+
+	// MaxRecvSize and MaxSendSize override the maximum size in bytes of the
+	// messages Terraform will receive from and send to the plugin. Zero
+	// leaves the corresponding gRPC default in effect. Providers with very
+	// large schemas can exceed the default receive limit.
+	MaxRecvSize int
+	MaxSendSize int
+}
+
+// DialOptions returns the gRPC dial options implied by the configuration,
+// for inclusion in a go-plugin ClientConfig.
+func (c GRPCClientConfig) DialOptions() []grpc.DialOption {
+	var callOpts []grpc.CallOption
+	if c.Compression {
+		callOpts = append(callOpts, grpc.UseCompressor(gzip.Name))
+	}
+	if c.MaxRecvSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(c.MaxRecvSize))
+	}
+	if c.MaxSendSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(c.MaxSendSize))
+	}
+	if len(callOpts) == 0 {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithDefaultCallOptions(callOpts...)}
+}
@@ -0,0 +1,22 @@
+package plugin
+
+import (
+	"testing"
+)
+
+func TestGRPCClientConfigDialOptions(t *testing.T) {
+	// The individual dial options are opaque, so we can only check whether
+	// the configuration produces any at all.
+	if got := (GRPCClientConfig{}).DialOptions(); got != nil {
+		t.Errorf("zero config produced dial options: %#v", got)
+	}
+
+	cfg := GRPCClientConfig{
+		Compression: true,
+		MaxRecvSize: 64 * 1024 * 1024,
+		MaxSendSize: 64 * 1024 * 1024,
+	}
+	if got := cfg.DialOptions(); len(got) != 1 {
+		t.Errorf("wrong number of dial options %d; want 1", len(got))
+	}
+}
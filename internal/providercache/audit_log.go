@@ -0,0 +1,155 @@
+package providercache
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/getproviders"
+)
+
+// AuditLog records provider installation decisions as JSON lines appended to
+// a file, so that operators can keep an audit trail of exactly which provider
+// packages were installed, from where, and whether their checksums and
+// signatures were verified.
+//
+// An AuditLog is safe for concurrent use. The log file is opened lazily on
+// the first write, so merely configuring an audit log does not create the
+// file. Write failures are reported to the normal diagnostic log rather than
+// failing the installation operation, since the installation itself is still
+// sound even if we can't record it.
+//
+// All methods are safe to call on a nil *AuditLog, in which case they do
+// nothing, so callers don't need to guard each call site.
+type AuditLog struct {
+	path string
+
+	mu     sync.Mutex
+	file   *os.File
+	failed bool
+}
+
+// NewAuditLog returns an audit log that will append JSON lines to the file
+// at the given path, creating it if necessary.
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// auditLogEntry is the wire format of one line of the audit log. Fields that
+// don't apply to a particular event are omitted.
+type auditLogEntry struct {
+	Time       string   `json:"time"`
+	Event      string   `json:"event"`
+	Provider   string   `json:"provider,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	CacheDir   string   `json:"cache_dir,omitempty"`
+	LocalDir   string   `json:"local_dir,omitempty"`
+	AuthResult string   `json:"auth_result,omitempty"`
+	KeyID      string   `json:"key_id,omitempty"`
+	Hashes     []string `json:"hashes,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func (l *AuditLog) write(entry auditLogEntry) {
+	if l == nil {
+		return
+	}
+	entry.Time = time.Now().UTC().Format(time.RFC3339)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failed {
+		return
+	}
+	if l.file == nil {
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Printf("[ERROR] can't open provider installation audit log: %s", err)
+			l.failed = true
+			return
+		}
+		l.file = f
+	}
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		// Should never happen, since our entry type contains only
+		// marshalable fields.
+		log.Printf("[ERROR] can't encode provider installation audit log entry: %s", err)
+		return
+	}
+	buf = append(buf, '\n')
+	if _, err := l.file.Write(buf); err != nil {
+		log.Printf("[ERROR] can't write to provider installation audit log: %s", err)
+		l.failed = true
+	}
+}
+
+// alreadyInstalled records that a suitable package for the given provider
+// version was already present in the target directory and still matches the
+// hashes recorded in the dependency lock file.
+func (l *AuditLog) alreadyInstalled(provider addrs.Provider, version getproviders.Version) {
+	l.write(auditLogEntry{
+		Event:    "already_installed",
+		Provider: provider.String(),
+		Version:  version.String(),
+	})
+}
+
+// linkFromCache records that the given provider version was linked into the
+// target directory from the shared global cache directory rather than being
+// fetched from its origin, along with the hashes that will be recorded for
+// it in the dependency lock file.
+func (l *AuditLog) linkFromCache(provider addrs.Provider, version getproviders.Version, cacheDir, localDir string, hashes []getproviders.Hash) {
+	l.write(auditLogEntry{
+		Event:    "link_from_cache",
+		Provider: provider.String(),
+		Version:  version.String(),
+		CacheDir: cacheDir,
+		LocalDir: localDir,
+		Hashes:   hashStrings(hashes),
+	})
+}
+
+// fetched records that the given provider version was fetched from the given
+// package location, along with the outcome of package authentication and the
+// hashes that will be recorded for it in the dependency lock file.
+func (l *AuditLog) fetched(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation, localDir string, authResult *getproviders.PackageAuthenticationResult, hashes []getproviders.Hash) {
+	entry := auditLogEntry{
+		Event:    "fetched",
+		Provider: provider.String(),
+		Version:  version.String(),
+		Location: location.String(),
+		LocalDir: localDir,
+		Hashes:   hashStrings(hashes),
+	}
+	if authResult != nil {
+		entry.AuthResult = authResult.String()
+		entry.KeyID = authResult.KeyID
+	}
+	l.write(entry)
+}
+
+// installFailed records that installation of the given provider failed
+// altogether, after any retries and fallbacks.
+func (l *AuditLog) installFailed(provider addrs.Provider, err error) {
+	l.write(auditLogEntry{
+		Event:    "install_failed",
+		Provider: provider.String(),
+		Error:    err.Error(),
+	})
+}
+
+func hashStrings(hashes []getproviders.Hash) []string {
+	if len(hashes) == 0 {
+		return nil
+	}
+	ret := make([]string, len(hashes))
+	for i, hash := range hashes {
+		ret[i] = string(hash)
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+package providercache
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/depsfile"
+	"github.com/hashicorp/terraform/internal/getproviders"
+)
+
+func TestAuditLog(t *testing.T) {
+	source := getproviders.NewFilesystemMirrorSource("testdata/cachedir")
+
+	platform := getproviders.Platform{OS: "linux", Arch: "amd64"}
+	dir := NewDirWithPlatform(t.TempDir(), platform)
+	installer := NewInstaller(dir, source)
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	installer.SetAuditLog(NewAuditLog(logPath))
+
+	provider := addrs.MustParseProviderSourceString("null")
+	reqs := getproviders.Requirements{
+		provider: getproviders.MustParseVersionConstraints("2.0.0"),
+	}
+	ctx := context.TODO()
+
+	locks, err := installer.EnsureProviderVersions(ctx, depsfile.NewLocks(), reqs, InstallNewProvidersOnly)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// A second run with the resulting locks should find the provider already
+	// installed.
+	if _, err := installer.EnsureProviderVersions(ctx, locks, reqs, InstallNewProvidersOnly); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// And a failed installation should be recorded too.
+	badReqs := getproviders.Requirements{
+		addrs.MustParseProviderSourceString("missing/executable"): getproviders.MustParseVersionConstraints("2.0.0"),
+	}
+	if _, err := installer.EnsureProviderVersions(ctx, depsfile.NewLocks(), badReqs, InstallNewProvidersOnly); err == nil {
+		t.Fatal("expected error from missing executable")
+	}
+
+	buf, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 audit log entries, got %d:\n%s", len(lines), buf)
+	}
+
+	var entries []auditLogEntry
+	for _, line := range lines {
+		var entry auditLogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid audit log line %q: %s", line, err)
+		}
+		if entry.Time == "" {
+			t.Errorf("audit log entry has no timestamp: %s", line)
+		}
+		entries = append(entries, entry)
+	}
+
+	if entries[0].Event != "fetched" || entries[0].Provider != "registry.terraform.io/hashicorp/null" || entries[0].Version != "2.0.0" {
+		t.Errorf("wrong first entry: %#v", entries[0])
+	}
+	if !strings.Contains(entries[0].Location, "testdata/cachedir") {
+		t.Errorf("first entry does not record the package location: %#v", entries[0])
+	}
+	wantHash := string(getproviders.HashScheme1.New("qjsREM4DqEWECD43FcPqddZ9oxCG+IaMTxvWPciS05g="))
+	if len(entries[0].Hashes) != 1 || entries[0].Hashes[0] != wantHash {
+		t.Errorf("wrong hashes in first entry: %#v", entries[0].Hashes)
+	}
+
+	if entries[1].Event != "already_installed" || entries[1].Provider != "registry.terraform.io/hashicorp/null" || entries[1].Version != "2.0.0" {
+		t.Errorf("wrong second entry: %#v", entries[1])
+	}
+
+	if entries[2].Event != "install_failed" || entries[2].Provider != "registry.terraform.io/missing/executable" {
+		t.Errorf("wrong third entry: %#v", entries[2])
+	}
+	if !strings.Contains(entries[2].Error, "provider binary not found") {
+		t.Errorf("third entry does not record the error: %#v", entries[2])
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
+	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/getproviders"
 )
 
@@ -28,6 +30,16 @@ func (d *Dir) InstallPackage(ctx context.Context, meta getproviders.PackageMeta,
 	// incorporate any changes we make here.
 	d.metaCache = nil
 
+	// Take an inter-process lock on the package directory so that concurrent
+	// Terraform processes sharing this cache directory (such as via
+	// TF_PLUGIN_CACHE_DIR) take turns installing this package rather than
+	// trampling one another's work.
+	unlock, err := lockPackageDir(newPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	log.Printf("[TRACE] providercache.Dir.InstallPackage: installing %s v%s from %s", meta.Provider, meta.Version, meta.Location)
 	switch meta.Location.(type) {
 	case getproviders.PackageHTTPURL:
@@ -83,6 +95,18 @@ func (d *Dir) LinkFromOtherCache(entry *CachedProvider, allowedHashes []getprovi
 	// incorporate any changes we make here.
 	d.metaCache = nil
 
+	// Take an inter-process lock on our own target directory, in case the
+	// receiver is itself a cache directory shared with other processes. We
+	// intentionally don't lock the source package, because the source may be
+	// read-only from our perspective; writers replace packages atomically
+	// with a rename, so the worst a concurrent replacement can do to us is
+	// cause the hash check above to fail.
+	unlock, err := lockPackageDir(newPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// We re-use the process of installing from a local directory here, because
 	// the two operations are fundamentally the same: symlink if possible,
 	// deep-copy otherwise.
@@ -102,6 +126,59 @@ func (d *Dir) LinkFromOtherCache(entry *CachedProvider, allowedHashes []getprovi
 	}
 	// No further hash check here because we already checked the hash
 	// of the source directory above.
-	_, err := installFromLocalDir(context.TODO(), meta, newPath, nil)
+	_, err = installFromLocalDir(context.TODO(), meta, newPath, nil)
 	return err
 }
+
+// Prune removes from the cache directory any package for one of the providers
+// given in "retain" whose version is not one of that provider's retained
+// versions, and returns descriptions of the packages it removed.
+//
+// Providers that don't appear in "retain" at all are left untouched, because
+// a cache directory can be shared between many configurations and the caller
+// typically only knows which versions a single configuration requires.
+// Only packages for the receiver's target platform are considered.
+//
+// Each removal holds the same inter-process lock used during installation, so
+// pruning is safe to run concurrently with installations by other processes
+// sharing the same cache directory.
+func (d *Dir) Prune(retain map[addrs.Provider][]getproviders.Version) ([]CachedProvider, error) {
+	var removed []CachedProvider
+
+	for provider, entries := range d.AllAvailablePackages() {
+		retainVersions, relevant := retain[provider]
+		if !relevant {
+			continue
+		}
+		for _, entry := range entries {
+			keep := false
+			for _, version := range retainVersions {
+				if entry.Version == version {
+					keep = true
+					break
+				}
+			}
+			if keep {
+				continue
+			}
+
+			unlock, err := lockPackageDir(entry.PackageDir)
+			if err != nil {
+				return removed, err
+			}
+			log.Printf("[TRACE] providercache.Dir.Prune: removing %s v%s from %s", entry.Provider, entry.Version, entry.PackageDir)
+			err = os.RemoveAll(entry.PackageDir)
+			unlock()
+			if err != nil {
+				return removed, fmt.Errorf("failed to remove %s: %s", entry.PackageDir, err)
+			}
+			removed = append(removed, entry)
+		}
+	}
+
+	// Invalidate our metaCache so that subsequent read calls will re-scan to
+	// incorporate any changes we made here.
+	d.metaCache = nil
+
+	return removed, nil
+}
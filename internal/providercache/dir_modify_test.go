@@ -2,6 +2,7 @@ package providercache
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -65,6 +66,80 @@ func TestInstallPackage(t *testing.T) {
 	}
 }
 
+func TestDirPrune(t *testing.T) {
+	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linuxPlatform := getproviders.Platform{
+		OS:   "linux",
+		Arch: "amd64",
+	}
+	nullProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "null",
+	)
+	happycloudProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "happycloud",
+	)
+
+	// We'll construct some fake unpacked packages directly, because Prune
+	// cares only about which package directories exist.
+	for _, dir := range []string{
+		"registry.terraform.io/hashicorp/null/2.0.0/linux_amd64",
+		"registry.terraform.io/hashicorp/null/2.1.0/linux_amd64",
+		"registry.terraform.io/hashicorp/happycloud/1.0.0/linux_amd64",
+	} {
+		err := os.MkdirAll(filepath.Join(tmpDirPath, dir), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tmpDir := NewDirWithPlatform(tmpDirPath, linuxPlatform)
+
+	// We retain only null 2.1.0; happycloud isn't mentioned at all and so
+	// must survive even though its version isn't retained anywhere.
+	removed, err := tmpDir.Prune(map[addrs.Provider][]getproviders.Version{
+		nullProvider: {versions.MustParseVersion("2.1.0")},
+	})
+	if err != nil {
+		t.Fatalf("Prune failed: %s", err)
+	}
+
+	wantRemoved := []CachedProvider{
+		{
+			Provider:   nullProvider,
+			Version:    versions.MustParseVersion("2.0.0"),
+			PackageDir: tmpDirPath + "/registry.terraform.io/hashicorp/null/2.0.0/linux_amd64",
+		},
+	}
+	if diff := cmp.Diff(wantRemoved, removed); diff != "" {
+		t.Errorf("wrong removed packages\n%s", diff)
+	}
+
+	got := tmpDir.AllAvailablePackages()
+	want := map[addrs.Provider][]CachedProvider{
+		nullProvider: {
+			CachedProvider{
+				Provider:   nullProvider,
+				Version:    versions.MustParseVersion("2.1.0"),
+				PackageDir: tmpDirPath + "/registry.terraform.io/hashicorp/null/2.1.0/linux_amd64",
+			},
+		},
+		happycloudProvider: {
+			CachedProvider{
+				Provider:   happycloudProvider,
+				Version:    versions.MustParseVersion("1.0.0"),
+				PackageDir: tmpDirPath + "/registry.terraform.io/hashicorp/happycloud/1.0.0/linux_amd64",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong cache contents after prune\n%s", diff)
+	}
+}
+
 func TestLinkFromOtherCache(t *testing.T) {
 	srcDirPath := "testdata/cachedir"
 	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
@@ -47,6 +47,10 @@ type Installer struct {
 	// lifecycle for, and therefore does not need to worry about the
 	// installation of.
 	unmanagedProviderTypes map[addrs.Provider]struct{}
+
+	// auditLog is an optional audit trail that, if set, records each
+	// installation decision the installer makes.
+	auditLog *AuditLog
 }
 
 // NewInstaller constructs and returns a new installer with the given target
@@ -137,6 +141,15 @@ func (i *Installer) SetUnmanagedProviderTypes(types map[addrs.Provider]struct{})
 	i.unmanagedProviderTypes = types
 }
 
+// SetAuditLog tells the receiver to record its installation decisions in the
+// given audit log, including where each package came from and whether its
+// checksums and signatures were verified.
+//
+// The default, if this method isn't called, is to keep no audit trail.
+func (i *Installer) SetAuditLog(auditLog *AuditLog) {
+	i.auditLog = auditLog
+}
+
 // EnsureProviderVersions compares the given provider requirements with what
 // is already available in the installer's target directory and then takes
 // appropriate installation actions to ensure that suitable packages
@@ -338,8 +351,16 @@ NeedProvider:
 					if cb := evts.ProviderAlreadyInstalled; cb != nil {
 						cb(provider, version)
 					}
+					i.auditLog.alreadyInstalled(provider, version)
 					continue
 				}
+				// The package on disk no longer matches the lock file, so
+				// we'll fall through and reinstall it below, but we'll
+				// announce that so the user can see that the package was
+				// replaced rather than reused.
+				if cb := evts.ProviderInstalledPackageInvalid; cb != nil {
+					cb(provider, version)
+				}
 			}
 		}
 
@@ -429,6 +450,7 @@ NeedProvider:
 				if cb := evts.LinkFromCacheSuccess; cb != nil {
 					cb(provider, version, new.PackageDir)
 				}
+				i.auditLog.linkFromCache(provider, version, i.globalCacheDir.baseDir, new.PackageDir, newHashes)
 				continue // Don't need to do full install, then.
 			}
 		}
@@ -454,7 +476,7 @@ NeedProvider:
 		// either directly into our target directory or via the global cache
 		// directory.
 		if cb := evts.FetchPackageBegin; cb != nil {
-			cb(provider, version, meta.Location)
+			cb(provider, version, meta.Location, meta.SourceKind)
 		}
 		var installTo, linkTo *Dir
 		if i.globalCacheDir != nil {
@@ -553,8 +575,9 @@ NeedProvider:
 		locks.SetProvider(provider, version, reqs[provider], newHashes)
 
 		if cb := evts.FetchPackageSuccess; cb != nil {
-			cb(provider, version, new.PackageDir, authResult)
+			cb(provider, version, new.PackageDir, authResult, len(preferredHashes) > 0)
 		}
+		i.auditLog.fetched(provider, version, meta.Location, new.PackageDir, authResult, newHashes)
 	}
 
 	// Emit final event for fetching if any were successfully fetched
@@ -575,6 +598,9 @@ NeedProvider:
 	}
 
 	if len(errs) > 0 {
+		for provider, err := range errs {
+			i.auditLog.installFailed(provider, err)
+		}
 		return locks, InstallerError{
 			ProviderErrors: errs,
 		}
@@ -40,6 +40,16 @@ type InstallerEvents struct {
 	// available version.
 	ProviderAlreadyInstalled func(provider addrs.Provider, selectedVersion getproviders.Version)
 
+	// ProviderInstalledPackageInvalid is called for any provider whose
+	// selected version is already present in the local provider cache
+	// directory but no longer matches any of the checksums recorded for it
+	// in the dependency lock file, and which will therefore be reinstalled
+	// from the configured installation methods.
+	//
+	// This usually indicates that the cache directory was modified by
+	// something other than Terraform, such as a sync tool truncating files.
+	ProviderInstalledPackageInvalid func(provider addrs.Provider, selectedVersion getproviders.Version)
+
 	// The BuiltInProvider... family of events describe the outcome for any
 	// requested providers that are built in to Terraform. Only one of these
 	// methods will be called for each such provider, and no other method
@@ -101,9 +111,17 @@ type InstallerEvents struct {
 	//
 	// The Query, Begin, Success, and Failure events will each occur only once
 	// per distinct provider.
+	//
+	// FetchPackageBegin additionally reports what kind of source the
+	// package is coming from, so a UI can distinguish e.g. a provider's
+	// origin registry from a network mirror when both would deliver the
+	// package from an HTTP URL. FetchPackageSuccess reports whether the
+	// fetched package was verified against hashes already recorded in the
+	// dependency lock file; false means the hashes were being recorded
+	// for the first time.
 	FetchPackageMeta    func(provider addrs.Provider, version getproviders.Version) // fetching metadata prior to real download
-	FetchPackageBegin   func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation)
-	FetchPackageSuccess func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult)
+	FetchPackageBegin   func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation, sourceKind getproviders.SourceKind)
+	FetchPackageSuccess func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult, hashesVerified bool)
 	FetchPackageFailure func(provider addrs.Provider, version getproviders.Version, err error)
 
 	// The ProvidersFetched event is called after all fetch operations if at
@@ -52,6 +52,13 @@ func installerLogEventsForTests(into chan<- *testInstallerEventLogItem) *Install
 				Args:     selectedVersion,
 			}
 		},
+		ProviderInstalledPackageInvalid: func(provider addrs.Provider, selectedVersion getproviders.Version) {
+			into <- &testInstallerEventLogItem{
+				Event:    "ProviderInstalledPackageInvalid",
+				Provider: provider,
+				Args:     selectedVersion,
+			}
+		},
 		BuiltInProviderAvailable: func(provider addrs.Provider) {
 			into <- &testInstallerEventLogItem{
 				Event:    "BuiltInProviderAvailable",
@@ -133,7 +140,7 @@ func installerLogEventsForTests(into chan<- *testInstallerEventLogItem) *Install
 				Args:     version.String(),
 			}
 		},
-		FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation) {
+		FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation, sourceKind getproviders.SourceKind) {
 			into <- &testInstallerEventLogItem{
 				Event:    "FetchPackageBegin",
 				Provider: provider,
@@ -143,7 +150,7 @@ func installerLogEventsForTests(into chan<- *testInstallerEventLogItem) *Install
 				}{version.String(), location},
 			}
 		},
-		FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult) {
+		FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult, hashesVerified bool) {
 			into <- &testInstallerEventLogItem{
 				Event:    "FetchPackageSuccess",
 				Provider: provider,
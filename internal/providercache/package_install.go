@@ -3,8 +3,7 @@ package providercache
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"log"
 	"os"
 	"path/filepath"
 
@@ -22,55 +21,44 @@ import (
 // specific protocol and set of expectations.)
 var unzip = getter.ZipDecompressor{}
 
+// maxHTTPPackageRetries is how many additional attempts we make after a
+// package download fails partway through.
+const maxHTTPPackageRetries = 2
+
 func installFromHTTPURL(ctx context.Context, meta getproviders.PackageMeta, targetDir string, allowedHashes []getproviders.Hash) (*getproviders.PackageAuthenticationResult, error) {
 	url := meta.Location.String()
 
 	// When we're installing from an HTTP URL we expect the URL to refer to
-	// a zip file. We'll fetch that into a temporary file here and then
-	// delegate to installFromLocalArchive below to actually extract it.
+	// a zip file. We'll fetch that into a local file here and then delegate
+	// to installFromLocalArchive below to actually extract it.
 	// (We're not using go-getter here because its HTTP getter has a bunch
 	// of extraneous functionality we don't need or want, like indirection
 	// through X-Terraform-Get header, attempting partial fetches for
 	// files that already exist, etc.)
+	//
+	// The archive is staged next to the target directory, rather than in an
+	// anonymous temporary file, so that DownloadPackageArchive can resume a
+	// download that an earlier interrupted run left behind.
 
-	httpClient := httpclient.New()
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("invalid provider download request: %s", err)
-	}
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		if ctx.Err() == context.Canceled {
-			// "context canceled" is not a user-friendly error message,
-			// so we'll return a more appropriate one here.
-			return nil, fmt.Errorf("provider download was interrupted")
-		}
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unsuccessful request to %s: %s", url, resp.Status)
-	}
-
-	f, err := ioutil.TempFile("", "terraform-provider")
-	if err != nil {
-		return nil, fmt.Errorf("failed to open temporary file to download from %s", url)
-	}
-	defer f.Close()
-	defer os.Remove(f.Name())
+	archiveFilename := filepath.Join(filepath.Dir(targetDir), "."+filepath.Base(targetDir)+".zip")
 
-	// We'll borrow go-getter's "cancelable copy" implementation here so that
-	// the download can potentially be interrupted partway through.
-	n, err := getter.Copy(ctx, f, resp.Body)
-	if err == nil && n < resp.ContentLength {
-		err = fmt.Errorf("incorrect response size: expected %d bytes, but got %d bytes", resp.ContentLength, n)
+	// Because the download can resume after an interruption, a retry here
+	// picks up where the failed attempt left off rather than starting the
+	// whole transfer again, which matters for the larger provider packages
+	// on an unreliable network.
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = getproviders.DownloadPackageArchive(ctx, httpclient.New(), url, archiveFilename)
+		if err == nil || attempt >= maxHTTPPackageRetries || ctx.Err() != nil {
+			break
+		}
+		log.Printf("[WARN] failed to download provider package from %s: %s; will retry", url, err)
 	}
 	if err != nil {
 		return nil, err
 	}
+	defer os.Remove(archiveFilename)
 
-	archiveFilename := f.Name()
 	localLocation := getproviders.PackageLocalArchive(archiveFilename)
 
 	var authResult *getproviders.PackageAuthenticationResult
@@ -125,11 +113,30 @@ func installFromLocalArchive(ctx context.Context, meta getproviders.PackageMeta,
 
 	filename := meta.Location.String()
 
-	err := unzip.Decompress(targetDir, filename, true, 0000)
+	// We extract into a staging directory next to the target and then move
+	// the completed result into place, so that an interrupted extraction can
+	// never leave a partial package where the cache expects a complete one.
+	// The dot prefix keeps the staging name from ever being mistaken for a
+	// real platform directory, and "terraform init" removes any staging
+	// directories left behind by an interrupted earlier run.
+	stageDir := filepath.Join(filepath.Dir(targetDir), "."+filepath.Base(targetDir)+".partial")
+	if err := os.RemoveAll(stageDir); err != nil {
+		return authResult, fmt.Errorf("failed to remove stale staging directory %s: %s", stageDir, err)
+	}
+
+	err := unzip.Decompress(stageDir, filename, true, 0000)
 	if err != nil {
+		os.RemoveAll(stageDir)
 		return authResult, err
 	}
 
+	if err := os.RemoveAll(targetDir); err != nil {
+		return authResult, fmt.Errorf("failed to remove existing directory %s: %s", targetDir, err)
+	}
+	if err := os.Rename(stageDir, targetDir); err != nil {
+		return authResult, fmt.Errorf("failed to move %s into place at %s: %s", stageDir, targetDir, err)
+	}
+
 	return authResult, nil
 }
 
@@ -0,0 +1,51 @@
+package providercache
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// lockPackageDir acquires an exclusive inter-process lock associated with the
+// package directory at the given path, so that concurrent Terraform processes
+// sharing a cache directory take turns installing into or removing a
+// particular package directory instead of corrupting one another's work.
+//
+// The lock is represented by a separate dot-prefixed file next to the package
+// directory, rather than by the directory itself, because installation
+// atomically replaces the directory and so a lock on the directory would not
+// survive the replacement. The dot prefix keeps the lock file from ever being
+// mistaken for a package directory when scanning the cache.
+//
+// If another process currently holds the lock then this function blocks until
+// that process releases it. On success the caller must call the returned
+// unlock function once its modifications are complete. The lock file
+// intentionally remains on disk after unlocking, because deleting it would
+// race with another process that has already opened it.
+func lockPackageDir(targetDir string) (unlock func() error, err error) {
+	parentDir := filepath.Dir(targetDir)
+	if err := os.MkdirAll(parentDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %s", parentDir, err)
+	}
+
+	lockFilename := filepath.Join(parentDir, "."+filepath.Base(targetDir)+".lock")
+	f, err := os.OpenFile(lockFilename, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %s", lockFilename, err)
+	}
+
+	log.Printf("[TRACE] providercache: acquiring lock on %s", lockFilename)
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock %s: %s", lockFilename, err)
+	}
+	log.Printf("[TRACE] providercache: acquired lock on %s", lockFilename)
+
+	return func() error {
+		log.Printf("[TRACE] providercache: releasing lock on %s", lockFilename)
+		err := unlockFile(f)
+		f.Close()
+		return err
+	}, nil
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+// +build !windows
+
+package providercache
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// use fcntl POSIX locks for the most consistent behavior across platforms, and
+// hopefully some compatibility over NFS and CIFS.
+func lockFile(f *os.File) error {
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_WRLCK,
+		Whence: int16(io.SeekStart),
+		Start:  0,
+		Len:    0,
+	}
+
+	// We use F_SETLKW rather than F_SETLK so that we wait for any
+	// conflicting lock to be released, because concurrent installers
+	// sharing a cache directory should take turns rather than fail.
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLKW, flock)
+}
+
+func unlockFile(f *os.File) error {
+	flock := &syscall.Flock_t{
+		Type:   syscall.F_UNLCK,
+		Whence: int16(io.SeekStart),
+		Start:  0,
+		Len:    0,
+	}
+
+	return syscall.FcntlFlock(f.Fd(), syscall.F_SETLK, flock)
+}
@@ -83,6 +83,13 @@ type GetProviderSchemaResponse struct {
 	// DataSources maps the data source name to that data source's schema.
 	DataSources map[string]Schema
 
+	// OverrideSafeAttributes names the top-level attributes of the provider
+	// configuration that may be overridden on a per-resource basis using the
+	// experimental provider_override block. The plugin wire protocol does not
+	// yet carry this field, so for now only built-in providers and other
+	// providers running in-process can declare override-safe attributes.
+	OverrideSafeAttributes []string
+
 	// Diagnostics contains any warnings or errors from the method call.
 	Diagnostics tfdiags.Diagnostics
 }
@@ -152,6 +159,62 @@ type UpgradeResourceStateResponse struct {
 	Diagnostics tfdiags.Diagnostics
 }
 
+// ResourceStateMover is an optional extension of Interface for providers
+// that can translate the stored state of one of their resource types into
+// the schema of another of their resource types.
+//
+// Terraform uses this capability when a "moved" block declares a move
+// between two different resource types, so that providers which know how
+// to map one type onto another can preserve the existing remote object
+// rather than forcing it to be destroyed and recreated. Terraform calls
+// MoveResourceState on an unconfigured provider instance, before the plan
+// walk begins, in the same way as UpgradeResourceState.
+type ResourceStateMover interface {
+	Interface
+
+	// MoveResourceState translates the given stored state, created as
+	// SourceTypeName, into an equivalent state conforming to the current
+	// schema of TargetTypeName. Providers that cannot translate between
+	// the requested pair of types must return an error diagnostic.
+	MoveResourceState(MoveResourceStateRequest) MoveResourceStateResponse
+}
+
+type MoveResourceStateRequest struct {
+	// SourceTypeName is the resource type that the given state was most
+	// recently stored as.
+	SourceTypeName string
+
+	// SourceSchemaVersion is the version of the source resource type's
+	// schema that created the given state.
+	SourceSchemaVersion int64
+
+	// SourceStateJSON is the json state encoding of the object being moved.
+	// Because the source schema may belong to an older provider version,
+	// this contains only the raw data as stored in the state.
+	SourceStateJSON []byte
+
+	// SourcePrivate is the private state data stored alongside the object
+	// being moved.
+	SourcePrivate []byte
+
+	// TargetTypeName is the resource type whose current schema the
+	// translated state must conform to.
+	TargetTypeName string
+}
+
+type MoveResourceStateResponse struct {
+	// TargetState is the equivalent of the source state in the target
+	// resource type's current schema.
+	TargetState cty.Value
+
+	// TargetPrivate is the private state data to store alongside the moved
+	// object.
+	TargetPrivate []byte
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
 type ConfigureProviderRequest struct {
 	// Terraform version is the version string from the running instance of
 	// terraform. Providers can use TerraformVersion to verify compatibility,
@@ -27,6 +27,11 @@ type Schemas struct {
 	DataSources   map[string]*configschema.Block
 
 	ResourceTypeSchemaVersions map[string]uint64
+
+	// OverrideSafeAttributes is the set of provider configuration attributes
+	// that the provider has declared safe to override per-resource with the
+	// experimental provider_override block.
+	OverrideSafeAttributes []string
 }
 
 // SchemaForResourceType attempts to find a schema for the given mode and type.
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"log"
 
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/dag"
 	"github.com/hashicorp/terraform/internal/logging"
+	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/tfdiags"
 )
 
 // ApplyMoves modifies in-place the given state object so that any existing
@@ -18,21 +22,29 @@ import (
 // either the source or destination of a move to a MoveResult describing
 // what happened at that address.
 //
-// ApplyMoves does not have any error situations itself, and will instead just
-// ignore any unresolvable move statements. Validation of a set of moves is
-// a separate concern applied to the configuration, because validity of
-// moves is always dependent only on the configuration, not on the state.
+// ApplyMoves will just ignore any unresolvable move statements. Validation
+// of a set of moves is a separate concern applied to the configuration,
+// because validity of moves is always dependent only on the configuration,
+// not on the state.
+//
+// Moves between two different resource types require the relevant provider
+// to translate the stored state, so the given getProvider callback must
+// start an unconfigured instance of the requested provider, or return an
+// error if it cannot. Callers with no access to provider plugins may pass
+// a nil getProvider, in which case cross-type moves return error
+// diagnostics. Other moves have no error situations at all.
 //
 // ApplyMoves expects exclusive access to the given state while it's running.
 // Don't read or write any part of the state structure until ApplyMoves returns.
-func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
+func ApplyMoves(stmts []MoveStatement, state *states.State, getProvider func(addrs.Provider) (providers.Interface, error)) (MoveResults, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
 	ret := MoveResults{
 		Changes: make(map[addrs.UniqueKey]MoveSuccess),
 		Blocked: make(map[addrs.UniqueKey]MoveBlocked),
 	}
 
 	if len(stmts) == 0 {
-		return ret
+		return ret, diags
 	}
 
 	// The methodology here is to construct a small graph of all of the move
@@ -47,7 +59,7 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 	// separate validation step should detect this and return an error.
 	if diags := validateMoveStatementGraph(g); diags.HasErrors() {
 		log.Printf("[ERROR] ApplyMoves: %s", diags.ErrWithWarnings())
-		return ret
+		return ret, nil
 	}
 
 	// The graph must be reduced in order for ReverseDepthFirstWalk to work
@@ -65,7 +77,7 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 
 	if startNodes.Len() == 0 {
 		log.Println("[TRACE] refactoring.ApplyMoves: No 'moved' statements to consider in this configuration")
-		return ret
+		return ret, diags
 	}
 
 	log.Printf("[TRACE] refactoring.ApplyMoves: Processing 'moved' statements in the configuration\n%s", logging.Indent(g.String()))
@@ -154,6 +166,20 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 							continue
 						}
 
+						// If the two addresses are of different resource
+						// types then we must ask the provider to translate
+						// the stored state before we move it. If that
+						// fails then we'll leave the objects at their old
+						// addresses, since a failed translation has not
+						// modified them.
+						if rAddr.Resource.Type != newAddr.Resource.Type {
+							moreDiags := moveResourceStateType(rs, newAddr.Resource.Type, getProvider)
+							diags = diags.Append(moreDiags)
+							if moreDiags.HasErrors() {
+								continue
+							}
+						}
+
 						for key := range rs.Instances {
 							oldInst := rAddr.Instance(key)
 							newInst := newAddr.Instance(key)
@@ -176,6 +202,19 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 								continue
 							}
 
+							// As with whole-resource moves above, a move
+							// between two different resource types needs
+							// the provider to translate the stored state
+							// first, and a failed translation leaves the
+							// object at its old address.
+							if iAddr.Resource.Resource.Type != newAddr.Resource.Resource.Type {
+								moreDiags := moveInstanceStateType(rs, key, newAddr.Resource.Resource.Type, getProvider)
+								diags = diags.Append(moreDiags)
+								if moreDiags.HasErrors() {
+									continue
+								}
+							}
+
 							recordOldAddr(iAddr, newAddr)
 
 							state.MoveAbsResourceInstance(iAddr, newAddr)
@@ -191,7 +230,138 @@ func ApplyMoves(stmts []MoveStatement, state *states.State) MoveResults {
 		return nil
 	})
 
-	return ret
+	return ret, diags
+}
+
+// moveResourceStateType asks the given resource's provider to translate the
+// stored state of all of the resource's instances into the schema of a
+// different resource type, in support of "moved" statements whose two
+// addresses are of different resource types.
+func moveResourceStateType(rs *states.Resource, targetType string, getProvider func(addrs.Provider) (providers.Interface, error)) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for key := range rs.Instances {
+		diags = diags.Append(moveInstanceStateType(rs, key, targetType, getProvider))
+	}
+	return diags
+}
+
+// moveInstanceStateType asks the given resource's provider to translate the
+// stored state of one of the resource's instances into the schema of a
+// different resource type. On success the instance's objects are replaced
+// in-place; on failure the instance is left unmodified and error
+// diagnostics describe what went wrong.
+func moveInstanceStateType(rs *states.Resource, key addrs.InstanceKey, targetType string, getProvider func(addrs.Provider) (providers.Interface, error)) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	sourceType := rs.Addr.Resource.Type
+	instAddr := rs.Addr.Instance(key)
+	providerAddr := rs.ProviderConfig.Provider
+
+	if getProvider == nil {
+		// Callers with no way to start provider plugins pass a nil
+		// getProvider, in which case cross-type moves are wholly
+		// unsupported.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported move between resource types",
+			fmt.Sprintf("Cannot move %s to type %s, because provider plugins are not available in this context.", instAddr, targetType),
+		))
+		return diags
+	}
+
+	provider, err := getProvider(providerAddr)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to move between resource types",
+			fmt.Sprintf("Cannot move %s to type %s: failed to start provider %s: %s.", instAddr, targetType, providerAddr, err),
+		))
+		return diags
+	}
+	mover, ok := provider.(providers.ResourceStateMover)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported move between resource types",
+			fmt.Sprintf("Cannot move %s to type %s, because provider %s does not support translating state between resource types.", instAddr, targetType, providerAddr),
+		))
+		return diags
+	}
+
+	schema, ok := provider.GetProviderSchema().ResourceTypes[targetType]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported move between resource types",
+			fmt.Sprintf("Cannot move %s to type %s, because provider %s has no resource type of that name.", instAddr, targetType, providerAddr),
+		))
+		return diags
+	}
+
+	translate := func(os *states.ResourceInstanceObjectSrc) (*states.ResourceInstanceObjectSrc, tfdiags.Diagnostics) {
+		var diags tfdiags.Diagnostics
+		if os.AttrsJSON == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Unsupported move between resource types",
+				fmt.Sprintf("Cannot move %s to type %s, because its state uses the legacy flatmap encoding from Terraform v0.11 or earlier. Apply at least once with the current resource type before moving it.", instAddr, targetType),
+			))
+			return nil, diags
+		}
+		resp := mover.MoveResourceState(providers.MoveResourceStateRequest{
+			SourceTypeName:      sourceType,
+			SourceSchemaVersion: int64(os.SchemaVersion),
+			SourceStateJSON:     os.AttrsJSON,
+			SourcePrivate:       os.Private,
+			TargetTypeName:      targetType,
+		})
+		diags = diags.Append(resp.Diagnostics)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		attrs, err := ctyjson.Marshal(resp.TargetState, schema.Block.ImpliedType())
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid resource move result",
+				fmt.Sprintf("Provider %s returned an invalid state while moving %s to type %s: %s.\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.", providerAddr, instAddr, targetType, err),
+			))
+			return nil, diags
+		}
+		return &states.ResourceInstanceObjectSrc{
+			SchemaVersion:       uint64(schema.Version),
+			AttrsJSON:           attrs,
+			Private:             resp.TargetPrivate,
+			Status:              os.Status,
+			Dependencies:        os.Dependencies,
+			CreateBeforeDestroy: os.CreateBeforeDestroy,
+		}, diags
+	}
+
+	// We translate every object first and then commit the replacements
+	// only if all of them succeeded, so that a partial failure leaves the
+	// instance wholly unmodified.
+	is := rs.Instances[key]
+	var newCurrent *states.ResourceInstanceObjectSrc
+	if is.Current != nil {
+		var moreDiags tfdiags.Diagnostics
+		newCurrent, moreDiags = translate(is.Current)
+		diags = diags.Append(moreDiags)
+	}
+	newDeposed := make(map[states.DeposedKey]*states.ResourceInstanceObjectSrc, len(is.Deposed))
+	for dk, os := range is.Deposed {
+		newObj, moreDiags := translate(os)
+		diags = diags.Append(moreDiags)
+		newDeposed[dk] = newObj
+	}
+	if diags.HasErrors() {
+		return diags
+	}
+
+	is.Current = newCurrent
+	for dk, newObj := range newDeposed {
+		is.Deposed[dk] = newObj
+	}
+	return diags
 }
 
 // buildMoveStatementGraph constructs a dependency graph of the given move
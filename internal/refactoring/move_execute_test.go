@@ -10,7 +10,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/states"
 )
 
@@ -680,7 +684,10 @@ func TestApplyMoves(t *testing.T) {
 			t.Logf("resource instances in prior state:\n%s", spew.Sdump(allResourceInstanceAddrsInState(test.State)))
 
 			state := test.State.DeepCopy() // don't modify the test case in-place
-			gotResults := ApplyMoves(test.Stmts, state)
+			gotResults, diags := ApplyMoves(test.Stmts, state, nil)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+			}
 
 			if diff := cmp.Diff(test.WantResults, gotResults); diff != "" {
 				t.Errorf("wrong results\n%s", diff)
@@ -694,6 +701,132 @@ func TestApplyMoves(t *testing.T) {
 	}
 }
 
+func TestApplyMovesCrossResourceType(t *testing.T) {
+	providerAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.MustParseProviderSourceString("example.com/foo/bar"),
+	}
+
+	fromAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "foo_old",
+		Name: "a",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	toAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "foo_new",
+		Name: "a",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+
+	buildState := func() *states.State {
+		return states.BuildState(func(s *states.SyncState) {
+			s.SetResourceInstanceCurrent(
+				fromAddr,
+				&states.ResourceInstanceObjectSrc{
+					Status:    states.ObjectReady,
+					AttrsJSON: []byte(`{"name":"boop"}`),
+				},
+				providerAddr,
+			)
+		})
+	}
+	stmts := []MoveStatement{
+		testMoveStatement(t, "", "foo_old.a", "foo_new.a"),
+	}
+
+	t.Run("provider translates the state", func(t *testing.T) {
+		provider := &moveStateStubProvider{
+			moveFunc: func(req providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
+				if req.SourceTypeName != "foo_old" || req.TargetTypeName != "foo_new" {
+					t.Errorf("unexpected move request\ngot:  %s to %s\nwant: foo_old to foo_new", req.SourceTypeName, req.TargetTypeName)
+				}
+				return providers.MoveResourceStateResponse{
+					TargetState: cty.ObjectVal(map[string]cty.Value{
+						"title": cty.StringVal("boop"),
+					}),
+				}
+			},
+		}
+		getProvider := func(addr addrs.Provider) (providers.Interface, error) {
+			return provider, nil
+		}
+
+		state := buildState()
+		results, diags := ApplyMoves(stmts, state, getProvider)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+		}
+		if got, want := len(results.Changes), 1; got != want {
+			t.Errorf("wrong number of moves %d; want %d", got, want)
+		}
+
+		if state.ResourceInstance(fromAddr) != nil {
+			t.Errorf("object is still present at %s", fromAddr)
+		}
+		is := state.ResourceInstance(toAddr)
+		if is == nil || is.Current == nil {
+			t.Fatalf("no current object at %s", toAddr)
+		}
+		if got, want := string(is.Current.AttrsJSON), `{"title":"boop"}`; got != want {
+			t.Errorf("wrong moved state\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := is.Current.SchemaVersion, uint64(2); got != want {
+			t.Errorf("wrong schema version %d; want %d", got, want)
+		}
+	})
+
+	t.Run("without provider access", func(t *testing.T) {
+		state := buildState()
+		_, diags := ApplyMoves(stmts, state, nil)
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := diags.Err().Error(), "Unsupported move between resource types"; !strings.Contains(got, want) {
+			t.Errorf("missing expected error\ngot: %s\nwant substring: %s", got, want)
+		}
+
+		// A failed translation must leave the object at its old address.
+		if state.ResourceInstance(fromAddr) == nil {
+			t.Errorf("object is no longer present at %s", fromAddr)
+		}
+		if state.ResourceInstance(toAddr) != nil {
+			t.Errorf("object was moved to %s anyway", toAddr)
+		}
+	})
+}
+
+// moveStateStubProvider is a placeholder providers.ResourceStateMover
+// implementation for TestApplyMovesCrossResourceType. Calling any method
+// other than the ones defined below will panic.
+type moveStateStubProvider struct {
+	providers.Interface
+
+	moveFunc func(providers.MoveResourceStateRequest) providers.MoveResourceStateResponse
+}
+
+func (p *moveStateStubProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
+	return providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"foo_new": {
+				Version: 2,
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"title": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *moveStateStubProvider) MoveResourceState(req providers.MoveResourceStateRequest) providers.MoveResourceStateResponse {
+	return p.moveFunc(req)
+}
+
+func (p *moveStateStubProvider) Close() error {
+	return nil
+}
+
 func testMoveStatement(t *testing.T, module string, from string, to string) MoveStatement {
 	t.Helper()
 
@@ -190,8 +190,13 @@ func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts
 				}
 			}
 
-			// Resource types must match.
-			if resourceTypesDiffer(absFrom, absTo) {
+			// Resource types must belong to the same provider. Moves
+			// between two different resource types of the same provider
+			// are allowed, because in that case we can ask the provider
+			// to translate the state during the planning step, but we
+			// have no way to translate state between two unrelated
+			// providers.
+			if resourceTypesDiffer(absFrom, absTo) && resourceProvidersDiffer(absFrom, absTo) {
 				diags = diags.Append(&hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Resource type mismatch",
@@ -297,6 +302,25 @@ func resourceTypesDiffer(absFrom, absTo addrs.AbsMoveable) bool {
 	}
 }
 
+// resourceProvidersDiffer decides whether the two addresses seem to belong
+// to different providers, using the provider name implied by each resource
+// type. This is an approximation -- a module can locally rebind a type
+// prefix to another provider using required_providers -- but a mismatch in
+// the implied names is a reliable signal that no single provider could be
+// asked to translate state between the two types.
+func resourceProvidersDiffer(absFrom, absTo addrs.AbsMoveable) bool {
+	switch absFrom := absFrom.(type) {
+	case addrs.AbsMoveableResource:
+		// addrs.UnifyMoveEndpoints guarantees that both addresses are of the
+		// same kind, so at this point we can assume that absTo is also an
+		// addrs.AbsResourceInstance or addrs.AbsResource.
+		absTo := absTo.(addrs.AbsMoveableResource)
+		return absFrom.AffectedAbsResource().Resource.ImpliedProvider() != absTo.AffectedAbsResource().Resource.ImpliedProvider()
+	default:
+		return false
+	}
+}
+
 func movableObjectDeclRange(addr addrs.AbsMoveable, cfg *configs.Config) (tfdiags.SourceRange, bool) {
 	switch addr := addr.(type) {
 	case addrs.ModuleInstance:
@@ -62,6 +62,11 @@ func TestValidateMoves(t *testing.T) {
 					`test.single[0]`, // valid because test.single doesn't have "count" set
 					`test.target3`,
 				),
+				makeTestMoveStmt(t,
+					``,
+					`test.nonexist5`,
+					`test_unit.target1`, // valid because both types imply the same provider, which can translate the state
+				),
 				makeTestMoveStmt(t,
 					``,
 					`test.zero_count[0]`, // valid because test.zero_count has count = 0
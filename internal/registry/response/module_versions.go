@@ -20,6 +20,56 @@ type ModuleVersion struct {
 	Version    string              `json:"version"`
 	Root       VersionSubmodule    `json:"root"`
 	Submodules []*VersionSubmodule `json:"submodules"`
+
+	// Deprecated, DeprecationMessage and ReplacementVersion are the flat
+	// representation of deprecation metadata that some registry
+	// implementations include in the versions response. All of these are
+	// optional, and registries that don't track deprecation at all just
+	// omit them.
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationMessage string `json:"deprecation_message,omitempty"`
+	ReplacementVersion string `json:"replacement_version,omitempty"`
+
+	// Deprecation is the newer object representation of the same metadata,
+	// used by registries implementing the extended versions endpoint. When
+	// both forms are present the object form wins.
+	Deprecation *ModuleVersionDeprecation `json:"deprecation,omitempty"`
+
+	// Yanked marks a version that the registry has withdrawn entirely,
+	// meaning that it should not be selected for new installations.
+	Yanked bool `json:"yanked,omitempty"`
+}
+
+// ModuleVersionDeprecation is the object form of version deprecation
+// metadata in a module versions response.
+type ModuleVersionDeprecation struct {
+	Message            string `json:"message,omitempty"`
+	ReplacementVersion string `json:"replacement_version,omitempty"`
+}
+
+// IsDeprecated returns true if the registry marked this version as
+// deprecated, in either of the two representations we support.
+func (v *ModuleVersion) IsDeprecated() bool {
+	return v.Deprecated || v.Deprecation != nil
+}
+
+// DeprecationText returns the registry's deprecation message for this
+// version, or an empty string if there is none.
+func (v *ModuleVersion) DeprecationText() string {
+	if v.Deprecation != nil && v.Deprecation.Message != "" {
+		return v.Deprecation.Message
+	}
+	return v.DeprecationMessage
+}
+
+// DeprecationReplacement returns the version that the registry suggests
+// using instead of this deprecated version, or an empty string if the
+// registry didn't suggest one.
+func (v *ModuleVersion) DeprecationReplacement() string {
+	if v.Deprecation != nil && v.Deprecation.ReplacementVersion != "" {
+		return v.Deprecation.ReplacementVersion
+	}
+	return v.ReplacementVersion
 }
 
 // VersionSubmodule is the output metadata for a submodule within a given
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -19,6 +20,39 @@ import (
 type Session struct {
 	// Scope is the evaluation scope where expressions will be evaluated.
 	Scope *lang.Scope
+
+	// NamedValues are the addresses of the named values (input variables
+	// and local values) declared in the root module, shown by the ":vars"
+	// command. The caller populates this from the configuration, since the
+	// evaluation scope has no way to enumerate what it can resolve.
+	NamedValues []string
+}
+
+// InputIncomplete returns true if the given source appears to be an
+// incomplete expression that might become valid with additional lines of
+// input, because it has more opening brackets than closing brackets.
+//
+// The console uses this to decide whether to evaluate what was typed so far
+// or to prompt for a continuation line.
+func InputIncomplete(src string) bool {
+	// We append a newline because console input has no trailing newline,
+	// and without one the lexer won't recognize a closing heredoc marker
+	// on the final line.
+	tokens, _ := hclsyntax.LexExpression([]byte(src+"\n"), "<console-input>", hcl.Pos{Line: 1, Column: 1})
+	depth := 0
+	for _, tok := range tokens {
+		switch tok.Type {
+		case hclsyntax.TokenOParen, hclsyntax.TokenOBrack, hclsyntax.TokenOBrace,
+			hclsyntax.TokenOHeredoc, hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+			depth++
+		case hclsyntax.TokenCParen, hclsyntax.TokenCBrack, hclsyntax.TokenCBrace,
+			hclsyntax.TokenCHeredoc, hclsyntax.TokenTemplateSeqEnd:
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return depth > 0
 }
 
 // Handle handles a single line of input from the REPL.
@@ -36,12 +70,78 @@ func (s *Session) Handle(line string) (string, bool, tfdiags.Diagnostics) {
 	case strings.TrimSpace(line) == "help":
 		ret, diags := s.handleHelp()
 		return ret, false, diags
+	case strings.HasPrefix(strings.TrimSpace(line), ":"):
+		return s.handleCommand(strings.TrimSpace(line))
 	default:
 		ret, diags := s.handleEval(line)
 		return ret, false, diags
 	}
 }
 
+// HandleJSON evaluates a single expression and returns the result as a
+// JSON-encoded value on a single line, for non-interactive use such as
+// "terraform console -eval" and scripted stdin input with the -json option.
+//
+// Unlike Handle, this accepts only plain expressions: console meta-commands
+// such as ":type" have no JSON representation and are rejected. Sensitive
+// value marks are discarded, so the result contains the underlying values,
+// as with "terraform output -json".
+func (s *Session) HandleJSON(line string) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if strings.HasPrefix(strings.TrimSpace(line), ":") {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Console command not allowed in JSON mode",
+			"Console commands such as \":type\" produce no JSON representation, so only plain expressions can be evaluated when JSON output is selected.",
+		))
+		return "", diags
+	}
+
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(line), "<console-input>", hcl.Pos{Line: 1, Column: 1})
+	diags = diags.Append(parseDiags)
+	if parseDiags.HasErrors() {
+		return "", diags
+	}
+
+	val, valDiags := s.Scope.EvalExpr(expr, cty.DynamicPseudoType)
+	diags = diags.Append(valDiags)
+	if valDiags.HasErrors() {
+		return "", diags
+	}
+
+	if marks.Contains(val, marks.TypeType) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid use of type function",
+			"The console-only \"type\" function cannot be used when JSON output is selected, because types have no JSON representation.",
+		))
+		return "", diags
+	}
+
+	val, _ = val.UnmarkDeep()
+	if !val.IsWhollyKnown() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Result depends on unknown values",
+			"The result of this expression depends on values that Terraform cannot predict until apply, so it cannot be encoded as JSON.",
+		))
+		return "", diags
+	}
+
+	buf, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Cannot encode result as JSON",
+			fmt.Sprintf("Failed to encode the result of this expression as JSON: %s.", err),
+		))
+		return "", diags
+	}
+
+	return string(buf), diags
+}
+
 func (s *Session) handleEval(line string) (string, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -83,6 +183,75 @@ func (s *Session) handleEval(line string) (string, tfdiags.Diagnostics) {
 	return FormatValue(val, 0), diags
 }
 
+// handleCommand handles a console meta-command: a line beginning with a
+// colon, such as ":type" or ":vars".
+func (s *Session) handleCommand(line string) (string, bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	name, rest := line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		name, rest = line[:i], strings.TrimSpace(line[i+1:])
+	}
+
+	switch name {
+	case ":type":
+		if rest == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Missing expression for :type",
+				"The \":type\" command requires an expression to show the type of, as in \":type var.example\".",
+			))
+			return "", false, diags
+		}
+		ret, diags := s.handleType(rest)
+		return ret, false, diags
+	case ":vars":
+		return s.handleVars(), false, nil
+	case ":help":
+		ret, diags := s.handleHelp()
+		return ret, false, diags
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unknown console command",
+			fmt.Sprintf("There is no console command named %q. The available commands are \":type\", \":vars\", and \":help\".", name),
+		))
+		return "", false, diags
+	}
+}
+
+// handleType evaluates the given expression and renders its cty type,
+// rather than its value.
+func (s *Session) handleType(line string) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(line), "<console-input>", hcl.Pos{Line: 1, Column: 1})
+	diags = diags.Append(parseDiags)
+	if parseDiags.HasErrors() {
+		return "", diags
+	}
+
+	val, valDiags := s.Scope.EvalExpr(expr, cty.DynamicPseudoType)
+	diags = diags.Append(valDiags)
+	if valDiags.HasErrors() {
+		return "", diags
+	}
+
+	val, _ = val.UnmarkDeep()
+	return typeString(val.Type()), diags
+}
+
+// handleVars lists the named values available for use in expressions.
+func (s *Session) handleVars() string {
+	if len(s.NamedValues) == 0 {
+		return "The root module declares no input variables or local values."
+	}
+	names := make([]string, len(s.NamedValues))
+	copy(names, s.NamedValues)
+	sort.Strings(names)
+	return strings.Join(names, "\n")
+}
+
 func (s *Session) handleHelp() (string, tfdiags.Diagnostics) {
 	text := `
 The Terraform console allows you to experiment with Terraform interpolations.
@@ -91,6 +260,15 @@ from a configuration. For example: "aws_instance.foo.id" would evaluate
 to the ID of "aws_instance.foo" if it exists in your state.
 
 Type in the interpolation to test and hit <enter> to see the result.
+If the input has unbalanced brackets then the console keeps reading
+continuation lines until the expression is complete.
+
+The console also supports some commands:
+    :type EXPR    Print the type of the given expression, instead of
+                  its value.
+    :vars         List the input variables and local values available
+                  for use in expressions.
+    :help         Show this text.
 
 To exit the console, type "exit" and hit <enter>, or use Control-C or
 Control-D.
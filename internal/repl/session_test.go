@@ -116,7 +116,7 @@ func TestSession_basicState(t *testing.T) {
 				{
 					Input:         "module.module.foo",
 					Error:         true,
-					ErrorContains: `Unsupported attribute: This object does not have an attribute named "foo"`,
+					ErrorContains: `Reference to undeclared output value: An output value with the name "foo" has not been declared in module.module.`,
 				},
 			},
 		})
@@ -253,6 +253,115 @@ func TestSession_stateless(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("type command", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:  `:type "foo"`,
+					Output: "string",
+				},
+			},
+		})
+	})
+
+	t.Run("type command without expression", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:         ":type",
+					Error:         true,
+					ErrorContains: "Missing expression for :type",
+				},
+			},
+		})
+	})
+
+	t.Run("vars command with no declarations", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:  ":vars",
+					Output: "The root module declares no input variables or local values.",
+				},
+			},
+		})
+	})
+
+	t.Run("help command", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:          ":help",
+					OutputContains: ":type",
+				},
+			},
+		})
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:         ":frobnicate",
+					Error:         true,
+					ErrorContains: `There is no console command named ":frobnicate"`,
+				},
+			},
+		})
+	})
+}
+
+func TestSession_varsCommand(t *testing.T) {
+	// The ":vars" command only consults NamedValues, so we don't need a
+	// full evaluation scope here.
+	s := &Session{
+		NamedValues: []string{"var.b", "local.a", "var.a"},
+	}
+	got, exit, diags := s.Handle(":vars")
+	if exit {
+		t.Fatal("unexpected exit")
+	}
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	want := "local.a\nvar.a\nvar.b"
+	if got != want {
+		t.Errorf("wrong result\ngot:\n%s\n\nwant:\n%s", got, want)
+	}
+}
+
+func TestInputIncomplete(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  bool
+	}{
+		{``, false},
+		{`1 + 5`, false},
+		{`"foo"`, false},
+		{`[1, 2, 3]`, false},
+		{`[1, 2,`, true},
+		{`{a = 1`, true},
+		{`max(1, 2`, true},
+		{`max(1, [2`, true},
+		{`max(1, [2])`, false},
+		{"[1,\n2,\n]", false},
+		{"{\na = 1\n", true},
+		{`"${foo`, true},
+		{`"${foo}"`, false},
+		{"<<EOT\nhello", true},
+		{"<<EOT\nhello\nEOT", false},
+		// An excess of closing brackets is complete (and invalid); we
+		// leave the parser to report that, rather than reading forever.
+		{`)`, false},
+		{`max(1, 2))`, false},
+	}
+	for _, test := range tests {
+		got := InputIncomplete(test.Input)
+		if got != test.Want {
+			t.Errorf("InputIncomplete(%q) = %t; want %t", test.Input, got, test.Want)
+		}
+	}
 }
 
 func testSession(t *testing.T, test testSessionTest) {
@@ -0,0 +1,240 @@
+// Package encryption implements optional encryption at rest for state
+// snapshots stored on the local filesystem.
+//
+// An encrypted snapshot is stored as a JSON "envelope" object that records
+// the encryption method and the parameters needed to decrypt it (other than
+// the passphrase itself), so that a snapshot written with one configuration
+// can still be read after the configuration's tuning parameters change.
+//
+// The envelope format is deliberately self-describing so that other software
+// can recognize an encrypted state file without being able to read it:
+// any JSON object with a non-zero "encryption_version" property is an
+// encrypted snapshot.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// envelopeVersion identifies the envelope format produced by this
+	// package. Decrypt rejects any other version, so a future format change
+	// must increment this.
+	envelopeVersion = 1
+
+	// envelopeMethod names the only encryption method currently supported.
+	envelopeMethod = "aes256-gcm"
+
+	// DefaultIterations is the PBKDF2 iteration count used when the
+	// configuration doesn't specify one. Decryption always uses the count
+	// recorded in the envelope, so changing this default doesn't invalidate
+	// previously-written snapshots.
+	DefaultIterations = 600000
+
+	saltLength = 16
+	keyLength  = 32
+)
+
+// Config describes where the encryption passphrase comes from. Exactly one
+// of the three sources must be set.
+type Config struct {
+	// Passphrase is a literal passphrase given directly in configuration.
+	Passphrase string
+
+	// PassphraseEnv names an environment variable to read the passphrase
+	// from, keeping it out of the configuration itself.
+	PassphraseEnv string
+
+	// KeyCommand is an external program to run to obtain the passphrase,
+	// for integration with external key management systems. The program
+	// must print the passphrase to its stdout; trailing whitespace is
+	// ignored.
+	KeyCommand string
+
+	// Iterations is the PBKDF2 iteration count used when encrypting new
+	// snapshots. Zero selects DefaultIterations.
+	Iterations int
+}
+
+// Encryptor encrypts and decrypts state snapshots using a key derived from
+// a passphrase with PBKDF2.
+type Encryptor struct {
+	passphrase []byte
+	iterations int
+}
+
+// New resolves the passphrase described by the given configuration and
+// returns an Encryptor using it. It fails if the configuration doesn't
+// select exactly one passphrase source, or if the selected source doesn't
+// produce a passphrase.
+func New(config Config) (*Encryptor, error) {
+	sources := 0
+	for _, set := range []bool{config.Passphrase != "", config.PassphraseEnv != "", config.KeyCommand != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("exactly one of passphrase, passphrase_env, or key_command must be set")
+	}
+
+	var passphrase []byte
+	switch {
+	case config.Passphrase != "":
+		passphrase = []byte(config.Passphrase)
+	case config.PassphraseEnv != "":
+		v := os.Getenv(config.PassphraseEnv)
+		if v == "" {
+			return nil, fmt.Errorf("environment variable %s is not set, but is configured as the state encryption passphrase source", config.PassphraseEnv)
+		}
+		passphrase = []byte(v)
+	case config.KeyCommand != "":
+		cmd := exec.Command(config.KeyCommand)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			detail := fmt.Sprintf("state encryption key command %s failed: %s", config.KeyCommand, err)
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				detail += ": " + msg
+			}
+			return nil, fmt.Errorf("%s", detail)
+		}
+		out := strings.TrimRight(stdout.String(), "\r\n")
+		if out == "" {
+			return nil, fmt.Errorf("state encryption key command %s printed no passphrase on its stdout", config.KeyCommand)
+		}
+		passphrase = []byte(out)
+	}
+
+	iterations := config.Iterations
+	if iterations == 0 {
+		iterations = DefaultIterations
+	}
+	if iterations < 0 {
+		return nil, fmt.Errorf("iterations must be a positive number")
+	}
+
+	return &Encryptor{
+		passphrase: passphrase,
+		iterations: iterations,
+	}, nil
+}
+
+// envelope is the JSON document an encrypted snapshot is stored as.
+type envelope struct {
+	Version    int    `json:"encryption_version"`
+	Method     string `json:"method"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// IsEncrypted returns true if the given data appears to be an encrypted
+// snapshot envelope, without attempting to decrypt it.
+func IsEncrypted(data []byte) bool {
+	var probe struct {
+		Version int `json:"encryption_version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return probe.Version != 0
+}
+
+// Encrypt encrypts the given plaintext snapshot, returning the serialized
+// envelope to store in its place. Each call uses a fresh random salt and
+// nonce, so encrypting the same snapshot twice produces different output.
+func (e *Encryptor) Encrypt(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %s", err)
+	}
+
+	gcm, err := e.cipher(salt, e.iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %s", err)
+	}
+
+	return json.MarshalIndent(&envelope{
+		Version:    envelopeVersion,
+		Method:     envelopeMethod,
+		Iterations: e.iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+// Decrypt decrypts a serialized envelope previously produced by Encrypt,
+// returning the plaintext snapshot. It uses the key derivation parameters
+// recorded in the envelope, so snapshots written under an earlier iteration
+// count remain readable.
+func (e *Encryptor) Decrypt(data []byte) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("invalid encrypted state envelope: %s", err)
+	}
+	if env.Version != envelopeVersion {
+		return nil, fmt.Errorf("encrypted state envelope has unsupported version %d; this version of Terraform supports only version %d", env.Version, envelopeVersion)
+	}
+	if env.Method != envelopeMethod {
+		return nil, fmt.Errorf("encrypted state envelope uses unsupported method %q", env.Method)
+	}
+	if env.Iterations < 1 {
+		return nil, fmt.Errorf("encrypted state envelope has invalid iteration count %d", env.Iterations)
+	}
+	if len(env.Salt) == 0 || len(env.Salt) > 1024 {
+		return nil, fmt.Errorf("encrypted state envelope has invalid salt length %d", len(env.Salt))
+	}
+
+	gcm, err := e.cipher(env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	// The remaining lengths must be validated before calling Open because
+	// crypto/cipher panics, rather than returning an error, when given a
+	// nonce of the wrong length, and a truncated or hand-edited envelope
+	// must produce a normal error instead of a crash.
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted state envelope has invalid nonce length %d", len(env.Nonce))
+	}
+	if len(env.Ciphertext) < gcm.Overhead() {
+		return nil, fmt.Errorf("encrypted state envelope has truncated ciphertext")
+	}
+
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt state: incorrect passphrase, or the encrypted data has been corrupted")
+	}
+	return plaintext, nil
+}
+
+// cipher derives a key from the passphrase, the given salt, and the given
+// PBKDF2 iteration count, and returns an AEAD cipher using it.
+func (e *Encryptor) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	key := pbkdf2.Key(e.passphrase, salt, iterations, keyLength, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
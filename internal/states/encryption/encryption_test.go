@@ -0,0 +1,188 @@
+package encryption
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// testIterations keeps the key derivation fast in tests; correctness
+// doesn't depend on the iteration count.
+const testIterations = 1000
+
+func TestEncryptorRoundTrip(t *testing.T) {
+	enc, err := New(Config{Passphrase: "correct horse battery staple", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte(`{"version": 4}`)
+	encrypted, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsEncrypted(encrypted) {
+		t.Error("IsEncrypted returned false for encrypted data")
+	}
+	if IsEncrypted(plaintext) {
+		t.Error("IsEncrypted returned true for plaintext data")
+	}
+	if strings.Contains(string(encrypted), "version\": 4") {
+		t.Error("encrypted data contains the plaintext")
+	}
+
+	decrypted, err := enc.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decrypted) != string(plaintext) {
+		t.Errorf("wrong decrypted result %q; want %q", decrypted, plaintext)
+	}
+
+	// A second encryption of the same plaintext must differ, since each
+	// call uses a fresh salt and nonce.
+	again, err := enc.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) == string(encrypted) {
+		t.Error("two encryptions of the same plaintext are identical")
+	}
+}
+
+func TestEncryptorWrongPassphrase(t *testing.T) {
+	enc, err := New(Config{Passphrase: "right", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := enc.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrong, err := New(Config{Passphrase: "wrong", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.Decrypt(encrypted); err == nil {
+		t.Fatal("decryption with the wrong passphrase succeeded; want error")
+	}
+}
+
+func TestEncryptorMalformedEnvelope(t *testing.T) {
+	// A truncated, corrupted, or hand-edited envelope must produce a
+	// normal error, not a panic: crypto/cipher panics when given a nonce
+	// of the wrong length, so Decrypt validates the envelope first.
+	enc, err := New(Config{Passphrase: "boop", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"empty nonce":      `{"encryption_version":1,"method":"aes256-gcm","iterations":1000,"salt":"c2FsdHNhbHRzYWx0c2FsdA==","nonce":"","ciphertext":"YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXo="}`,
+		"truncated nonce":  `{"encryption_version":1,"method":"aes256-gcm","iterations":1000,"salt":"c2FsdHNhbHRzYWx0c2FsdA==","nonce":"YWJjZA==","ciphertext":"YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXo="}`,
+		"missing salt":     `{"encryption_version":1,"method":"aes256-gcm","iterations":1000,"nonce":"YWJjZGVmZ2hpamts","ciphertext":"YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXo="}`,
+		"short ciphertext": `{"encryption_version":1,"method":"aes256-gcm","iterations":1000,"salt":"c2FsdHNhbHRzYWx0c2FsdA==","nonce":"YWJjZGVmZ2hpamts","ciphertext":"YWJjZA=="}`,
+	}
+	for name, envelope := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := enc.Decrypt([]byte(envelope)); err == nil {
+				t.Fatal("decryption of malformed envelope succeeded; want error")
+			}
+		})
+	}
+}
+
+func TestEncryptorIterationChange(t *testing.T) {
+	// A snapshot written with one iteration count must remain readable
+	// after the configured count changes, because the envelope records
+	// the count used.
+	old, err := New(Config{Passphrase: "boop", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := old.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := New(Config{Passphrase: "boop", Iterations: testIterations * 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := changed.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decrypted) != "secret" {
+		t.Errorf("wrong decrypted result %q; want %q", decrypted, "secret")
+	}
+}
+
+func TestNewPassphraseEnv(t *testing.T) {
+	t.Setenv("TF_TEST_STATE_PASSPHRASE", "from the environment")
+	enc, err := New(Config{PassphraseEnv: "TF_TEST_STATE_PASSPHRASE", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct, err := New(Config{Passphrase: "from the environment", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := enc.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := direct.Decrypt(encrypted); err != nil {
+		t.Errorf("passphrase from environment doesn't match the same passphrase given directly: %s", err)
+	}
+
+	if _, err := New(Config{PassphraseEnv: "TF_TEST_STATE_PASSPHRASE_UNSET"}); err == nil {
+		t.Error("succeeded with unset environment variable; want error")
+	}
+}
+
+func TestNewKeyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test key command is a unix shell script")
+	}
+
+	script := filepath.Join(t.TempDir(), "keyprovider.sh")
+	if err := ioutil.WriteFile(script, []byte("#!/bin/sh\necho 'from a command'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := New(Config{KeyCommand: script, Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	direct, err := New(Config{Passphrase: "from a command", Iterations: testIterations})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := enc.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := direct.Decrypt(encrypted); err != nil {
+		t.Errorf("passphrase from key command doesn't match the same passphrase given directly: %s", err)
+	}
+
+	if _, err := New(Config{KeyCommand: filepath.Join(t.TempDir(), "nonexist")}); err == nil {
+		t.Error("succeeded with nonexistent key command; want error")
+	}
+}
+
+func TestNewConfigValidation(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("succeeded with no passphrase source; want error")
+	}
+	if _, err := New(Config{Passphrase: "a", PassphraseEnv: "B"}); err == nil {
+		t.Error("succeeded with two passphrase sources; want error")
+	}
+}
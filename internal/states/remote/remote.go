@@ -1,6 +1,8 @@
 package remote
 
 import (
+	"time"
+
 	"github.com/hashicorp/terraform/internal/states/statemgr"
 )
 
@@ -28,6 +30,20 @@ type ClientLocker interface {
 	statemgr.Locker
 }
 
+// ClientLockRenewer is an optional interface that allows a remote state
+// backend whose locks are expiring leases to have them renewed periodically
+// during long-running operations, per statemgr.LockRenewer.
+type ClientLockRenewer interface {
+	ClientLocker
+
+	// LockRenewInterval returns how often RenewLock should be called while
+	// a lock is held, or zero to disable renewal.
+	LockRenewInterval() time.Duration
+
+	// RenewLock extends the lease of the lock with the given id.
+	RenewLock(id string) error
+}
+
 // Payload is the return value from the remote state storage.
 type Payload struct {
 	MD5  []byte
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"sync"
+	"time"
 
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/states/statefile"
 	"github.com/hashicorp/terraform/internal/states/statemgr"
+	"github.com/hashicorp/terraform/internal/tracing"
 )
 
 // State implements the State interfaces in the state package to handle
@@ -99,6 +101,9 @@ func (s *State) WriteStateForMigration(f *statefile.File, force bool) error {
 
 // statemgr.Refresher impl.
 func (s *State) RefreshState() error {
+	span := tracing.StartSpan("read remote state")
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return s.refreshState()
@@ -140,6 +145,9 @@ func (s *State) refreshState() error {
 
 // statemgr.Persister impl.
 func (s *State) PersistState() error {
+	span := tracing.StartSpan("persist remote state")
+	defer span.End()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -178,6 +186,10 @@ func (s *State) PersistState() error {
 		return err
 	}
 
+	if err := statemgr.CheckPersistedStateSize(buf.Bytes(), s.state); err != nil {
+		return err
+	}
+
 	err = s.Client.Put(buf.Bytes())
 	if err != nil {
 		return err
@@ -225,6 +237,37 @@ func (s *State) Unlock(id string) error {
 	return nil
 }
 
+// LockRenewInterval returns the Client's renewal interval if it implements
+// ClientLockRenewer, making State a statemgr.LockRenewer on its behalf.
+func (s *State) LockRenewInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.disableLocks {
+		return 0
+	}
+
+	if c, ok := s.Client.(ClientLockRenewer); ok {
+		return c.LockRenewInterval()
+	}
+	return 0
+}
+
+// RenewLock calls the Client's RenewLock method if it's implemented.
+func (s *State) RenewLock(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.disableLocks {
+		return nil
+	}
+
+	if c, ok := s.Client.(ClientLockRenewer); ok {
+		return c.RenewLock(id)
+	}
+	return nil
+}
+
 // DisableLocks turns the Lock and Unlock methods into no-ops. This is intended
 // to be called during initialization of a state manager and should not be
 // called after any of the statemgr.Full interface methods have been called.
@@ -15,6 +15,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/encryption"
 	"github.com/hashicorp/terraform/internal/states/statefile"
 )
 
@@ -57,6 +58,13 @@ type Filesystem struct {
 	readFile      *statefile.File
 	backupFile    *statefile.File
 	writtenBackup bool
+
+	// encryption, if non-nil, causes every snapshot (including backups) to
+	// be encrypted before it is written to disk and decrypted after it is
+	// read. A plaintext snapshot can still be read while encryption is
+	// enabled, so enabling encryption on an existing state file takes
+	// effect transparently on the next write.
+	encryption *encryption.Encryptor
 }
 
 var (
@@ -108,6 +116,15 @@ func (s *Filesystem) BackupPath() string {
 	return s.backupPath
 }
 
+// SetEncryption configures the receiver to encrypt every snapshot it writes
+// (including backups) and to decrypt encrypted snapshots it reads.
+//
+// For correct operation, this must be called before any other state methods
+// are called.
+func (s *Filesystem) SetEncryption(enc *encryption.Encryptor) {
+	s.encryption = enc
+}
+
 // State is an implementation of Reader.
 func (s *Filesystem) State() *states.State {
 	defer s.mutex()()
@@ -155,8 +172,16 @@ func (s *Filesystem) writeState(state *states.State, meta *SnapshotMeta) error {
 			}
 			defer bfh.Close()
 
-			err = statefile.Write(s.backupFile, bfh)
+			var bbuf bytes.Buffer
+			err = statefile.Write(s.backupFile, &bbuf)
+			if err != nil {
+				return fmt.Errorf("failed to write to local state backup file: %s", err)
+			}
+			braw, err := s.encryptIfEnabled(bbuf.Bytes())
 			if err != nil {
+				return fmt.Errorf("failed to encrypt local state backup file: %s", err)
+			}
+			if _, err := bfh.Write(braw); err != nil {
 				return fmt.Errorf("failed to write to local state backup file: %s", err)
 			}
 
@@ -184,15 +209,14 @@ func (s *Filesystem) writeState(state *states.State, meta *SnapshotMeta) error {
 	}
 	s.file.State = state.DeepCopy()
 
-	if _, err := s.stateFileOut.Seek(0, io.SeekStart); err != nil {
-		return err
-	}
-	if err := s.stateFileOut.Truncate(0); err != nil {
-		return err
-	}
-
 	if state == nil {
 		// if we have no state, don't write anything else.
+		if _, err := s.stateFileOut.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if err := s.stateFileOut.Truncate(0); err != nil {
+			return err
+		}
 		log.Print("[TRACE] statemgr.Filesystem: state is nil, so leaving the file empty")
 		return nil
 	}
@@ -212,7 +236,29 @@ func (s *Filesystem) writeState(state *states.State, meta *SnapshotMeta) error {
 	}
 
 	log.Printf("[TRACE] statemgr.Filesystem: writing snapshot at %s", s.path)
-	if err := statefile.Write(s.file, s.stateFileOut); err != nil {
+
+	// We serialize to a buffer first so that we can apply the optional size
+	// guard, and so that a snapshot that fails it leaves the previous
+	// snapshot intact on disk.
+	var buf bytes.Buffer
+	if err := statefile.Write(s.file, &buf); err != nil {
+		return err
+	}
+	if err := CheckPersistedStateSize(buf.Bytes(), s.file.State); err != nil {
+		return err
+	}
+	raw, err := s.encryptIfEnabled(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to encrypt state: %s", err)
+	}
+
+	if _, err := s.stateFileOut.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := s.stateFileOut.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := s.stateFileOut.Write(raw); err != nil {
 		return err
 	}
 
@@ -273,7 +319,7 @@ func (s *Filesystem) refreshState() error {
 		reader = s.stateFileOut
 	}
 
-	f, err := statefile.Read(reader)
+	f, err := s.readSnapshot(reader)
 	// if there's no state then a nil file is fine
 	if err != nil {
 		if err != statefile.ErrNoState {
@@ -429,6 +475,36 @@ func (s *Filesystem) WriteStateForMigration(f *statefile.File, force bool) error
 	return nil
 }
 
+// readSnapshot reads a state snapshot from the given reader, transparently
+// decrypting it first if it is encrypted. Finding an encrypted snapshot
+// while no encryption is configured is an error, since otherwise we'd
+// misreport the envelope as a corrupt state file.
+func (s *Filesystem) readSnapshot(r io.Reader) (*statefile.File, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if encryption.IsEncrypted(raw) {
+		if s.encryption == nil {
+			return nil, fmt.Errorf("the state file is encrypted, so the local backend's \"encryption\" block must be configured in order to read it")
+		}
+		raw, err = s.encryption.Decrypt(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statefile.Read(bytes.NewReader(raw))
+}
+
+// encryptIfEnabled encrypts the given serialized snapshot when encryption
+// is enabled, or returns it unchanged when it is not.
+func (s *Filesystem) encryptIfEnabled(raw []byte) ([]byte, error) {
+	if s.encryption == nil {
+		return raw, nil
+	}
+	return s.encryption.Encrypt(raw)
+}
+
 // Open the state file, creating the directories and file as needed.
 func (s *Filesystem) createStateFiles() error {
 	log.Printf("[TRACE] statemgr.Filesystem: preparing to manage state snapshots at %s", s.path)
@@ -452,7 +528,7 @@ func (s *Filesystem) createStateFiles() error {
 
 	// If the file already existed with content then that'll be the content
 	// of our backup file if we write a change later.
-	s.backupFile, err = statefile.Read(s.stateFileOut)
+	s.backupFile, err = s.readSnapshot(s.stateFileOut)
 	if err != nil {
 		if err != statefile.ErrNoState {
 			return err
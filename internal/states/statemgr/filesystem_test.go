@@ -15,6 +15,7 @@ import (
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/states"
+	"github.com/hashicorp/terraform/internal/states/encryption"
 	"github.com/hashicorp/terraform/internal/states/statefile"
 	tfversion "github.com/hashicorp/terraform/version"
 )
@@ -339,6 +340,78 @@ func TestFilesystem_impl(t *testing.T) {
 	var _ Locker = new(Filesystem)
 }
 
+func TestFilesystem_encryption(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+
+	enc, err := encryption.New(encryption.Config{Passphrase: "test passphrase", Iterations: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	ls := NewFilesystem(f.Name())
+	ls.SetEncryption(enc)
+	if err := ls.RefreshState(); err != nil {
+		t.Fatalf("initial refresh failed: %s", err)
+	}
+
+	state := TestFullInitialState()
+	if err := ls.WriteState(state); err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+
+	// The snapshot on disk must be an encryption envelope, not plaintext.
+	raw, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !encryption.IsEncrypted(raw) {
+		t.Fatalf("snapshot on disk is not encrypted:\n%s", raw)
+	}
+
+	// A fresh manager with the same encryption must read it back.
+	reread := NewFilesystem(f.Name())
+	reread.SetEncryption(enc)
+	if err := reread.RefreshState(); err != nil {
+		t.Fatalf("refresh of encrypted snapshot failed: %s", err)
+	}
+	if got := reread.State(); !got.Equal(state) {
+		for _, problem := range deep.Equal(state, got) {
+			t.Error(problem)
+		}
+	}
+
+	// A manager without encryption configured must fail with a message
+	// about encryption rather than misreporting a corrupt state file.
+	noEnc := NewFilesystem(f.Name())
+	err = noEnc.RefreshState()
+	if err == nil {
+		t.Fatal("refresh without encryption succeeded; want error")
+	}
+	if !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("wrong error: %s", err)
+	}
+
+	// A plaintext snapshot must still be readable while encryption is
+	// enabled, so that enabling encryption on existing state works.
+	plainFile := testFilesystem(t)
+	defer os.Remove(plainFile.readPath)
+	migrated := NewFilesystem(plainFile.readPath)
+	migrated.SetEncryption(enc)
+	if err := migrated.RefreshState(); err != nil {
+		t.Fatalf("refresh of plaintext snapshot with encryption enabled failed: %s", err)
+	}
+	if migrated.State() == nil {
+		t.Fatal("no state read from plaintext snapshot")
+	}
+}
+
 func testFilesystem(t *testing.T) *Filesystem {
 	f, err := ioutil.TempFile("", "tf")
 	if err != nil {
@@ -61,6 +61,28 @@ type Locker interface {
 	Unlock(id string) error
 }
 
+// LockRenewer is an optional extension of Locker for state managers whose
+// locks are leases that can expire, such as TTL-based locks in a remote
+// store. Terraform calls RenewLock periodically while a long-running
+// operation holds the lock, so that the lease does not lapse mid-operation.
+//
+// Implementations whose locks never expire, or that are not configured for
+// renewal, should return zero from LockRenewInterval, in which case
+// Terraform never calls RenewLock.
+type LockRenewer interface {
+	Locker
+
+	// LockRenewInterval returns how often RenewLock should be called while
+	// a lock is held. A zero or negative interval disables renewal.
+	LockRenewInterval() time.Duration
+
+	// RenewLock extends the lease of the lock that was previously acquired
+	// with the given id. An error indicates that the lock could not be
+	// renewed and may no longer be held, in which case the operation in
+	// progress is no longer protected from concurrent writers.
+	RenewLock(id string) error
+}
+
 // test hook to verify that LockWithContext has attempted a lock
 var postLockHook func()
 
@@ -70,6 +92,14 @@ var postLockHook func()
 // This method has a built-in retry/backoff behavior up to the context's
 // timeout.
 func LockWithContext(ctx context.Context, s Locker, info *LockInfo) (string, error) {
+	return LockWithProgress(ctx, s, info, nil)
+}
+
+// LockWithProgress is like LockWithContext, but additionally calls the given
+// callback each time a lock attempt fails because another process is holding
+// the lock, passing the holder's lock info so that the caller can report who
+// we are waiting on. The callback may be nil.
+func LockWithProgress(ctx context.Context, s Locker, info *LockInfo, progress func(held *LockInfo)) (string, error) {
 	delay := time.Second
 	maxDelay := 16 * time.Second
 	for {
@@ -90,6 +120,10 @@ func LockWithContext(ctx context.Context, s Locker, info *LockInfo) (string, err
 			return "", err
 		}
 
+		if progress != nil {
+			progress(le.Info)
+		}
+
 		if postLockHook != nil {
 			postLockHook()
 		}
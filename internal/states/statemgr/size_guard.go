@@ -0,0 +1,120 @@
+package statemgr
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/states"
+)
+
+// State snapshots that grow very large (usually because a resource type
+// stores entire rendered documents in its attributes) tend to fail in
+// backend-specific and hard-to-diagnose ways during upload, so we optionally
+// guard persistence with a soft (warning) and hard (error) limit on the
+// serialized snapshot size, both given in bytes:
+//
+//	TF_STATE_PERSIST_WARN_BYTES
+//	TF_STATE_PERSIST_MAX_BYTES
+//
+// Both are unset by default, which disables the corresponding check.
+var (
+	statePersistWarnBytes = stateSizeLimitFromEnv("TF_STATE_PERSIST_WARN_BYTES")
+	statePersistMaxBytes  = stateSizeLimitFromEnv("TF_STATE_PERSIST_MAX_BYTES")
+)
+
+func stateSizeLimitFromEnv(name string) int64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0
+	}
+	limit, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || limit < 0 {
+		log.Printf("[WARN] statemgr: ignoring invalid %s value %q; expected a whole number of bytes", name, raw)
+		return 0
+	}
+	return limit
+}
+
+// CheckPersistedStateSize verifies a serialized state snapshot against the
+// optional size limits before it's written to the backend. It returns an
+// error if the snapshot exceeds the hard limit, and logs a warning naming
+// the largest resource instances if the snapshot exceeds the soft limit, so
+// that the user knows what to refactor.
+func CheckPersistedStateSize(raw []byte, state *states.State) error {
+	size := int64(len(raw))
+
+	if statePersistMaxBytes > 0 && size > statePersistMaxBytes {
+		return fmt.Errorf(
+			"serialized state snapshot is %d bytes, which exceeds the configured maximum of %d bytes (TF_STATE_PERSIST_MAX_BYTES); the largest resource instances are:\n%s",
+			size, statePersistMaxBytes, largestResourceInstancesStr(state, 10),
+		)
+	}
+
+	if statePersistWarnBytes > 0 && size > statePersistWarnBytes {
+		log.Printf(
+			"[WARN] statemgr: serialized state snapshot is %d bytes, which exceeds the configured warning threshold of %d bytes (TF_STATE_PERSIST_WARN_BYTES); the largest resource instances are:\n%s",
+			size, statePersistWarnBytes, largestResourceInstancesStr(state, 10),
+		)
+	}
+
+	return nil
+}
+
+// resourceInstanceObjectSize approximates the serialized size of a single
+// resource instance object by counting its stored attribute data.
+func resourceInstanceObjectSize(obj *states.ResourceInstanceObjectSrc) int {
+	if obj == nil {
+		return 0
+	}
+	size := len(obj.AttrsJSON)
+	for k, v := range obj.AttrsFlat {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// largestResourceInstancesStr returns a multi-line summary of the n largest
+// resource instances in the given state by serialized attribute size, for
+// inclusion in size guard messages.
+func largestResourceInstancesStr(state *states.State, n int) string {
+	type instanceSize struct {
+		addr string
+		size int
+	}
+	var sizes []instanceSize
+
+	if state != nil {
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				for key, is := range rs.Instances {
+					addr := rs.Addr.Instance(key)
+					size := resourceInstanceObjectSize(is.Current)
+					for _, dobj := range is.Deposed {
+						size += resourceInstanceObjectSize(dobj)
+					}
+					sizes = append(sizes, instanceSize{addr.String(), size})
+				}
+			}
+		}
+	}
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].size != sizes[j].size {
+			return sizes[i].size > sizes[j].size
+		}
+		return sizes[i].addr < sizes[j].addr
+	})
+	if len(sizes) > n {
+		sizes = sizes[:n]
+	}
+
+	var buf strings.Builder
+	for _, s := range sizes {
+		fmt.Fprintf(&buf, "  - %s: %d bytes\n", s.addr, s.size)
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
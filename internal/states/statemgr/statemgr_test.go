@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"flag"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -89,6 +90,65 @@ func TestLockWithContext(t *testing.T) {
 	}
 }
 
+func TestLockWithProgress(t *testing.T) {
+	s := NewFullFake(nil, TestFullInitialState())
+
+	id, err := s.Lock(NewLockInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// block until LockWithProgress has made a first attempt
+	attempted := make(chan struct{})
+	postLockHook = func() {
+		close(attempted)
+		postLockHook = nil
+	}
+
+	// unlock the state during LockWithProgress
+	unlocked := make(chan struct{})
+	var unlockErr error
+	go func() {
+		defer close(unlocked)
+		<-attempted
+		unlockErr = s.Unlock(id)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var held *LockInfo
+	progress := func(current *LockInfo) {
+		mu.Lock()
+		held = current
+		mu.Unlock()
+	}
+
+	info := NewLockInfo()
+	info.Info = "lock with progress"
+	_, err = LockWithProgress(ctx, s, info, progress)
+	if err != nil {
+		t.Fatal("lock should have completed within 2s:", err)
+	}
+
+	// ensure the goroutine completes
+	<-unlocked
+	if unlockErr != nil {
+		t.Fatal(unlockErr)
+	}
+
+	// the progress callback must have been told about the existing lock
+	mu.Lock()
+	defer mu.Unlock()
+	if held == nil {
+		t.Fatal("progress callback was never called with the existing lock info")
+	}
+	if held.ID == "" {
+		t.Fatal("progress callback was called without a lock ID")
+	}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	os.Exit(m.Run())
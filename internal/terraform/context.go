@@ -6,6 +6,7 @@ import (
 	"log"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/configs"
@@ -41,6 +42,20 @@ type ContextOpts struct {
 	Providers    map[addrs.Provider]providers.Factory
 	Provisioners map[string]provisioners.Factory
 
+	// RefreshParallelism, if non-zero, is a separate concurrency limit for
+	// planning walks, which refresh existing remote objects and are
+	// typically network-bound, so that they can run at a higher concurrency
+	// than the walks that apply changes. Zero means to use Parallelism for
+	// all walks.
+	RefreshParallelism int
+
+	// OperationTimeouts associates resource instance addresses (in their
+	// string representation) with deadlines for the provider calls that
+	// apply their changes. When a call outlives its deadline Terraform
+	// abandons it, records the object as tainted, and returns an error,
+	// rather than waiting indefinitely for the provider.
+	OperationTimeouts map[string]time.Duration
+
 	UIInput UIInput
 }
 
@@ -81,7 +96,9 @@ type Context struct {
 	uiInput UIInput
 
 	l                   sync.Mutex // Lock acquired during any task
-	parallelSem         Semaphore
+	parallelSem         *PrioritySemaphore
+	refreshParallelSem  *PrioritySemaphore // nil means to use parallelSem for planning walks too
+	operationTimeouts   map[string]time.Duration
 	providerInputConfig map[string]map[string]cty.Value
 	runCond             *sync.Cond
 	runContext          context.Context
@@ -127,6 +144,20 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 		par = 10
 	}
 
+	refreshPar := opts.RefreshParallelism
+	if refreshPar < 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid refresh parallelism value",
+			fmt.Sprintf("The refresh parallelism must be a positive value. Not %d.", refreshPar),
+		))
+		return nil, diags
+	}
+	var refreshSem *PrioritySemaphore
+	if refreshPar != 0 {
+		refreshSem = NewPrioritySemaphore(refreshPar)
+	}
+
 	plugins := newContextPlugins(opts.Providers, opts.Provisioners)
 
 	log.Printf("[TRACE] terraform.NewContext: complete")
@@ -138,7 +169,9 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 
 		plugins: plugins,
 
-		parallelSem:         NewSemaphore(par),
+		parallelSem:         NewPrioritySemaphore(par),
+		refreshParallelSem:  refreshSem,
+		operationTimeouts:   opts.OperationTimeouts,
 		providerInputConfig: make(map[string]map[string]cty.Value),
 		sh:                  sh,
 	}, diags
@@ -3,6 +3,7 @@ package terraform
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -598,6 +599,70 @@ resource "test_object" "x" {
 
 }
 
+func TestContext2Apply_operationTimeout(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "new"
+}`,
+	})
+
+	p := simpleMockProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+		// Simulate a provider that hangs during apply, to check that the
+		// operation timeout abandons the call rather than waiting.
+		time.Sleep(5 * time.Second)
+		resp.NewState = req.PlannedState
+		return resp
+	}
+
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_object.a").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"old","test_bool":null,"test_list":null,"test_map":null,"test_number":null}`),
+		},
+		mustProviderConfig(`provider["registry.terraform.io/hashicorp/test"]`),
+	)
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		OperationTimeouts: map[string]time.Duration{
+			"test_object.a": 100 * time.Millisecond,
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	if diags.HasErrors() {
+		t.Fatalf("plan: %s", diags.Err())
+	}
+
+	newState, diags := ctx.Apply(plan, m)
+	if !diags.HasErrors() {
+		t.Fatal("apply succeeded; want operation timeout error")
+	}
+	if got, want := diags.Err().Error(), "Operation timeout exceeded"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+
+	// The object should keep its prior value, marked as tainted since the
+	// abandoned provider call may still have changed the remote object.
+	is := newState.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if is == nil || is.Current == nil {
+		t.Fatal("no state recorded for test_object.a")
+	}
+	if is.Current.Status != states.ObjectTainted {
+		t.Errorf("wrong status %q; want %q", is.Current.Status, states.ObjectTainted)
+	}
+	if got := string(is.Current.AttrsJSON); !strings.Contains(got, `"old"`) {
+		t.Errorf("state value was updated despite the timeout: %s", got)
+	}
+}
+
 func TestContext2Apply_nullableVariables(t *testing.T) {
 	m := testModule(t, "apply-nullable-variables")
 	state := states.NewState()
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform/internal/instances"
 	"github.com/hashicorp/terraform/internal/lang/globalref"
 	"github.com/hashicorp/terraform/internal/plans"
+	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/refactoring"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/tfdiags"
@@ -389,7 +390,7 @@ func (c *Context) destroyPlan(config *configs.Config, prevRunState *states.State
 	return destroyPlan, diags
 }
 
-func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State, targets []addrs.Targetable) ([]refactoring.MoveStatement, refactoring.MoveResults) {
+func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState *states.State, targets []addrs.Targetable) ([]refactoring.MoveStatement, refactoring.MoveResults, tfdiags.Diagnostics) {
 	explicitMoveStmts := refactoring.FindMoveStatements(config)
 	implicitMoveStmts := refactoring.ImpliedMoveStatements(config, prevRunState, explicitMoveStmts)
 	var moveStmts []refactoring.MoveStatement
@@ -398,8 +399,29 @@ func (c *Context) prePlanFindAndApplyMoves(config *configs.Config, prevRunState
 		moveStmts = append(moveStmts, explicitMoveStmts...)
 		moveStmts = append(moveStmts, implicitMoveStmts...)
 	}
-	moveResults := refactoring.ApplyMoves(moveStmts, prevRunState)
-	return moveStmts, moveResults
+
+	// Moves between two different resource types need the relevant
+	// provider to translate the stored state, so we make unconfigured
+	// provider instances available to the move logic, started on first
+	// use and closed again once the moves are complete.
+	openProviders := make(map[addrs.Provider]providers.Interface)
+	getProvider := func(addr addrs.Provider) (providers.Interface, error) {
+		if provider, ok := openProviders[addr]; ok {
+			return provider, nil
+		}
+		provider, err := c.plugins.NewProviderInstance(addr)
+		if err != nil {
+			return nil, err
+		}
+		openProviders[addr] = provider
+		return provider, nil
+	}
+	moveResults, moveDiags := refactoring.ApplyMoves(moveStmts, prevRunState, getProvider)
+	for _, provider := range openProviders {
+		provider.Close()
+	}
+
+	return moveStmts, moveResults, moveDiags
 }
 
 func (c *Context) prePlanVerifyTargetedMoves(moveResults refactoring.MoveResults, targets []addrs.Targetable) tfdiags.Diagnostics {
@@ -479,7 +501,11 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 	log.Printf("[DEBUG] Building and walking plan graph for %s", opts.Mode)
 
 	prevRunState = prevRunState.DeepCopy() // don't modify the caller's object when we process the moves
-	moveStmts, moveResults := c.prePlanFindAndApplyMoves(config, prevRunState, opts.Targets)
+	moveStmts, moveResults, moveDiags := c.prePlanFindAndApplyMoves(config, prevRunState, opts.Targets)
+	diags = diags.Append(moveDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
 
 	// If resource targeting is in effect then it might conflict with the
 	// move result.
@@ -497,6 +523,29 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 		return nil, diags
 	}
 
+	// Announce to the hooks how many resource instances this walk expects
+	// to refresh, so that UI layers can report overall progress as the
+	// individual PostRefresh calls arrive. A destroy plan refreshes via a
+	// nested normal plan instead, which makes its own announcement.
+	if !opts.SkipRefresh && opts.Mode != plans.DestroyMode {
+		total := 0
+		for _, ms := range prevRunState.Modules {
+			for _, rs := range ms.Resources {
+				if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+					continue
+				}
+				for _, is := range rs.Instances {
+					if is.Current != nil {
+						total++
+					}
+				}
+			}
+		}
+		for _, hook := range c.hooks {
+			hook.RefreshTotal(total)
+		}
+	}
+
 	// If we get here then we should definitely have a non-nil "graph", which
 	// we can now walk.
 	changes := plans.NewChanges()
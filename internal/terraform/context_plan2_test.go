@@ -11,6 +11,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
 	"github.com/hashicorp/terraform/internal/lang/marks"
 	"github.com/hashicorp/terraform/internal/plans"
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 func TestContext2Plan_removedDuringRefresh(t *testing.T) {
@@ -1175,6 +1177,101 @@ func TestContext2Plan_movedResourceBasic(t *testing.T) {
 	})
 }
 
+func TestContext2Plan_movedResourceTypeChange(t *testing.T) {
+	addrA := mustResourceInstanceAddr("test_object.a")
+	addrB := mustResourceInstanceAddr("test_thing.a")
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_thing" "a" {
+				title = "boop"
+			}
+
+			moved {
+				from = test_object.a
+				to   = test_thing.a
+			}
+		`,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		// The prior state tracks test_object.a, which we should treat as
+		// test_thing.a after asking the provider to translate the state,
+		// because of the "moved" block in the config.
+		s.SetResourceInstanceCurrent(addrA, &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"name":"boop"}`),
+			Status:    states.ObjectReady,
+		}, mustProviderConfig(`provider["registry.terraform.io/hashicorp/test"]`))
+	})
+
+	p := new(MockProvider)
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		ResourceTypes: map[string]*configschema.Block{
+			"test_object": {
+				Attributes: map[string]*configschema.Attribute{
+					"name": {Type: cty.String, Optional: true},
+				},
+			},
+			"test_thing": {
+				Attributes: map[string]*configschema.Attribute{
+					"title": {Type: cty.String, Optional: true},
+				},
+			},
+		},
+	})
+	p.MoveResourceStateFn = func(req providers.MoveResourceStateRequest) (resp providers.MoveResourceStateResponse) {
+		if req.SourceTypeName != "test_object" || req.TargetTypeName != "test_thing" {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unexpected move from %q to %q", req.SourceTypeName, req.TargetTypeName))
+			return resp
+		}
+		// The translation just renames the "name" attribute to "title".
+		old, err := ctyjson.Unmarshal(req.SourceStateJSON, cty.Object(map[string]cty.Type{"name": cty.String}))
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			return resp
+		}
+		resp.TargetState = cty.ObjectVal(map[string]cty.Value{
+			"title": old.GetAttr("name"),
+		})
+		return resp
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors\n%s", diags.Err().Error())
+	}
+	if !p.MoveResourceStateCalled {
+		t.Fatal("provider's MoveResourceState was never called")
+	}
+
+	t.Run(addrA.String(), func(t *testing.T) {
+		instPlan := plan.Changes.ResourceInstance(addrA)
+		if instPlan != nil {
+			t.Fatalf("unexpected plan for %s; should've moved to %s", addrA, addrB)
+		}
+	})
+	t.Run(addrB.String(), func(t *testing.T) {
+		instPlan := plan.Changes.ResourceInstance(addrB)
+		if instPlan == nil {
+			t.Fatalf("no plan for %s at all", addrB)
+		}
+
+		if got, want := instPlan.Addr, addrB; !got.Equal(want) {
+			t.Errorf("wrong current address\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := instPlan.PrevRunAddr, addrA; !got.Equal(want) {
+			t.Errorf("wrong previous run address\ngot:  %s\nwant: %s", got, want)
+		}
+		if got, want := instPlan.Action, plans.NoOp; got != want {
+			t.Errorf("wrong planned action\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
+
 func TestContext2Plan_movedResourceCollision(t *testing.T) {
 	addrNoKey := mustResourceInstanceAddr("test_object.a")
 	addrZeroKey := mustResourceInstanceAddr("test_object.a[0]")
@@ -3532,3 +3629,235 @@ resource "test_object" "b" {
 		t.Fatalf("no cycle error found:\n got: %s\n", msg)
 	}
 }
+
+func TestContext2Plan_providerOverride(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+terraform {
+  experiments = [provider_overrides]
+}
+
+provider "aws" {
+  region = "us-west-2"
+}
+
+resource "aws_instance" "a" {
+  provider_override {
+    region = "eu-west-1"
+  }
+}
+
+resource "aws_instance" "b" {
+}
+`,
+	})
+
+	p := testProvider("aws")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		Provider: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"region": {Type: cty.String, Optional: true},
+			},
+		},
+		ResourceTypes: map[string]*configschema.Block{
+			"aws_instance": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+		OverrideSafeAttributes: []string{"region"},
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	changeA := plan.Changes.ResourceInstance(mustResourceInstanceAddr("aws_instance.a"))
+	if changeA == nil {
+		t.Fatal("no planned change for aws_instance.a")
+	}
+	baseAlias, values, ok := configs.DecodeProviderOverrideAlias(changeA.ProviderAddr.Alias)
+	if !ok {
+		t.Fatalf("provider address %s for aws_instance.a does not record an override", changeA.ProviderAddr)
+	}
+	if baseAlias != "" {
+		t.Errorf("wrong base alias %q; want default configuration", baseAlias)
+	}
+	if got, want := values["region"], cty.StringVal("eu-west-1"); !want.RawEquals(got) {
+		t.Errorf("wrong overridden region %#v; want %#v", got, want)
+	}
+
+	changeB := plan.Changes.ResourceInstance(mustResourceInstanceAddr("aws_instance.b"))
+	if changeB == nil {
+		t.Fatal("no planned change for aws_instance.b")
+	}
+	if changeB.ProviderAddr.Alias != "" {
+		t.Errorf("aws_instance.b should use the default provider configuration, but uses %s", changeB.ProviderAddr)
+	}
+}
+
+func TestContext2Plan_providerOverrideResourceRemoved(t *testing.T) {
+	// A resource that was created with a provider_override records the
+	// synthetic provider configuration alias in the state, and the encoded
+	// override values must be enough to reconstruct the configuration for
+	// destroy even after the resource block (and its override) are removed
+	// from the configuration entirely.
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "aws" {
+  region = "us-west-2"
+}
+`,
+	})
+
+	p := testProvider("aws")
+	p.GetProviderSchemaResponse = getProviderSchemaResponseFromProviderSchema(&ProviderSchema{
+		Provider: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"region": {Type: cty.String, Optional: true},
+			},
+		},
+		ResourceTypes: map[string]*configschema.Block{
+			"aws_instance": {
+				Attributes: map[string]*configschema.Attribute{
+					"id": {Type: cty.String, Computed: true},
+				},
+			},
+		},
+		OverrideSafeAttributes: []string{"region"},
+	})
+
+	override := &configs.ProviderOverride{
+		Values: map[string]cty.Value{
+			"region": cty.StringVal("eu-west-1"),
+		},
+	}
+	providerAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewDefaultProvider("aws"),
+		Alias:    override.SyntheticAlias(""),
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("aws_instance.a"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"foo"}`),
+				Status:    states.ObjectReady,
+			},
+			providerAddr,
+		)
+	})
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	change := plan.Changes.ResourceInstance(mustResourceInstanceAddr("aws_instance.a"))
+	if change == nil {
+		t.Fatal("no planned change for aws_instance.a")
+	}
+	if got, want := change.Action, plans.Delete; got != want {
+		t.Errorf("wrong planned action %s; want %s", got, want)
+	}
+	if change.ProviderAddr.String() != providerAddr.String() {
+		t.Errorf("wrong provider address %s; want %s", change.ProviderAddr, providerAddr)
+	}
+
+	// The reconstructed provider configuration must have been configured
+	// with the overridden region, not the base one.
+	if !p.ConfigureProviderCalled {
+		t.Fatal("provider was never configured")
+	}
+	if got, want := p.ConfigureProviderRequest.Config.GetAttr("region"), cty.StringVal("eu-west-1"); !want.RawEquals(got) {
+		t.Errorf("wrong region in provider configuration %#v; want %#v", got, want)
+	}
+}
+
+func TestContext2Plan_modulePrecondition(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "tier" {
+  type = string
+}
+
+variable "instance_count" {
+  type = number
+}
+
+precondition {
+  condition     = var.tier != "prod" || var.instance_count >= 3
+  error_message = "Production deployments need at least three instances."
+}
+
+resource "test_object" "a" {
+  test_string = var.tier
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	t.Run("condition passes", func(t *testing.T) {
+		plan, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+			Mode: plans.NormalMode,
+			SetVariables: InputValues{
+				"tier": &InputValue{
+					Value:      cty.StringVal("prod"),
+					SourceType: ValueFromCLIArg,
+				},
+				"instance_count": &InputValue{
+					Value:      cty.NumberIntVal(3),
+					SourceType: ValueFromCLIArg,
+				},
+			},
+		})
+		assertNoErrors(t, diags)
+
+		result, ok := plan.Conditions["preconditions[0]"]
+		if !ok {
+			t.Fatalf("no condition result for the module precondition; have %#v", plan.Conditions)
+		}
+		if result.Result != cty.True {
+			t.Errorf("wrong condition result %#v; want true", result.Result)
+		}
+	})
+
+	t.Run("condition fails", func(t *testing.T) {
+		_, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+			Mode: plans.NormalMode,
+			SetVariables: InputValues{
+				"tier": &InputValue{
+					Value:      cty.StringVal("prod"),
+					SourceType: ValueFromCLIArg,
+				},
+				"instance_count": &InputValue{
+					Value:      cty.NumberIntVal(1),
+					SourceType: ValueFromCLIArg,
+				},
+			},
+		})
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want errors")
+		}
+		if got, want := diags.Err().Error(), "Module precondition failed: Production deployments need at least three instances."; got != want {
+			t.Fatalf("wrong error:\ngot:  %s\nwant: %q", got, want)
+		}
+	})
+}
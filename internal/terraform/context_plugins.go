@@ -105,6 +105,7 @@ func (cp *contextPlugins) ProviderSchema(addr addrs.Provider) (*ProviderSchema,
 		DataSources:   make(map[string]*configschema.Block),
 
 		ResourceTypeSchemaVersions: make(map[string]uint64),
+		OverrideSafeAttributes:     resp.OverrideSafeAttributes,
 	}
 
 	if resp.Provider.Version < 0 {
@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
@@ -197,6 +199,11 @@ type EvalContext interface {
 	// objects accessible through it.
 	MoveResults() refactoring.MoveResults
 
+	// OperationTimeout returns the user-specified deadline for provider
+	// calls that apply changes to the given resource instance, or zero if
+	// no deadline was specified for it.
+	OperationTimeout(addr addrs.AbsResourceInstance) time.Duration
+
 	// WithPath returns a copy of the context with the internal path set to the
 	// path argument.
 	WithPath(path addrs.ModuleInstance) EvalContext
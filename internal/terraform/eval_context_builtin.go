@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform/internal/instances"
 	"github.com/hashicorp/terraform/internal/plans"
@@ -71,6 +72,7 @@ type BuiltinEvalContext struct {
 	PrevRunStateValue     *states.SyncState
 	InstanceExpanderValue *instances.Expander
 	MoveResultsValue      refactoring.MoveResults
+	OperationTimeouts     map[string]time.Duration
 }
 
 // BuiltinEvalContext implements EvalContext
@@ -501,3 +503,7 @@ func (ctx *BuiltinEvalContext) InstanceExpander() *instances.Expander {
 func (ctx *BuiltinEvalContext) MoveResults() refactoring.MoveResults {
 	return ctx.MoveResultsValue
 }
+
+func (ctx *BuiltinEvalContext) OperationTimeout(addr addrs.AbsResourceInstance) time.Duration {
+	return ctx.OperationTimeouts[addr.String()]
+}
@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/hashicorp/terraform/internal/addrs"
@@ -145,6 +147,10 @@ type MockEvalContext struct {
 	MoveResultsCalled  bool
 	MoveResultsResults refactoring.MoveResults
 
+	OperationTimeoutCalled  bool
+	OperationTimeoutAddr    addrs.AbsResourceInstance
+	OperationTimeoutTimeout time.Duration
+
 	InstanceExpanderCalled   bool
 	InstanceExpanderExpander *instances.Expander
 }
@@ -389,6 +395,12 @@ func (c *MockEvalContext) PrevRunState() *states.SyncState {
 	return c.PrevRunStateState
 }
 
+func (c *MockEvalContext) OperationTimeout(addr addrs.AbsResourceInstance) time.Duration {
+	c.OperationTimeoutCalled = true
+	c.OperationTimeoutAddr = addr
+	return c.OperationTimeoutTimeout
+}
+
 func (c *MockEvalContext) MoveResults() refactoring.MoveResults {
 	c.MoveResultsCalled = true
 	return c.MoveResultsResults
@@ -213,6 +213,11 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 				suggestion = fmt.Sprintf("\n\nDid you mean the data resource %s?", candidateAddr)
 			}
 		}
+		// Otherwise, the reference might just be a typo for one of the other
+		// resources of the same mode declared in this module.
+		if suggestion == "" {
+			suggestion = resourceNameSuggestion(modCfg.Module, addr)
+		}
 
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -277,8 +282,10 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.Config, addr addrs.ModuleCall, remain hcl.Traversal, rng tfdiags.SourceRange) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
-	// For now, our focus here is just in testing that the referenced module
-	// call exists. All other validation is deferred until evaluation time.
+	// Our focus here is just in testing that the referenced module call
+	// exists and, if the reference selects an output value, that the child
+	// module declares an output of that name. All other validation is
+	// deferred until evaluation time.
 	_, exists := modCfg.Module.ModuleCalls[addr.Name]
 	if !exists {
 		var suggestions []string
@@ -286,9 +293,9 @@ func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.
 			suggestions = append(suggestions, name)
 		}
 		sort.Strings(suggestions)
-		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
-		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+		var suggestion string
+		if candidates := didyoumean.NameSuggestions(addr.Name, suggestions, 3); len(candidates) > 0 {
+			suggestion = fmt.Sprintf(" Did you mean %s?", didyoumean.SuggestionSentence(candidates))
 		}
 
 		diags = diags.Append(&hcl.Diagnostic{
@@ -300,9 +307,62 @@ func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.
 		return diags
 	}
 
+	if len(remain) > 0 {
+		if step, ok := remain[0].(hcl.TraverseAttr); ok {
+			// An attribute step directly after the module call selects one
+			// of the child module's output values, which we can also check
+			// statically because output names don't vary by instance.
+			childCfg := modCfg.Children[addr.Name]
+			if childCfg == nil {
+				// Should never happen for a declared module call, but we'll
+				// leave this for evaluation to catch rather than crashing.
+				return diags
+			}
+			if _, exists := childCfg.Module.Outputs[step.Name]; !exists {
+				var suggestions []string
+				for name := range childCfg.Module.Outputs {
+					suggestions = append(suggestions, name)
+				}
+				sort.Strings(suggestions)
+				var suggestion string
+				if candidates := didyoumean.NameSuggestions(step.Name, suggestions, 3); len(candidates) > 0 {
+					suggestion = fmt.Sprintf(" Did you mean %s?", didyoumean.SuggestionSentence(candidates))
+				}
+
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  `Reference to undeclared output value`,
+					Detail:   fmt.Sprintf(`An output value with the name %q has not been declared in %s.%s`, step.Name, moduleConfigDisplayAddr(childCfg.Path), suggestion),
+					Subject:  rng.ToHCL().Ptr(),
+				})
+				return diags
+			}
+		}
+	}
+
 	return diags
 }
 
+// resourceNameSuggestion returns a suggestion sentence to append to a
+// "Reference to undeclared resource" message, naming up to three resources of
+// the same mode declared in the given module whose addresses are close to the
+// given address. Returns an empty string if nothing is close enough.
+func resourceNameSuggestion(mod *configs.Module, addr addrs.Resource) string {
+	resources := mod.ManagedResources
+	if addr.Mode == addrs.DataResourceMode {
+		resources = mod.DataResources
+	}
+	var suggestions []string
+	for name := range resources {
+		suggestions = append(suggestions, name)
+	}
+	sort.Strings(suggestions)
+	if candidates := didyoumean.NameSuggestions(addr.String(), suggestions, 3); len(candidates) > 0 {
+		return fmt.Sprintf(" Did you mean %s?", didyoumean.SuggestionSentence(candidates))
+	}
+	return ""
+}
+
 // moduleConfigDisplayAddr returns a string describing the given module
 // address that is appropriate for returning to users in situations where the
 // root module is possible. Specifically, it returns "the root module" if the
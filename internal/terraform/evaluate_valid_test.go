@@ -64,6 +64,26 @@ For example, to correlate with indices of a referring resource, use:
 			"boop_whatever.nope",
 			`Invalid resource type: A managed resource type "boop_whatever" is not supported by provider "registry.terraform.io/foobar/beep".`,
 		},
+		{
+			"aws_instance.no_cout",
+			`Reference to undeclared resource: A managed resource "aws_instance" "no_cout" has not been declared in the root module. Did you mean "aws_instance.no_count"?`,
+		},
+		{
+			"data.beep.bop",
+			`Reference to undeclared resource: A data resource "beep" "bop" has not been declared in the root module. Did you mean "data.beep.boop"?`,
+		},
+		{
+			"module.subm.normal",
+			``,
+		},
+		{
+			"module.subm.normle",
+			`Reference to undeclared output value: An output value with the name "normle" has not been declared in module.subm. Did you mean "normal"?`,
+		},
+		{
+			"module.sbm.normal",
+			`Reference to undeclared module: No module call named "sbm" is declared in the root module. Did you mean "subm"?`,
+		},
 	}
 
 	cfg := testModule(t, "static-validate-refs")
@@ -1,7 +1,43 @@
 package terraform
 
-import "os"
+import (
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+)
 
 // This file holds feature flags for the next release
 
 var flagWarnOutputErrors = os.Getenv("TF_WARN_OUTPUT_ERRORS") != ""
+
+// flagInconsistentApplyWarnProviders is a set of provider addresses for which
+// "Provider produced inconsistent result after apply" problems are downgraded
+// from errors to warnings, populated from a comma-separated list of provider
+// source addresses in TF_PROVIDER_INCONSISTENT_RESULT_WARN.
+//
+// This is an escape hatch for providers with known inconsistency bugs that
+// would otherwise block all applies; the warnings still report full detail so
+// the underlying provider bug remains visible.
+var flagInconsistentApplyWarnProviders = parseInconsistentApplyWarnProviders(os.Getenv("TF_PROVIDER_INCONSISTENT_RESULT_WARN"))
+
+func parseInconsistentApplyWarnProviders(raw string) map[addrs.Provider]bool {
+	if raw == "" {
+		return nil
+	}
+	ret := make(map[addrs.Provider]bool)
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		addr, diags := addrs.ParseProviderSourceString(s)
+		if diags.HasErrors() {
+			// An invalid address here can't do any harm, so we just ignore
+			// it rather than failing the whole run over an env variable.
+			continue
+		}
+		ret[addr] = true
+	}
+	return ret
+}
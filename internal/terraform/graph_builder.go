@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/terraform/internal/addrs"
 	"github.com/hashicorp/terraform/internal/logging"
 	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/hashicorp/terraform/internal/tracing"
 )
 
 // GraphBuilder is an interface that can be implemented and used with
@@ -27,6 +28,10 @@ type BasicGraphBuilder struct {
 }
 
 func (b *BasicGraphBuilder) Build(path addrs.ModuleInstance) (*Graph, tfdiags.Diagnostics) {
+	span := tracing.StartSpan("build graph")
+	span.SetAttribute("graph_name", b.Name)
+	defer span.End()
+
 	var diags tfdiags.Diagnostics
 	g := &Graph{Path: path}
 
@@ -101,6 +101,9 @@ func (b *PlanGraphBuilder) Steps() []GraphTransformer {
 		&LocalTransformer{Config: b.Config},
 		&OutputTransformer{Config: b.Config, RefreshOnly: b.skipPlanChanges},
 
+		// Add module-level precondition blocks
+		&ModulePreconditionTransformer{Config: b.Config},
+
 		// Add orphan resources
 		&OrphanResourceInstanceTransformer{
 			Concrete: b.ConcreteResourceOrphan,
@@ -0,0 +1,127 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/internal/dag"
+	"github.com/hashicorp/terraform/internal/plans"
+)
+
+// GraphImpactDot returns a DOT rendering of only the part of the given graph
+// that is impacted by the changes in the given plan: the resource instances
+// with planned actions other than "no-op", along with everything that
+// directly or transitively depends on one of them. Impacted nodes that have
+// a planned action are annotated with it, so that a reviewer can see the
+// blast radius of a change at a glance.
+//
+// The given graph should be an apply-time graph built from the same plan,
+// so that its vertices represent individual resource instances.
+func GraphImpactDot(g *Graph, plan *plans.Plan) (string, error) {
+	changes := make(map[string]plans.Action)
+	if plan != nil {
+		for _, rc := range plan.Changes.Resources {
+			if rc.Action == plans.NoOp {
+				continue
+			}
+			changes[rc.Addr.String()] = rc.Action
+		}
+	}
+
+	// The impacted set contains every vertex with a planned change and every
+	// vertex that depends on one of those, directly or indirectly.
+	impacted := make(dag.Set)
+	for _, v := range g.Vertices() {
+		iv, ok := v.(GraphNodeResourceInstance)
+		if !ok {
+			continue
+		}
+		if _, changed := changes[iv.ResourceInstanceAddr().String()]; !changed {
+			continue
+		}
+		impacted.Add(v)
+		dependents, err := g.Descendents(v)
+		if err != nil {
+			return "", err
+		}
+		for _, dv := range dependents {
+			impacted.Add(dv)
+		}
+	}
+
+	// As in the full DOT rendering, we only draw vertices that opt in to
+	// being drawn; the others are implementation details of the walk, such
+	// as the root node, which would appear as a dependent of every change.
+	var nodes []string
+	var edges []string
+	drawn := make(dag.Set)
+	for _, v := range g.Vertices() {
+		if !impacted.Include(v) {
+			continue
+		}
+		if _, ok := v.(dag.GraphNodeDotter); !ok {
+			continue
+		}
+		switch v.(type) {
+		case GraphNodeProvider, GraphNodeCloseProvider:
+			// Provider configure/close nodes bracket every use of their
+			// provider, so drawing them would suggest a far larger blast
+			// radius than the changes really have.
+			continue
+		}
+		drawn.Add(v)
+
+		name := dag.VertexName(v)
+		label := name
+		if iv, ok := v.(GraphNodeResourceInstance); ok {
+			if action, changed := changes[iv.ResourceInstanceAddr().String()]; changed {
+				label = fmt.Sprintf("%s (%s)", name, impactActionName(action))
+			}
+		}
+		nodes = append(nodes, fmt.Sprintf("\t\t\"[root] %s\" [label = %q, shape = \"box\"]\n", name, label))
+	}
+	for _, e := range g.Edges() {
+		if !drawn.Include(e.Source()) || !drawn.Include(e.Target()) {
+			continue
+		}
+		edges = append(edges, fmt.Sprintf("\t\t\"[root] %s\" -> \"[root] %s\"\n", dag.VertexName(e.Source()), dag.VertexName(e.Target())))
+	}
+	sort.Strings(nodes)
+	sort.Strings(edges)
+
+	var buf strings.Builder
+	buf.WriteString("digraph {\n")
+	buf.WriteString("\tcompound = \"true\"\n")
+	buf.WriteString("\tnewrank = \"true\"\n")
+	buf.WriteString("\tsubgraph \"root\" {\n")
+	for _, n := range nodes {
+		buf.WriteString(n)
+	}
+	for _, e := range edges {
+		buf.WriteString(e)
+	}
+	buf.WriteString("\t}\n")
+	buf.WriteString("}\n")
+	return buf.String(), nil
+}
+
+// impactActionName returns the annotation used in GraphImpactDot node labels
+// for the given planned action, using the same vocabulary as the human-
+// readable plan rendering.
+func impactActionName(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Read:
+		return "read"
+	case plans.Update:
+		return "update"
+	case plans.Delete:
+		return "delete"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace"
+	default:
+		return strings.ToLower(action.String())
+	}
+}
@@ -0,0 +1,99 @@
+package terraform
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hashicorp/terraform/internal/dag"
+)
+
+// graphJSON is the top-level object of the JSON graph serialization
+// produced by GraphJSON.
+type graphJSON struct {
+	FormatVersion string          `json:"format_version"`
+	Nodes         []graphNodeJSON `json:"nodes"`
+	Edges         []graphEdgeJSON `json:"edges"`
+}
+
+// graphNodeJSON describes a single vertex of the graph.
+type graphNodeJSON struct {
+	// Name is the same label used for this node in the DOT rendering, and
+	// identifies the node within the graph. Edges refer to nodes by name.
+	Name string `json:"name"`
+
+	// Type describes what kind of object the node represents: one of
+	// "resource", "resource_instance", "provider", or "other".
+	Type string `json:"type"`
+
+	// Address is the resource or provider configuration address the node
+	// relates to. Omitted for nodes of type "other".
+	Address string `json:"address,omitempty"`
+
+	// Provider is the fully-qualified name of the provider the node belongs
+	// to. Omitted for nodes that aren't associated with a provider.
+	Provider string `json:"provider,omitempty"`
+}
+
+// graphEdgeJSON describes a single dependency edge, pointing from the
+// dependent node to the node it depends on.
+type graphEdgeJSON struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// GraphJSON returns a machine-readable JSON representation of the given
+// Terraform graph, with a node per vertex and an edge per dependency, as
+// an alternative to the DOT rendering produced by GraphDot.
+func GraphJSON(g *Graph) ([]byte, error) {
+	out := graphJSON{
+		FormatVersion: "1.0",
+		Nodes:         []graphNodeJSON{},
+		Edges:         []graphEdgeJSON{},
+	}
+
+	for _, v := range g.Vertices() {
+		node := graphNodeJSON{
+			Name: dag.VertexName(v),
+			Type: "other",
+		}
+		// The order of these cases is important: a node representing a
+		// single resource instance typically also implements the
+		// whole-resource interface.
+		switch tv := v.(type) {
+		case GraphNodeResourceInstance:
+			node.Type = "resource_instance"
+			node.Address = tv.ResourceInstanceAddr().String()
+		case GraphNodeConfigResource:
+			node.Type = "resource"
+			node.Address = tv.ResourceAddr().String()
+		case GraphNodeProvider:
+			node.Type = "provider"
+			node.Address = tv.ProviderAddr().String()
+		}
+		switch tv := v.(type) {
+		case GraphNodeProviderConsumer:
+			node.Provider = tv.Provider().String()
+		case GraphNodeProvider:
+			node.Provider = tv.ProviderAddr().Provider.String()
+		}
+		out.Nodes = append(out.Nodes, node)
+	}
+	sort.Slice(out.Nodes, func(i, j int) bool {
+		return out.Nodes[i].Name < out.Nodes[j].Name
+	})
+
+	for _, e := range g.Edges() {
+		out.Edges = append(out.Edges, graphEdgeJSON{
+			Source: dag.VertexName(e.Source()),
+			Target: dag.VertexName(e.Target()),
+		})
+	}
+	sort.Slice(out.Edges, func(i, j int) bool {
+		if out.Edges[i].Source != out.Edges[j].Source {
+			return out.Edges[i].Source < out.Edges[j].Source
+		}
+		return out.Edges[i].Target < out.Edges[j].Target
+	})
+
+	return json.MarshalIndent(out, "", "  ")
+}
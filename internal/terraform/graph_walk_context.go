@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/terraform/internal/refactoring"
 	"github.com/hashicorp/terraform/internal/states"
 	"github.com/hashicorp/terraform/internal/tfdiags"
+	"github.com/hashicorp/terraform/internal/tracing"
 )
 
 // ContextGraphWalker is the GraphWalker implementation used with the
@@ -93,6 +94,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		InstanceExpanderValue: w.InstanceExpander,
 		Plugins:               w.Context.plugins,
 		MoveResultsValue:      w.MoveResults,
+		OperationTimeouts:     w.Context.operationTimeouts,
 		ProviderCache:         w.providerCache,
 		ProviderInputConfig:   w.Context.providerInputConfig,
 		ProviderLock:          &w.providerLock,
@@ -127,10 +129,48 @@ func (w *ContextGraphWalker) init() {
 	}
 }
 
+// GraphNodeApplyPriority is implemented by graph nodes whose scheduling
+// during the apply walks can be biased by configuration. A higher value
+// makes the node wait behind other ready nodes whenever the parallelism
+// limit forces a choice between them; zero is the neutral default. The
+// priority never overrides dependency ordering.
+type GraphNodeApplyPriority interface {
+	ApplyPriority() int
+}
+
 func (w *ContextGraphWalker) Execute(ctx EvalContext, n GraphNodeExecutable) tfdiags.Diagnostics {
-	// Acquire a lock on the semaphore
-	w.Context.parallelSem.Acquire()
-	defer w.Context.parallelSem.Release()
+	// Acquire a lock on the semaphore. Refreshing happens as part of the
+	// planning walk, so a planning walk gets the separate refresh
+	// parallelism limit when one is configured.
+	sem := w.Context.parallelSem
+	if w.Operation == walkPlan && w.Context.refreshParallelSem != nil {
+		sem = w.Context.refreshParallelSem
+	}
+
+	// During the apply walks a node can declare an apply priority, which
+	// biases which of the currently-ready nodes gets a slot next whenever
+	// more nodes are ready than the parallelism limit allows. The
+	// dependency ordering enforced by the graph itself always takes
+	// precedence, because a node doesn't contend for a slot at all until
+	// everything it depends on has completed.
+	priority := 0
+	if w.Operation == walkApply || w.Operation == walkDestroy {
+		if pn, ok := n.(GraphNodeApplyPriority); ok {
+			priority = pn.ApplyPriority()
+		}
+	}
+	sem.Acquire(priority)
+	defer sem.Release()
+
+	// Resource instances are where most of the walk time goes, so they
+	// each get their own span when tracing is enabled. The span records
+	// only the instance address and operation, never any attribute values.
+	if rn, ok := n.(GraphNodeResourceInstance); ok {
+		span := tracing.StartSpan("resource instance")
+		span.SetAttribute("resource_address", rn.ResourceInstanceAddr().String())
+		span.SetAttribute("operation", w.Operation.String())
+		defer span.End()
+	}
 
 	return n.Execute(ctx, w.Operation)
 }
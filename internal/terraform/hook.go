@@ -71,6 +71,12 @@ type Hook interface {
 	PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error)
 	PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value, newState cty.Value) (HookAction, error)
 
+	// RefreshTotal is called once before a graph walk that will refresh
+	// resource instances, giving the total number of instances the walk
+	// expects to refresh so that hook implementations can report overall
+	// progress from their PostRefresh calls.
+	RefreshTotal(total int) (HookAction, error)
+
 	// PreImportState and PostImportState are called before and after
 	// (respectively) each state import operation for a given resource address.
 	PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error)
@@ -132,6 +138,10 @@ func (*NilHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generatio
 	return HookActionContinue, nil
 }
 
+func (*NilHook) RefreshTotal(total int) (HookAction, error) {
+	return HookActionContinue, nil
+}
+
 func (*NilHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	return HookActionContinue, nil
 }
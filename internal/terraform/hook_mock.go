@@ -96,6 +96,11 @@ type MockHook struct {
 	PostRefreshReturn     HookAction
 	PostRefreshError      error
 
+	RefreshTotalCalled bool
+	RefreshTotalValue  int
+	RefreshTotalReturn HookAction
+	RefreshTotalError  error
+
 	PreImportStateCalled bool
 	PreImportStateAddr   addrs.AbsResourceInstance
 	PreImportStateID     string
@@ -244,6 +249,15 @@ func (h *MockHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Genera
 	return h.PostRefreshReturn, h.PostRefreshError
 }
 
+func (h *MockHook) RefreshTotal(total int) (HookAction, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.RefreshTotalCalled = true
+	h.RefreshTotalValue = total
+	return h.RefreshTotalReturn, h.RefreshTotalError
+}
+
 func (h *MockHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	h.Lock()
 	defer h.Unlock()
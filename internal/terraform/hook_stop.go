@@ -63,6 +63,10 @@ func (h *stopHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Genera
 	return h.hook()
 }
 
+func (h *stopHook) RefreshTotal(total int) (HookAction, error) {
+	return h.hook()
+}
+
 func (h *stopHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	return h.hook()
 }
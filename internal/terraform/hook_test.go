@@ -110,6 +110,13 @@ func (h *testHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Genera
 	return HookActionContinue, nil
 }
 
+func (h *testHook) RefreshTotal(total int) (HookAction, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Calls = append(h.Calls, &testHookCall{"RefreshTotal", ""})
+	return HookActionContinue, nil
+}
+
 func (h *testHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
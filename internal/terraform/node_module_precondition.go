@@ -0,0 +1,123 @@
+package terraform
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/internal/addrs"
+	"github.com/hashicorp/terraform/internal/configs"
+	"github.com/hashicorp/terraform/internal/lang"
+	"github.com/hashicorp/terraform/internal/tfdiags"
+)
+
+// nodeExpandModulePreconditions represents the module-level precondition
+// blocks of a module that has not yet been expanded.
+type nodeExpandModulePreconditions struct {
+	Module addrs.Module
+	Config []*configs.CheckRule
+}
+
+var (
+	_ GraphNodeModulePath        = (*nodeExpandModulePreconditions)(nil)
+	_ GraphNodeReferencer        = (*nodeExpandModulePreconditions)(nil)
+	_ GraphNodeDynamicExpandable = (*nodeExpandModulePreconditions)(nil)
+	_ graphNodeExpandsInstances  = (*nodeExpandModulePreconditions)(nil)
+)
+
+func (n *nodeExpandModulePreconditions) expandsInstances() {}
+
+func (n *nodeExpandModulePreconditions) Name() string {
+	path := n.Module.String()
+	if path != "" {
+		return path + ".preconditions (expand)"
+	}
+	return "preconditions (expand)"
+}
+
+// GraphNodeModulePath
+func (n *nodeExpandModulePreconditions) ModulePath() addrs.Module {
+	return n.Module
+}
+
+// GraphNodeReferencer
+func (n *nodeExpandModulePreconditions) References() []*addrs.Reference {
+	return referencesForModulePreconditions(n.Config)
+}
+
+func (n *nodeExpandModulePreconditions) DynamicExpand(ctx EvalContext) (*Graph, error) {
+	var g Graph
+	expander := ctx.InstanceExpander()
+	for _, module := range expander.ExpandModule(n.Module) {
+		o := &nodeModulePreconditions{
+			Addr:   module,
+			Config: n.Config,
+		}
+		log.Printf("[TRACE] Expanding module preconditions: adding %s as %T", o.Name(), o)
+		g.Add(o)
+	}
+	return &g, nil
+}
+
+// nodeModulePreconditions represents the module-level precondition blocks of
+// a particular module instance.
+type nodeModulePreconditions struct {
+	Addr   addrs.ModuleInstance
+	Config []*configs.CheckRule
+}
+
+var (
+	_ GraphNodeModuleInstance = (*nodeModulePreconditions)(nil)
+	_ GraphNodeReferencer     = (*nodeModulePreconditions)(nil)
+	_ GraphNodeExecutable     = (*nodeModulePreconditions)(nil)
+)
+
+func (n *nodeModulePreconditions) Name() string {
+	path := n.Addr.String()
+	if path != "" {
+		return path + ".preconditions"
+	}
+	return "preconditions"
+}
+
+// GraphNodeModuleInstance
+func (n *nodeModulePreconditions) Path() addrs.ModuleInstance {
+	return n.Addr
+}
+
+// GraphNodeModulePath
+func (n *nodeModulePreconditions) ModulePath() addrs.Module {
+	return n.Addr.Module()
+}
+
+// GraphNodeReferencer
+func (n *nodeModulePreconditions) References() []*addrs.Reference {
+	return referencesForModulePreconditions(n.Config)
+}
+
+// GraphNodeExecutable
+// nodeModulePreconditions.Execute evaluates all of the module's precondition
+// blocks against the module instance's evaluation scope. Conditions with
+// unknown results are silently deferred, so during validate, where the input
+// variable values are not yet decided, only constant violations can be
+// detected.
+func (n *nodeModulePreconditions) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
+	return evalCheckRules(
+		addrs.ModulePrecondition,
+		n.Config,
+		ctx, n.Addr, EvalDataForNoInstanceKey,
+		tfdiags.Error,
+	)
+}
+
+// referencesForModulePreconditions collects all of the references in the
+// condition and error message expressions of the given check rules, so that
+// the graph can order precondition evaluation after everything it refers to.
+func referencesForModulePreconditions(rules []*configs.CheckRule) []*addrs.Reference {
+	var refs []*addrs.Reference
+	for _, rule := range rules {
+		condRefs, _ := lang.ReferencesInExpr(rule.Condition)
+		refs = append(refs, condRefs...)
+		errRefs, _ := lang.ReferencesInExpr(rule.ErrorMessage)
+		refs = append(refs, errRefs...)
+	}
+	return refs
+}
@@ -3,9 +3,12 @@ package terraform
 import (
 	"fmt"
 	"log"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform/internal/configs"
 	"github.com/hashicorp/terraform/internal/configs/configschema"
+	"github.com/hashicorp/terraform/internal/didyoumean"
 	"github.com/hashicorp/terraform/internal/providers"
 	"github.com/hashicorp/terraform/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
@@ -76,6 +79,14 @@ func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider provi
 		configSchema = &configschema.Block{}
 	}
 
+	// If this configuration was synthesized from a provider_override block
+	// then the overridden argument names are encoded in our own alias, and
+	// each of them must be one the provider has declared as safe to
+	// override on a per-resource basis.
+	if _, overrideValues, ok := configs.DecodeProviderOverrideAlias(n.Addr.Alias); ok {
+		diags = diags.Append(n.validateProviderOverrideValues(overrideValues, schemaResp.OverrideSafeAttributes))
+	}
+
 	configVal, _, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, EvalDataForNoInstanceKey)
 	if evalDiags.HasErrors() {
 		return diags.Append(evalDiags)
@@ -96,6 +107,56 @@ func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider provi
 	return diags
 }
 
+// validateProviderOverrideValues checks that every argument overridden by
+// the provider_override block that produced this synthesized provider
+// configuration is one the provider has declared as safe to override.
+func (n *NodeApplyableProvider) validateProviderOverrideValues(values map[string]cty.Value, safeAttrs []string) (diags tfdiags.Diagnostics) {
+	safe := make(map[string]struct{}, len(safeAttrs))
+	for _, name := range safeAttrs {
+		safe[name] = struct{}{}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var subject *hcl.Range
+	if n.Config != nil {
+		subject = n.Config.NameRange.Ptr()
+	}
+
+	for _, name := range names {
+		if _, ok := safe[name]; ok {
+			continue
+		}
+
+		if len(safeAttrs) == 0 {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Provider does not support per-resource overrides",
+				Detail:   fmt.Sprintf("Provider %s does not declare any of its configuration arguments as safe to override on a per-resource basis, so provider_override cannot be used with it.", n.Addr.Provider),
+				Subject:  subject,
+			})
+			break
+		}
+
+		var suggestion string
+		if candidates := didyoumean.NameSuggestions(name, safeAttrs, 3); len(candidates) > 0 {
+			suggestion = fmt.Sprintf(" Did you mean %s?", didyoumean.SuggestionSentence(candidates))
+		}
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Provider argument cannot be overridden",
+			Detail:   fmt.Sprintf("Provider %s does not declare %q as safe to override on a per-resource basis.%s", n.Addr.Provider, name, suggestion),
+			Subject:  subject,
+		})
+	}
+
+	return diags
+}
+
 // ConfigureProvider configures a provider that is already initialized and retrieved.
 // If verifyConfigIsKnown is true, ConfigureProvider will return an error if the
 // provider configVal is not wholly known and is meant only for use during import.
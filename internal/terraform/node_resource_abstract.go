@@ -114,6 +114,14 @@ func (n *NodeAbstractResource) Name() string {
 	return n.ResourceAddr().String()
 }
 
+// GraphNodeApplyPriority
+func (n *NodeAbstractResource) ApplyPriority() int {
+	if n.Config != nil && n.Config.Managed != nil {
+		return n.Config.Managed.ApplyPriority
+	}
+	return 0
+}
+
 // GraphNodeModulePath
 func (n *NodeAbstractResource) ModulePath() addrs.Module {
 	return n.Addr.Module
@@ -459,13 +467,13 @@ func (n *NodeAbstractResource) readResourceInstanceStateDeposed(ctx EvalContext,
 // graphNodesAreResourceInstancesInDifferentInstancesOfSameModule is an
 // annoyingly-task-specific helper function that returns true if and only if
 // the following conditions hold:
-// - Both of the given vertices represent specific resource instances, as
-//   opposed to unexpanded resources or any other non-resource-related object.
-// - The module instance addresses for both of the resource instances belong
-//   to the same static module.
-// - The module instance addresses for both of the resource instances are
-//   not equal, indicating that they belong to different instances of the
-//   same module.
+//   - Both of the given vertices represent specific resource instances, as
+//     opposed to unexpanded resources or any other non-resource-related object.
+//   - The module instance addresses for both of the resource instances belong
+//     to the same static module.
+//   - The module instance addresses for both of the resource instances are
+//     not equal, indicating that they belong to different instances of the
+//     same module.
 //
 // This result can be used as a way to compensate for the effects of
 // conservative analyses passes in our graph builders which make their
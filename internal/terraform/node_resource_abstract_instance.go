@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/terraform/internal/addrs"
@@ -2073,14 +2074,57 @@ func (n *NodeAbstractResourceInstance) apply(
 		return newState, keyData, diags
 	}
 
-	resp := provider.ApplyResourceChange(providers.ApplyResourceChangeRequest{
+	applyReq := providers.ApplyResourceChangeRequest{
 		TypeName:       n.Addr.Resource.Resource.Type,
 		PriorState:     unmarkedBefore,
 		Config:         unmarkedConfigVal,
 		PlannedState:   unmarkedAfter,
 		PlannedPrivate: change.Private,
 		ProviderMeta:   metaConfigVal,
-	})
+	}
+
+	var resp providers.ApplyResourceChangeResponse
+	if timeout := ctx.OperationTimeout(n.Addr); timeout > 0 {
+		// The user has placed a deadline on provider calls for this
+		// particular resource instance, typically because the provider is
+		// known to sometimes hang for it. The provider protocol has no way
+		// to cancel an individual call, so on timeout we abandon the call
+		// and let the goroutine running it terminate along with the
+		// provider when the walk finishes.
+		respCh := make(chan providers.ApplyResourceChangeResponse, 1)
+		go func() {
+			respCh <- provider.ApplyResourceChange(applyReq)
+		}()
+		timer := time.NewTimer(timeout)
+		select {
+		case resp = <-respCh:
+			timer.Stop()
+		case <-timer.C:
+			log.Printf("[WARN] %s: provider did not complete the %s operation within the %s operation timeout; abandoning the call", n.Addr, change.Action, timeout)
+
+			var timeoutDiags tfdiags.Diagnostics
+			timeoutDiags = timeoutDiags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Operation timeout exceeded",
+				fmt.Sprintf(
+					"The provider did not complete the %s operation for %s within the %s deadline given in the -operation-timeout option, so Terraform abandoned the call.\n\nThe provider may still complete the operation in the background, so the true status of the remote object is unknown. Terraform has recorded the object as tainted so that the next plan will propose to replace it.",
+					change.Action, n.Addr, timeout,
+				),
+			))
+			// We have no response to work with, so we'll keep the prior
+			// state for the object, marked as tainted since we can't know
+			// whether the provider partially completed the operation.
+			resp = providers.ApplyResourceChangeResponse{
+				NewState:    unmarkedBefore,
+				Private:     state.Private,
+				Diagnostics: timeoutDiags,
+			}
+			state = state.DeepCopy()
+			state.Status = states.ObjectTainted
+		}
+	} else {
+		resp = provider.ApplyResourceChange(applyReq)
+	}
 	applyDiags := resp.Diagnostics
 	if applyConfig != nil {
 		applyDiags = applyDiags.InConfigBody(applyConfig.Config, n.Addr.String())
@@ -2216,14 +2260,18 @@ func (n *NodeAbstractResourceInstance) apply(
 				// error that incorrectly blames the downstream resource for the change.
 
 			} else {
+				severity := tfdiags.Error
+				if flagInconsistentApplyWarnProviders[n.ResolvedProvider.Provider] {
+					// The user has explicitly asked to tolerate inconsistent
+					// results from this provider, so we'll report the problem
+					// but allow the apply to complete.
+					severity = tfdiags.Warning
+				}
 				for _, err := range errs {
 					diags = diags.Append(tfdiags.Sourceless(
-						tfdiags.Error,
+						severity,
 						"Provider produced inconsistent result after apply",
-						fmt.Sprintf(
-							"When applying changes to %s, provider %q produced an unexpected new value: %s.\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.",
-							n.Addr, n.ResolvedProvider.String(), tfdiags.FormatError(err),
-						),
+						inconsistentResultDetail(n.Addr, n.ResolvedProvider, err, change.After, newVal),
 					))
 				}
 			}
@@ -2302,6 +2350,55 @@ func (n *NodeAbstractResourceInstance) apply(
 	}
 }
 
+// inconsistentResultDetail produces the detail text for a "Provider produced
+// inconsistent result after apply" diagnostic. As well as describing the
+// problem itself it includes the planned and actual values at the offending
+// path where we're able to extract them, so that the user can include
+// meaningful detail when reporting the bug to the provider developer without
+// needing to re-run the operation with trace logging enabled.
+func inconsistentResultDetail(addr addrs.AbsResourceInstance, provider addrs.AbsProviderConfig, err error, planned, actual cty.Value) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf,
+		"When applying changes to %s, provider %q produced an unexpected new value: %s.",
+		addr, provider.String(), tfdiags.FormatError(err),
+	)
+
+	if pathErr, ok := err.(cty.PathError); ok && len(pathErr.Path) > 0 {
+		fmt.Fprintf(&buf,
+			"\n\nWhen reporting this to the provider developer, include the following values for %s:\n    Planned value: %s\n    Actual value:  %s",
+			tfdiags.FormatCtyPath(pathErr.Path),
+			inconsistentResultValueStr(planned, pathErr.Path),
+			inconsistentResultValueStr(actual, pathErr.Path),
+		)
+	}
+
+	buf.WriteString("\n\nThis is a bug in the provider, which should be reported in the provider's own issue tracker.")
+	return buf.String()
+}
+
+// inconsistentResultValueStr renders the value at the given path inside the
+// given value for inclusion in an inconsistent-result diagnostic, redacting
+// it if it (or any container it belongs to) is marked as sensitive.
+func inconsistentResultValueStr(val cty.Value, path cty.Path) string {
+	if val == cty.NilVal {
+		return "(none)"
+	}
+	if got, err := path.Apply(val); err == nil {
+		if got.IsMarked() {
+			return "(sensitive value)"
+		}
+		return got.GoString()
+	}
+	// Path.Apply can fail when traversing a marked collection, so we'll
+	// retry against an unmarked copy but then redact the result, since we
+	// can no longer tell whether this particular value was sensitive.
+	unmarked, _ := val.UnmarkDeep()
+	if _, err := path.Apply(unmarked); err == nil && val.ContainsMarked() {
+		return "(sensitive value)"
+	}
+	return "(value not present)"
+}
+
 func (n *NodeAbstractResourceInstance) prevRunAddr(ctx EvalContext) addrs.AbsResourceInstance {
 	return resourceInstancePrevRunAddr(ctx, n.Addr)
 }
@@ -54,6 +54,8 @@ func getProviderSchemaResponseFromProviderSchema(providerSchema *ProviderSchema)
 		ProviderMeta:  providers.Schema{Block: providerSchema.ProviderMeta},
 		ResourceTypes: map[string]providers.Schema{},
 		DataSources:   map[string]providers.Schema{},
+
+		OverrideSafeAttributes: providerSchema.OverrideSafeAttributes,
 	}
 
 	for name, schema := range providerSchema.ResourceTypes {
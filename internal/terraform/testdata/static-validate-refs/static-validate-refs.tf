@@ -21,3 +21,6 @@ resource "boop_whatever" "nope" {
 
 data "beep" "boop" {
 }
+module "subm" {
+  source = "./subm"
+}
@@ -0,0 +1,3 @@
+output "normal" {
+  value = "yep"
+}
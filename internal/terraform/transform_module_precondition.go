@@ -0,0 +1,40 @@
+package terraform
+
+import (
+	"github.com/hashicorp/terraform/internal/configs"
+)
+
+// ModulePreconditionTransformer is a GraphTransformer that adds a node for
+// the module-level precondition blocks of each module in the configuration
+// that declares any.
+type ModulePreconditionTransformer struct {
+	Config *configs.Config
+}
+
+func (t *ModulePreconditionTransformer) Transform(g *Graph) error {
+	return t.transformModule(g, t.Config)
+}
+
+func (t *ModulePreconditionTransformer) transformModule(g *Graph, c *configs.Config) error {
+	if c == nil {
+		// Can't have any preconditions if there's no config
+		return nil
+	}
+
+	if len(c.Module.Preconditions) > 0 {
+		node := &nodeExpandModulePreconditions{
+			Module: c.Path,
+			Config: c.Module.Preconditions,
+		}
+		g.Add(node)
+	}
+
+	// Also populate preconditions for child modules
+	for _, cc := range c.Children {
+		if err := t.transformModule(g, cc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -319,6 +319,24 @@ func (t *MissingProviderTransformer) Transform(g *Graph) error {
 			continue
 		}
 
+		// If the consumer requires an exact provider configuration whose
+		// alias was synthesized from a provider_override block -- which can
+		// happen when the resource that declared the override has since been
+		// removed from the configuration, leaving only its record in the
+		// state -- then we can reconstruct that configuration from the
+		// override values encoded in the alias itself, so that the remote
+		// object can still be refreshed and destroyed.
+		if addr, exact := pv.ProvidedBy(); exact {
+			if absAddr, ok := addr.(addrs.AbsProviderConfig); ok && absAddr.Alias != "" {
+				if _, exists := m[absAddr.String()]; !exists {
+					if provider := t.maybeSynthesizeOverrideProvider(absAddr); provider != nil {
+						g.Add(provider)
+						m[absAddr.String()] = provider
+					}
+				}
+			}
+		}
+
 		// For our work here we actually care only about the provider type and
 		// we plan to place all default providers in the root module.
 		providerFqn := pv.Provider()
@@ -350,6 +368,47 @@ func (t *MissingProviderTransformer) Transform(g *Graph) error {
 	return err
 }
 
+// maybeSynthesizeOverrideProvider reconstructs a provider configuration node
+// for the given address if and only if its alias encodes the values of a
+// provider_override block, merging those values over the base provider
+// configuration in the same module when one is still declared. It returns
+// nil if the alias is not a synthetic override alias or if the containing
+// module is no longer present in the configuration.
+func (t *MissingProviderTransformer) maybeSynthesizeOverrideProvider(addr addrs.AbsProviderConfig) GraphNodeProvider {
+	baseAlias, values, ok := configs.DecodeProviderOverrideAlias(addr.Alias)
+	if !ok || t.Config == nil {
+		return nil
+	}
+	modCfg := t.Config.Descendent(addr.Module)
+	if modCfg == nil {
+		return nil
+	}
+
+	localName := modCfg.Module.LocalNameForProvider(addr.Provider)
+	var body hcl.Body = configs.SynthBody(fmt.Sprintf("%s provider_override", addr), values)
+	declRange := hcl.Range{Filename: fmt.Sprintf("<%s>", addr)}
+	baseKey := localName
+	if baseAlias != "" {
+		baseKey = fmt.Sprintf("%s.%s", localName, baseAlias)
+	}
+	if base, exists := modCfg.Module.ProviderConfigs[baseKey]; exists {
+		body = configs.MergeBodies(base.Config, body)
+		declRange = base.DeclRange
+	}
+
+	log.Printf("[DEBUG] reconstructing provider configuration %s from the provider_override values recorded in state", addr)
+
+	return t.Concrete(&NodeAbstractProvider{
+		Addr: addr,
+		Config: &configs.Provider{
+			Name:      localName,
+			Alias:     addr.Alias,
+			Config:    body,
+			DeclRange: declRange,
+		},
+	}).(GraphNodeProvider)
+}
+
 // PruneProviderTransformer removes any providers that are not actually used by
 // anything, and provider proxies. This avoids the provider being initialized
 // and configured.  This both saves resources but also avoids errors since
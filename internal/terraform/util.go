@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"sort"
+	"sync"
 )
 
 // Semaphore is a wrapper around a channel to provide
@@ -46,6 +47,71 @@ func (s Semaphore) Release() {
 	}
 }
 
+// PrioritySemaphore is a semaphore whose waiters each declare a scheduling
+// priority, and whose released slots go to the waiter with the lowest
+// priority value, first-come-first-served among equal priorities. The graph
+// walk uses this to bias which of the currently-ready nodes runs next when
+// more nodes are ready than the parallelism limit allows, without affecting
+// ordering in any other way.
+type PrioritySemaphore struct {
+	mu      sync.Mutex
+	avail   int
+	waiters []*semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	priority int
+	ready    chan struct{}
+}
+
+// NewPrioritySemaphore creates a priority semaphore that allows up to a
+// given limit of simultaneous acquisitions.
+func NewPrioritySemaphore(n int) *PrioritySemaphore {
+	if n <= 0 {
+		panic("semaphore with limit <=0")
+	}
+	return &PrioritySemaphore{avail: n}
+}
+
+// Acquire is used to acquire an available slot, blocking until one is
+// available. When several callers are blocked at once, slots are granted in
+// increasing order of their declared priorities.
+func (s *PrioritySemaphore) Acquire(priority int) {
+	s.mu.Lock()
+	if s.avail > 0 {
+		s.avail--
+		s.mu.Unlock()
+		return
+	}
+	waiter := &semaphoreWaiter{
+		priority: priority,
+		ready:    make(chan struct{}),
+	}
+	s.waiters = append(s.waiters, waiter)
+	s.mu.Unlock()
+	<-waiter.ready
+}
+
+// Release is used to return a slot. Acquire must be called as a
+// pre-condition.
+func (s *PrioritySemaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.waiters) == 0 {
+		s.avail++
+		return
+	}
+	best := 0
+	for i, waiter := range s.waiters {
+		if waiter.priority < s.waiters[best].priority {
+			best = i
+		}
+	}
+	waiter := s.waiters[best]
+	s.waiters = append(s.waiters[:best], s.waiters[best+1:]...)
+	close(waiter.ready)
+}
+
 // strSliceContains checks if a given string is contained in a slice
 // When anybody asks why Go needs generics, here you go.
 func strSliceContains(haystack []string, needle string) bool {
@@ -34,6 +34,62 @@ func TestSemaphore(t *testing.T) {
 	s.Release()
 }
 
+func TestPrioritySemaphore(t *testing.T) {
+	s := NewPrioritySemaphore(1)
+	timer := time.AfterFunc(5*time.Second, func() {
+		panic("deadlock")
+	})
+	defer timer.Stop()
+
+	s.Acquire(0) // take the only slot
+
+	// waitForWaiters blocks until the given number of goroutines are
+	// queued on the semaphore, so that we can line up contenders in a
+	// known order.
+	waitForWaiters := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			s.mu.Lock()
+			count := len(s.waiters)
+			s.mu.Unlock()
+			if count >= n {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %d waiters", n)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Contenders arrive in the order 5, 0, 3, but must be granted the
+	// slot in increasing priority order regardless.
+	acquired := make(chan int, 3)
+	for i, priority := range []int{5, 0, 3} {
+		priority := priority
+		go func() {
+			s.Acquire(priority)
+			acquired <- priority
+		}()
+		waitForWaiters(i + 1)
+	}
+
+	for _, want := range []int{0, 3, 5} {
+		s.Release()
+		if got := <-acquired; got != want {
+			t.Fatalf("wrong waiter acquired the slot: got priority %d, want %d", got, want)
+		}
+	}
+
+	// The final waiter still holds the slot; releasing it returns the
+	// semaphore to its full capacity.
+	s.Release()
+	if s.avail != 1 {
+		t.Fatalf("wrong available count %d after all releases; want 1", s.avail)
+	}
+}
+
 func TestStrSliceContains(t *testing.T) {
 	if strSliceContains(nil, "foo") {
 		t.Fatalf("Bad")
@@ -0,0 +1,338 @@
+// Package tracing is a minimal, self-contained tracing facility for the
+// Terraform CLI which exports spans using the JSON encoding of the
+// OpenTelemetry protocol over HTTP (OTLP/HTTP), so that the resulting
+// traces can be ingested by any OpenTelemetry-compatible collector.
+//
+// Tracing is entirely disabled by default and all of the entry points in
+// this package reduce to inexpensive no-ops in that case, so callers can
+// instrument interesting phases unconditionally without worrying about
+// the cost for users who haven't opted in.
+//
+// Span names and attributes are restricted to the sort of identifiers
+// Terraform would also write to its own logs: phase names, resource
+// addresses, durations, and similar. Callers must never record
+// configuration, state, or variable _values_ as attributes, because
+// traces are typically sent to shared collection systems that are not
+// appropriate places for potentially-sensitive data.
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// EndpointEnvVar is the name of the environment variable which enables
+// trace export when set to the base URL of an OTLP/HTTP collector. It
+// takes priority over the trace_endpoint setting in the CLI configuration.
+const EndpointEnvVar = "TF_TRACE_ENDPOINT"
+
+// exportTimeout is how long Shutdown will wait for the collector to
+// accept the export request before giving up.
+const exportTimeout = 10 * time.Second
+
+var (
+	globalMu sync.Mutex
+	global   *tracer
+)
+
+// Enable activates trace collection for the remainder of the process,
+// sending the collected spans to the OTLP/HTTP collector at the given
+// endpoint URL when Shutdown is called. The version argument populates
+// the service.version resource attribute, and should be the full
+// Terraform version string.
+//
+// If the endpoint URL has no path component then the standard OTLP/HTTP
+// traces path "/v1/traces" is appended, matching the behavior of the
+// official OpenTelemetry SDKs for a base endpoint URL.
+func Enable(endpoint string, version string) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = newTracer(endpoint, version)
+}
+
+// Enabled returns true if trace collection is currently active.
+func Enabled() bool {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	return global != nil
+}
+
+// StartSpan begins a new span with the given name, which the caller must
+// eventually close by calling End. If tracing is not enabled then it
+// returns nil, which is a valid receiver for all of the Span methods, so
+// callers don't need to check Enabled first.
+//
+// Spans represent coarse phases of Terraform's work, such as "init
+// installing providers" or "plan resource instance", and are all
+// recorded as direct children of a root span covering the whole command.
+func StartSpan(name string) *Span {
+	globalMu.Lock()
+	t := global
+	globalMu.Unlock()
+	if t == nil {
+		return nil
+	}
+	return t.startSpan(name)
+}
+
+// Shutdown ends trace collection and exports all of the collected spans
+// to the configured collector, blocking until the export has completed
+// or failed. It does nothing and returns no error if tracing was never
+// enabled.
+//
+// A trace export failure never affects the outcome of the command that
+// was being traced, so callers should report the returned error only as
+// a log message or warning.
+func Shutdown() error {
+	globalMu.Lock()
+	t := global
+	global = nil
+	globalMu.Unlock()
+	if t == nil {
+		return nil
+	}
+	return t.shutdown()
+}
+
+// Span represents a single named, timed operation within a trace. A nil
+// *Span is valid and all of its methods are no-ops, which is how this
+// package avoids imposing any overhead when tracing is disabled.
+type Span struct {
+	tracer *tracer
+	id     [8]byte
+	parent [8]byte
+	name   string
+	start  time.Time
+	end    time.Time
+	attrs  []spanAttr
+}
+
+type spanAttr struct {
+	key   string
+	value string
+}
+
+// SetAttribute records a string attribute on the span. Attribute values
+// must be identifiers such as resource addresses or phase names, never
+// values from the configuration or state.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.attrs = append(s.attrs, spanAttr{key, value})
+}
+
+// End closes the span, recording its end time. A span must not be used
+// again after End is called.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.tracer.endSpan(s)
+}
+
+// tracer accumulates the spans for a single trace, covering one run of
+// the Terraform CLI.
+type tracer struct {
+	mu       sync.Mutex
+	endpoint string
+	version  string
+	traceID  [16]byte
+	root     *Span
+	finished []*Span
+}
+
+func newTracer(endpoint string, version string) *tracer {
+	t := &tracer{
+		endpoint: endpoint,
+		version:  version,
+	}
+	randomBytes(t.traceID[:])
+	t.root = &Span{
+		tracer: t,
+		name:   "terraform",
+		start:  time.Now(),
+	}
+	randomBytes(t.root.id[:])
+	return t
+}
+
+func (t *tracer) startSpan(name string) *Span {
+	s := &Span{
+		tracer: t,
+		parent: t.root.id,
+		name:   name,
+		start:  time.Now(),
+	}
+	randomBytes(s.id[:])
+	return s
+}
+
+func (t *tracer) endSpan(s *Span) {
+	s.end = time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.finished = append(t.finished, s)
+}
+
+func (t *tracer) shutdown() error {
+	t.mu.Lock()
+	t.root.end = time.Now()
+	t.finished = append(t.finished, t.root)
+	spans := t.finished
+	t.mu.Unlock()
+
+	body, err := json.Marshal(t.exportRequest(spans))
+	if err != nil {
+		// Should never happen, since we fully control the data structure.
+		return fmt.Errorf("failed to encode trace data: %s", err)
+	}
+
+	endpoint, err := t.exportURL()
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: exportTimeout}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to export trace data to %s: %s", endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("trace collector at %s rejected the export request: %s", endpoint, resp.Status)
+	}
+	return nil
+}
+
+func (t *tracer) exportURL() (string, error) {
+	u, err := url.Parse(t.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid trace endpoint URL %q: %s", t.endpoint, err)
+	}
+	if !u.IsAbs() {
+		return "", fmt.Errorf("invalid trace endpoint URL %q: must be an absolute URL", t.endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	return u.String(), nil
+}
+
+func randomBytes(buf []byte) {
+	if _, err := rand.Read(buf); err != nil {
+		// The documented behavior of crypto/rand.Read is that it never
+		// returns an error on any of the platforms Terraform supports.
+		panic(fmt.Sprintf("failed to generate random trace identifier: %s", err))
+	}
+}
+
+// The types below describe the subset of the OTLP JSON encoding that we
+// produce. The field names and value encodings (hex-encoded identifiers,
+// decimal string timestamps) follow the OpenTelemetry protocol
+// specification for the JSON mapping of the protobuf schema.
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// spanKindInternal is the OTLP SPAN_KIND_INTERNAL constant, which is the
+// appropriate kind for all of the spans Terraform produces since they
+// describe work within a single process.
+const spanKindInternal = 1
+
+func (t *tracer) exportRequest(spans []*Span) otlpExportRequest {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		otlpSpans = append(otlpSpans, t.encodeSpan(s))
+	}
+	return otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpKeyValue{
+						stringAttr("service.name", "terraform"),
+						stringAttr("service.version", t.version),
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: "github.com/hashicorp/terraform"},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t *tracer) encodeSpan(s *Span) otlpSpan {
+	ret := otlpSpan{
+		TraceID:           hex.EncodeToString(t.traceID[:]),
+		SpanID:            hex.EncodeToString(s.id[:]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: fmt.Sprintf("%d", s.start.UnixNano()),
+		EndTimeUnixNano:   fmt.Sprintf("%d", s.end.UnixNano()),
+	}
+	var zero [8]byte
+	if s.parent != zero {
+		ret.ParentSpanID = hex.EncodeToString(s.parent[:])
+	}
+	for _, attr := range s.attrs {
+		ret.Attributes = append(ret.Attributes, stringAttr(attr.key, attr.value))
+	}
+	return ret
+}
+
+func stringAttr(key, value string) otlpKeyValue {
+	return otlpKeyValue{
+		Key:   key,
+		Value: otlpAnyValue{StringValue: value},
+	}
+}
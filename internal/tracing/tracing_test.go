@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSpanNilSafe(t *testing.T) {
+	// When tracing is disabled StartSpan returns nil, and all of the Span
+	// methods must then be harmless no-ops so that callers can instrument
+	// code unconditionally.
+	var s *Span
+	s.SetAttribute("key", "value")
+	s.End()
+}
+
+func TestTracerExport(t *testing.T) {
+	var gotPath string
+	var gotContentType string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotPath = req.URL.Path
+		gotContentType = req.Header.Get("Content-Type")
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %s", err)
+		}
+		gotBody = body
+	}))
+	defer srv.Close()
+
+	tr := newTracer(srv.URL, "1.3.0")
+	span := tr.startSpan("plan resource instance")
+	span.SetAttribute("resource_address", "null_resource.example")
+	span.End()
+
+	if err := tr.shutdown(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got, want := gotPath, "/v1/traces"; got != want {
+		t.Errorf("wrong request path %q; want %q", got, want)
+	}
+	if got, want := gotContentType, "application/json"; got != want {
+		t.Errorf("wrong content type %q; want %q", got, want)
+	}
+
+	var req otlpExportRequest
+	if err := json.Unmarshal(gotBody, &req); err != nil {
+		t.Fatalf("response body is not valid JSON: %s", err)
+	}
+	if got, want := len(req.ResourceSpans), 1; got != want {
+		t.Fatalf("wrong number of resourceSpans %d; want %d", got, want)
+	}
+	resAttrs := req.ResourceSpans[0].Resource.Attributes
+	if got, want := len(resAttrs), 2; got != want {
+		t.Fatalf("wrong number of resource attributes %d; want %d", got, want)
+	}
+	if got, want := resAttrs[0].Value.StringValue, "terraform"; got != want {
+		t.Errorf("wrong service.name %q; want %q", got, want)
+	}
+	if got, want := resAttrs[1].Value.StringValue, "1.3.0"; got != want {
+		t.Errorf("wrong service.version %q; want %q", got, want)
+	}
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if got, want := len(spans), 2; got != want {
+		t.Fatalf("wrong number of spans %d; want %d", got, want)
+	}
+
+	// The child span must be exported first, parented to the root span.
+	child, root := spans[0], spans[1]
+	if got, want := child.Name, "plan resource instance"; got != want {
+		t.Errorf("wrong child span name %q; want %q", got, want)
+	}
+	if got, want := root.Name, "terraform"; got != want {
+		t.Errorf("wrong root span name %q; want %q", got, want)
+	}
+	if root.ParentSpanID != "" {
+		t.Errorf("root span has unexpected parent %q", root.ParentSpanID)
+	}
+	if got, want := child.ParentSpanID, root.SpanID; got != want {
+		t.Errorf("wrong child parentSpanId %q; want %q", got, want)
+	}
+	if child.TraceID != root.TraceID {
+		t.Errorf("spans have differing traceIds %q and %q", child.TraceID, root.TraceID)
+	}
+	if len(child.Attributes) != 1 || child.Attributes[0].Key != "resource_address" || child.Attributes[0].Value.StringValue != "null_resource.example" {
+		t.Errorf("wrong child span attributes %#v", child.Attributes)
+	}
+	if child.StartTimeUnixNano == "" || child.EndTimeUnixNano == "" {
+		t.Errorf("child span is missing timestamps")
+	}
+}
+
+func TestTracerExportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	tr := newTracer(srv.URL, "1.3.0")
+	if err := tr.shutdown(); err == nil {
+		t.Fatal("unexpected success; want error")
+	}
+}
+
+func TestTracerExportURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{"https://collector.example.com", "https://collector.example.com/v1/traces", false},
+		{"https://collector.example.com/", "https://collector.example.com/v1/traces", false},
+		{"https://collector.example.com/custom/path", "https://collector.example.com/custom/path", false},
+		{"not-a-url", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.endpoint, func(t *testing.T) {
+			tr := newTracer(test.endpoint, "1.3.0")
+			got, err := tr.exportURL()
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("unexpected success; want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.want {
+				t.Errorf("wrong URL %q; want %q", got, test.want)
+			}
+		})
+	}
+}
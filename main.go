@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/terraform/internal/httpclient"
 	"github.com/hashicorp/terraform/internal/logging"
 	"github.com/hashicorp/terraform/internal/terminal"
+	"github.com/hashicorp/terraform/internal/tracing"
 	"github.com/hashicorp/terraform/version"
 	"github.com/mattn/go-shellwords"
 	"github.com/mitchellh/cli"
@@ -135,6 +136,24 @@ func realMain() int {
 		}
 	}
 
+	// Optionally enable trace export, from either the environment or the
+	// CLI configuration. We do this early so that spans covering command
+	// startup are captured too, and defer the export itself until we're
+	// otherwise done so that a slow or unreachable collector can't delay
+	// the command's real work.
+	if traceEndpoint := os.Getenv(tracing.EndpointEnvVar); traceEndpoint != "" {
+		tracing.Enable(traceEndpoint, version.String())
+	} else if config.TraceEndpoint != "" {
+		tracing.Enable(config.TraceEndpoint, version.String())
+	}
+	defer func() {
+		if err := tracing.Shutdown(); err != nil {
+			// A failed export must never affect the outcome of the
+			// command itself, so we just log it.
+			log.Printf("[WARN] %s", err)
+		}
+	}()
+
 	// Get any configured credentials from the config and initialize
 	// a service discovery object. The slightly awkward predeclaration of
 	// disco is required to allow us to pass untyped nil as the creds source
@@ -309,7 +328,10 @@ func realMain() int {
 		}
 	}
 
+	cmdSpan := tracing.StartSpan("run command")
+	cmdSpan.SetAttribute("command", cliRunner.Subcommand())
 	exitCode, err := cliRunner.Run()
+	cmdSpan.End()
 	if err != nil {
 		Ui.Error(fmt.Sprintf("Error executing CLI: %s", err.Error()))
 		return 1
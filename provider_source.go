@@ -6,6 +6,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/apparentlymart/go-userdirs/userdirs"
 	"github.com/hashicorp/terraform-svchost/disco"
@@ -41,7 +42,7 @@ func explicitProviderSource(config *cliconfig.ProviderInstallation, services *di
 
 	log.Printf("[DEBUG] Explicit provider installation configuration is set")
 	for _, methodConfig := range config.Methods {
-		source, moreDiags := providerSourceForCLIConfigLocation(methodConfig.Location, services)
+		source, moreDiags := providerSourceForCLIConfigLocation(methodConfig, services)
 		diags = diags.Append(moreDiags)
 		if moreDiags.HasErrors() {
 			continue
@@ -67,9 +68,10 @@ func explicitProviderSource(config *cliconfig.ProviderInstallation, services *di
 		}
 
 		searchRules = append(searchRules, getproviders.MultiSourceSelector{
-			Source:  source,
-			Include: include,
-			Exclude: exclude,
+			Source:          source,
+			Include:         include,
+			Exclude:         exclude,
+			FallbackOnError: methodConfig.FallbackOnError,
 		})
 
 		log.Printf("[TRACE] Selected provider installation method %#v with includes %s and excludes %s", methodConfig.Location, include, exclude)
@@ -184,10 +186,25 @@ func implicitProviderSource(services *disco.Disco) getproviders.Source {
 	return getproviders.MultiSource(searchRules)
 }
 
-func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocation, services *disco.Disco) (getproviders.Source, tfdiags.Diagnostics) {
+func providerSourceForCLIConfigLocation(methodConfig *cliconfig.ProviderInstallationMethod, services *disco.Disco) (getproviders.Source, tfdiags.Diagnostics) {
+	// withRetries wraps a network-based source with the method's configured
+	// retry/backoff settings, if any. It must be applied inside any
+	// MemoizeSource wrapper, because MemoizeSource caches errors.
+	withRetries := func(source getproviders.Source) getproviders.Source {
+		if methodConfig.Retries <= 0 {
+			return source
+		}
+		wait := time.Duration(methodConfig.RetryWait) * time.Second
+		if wait <= 0 {
+			wait = 1 * time.Second
+		}
+		return getproviders.NewRetrySource(source, methodConfig.Retries, wait)
+	}
+
+	loc := methodConfig.Location
 	if loc == cliconfig.ProviderInstallationDirect {
 		return getproviders.NewMemoizeSource(
-			getproviders.NewRegistrySource(services),
+			withRetries(getproviders.NewRegistrySource(services)),
 		), nil
 	}
 
@@ -216,7 +233,7 @@ func providerSourceForCLIConfigLocation(loc cliconfig.ProviderInstallationLocati
 			))
 			return nil, diags
 		}
-		return getproviders.NewHTTPMirrorSource(url, services.CredentialsSource()), nil
+		return withRetries(getproviders.NewHTTPMirrorSource(url, services.CredentialsSource())), nil
 
 	default:
 		// We should not get here because the set of cases above should